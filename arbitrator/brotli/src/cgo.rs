@@ -64,3 +64,36 @@ pub extern "C" fn brotli_decompress(
     }
     BrotliStatus::Success
 }
+
+/// Brotli compresses the given Go data into a buffer of limited capacity, using the raw bytes of an LZ77 dictionary.
+#[no_mangle]
+pub extern "C" fn brotli_compress_with_dict_bytes(
+    input: BrotliBuffer,
+    mut output: BrotliBuffer,
+    dictionary: BrotliBuffer,
+    level: u32,
+) -> BrotliStatus {
+    let window = DEFAULT_WINDOW_SIZE;
+    let (input, dictionary) = (input.as_slice(), dictionary.as_slice());
+    let buffer = output.as_uninit();
+    match crate::compress_fixed_with_dict_bytes(input, buffer, level, window, dictionary) {
+        Ok(slice) => unsafe { *output.len = slice.len() },
+        Err(status) => return status,
+    }
+    BrotliStatus::Success
+}
+
+/// Brotli decompresses the given Go data into a buffer of limited capacity, using the raw bytes of an LZ77 dictionary.
+#[no_mangle]
+pub extern "C" fn brotli_decompress_with_dict_bytes(
+    input: BrotliBuffer,
+    mut output: BrotliBuffer,
+    dictionary: BrotliBuffer,
+) -> BrotliStatus {
+    let (input, dictionary) = (input.as_slice(), dictionary.as_slice());
+    match crate::decompress_fixed_with_dict_bytes(input, output.as_uninit(), dictionary) {
+        Ok(slice) => unsafe { *output.len = slice.len() },
+        Err(status) => return status,
+    }
+    BrotliStatus::Success
+}
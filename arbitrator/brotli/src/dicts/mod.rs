@@ -25,6 +25,45 @@ extern "C" {
     fn BrotliEncoderGetPreparedDictionarySize(
         dictionary: *const EncoderPreparedDictionary,
     ) -> usize;
+
+    /// Frees a dictionary prepared by `BrotliEncoderPrepareDictionary`.
+    fn BrotliEncoderDestroyPreparedDictionary(dictionary: *mut EncoderPreparedDictionary);
+}
+
+/// Prepares the raw bytes of an LZ77 dictionary for compression at the given level.
+/// The dictionary must be freed with [`destroy_prepared`] once no encoder uses it.
+pub(crate) fn prepare_raw(
+    data: &[u8],
+    level: u32,
+) -> Result<*mut EncoderPreparedDictionary, BrotliStatus> {
+    let dict = unsafe {
+        BrotliEncoderPrepareDictionary(
+            BrotliSharedDictionaryType::Raw,
+            data.len() as c_int,
+            data.as_ptr(),
+            level as c_int,
+            None,
+            None,
+            ptr::null_mut(),
+        )
+    };
+    if dict.is_null() {
+        return Err(BrotliStatus::Failure);
+    }
+    if unsafe { BrotliEncoderGetPreparedDictionarySize(dict) } == 0 {
+        unsafe { destroy_prepared(dict) };
+        return Err(BrotliStatus::Failure);
+    }
+    Ok(dict)
+}
+
+/// Frees a dictionary made by [`prepare_raw`].
+///
+/// # Safety
+///
+/// The dictionary must not be used afterward.
+pub(crate) unsafe fn destroy_prepared(dict: *mut EncoderPreparedDictionary) {
+    BrotliEncoderDestroyPreparedDictionary(dict)
 }
 
 /// Forces a type to implement [`Sync`].
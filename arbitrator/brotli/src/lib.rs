@@ -141,6 +141,37 @@ pub fn compress_fixed<'a>(
     level: u32,
     window_size: u32,
     dictionary: Dictionary,
+) -> Result<&'a [u8], BrotliStatus> {
+    let dictionary = dictionary.ptr(level)?;
+    compress_fixed_prepared(input, output, level, window_size, dictionary)
+}
+
+/// Brotli compresses a slice into a buffer of limited capacity, using the raw bytes of an LZ77 dictionary.
+/// Unlike the built-in dictionaries, the dictionary is prepared anew for each call.
+/// An empty dictionary is the same as none at all.
+pub fn compress_fixed_with_dict_bytes<'a>(
+    input: &'a [u8],
+    output: &'a mut [MaybeUninit<u8>],
+    level: u32,
+    window_size: u32,
+    dictionary: &[u8],
+) -> Result<&'a [u8], BrotliStatus> {
+    if dictionary.is_empty() {
+        return compress_fixed_prepared(input, output, level, window_size, None);
+    }
+    let prepared = dicts::prepare_raw(dictionary, level)?;
+    let result = compress_fixed_prepared(input, output, level, window_size, Some(prepared as _));
+    unsafe { dicts::destroy_prepared(prepared) };
+    result
+}
+
+/// Brotli compresses a slice into a buffer of limited capacity, attaching a prepared dictionary if given.
+fn compress_fixed_prepared<'a>(
+    input: &'a [u8],
+    output: &'a mut [MaybeUninit<u8>],
+    level: u32,
+    window_size: u32,
+    dictionary: Option<*const EncoderPreparedDictionary>,
 ) -> Result<&'a [u8], BrotliStatus> {
     unsafe {
         let state = BrotliEncoderCreateInstance(None, None, ptr::null_mut());
@@ -166,10 +197,8 @@ pub fn compress_fixed<'a>(
         ));
 
         // attach a custom dictionary if requested
-        match dictionary.ptr(level) {
-            Ok(Some(dict)) => check!(BrotliEncoderAttachPreparedDictionary(state, dict)),
-            Err(status) => check!(status),
-            _ => {}
+        if let Some(dict) = dictionary {
+            check!(BrotliEncoderAttachPreparedDictionary(state, dict));
         }
 
         let mut in_len = input.len();
@@ -257,11 +286,21 @@ pub fn decompress(input: &[u8], dictionary: Dictionary) -> Result<Vec<u8>, Brotl
     }
 }
 
-/// Brotli decompresses a slice into
+/// Brotli decompresses a slice into a buffer of limited capacity.
 pub fn decompress_fixed<'a>(
     input: &'a [u8],
     output: &'a mut [MaybeUninit<u8>],
     dictionary: Dictionary,
+) -> Result<&'a [u8], BrotliStatus> {
+    decompress_fixed_with_dict_bytes(input, output, dictionary.slice().unwrap_or_default())
+}
+
+/// Brotli decompresses a slice into a buffer of limited capacity, using the raw bytes of an LZ77 dictionary.
+/// An empty dictionary is the same as none at all.
+pub fn decompress_fixed_with_dict_bytes<'a>(
+    input: &'a [u8],
+    output: &'a mut [MaybeUninit<u8>],
+    dictionary: &[u8],
 ) -> Result<&'a [u8], BrotliStatus> {
     unsafe {
         let state = BrotliDecoderCreateInstance(None, None, ptr::null_mut());
@@ -275,12 +314,12 @@ pub fn decompress_fixed<'a>(
             };
         }
 
-        if let Some(dict) = dictionary.slice() {
+        if !dictionary.is_empty() {
             let attatched = BrotliDecoderAttachDictionary(
                 state,
                 BrotliSharedDictionaryType::Raw,
-                dict.len(),
-                dict.as_ptr(),
+                dictionary.len(),
+                dictionary.as_ptr(),
             );
             check!(attatched == BrotliBool::True);
         }
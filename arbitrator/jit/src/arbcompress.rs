@@ -37,5 +37,14 @@ wrap! {
         out_buf_ptr: GuestPtr,
         out_len_ptr: GuestPtr,
         dictionary: Dictionary
+    ) -> BrotliStatus;
+
+    fn brotli_decompress_with_dict_bytes(
+        in_buf_ptr: GuestPtr,
+        in_buf_len: u32,
+        out_buf_ptr: GuestPtr,
+        out_len_ptr: GuestPtr,
+        dict_buf_ptr: GuestPtr,
+        dict_buf_len: u32
     ) -> BrotliStatus
 }
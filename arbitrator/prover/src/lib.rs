@@ -47,6 +47,7 @@ use std::{
     },
 };
 use utils::CBytes;
+use value::ProgramCounter;
 
 lazy_static::lazy_static! {
     static ref BLOBHASH_PREIMAGE_CACHE: Mutex<LruCache<Bytes32, Arc<OnceCell<CBytes>>>> = Mutex::new(LruCache::new(NonZeroUsize::new(12).unwrap()));
@@ -381,6 +382,26 @@ pub unsafe extern "C" fn arbitrator_get_status(mach: *const Machine) -> u8 {
     (*mach).get_status() as u8
 }
 
+/// If the machine errored, writes where to `pc` and the erroring module's name to `module_name`.
+/// Returns false if the machine hasn't errored.
+#[no_mangle]
+pub unsafe extern "C" fn arbitrator_get_error_context(
+    mach: *const Machine,
+    pc: *mut ProgramCounter,
+    module_name: *mut RustBytes,
+) -> bool {
+    let Some(error_pc) = (*mach).get_error_pc() else {
+        return false;
+    };
+    let name = (*mach)
+        .get_module_names(error_pc.module())
+        .map(|names| names.module.clone())
+        .unwrap_or_default();
+    *pc = error_pc;
+    (*module_name).write(name.into_bytes());
+    true
+}
+
 #[no_mangle]
 pub unsafe extern "C" fn arbitrator_global_state(mach: *mut Machine) -> GlobalState {
     (*mach).get_global_state()
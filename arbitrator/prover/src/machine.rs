@@ -1926,6 +1926,11 @@ impl Machine {
         Some(self.pc)
     }
 
+    /// Returns the program counter the machine stopped at if it errored.
+    pub fn get_error_pc(&self) -> Option<ProgramCounter> {
+        (self.status == MachineStatus::Errored).then_some(self.pc)
+    }
+
     #[cfg(feature = "native")]
     fn test_next_instruction(func: &Function, pc: &ProgramCounter) {
         let inst: usize = pc.inst.try_into().unwrap();
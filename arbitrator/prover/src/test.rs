@@ -67,3 +67,33 @@ pub fn test_compress() -> Result<()> {
     }
     Ok(())
 }
+
+#[test]
+pub fn test_compress_with_dict_bytes() -> Result<()> {
+    let data = include_bytes!("../../../target/machines/latest/forward_stub.wasm");
+    let dict = Dictionary::StylusProgram.slice().unwrap();
+
+    let mut buffer = Vec::with_capacity(brotli::compression_bound(data.len(), 11));
+    let deflate =
+        brotli::compress_fixed_with_dict_bytes(data, buffer.spare_capacity_mut(), 11, 22, dict)
+            .unwrap()
+            .to_vec();
+
+    // raw dictionary bytes are interchangeable with the built-in dictionary they came from
+    let inflate = brotli::decompress(&deflate, Dictionary::StylusProgram).unwrap();
+    assert_eq!(hex::encode(inflate), hex::encode(data));
+
+    let mut buffer = Vec::with_capacity(data.len());
+    let inflate =
+        brotli::decompress_fixed_with_dict_bytes(&deflate, buffer.spare_capacity_mut(), dict)
+            .unwrap();
+    assert_eq!(hex::encode(inflate), hex::encode(data));
+
+    // the dictionary is needed to decompress
+    let mut buffer = Vec::with_capacity(data.len());
+    assert!(
+        brotli::decompress_fixed_with_dict_bytes(&deflate, buffer.spare_capacity_mut(), &[])
+            .is_err()
+    );
+    Ok(())
+}
@@ -124,6 +124,7 @@ impl From<IntegerValType> for ArbValueType {
 
 #[serde_as]
 #[derive(Clone, Copy, Debug, Default, PartialEq, Eq, Serialize, Deserialize)]
+#[repr(C)]
 pub struct ProgramCounter {
     #[serde_as(as = "TryFromInto<usize>")]
     pub module: u32,
@@ -2,15 +2,23 @@ package arbnode
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"time"
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/ethdb"
 
+	"github.com/offchainlabs/nitro/arbstate"
 	"github.com/offchainlabs/nitro/arbutil"
+	"github.com/offchainlabs/nitro/execution"
+	"github.com/offchainlabs/nitro/execution/gethexec"
 	"github.com/offchainlabs/nitro/staker"
+	"github.com/offchainlabs/nitro/util/headerreader"
+	"github.com/offchainlabs/nitro/util/inboxproof"
 	"github.com/offchainlabs/nitro/validator"
 	"github.com/offchainlabs/nitro/validator/server_api"
 )
@@ -62,3 +70,227 @@ func (a *BlockValidatorDebugAPI) ValidationInputsAt(ctx context.Context, msgNum
 ) (server_api.InputJSON, error) {
 	return a.val.ValidationInputsAt(ctx, arbutil.MessageIndex(msgNum), target)
 }
+
+type InboxDebugAPI struct {
+	inboxTracker *InboxTracker
+}
+
+// QuarantinedBatch returns the raw bytes of a batch that couldn't be fully decoded, and why.
+func (a *InboxDebugAPI) QuarantinedBatch(ctx context.Context, batchNum hexutil.Uint64) (*QuarantinedBatch, error) {
+	return a.inboxTracker.GetQuarantinedBatch(uint64(batchNum))
+}
+
+// QuarantinedBatches lists the numbers of the batches that couldn't be fully decoded.
+func (a *InboxDebugAPI) QuarantinedBatches(ctx context.Context) ([]hexutil.Uint64, error) {
+	batchNums, err := a.inboxTracker.GetQuarantinedBatchNumbers()
+	if err != nil {
+		return nil, err
+	}
+	result := make([]hexutil.Uint64, 0, len(batchNums))
+	for _, batchNum := range batchNums {
+		result = append(result, hexutil.Uint64(batchNum))
+	}
+	return result, nil
+}
+
+// maxL1PricingTraceRange bounds how many blocks a single L1 pricing update trace query covers
+const maxL1PricingTraceRange = 10000
+
+type L1PricingTraceAPI struct {
+	inboxTracker *InboxTracker
+	exec         *gethexec.ExecutionNode
+}
+
+// GetL1PricingUpdateTrace explains how batch posting reports in blocks from through to (inclusive)
+// changed the L1 price per unit: what each report cost, the price and surplus before and after,
+// and which adjustment rules fired.
+func (a *L1PricingTraceAPI) GetL1PricingUpdateTrace(ctx context.Context, from, to hexutil.Uint64) ([]execution.L1PricingUpdateTrace, error) {
+	if to < from {
+		return nil, fmt.Errorf("invalid block range %v to %v", uint64(from), uint64(to))
+	}
+	if to-from >= maxL1PricingTraceRange {
+		return nil, fmt.Errorf("block range %v to %v is longer than the maximum of %v blocks", uint64(from), uint64(to), maxL1PricingTraceRange)
+	}
+	traces, err := a.inboxTracker.GetL1PricingUpdateTraces(uint64(from), uint64(to))
+	if err != nil {
+		return nil, err
+	}
+	canonical := traces[:0]
+	for _, trace := range traces {
+		msgIndex, err := a.exec.ExecEngine.BlockNumberToMessageIndex(uint64(trace.BlockNumber))
+		if err != nil {
+			return nil, err
+		}
+		result, err := a.exec.ResultAtPos(msgIndex)
+		if err != nil {
+			// the block is past the head after a reorg
+			continue
+		}
+		if result.BlockHash == trace.BlockHash {
+			canonical = append(canonical, trace)
+		}
+	}
+	return canonical, nil
+}
+
+type InboxProofAPI struct {
+	inboxReader  *InboxReader
+	inboxTracker *InboxTracker
+	txStreamer   *TransactionStreamer
+	exec         *gethexec.ExecutionNode
+}
+
+// GetTransactionInclusionProof proves that the transaction was part of a batch posted to the SequencerInbox.
+// The proof can be checked with inboxproof.Verify against the SequencerInbox's accumulator for the batch.
+func (a *InboxProofAPI) GetTransactionInclusionProof(ctx context.Context, txHash common.Hash) (*inboxproof.InclusionProof, error) {
+	tx, _, blockNum, _ := rawdb.ReadTransaction(a.exec.ChainDB, txHash)
+	if tx == nil {
+		return nil, fmt.Errorf("transaction %v not found", txHash)
+	}
+	msgIndex, err := a.exec.ExecEngine.BlockNumberToMessageIndex(blockNum)
+	if err != nil {
+		return nil, err
+	}
+	batch, found, err := a.inboxTracker.FindInboxBatchContainingMessage(msgIndex)
+	if err != nil {
+		return nil, err
+	}
+	if !found {
+		return nil, fmt.Errorf("message %v containing transaction %v not yet posted in a batch", msgIndex, txHash)
+	}
+	meta, err := a.inboxTracker.GetBatchMetadata(batch)
+	if err != nil {
+		return nil, err
+	}
+	var prevMeta BatchMetadata
+	if batch > 0 {
+		prevMeta, err = a.inboxTracker.GetBatchMetadata(batch - 1)
+		if err != nil {
+			return nil, err
+		}
+	}
+	batchData, batchBlockHash, err := a.inboxReader.GetSequencerMessageBytes(ctx, batch)
+	if err != nil {
+		return nil, err
+	}
+	segments, _, err := arbstate.ParseMessageSegments(ctx, batch, batchBlockHash, batchData, a.inboxTracker.dapReaders, a.inboxTracker)
+	if err != nil {
+		return nil, err
+	}
+
+	proof := &inboxproof.InclusionProof{
+		BatchNumber:         batch,
+		MessageIndex:        uint64(msgIndex),
+		MessageIndexInBatch: uint64(msgIndex - prevMeta.MessageCount),
+		BatchData:           batchData,
+		BatchDataHash:       crypto.Keccak256Hash(batchData),
+		BeforeInboxAcc:      prevMeta.Accumulator,
+		AfterInboxAcc:       meta.Accumulator,
+	}
+	if meta.DelayedMessageCount > 0 {
+		proof.DelayedInboxAcc, err = a.inboxTracker.GetDelayedAcc(meta.DelayedMessageCount - 1)
+		if err != nil {
+			return nil, err
+		}
+	}
+	isDelayed := true
+	if proof.MessageIndexInBatch < uint64(len(segments)) {
+		segment := segments[proof.MessageIndexInBatch]
+		proof.SegmentOffset = &segment.Offset
+		isDelayed = segment.Data[0] == arbstate.BatchSegmentKindDelayedMessages
+	}
+	if !isDelayed {
+		return proof, nil
+	}
+
+	// a delayed message is read if the message advanced the delayed messages read count
+	msg, err := a.txStreamer.GetMessage(msgIndex)
+	if err != nil {
+		return nil, err
+	}
+	var prevDelayedRead uint64
+	if msgIndex > 0 {
+		prevMsg, err := a.txStreamer.GetMessage(msgIndex - 1)
+		if err != nil {
+			return nil, err
+		}
+		prevDelayedRead = prevMsg.DelayedMessagesRead
+	}
+	if msg.DelayedMessagesRead <= prevDelayedRead {
+		return nil, errors.New("message didn't read a delayed message")
+	}
+	delayed := &inboxproof.DelayedMessageProof{
+		Index: msg.DelayedMessagesRead - 1,
+	}
+	if delayed.Index > 0 {
+		delayed.BeforeAcc, err = a.inboxTracker.GetDelayedAcc(delayed.Index - 1)
+		if err != nil {
+			return nil, err
+		}
+	}
+	for i := delayed.Index; i < meta.DelayedMessageCount; i++ {
+		delayedMsg, err := a.inboxTracker.GetDelayedMessage(ctx, i)
+		if err != nil {
+			return nil, err
+		}
+		delayed.MessageHashes = append(delayed.MessageHashes, delayedMsg.DelayedInboxHash())
+	}
+	proof.DelayedMessage = delayed
+	return proof, nil
+}
+
+type ConfirmationStatus struct {
+	BatchPosted      bool   `json:"batchPosted"`
+	L1BatchBlock     uint64 `json:"l1BatchBlock"`
+	L1BatchConfirmed bool   `json:"l1BatchConfirmed"`
+	L1BatchFinalized bool   `json:"l1BatchFinalized"`
+}
+
+type L1ConfirmationAPI struct {
+	inboxTracker *InboxTracker
+	l1Reader     *headerreader.HeaderReader
+	exec         *gethexec.ExecutionNode
+}
+
+// GetL1ConfirmationStatus reports whether the batch containing an L2 block was posted to the parent chain,
+// and whether the parent chain block it was posted in is safe (confirmed) and finalized.
+func (a *L1ConfirmationAPI) GetL1ConfirmationStatus(ctx context.Context, blockNumber hexutil.Uint64) (ConfirmationStatus, error) {
+	var status ConfirmationStatus
+	msgIndex, err := a.exec.ExecEngine.BlockNumberToMessageIndex(uint64(blockNumber))
+	if err != nil {
+		return status, err
+	}
+	head, err := a.exec.ExecEngine.HeadMessageNumber()
+	if err != nil {
+		return status, err
+	}
+	if msgIndex > head {
+		return status, fmt.Errorf("block %v not found", uint64(blockNumber))
+	}
+	batch, found, err := a.inboxTracker.FindInboxBatchContainingMessage(msgIndex)
+	if err != nil || !found {
+		return status, err
+	}
+	meta, err := a.inboxTracker.GetBatchMetadata(batch)
+	if err != nil {
+		return status, err
+	}
+	status.BatchPosted = true
+	status.L1BatchBlock = meta.ParentChainBlock
+
+	safeBlock, err := a.l1Reader.LatestSafeBlockNr(ctx)
+	if errors.Is(err, headerreader.ErrBlockNumberNotSupported) {
+		return status, nil
+	} else if err != nil {
+		return status, err
+	}
+	status.L1BatchConfirmed = meta.ParentChainBlock <= safeBlock
+	finalizedBlock, err := a.l1Reader.LatestFinalizedBlockNr(ctx)
+	if errors.Is(err, headerreader.ErrBlockNumberNotSupported) {
+		return status, nil
+	} else if err != nil {
+		return status, err
+	}
+	status.L1BatchFinalized = meta.ParentChainBlock <= finalizedBlock
+	return status, nil
+}
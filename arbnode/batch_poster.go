@@ -12,7 +12,9 @@ import (
 	"fmt"
 	"math"
 	"math/big"
+	"os"
 	"strings"
+	"sync"
 	"sync/atomic"
 	"time"
 
@@ -26,6 +28,7 @@ import (
 	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/consensus/misc/eip4844"
 	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/crypto/kzg4844"
 	"github.com/ethereum/go-ethereum/ethdb"
 	"github.com/ethereum/go-ethereum/log"
@@ -35,9 +38,11 @@ import (
 	"github.com/ethereum/go-ethereum/rpc"
 
 	"github.com/offchainlabs/bold/solgen/go/bridgegen"
+	"github.com/offchainlabs/nitro/arbcompress"
 	"github.com/offchainlabs/nitro/arbnode/dataposter"
 	"github.com/offchainlabs/nitro/arbnode/dataposter/storage"
 	"github.com/offchainlabs/nitro/arbnode/redislock"
+	"github.com/offchainlabs/nitro/arbos/arbosState"
 	"github.com/offchainlabs/nitro/arbos/arbostypes"
 	"github.com/offchainlabs/nitro/arbstate"
 	"github.com/offchainlabs/nitro/arbstate/daprovider"
@@ -123,6 +128,16 @@ type BatchPoster struct {
 	postedFirstBatch     bool        // indicates if batch poster has posted the first batch
 
 	accessList func(SequencerInboxAccs, AfterDelayedMessagesRead uint64) types.AccessList
+
+	// ArbOS's brotli compression level is cached until a chain owner changes a parameter
+	chainParamsChanges               atomic.Uint64 // number of chain parameter changes seen, starting at 1
+	compressionLevelMutex            sync.Mutex
+	arbOSCompressionLevel            int
+	arbOSCompressionLevelChangesSeen uint64 // chainParamsChanges when arbOSCompressionLevel was read, or 0 if it wasn't
+
+	compressionDictionary       []byte      // read from the configured file, or nil if there isn't one
+	compressionDictionaryHash   common.Hash // keccak hash of compressionDictionary
+	compressionDictionarySentIn uint64      // one more than the last batch that distributed compressionDictionary, or 0 if none did
 }
 
 type l1BlockBound int
@@ -157,6 +172,7 @@ type BatchPosterConfig struct {
 	// Batch posting error delay.
 	ErrorDelay                     time.Duration               `koanf:"error-delay" reload:"hot"`
 	CompressionLevel               int                         `koanf:"compression-level" reload:"hot"`
+	UseArbOSCompressionLevel       bool                        `koanf:"use-arbos-compression-level" reload:"hot"`
 	DASRetentionPeriod             time.Duration               `koanf:"das-retention-period" reload:"hot"`
 	GasRefunderAddress             string                      `koanf:"gas-refunder-address" reload:"hot"`
 	DataPoster                     dataposter.DataPosterConfig `koanf:"data-poster" reload:"hot"`
@@ -175,6 +191,7 @@ type BatchPosterConfig struct {
 	CheckBatchCorrectness          bool                        `koanf:"check-batch-correctness"`
 	MaxEmptyBatchDelay             time.Duration               `koanf:"max-empty-batch-delay"`
 	DelayBufferThresholdMargin     uint64                      `koanf:"delay-buffer-threshold-margin"`
+	CompressionDictionaryFile      string                      `koanf:"compression-dictionary-file"`
 
 	gasRefunder  common.Address
 	l1BlockBound l1BlockBound
@@ -220,6 +237,7 @@ func BatchPosterConfigAddOptions(prefix string, f *pflag.FlagSet) {
 	f.Duration(prefix+".poll-interval", DefaultBatchPosterConfig.PollInterval, "how long to wait after no batches are ready to be posted before checking again")
 	f.Duration(prefix+".error-delay", DefaultBatchPosterConfig.ErrorDelay, "how long to delay after error posting batch")
 	f.Int(prefix+".compression-level", DefaultBatchPosterConfig.CompressionLevel, "batch compression level")
+	f.Bool(prefix+".use-arbos-compression-level", DefaultBatchPosterConfig.UseArbOSCompressionLevel, "compress batches with the brotli compression level ArbOS uses for pricing instead of compression-level")
 	f.Duration(prefix+".das-retention-period", DefaultBatchPosterConfig.DASRetentionPeriod, "In AnyTrust mode, the period which DASes are requested to retain the stored batches.")
 	f.String(prefix+".gas-refunder-address", DefaultBatchPosterConfig.GasRefunderAddress, "The gas refunder contract address (optional)")
 	f.Uint64(prefix+".extra-batch-gas", DefaultBatchPosterConfig.ExtraBatchGas, "use this much more gas than estimation says is necessary to post batches")
@@ -234,6 +252,7 @@ func BatchPosterConfigAddOptions(prefix string, f *pflag.FlagSet) {
 	f.Bool(prefix+".check-batch-correctness", DefaultBatchPosterConfig.CheckBatchCorrectness, "setting this to true will run the batch against an inbox multiplexer and verifies that it produces the correct set of messages")
 	f.Duration(prefix+".max-empty-batch-delay", DefaultBatchPosterConfig.MaxEmptyBatchDelay, "maximum empty batch posting delay, batch poster will only be able to post an empty batch if this time period building a batch has passed")
 	f.Uint64(prefix+".delay-buffer-threshold-margin", DefaultBatchPosterConfig.DelayBufferThresholdMargin, "the number of blocks to post the batch before reaching the delay buffer threshold")
+	f.String(prefix+".compression-dictionary-file", DefaultBatchPosterConfig.CompressionDictionaryFile, "if non-empty, a file with the brotli dictionary to compress batches with once a chain owner makes it ArbOS's active compression dictionary")
 	redislock.AddConfigOptions(prefix+".redis-lock", f)
 	dataposter.DataPosterConfigAddOptions(prefix+".data-poster", f, dataposter.DefaultDataPosterConfig)
 	genericconf.WalletConfigAddOptions(prefix+".parent-chain-wallet", f, DefaultBatchPosterConfig.ParentChainWallet.Pathname)
@@ -359,6 +378,17 @@ func NewBatchPoster(ctx context.Context, opts *BatchPosterOpts) (*BatchPoster, e
 		redisLock:          redisLock,
 		dapReaders:         opts.DAPReaders,
 	}
+	b.chainParamsChanges.Store(1)
+	if dictionaryFile := opts.Config().CompressionDictionaryFile; dictionaryFile != "" {
+		b.compressionDictionary, err = os.ReadFile(dictionaryFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read compression dictionary: %w", err)
+		}
+		if len(b.compressionDictionary) == 0 || len(b.compressionDictionary) > arbosState.MaxCompressionDictionarySize {
+			return nil, fmt.Errorf("compression dictionary of %d bytes isn't between 1 and %d bytes", len(b.compressionDictionary), arbosState.MaxCompressionDictionarySize)
+		}
+		b.compressionDictionaryHash = crypto.Keccak256Hash(b.compressionDictionary)
+	}
 	b.messagesPerBatch, err = arbmath.NewMovingAverage[uint64](20)
 	if err != nil {
 		return nil, err
@@ -418,6 +448,7 @@ type simulatedMuxBackend struct {
 	allMsgs               map[arbutil.MessageIndex]*arbostypes.MessageWithMetadata
 	delayedInboxStart     uint64
 	delayedInbox          []*arbostypes.MessageWithMetadata
+	dictionary            []byte
 }
 
 func (b *simulatedMuxBackend) PeekSequencerInbox() ([]byte, common.Hash, error) {
@@ -437,6 +468,15 @@ func (b *simulatedMuxBackend) ReadDelayedInbox(seqNum uint64) (*arbostypes.L1Inc
 	return nil, fmt.Errorf("error serving ReadDelayedInbox, all delayed messages were read. Requested delayed message position:%d, Total delayed messages: %d", pos, len(b.delayedInbox))
 }
 
+func (b *simulatedMuxBackend) GetCompressionDictionary(hash common.Hash) ([]byte, error) {
+	if len(b.dictionary) == 0 || crypto.Keccak256Hash(b.dictionary) != hash {
+		return nil, fmt.Errorf("%w %v", arbstate.ErrUnknownCompressionDictionary, hash)
+	}
+	return b.dictionary, nil
+}
+
+func (b *simulatedMuxBackend) AddCompressionDictionary(dictionary []byte) error { return nil }
+
 type AccessListOpts struct {
 	SequencerInboxAddr       common.Address
 	BridgeAddr               common.Address
@@ -633,6 +673,86 @@ func (b *BatchPoster) pollForL1PriceData(ctx context.Context) {
 	}
 }
 
+// CompressionLevel returns the brotli compression level new batches are compressed with,
+// before it's lowered to catch up on a backlog.
+func (b *BatchPoster) CompressionLevel() int {
+	config := b.config()
+	if !config.UseArbOSCompressionLevel {
+		return config.CompressionLevel
+	}
+	b.compressionLevelMutex.Lock()
+	defer b.compressionLevelMutex.Unlock()
+	changesSeen := b.chainParamsChanges.Load()
+	if b.arbOSCompressionLevelChangesSeen != changesSeen {
+		level, err := b.arbOSVersionGetter.BrotliCompressionLevel()
+		if err != nil {
+			log.Warn("failed to read ArbOS brotli compression level, using the configured level", "err", err)
+			return config.CompressionLevel
+		}
+		// #nosec G115
+		b.arbOSCompressionLevel = int(level)
+		b.arbOSCompressionLevelChangesSeen = changesSeen
+	}
+	return b.arbOSCompressionLevel
+}
+
+// compressionDictionaryForBatch returns the dictionary a new batch should be compressed with,
+// which it should only be once a chain owner has made it ArbOS's active dictionary and it's been distributed.
+// Until it's been distributed, distribute is whether the new batch should distribute it.
+func (b *BatchPoster) compressionDictionaryForBatch(seqNum uint64) (dictionary []byte, distribute bool) {
+	if len(b.compressionDictionary) == 0 {
+		return nil, false
+	}
+	_, active, err := b.arbOSVersionGetter.ActiveCompressionDictionary()
+	if err != nil {
+		log.Warn("failed to read ArbOS compression dictionary, compressing without it", "err", err)
+		return nil, false
+	}
+	if active != b.compressionDictionaryHash {
+		return nil, false
+	}
+	_, err = b.inbox.GetCompressionDictionary(active)
+	if err == nil {
+		return b.compressionDictionary, false
+	}
+	if !errors.Is(err, arbstate.ErrUnknownCompressionDictionary) {
+		log.Warn("failed to look up compression dictionary, compressing without it", "err", err)
+		return nil, false
+	}
+	// wait for the inbox to read a batch already distributing the dictionary before distributing it again
+	if seqNum < b.compressionDictionarySentIn {
+		return nil, false
+	}
+	batchCount, err := b.inbox.GetBatchCount()
+	if err != nil || batchCount < b.compressionDictionarySentIn {
+		return nil, false
+	}
+	b.compressionDictionarySentIn = seqNum + 1
+	return nil, true
+}
+
+// watchChainParameterChanges invalidates the cached ArbOS parameters as soon as
+// execution applies a block in which a chain owner changed them.
+func (b *BatchPoster) watchChainParameterChanges(ctx context.Context) {
+	changes := make(chan execution.ChainParameterChange, 16)
+	sub := b.arbOSVersionGetter.SubscribeChainParameterChanges(changes)
+	defer sub.Unsubscribe()
+	for {
+		select {
+		case change := <-changes:
+			log.Debug("chain parameters changed, invalidating cached ArbOS parameters", "message", change.MessageIndex)
+			b.chainParamsChanges.Add(1)
+		case err := <-sub.Err():
+			if err != nil {
+				log.Warn("chain parameter change subscription failed", "err", err)
+			}
+			return
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
 // pollForReverts runs a gouroutine that listens to l1 block headers, checks
 // if any transaction made by batch poster was reverted.
 func (b *BatchPoster) pollForReverts(ctx context.Context) {
@@ -722,6 +842,9 @@ type batchSegments struct {
 	lastCompressedSize    int
 	trailingHeaders       int // how many trailing segments are headers
 	isDone                bool
+	dictionary            []byte // if not nil, the batch is also compressed with it, and whichever is smaller is posted
+	dictionaryHash        common.Hash
+	uncompressed          bool // if the batch is posted as its RLP-encoded segments, as a chain owner can set in ArbOS
 }
 
 type buildingBatch struct {
@@ -736,7 +859,7 @@ type buildingBatch struct {
 	firstUsefulMsg     *arbostypes.MessageWithMetadata
 }
 
-func newBatchSegments(firstDelayed uint64, config *BatchPosterConfig, backlog uint64, use4844 bool) *batchSegments {
+func newBatchSegments(firstDelayed uint64, config *BatchPosterConfig, compressionLevel int, backlog uint64, use4844 bool) *batchSegments {
 	maxSize := config.MaxSize
 	if use4844 {
 		maxSize = config.Max4844BatchSize
@@ -747,8 +870,7 @@ func newBatchSegments(firstDelayed uint64, config *BatchPosterConfig, backlog ui
 		maxSize -= 40
 	}
 	compressedBuffer := bytes.NewBuffer(make([]byte, 0, maxSize*2))
-	compressionLevel := config.CompressionLevel
-	recompressionLevel := config.CompressionLevel
+	recompressionLevel := compressionLevel
 	if backlog > 20 {
 		compressionLevel = arbmath.MinInt(compressionLevel, brotli.DefaultCompression)
 	}
@@ -814,6 +936,10 @@ func (s *batchSegments) testForOverflow(isHeader bool) (bool, error) {
 	if isHeader || len(s.rawSegments) == s.trailingHeaders {
 		return false, nil
 	}
+	if s.uncompressed {
+		// the batch is posted as is, so its size is known without flushing the compressor
+		return s.totalUncompressedSize >= s.sizeLimit, nil
+	}
 	err := s.compressedWriter.Flush()
 	if err != nil {
 		return true, err
@@ -881,6 +1007,15 @@ func (s *batchSegments) addL2Msg(l2msg []byte) (bool, error) {
 	return s.addSegment(segment, false)
 }
 
+// distributeCompressionDictionary adds a segment distributing the dictionary, so later batches can be compressed with it.
+// Like other headers, it's trimmed if no message follows it.
+func (s *batchSegments) distributeCompressionDictionary(dictionary []byte) (bool, error) {
+	segment := make([]byte, 1, len(dictionary)+1)
+	segment[0] = arbstate.BatchSegmentKindCompressionDictionary
+	segment = append(segment, dictionary...)
+	return s.addSegment(segment, true)
+}
+
 func (s *batchSegments) prepareIntSegment(val uint64, segmentHeader byte) ([]byte, error) {
 	segment := make([]byte, 1, 16)
 	segment[0] = segmentHeader
@@ -952,6 +1087,13 @@ func (s *batchSegments) CloseAndGetBytes() ([]byte, error) {
 	if len(s.rawSegments) == 0 {
 		return nil, nil
 	}
+	if s.uncompressed {
+		encoded, err := s.encodeSegments()
+		if err != nil {
+			return nil, err
+		}
+		return append([]byte{daprovider.UncompressedMessageHeaderByte}, encoded...), nil
+	}
 	err := s.compressedWriter.Close()
 	if err != nil {
 		return nil, err
@@ -960,9 +1102,47 @@ func (s *batchSegments) CloseAndGetBytes() ([]byte, error) {
 	fullMsg := make([]byte, 1, len(compressedBytes)+1)
 	fullMsg[0] = daprovider.BrotliMessageHeaderByte
 	fullMsg = append(fullMsg, compressedBytes...)
+	if s.dictionary != nil {
+		withDictionary, err := s.compressWithDictionary()
+		if err != nil {
+			log.Warn("failed to compress batch with dictionary", "err", err)
+		} else if len(withDictionary) < len(fullMsg) {
+			return withDictionary, nil
+		}
+	}
 	return fullMsg, nil
 }
 
+// encodeSegments concatenates the RLP encodings of the batch's segments, which is what gets compressed.
+func (s *batchSegments) encodeSegments() ([]byte, error) {
+	var encoded []byte
+	for _, segment := range s.rawSegments {
+		enc, err := rlp.EncodeToBytes(segment)
+		if err != nil {
+			return nil, err
+		}
+		encoded = append(encoded, enc...)
+	}
+	return encoded, nil
+}
+
+// compressWithDictionary compresses the batch's segments with its dictionary, prefixed by the dictionary's hash.
+func (s *batchSegments) compressWithDictionary() ([]byte, error) {
+	encoded, err := s.encodeSegments()
+	if err != nil {
+		return nil, err
+	}
+	// #nosec G115
+	compressed, err := arbcompress.CompressWithDictionaryBytes(encoded, uint32(s.recompressionLevel), s.dictionary)
+	if err != nil {
+		return nil, err
+	}
+	fullMsg := make([]byte, 0, 1+common.HashLength+len(compressed))
+	fullMsg = append(fullMsg, daprovider.BrotliDictionaryMessageHeaderByte)
+	fullMsg = append(fullMsg, s.dictionaryHash.Bytes()...)
+	return append(fullMsg, compressed...), nil
+}
+
 func (b *BatchPoster) encodeAddBatch(
 	seqNum *big.Int,
 	prevMsgNum arbutil.MessageIndex,
@@ -1182,15 +1362,35 @@ func (b *BatchPoster) maybePostSequencerBatch(ctx context.Context) (bool, error)
 		}
 
 		b.building = &buildingBatch{
-			segments:      newBatchSegments(batchPosition.DelayedMessageCount, b.config(), b.GetBacklogEstimate(), use4844),
+			segments:      newBatchSegments(batchPosition.DelayedMessageCount, config, b.CompressionLevel(), b.GetBacklogEstimate(), use4844),
 			msgCount:      batchPosition.MessageCount,
 			startMsgCount: batchPosition.MessageCount,
 			use4844:       use4844,
 		}
+		uncompressed, err := b.arbOSVersionGetter.L1TxBatchCompressionDisabled()
+		if err != nil {
+			log.Warn("failed to read whether ArbOS has batch compression disabled, compressing the batch", "err", err)
+		} else if uncompressed {
+			b.building.segments.uncompressed = true
+		}
+		dictionary, distributeDictionary := b.compressionDictionaryForBatch(batchPosition.NextSeqNum)
+		if b.building.segments.uncompressed {
+			dictionary = nil
+		}
+		if dictionary != nil {
+			b.building.segments.dictionary = dictionary
+			b.building.segments.dictionaryHash = b.compressionDictionaryHash
+		} else if distributeDictionary {
+			log.Info("distributing compression dictionary", "hash", b.compressionDictionaryHash, "batch", batchPosition.NextSeqNum)
+			if _, err := b.building.segments.distributeCompressionDictionary(b.compressionDictionary); err != nil {
+				return false, err
+			}
+		}
 		if b.config().CheckBatchCorrectness {
 			b.building.muxBackend = &simulatedMuxBackend{
 				batchSeqNum: batchPosition.NextSeqNum,
 				allMsgs:     make(map[arbutil.MessageIndex]*arbostypes.MessageWithMetadata),
+				dictionary:  dictionary,
 			}
 		}
 	}
@@ -1638,6 +1838,7 @@ func (b *BatchPoster) Start(ctxIn context.Context) {
 	b.StopWaiter.Start(ctxIn, b)
 	b.LaunchThread(b.pollForReverts)
 	b.LaunchThread(b.pollForL1PriceData)
+	b.LaunchThread(b.watchChainParameterChanges)
 	commonEphemeralErrorHandler := util.NewEphemeralErrorHandler(time.Minute, "", 0)
 	exceedMaxMempoolSizeEphemeralErrorHandler := util.NewEphemeralErrorHandler(5*time.Minute, dataposter.ErrExceedsMaxMempoolSize.Error(), time.Minute)
 	storageRaceEphemeralErrorHandler := util.NewEphemeralErrorHandler(5*time.Minute, storage.ErrStorageRace.Error(), time.Minute)
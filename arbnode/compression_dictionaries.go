@@ -0,0 +1,48 @@
+// Copyright 2024, Offchain Labs, Inc.
+// For license information, see https://github.com/nitro/blob/master/LICENSE
+
+package arbnode
+
+import (
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/log"
+
+	"github.com/offchainlabs/nitro/arbstate"
+)
+
+func (b *multiplexerBackend) GetCompressionDictionary(hash common.Hash) ([]byte, error) {
+	return b.inbox.GetCompressionDictionary(hash)
+}
+
+func (b *multiplexerBackend) AddCompressionDictionary(dictionary []byte) error {
+	return b.inbox.addCompressionDictionary(dictionary)
+}
+
+// GetCompressionDictionary returns a dictionary distributed in a batch by its keccak hash.
+func (t *InboxTracker) GetCompressionDictionary(hash common.Hash) ([]byte, error) {
+	key := append(append([]byte{}, compressionDictionaryPrefix...), hash.Bytes()...)
+	hasKey, err := t.db.Has(key)
+	if err != nil {
+		return nil, err
+	}
+	if !hasKey {
+		return nil, fmt.Errorf("%w %v", arbstate.ErrUnknownCompressionDictionary, hash)
+	}
+	return t.db.Get(key)
+}
+
+// addCompressionDictionary stores a dictionary distributed in a batch.
+// Dictionaries are keyed by their hash, so they're kept through reorgs as a later batch can use them again.
+func (t *InboxTracker) addCompressionDictionary(dictionary []byte) error {
+	hash := crypto.Keccak256Hash(dictionary)
+	key := append(append([]byte{}, compressionDictionaryPrefix...), hash.Bytes()...)
+	hasKey, err := t.db.Has(key)
+	if err != nil || hasKey {
+		return err
+	}
+	log.Info("storing compression dictionary", "hash", hash, "size", len(dictionary))
+	return t.db.Put(key, dictionary)
+}
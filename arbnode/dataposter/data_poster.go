@@ -74,7 +74,7 @@ type DataPoster struct {
 	headerReader      *headerreader.HeaderReader
 	client            *ethclient.Client
 	auth              *bind.TransactOpts
-	signer            signerFn
+	signer            SignerFn
 	config            ConfigFetcher
 	usingNoOpStorage  bool
 	metadataRetriever func(ctx context.Context, blockNum *big.Int) ([]byte, error)
@@ -97,10 +97,10 @@ type DataPoster struct {
 	maxFeeCapExpression *govaluate.EvaluableExpression
 }
 
-// signerFn is a signer function callback when a contract requires a method to
+// SignerFn is a signer function callback when a contract requires a method to
 // sign the transaction before submission.
 // This can be local or external, hence the context parameter.
-type signerFn func(context.Context, common.Address, *types.Transaction) (*types.Transaction, error)
+type SignerFn func(context.Context, common.Address, *types.Transaction) (*types.Transaction, error)
 
 type DataPosterOpts struct {
 	Database          ethdb.Database
@@ -177,7 +177,7 @@ func NewDataPoster(ctx context.Context, opts *DataPosterOpts) (*DataPoster, erro
 		dp.extraBacklog = func() uint64 { return 0 }
 	}
 	if cfg.ExternalSigner.URL != "" {
-		signer, sender, err := externalSigner(ctx, &cfg.ExternalSigner)
+		signer, sender, err := ExternalSigner(ctx, &cfg.ExternalSigner)
 		if err != nil {
 			return nil, err
 		}
@@ -278,10 +278,10 @@ func TxToSignTxArgs(addr common.Address, tx *types.Transaction) (*apitypes.SendT
 	}, nil
 }
 
-// externalSigner returns signer function and ethereum address of the signer.
+// ExternalSigner returns signer function and ethereum address of the signer.
 // Returns an error if address isn't specified or if it can't connect to the
 // signer RPC server.
-func externalSigner(ctx context.Context, opts *ExternalSignerCfg) (signerFn, common.Address, error) {
+func ExternalSigner(ctx context.Context, opts *ExternalSignerCfg) (SignerFn, common.Address, error) {
 	if opts.Address == "" {
 		return nil, common.Address{}, errors.New("external signer (From) address specified")
 	}
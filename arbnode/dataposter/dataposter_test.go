@@ -69,7 +69,7 @@ func TestExternalSigner(t *testing.T) {
 		t.Fatalf("Error getting signer test config: %v", err)
 	}
 	ctx := context.Background()
-	signer, addr, err := externalSigner(ctx, signerCfg)
+	signer, addr, err := ExternalSigner(ctx, signerCfg)
 	if err != nil {
 		t.Fatalf("Error getting external signer: %v", err)
 	}
@@ -6,7 +6,6 @@ import (
 	"crypto/x509"
 	"errors"
 	"fmt"
-	"math/big"
 	"net"
 	"net/http"
 	"os"
@@ -20,6 +19,7 @@ import (
 	"github.com/ethereum/go-ethereum/accounts/keystore"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/rpc"
 	"github.com/ethereum/go-ethereum/signer/core/apitypes"
 
@@ -155,6 +155,7 @@ func (s *SignerServer) Start() error {
 
 // setupAccount creates a new account in a given directory, unlocks it, creates
 // signer with that account and returns it along with account address.
+// The signer signs for the chain ID of each transaction, so it serves parent and child chains alike.
 func setupAccount(dir string) (bind.SignerFn, common.Address, error) {
 	ks := keystore.NewKeyStore(
 		dir,
@@ -168,11 +169,13 @@ func setupAccount(dir string) (bind.SignerFn, common.Address, error) {
 	if err := ks.Unlock(a, "password"); err != nil {
 		return nil, common.Address{}, fmt.Errorf("unlocking account: %w", err)
 	}
-	txOpts, err := bind.NewKeyStoreTransactorWithChainID(ks, a, big.NewInt(1337))
-	if err != nil {
-		return nil, common.Address{}, fmt.Errorf("creating transactor: %w", err)
+	signer := func(address common.Address, tx *types.Transaction) (*types.Transaction, error) {
+		if address != a.Address {
+			return nil, bind.ErrNotAuthorized
+		}
+		return ks.SignTx(a, tx, tx.ChainId())
 	}
-	return txOpts.Signer, a.Address, nil
+	return signer, a.Address, nil
 }
 
 type SignerAPI struct {
@@ -91,6 +91,7 @@ type InboxReader struct {
 	delayedBridge  *DelayedBridge
 	sequencerInbox *SequencerInbox
 	caughtUpChan   chan struct{}
+	catchupChan    chan struct{}
 	client         *ethclient.Client
 	l1Reader       *headerreader.HeaderReader
 
@@ -112,6 +113,7 @@ func NewInboxReader(tracker *InboxTracker, client *ethclient.Client, l1Reader *h
 		l1Reader:          l1Reader,
 		firstMessageBlock: firstMessageBlock,
 		caughtUpChan:      make(chan struct{}),
+		catchupChan:       make(chan struct{}, 1),
 		config:            config,
 	}, nil
 }
@@ -222,6 +224,15 @@ func (r *InboxReader) DelayedBridge() *DelayedBridge {
 	return r.delayedBridge
 }
 
+// RequestCatchup makes the inbox reader check L1 for new messages without
+// waiting for enough new blocks or the check delay.
+func (r *InboxReader) RequestCatchup() {
+	select {
+	case r.catchupChan <- struct{}{}:
+	default:
+	}
+}
+
 func (r *InboxReader) CaughtUp() chan struct{} {
 	return r.caughtUpChan
 }
@@ -324,6 +335,8 @@ func (r *InboxReader) run(ctx context.Context, hadError bool) error {
 					return nil
 				case <-checkDelayTimer.C:
 					break WaitForHeight
+				case <-r.catchupChan:
+					break WaitForHeight
 				}
 			}
 			checkDelayTimer.Stop()
@@ -44,6 +44,8 @@ type InboxTracker struct {
 
 	batchMetaMutex sync.Mutex
 	batchMeta      *containers.LruCache[uint64, BatchMetadata]
+
+	quarantineMutex sync.Mutex
 }
 
 func NewInboxTracker(db ethdb.Database, txStreamer *TransactionStreamer, dapReaders []daprovider.Reader, snapSyncConfig SnapSyncConfig) (*InboxTracker, error) {
@@ -294,12 +296,19 @@ func (t *InboxTracker) PopulateFeedBacklog(broadcastServer *broadcaster.Broadcas
 	if err != nil {
 		return fmt.Errorf("error getting tx streamer message count: %w", err)
 	}
+	backlogConfig := broadcastServer.BacklogConfig()
+	startMessage = arbutil.MessageIndex(backlogConfig.StartupStart(uint64(startMessage), uint64(messageCount)))
+	minTimestamp := backlogConfig.StartupMinTimestamp(time.Now())
 	var feedMessages []*m.BroadcastFeedMessage
 	for seqNum := startMessage; seqNum < messageCount; seqNum++ {
 		message, err := t.txStreamer.GetMessage(seqNum)
 		if err != nil {
 			return fmt.Errorf("error getting message %v: %w", seqNum, err)
 		}
+		// Message timestamps never decrease, so this only skips a prefix of the messages
+		if message.Message.Header.Timestamp < minTimestamp {
+			continue
+		}
 
 		msgResult, err := t.txStreamer.ResultAtCount(seqNum + 1)
 		var blockHash *common.Hash
@@ -313,6 +322,9 @@ func (t *InboxTracker) PopulateFeedBacklog(broadcastServer *broadcaster.Broadcas
 		}
 		feedMessages = append(feedMessages, feedMessage)
 	}
+	if len(feedMessages) > 0 && feedMessages[0].SequenceNumber > startMessage {
+		log.Info("skipped old messages when populating feed backlog", "start", startMessage, "first", feedMessages[0].SequenceNumber)
+	}
 	broadcastServer.BroadcastFeedMessages(feedMessages)
 	return nil
 }
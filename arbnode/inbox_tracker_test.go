@@ -1,10 +1,13 @@
 package arbnode
 
 import (
+	"errors"
+	"reflect"
 	"testing"
 
 	"github.com/ethereum/go-ethereum/core/rawdb"
 
+	"github.com/offchainlabs/nitro/arbstate"
 	"github.com/offchainlabs/nitro/util/containers"
 )
 
@@ -57,3 +60,35 @@ func TestDeleteBatchMetadata(t *testing.T) {
 	}
 
 }
+
+func TestQuarantineMalformedBatch(t *testing.T) {
+	tracker := &InboxTracker{
+		db: rawdb.NewMemoryDatabase(),
+	}
+	data := []byte("malformed")
+	tracker.quarantineMalformedBatch(7, arbstate.MalformedBatchBadCompression, data, errors.New("bad brotli"))
+	tracker.quarantineMalformedBatch(7, arbstate.MalformedBatchBadCompression, data, errors.New("bad brotli again"))
+	tracker.quarantineMalformedBatch(7, arbstate.MalformedBatchInvalidSegmentKind, data, errors.New("bad kind"))
+	tracker.quarantineMalformedBatch(3, arbstate.MalformedBatchUnknownFormat, data, errors.New("unknown"))
+
+	batchNums, err := tracker.GetQuarantinedBatchNumbers()
+	Require(t, err)
+	if !reflect.DeepEqual(batchNums, []uint64{3, 7}) {
+		Fail(t, "unexpected quarantined batches", batchNums)
+	}
+
+	batch, err := tracker.GetQuarantinedBatch(7)
+	Require(t, err)
+	expected := []MalformedBatchFailure{
+		{Class: "bad_compression", Error: "bad brotli"},
+		{Class: "invalid_segment_kind", Error: "bad kind"},
+	}
+	if string(batch.Data) != string(data) || !reflect.DeepEqual(batch.Failures, expected) {
+		Fail(t, "unexpected quarantined batch", batch)
+	}
+
+	_, err = tracker.GetQuarantinedBatch(4)
+	if !errors.Is(err, ErrBatchNotQuarantined) {
+		Fail(t, "expected batch 4 not to be quarantined, got", err)
+	}
+}
@@ -0,0 +1,98 @@
+// Copyright 2024, Offchain Labs, Inc.
+// For license information, see https://github.com/nitro/blob/master/LICENSE
+
+package arbnode
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/json"
+
+	"github.com/ethereum/go-ethereum/log"
+
+	"github.com/offchainlabs/nitro/execution"
+)
+
+// recordL1PricingUpdates stores how each batch posting report execution applies changed the L1 pricing model.
+// The traces are only kept to explain fee changes, so storing them is best effort.
+func (t *InboxTracker) recordL1PricingUpdates(ctx context.Context, exec execution.FullExecutionClient) {
+	traces := make(chan execution.L1PricingUpdateTrace, 16)
+	sub := exec.SubscribeL1PricingUpdates(traces)
+	defer sub.Unsubscribe()
+	for {
+		select {
+		case trace := <-traces:
+			if err := t.addL1PricingUpdateTrace(trace); err != nil {
+				log.Warn("failed to store L1 pricing update trace", "block", uint64(trace.BlockNumber), "err", err)
+			}
+		case err := <-sub.Err():
+			if err != nil {
+				log.Warn("L1 pricing update subscription failed", "err", err)
+			}
+			return
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func (t *InboxTracker) addL1PricingUpdateTrace(trace execution.L1PricingUpdateTrace) error {
+	existing, err := t.readL1PricingUpdateTraces(uint64(trace.BlockNumber))
+	if err != nil {
+		return err
+	}
+	var blockTraces []execution.L1PricingUpdateTrace
+	for _, other := range existing {
+		// traces of a block that has since been reorged out are replaced
+		if other.BlockHash == trace.BlockHash && other.TxIndex != trace.TxIndex {
+			blockTraces = append(blockTraces, other)
+		}
+	}
+	blockTraces = append(blockTraces, trace)
+	encoded, err := json.Marshal(blockTraces)
+	if err != nil {
+		return err
+	}
+	return t.db.Put(dbKey(l1PricingTracePrefix, uint64(trace.BlockNumber)), encoded)
+}
+
+func (t *InboxTracker) readL1PricingUpdateTraces(blockNum uint64) ([]execution.L1PricingUpdateTrace, error) {
+	key := dbKey(l1PricingTracePrefix, blockNum)
+	hasKey, err := t.db.Has(key)
+	if err != nil || !hasKey {
+		return nil, err
+	}
+	encoded, err := t.db.Get(key)
+	if err != nil {
+		return nil, err
+	}
+	var traces []execution.L1PricingUpdateTrace
+	if err := json.Unmarshal(encoded, &traces); err != nil {
+		return nil, err
+	}
+	return traces, nil
+}
+
+// GetL1PricingUpdateTraces returns the stored L1 pricing update traces of blocks from through to, inclusive.
+// Traces of blocks that have been reorged out may still be returned, so callers should check the block hash.
+func (t *InboxTracker) GetL1PricingUpdateTraces(from, to uint64) ([]execution.L1PricingUpdateTrace, error) {
+	iter := t.db.NewIterator(l1PricingTracePrefix, uint64ToKey(from))
+	defer iter.Release()
+	traces := []execution.L1PricingUpdateTrace{}
+	for iter.Next() {
+		key := iter.Key()
+		if len(key) != len(l1PricingTracePrefix)+8 {
+			continue
+		}
+		blockNum := binary.BigEndian.Uint64(key[len(l1PricingTracePrefix):])
+		if blockNum > to {
+			break
+		}
+		var blockTraces []execution.L1PricingUpdateTrace
+		if err := json.Unmarshal(iter.Value(), &blockTraces); err != nil {
+			return nil, err
+		}
+		traces = append(traces, blockTraces...)
+	}
+	return traces, iter.Error()
+}
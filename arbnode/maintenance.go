@@ -181,10 +181,12 @@ func (mr *MaintenanceRunner) runMaintenance() {
 			results <- db.Compact(nil, nil)
 		}()
 	}
-	expected++
-	go func() {
-		results <- mr.exec.Maintenance()
-	}()
+	if mr.exec != nil {
+		expected++
+		go func() {
+			results <- mr.exec.Maintenance()
+		}()
+	}
 	for i := 0; i < expected; i++ {
 		err := <-results
 		if err != nil {
@@ -0,0 +1,150 @@
+// Copyright 2024, Offchain Labs, Inc.
+// For license information, see https://github.com/nitro/blob/master/LICENSE
+
+package arbnode
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/metrics"
+	"github.com/ethereum/go-ethereum/rlp"
+
+	"github.com/offchainlabs/nitro/arbstate"
+)
+
+var malformedBatchCounters = make(map[arbstate.MalformedBatchClass]metrics.Counter)
+
+func init() {
+	for _, class := range arbstate.AllMalformedBatchClasses() {
+		malformedBatchCounters[class] = metrics.NewRegisteredCounter("arb/inbox/malformed/"+class.String(), nil)
+	}
+}
+
+// maxQuarantinedBatchFailures bounds how many failures are kept per quarantined batch
+const maxQuarantinedBatchFailures = 16
+
+type quarantinedBatchFailure struct {
+	Class uint8
+	Error string
+}
+
+type quarantinedBatchEntry struct {
+	Data     []byte
+	Failures []quarantinedBatchFailure
+}
+
+// MalformedBatchFailure describes why part of a quarantined batch couldn't be decoded.
+type MalformedBatchFailure struct {
+	Class string `json:"class"`
+	Error string `json:"error"`
+}
+
+// QuarantinedBatch is a batch that couldn't be fully decoded, kept for diagnostics.
+type QuarantinedBatch struct {
+	BatchNumber hexutil.Uint64          `json:"batchNumber"`
+	Data        hexutil.Bytes           `json:"data"`
+	Failures    []MalformedBatchFailure `json:"failures"`
+}
+
+func (b *multiplexerBackend) ReportMalformedBatch(batchNum uint64, class arbstate.MalformedBatchClass, data []byte, err error) {
+	b.inbox.quarantineMalformedBatch(batchNum, class, data, err)
+}
+
+// quarantineMalformedBatch stores the raw bytes of a malformed batch along with why it failed to decode.
+// Failures are only recorded for diagnostics, so storing them is best effort.
+func (t *InboxTracker) quarantineMalformedBatch(batchNum uint64, class arbstate.MalformedBatchClass, data []byte, failure error) {
+	if counter, ok := malformedBatchCounters[class]; ok {
+		counter.Inc(1)
+	}
+	log.Warn("quarantining malformed batch", "batch", batchNum, "class", class, "err", failure)
+
+	t.quarantineMutex.Lock()
+	defer t.quarantineMutex.Unlock()
+	entry, err := t.readQuarantinedBatch(batchNum)
+	if err != nil {
+		log.Warn("failed to read quarantined batch", "batch", batchNum, "err", err)
+		return
+	}
+	if entry == nil {
+		entry = &quarantinedBatchEntry{}
+	}
+	entry.Data = data
+	for _, existing := range entry.Failures {
+		if existing.Class == uint8(class) {
+			// we've seen this batch fail this way already, likely because it was read again after a reorg
+			return
+		}
+	}
+	if len(entry.Failures) >= maxQuarantinedBatchFailures {
+		return
+	}
+	entry.Failures = append(entry.Failures, quarantinedBatchFailure{uint8(class), failure.Error()})
+	encoded, err := rlp.EncodeToBytes(entry)
+	if err == nil {
+		err = t.db.Put(dbKey(quarantinedBatchPrefix, batchNum), encoded)
+	}
+	if err != nil {
+		log.Warn("failed to quarantine malformed batch", "batch", batchNum, "err", err)
+	}
+}
+
+func (t *InboxTracker) readQuarantinedBatch(batchNum uint64) (*quarantinedBatchEntry, error) {
+	key := dbKey(quarantinedBatchPrefix, batchNum)
+	hasKey, err := t.db.Has(key)
+	if err != nil || !hasKey {
+		return nil, err
+	}
+	encoded, err := t.db.Get(key)
+	if err != nil {
+		return nil, err
+	}
+	var entry quarantinedBatchEntry
+	if err := rlp.DecodeBytes(encoded, &entry); err != nil {
+		return nil, err
+	}
+	return &entry, nil
+}
+
+var ErrBatchNotQuarantined = errors.New("batch not quarantined")
+
+// GetQuarantinedBatch returns a malformed batch's raw bytes and decode failures.
+func (t *InboxTracker) GetQuarantinedBatch(batchNum uint64) (*QuarantinedBatch, error) {
+	entry, err := t.readQuarantinedBatch(batchNum)
+	if err != nil {
+		return nil, err
+	}
+	if entry == nil {
+		return nil, fmt.Errorf("%w: %d", ErrBatchNotQuarantined, batchNum)
+	}
+	batch := &QuarantinedBatch{
+		BatchNumber: hexutil.Uint64(batchNum),
+		Data:        entry.Data,
+		Failures:    make([]MalformedBatchFailure, 0, len(entry.Failures)),
+	}
+	for _, failure := range entry.Failures {
+		batch.Failures = append(batch.Failures, MalformedBatchFailure{
+			Class: arbstate.MalformedBatchClass(failure.Class).String(),
+			Error: failure.Error,
+		})
+	}
+	return batch, nil
+}
+
+// GetQuarantinedBatchNumbers returns the numbers of all quarantined batches in ascending order.
+func (t *InboxTracker) GetQuarantinedBatchNumbers() ([]uint64, error) {
+	iter := t.db.NewIterator(quarantinedBatchPrefix, nil)
+	defer iter.Release()
+	var batchNums []uint64
+	for iter.Next() {
+		key := iter.Key()
+		if len(key) != len(quarantinedBatchPrefix)+8 {
+			continue
+		}
+		batchNums = append(batchNums, binary.BigEndian.Uint64(key[len(quarantinedBatchPrefix):]))
+	}
+	return batchNums, iter.Error()
+}
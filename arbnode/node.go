@@ -99,6 +99,7 @@ type Config struct {
 	TransactionStreamer TransactionStreamerConfig      `koanf:"transaction-streamer" reload:"hot"`
 	Maintenance         MaintenanceConfig              `koanf:"maintenance" reload:"hot"`
 	ResourceMgmt        resourcemanager.Config         `koanf:"resource-mgmt" reload:"hot"`
+	VerifierRelay       VerifierRelayConfig            `koanf:"verifier-relay" reload:"hot"`
 	// SnapSyncConfig is only used for testing purposes, these should not be configured in production.
 	SnapSyncTest SnapSyncConfig
 }
@@ -135,6 +136,9 @@ func (c *Config) Validate() error {
 	if err := c.Staker.Validate(); err != nil {
 		return err
 	}
+	if err := c.VerifierRelay.Validate(c); err != nil {
+		return err
+	}
 	return nil
 }
 
@@ -165,6 +169,7 @@ func ConfigAddOptions(prefix string, f *flag.FlagSet, feedInputEnable bool, feed
 	DangerousConfigAddOptions(prefix+".dangerous", f)
 	TransactionStreamerConfigAddOptions(prefix+".transaction-streamer", f)
 	MaintenanceConfigAddOptions(prefix+".maintenance", f)
+	VerifierRelayConfigAddOptions(prefix+".verifier-relay", f)
 }
 
 var ConfigDefault = Config{
@@ -185,6 +190,7 @@ var ConfigDefault = Config{
 	TransactionStreamer: DefaultTransactionStreamerConfig,
 	ResourceMgmt:        resourcemanager.DefaultConfig,
 	Maintenance:         DefaultMaintenanceConfig,
+	VerifierRelay:       DefaultVerifierRelayConfig,
 	SnapSyncTest:        DefaultSnapSyncConfig,
 }
 
@@ -282,6 +288,7 @@ type Node struct {
 	MaintenanceRunner       *MaintenanceRunner
 	DASLifecycleManager     *das.LifecycleManager
 	SyncMonitor             *SyncMonitor
+	FeedVerifier            *FeedVerifier
 	configFetcher           ConfigFetcher
 	ctx                     context.Context
 }
@@ -429,6 +436,9 @@ func createNodeImpl(
 	blobReader daprovider.BlobReader,
 ) (*Node, error) {
 	config := configFetcher.Get()
+	if exec == nil && !config.VerifierRelay.Enable {
+		return nil, errors.New("execution client is required unless running as a verifier relay")
+	}
 
 	err := checkArbDbSchemaVersion(arbDb)
 	if err != nil {
@@ -468,6 +478,11 @@ func createNodeImpl(
 	if err != nil {
 		return nil, err
 	}
+	var feedVerifier *FeedVerifier
+	if config.VerifierRelay.Enable {
+		feedVerifier = NewFeedVerifier()
+		txStreamer.SetFeedVerifier(feedVerifier)
+	}
 	var coordinator *SeqCoordinator
 	var bpVerifier *contracts.AddressVerifier
 	if deployInfo != nil && l1client != nil {
@@ -538,6 +553,7 @@ func createNodeImpl(
 			MaintenanceRunner:       maintenanceRunner,
 			DASLifecycleManager:     nil,
 			SyncMonitor:             syncMonitor,
+			FeedVerifier:            feedVerifier,
 			configFetcher:           configFetcher,
 			ctx:                     ctx,
 		}, nil
@@ -629,7 +645,9 @@ func createNodeImpl(
 	txStreamer.SetInboxReaders(inboxReader, delayedBridge)
 
 	var statelessBlockValidator *staker.StatelessBlockValidator
-	if config.BlockValidator.RedisValidationClientConfig.Enabled() || config.BlockValidator.ValidationServerConfigs[0].URL != "" {
+	if exec == nil {
+		err = errors.New("no execution client to validate with")
+	} else if config.BlockValidator.RedisValidationClientConfig.Enabled() || config.BlockValidator.ValidationServerConfigs[0].URL != "" {
 		statelessBlockValidator, err = staker.NewStatelessBlockValidator(
 			inboxReader,
 			inboxTracker,
@@ -793,6 +811,7 @@ func createNodeImpl(
 		MaintenanceRunner:       maintenanceRunner,
 		DASLifecycleManager:     dasLifecycleManager,
 		SyncMonitor:             syncMonitor,
+		FeedVerifier:            feedVerifier,
 		configFetcher:           configFetcher,
 		ctx:                     ctx,
 	}, nil
@@ -879,6 +898,36 @@ func CreateNode(
 			Public:    false,
 		})
 	}
+	if execNode, ok := currentNode.Execution.(*gethexec.ExecutionNode); ok && currentNode.InboxReader != nil {
+		apis = append(apis, rpc.API{
+			Namespace: "arb",
+			Version:   "1.0",
+			Service: &InboxProofAPI{
+				inboxReader:  currentNode.InboxReader,
+				inboxTracker: currentNode.InboxTracker,
+				txStreamer:   currentNode.TxStreamer,
+				exec:         execNode,
+			},
+			Public: false,
+		}, rpc.API{
+			Namespace: "arb",
+			Version:   "1.0",
+			Service: &L1ConfirmationAPI{
+				inboxTracker: currentNode.InboxTracker,
+				l1Reader:     currentNode.L1Reader,
+				exec:         execNode,
+			},
+			Public: false,
+		}, rpc.API{
+			Namespace: "arb",
+			Version:   "1.0",
+			Service: &L1PricingTraceAPI{
+				inboxTracker: currentNode.InboxTracker,
+				exec:         execNode,
+			},
+			Public: false,
+		})
+	}
 	if currentNode.StatelessBlockValidator != nil {
 		apis = append(apis, rpc.API{
 			Namespace: "arbdebug",
@@ -889,6 +938,24 @@ func CreateNode(
 			Public: false,
 		})
 	}
+	if currentNode.FeedVerifier != nil {
+		apis = append(apis, rpc.API{
+			Namespace: "arb",
+			Version:   "1.0",
+			Service:   &VerifierRelayAPI{node: currentNode},
+			Public:    false,
+		})
+	}
+	if currentNode.InboxTracker != nil {
+		apis = append(apis, rpc.API{
+			Namespace: "arbdebug",
+			Version:   "1.0",
+			Service: &InboxDebugAPI{
+				inboxTracker: currentNode.InboxTracker,
+			},
+			Public: false,
+		})
+	}
 
 	stack.RegisterAPIs(apis)
 
@@ -914,9 +981,11 @@ func (n *Node) Start(ctx context.Context) error {
 	if execClient != nil {
 		execClient.SetConsensusClient(n)
 	}
-	err = n.Execution.Start(ctx)
-	if err != nil {
-		return fmt.Errorf("error starting exec client: %w", err)
+	if n.Execution != nil {
+		err = n.Execution.Start(ctx)
+		if err != nil {
+			return fmt.Errorf("error starting exec client: %w", err)
+		}
 	}
 	if n.BlobReader != nil {
 		err = n.BlobReader.Initialize(ctx)
@@ -930,6 +999,9 @@ func (n *Node) Start(ctx context.Context) error {
 			return fmt.Errorf("error initializing inbox tracker: %w", err)
 		}
 	}
+	if n.InboxTracker != nil && n.Execution != nil {
+		go n.InboxTracker.recordL1PricingUpdates(ctx, n.Execution)
+	}
 	if n.BroadcastServer != nil {
 		err = n.BroadcastServer.Initialize()
 		if err != nil {
@@ -963,7 +1035,7 @@ func (n *Node) Start(ctx context.Context) error {
 	}
 	if n.SeqCoordinator != nil {
 		n.SeqCoordinator.Start(ctx)
-	} else {
+	} else if n.Execution != nil {
 		n.Execution.Activate()
 	}
 	if n.MaintenanceRunner != nil {
@@ -12,6 +12,9 @@ var (
 	parentChainBlockNumberPrefix []byte = []byte("p") // maps a delayed sequence number to a parent chain block number
 	sequencerBatchMetaPrefix     []byte = []byte("s") // maps a batch sequence number to BatchMetadata
 	delayedSequencedPrefix       []byte = []byte("a") // maps a delayed message count to the first sequencer batch sequence number with this delayed count
+	quarantinedBatchPrefix       []byte = []byte("q") // maps a batch sequence number to the raw bytes and decode failures of a malformed batch
+	l1PricingTracePrefix         []byte = []byte("l") // maps an L2 block number to how its batch posting reports changed the L1 pricing model
+	compressionDictionaryPrefix  []byte = []byte("c") // maps a keccak hash to the compression dictionary with that hash
 
 	messageCountKey             []byte = []byte("_messageCount")                // contains the current message count
 	lastPrunedMessageKey        []byte = []byte("_lastPrunedMessageKey")        // contains the last pruned message key
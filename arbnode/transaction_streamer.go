@@ -47,6 +47,9 @@ type TransactionStreamer struct {
 	exec             execution.ExecutionSequencer
 	execLastMsgCount arbutil.MessageIndex
 	validator        *staker.BlockValidator
+	feedVerifier     *FeedVerifier
+	// Without execution, messages are relayed as they're stored rather than once they're executed
+	relayedMsgCount arbutil.MessageIndex
 
 	db             ethdb.Database
 	fatalErrChan   chan<- error
@@ -151,6 +154,16 @@ func (s *TransactionStreamer) SetBlockValidator(validator *staker.BlockValidator
 	s.validator = validator
 }
 
+func (s *TransactionStreamer) SetFeedVerifier(feedVerifier *FeedVerifier) {
+	if s.Started() {
+		panic("trying to set feed verifier after start")
+	}
+	if s.feedVerifier != nil {
+		panic("trying to set feed verifier when already set")
+	}
+	s.feedVerifier = feedVerifier
+}
+
 func (s *TransactionStreamer) SetSeqCoordinator(coordinator *SeqCoordinator) {
 	if s.Started() {
 		panic("trying to set coordinator after start")
@@ -353,19 +366,27 @@ func (s *TransactionStreamer) reorg(batch ethdb.Batch, count arbutil.MessageInde
 	s.reorgMutex.Lock()
 	defer s.reorgMutex.Unlock()
 
-	messagesResults, err := s.exec.Reorg(count, newMessages, oldMessages)
-	if err != nil {
-		return err
-	}
+	var messagesResults []*execution.MessageResult
+	if s.exec != nil {
+		messagesResults, err = s.exec.Reorg(count, newMessages, oldMessages)
+		if err != nil {
+			return err
+		}
 
-	messagesWithComputedBlockHash := make([]arbostypes.MessageWithMetadataAndBlockHash, 0, len(messagesResults))
-	for i := 0; i < len(messagesResults); i++ {
-		messagesWithComputedBlockHash = append(messagesWithComputedBlockHash, arbostypes.MessageWithMetadataAndBlockHash{
-			MessageWithMeta: newMessages[i].MessageWithMeta,
-			BlockHash:       &messagesResults[i].BlockHash,
-		})
+		messagesWithComputedBlockHash := make([]arbostypes.MessageWithMetadataAndBlockHash, 0, len(messagesResults))
+		for i := 0; i < len(messagesResults); i++ {
+			messagesWithComputedBlockHash = append(messagesWithComputedBlockHash, arbostypes.MessageWithMetadataAndBlockHash{
+				MessageWithMeta: newMessages[i].MessageWithMeta,
+				BlockHash:       &messagesResults[i].BlockHash,
+			})
+		}
+		s.broadcastMessages(messagesWithComputedBlockHash, count)
+	} else {
+		// There's nothing to execute, so the new messages are relayed as they are
+		s.broadcastMessages(newMessages, count)
+		// #nosec G115
+		s.relayedMsgCount = count + arbutil.MessageIndex(len(newMessages))
 	}
-	s.broadcastMessages(messagesWithComputedBlockHash, count)
 
 	if s.validator != nil {
 		err = s.validator.Reorg(s.GetContext(), count)
@@ -497,6 +518,9 @@ func (s *TransactionStreamer) GetProcessedMessageCount() (arbutil.MessageIndex,
 	if err != nil {
 		return 0, err
 	}
+	if s.exec == nil {
+		return msgCount, nil
+	}
 	digestedHead, err := s.exec.HeadMessageNumber()
 	if err != nil {
 		return 0, err
@@ -674,7 +698,9 @@ func (s *TransactionStreamer) AddMessagesAndEndBatch(pos arbutil.MessageIndex, m
 
 	if messagesAreConfirmed {
 		// Trim confirmed messages from l1pricedataCache
-		s.exec.MarkFeedStart(pos + arbutil.MessageIndex(len(messages)))
+		if s.exec != nil {
+			s.exec.MarkFeedStart(pos + arbutil.MessageIndex(len(messages)))
+		}
 		s.reorgMutex.RLock()
 		dups, _, _, err := s.countDuplicateMessages(pos, messagesWithBlockHash, nil)
 		s.reorgMutex.RUnlock()
@@ -682,6 +708,9 @@ func (s *TransactionStreamer) AddMessagesAndEndBatch(pos arbutil.MessageIndex, m
 			return err
 		}
 		if dups == uint64(len(messages)) {
+			if s.feedVerifier != nil {
+				s.feedVerifier.verified(pos + arbutil.MessageIndex(len(messages)))
+			}
 			return endBatch(batch)
 		}
 		// cant keep reorg lock when catching insertionMutex.
@@ -693,7 +722,11 @@ func (s *TransactionStreamer) AddMessagesAndEndBatch(pos arbutil.MessageIndex, m
 	s.insertionMutex.Lock()
 	defer s.insertionMutex.Unlock()
 
-	return s.addMessagesAndEndBatchImpl(pos, messagesAreConfirmed, messagesWithBlockHash, batch)
+	err := s.addMessagesAndEndBatchImpl(pos, messagesAreConfirmed, messagesWithBlockHash, batch)
+	if err == nil && messagesAreConfirmed && s.feedVerifier != nil {
+		s.feedVerifier.verified(pos + arbutil.MessageIndex(len(messages)))
+	}
+	return err
 }
 
 func (s *TransactionStreamer) getPrevPrevDelayedRead(pos arbutil.MessageIndex) (uint64, error) {
@@ -825,6 +858,10 @@ func (s *TransactionStreamer) addMessagesAndEndBatchImpl(messageStartPos arbutil
 		if err != nil {
 			return err
 		}
+		if confirmedReorg && oldMsg != nil && s.feedVerifier != nil {
+			// #nosec G115
+			s.feedVerifier.mismatch(messageStartPos+arbutil.MessageIndex(duplicates), oldMsg, &messages[duplicates].MessageWithMeta)
+		}
 		if duplicates > 0 {
 			lastDelayedRead = messages[duplicates-1].MessageWithMeta.DelayedMessagesRead
 			messages = messages[duplicates:]
@@ -998,6 +1035,20 @@ func (s *TransactionStreamer) ResumeReorgs() {
 	s.reorgMutex.RUnlock()
 }
 
+// RequestInboxCatchup is called when the feed no longer has messages this node
+// needs, which then have to be read from L1.
+func (s *TransactionStreamer) RequestInboxCatchup(firstAvailable arbutil.MessageIndex) {
+	msgCount, err := s.GetMessageCount()
+	if err != nil {
+		log.Warn("error getting message count for inbox catchup", "err", err)
+	} else if msgCount < firstAvailable {
+		log.Info("reading messages missing from feed backlog from L1", "msgCount", msgCount, "firstAvailable", firstAvailable)
+	}
+	if s.inboxReader != nil {
+		s.inboxReader.RequestCatchup()
+	}
+}
+
 func (s *TransactionStreamer) PopulateFeedBacklog() error {
 	if s.broadcastServer == nil {
 		return nil
@@ -1088,6 +1139,9 @@ func (s *TransactionStreamer) ResultAtCount(count arbutil.MessageIndex) (*execut
 	} else if !dbutil.IsErrNotFound(err) {
 		return nil, err
 	}
+	if s.exec == nil {
+		return nil, errors.New("no message result stored, and no execution client to compute it")
+	}
 	log.Info(FailedToGetMsgResultFromDB, "count", count)
 
 	msgResult, err := s.exec.ResultAtPos(pos)
@@ -1147,6 +1201,9 @@ func (s *TransactionStreamer) ExecuteNextMsg(ctx context.Context) bool {
 		return false
 	}
 	defer s.reorgMutex.RUnlock()
+	if s.exec == nil {
+		return s.relayNextMsgs()
+	}
 	prevMessageCount := s.execLastMsgCount
 	msgCount, err := s.GetMessageCount()
 	if err != nil {
@@ -1210,6 +1267,36 @@ func (s *TransactionStreamer) ExecuteNextMsg(ctx context.Context) bool {
 	return pos+1 < msgCount
 }
 
+// maxRelayedMsgs bounds how many messages a node without execution broadcasts at once
+const maxRelayedMsgs = 1024
+
+// relayNextMsgs broadcasts stored messages that haven't been yet, with the block hashes they came with, if any.
+// The reorg mutex must be held for reading.
+// return value: true if should be called again immediately
+func (s *TransactionStreamer) relayNextMsgs() bool {
+	msgCount, err := s.GetMessageCount()
+	if err != nil {
+		log.Error("relayNextMsgs failed to get message count", "err", err)
+		return false
+	}
+	if s.relayedMsgCount >= msgCount {
+		return false
+	}
+	relayEnd := min(msgCount, s.relayedMsgCount+maxRelayedMsgs)
+	msgs := make([]arbostypes.MessageWithMetadataAndBlockHash, 0, relayEnd-s.relayedMsgCount)
+	for pos := s.relayedMsgCount; pos < relayEnd; pos++ {
+		msg, err := s.getMessageWithMetadataAndBlockHash(pos)
+		if err != nil {
+			log.Error("relayNextMsgs failed to readMessage", "err", err, "pos", pos)
+			return false
+		}
+		msgs = append(msgs, *msg)
+	}
+	s.broadcastMessages(msgs, s.relayedMsgCount)
+	s.relayedMsgCount = relayEnd
+	return relayEnd < msgCount
+}
+
 func (s *TransactionStreamer) executeMessages(ctx context.Context, ignored struct{}) time.Duration {
 	if s.ExecuteNextMsg(ctx) {
 		return 0
@@ -1218,6 +1305,14 @@ func (s *TransactionStreamer) executeMessages(ctx context.Context, ignored struc
 }
 
 func (s *TransactionStreamer) Start(ctxIn context.Context) error {
+	if s.exec == nil {
+		// Stored messages were either relayed before or are in the feed backlog populated on startup
+		msgCount, err := s.GetMessageCount()
+		if err != nil {
+			return err
+		}
+		s.relayedMsgCount = msgCount
+	}
 	s.StopWaiter.Start(ctxIn, s)
 	return stopwaiter.CallIterativelyWith[struct{}](&s.StopWaiterSafe, s.executeMessages, s.newMessageNotifier)
 }
@@ -0,0 +1,219 @@
+// Copyright 2024, Offchain Labs, Inc.
+// For license information, see https://github.com/nitro/blob/master/LICENSE
+
+package arbnode
+
+import (
+	"context"
+	"errors"
+	"runtime"
+	"sync"
+
+	flag "github.com/spf13/pflag"
+
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/metrics"
+
+	"github.com/offchainlabs/nitro/arbos/arbostypes"
+	"github.com/offchainlabs/nitro/arbutil"
+)
+
+var (
+	feedVerifiedCounter   = metrics.NewRegisteredCounter("arb/verifierrelay/feed/verified", nil)
+	feedMismatchCounter   = metrics.NewRegisteredCounter("arb/verifierrelay/feed/mismatch", nil)
+	verifierRelayMemGauge = metrics.NewRegisteredGauge("arb/verifierrelay/memory/heap", nil)
+)
+
+// VerifierRelayConfig configures a node that relays the feed and checks it against the batches posted to the parent chain,
+// without executing any messages or keeping chain state.
+type VerifierRelayConfig struct {
+	Enable       bool   `koanf:"enable"`
+	MemoryTarget uint64 `koanf:"memory-target" reload:"hot"`
+}
+
+var DefaultVerifierRelayConfig = VerifierRelayConfig{
+	Enable:       false,
+	MemoryTarget: 512 * 1024 * 1024,
+}
+
+func VerifierRelayConfigAddOptions(prefix string, f *flag.FlagSet) {
+	f.Bool(prefix+".enable", DefaultVerifierRelayConfig.Enable, "run without execution, only relaying the feed and verifying it against batches read from the parent chain")
+	f.Uint64(prefix+".memory-target", DefaultVerifierRelayConfig.MemoryTarget, "heap size in bytes above which the verifier relay reports itself as degraded (0 = no target)")
+}
+
+// Validate checks that only the components a verifier relay runs are enabled, given the rest of the node config.
+func (c *VerifierRelayConfig) Validate(config *Config) error {
+	if !c.Enable {
+		return nil
+	}
+	if config.Sequencer || config.DelayedSequencer.Enable || config.SeqCoordinator.Enable {
+		return errors.New("verifier relay cannot sequence")
+	}
+	if config.BatchPoster.Enable {
+		return errors.New("verifier relay cannot post batches")
+	}
+	if config.BlockValidator.Enable || config.Staker.Enable {
+		return errors.New("verifier relay cannot validate or stake, as it doesn't execute messages")
+	}
+	if !config.ParentChainReader.Enable {
+		return errors.New("verifier relay requires the parent chain reader to verify the feed")
+	}
+	if !config.Feed.Input.Enable() || !config.Feed.Output.Enable {
+		return errors.New("verifier relay requires both feed input and output")
+	}
+	return nil
+}
+
+// ConfigDefaultVerifierRelay is the preset for a verifier relay, which still needs its feed input set.
+func ConfigDefaultVerifierRelay() *Config {
+	config := ConfigDefault
+	config.Sequencer = false
+	config.DelayedSequencer.Enable = false
+	config.SeqCoordinator.Enable = false
+	config.BatchPoster.Enable = false
+	config.BlockValidator.Enable = false
+	config.Staker.Enable = false
+	config.MessagePruner.Enable = false
+	config.Feed.Output.Enable = true
+	config.VerifierRelay.Enable = true
+	return &config
+}
+
+// FeedMismatch is a feed message that was replaced by a different message in a batch.
+type FeedMismatch struct {
+	Position arbutil.MessageIndex            `json:"position"`
+	Feed     *arbostypes.MessageWithMetadata `json:"feed"`
+	Batch    *arbostypes.MessageWithMetadata `json:"batch"`
+}
+
+// FeedVerifier tracks how much of the feed has been confirmed by batches, and which feed messages weren't.
+type FeedVerifier struct {
+	mutex         sync.Mutex
+	verifiedCount arbutil.MessageIndex
+	mismatches    []FeedMismatch
+}
+
+// maxRecordedMismatches bounds the mismatches kept for the health endpoint, keeping the most recent ones.
+const maxRecordedMismatches = 64
+
+func NewFeedVerifier() *FeedVerifier {
+	return &FeedVerifier{}
+}
+
+// verified records that the messages before count match the batches posted so far.
+func (v *FeedVerifier) verified(count arbutil.MessageIndex) {
+	v.mutex.Lock()
+	defer v.mutex.Unlock()
+	if count <= v.verifiedCount {
+		return
+	}
+	// #nosec G115
+	feedVerifiedCounter.Inc(int64(count - v.verifiedCount))
+	v.verifiedCount = count
+}
+
+// mismatch records that the feed message at pos was replaced by the batch's.
+func (v *FeedVerifier) mismatch(pos arbutil.MessageIndex, feedMsg *arbostypes.MessageWithMetadata, batchMsg *arbostypes.MessageWithMetadata) {
+	log.Error("feed message doesn't match batch posted to parent chain", "pos", pos, "feed-header", feedMsg.Message.Header, "batch-header", batchMsg.Message.Header)
+	feedMismatchCounter.Inc(1)
+	v.mutex.Lock()
+	defer v.mutex.Unlock()
+	v.mismatches = append(v.mismatches, FeedMismatch{Position: pos, Feed: feedMsg, Batch: batchMsg})
+	if len(v.mismatches) > maxRecordedMismatches {
+		v.mismatches = v.mismatches[len(v.mismatches)-maxRecordedMismatches:]
+	}
+}
+
+// VerifiedCount is the number of messages confirmed by batches.
+func (v *FeedVerifier) VerifiedCount() arbutil.MessageIndex {
+	v.mutex.Lock()
+	defer v.mutex.Unlock()
+	return v.verifiedCount
+}
+
+// Mismatches returns the most recent feed messages that didn't match their batches.
+func (v *FeedVerifier) Mismatches() []FeedMismatch {
+	v.mutex.Lock()
+	defer v.mutex.Unlock()
+	return append([]FeedMismatch{}, v.mismatches...)
+}
+
+type VerifierRelayHealth struct {
+	Status        string               `json:"status"` // "ok", or "degraded" if the feed didn't match the batches or memory is over target
+	Components    []string             `json:"components"`
+	MessageCount  arbutil.MessageIndex `json:"messageCount"`
+	VerifiedCount arbutil.MessageIndex `json:"verifiedCount"`
+	Mismatches    []FeedMismatch       `json:"mismatches,omitempty"`
+	HeapBytes     uint64               `json:"heapBytes"`
+	MemoryTarget  uint64               `json:"memoryTarget"`
+	FeedClients   int32                `json:"feedClients"`
+}
+
+// VerifierRelayAPI stands in for the execution node's health endpoint when running as a verifier relay.
+type VerifierRelayAPI struct {
+	node *Node
+}
+
+func (a *VerifierRelayAPI) NodeHealth(ctx context.Context) (VerifierRelayHealth, error) {
+	return a.node.VerifierRelayHealth()
+}
+
+// VerifierRelayHealth reports the state of the components a verifier relay runs.
+func (n *Node) VerifierRelayHealth() (VerifierRelayHealth, error) {
+	health := VerifierRelayHealth{
+		Status:       "ok",
+		Components:   n.components(),
+		MemoryTarget: n.configFetcher.Get().VerifierRelay.MemoryTarget,
+	}
+	var err error
+	health.MessageCount, err = n.TxStreamer.GetMessageCount()
+	if err != nil {
+		return health, err
+	}
+	if n.FeedVerifier != nil {
+		health.VerifiedCount = n.FeedVerifier.VerifiedCount()
+		health.Mismatches = n.FeedVerifier.Mismatches()
+	}
+	if n.BroadcastServer != nil {
+		health.FeedClients = n.BroadcastServer.ClientCount()
+	}
+	var memStats runtime.MemStats
+	runtime.ReadMemStats(&memStats)
+	health.HeapBytes = memStats.HeapAlloc
+	// #nosec G115
+	verifierRelayMemGauge.Update(int64(health.HeapBytes))
+	if len(health.Mismatches) > 0 || (health.MemoryTarget != 0 && health.HeapBytes > health.MemoryTarget) {
+		health.Status = "degraded"
+	}
+	return health, nil
+}
+
+// components lists the components the node is running.
+func (n *Node) components() []string {
+	var components []string
+	if n.Execution != nil {
+		components = append(components, "execution")
+	}
+	if n.BroadcastClients != nil {
+		components = append(components, "feed-input")
+	}
+	if n.InboxReader != nil {
+		components = append(components, "inbox-reader")
+	}
+	if n.FeedVerifier != nil {
+		components = append(components, "feed-verifier")
+	}
+	if n.BroadcastServer != nil {
+		components = append(components, "feed-output")
+	}
+	if n.BatchPoster != nil {
+		components = append(components, "batch-poster")
+	}
+	if n.BlockValidator != nil {
+		components = append(components, "block-validator")
+	}
+	if n.Staker != nil {
+		components = append(components, "staker")
+	}
+	return components
+}
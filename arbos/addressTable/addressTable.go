@@ -8,6 +8,7 @@ package addressTable
 import (
 	"bytes"
 	"errors"
+	"fmt"
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/rlp"
@@ -16,6 +17,11 @@ import (
 	"github.com/offchainlabs/nitro/arbos/util"
 )
 
+var (
+	ErrInvalidEncoding = errors.New("invalid encoding of compressed address")
+	ErrInvalidIndex    = errors.New("invalid index in compressed address")
+)
+
 type AddressTable struct {
 	backingStorage *storage.Storage
 	byAddress      *storage.Storage // 0 means item isn't in the table; n > 0 means it's in the table at slot n-1
@@ -55,6 +61,19 @@ func (atab *AddressTable) Register(addr common.Address) (uint64, error) {
 	return newNumItems - 1, nil
 }
 
+// RegisterMany registers each address that isn't in the table yet, returning the indices of all of them
+func (atab *AddressTable) RegisterMany(addrs []common.Address) ([]uint64, error) {
+	indices := make([]uint64, 0, len(addrs))
+	for _, addr := range addrs {
+		index, err := atab.Register(addr)
+		if err != nil {
+			return nil, err
+		}
+		indices = append(indices, index)
+	}
+	return indices, nil
+}
+
 func (atab *AddressTable) Lookup(addr common.Address) (uint64, bool, error) {
 	addrAsHash := common.BytesToHash(addr.Bytes())
 	res, err := atab.byAddress.GetUint64(addrAsHash)
@@ -65,6 +84,21 @@ func (atab *AddressTable) Lookup(addr common.Address) (uint64, bool, error) {
 	}
 }
 
+// LookupMany looks up each address, returning 0 as the index of those not in the table
+func (atab *AddressTable) LookupMany(addrs []common.Address) ([]uint64, []bool, error) {
+	indices := make([]uint64, 0, len(addrs))
+	exists := make([]bool, 0, len(addrs))
+	for _, addr := range addrs {
+		index, found, err := atab.Lookup(addr)
+		if err != nil {
+			return nil, nil, err
+		}
+		indices = append(indices, index)
+		exists = append(exists, found)
+	}
+	return indices, exists, nil
+}
+
 func (atab *AddressTable) AddressExists(addr common.Address) (bool, error) {
 	_, ret, err := atab.Lookup(addr)
 	return ret, err
@@ -101,7 +135,7 @@ func (atab *AddressTable) Decompress(buf []byte) (common.Address, uint64, error)
 	decoder := rlp.NewStream(rd, 21)
 	input, err := decoder.Bytes()
 	if err != nil {
-		return common.Address{}, 0, err
+		return common.Address{}, 0, fmt.Errorf("%w: %w", ErrInvalidEncoding, err)
 	}
 	if len(input) == 20 {
 		// #nosec G115
@@ -111,14 +145,14 @@ func (atab *AddressTable) Decompress(buf []byte) (common.Address, uint64, error)
 		rd = bytes.NewReader(buf)
 		index, err := rlp.NewStream(rd, 9).Uint64()
 		if err != nil {
-			return common.Address{}, 0, err
+			return common.Address{}, 0, fmt.Errorf("%w: %w", ErrInvalidEncoding, err)
 		}
 		addr, exists, err := atab.LookupIndex(index)
 		if err != nil {
 			return common.Address{}, 0, err
 		}
 		if !exists {
-			return common.Address{}, 0, errors.New("invalid index in compressed address")
+			return common.Address{}, 0, ErrInvalidIndex
 		}
 		// #nosec G115
 		numBytesRead := uint64(rd.Size() - int64(rd.Len()))
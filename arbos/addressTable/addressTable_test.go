@@ -5,6 +5,7 @@ package addressTable
 
 import (
 	"bytes"
+	"errors"
 	"testing"
 
 	"github.com/ethereum/go-ethereum/common"
@@ -35,6 +36,35 @@ func TestAddressTableInit(t *testing.T) {
 	}
 }
 
+func TestAddressTableMany(t *testing.T) {
+	sto := storage.NewMemoryBacked(burn.NewSystemBurner(nil, false))
+	Initialize(sto)
+	atab := Open(sto)
+	addr1 := common.BytesToAddress(crypto.Keccak256([]byte{1})[:20])
+	addr2 := common.BytesToAddress(crypto.Keccak256([]byte{2})[:20])
+	addr3 := common.BytesToAddress(crypto.Keccak256([]byte{3})[:20])
+
+	_, err := atab.Register(addr2)
+	Require(t, err)
+	indices, err := atab.RegisterMany([]common.Address{addr1, addr2, addr1})
+	Require(t, err)
+	if len(indices) != 3 || indices[0] != 1 || indices[1] != 0 || indices[2] != 1 {
+		Fail(t, indices)
+	}
+	if size(t, atab) != 2 {
+		Fail(t)
+	}
+
+	indices, exists, err := atab.LookupMany([]common.Address{addr3, addr2, addr1})
+	Require(t, err)
+	if len(indices) != 3 || indices[1] != 0 || indices[2] != 1 {
+		Fail(t, indices)
+	}
+	if len(exists) != 3 || exists[0] || !exists[1] || !exists[2] {
+		Fail(t, exists)
+	}
+}
+
 func TestAddressTable1(t *testing.T) {
 	sto := storage.NewMemoryBacked(burn.NewSystemBurner(nil, false))
 	Initialize(sto)
@@ -135,6 +165,21 @@ func TestAddressTableCompressInTable(t *testing.T) {
 	}
 }
 
+func TestAddressTableDecompressInvalid(t *testing.T) {
+	sto := storage.NewMemoryBacked(burn.NewSystemBurner(nil, false))
+	Initialize(sto)
+	atab := Open(sto)
+
+	for _, buf := range [][]byte{{}, {0x94, 0x01}, {0x81, 0x05}, {0x95}} {
+		if _, _, err := atab.Decompress(buf); !errors.Is(err, ErrInvalidEncoding) {
+			Fail(t, "decompressing", buf, "expected invalid encoding, got", err)
+		}
+	}
+	if _, _, err := atab.Decompress([]byte{0x05}); !errors.Is(err, ErrInvalidIndex) {
+		Fail(t, "expected invalid index, got", err)
+	}
+}
+
 func size(t *testing.T, atab *AddressTable) uint64 {
 	size, err := atab.Size()
 	Require(t, err)
@@ -4,6 +4,7 @@
 package arbosState
 
 import (
+	"encoding/binary"
 	"errors"
 	"fmt"
 	"math/big"
@@ -30,8 +31,10 @@ import (
 	"github.com/offchainlabs/nitro/arbos/programs"
 	"github.com/offchainlabs/nitro/arbos/retryables"
 	"github.com/offchainlabs/nitro/arbos/storage"
+	"github.com/offchainlabs/nitro/arbos/tipdistribution"
 	"github.com/offchainlabs/nitro/arbos/util"
 	"github.com/offchainlabs/nitro/cmd/chaininfo"
+	"github.com/offchainlabs/nitro/util/arbmath"
 	"github.com/offchainlabs/nitro/util/testhelpers/env"
 )
 
@@ -57,7 +60,12 @@ type ArbosState struct {
 	chainConfig            storage.StorageBackedBytes
 	genesisBlockNum        storage.StorageBackedUint64
 	infraFeeAccount        storage.StorageBackedAddress
-	brotliCompressionLevel storage.StorageBackedUint64 // brotli compression level used for pricing
+	brotliCompressionLevel storage.StorageBackedUint64  // brotli compression level used for pricing
+	totalGasUsed           storage.StorageBackedUint64  // cumulative L2 gas used by transactions
+	autoRedeemGasLimit     storage.StorageBackedUint64  // max gas given to a retryable's auto-redeem, or 0 if unlimited
+	gasPaymaster           storage.StorageBackedAddress // pays the gas of transactions offering no fee, or 0 if none
+	uncompressedBatches    storage.StorageBackedUint64  // 1 if batches should be posted uncompressed
+	precompileGasAudit     storage.StorageBackedUint64  // 1 if precompile calls should log their gas usage
 	backingStorage         *storage.Storage
 	Burner                 burn.Burner
 }
@@ -92,6 +100,11 @@ func OpenArbosState(stateDB vm.StateDB, burner burn.Burner) (*ArbosState, error)
 		backingStorage.OpenStorageBackedUint64(uint64(genesisBlockNumOffset)),
 		backingStorage.OpenStorageBackedAddress(uint64(infraFeeAccountOffset)),
 		backingStorage.OpenStorageBackedUint64(uint64(brotliCompressionLevelOffset)),
+		backingStorage.OpenStorageBackedUint64(uint64(totalGasUsedOffset)),
+		backingStorage.OpenStorageBackedUint64(uint64(autoRedeemGasLimitOffset)),
+		backingStorage.OpenStorageBackedAddress(uint64(gasPaymasterOffset)),
+		backingStorage.OpenStorageBackedUint64(uint64(uncompressedBatchesOffset)),
+		backingStorage.OpenStorageBackedUint64(uint64(precompileGasAuditOffset)),
 		backingStorage,
 		burner,
 	}, nil
@@ -154,6 +167,11 @@ const (
 	genesisBlockNumOffset
 	infraFeeAccountOffset
 	brotliCompressionLevelOffset
+	totalGasUsedOffset
+	autoRedeemGasLimitOffset
+	gasPaymasterOffset
+	uncompressedBatchesOffset
+	precompileGasAuditOffset
 )
 
 type SubspaceID []byte
@@ -168,6 +186,15 @@ var (
 	blockhashesSubspace  SubspaceID = []byte{6}
 	chainConfigSubspace  SubspaceID = []byte{7}
 	programsSubspace     SubspaceID = []byte{8}
+	// upgrades scheduled after the one in upgradeVersion and upgradeTimestamp
+	scheduledUpgradesSubspace SubspaceID = []byte{9}
+	// the active compression dictionary's id at 0, and each dictionary's hash at its id
+	compressionDictionariesSubspace SubspaceID = []byte{10}
+	// 11 is unused
+	tipDistributionSubspace SubspaceID = []byte{12}
+	chainNamespaceSubspace  SubspaceID = []byte{13}
+	// the senders whose transactions offering no fee the gas paymaster pays for
+	gasPaymasterSponsoredSubspace SubspaceID = []byte{14}
 )
 
 var PrecompileMinArbOSVersions = make(map[common.Address]uint64)
@@ -243,13 +270,23 @@ func InitializeArbosState(stateDB vm.StateDB, burner burn.Burner, chainConfig *p
 func (state *ArbosState) UpgradeArbosVersionIfNecessary(
 	currentTimestamp uint64, stateDB vm.StateDB, chainConfig *params.ChainConfig,
 ) error {
-	upgradeTo, err := state.upgradeVersion.Get()
-	state.Restrict(err)
-	flagday, _ := state.upgradeTimestamp.Get()
-	if state.arbosVersion < upgradeTo && currentTimestamp >= flagday {
-		return state.UpgradeArbosVersion(upgradeTo, false, stateDB, chainConfig)
+	for {
+		upgradeTo, err := state.upgradeVersion.Get()
+		state.Restrict(err)
+		flagday, _ := state.upgradeTimestamp.Get()
+		if state.arbosVersion >= upgradeTo || currentTimestamp < flagday {
+			return nil
+		}
+		if err := state.UpgradeArbosVersion(upgradeTo, false, stateDB, chainConfig); err != nil {
+			return err
+		}
+		// make the next queued upgrade, if any, the one to wait for
+		promoted, err := state.promoteScheduledUpgrade()
+		state.Restrict(err)
+		if !promoted {
+			return nil
+		}
 	}
-	return nil
 }
 
 var ErrFatalNodeOutOfDate = errors.New("please upgrade to the latest version of the node software")
@@ -331,6 +368,12 @@ func (state *ArbosState) UpgradeArbosVersion(
 		case params.ArbosVersion_32:
 			// no change state needed
 
+		case 33, 34, 35, 36, 37, 38, 39:
+			// these versions are left to Orbit chains for custom upgrades.
+
+		case util.ArbosVersion_40:
+			// no change state needed
+
 		default:
 			return fmt.Errorf(
 				"the chain is upgrading to unsupported ArbOS version %v, %w",
@@ -371,6 +414,131 @@ func (state *ArbosState) ScheduleArbOSUpgrade(newVersion uint64, timestamp uint6
 	return state.upgradeTimestamp.Set(timestamp)
 }
 
+type ScheduledUpgrade struct {
+	Version   uint64
+	Timestamp uint64
+}
+
+// scheduledUpgrades opens the queue of upgrades after the next one.
+// The queue is only initialized once an upgrade is first queued, so existing chains' state is unchanged until then.
+func (state *ArbosState) scheduledUpgrades(initialize bool) (*storage.Queue, error) {
+	sto := state.backingStorage.OpenSubStorage(scheduledUpgradesSubspace)
+	if initialize {
+		nextPut, err := sto.GetUint64ByUint64(0)
+		if err != nil {
+			return nil, err
+		}
+		if nextPut == 0 {
+			if err := storage.InitializeQueue(sto); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return storage.OpenQueue(sto), nil
+}
+
+func packScheduledUpgrade(version uint64, timestamp uint64) common.Hash {
+	var packed common.Hash
+	binary.BigEndian.PutUint64(packed[16:24], version)
+	binary.BigEndian.PutUint64(packed[24:32], timestamp)
+	return packed
+}
+
+func unpackScheduledUpgrade(packed common.Hash) ScheduledUpgrade {
+	return ScheduledUpgrade{
+		Version:   binary.BigEndian.Uint64(packed[16:24]),
+		Timestamp: binary.BigEndian.Uint64(packed[24:32]),
+	}
+}
+
+// QueueArbOSUpgrade schedules an upgrade to happen after any already pending ones.
+// The caller is responsible for ensuring the version is higher than those of the pending upgrades.
+func (state *ArbosState) QueueArbOSUpgrade(newVersion uint64, timestamp uint64) error {
+	nextVersion, err := state.upgradeVersion.Get()
+	if err != nil {
+		return err
+	}
+	if state.arbosVersion >= nextVersion {
+		// nothing is pending, so this is the next upgrade
+		return state.ScheduleArbOSUpgrade(newVersion, timestamp)
+	}
+	queue, err := state.scheduledUpgrades(true)
+	if err != nil {
+		return err
+	}
+	return queue.Put(packScheduledUpgrade(newVersion, timestamp))
+}
+
+// PendingArbOSUpgrades returns the upgrades that haven't happened yet, in the order they'll happen.
+func (state *ArbosState) PendingArbOSUpgrades() ([]ScheduledUpgrade, error) {
+	version, timestamp, err := state.GetScheduledUpgrade()
+	if err != nil || state.arbosVersion >= version {
+		return nil, err
+	}
+	pending := []ScheduledUpgrade{{version, timestamp}}
+	queue, err := state.scheduledUpgrades(false)
+	if err != nil {
+		return nil, err
+	}
+	err = queue.ForEach(func(_ uint64, packed common.Hash) (bool, error) {
+		pending = append(pending, unpackScheduledUpgrade(packed))
+		return false, nil
+	})
+	return pending, err
+}
+
+// ReplacePendingArbOSUpgrades replaces the upgrades that haven't happened yet with upgrades, which are expected
+// to be in the order they'll happen. No upgrades cancels all of them.
+func (state *ArbosState) ReplacePendingArbOSUpgrades(upgrades []ScheduledUpgrade) error {
+	queue, err := state.scheduledUpgrades(false)
+	if err != nil {
+		return err
+	}
+	for {
+		packed, err := queue.Get()
+		if err != nil {
+			return err
+		}
+		if packed == nil {
+			break
+		}
+	}
+	if len(upgrades) == 0 {
+		return state.ScheduleArbOSUpgrade(0, 0)
+	}
+	if err := state.ScheduleArbOSUpgrade(upgrades[0].Version, upgrades[0].Timestamp); err != nil {
+		return err
+	}
+	if len(upgrades) == 1 {
+		return nil
+	}
+	queue, err = state.scheduledUpgrades(true)
+	if err != nil {
+		return err
+	}
+	for _, upgrade := range upgrades[1:] {
+		if err := queue.Put(packScheduledUpgrade(upgrade.Version, upgrade.Timestamp)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// promoteScheduledUpgrade moves the first queued upgrade into the next upgrade's slot,
+// returning false if none are queued.
+func (state *ArbosState) promoteScheduledUpgrade() (bool, error) {
+	queue, err := state.scheduledUpgrades(false)
+	if err != nil {
+		return false, err
+	}
+	packed, err := queue.Get()
+	if err != nil || packed == nil {
+		return false, err
+	}
+	next := unpackScheduledUpgrade(*packed)
+	return true, state.ScheduleArbOSUpgrade(next.Version, next.Timestamp)
+}
+
 func (state *ArbosState) GetScheduledUpgrade() (uint64, uint64, error) {
 	version, err := state.upgradeVersion.Get()
 	if err != nil {
@@ -411,6 +579,113 @@ func (state *ArbosState) SetBrotliCompressionLevel(val uint64) error {
 	return errors.New("invalid brotli compression level")
 }
 
+// L1TxBatchCompressionDisabled returns whether batches should be posted uncompressed, for debugging and benchmarking.
+func (state *ArbosState) L1TxBatchCompressionDisabled() (bool, error) {
+	disabled, err := state.uncompressedBatches.Get()
+	return disabled != 0, err
+}
+
+func (state *ArbosState) SetL1TxBatchCompressionDisabled(disabled bool) error {
+	if disabled {
+		return state.uncompressedBatches.Set(1)
+	}
+	return state.uncompressedBatches.Set(0)
+}
+
+// PrecompileGasAudit returns whether precompile calls should log the gas they charge and the storage they access,
+// for auditing their gas costs on debug chains.
+func (state *ArbosState) PrecompileGasAudit() (bool, error) {
+	enabled, err := state.precompileGasAudit.Get()
+	return enabled != 0, err
+}
+
+func (state *ArbosState) SetPrecompileGasAudit(enabled bool) error {
+	if enabled {
+		return state.precompileGasAudit.Set(1)
+	}
+	return state.precompileGasAudit.Set(0)
+}
+
+// PrecompileGasAuditEnabled reads PrecompileGasAudit without charging for it, like ArbOSVersion.
+func PrecompileGasAuditEnabled(stateDB vm.StateDB) bool {
+	backingStorage := storage.NewGeth(stateDB, burn.NewSystemBurner(nil, false))
+	enabled, err := backingStorage.GetUint64ByUint64(uint64(precompileGasAuditOffset))
+	return err == nil && enabled != 0
+}
+
+// MaxCompressionDictionarySize is the largest dictionary batches can be compressed with.
+const MaxCompressionDictionarySize = 64 * 1024
+
+// CompressionDictionaryHash returns the hash of the dictionary with the id, or zero if there isn't one.
+func (state *ArbosState) CompressionDictionaryHash(id uint8) (common.Hash, error) {
+	if id == 0 {
+		return common.Hash{}, nil
+	}
+	return state.backingStorage.OpenSubStorage(compressionDictionariesSubspace).GetByUint64(uint64(id))
+}
+
+// SetCompressionDictionary sets the hash of the dictionary with the id and makes it the active dictionary.
+// A zero hash removes the dictionary, leaving no dictionary active if it was.
+func (state *ArbosState) SetCompressionDictionary(id uint8, hash common.Hash) error {
+	if id == 0 {
+		return errors.New("compression dictionary id 0 is reserved")
+	}
+	sto := state.backingStorage.OpenSubStorage(compressionDictionariesSubspace)
+	if hash == (common.Hash{}) {
+		active, err := sto.GetUint64ByUint64(0)
+		if err != nil {
+			return err
+		}
+		if active == uint64(id) {
+			if err := sto.ClearByUint64(0); err != nil {
+				return err
+			}
+		}
+		return sto.ClearByUint64(uint64(id))
+	}
+	if err := sto.SetByUint64(uint64(id), hash); err != nil {
+		return err
+	}
+	return sto.SetUint64ByUint64(0, uint64(id))
+}
+
+// ActiveCompressionDictionary returns the id and hash of the dictionary batches should be compressed with,
+// or zeros if there isn't one.
+func (state *ArbosState) ActiveCompressionDictionary() (uint8, common.Hash, error) {
+	active, err := state.backingStorage.OpenSubStorage(compressionDictionariesSubspace).GetUint64ByUint64(0)
+	if err != nil || active == 0 {
+		return 0, common.Hash{}, err
+	}
+	hash, err := state.CompressionDictionaryHash(uint8(active))
+	return uint8(active), hash, err
+}
+
+func (state *ArbosState) TotalGasUsed() (uint64, error) {
+	return state.totalGasUsed.Get()
+}
+
+func (state *ArbosState) AddToTotalGasUsed(gas uint64) error {
+	total, err := state.totalGasUsed.Get()
+	if err != nil {
+		return err
+	}
+	return state.totalGasUsed.Set(arbmath.SaturatingUAdd(total, gas))
+}
+
+func (state *ArbosState) RetryableAutoRedeemGasLimit() (uint64, error) {
+	return state.autoRedeemGasLimit.Get()
+}
+
+func (state *ArbosState) SetRetryableAutoRedeemGasLimit(limit uint64) error {
+	if state.arbosVersion < util.ArbosVersion_40 {
+		return errors.New("auto-redeem gas limit isn't supported before ArbOS 40")
+	}
+	if limit != 0 && limit < params.TxGas {
+		return errors.New("auto-redeem gas limit is below the minimum transaction gas")
+	}
+	return state.autoRedeemGasLimit.Set(limit)
+}
+
 func (state *ArbosState) RetryableState() *retryables.RetryableState {
 	return state.retryableState
 }
@@ -442,6 +717,11 @@ func (state *ArbosState) Programs() *programs.Programs {
 	return state.programs
 }
 
+// TipDistribution is opened on demand, as it's only used once a chain owner configures it
+func (state *ArbosState) TipDistribution() *tipdistribution.TipDistribution {
+	return tipdistribution.Open(state.backingStorage.OpenCachedSubStorage(tipDistributionSubspace))
+}
+
 func (state *ArbosState) Blockhashes() *blockhash.Blockhashes {
 	return state.blockhashes
 }
@@ -462,6 +742,19 @@ func (state *ArbosState) SetInfraFeeAccount(account common.Address) error {
 	return state.infraFeeAccount.Set(account)
 }
 
+func (state *ArbosState) GasPaymaster() (common.Address, error) {
+	return state.gasPaymaster.Get()
+}
+
+func (state *ArbosState) SetGasPaymaster(paymaster common.Address) error {
+	return state.gasPaymaster.Set(paymaster)
+}
+
+// GasPaymasterSponsoredSenders are the senders the gas paymaster pays for, so it can't be drained by anyone else
+func (state *ArbosState) GasPaymasterSponsoredSenders() *addressSet.AddressSet {
+	return addressSet.OpenAddressSet(state.backingStorage.OpenCachedSubStorage(gasPaymasterSponsoredSubspace))
+}
+
 func (state *ArbosState) Keccak(data ...[]byte) ([]byte, error) {
 	return state.backingStorage.Keccak(data...)
 }
@@ -482,6 +775,22 @@ func (state *ArbosState) SetChainConfig(serializedChainConfig []byte) error {
 	return state.chainConfig.Set(serializedChainConfig)
 }
 
+// MaxChainNamespaceLength is the maximum length in bytes of a chain's namespace
+const MaxChainNamespaceLength = 32
+
+// ChainNamespace is the human-readable identifier the chain owner gave the chain, or empty if it hasn't been set.
+func (state *ArbosState) ChainNamespace() (string, error) {
+	namespace, err := state.backingStorage.OpenStorageBackedBytes(chainNamespaceSubspace).Get()
+	return string(namespace), err
+}
+
+func (state *ArbosState) SetChainNamespace(namespace string) error {
+	if len(namespace) > MaxChainNamespaceLength {
+		return fmt.Errorf("chain namespace is %d bytes, more than the maximum of %d", len(namespace), MaxChainNamespaceLength)
+	}
+	return state.backingStorage.OpenStorageBackedBytes(chainNamespaceSubspace).Set([]byte(namespace))
+}
+
 func (state *ArbosState) GenesisBlockNum() (uint64, error) {
 	return state.genesisBlockNum.Get()
 }
@@ -8,10 +8,17 @@ import (
 	"testing"
 
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/ethereum/go-ethereum/triedb"
+	"github.com/ethereum/go-ethereum/triedb/hashdb"
 
+	"github.com/offchainlabs/nitro/arbos/arbostypes"
 	"github.com/offchainlabs/nitro/arbos/burn"
 	"github.com/offchainlabs/nitro/arbos/storage"
 	"github.com/offchainlabs/nitro/arbos/util"
+	"github.com/offchainlabs/nitro/cmd/chaininfo"
 	"github.com/offchainlabs/nitro/util/colors"
 )
 
@@ -92,3 +99,125 @@ func TestStorageSlots(t *testing.T) {
 		Fail(t, "page offset mismatch")
 	}
 }
+
+func TestScheduledUpgradesQueue(t *testing.T) {
+	db := state.NewDatabaseWithConfig(rawdb.NewMemoryDatabase(), &triedb.Config{Preimages: false, HashDB: hashdb.Defaults})
+	statedb, err := state.New(common.Hash{}, db, nil)
+	Require(t, err)
+	chainConfig := chaininfo.ArbitrumDevTestChainConfig()
+	chainConfig.ArbitrumChainParams.InitialArbOSVersion = params.ArbosVersion_30
+	arbState, err := InitializeArbosState(statedb, burn.NewSystemBurner(nil, false), chainConfig, arbostypes.TestInitMessage)
+	Require(t, err)
+
+	checkPending := func(expected ...ScheduledUpgrade) {
+		t.Helper()
+		pending, err := arbState.PendingArbOSUpgrades()
+		Require(t, err)
+		if len(pending) != len(expected) {
+			Fail(t, "expected pending upgrades", expected, "got", pending)
+		}
+		for i := range pending {
+			if pending[i] != expected[i] {
+				Fail(t, "expected pending upgrades", expected, "got", pending)
+			}
+		}
+	}
+	checkPending()
+
+	Require(t, arbState.QueueArbOSUpgrade(params.ArbosVersion_31, 10))
+	Require(t, arbState.QueueArbOSUpgrade(params.ArbosVersion_32, 20))
+	checkPending(ScheduledUpgrade{params.ArbosVersion_31, 10}, ScheduledUpgrade{params.ArbosVersion_32, 20})
+
+	Require(t, arbState.UpgradeArbosVersionIfNecessary(5, statedb, chainConfig))
+	if arbState.ArbOSVersion() != params.ArbosVersion_30 {
+		Fail(t, "upgraded before the scheduled timestamp")
+	}
+
+	// the completed upgrade disappears and the next one takes its place
+	Require(t, arbState.UpgradeArbosVersionIfNecessary(15, statedb, chainConfig))
+	if arbState.ArbOSVersion() != params.ArbosVersion_31 {
+		Fail(t, "expected ArbOS version", params.ArbosVersion_31, "got", arbState.ArbOSVersion())
+	}
+	checkPending(ScheduledUpgrade{params.ArbosVersion_32, 20})
+
+	Require(t, arbState.UpgradeArbosVersionIfNecessary(25, statedb, chainConfig))
+	if arbState.ArbOSVersion() != params.ArbosVersion_32 {
+		Fail(t, "expected ArbOS version", params.ArbosVersion_32, "got", arbState.ArbOSVersion())
+	}
+	checkPending()
+
+	// replacing the pending upgrades reschedules them, and replacing them with none cancels them
+	Require(t, arbState.QueueArbOSUpgrade(100, 30))
+	Require(t, arbState.QueueArbOSUpgrade(101, 40))
+	Require(t, arbState.QueueArbOSUpgrade(102, 50))
+	rescheduled := []ScheduledUpgrade{{100, 30}, {101, 45}, {102, 50}}
+	Require(t, arbState.ReplacePendingArbOSUpgrades(rescheduled))
+	checkPending(rescheduled...)
+	Require(t, arbState.ReplacePendingArbOSUpgrades(nil))
+	checkPending()
+	Require(t, arbState.UpgradeArbosVersionIfNecessary(60, statedb, chainConfig))
+	if arbState.ArbOSVersion() != params.ArbosVersion_32 {
+		Fail(t, "expected canceled upgrades not to happen, got ArbOS version", arbState.ArbOSVersion())
+	}
+}
+
+func TestCompressionDictionaries(t *testing.T) {
+	arbState, _ := NewArbosMemoryBackedArbOSState()
+	checkActive := func(expectedId uint8, expectedHash common.Hash) {
+		t.Helper()
+		id, hash, err := arbState.ActiveCompressionDictionary()
+		Require(t, err)
+		if id != expectedId || hash != expectedHash {
+			Fail(t, "expected active dictionary", expectedId, expectedHash, "got", id, hash)
+		}
+	}
+	checkActive(0, common.Hash{})
+
+	first, second := common.HexToHash("0x01"), common.HexToHash("0x02")
+	Require(t, arbState.SetCompressionDictionary(1, first))
+	Require(t, arbState.SetCompressionDictionary(2, second))
+	checkActive(2, second)
+
+	// removing an inactive dictionary leaves the active one alone
+	Require(t, arbState.SetCompressionDictionary(1, common.Hash{}))
+	checkActive(2, second)
+	hash, err := arbState.CompressionDictionaryHash(1)
+	Require(t, err)
+	if hash != (common.Hash{}) {
+		Fail(t, "removed dictionary still has hash", hash)
+	}
+
+	Require(t, arbState.SetCompressionDictionary(2, common.Hash{}))
+	checkActive(0, common.Hash{})
+
+	if arbState.SetCompressionDictionary(0, first) == nil {
+		Fail(t, "set the reserved dictionary id")
+	}
+}
+
+func TestRetryableAutoRedeemGasLimitVersionGate(t *testing.T) {
+	db := state.NewDatabaseWithConfig(rawdb.NewMemoryDatabase(), &triedb.Config{Preimages: false, HashDB: hashdb.Defaults})
+	statedb, err := state.New(common.Hash{}, db, nil)
+	Require(t, err)
+	chainConfig := chaininfo.ArbitrumDevTestChainConfig()
+	chainConfig.ArbitrumChainParams.InitialArbOSVersion = params.ArbosVersion_32
+	arbState, err := InitializeArbosState(statedb, burn.NewSystemBurner(nil, false), chainConfig, arbostypes.TestInitMessage)
+	Require(t, err)
+
+	if err := arbState.SetRetryableAutoRedeemGasLimit(params.TxGas); err == nil {
+		Fail(t, "expected setting the auto-redeem gas limit before ArbOS 40 to fail")
+	}
+	limit, err := arbState.RetryableAutoRedeemGasLimit()
+	Require(t, err)
+	if limit != 0 {
+		Fail(t, "expected no auto-redeem gas limit, got", limit)
+	}
+
+	Require(t, arbState.UpgradeArbosVersion(util.ArbosVersion_40, false, statedb, chainConfig))
+	Require(t, arbState.SetRetryableAutoRedeemGasLimit(params.TxGas))
+	limit, err = arbState.RetryableAutoRedeemGasLimit()
+	Require(t, err)
+	if limit != params.TxGas {
+		Fail(t, "expected auto-redeem gas limit", params.TxGas, "got", limit)
+	}
+}
@@ -128,6 +128,19 @@ func (msg *L1IncomingMessage) Equals(other *L1IncomingMessage) bool {
 	return msg.Header.Equals(other.Header) && bytes.Equal(msg.L2msg, other.L2msg)
 }
 
+// DelayedInboxHash is the hash the delayed inbox accumulates for this message.
+func (msg *L1IncomingMessage) DelayedInboxHash() common.Hash {
+	return crypto.Keccak256Hash(
+		[]byte{msg.Header.Kind},
+		msg.Header.Poster.Bytes(),
+		arbmath.UintToBytes(msg.Header.BlockNumber),
+		arbmath.UintToBytes(msg.Header.Timestamp),
+		msg.Header.RequestId.Bytes(),
+		arbmath.U256Bytes(msg.Header.L1BaseFee),
+		crypto.Keccak256(msg.L2msg),
+	)
+}
+
 func hashesEqual(ha, hb *common.Hash) bool {
 	if (ha == nil) != (hb == nil) {
 		return false
@@ -11,19 +11,33 @@ import (
 	"github.com/ethereum/go-ethereum/params"
 
 	"github.com/offchainlabs/nitro/arbos/storage"
+	"github.com/offchainlabs/nitro/util/arbmath"
 )
 
 type Blockhashes struct {
-	backingStorage *storage.Storage
-	l1BlockNumber  storage.StorageBackedUint64
+	backingStorage   *storage.Storage
+	l1BlockNumber    storage.StorageBackedUint64
+	l1BlockTime      storage.StorageBackedUint64 // the expected timestamp of the current L1 block
+	l1BlockStaleness storage.StorageBackedUint64
 }
 
+// the staleness of the L1 block number is stored after the 256 block hashes
+const (
+	l1BlockTimeOffset      uint64 = 257
+	l1BlockStalenessOffset uint64 = 258
+)
+
 func InitializeBlockhashes(backingStorage *storage.Storage) {
 	// no need to do anything, nextBlockNumber is already zero and no hashes are needed when nextBlockNumber is zero
 }
 
 func OpenBlockhashes(backingStorage *storage.Storage) *Blockhashes {
-	return &Blockhashes{backingStorage.WithoutCache(), backingStorage.OpenStorageBackedUint64(0)}
+	return &Blockhashes{
+		backingStorage.WithoutCache(),
+		backingStorage.OpenStorageBackedUint64(0),
+		backingStorage.OpenStorageBackedUint64(l1BlockTimeOffset),
+		backingStorage.OpenStorageBackedUint64(l1BlockStalenessOffset),
+	}
 }
 
 func (bh *Blockhashes) L1BlockNumber() (uint64, error) {
@@ -77,3 +91,28 @@ func (bh *Blockhashes) RecordNewL1Block(number uint64, blockHash common.Hash, ar
 	}
 	return bh.l1BlockNumber.Set(number + 1)
 }
+
+// UpdateL1BlockStaleness records how many seconds the current L1 block's expected timestamp is before the
+// L2 block's time. L1 timestamps aren't available, so the first L1 block is expected at the time it's reported,
+// and each after that the expected seconds between L1 blocks later, though never later than it's reported.
+func (bh *Blockhashes) UpdateL1BlockStaleness(l1BlocksAdvanced, l1BlockSeconds, currentTime uint64) error {
+	expectedTime, err := bh.l1BlockTime.Get()
+	if err != nil {
+		return err
+	}
+	if expectedTime == 0 {
+		expectedTime = currentTime
+	} else if l1BlocksAdvanced > 0 {
+		expectedTime = arbmath.SaturatingUAdd(expectedTime, arbmath.SaturatingUMul(l1BlocksAdvanced, l1BlockSeconds))
+		expectedTime = arbmath.MinInt(expectedTime, currentTime)
+	}
+	if err := bh.l1BlockTime.Set(expectedTime); err != nil {
+		return err
+	}
+	return bh.l1BlockStaleness.Set(arbmath.SaturatingUSub(currentTime, expectedTime))
+}
+
+// L1BlockStaleness gets the staleness of the L1 block number in seconds, as of the last L2 block.
+func (bh *Blockhashes) L1BlockStaleness() (uint64, error) {
+	return bh.l1BlockStaleness.Get()
+}
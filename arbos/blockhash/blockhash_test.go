@@ -89,6 +89,46 @@ func TestBlockhash(t *testing.T) {
 
 }
 
+func TestL1BlockStaleness(t *testing.T) {
+	sto := storage.NewMemoryBacked(burn.NewSystemBurner(nil, false))
+	InitializeBlockhashes(sto)
+	bh := OpenBlockhashes(sto)
+
+	expectStaleness := func(expected uint64) {
+		t.Helper()
+		staleness, err := bh.L1BlockStaleness()
+		Require(t, err)
+		if staleness != expected {
+			Fail(t, "expected staleness", expected, "got", staleness)
+		}
+	}
+
+	// the first update expects the L1 block at the time it's reported
+	Require(t, bh.UpdateL1BlockStaleness(1, 12, 1000))
+	expectStaleness(0)
+	Require(t, bh.UpdateL1BlockStaleness(0, 12, 1005))
+	expectStaleness(5)
+	Require(t, bh.UpdateL1BlockStaleness(0, 12, 1030))
+	expectStaleness(30)
+
+	// each new L1 block is expected 12 seconds after the last
+	Require(t, bh.UpdateL1BlockStaleness(1, 12, 1031))
+	expectStaleness(19)
+	Require(t, bh.UpdateL1BlockStaleness(0, 12, 1035))
+	expectStaleness(23)
+
+	// but not after it's reported
+	Require(t, bh.UpdateL1BlockStaleness(5, 12, 1040))
+	expectStaleness(0)
+
+	// the block hashes are left alone
+	bnum, err := bh.L1BlockNumber()
+	Require(t, err)
+	if bnum != 0 {
+		Fail(t, "tracking staleness changed the L1 block number to", bnum)
+	}
+}
+
 func Require(t *testing.T, err error, printables ...interface{}) {
 	t.Helper()
 	testhelpers.RequireImpl(t, err, printables...)
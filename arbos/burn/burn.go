@@ -22,6 +22,11 @@ type Burner interface {
 	TracingInfo() *util.TracingInfo
 }
 
+// StorageAccessCounter is implemented by burners that count the storage accesses they're charged for
+type StorageAccessCounter interface {
+	CountStorageAccess(write bool)
+}
+
 type SystemBurner struct {
 	gasBurnt    uint64
 	tracingInfo *util.TracingInfo
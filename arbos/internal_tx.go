@@ -5,6 +5,7 @@ package arbos
 
 import (
 	"encoding/hex"
+	"errors"
 	"fmt"
 	"math/big"
 
@@ -15,6 +16,7 @@ import (
 	"github.com/ethereum/go-ethereum/params"
 
 	"github.com/offchainlabs/nitro/arbos/arbosState"
+	"github.com/offchainlabs/nitro/arbos/l1pricing"
 	"github.com/offchainlabs/nitro/arbos/util"
 	"github.com/offchainlabs/nitro/util/arbmath"
 )
@@ -82,6 +84,13 @@ func ApplyInternalTxUpdate(tx *types.ArbitrumInternalTx, state *arbosState.Arbos
 
 		currentTime := evm.Context.Time
 
+		if state.ArbOSVersion() >= util.ArbosVersion_40 {
+			l1BlockSeconds, err := state.L1PricingState().L1BlockTimeSeconds()
+			state.Restrict(err)
+			l1BlocksAdvanced := arbmath.SaturatingUSub(l1BlockNumber, oldL1BlockNumber)
+			state.Restrict(state.Blockhashes().UpdateL1BlockStaleness(l1BlocksAdvanced, l1BlockSeconds, currentTime))
+		}
+
 		// Try to reap 2 retryables
 		_ = state.RetryableState().TryToReapOneRetryable(currentTime, evm, util.TracingDuringEVM)
 		_ = state.RetryableState().TryToReapOneRetryable(currentTime, evm, util.TracingDuringEVM)
@@ -90,22 +99,64 @@ func ApplyInternalTxUpdate(tx *types.ArbitrumInternalTx, state *arbosState.Arbos
 
 		return state.UpgradeArbosVersionIfNecessary(currentTime, evm.StateDB, evm.ChainConfig())
 	case InternalTxBatchPostingReportMethodID:
-		inputs, err := util.UnpackInternalTxDataBatchPostingReport(tx.Data)
-		if err != nil {
-			return err
-		}
-		batchTimestamp := util.SafeMapGet[*big.Int](inputs, "batchTimestamp")
-		batchPosterAddress := util.SafeMapGet[common.Address](inputs, "batchPosterAddress")
-		batchDataGas := util.SafeMapGet[uint64](inputs, "batchDataGas")
-		l1BaseFeeWei := util.SafeMapGet[*big.Int](inputs, "l1BaseFeeWei")
+		return applyBatchPostingReport(tx, state, evm, nil)
+	default:
+		return fmt.Errorf("unknown internal tx method selector: %v", hex.EncodeToString(tx.Data[:4]))
+	}
+}
 
-		l1p := state.L1PricingState()
-		perBatchGas, err := l1p.PerBatchGasCost()
-		if err != nil {
-			log.Warn("L1Pricing PerBatchGas failed", "err", err)
+// TraceBatchPostingReport applies a batch posting report internal tx as ApplyInternalTxUpdate does,
+// returning a trace of how it updated the L1 pricing model.
+func TraceBatchPostingReport(tx *types.ArbitrumInternalTx, state *arbosState.ArbosState, evm *vm.EVM) (*l1pricing.UpdateTrace, error) {
+	if len(tx.Data) < 4 || *(*[4]byte)(tx.Data[:4]) != InternalTxBatchPostingReportMethodID {
+		return nil, errors.New("internal tx isn't a batch posting report")
+	}
+	trace := &l1pricing.UpdateTrace{}
+	if err := applyBatchPostingReport(tx, state, evm, trace); err != nil {
+		return nil, err
+	}
+	return trace, nil
+}
+
+// applyBatchPostingReport records the L1 pricing update into trace, if it isn't nil.
+func applyBatchPostingReport(tx *types.ArbitrumInternalTx, state *arbosState.ArbosState, evm *vm.EVM, trace *l1pricing.UpdateTrace) error {
+	inputs, err := util.UnpackInternalTxDataBatchPostingReport(tx.Data)
+	if err != nil {
+		return err
+	}
+	batchTimestamp := util.SafeMapGet[*big.Int](inputs, "batchTimestamp")
+	batchPosterAddress := util.SafeMapGet[common.Address](inputs, "batchPosterAddress")
+	batchDataGas := util.SafeMapGet[uint64](inputs, "batchDataGas")
+	l1BaseFeeWei := util.SafeMapGet[*big.Int](inputs, "l1BaseFeeWei")
+
+	l1p := state.L1PricingState()
+	perBatchGas, err := l1p.PerBatchGasCost()
+	if err != nil {
+		log.Warn("L1Pricing PerBatchGas failed", "err", err)
+	}
+	gasSpent := arbmath.SaturatingAdd(perBatchGas, arbmath.SaturatingCast[int64](batchDataGas))
+	weiSpent := arbmath.BigMulByUint(l1BaseFeeWei, arbmath.SaturatingUCast[uint64](gasSpent))
+	if state.ArbOSVersion() >= util.ArbosVersion_40 {
+		if err := l1p.SetL1GasUsedLastBatch(arbmath.SaturatingUCast[uint64](gasSpent)); err != nil {
+			log.Warn("L1Pricing SetL1GasUsedLastBatch failed", "err", err)
 		}
-		gasSpent := arbmath.SaturatingAdd(perBatchGas, arbmath.SaturatingCast[int64](batchDataGas))
-		weiSpent := arbmath.BigMulByUint(l1BaseFeeWei, arbmath.SaturatingUCast[uint64](gasSpent))
+	}
+	if trace != nil {
+		trace.BatchDataGas = batchDataGas
+		trace.PerBatchGas = perBatchGas
+		err = l1p.TraceUpdateForBatchPosterSpending(
+			evm.StateDB,
+			evm,
+			state.ArbOSVersion(),
+			batchTimestamp.Uint64(),
+			evm.Context.Time,
+			batchPosterAddress,
+			weiSpent,
+			l1BaseFeeWei,
+			util.TracingDuringEVM,
+			trace,
+		)
+	} else {
 		err = l1p.UpdateForBatchPosterSpending(
 			evm.StateDB,
 			evm,
@@ -117,11 +168,9 @@ func ApplyInternalTxUpdate(tx *types.ArbitrumInternalTx, state *arbosState.Arbos
 			l1BaseFeeWei,
 			util.TracingDuringEVM,
 		)
-		if err != nil {
-			log.Warn("L1Pricing UpdateForSequencerSpending failed", "err", err)
-		}
-		return nil
-	default:
-		return fmt.Errorf("unknown internal tx method selector: %v", hex.EncodeToString(tx.Data[:4]))
 	}
+	if err != nil {
+		log.Warn("L1Pricing UpdateForSequencerSpending failed", "err", err)
+	}
+	return nil
 }
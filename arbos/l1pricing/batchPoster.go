@@ -23,6 +23,7 @@ var (
 
 	ErrAlreadyExists = errors.New("tried to add a batch poster that already exists")
 	ErrNotExist      = errors.New("tried to open a batch poster that does not exist")
+	ErrFundsDue      = errors.New("tried to remove a batch poster that is owed funds")
 )
 
 // BatchPostersTable is the layout of storage in the table
@@ -36,6 +37,11 @@ type BatchPosterState struct {
 	fundsDue     storage.StorageBackedBigInt
 	payTo        storage.StorageBackedAddress
 	postersTable *BatchPostersTable
+
+	// statistics accumulated since ArbOS 40, which read as zero in older states
+	unitsPosted     storage.StorageBackedUint64
+	feesCollected   storage.StorageBackedBigInt
+	statsUpdateTime storage.StorageBackedUint64
 }
 
 func InitializeBatchPostersTable(storage *storage.Storage) error {
@@ -71,9 +77,12 @@ func (bpt *BatchPostersTable) OpenPoster(poster common.Address, createIfNotExist
 func (bpt *BatchPostersTable) internalOpen(poster common.Address) *BatchPosterState {
 	bpStorage := bpt.posterInfo.OpenSubStorage(poster.Bytes())
 	return &BatchPosterState{
-		fundsDue:     bpStorage.OpenStorageBackedBigInt(0),
-		payTo:        bpStorage.OpenStorageBackedAddress(1),
-		postersTable: bpt,
+		fundsDue:        bpStorage.OpenStorageBackedBigInt(0),
+		payTo:           bpStorage.OpenStorageBackedAddress(1),
+		postersTable:    bpt,
+		unitsPosted:     bpStorage.OpenStorageBackedUint64(2),
+		feesCollected:   bpStorage.OpenStorageBackedBigInt(3),
+		statsUpdateTime: bpStorage.OpenStorageBackedUint64(4),
 	}
 }
 
@@ -104,6 +113,32 @@ func (bpt *BatchPostersTable) AddPoster(posterAddress common.Address, payTo comm
 	return bpState, nil
 }
 
+// RemovePoster removes a batch poster, which mustn't be owed funds so none are stranded
+func (bpt *BatchPostersTable) RemovePoster(poster common.Address, arbosVersion uint64) error {
+	isBatchPoster, err := bpt.posterAddrs.IsMember(poster)
+	if err != nil {
+		return err
+	}
+	if !isBatchPoster {
+		return ErrNotExist
+	}
+	bpState := bpt.internalOpen(poster)
+	fundsDue, err := bpState.fundsDue.Get()
+	if err != nil {
+		return err
+	}
+	if fundsDue.Sign() != 0 {
+		return ErrFundsDue
+	}
+	if err := bpState.payTo.Set(common.Address{}); err != nil {
+		return err
+	}
+	if err := bpState.clearStats(); err != nil {
+		return err
+	}
+	return bpt.posterAddrs.Remove(poster, arbosVersion)
+}
+
 func (bpt *BatchPostersTable) AllPosters(maxNumToGet uint64) ([]common.Address, error) {
 	return bpt.posterAddrs.AllMembers(maxNumToGet)
 }
@@ -141,6 +176,53 @@ func (bps *BatchPosterState) SetPayTo(addr common.Address) error {
 	return bps.payTo.Set(addr)
 }
 
+// Stats returns the units the batch poster has posted, the wei it has been paid for them,
+// and the time of the last batch posting report that updated them
+func (bps *BatchPosterState) Stats() (uint64, *big.Int, uint64, error) {
+	units, err := bps.unitsPosted.Get()
+	if err != nil {
+		return 0, nil, 0, err
+	}
+	fees, err := bps.feesCollected.Get()
+	if err != nil {
+		return 0, nil, 0, err
+	}
+	updateTime, err := bps.statsUpdateTime.Get()
+	if err != nil {
+		return 0, nil, 0, err
+	}
+	return units, fees, updateTime, nil
+}
+
+// RecordPosting adds a batch posting report's units and payment to the batch poster's statistics
+func (bps *BatchPosterState) RecordPosting(units uint64, paid *big.Int, updateTime uint64) error {
+	prevUnits, err := bps.unitsPosted.Get()
+	if err != nil {
+		return err
+	}
+	if err := bps.unitsPosted.Set(arbmath.SaturatingUAdd(prevUnits, units)); err != nil {
+		return err
+	}
+	prevFees, err := bps.feesCollected.Get()
+	if err != nil {
+		return err
+	}
+	if err := bps.feesCollected.SetSaturatingWithWarning(arbmath.BigAdd(prevFees, paid), "batch poster fees collected"); err != nil {
+		return err
+	}
+	return bps.statsUpdateTime.Set(updateTime)
+}
+
+func (bps *BatchPosterState) clearStats() error {
+	if err := bps.unitsPosted.Clear(); err != nil {
+		return err
+	}
+	if err := bps.feesCollected.SetChecked(common.Big0); err != nil {
+		return err
+	}
+	return bps.statsUpdateTime.Clear()
+}
+
 type FundsDueItem struct {
 	dueTo   common.Address
 	balance *big.Int
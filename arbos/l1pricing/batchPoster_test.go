@@ -4,11 +4,13 @@
 package l1pricing
 
 import (
+	"errors"
 	"math/big"
 	"testing"
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/ethereum/go-ethereum/params"
 
 	"github.com/offchainlabs/nitro/arbos/burn"
 	"github.com/offchainlabs/nitro/arbos/storage"
@@ -113,4 +115,33 @@ func TestBatchPosterTable(t *testing.T) {
 	if totalDue.Uint64() != 13+42 {
 		t.Fatal()
 	}
+
+	// test accumulating the posting stats
+	Require(t, bp2.RecordPosting(100, big.NewInt(7), 1000))
+	Require(t, bp2.RecordPosting(50, big.NewInt(3), 1010))
+	units, fees, updateTime, err := bp2.Stats()
+	Require(t, err)
+	if units != 150 || fees.Uint64() != 10 || updateTime != 1010 {
+		t.Fatal("unexpected stats", units, fees, updateTime)
+	}
+
+	// test removal, which isn't allowed while funds are due
+	if err := bpTable.RemovePoster(addr1, params.ArbosVersion_32); !errors.Is(err, ErrFundsDue) {
+		t.Fatal("expected removing a poster that's owed funds to fail, got", err)
+	}
+	Require(t, bp1.SetFundsDue(common.Big0))
+	Require(t, bpTable.RemovePoster(addr1, params.ArbosVersion_32))
+	exists, err = bpTable.ContainsPoster(addr1)
+	Require(t, err)
+	if exists {
+		t.Fatal()
+	}
+	allPosters, err = bpTable.AllPosters(math.MaxUint64)
+	Require(t, err)
+	if len(allPosters) != 1 || allPosters[0] != addr2 {
+		t.Fatal("unexpected posters after removal", allPosters)
+	}
+	if err := bpTable.RemovePoster(addr1, params.ArbosVersion_32); !errors.Is(err, ErrNotExist) {
+		t.Fatal("expected removing a poster twice to fail, got", err)
+	}
 }
@@ -43,6 +43,16 @@ type L1PricingState struct {
 	perBatchGasCost      storage.StorageBackedInt64   // introduced in ArbOS version 3
 	amortizedCostCapBips storage.StorageBackedUint64  // in basis points; introduced in ArbOS version 3
 	l1FeesAvailable      storage.StorageBackedBigUint
+	// covers whatever batch posting costs the fees collected can't; introduced in ArbOS version 40
+	batchEthPaymentAddress storage.StorageBackedAddress
+	l1GasUsedLastBatch     storage.StorageBackedUint64 // introduced in ArbOS version 40
+	// scales the calldata units charged per tx, 0 meaning no scaling; in basis points; introduced in ArbOS version 40
+	dataGasFactorBips storage.StorageBackedUint64
+	// bounds each update's change to the price per unit, as a percentage of the price, 0 meaning no bound;
+	// introduced in ArbOS version 40
+	throttlePercent storage.StorageBackedUint64
+	// the expected time between L1 blocks, 0 meaning DefaultL1BlockTimeSeconds; introduced in ArbOS version 40
+	l1BlockTimeSeconds storage.StorageBackedUint64
 }
 
 var (
@@ -67,6 +77,11 @@ const (
 	perBatchGasCostOffset
 	amortizedCostCapBipsOffset
 	l1FeesAvailableOffset
+	batchEthPaymentAddressOffset
+	l1GasUsedLastBatchOffset
+	dataGasFactorBipsOffset
+	throttlePercentOffset
+	l1BlockTimeSecondsOffset
 )
 
 const (
@@ -74,6 +89,7 @@ const (
 	InitialPerUnitReward      = 10
 	InitialPerBatchGasCostV6  = 100_000
 	InitialPerBatchGasCostV12 = 210_000 // overridden as part of the upgrade
+	DefaultL1BlockTimeSeconds = 12      // Ethereum's slot time
 )
 
 // one minute at 100000 bytes / sec
@@ -129,6 +145,11 @@ func OpenL1PricingState(sto *storage.Storage) *L1PricingState {
 		sto.OpenStorageBackedInt64(perBatchGasCostOffset),
 		sto.OpenStorageBackedUint64(amortizedCostCapBipsOffset),
 		sto.OpenStorageBackedBigUint(l1FeesAvailableOffset),
+		sto.OpenStorageBackedAddress(batchEthPaymentAddressOffset),
+		sto.OpenStorageBackedUint64(l1GasUsedLastBatchOffset),
+		sto.OpenStorageBackedUint64(dataGasFactorBipsOffset),
+		sto.OpenStorageBackedUint64(throttlePercentOffset),
+		sto.OpenStorageBackedUint64(l1BlockTimeSecondsOffset),
 	}
 }
 
@@ -144,6 +165,23 @@ func (ps *L1PricingState) SetPayRewardsTo(addr common.Address) error {
 	return ps.payRewardsTo.Set(addr)
 }
 
+func (ps *L1PricingState) BatchEthPaymentAddress() (common.Address, error) {
+	return ps.batchEthPaymentAddress.Get()
+}
+
+func (ps *L1PricingState) SetBatchEthPaymentAddress(addr common.Address) error {
+	return ps.batchEthPaymentAddress.Set(addr)
+}
+
+// L1GasUsedLastBatch is the L1 gas, including the per batch gas cost, of the last batch posting report processed
+func (ps *L1PricingState) L1GasUsedLastBatch() (uint64, error) {
+	return ps.l1GasUsedLastBatch.Get()
+}
+
+func (ps *L1PricingState) SetL1GasUsedLastBatch(gas uint64) error {
+	return ps.l1GasUsedLastBatch.Set(gas)
+}
+
 func (ps *L1PricingState) EquilibrationUnits() (*big.Int, error) {
 	return ps.equilibrationUnits.Get()
 }
@@ -253,6 +291,48 @@ func (ps *L1PricingState) SetAmortizedCostCapBips(cap uint64) error {
 	return ps.amortizedCostCapBips.Set(cap)
 }
 
+// DataGasFactorBips is the fraction of a tx's calldata units it's charged for, with 0 meaning all of them
+func (ps *L1PricingState) DataGasFactorBips() (uint64, error) {
+	return ps.dataGasFactorBips.Get()
+}
+
+func (ps *L1PricingState) SetDataGasFactorBips(factor uint64) error {
+	return ps.dataGasFactorBips.Set(factor)
+}
+
+// applyDataGasFactor scales calldata units by the data gas factor, if one is set
+func (ps *L1PricingState) applyDataGasFactor(units uint64) uint64 {
+	factor, _ := ps.DataGasFactorBips()
+	if factor == 0 {
+		return units
+	}
+	// #nosec G115 -- ArbOwner only allows factors up to one in bips
+	return arbmath.UintMulByBips(units, arbmath.Bips(factor))
+}
+
+// ThrottlePercent bounds how much an update may change the price per unit, as a percentage of the price,
+// with 0 meaning the change isn't bounded
+func (ps *L1PricingState) ThrottlePercent() (uint64, error) {
+	return ps.throttlePercent.Get()
+}
+
+func (ps *L1PricingState) SetThrottlePercent(percent uint64) error {
+	return ps.throttlePercent.Set(percent)
+}
+
+// L1BlockTimeSeconds is the expected time between L1 blocks, used to estimate when L1 blocks were produced
+func (ps *L1PricingState) L1BlockTimeSeconds() (uint64, error) {
+	seconds, err := ps.l1BlockTimeSeconds.Get()
+	if seconds == 0 {
+		seconds = DefaultL1BlockTimeSeconds
+	}
+	return seconds, err
+}
+
+func (ps *L1PricingState) SetL1BlockTimeSeconds(seconds uint64) error {
+	return ps.l1BlockTimeSeconds.Set(seconds)
+}
+
 func (ps *L1PricingState) L1FeesAvailable() (*big.Int, error) {
 	return ps.l1FeesAvailable.Get()
 }
@@ -311,7 +391,21 @@ func (ps *L1PricingState) UpdateForBatchPosterSpending(
 	if arbosVersion < params.ArbosVersion_10 {
 		return ps._preversion10_UpdateForBatchPosterSpending(statedb, evm, arbosVersion, updateTime, currentTime, batchPoster, weiSpent, l1Basefee, scenario)
 	}
+	return ps.updateForBatchPosterSpending(statedb, evm, arbosVersion, updateTime, currentTime, batchPoster, weiSpent, l1Basefee, scenario, nil)
+}
 
+// updateForBatchPosterSpending records into trace, if it isn't nil, which adjustment rules fired.
+func (ps *L1PricingState) updateForBatchPosterSpending(
+	statedb vm.StateDB,
+	evm *vm.EVM,
+	arbosVersion uint64,
+	updateTime, currentTime uint64,
+	batchPoster common.Address,
+	weiSpent *big.Int,
+	l1Basefee *big.Int,
+	scenario util.TracingScenario,
+	trace *UpdateTrace,
+) error {
 	batchPosterTable := ps.BatchPosterTable()
 	posterState, err := batchPosterTable.OpenPoster(batchPoster, true)
 	if err != nil {
@@ -356,6 +450,9 @@ func (ps *L1PricingState) UpdateForBatchPosterSpending(
 	if err := ps.SetUnitsSinceUpdate(unitsSinceUpdate); err != nil {
 		return err
 	}
+	if trace != nil {
+		trace.UnitsAllocated = unitsAllocated
+	}
 
 	// impose cap on amortized cost, if there is one
 	if arbosVersion >= params.ArbosVersion_3 {
@@ -372,10 +469,30 @@ func (ps *L1PricingState) UpdateForBatchPosterSpending(
 				// apply the cap on assignment of amortized cost;
 				// the difference will be a loss for the batch poster
 				weiSpent = weiSpentCap
+				if trace != nil {
+					trace.WeiAllocated = weiSpent
+				}
+				trace.fire(RuleAmortizedCostCap)
 			}
 		}
 	}
 
+	// users are only charged the data gas factor's share of their units, so only that share of the cost
+	// is recovered from them, and the batch poster takes the rest as a loss, as with the cost cap
+	if arbosVersion >= util.ArbosVersion_40 {
+		factor, err := ps.DataGasFactorBips()
+		if err != nil {
+			return err
+		}
+		if factor != 0 {
+			weiSpent = am.BigMulByBips(weiSpent, am.SaturatingCastToBips(factor))
+			if trace != nil {
+				trace.WeiAllocated = weiSpent
+			}
+			trace.fire(RuleDataGasFactor)
+		}
+	}
+
 	dueToPoster, err := posterState.FundsDue()
 	if err != nil {
 		return err
@@ -397,6 +514,7 @@ func (ps *L1PricingState) UpdateForBatchPosterSpending(
 	paymentForRewards := am.BigMulByUint(am.UintToBig(perUnitReward), unitsAllocated)
 	if am.BigLessThan(l1FeesAvailable, paymentForRewards) {
 		paymentForRewards = l1FeesAvailable
+		trace.fire(RuleRewardsLimitedByFunds)
 	}
 	fundsDueForRewards = am.BigSub(fundsDueForRewards, paymentForRewards)
 	if err := ps.SetFundsDueForRewards(fundsDueForRewards); err != nil {
@@ -419,8 +537,10 @@ func (ps *L1PricingState) UpdateForBatchPosterSpending(
 		return err
 	}
 	balanceToTransfer := balanceDueToPoster
+	paidToPoster := new(big.Int)
 	if am.BigLessThan(l1FeesAvailable, balanceToTransfer) {
 		balanceToTransfer = l1FeesAvailable
+		trace.fire(RulePosterPaidPartially)
 	}
 	if balanceToTransfer.Sign() > 0 {
 		addrToPay, err := posterState.PayTo()
@@ -438,6 +558,38 @@ func (ps *L1PricingState) UpdateForBatchPosterSpending(
 		if err != nil {
 			return err
 		}
+		paidToPoster.Add(paidToPoster, balanceToTransfer)
+	}
+
+	// pay whatever's still owed from the batch payment address, if there is one
+	if arbosVersion >= util.ArbosVersion_40 && balanceDueToPoster.Sign() > 0 {
+		paymentAddress, err := ps.BatchEthPaymentAddress()
+		if err != nil {
+			return err
+		}
+		if paymentAddress != (common.Address{}) {
+			payment := am.BigMin(balanceDueToPoster, statedb.GetBalance(paymentAddress).ToBig())
+			if payment.Sign() > 0 {
+				addrToPay, err := posterState.PayTo()
+				if err != nil {
+					return err
+				}
+				if err := util.TransferBalance(&paymentAddress, &addrToPay, payment, evm, scenario, "batchPosterPayment"); err != nil {
+					return err
+				}
+				if err := posterState.SetFundsDue(am.BigSub(balanceDueToPoster, payment)); err != nil {
+					return err
+				}
+				paidToPoster.Add(paidToPoster, payment)
+				trace.fire(RuleBatchPaymentAddress)
+			}
+		}
+	}
+
+	if arbosVersion >= util.ArbosVersion_40 {
+		if err := posterState.RecordPosting(unitsAllocated, paidToPoster, updateTime); err != nil {
+			return err
+		}
 	}
 
 	// update time
@@ -486,12 +638,31 @@ func (ps *L1PricingState) UpdateForBatchPosterSpending(
 			return err
 		}
 		newPrice := am.BigAdd(price, priceChange)
+		trace.fire(RulePriceAdjusted)
 		if newPrice.Sign() < 0 {
 			newPrice = common.Big0
+			trace.fire(RulePriceClampedToZero)
+		}
+		throttlePercent, err := ps.ThrottlePercent()
+		if err != nil {
+			return err
+		}
+		// a price of zero can't be bounded relative to itself, so it's left free to recover
+		if throttlePercent != 0 && price.Sign() > 0 {
+			maxChange := am.BigDivByUint(am.BigMulByUint(price, throttlePercent), 100)
+			if upper := am.BigAdd(price, maxChange); newPrice.Cmp(upper) > 0 {
+				newPrice = upper
+				trace.fire(RulePriceThrottled)
+			} else if lower := am.BigSub(price, maxChange); newPrice.Cmp(lower) < 0 {
+				newPrice = lower
+				trace.fire(RulePriceThrottled)
+			}
 		}
 		if err := ps.SetPricePerUnit(newPrice); err != nil {
 			return err
 		}
+	} else {
+		trace.fire(RuleNoUnitsAllocated)
 	}
 	return nil
 }
@@ -527,6 +698,7 @@ func (ps *L1PricingState) GetPosterInfo(tx *types.Transaction, poster common.Add
 		units = ps.getPosterUnitsWithoutCache(tx, poster, brotliCompressionLevel)
 		tx.SetCachedCalldataUnits(brotliCompressionLevel, units)
 	}
+	units = ps.applyDataGasFactor(units)
 
 	// Approximate the l1 fee charged for posting this tx's calldata
 	pricePerUnit, _ := ps.PricePerUnit()
@@ -591,6 +763,7 @@ func (ps *L1PricingState) PosterDataCost(message *core.Message, poster common.Ad
 	tx = makeFakeTxForMessage(message)
 	units := ps.getPosterUnitsWithoutCache(tx, poster, brotliCompressionLevel)
 	units = arbmath.UintMulByBips(units+estimationPaddingUnits, arbmath.OneInBips+estimationPaddingBasisPoints)
+	units = ps.applyDataGasFactor(units)
 	pricePerUnit, _ := ps.PricePerUnit()
 	return am.BigMulByUint(pricePerUnit, units), units
 }
@@ -0,0 +1,93 @@
+// Copyright 2024, Offchain Labs, Inc.
+// For license information, see https://github.com/nitro/blob/master/LICENSE
+
+package l1pricing
+
+import (
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/params"
+
+	"github.com/offchainlabs/nitro/arbos/util"
+)
+
+// The adjustment rules an L1 pricing update can fire, as recorded in an UpdateTrace.
+const (
+	RuleAmortizedCostCap      = "amortizedCostCap"      // the poster's cost was capped at the amortized cost cap
+	RuleDataGasFactor         = "dataGasFactor"         // the poster's cost was scaled by the data gas factor users are charged at
+	RuleRewardsLimitedByFunds = "rewardsLimitedByFunds" // there weren't enough L1 fees to pay all rewards due
+	RulePosterPaidPartially   = "posterPaidPartially"   // there weren't enough L1 fees to refund the poster in full
+	RuleBatchPaymentAddress   = "batchPaymentAddress"   // the batch payment address paid (some of) what the poster was still owed
+	RuleNoUnitsAllocated      = "noUnitsAllocated"      // no units were allocated to the update, so the price was left alone
+	RulePriceAdjusted         = "priceAdjusted"         // the price was moved towards equilibrating the surplus
+	RulePriceClampedToZero    = "priceClampedToZero"    // the adjusted price would've been negative, so it was set to zero
+	RulePriceThrottled        = "priceThrottled"        // the adjustment was bounded by the throttle percent
+	RuleUpdateFailed          = "updateFailed"          // the update errored, leaving the pricing model partly updated
+)
+
+// UpdateTrace records the inputs to an L1 pricing update made for a batch posting report,
+// and how it moved the price per unit, so fee changes can be explained after the fact.
+type UpdateTrace struct {
+	BatchPoster    common.Address
+	UpdateTime     uint64
+	BatchDataGas   uint64
+	PerBatchGas    int64
+	L1BaseFee      *big.Int
+	WeiSpent       *big.Int // the cost the batch poster reported
+	WeiAllocated   *big.Int // the cost assigned to the poster, after any cap or data gas factor
+	UnitsAllocated uint64
+	PriceBefore    *big.Int
+	PriceAfter     *big.Int
+	SurplusBefore  *big.Int
+	SurplusAfter   *big.Int
+	Rules          []string
+}
+
+func (t *UpdateTrace) fire(rule string) {
+	if t != nil {
+		t.Rules = append(t.Rules, rule)
+	}
+}
+
+// TraceUpdateForBatchPosterSpending is UpdateForBatchPosterSpending, additionally recording into trace
+// the update's inputs, which rules fired, and the price and surplus before and after.
+func (ps *L1PricingState) TraceUpdateForBatchPosterSpending(
+	statedb vm.StateDB,
+	evm *vm.EVM,
+	arbosVersion uint64,
+	updateTime, currentTime uint64,
+	batchPoster common.Address,
+	weiSpent *big.Int,
+	l1Basefee *big.Int,
+	scenario util.TracingScenario,
+	trace *UpdateTrace,
+) error {
+	trace.BatchPoster = batchPoster
+	trace.UpdateTime = updateTime
+	trace.L1BaseFee = l1Basefee
+	trace.WeiSpent = weiSpent
+	trace.WeiAllocated = weiSpent
+	var err error
+	if trace.PriceBefore, err = ps.PricePerUnit(); err != nil {
+		return err
+	}
+	if trace.SurplusBefore, err = ps.LastSurplus(); err != nil {
+		return err
+	}
+	if arbosVersion < params.ArbosVersion_10 {
+		err = ps._preversion10_UpdateForBatchPosterSpending(statedb, evm, arbosVersion, updateTime, currentTime, batchPoster, weiSpent, l1Basefee, scenario)
+	} else {
+		err = ps.updateForBatchPosterSpending(statedb, evm, arbosVersion, updateTime, currentTime, batchPoster, weiSpent, l1Basefee, scenario, trace)
+	}
+	if err != nil {
+		trace.fire(RuleUpdateFailed)
+		return err
+	}
+	if trace.PriceAfter, err = ps.PricePerUnit(); err != nil {
+		return err
+	}
+	trace.SurplusAfter, err = ps.LastSurplus()
+	return err
+}
@@ -5,6 +5,7 @@ package arbos
 
 import (
 	"math/big"
+	"slices"
 	"testing"
 
 	"github.com/holiman/uint256"
@@ -214,6 +215,195 @@ func _testL1PricingFundsDue(t *testing.T, testParams *l1PricingTest, expectedRes
 	}
 }
 
+func TestL1BatchEthPaymentAddress(t *testing.T) {
+	evm := newMockEVMForTesting()
+	burner := burn.NewSystemBurner(nil, false)
+	arbosSt, err := arbosState.OpenArbosState(evm.StateDB, burner)
+	Require(t, err)
+
+	l1p := arbosSt.L1PricingState()
+	Require(t, l1p.SetPerUnitReward(0))
+	poster := common.Address{3, 4, 5}
+	payTo := common.Address{6, 7}
+	_, err = l1p.BatchPosterTable().AddPoster(poster, payTo)
+	Require(t, err)
+	paymentAddress := common.Address{8, 9}
+	Require(t, l1p.SetBatchEthPaymentAddress(paymentAddress))
+	stored, err := l1p.BatchEthPaymentAddress()
+	Require(t, err)
+	if stored != paymentAddress {
+		Fail(t, "expected batch payment address", paymentAddress, "got", stored)
+	}
+
+	collected := big.NewInt(1000)
+	evm.StateDB.AddBalance(l1pricing.L1PricerFundsPoolAddress, uint256.MustFromBig(collected), tracing.BalanceChangeUnspecified)
+	Require(t, l1p.SetL1FeesAvailable(collected))
+	funding := big.NewInt(1500)
+	evm.StateDB.AddBalance(paymentAddress, uint256.MustFromBig(funding), tracing.BalanceChangeUnspecified)
+
+	posterState, err := l1p.BatchPosterTable().OpenPoster(poster, false)
+	Require(t, err)
+	update := func(updateTime uint64, weiSpent int64) {
+		t.Helper()
+		Require(t, l1p.UpdateForBatchPosterSpending(
+			evm.StateDB, evm, util.ArbosVersion_40, updateTime, updateTime, poster, big.NewInt(weiSpent), common.Big1, util.TracingDuringEVM,
+		))
+	}
+	checkBalances := func(expectedPayTo, expectedPayment, expectedDue int64) {
+		t.Helper()
+		if balance := evm.StateDB.GetBalance(payTo).ToBig(); balance.Int64() != expectedPayTo {
+			Fail(t, "expected batch poster to have been paid", expectedPayTo, "got", balance)
+		}
+		if balance := evm.StateDB.GetBalance(paymentAddress).ToBig(); balance.Int64() != expectedPayment {
+			Fail(t, "expected batch payment address to have", expectedPayment, "left, got", balance)
+		}
+		due, err := posterState.FundsDue()
+		Require(t, err)
+		if due.Int64() != expectedDue {
+			Fail(t, "expected", expectedDue, "to still be due to the batch poster, got", due)
+		}
+	}
+
+	// the fees collected are spent first, and the payment address covers the rest
+	update(1, 1200)
+	checkBalances(1200, 1300, 0)
+
+	// once it runs out the batch poster is left waiting on future fees
+	update(2, 1500)
+	checkBalances(2500, 0, 200)
+
+	// the payment address isn't used once it's unset
+	evm.StateDB.AddBalance(paymentAddress, uint256.MustFromBig(funding), tracing.BalanceChangeUnspecified)
+	Require(t, l1p.SetBatchEthPaymentAddress(common.Address{}))
+	update(3, 100)
+	checkBalances(2500, 1500, 300)
+}
+
+func TestTraceL1PricingUpdate(t *testing.T) {
+	poster := common.Address{3, 4, 5}
+	setup := func() (*vm.EVM, *l1pricing.L1PricingState) {
+		evm := newMockEVMForTesting()
+		arbosSt, err := arbosState.OpenArbosState(evm.StateDB, burn.NewSystemBurner(nil, false))
+		Require(t, err)
+		l1p := arbosSt.L1PricingState()
+		Require(t, l1p.SetPerUnitReward(0))
+		Require(t, l1p.SetPricePerUnit(big.NewInt(1000)))
+		Require(t, l1p.SetAmortizedCostCapBips(20000))
+		Require(t, l1p.SetEquilibrationUnits(big.NewInt(1000)))
+		Require(t, l1p.SetUnitsSinceUpdate(1000))
+		_, err = l1p.BatchPosterTable().AddPoster(poster, poster)
+		Require(t, err)
+		collected := big.NewInt(1e6)
+		evm.StateDB.AddBalance(l1pricing.L1PricerFundsPoolAddress, uint256.MustFromBig(collected), tracing.BalanceChangeUnspecified)
+		Require(t, l1p.SetL1FeesAvailable(collected))
+		return evm, l1p
+	}
+	tracedEvm, traced := setup()
+	evm, untraced := setup()
+
+	update := func(updateTime uint64, weiSpent int64) *l1pricing.UpdateTrace {
+		t.Helper()
+		trace := &l1pricing.UpdateTrace{}
+		Require(t, traced.TraceUpdateForBatchPosterSpending(
+			tracedEvm.StateDB, tracedEvm, util.ArbosVersion_40, updateTime, updateTime, poster, big.NewInt(weiSpent), big.NewInt(1000), util.TracingDuringEVM, trace,
+		))
+		Require(t, untraced.UpdateForBatchPosterSpending(
+			evm.StateDB, evm, util.ArbosVersion_40, updateTime, updateTime, poster, big.NewInt(weiSpent), big.NewInt(1000), util.TracingDuringEVM,
+		))
+		// tracing mustn't change what the update does
+		if tracedEvm.StateDB.(*state.StateDB).IntermediateRoot(true) != evm.StateDB.(*state.StateDB).IntermediateRoot(true) {
+			Fail(t, "traced update changed state differently than untraced update")
+		}
+		price, err := traced.PricePerUnit()
+		Require(t, err)
+		if !arbmath.BigEquals(trace.PriceAfter, price) {
+			Fail(t, "trace has price after", trace.PriceAfter, "but price is", price)
+		}
+		surplus, err := traced.LastSurplus()
+		Require(t, err)
+		if !arbmath.BigEquals(trace.SurplusAfter, surplus) {
+			Fail(t, "trace has surplus after", trace.SurplusAfter, "but surplus is", surplus)
+		}
+		return trace
+	}
+	checkRules := func(trace *l1pricing.UpdateTrace, expected ...string) {
+		t.Helper()
+		if len(trace.Rules) != len(expected) {
+			Fail(t, "expected rules", expected, "got", trace.Rules)
+		}
+		for i := range expected {
+			if trace.Rules[i] != expected[i] {
+				Fail(t, "expected rules", expected, "got", trace.Rules)
+			}
+		}
+	}
+
+	// the poster spent more than the amortized cost cap of twice the basefee per unit,
+	// and more than was collected, so the price rises to make up the deficit
+	trace := update(10, 3e6)
+	checkRules(trace, l1pricing.RuleAmortizedCostCap, l1pricing.RulePosterPaidPartially, l1pricing.RulePriceAdjusted)
+	if trace.UnitsAllocated != 1000 || trace.WeiSpent.Int64() != 3e6 || trace.WeiAllocated.Int64() != 2e6 {
+		Fail(t, "unexpected trace inputs", trace.UnitsAllocated, trace.WeiSpent, trace.WeiAllocated)
+	}
+	if trace.PriceBefore.Int64() != 1000 || trace.SurplusBefore.Sign() != 0 {
+		Fail(t, "unexpected trace starting point", trace.PriceBefore, trace.SurplusBefore)
+	}
+	if trace.SurplusAfter.Int64() != -1e6 || !arbmath.BigGreaterThan(trace.PriceAfter, trace.PriceBefore) {
+		Fail(t, "expected a deficit to raise the price, got surplus", trace.SurplusAfter, "and price", trace.PriceBefore, "->", trace.PriceAfter)
+	}
+
+	// without units since the last update nothing can be charged for, and the price is left alone
+	trace = update(11, 1000)
+	checkRules(trace, l1pricing.RuleAmortizedCostCap, l1pricing.RulePosterPaidPartially, l1pricing.RuleNoUnitsAllocated)
+	if trace.UnitsAllocated != 0 || !arbmath.BigEquals(trace.PriceBefore, trace.PriceAfter) {
+		Fail(t, "expected price to be left alone, got", trace.PriceBefore, "->", trace.PriceAfter)
+	}
+}
+
+func TestL1PricingDataGasFactorKeepsPrice(t *testing.T) {
+	evm := newMockEVMForTesting()
+	arbosSt, err := arbosState.OpenArbosState(evm.StateDB, burn.NewSystemBurner(nil, false))
+	Require(t, err)
+	l1p := arbosSt.L1PricingState()
+	Require(t, l1p.SetPerUnitReward(0))
+	Require(t, l1p.SetAmortizedCostCapBips(0))
+	Require(t, l1p.SetPricePerUnit(big.NewInt(1000)))
+	Require(t, l1p.SetEquilibrationUnits(big.NewInt(1000)))
+	Require(t, l1p.SetDataGasFactorBips(5000))
+	poster := common.Address{3, 4, 5}
+	_, err = l1p.BatchPosterTable().AddPoster(poster, poster)
+	Require(t, err)
+
+	// users were charged for half of 1000 units at the price, which is what the units cost the poster
+	Require(t, l1p.SetUnitsSinceUpdate(500))
+	collected := big.NewInt(5e5)
+	evm.StateDB.AddBalance(l1pricing.L1PricerFundsPoolAddress, uint256.MustFromBig(collected), tracing.BalanceChangeUnspecified)
+	Require(t, l1p.SetL1FeesAvailable(collected))
+
+	trace := &l1pricing.UpdateTrace{}
+	Require(t, l1p.TraceUpdateForBatchPosterSpending(
+		evm.StateDB, evm, util.ArbosVersion_40, 1, 1, poster, big.NewInt(1e6), big.NewInt(1000), util.TracingDuringEVM, trace,
+	))
+	if trace.WeiAllocated.Int64() != 5e5 || !slices.Contains(trace.Rules, l1pricing.RuleDataGasFactor) {
+		Fail(t, "expected the poster's cost to be scaled by the data gas factor, got", trace.WeiAllocated, trace.Rules)
+	}
+
+	// the discount is covered by the poster, so it isn't undone by raising the price
+	price, err := l1p.PricePerUnit()
+	Require(t, err)
+	if price.Int64() != 1000 {
+		Fail(t, "expected the price to stay at 1000, got", price)
+	}
+	surplus, err := l1p.LastSurplus()
+	Require(t, err)
+	if surplus.Sign() != 0 {
+		Fail(t, "expected no deficit, got surplus", surplus)
+	}
+	if balance := evm.StateDB.GetBalance(poster).ToBig(); balance.Int64() != 5e5 {
+		Fail(t, "expected the poster to be paid the discounted cost, got", balance)
+	}
+}
+
 func TestUpdateTimeUpgradeBehavior(t *testing.T) {
 	evm := newMockEVMForTesting()
 	burner := burn.NewSystemBurner(nil, false)
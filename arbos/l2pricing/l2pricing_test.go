@@ -90,6 +90,25 @@ func TestPricingModelExp(t *testing.T) {
 	}
 }
 
+func TestBaseFeeForBacklogMatchesPricingModel(t *testing.T) {
+	pricing := PricingForTest(t)
+	limit := getSpeedLimit(t, pricing)
+	for _, backlog := range []uint64{0, InitialBacklogTolerance * limit, 100000000, 1 << 40} {
+		expected, err := pricing.BaseFeeForBacklog(backlog)
+		Require(t, err)
+		Require(t, pricing.SetGasBacklog(backlog))
+		pricing.UpdatePricingModel(nil, 0, false)
+		if price := getPrice(t, pricing); !arbmath.BigEquals(expected, arbmath.UintToBig(price)) {
+			Fail(t, "basefee for backlog", backlog, "was", expected, "but the pricing model set", price)
+		}
+	}
+
+	Require(t, pricing.SetPricingInertia(0))
+	if _, err := pricing.BaseFeeForBacklog(1 << 40); err == nil {
+		Fail(t, "expected pricing a backlog without inertia to fail")
+	}
+}
+
 func getPrice(t *testing.T, pricing *L2PricingState) uint64 {
 	value, err := pricing.BaseFeeWei()
 	Require(t, err)
@@ -4,6 +4,7 @@
 package l2pricing
 
 import (
+	"errors"
 	"math/big"
 
 	"github.com/ethereum/go-ethereum/params"
@@ -47,11 +48,39 @@ func (ps *L2PricingState) UpdatePricingModel(l2BaseFee *big.Int, timePassed uint
 	tolerance, _ := ps.BacklogTolerance()
 	backlog, _ := ps.GasBacklog()
 	minBaseFee, _ := ps.MinBaseFeeWei()
+	_ = ps.SetBaseFeeWei(baseFeeForBacklog(backlog, minBaseFee, speedLimit, inertia, tolerance))
+}
+
+// BaseFeeForBacklog computes the basefee the pricing model would set for the given backlog under the current parameters
+func (ps *L2PricingState) BaseFeeForBacklog(backlog uint64) (*big.Int, error) {
+	speedLimit, err := ps.SpeedLimitPerSecond()
+	if err != nil {
+		return nil, err
+	}
+	inertia, err := ps.PricingInertia()
+	if err != nil {
+		return nil, err
+	}
+	tolerance, err := ps.BacklogTolerance()
+	if err != nil {
+		return nil, err
+	}
+	minBaseFee, err := ps.MinBaseFeeWei()
+	if err != nil {
+		return nil, err
+	}
+	if backlog > tolerance*speedLimit && inertia*speedLimit == 0 {
+		return nil, errors.New("pricing inertia and speed limit must be nonzero to price a backlog")
+	}
+	return baseFeeForBacklog(backlog, minBaseFee, speedLimit, inertia, tolerance), nil
+}
+
+func baseFeeForBacklog(backlog uint64, minBaseFee *big.Int, speedLimit, inertia, tolerance uint64) *big.Int {
 	baseFee := minBaseFee
 	if backlog > tolerance*speedLimit {
 		excess := arbmath.SaturatingCast[int64](backlog - tolerance*speedLimit)
 		exponentBips := arbmath.NaturalToBips(excess) / arbmath.SaturatingCast[arbmath.Bips](inertia*speedLimit)
 		baseFee = arbmath.BigMulByBips(minBaseFee, arbmath.ApproxExpBasisPoints(exponentBips, 4))
 	}
-	_ = ps.SetBaseFeeWei(baseFee)
+	return baseFee
 }
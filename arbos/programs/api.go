@@ -187,6 +187,16 @@ func newApiClosures(
 			keccakCost := am.SaturatingUMul(params.Keccak256WordGas, keccakWords)
 			baseCost = am.SaturatingUAdd(baseCost, keccakCost)
 		}
+
+		// limit and meter the init code as both create opcodes do (gasCreateEip3860 and gasCreate2Eip3860)
+		if evm.Context.ArbOSVersion >= util.ArbosVersion_40 {
+			if uint64(len(code)) > chainConfig.MaxInitCodeSize() {
+				return zeroAddr, nil, gas, vm.ErrMaxInitCodeSizeExceeded
+			}
+			initCodeWords := am.WordsForBytes(uint64(len(code)))
+			initCodeCost := am.SaturatingUMul(params.InitCodeWordGas, initCodeWords)
+			baseCost = am.SaturatingUAdd(baseCost, initCodeCost)
+		}
 		if gas < baseCost {
 			return zeroAddr, nil, gas, vm.ErrOutOfGas
 		}
@@ -120,6 +120,12 @@ func writeCost(value common.Hash) uint64 {
 	return StorageWriteCost
 }
 
+func countStorageAccess(burner burn.Burner, write bool) {
+	if counter, ok := burner.(burn.StorageAccessCounter); ok {
+		counter.CountStorageAccess(write)
+	}
+}
+
 func (s *Storage) Account() common.Address {
 	return s.account
 }
@@ -129,6 +135,7 @@ func (s *Storage) Get(key common.Hash) (common.Hash, error) {
 	if err != nil {
 		return common.Hash{}, err
 	}
+	countStorageAccess(s.burner, false)
 	if info := s.burner.TracingInfo(); info != nil {
 		info.RecordStorageGet(key)
 	}
@@ -166,6 +173,7 @@ func (s *Storage) Set(key common.Hash, value common.Hash) error {
 	if err != nil {
 		return err
 	}
+	countStorageAccess(s.burner, true)
 	if info := s.burner.TracingInfo(); info != nil {
 		info.RecordStorageSet(key, value)
 	}
@@ -376,6 +384,7 @@ func (ss *StorageSlot) Get() (common.Hash, error) {
 	if err != nil {
 		return common.Hash{}, err
 	}
+	countStorageAccess(ss.burner, false)
 	if info := ss.burner.TracingInfo(); info != nil {
 		info.RecordStorageGet(ss.slot)
 	}
@@ -391,6 +400,7 @@ func (ss *StorageSlot) Set(value common.Hash) error {
 	if err != nil {
 		return err
 	}
+	countStorageAccess(ss.burner, true)
 	if info := ss.burner.TracingInfo(); info != nil {
 		info.RecordStorageSet(ss.slot, value)
 	}
@@ -0,0 +1,140 @@
+// Copyright 2024, Offchain Labs, Inc.
+// For license information, see https://github.com/nitro/blob/master/LICENSE
+
+package tipdistribution
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/offchainlabs/nitro/arbos/storage"
+	"github.com/offchainlabs/nitro/util/arbmath"
+)
+
+// TipDistribution is a chain owner configured table splitting the tips transactions pay among recipients.
+// While the table is empty, tips follow the default fee routing.
+type TipDistribution struct {
+	storage *storage.Storage
+	count   storage.StorageBackedUint64
+}
+
+// PoolAddress collects a transaction's tips until they're split among the table's recipients
+var PoolAddress = common.HexToAddress("0xA4B00000000000000000000000000000000000f7")
+
+// MaxRecipients bounds the work done splitting the tips of every transaction
+const MaxRecipients = 16
+
+const countOffset uint64 = 0
+
+// each recipient's address and share are stored after the count
+func recipientOffset(index uint64) uint64 {
+	return 1 + 2*index
+}
+
+func sharesOffset(index uint64) uint64 {
+	return 2 + 2*index
+}
+
+var ErrInvalidTable = errors.New("invalid tip distribution table")
+
+func Open(sto *storage.Storage) *TipDistribution {
+	return &TipDistribution{sto, sto.OpenStorageBackedUint64(countOffset)}
+}
+
+// Active returns whether tips are being split per the table
+func (d *TipDistribution) Active() (bool, error) {
+	count, err := d.count.Get()
+	return count > 0, err
+}
+
+// Table returns the recipients and their shares in basis points
+func (d *TipDistribution) Table() ([]common.Address, []uint64, error) {
+	count, err := d.count.Get()
+	if err != nil {
+		return nil, nil, err
+	}
+	recipients := make([]common.Address, 0, count)
+	shares := make([]uint64, 0, count)
+	for i := uint64(0); i < count; i++ {
+		recipient, err := d.storage.OpenStorageBackedAddress(recipientOffset(i)).Get()
+		if err != nil {
+			return nil, nil, err
+		}
+		share, err := d.storage.GetUint64ByUint64(sharesOffset(i))
+		if err != nil {
+			return nil, nil, err
+		}
+		recipients = append(recipients, recipient)
+		shares = append(shares, share)
+	}
+	return recipients, shares, nil
+}
+
+// SetTable replaces the table, whose shares must be nonzero and sum to one in bips.
+// An empty table restores the default fee routing.
+func (d *TipDistribution) SetTable(recipients []common.Address, shares []uint64) error {
+	if len(recipients) != len(shares) {
+		return fmt.Errorf("%w: %d recipients but %d shares", ErrInvalidTable, len(recipients), len(shares))
+	}
+	if len(recipients) > MaxRecipients {
+		return fmt.Errorf("%w: %d recipients is more than the maximum of %d", ErrInvalidTable, len(recipients), MaxRecipients)
+	}
+	if len(recipients) > 0 {
+		total := uint64(0)
+		for i, share := range shares {
+			if share == 0 || share > uint64(arbmath.OneInBips) {
+				return fmt.Errorf("%w: recipient %v has a share of %d bips", ErrInvalidTable, recipients[i], share)
+			}
+			total += share
+		}
+		// #nosec G115
+		if total != uint64(arbmath.OneInBips) {
+			return fmt.Errorf("%w: shares sum to %d bips instead of %d", ErrInvalidTable, total, arbmath.OneInBips)
+		}
+	}
+
+	oldCount, err := d.count.Get()
+	if err != nil {
+		return err
+	}
+	for i := range recipients {
+		// #nosec G115
+		index := uint64(i)
+		if err := d.storage.OpenStorageBackedAddress(recipientOffset(index)).Set(recipients[i]); err != nil {
+			return err
+		}
+		if err := d.storage.SetUint64ByUint64(sharesOffset(index), shares[i]); err != nil {
+			return err
+		}
+	}
+	for i := uint64(len(recipients)); i < oldCount; i++ {
+		if err := d.storage.ClearByUint64(recipientOffset(i)); err != nil {
+			return err
+		}
+		if err := d.storage.ClearByUint64(sharesOffset(i)); err != nil {
+			return err
+		}
+	}
+	return d.count.Set(uint64(len(recipients)))
+}
+
+// Split divides the tips among the table's recipients, rounding each share down.
+// The remainder left by rounding goes to the first recipient, so the whole amount is always paid out.
+func (d *TipDistribution) Split(tips *big.Int) ([]common.Address, []*big.Int, error) {
+	recipients, shares, err := d.Table()
+	if err != nil || len(recipients) == 0 {
+		return nil, nil, err
+	}
+	amounts := make([]*big.Int, len(recipients))
+	remainder := new(big.Int).Set(tips)
+	for i, share := range shares {
+		// #nosec G115
+		amounts[i] = arbmath.BigMulByBips(tips, arbmath.Bips(share))
+		remainder.Sub(remainder, amounts[i])
+	}
+	amounts[0].Add(amounts[0], remainder)
+	return recipients, amounts, nil
+}
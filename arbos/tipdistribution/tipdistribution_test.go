@@ -0,0 +1,91 @@
+// Copyright 2024, Offchain Labs, Inc.
+// For license information, see https://github.com/nitro/blob/master/LICENSE
+
+package tipdistribution
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/offchainlabs/nitro/arbos/burn"
+	"github.com/offchainlabs/nitro/arbos/storage"
+	"github.com/offchainlabs/nitro/util/arbmath"
+	"github.com/offchainlabs/nitro/util/testhelpers"
+)
+
+func TestTipDistribution(t *testing.T) {
+	distribution := Open(storage.NewMemoryBacked(burn.NewSystemBurner(nil, false)))
+	active, err := distribution.Active()
+	Require(t, err)
+	if active {
+		Fail(t, "expected no tip distribution before one is set")
+	}
+
+	sequencer := testhelpers.RandomAddress()
+	treasury := testhelpers.RandomAddress()
+	publicGoods := testhelpers.RandomAddress()
+	Require(t, distribution.SetTable([]common.Address{sequencer, treasury, publicGoods}, []uint64{5000, 3333, 1667}))
+	active, err = distribution.Active()
+	Require(t, err)
+	if !active {
+		Fail(t, "expected the tip distribution to be active")
+	}
+
+	recipients, amounts, err := distribution.Split(big.NewInt(1_000_001))
+	Require(t, err)
+	expected := []int64{500_000 + 1, 333_300, 166_700} // the rounding remainder goes to the first recipient
+	for i, recipient := range []common.Address{sequencer, treasury, publicGoods} {
+		if recipients[i] != recipient || amounts[i].Int64() != expected[i] {
+			Fail(t, "expected recipient", i, "to be", recipient, "paid", expected[i], "got", recipients[i], amounts[i])
+		}
+	}
+
+	invalidTables := []struct {
+		recipients []common.Address
+		shares     []uint64
+	}{
+		{[]common.Address{sequencer}, []uint64{5000, 5000}},
+		{[]common.Address{sequencer, treasury}, []uint64{5000, 4999}},
+		{[]common.Address{sequencer, treasury}, []uint64{10000, 0}},
+		{make([]common.Address, MaxRecipients+1), make([]uint64, MaxRecipients+1)},
+	}
+	for _, table := range invalidTables {
+		if err := distribution.SetTable(table.recipients, table.shares); !errors.Is(err, ErrInvalidTable) {
+			Fail(t, "expected table", table.recipients, table.shares, "to be rejected, got", err)
+		}
+	}
+
+	// shrinking the table clears the entries it no longer has
+	// #nosec G115
+	Require(t, distribution.SetTable([]common.Address{treasury}, []uint64{uint64(arbmath.OneInBips)}))
+	recipients, shares, err := distribution.Table()
+	Require(t, err)
+	if len(recipients) != 1 || recipients[0] != treasury || shares[0] != uint64(arbmath.OneInBips) {
+		Fail(t, "unexpected table after shrinking it", recipients, shares)
+	}
+	stale, err := distribution.storage.GetUint64ByUint64(sharesOffset(2))
+	Require(t, err)
+	if stale != 0 {
+		Fail(t, "expected removed entries to be cleared, got share", stale)
+	}
+
+	Require(t, distribution.SetTable(nil, nil))
+	active, err = distribution.Active()
+	Require(t, err)
+	if active {
+		Fail(t, "expected an empty table to disable the tip distribution")
+	}
+}
+
+func Require(t *testing.T, err error, printables ...interface{}) {
+	t.Helper()
+	testhelpers.RequireImpl(t, err, printables...)
+}
+
+func Fail(t *testing.T, printables ...interface{}) {
+	t.Helper()
+	testhelpers.FailImpl(t, printables...)
+}
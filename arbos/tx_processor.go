@@ -21,6 +21,7 @@ import (
 	"github.com/offchainlabs/nitro/arbos/arbosState"
 	"github.com/offchainlabs/nitro/arbos/l1pricing"
 	"github.com/offchainlabs/nitro/arbos/retryables"
+	"github.com/offchainlabs/nitro/arbos/tipdistribution"
 	"github.com/offchainlabs/nitro/arbos/util"
 	"github.com/offchainlabs/nitro/util/arbmath"
 )
@@ -45,6 +46,7 @@ type TxProcessor struct {
 	evm              *vm.EVM
 	CurrentRetryable *common.Hash
 	CurrentRefundTo  *common.Address
+	gasPaymaster     *common.Address // set in StartTxHook if the gas paymaster prepaid this tx's gas
 
 	// Caches for the latest L1 block number and hash,
 	// for the NUMBER and BLOCKHASH opcodes.
@@ -289,6 +291,13 @@ func (p *TxProcessor) StartTxHook() (endTxNow bool, gasUsed uint64, err error, r
 		// evm.Context.BaseFee is already lowered to 0 when vm runs with NoBaseFee flag and 0 gas price
 		effectiveBaseFee := evm.Context.BaseFee
 		usergas := p.msg.GasLimit
+		if p.state.ArbOSVersion() >= util.ArbosVersion_40 {
+			autoRedeemGasLimit, err := p.state.RetryableAutoRedeemGasLimit()
+			p.state.Restrict(err)
+			if autoRedeemGasLimit != 0 && usergas > autoRedeemGasLimit {
+				usergas = autoRedeemGasLimit
+			}
+		}
 
 		maxGasCost := arbmath.BigMulByUint(tx.GasFeeCap, usergas)
 		maxFeePerGasTooLow := arbmath.BigLessThan(tx.GasFeeCap, effectiveBaseFee)
@@ -340,6 +349,14 @@ func (p *TxProcessor) StartTxHook() (endTxNow bool, gasUsed uint64, err error, r
 		if err := transfer(&tx.From, &tx.FeeRefundAddr, gasPriceRefund); err != nil {
 			glog.Error("failed to transfer gasPriceRefund", "err", err)
 		}
+		if p.state.ArbOSVersion() >= util.ArbosVersion_40 && usergas < p.msg.GasLimit {
+			// the auto-redeem was capped, so refund the basefee paid for gas it won't be given
+			cappedGasRefund := arbmath.BigMulByUint(effectiveBaseFee, p.msg.GasLimit-usergas)
+			cappedGasRefund = takeFunds(availableRefund, cappedGasRefund)
+			if err := transfer(&tx.From, &tx.FeeRefundAddr, cappedGasRefund); err != nil {
+				glog.Error("failed to transfer cappedGasRefund", "err", err)
+			}
+		}
 		availableRefund.Add(availableRefund, withheldGasFunds)
 		availableRefund.Add(availableRefund, withheldSubmissionFee)
 
@@ -399,10 +416,42 @@ func (p *TxProcessor) StartTxHook() (endTxNow bool, gasUsed uint64, err error, r
 		refundTo := tx.RefundTo
 		p.CurrentRetryable = &ticketId
 		p.CurrentRefundTo = &refundTo
+	default:
+		if p.state.ArbOSVersion() >= util.ArbosVersion_40 && tipe < types.ArbitrumDepositTxType {
+			p.prepayFromGasPaymaster()
+		}
 	}
 	return false, 0, nil, nil
 }
 
+// prepayFromGasPaymaster has the gas paymaster, if there is one, pay for the gas of a user transaction
+// offering no fee from a sender the owner has let it sponsor. The paymaster's funds are given to the sender, whom geth then charges at the basefee
+// as usual. Whatever gas goes unused is returned to the paymaster in EndTxHook. If the paymaster can't
+// cover the transaction, geth rejects it for offering too low a fee.
+func (p *TxProcessor) prepayFromGasPaymaster() {
+	if !p.msg.TxRunMode.ExecutedOnChain() || p.msg.GasFeeCap.Sign() != 0 || p.msg.GasTipCap.Sign() != 0 {
+		return
+	}
+	paymaster, err := p.state.GasPaymaster()
+	p.state.Restrict(err)
+	if paymaster == (common.Address{}) {
+		return
+	}
+	sponsored, err := p.state.GasPaymasterSponsoredSenders().IsMember(p.msg.From)
+	p.state.Restrict(err)
+	if !sponsored {
+		return
+	}
+	basefee := p.evm.Context.BaseFee
+	prepaid := arbmath.BigMulByUint(basefee, p.msg.GasLimit)
+	if err := util.TransferBalance(&paymaster, &p.msg.From, prepaid, p.evm, util.TracingBeforeEVM, "paymaster"); err != nil {
+		return
+	}
+	p.msg.GasPrice = new(big.Int).Set(basefee)
+	p.msg.GasFeeCap = new(big.Int).Set(basefee)
+	p.gasPaymaster = &paymaster
+}
+
 func GetPosterGas(state *arbosState.ArbosState, baseFee *big.Int, runMode core.MessageRunMode, posterCost *big.Int) uint64 {
 	if runMode == core.MessageGasEstimationMode {
 		// Suggest the amount of gas needed for a given amount of ETH is higher in case of congestion.
@@ -431,6 +480,10 @@ func (p *TxProcessor) GasChargingHook(gasRemaining *uint64) (common.Address, err
 
 	var gasNeededToStartEVM uint64
 	tipReceipient, _ := p.state.NetworkFeeAccount()
+	if p.distributesTips() {
+		// collect the tips to split them among the tip distribution table's recipients once the tx is done
+		tipReceipient = tipdistribution.PoolAddress
+	}
 	var basefee *big.Int
 	if p.evm.Context.BaseFeeInBlock != nil {
 		basefee = p.evm.Context.BaseFeeInBlock
@@ -508,6 +561,9 @@ func (p *TxProcessor) EndTxHook(gasLeft uint64, success bool) {
 		panic("Tx somehow refunds gas after computation")
 	}
 	gasUsed := p.msg.GasLimit - gasLeft
+	if p.state.ArbOSVersion() >= util.ArbosVersion_40 {
+		p.state.Restrict(p.state.AddToTotalGasUsed(gasUsed))
+	}
 
 	if underlyingTx != nil && underlyingTx.Type() == types.ArbitrumRetryTxType {
 		inner, _ := underlyingTx.GetInner().(*types.ArbitrumRetryTx)
@@ -611,6 +667,18 @@ func (p *TxProcessor) EndTxHook(gasLeft uint64, success bool) {
 		return
 	}
 
+	if p.gasPaymaster != nil {
+		// return the paymaster's prepayment for the gas geth refunded to the sender
+		refund := arbmath.BigMulByUint(p.msg.GasPrice, gasLeft)
+		if err := util.TransferBalance(&p.msg.From, p.gasPaymaster, refund, p.evm, scenario, "paymasterRefund"); err != nil {
+			log.Error("failed to return unused gas to the paymaster", "paymaster", *p.gasPaymaster, "refund", refund, "err", err)
+		}
+	}
+
+	if p.distributesTips() {
+		p.distributeTips(scenario)
+	}
+
 	var basefee *big.Int
 	if p.evm.Context.BaseFeeInBlock != nil {
 		basefee = p.evm.Context.BaseFeeInBlock
@@ -747,10 +815,39 @@ func (p *TxProcessor) L1BlockHash(blockCtx vm.BlockContext, l1BlockNumber uint64
 }
 
 func (p *TxProcessor) DropTip() bool {
+	if p.distributesTips() {
+		return false
+	}
 	version := p.state.ArbOSVersion()
 	return version != params.ArbosVersion_9 || p.delayedInbox
 }
 
+// distributesTips returns whether the tx's tips are collected and split per the chain owner's tip distribution table
+func (p *TxProcessor) distributesTips() bool {
+	if p.state.ArbOSVersion() < util.ArbosVersion_40 || p.delayedInbox {
+		return false
+	}
+	active, err := p.state.TipDistribution().Active()
+	p.state.Restrict(err)
+	return active
+}
+
+// distributeTips pays out the tips geth credited to the pool to the tip distribution table's recipients
+func (p *TxProcessor) distributeTips(scenario util.TracingScenario) {
+	pool := tipdistribution.PoolAddress
+	tips := p.evm.StateDB.GetBalance(pool).ToBig()
+	if tips.Sign() == 0 {
+		return
+	}
+	recipients, amounts, err := p.state.TipDistribution().Split(tips)
+	p.state.Restrict(err)
+	for i, recipient := range recipients {
+		if err := util.TransferBalance(&pool, &recipient, amounts[i], p.evm, scenario, "tipDistribution"); err != nil {
+			log.Error("failed to distribute tips", "recipient", recipient, "amount", amounts[i], "err", err)
+		}
+	}
+}
+
 func (p *TxProcessor) GetPaidGasPrice() *big.Int {
 	gasPrice := p.evm.GasPrice
 	version := p.state.ArbOSVersion()
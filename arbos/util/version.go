@@ -0,0 +1,10 @@
+// Copyright 2024, Offchain Labs, Inc.
+// For license information, see https://github.com/nitro/blob/master/LICENSE
+
+package util
+
+// ArbosVersion_40 is the first ArbOS version after ArbOS 32, which chains are already running,
+// so new precompile methods and changes to how blocks execute are gated on it.
+// Versions 33 through 39 are left to Orbit chains for custom upgrades.
+// It belongs with the other ArbOS versions in go-ethereum's params, and moves there when geth next updates.
+const ArbosVersion_40 = uint64(40)
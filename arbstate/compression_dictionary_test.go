@@ -0,0 +1,162 @@
+// Copyright 2024, Offchain Labs, Inc.
+// For license information, see https://github.com/nitro/blob/master/LICENSE
+
+package arbstate
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"math/rand"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rlp"
+
+	"github.com/offchainlabs/nitro/arbcompress"
+	"github.com/offchainlabs/nitro/arbos/arbosState"
+	"github.com/offchainlabs/nitro/arbstate/daprovider"
+)
+
+// dictionaryMultiplexerBackend remembers the compression dictionaries distributed to it
+type dictionaryMultiplexerBackend struct {
+	multiplexerBackend
+	dictionaries map[common.Hash][]byte
+}
+
+func (b *dictionaryMultiplexerBackend) GetCompressionDictionary(hash common.Hash) ([]byte, error) {
+	dictionary, ok := b.dictionaries[hash]
+	if !ok {
+		return nil, fmt.Errorf("%w %v", ErrUnknownCompressionDictionary, hash)
+	}
+	return dictionary, nil
+}
+
+func (b *dictionaryMultiplexerBackend) AddCompressionDictionary(dictionary []byte) error {
+	b.dictionaries[crypto.Keccak256Hash(dictionary)] = dictionary
+	return nil
+}
+
+func testAddresses(kind string, count int) []common.Address {
+	addresses := make([]common.Address, count)
+	for i := range addresses {
+		addresses[i] = common.BytesToAddress(crypto.Keccak256([]byte(fmt.Sprint(kind, i))))
+	}
+	return addresses
+}
+
+// transferSegments makes segments of signed ERC20 transfers, mostly of a few popular tokens to a few popular recipients
+func transferSegments(t testing.TB, rng *rand.Rand, count int) [][]byte {
+	tokens := testAddresses("token", 8)
+	recipients := testAddresses("recipient", 32)
+	transferSelector := common.FromHex("0xa9059cbb")
+	segments := make([][]byte, 0, count)
+	for i := 0; i < count; i++ {
+		recipient := recipients[rng.Intn(len(recipients))]
+		if rng.Intn(4) == 0 {
+			rng.Read(recipient[:])
+		}
+		amount := new(big.Int).Mul(big.NewInt(rng.Int63n(10_000)+1), big.NewInt(1e15))
+		data := append([]byte{}, transferSelector...)
+		data = append(data, common.LeftPadBytes(recipient.Bytes(), 32)...)
+		data = append(data, common.LeftPadBytes(amount.Bytes(), 32)...)
+		token := tokens[rng.Intn(len(tokens))]
+		signature := make([]byte, 64)
+		rng.Read(signature)
+		tx := types.NewTx(&types.DynamicFeeTx{
+			ChainID:   big.NewInt(412346),
+			Nonce:     uint64(rng.Intn(1000)),
+			GasTipCap: big.NewInt(0),
+			GasFeeCap: big.NewInt(100_000_000),
+			Gas:       uint64(50_000 + rng.Intn(20_000)),
+			To:        &token,
+			Data:      data,
+			V:         big.NewInt(int64(rng.Intn(2))),
+			R:         new(big.Int).SetBytes(signature[:32]),
+			S:         new(big.Int).SetBytes(signature[32:]),
+		})
+		txBytes, err := tx.MarshalBinary()
+		if err != nil {
+			t.Fatal(err)
+		}
+		// an L2 message of kind 4 is a single signed transaction
+		segments = append(segments, append([]byte{BatchSegmentKindL2Message, 4}, txBytes...))
+	}
+	return segments
+}
+
+func encodeSegments(t testing.TB, segments [][]byte) []byte {
+	var encoded []byte
+	for _, segment := range segments {
+		bytes, err := rlp.EncodeToBytes(segment)
+		if err != nil {
+			t.Fatal(err)
+		}
+		encoded = append(encoded, bytes...)
+	}
+	return encoded
+}
+
+func TestCompressionDictionary(t *testing.T) {
+	ctx := context.Background()
+	dictionary := encodeSegments(t, transferSegments(t, rand.New(rand.NewSource(1)), 200))
+	if len(dictionary) > arbosState.MaxCompressionDictionarySize {
+		t.Fatal("dictionary too large", len(dictionary))
+	}
+	segments := transferSegments(t, rand.New(rand.NewSource(2)), 100)
+	encoded := encodeSegments(t, segments)
+
+	plain, err := arbcompress.CompressWell(encoded)
+	if err != nil {
+		t.Fatal(err)
+	}
+	withDictionary, err := arbcompress.CompressWithDictionaryBytes(encoded, arbcompress.LEVEL_WELL, dictionary)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Logf(
+		"compressed %d bytes of transfers to %d bytes, or %d bytes with a %d byte dictionary (%.1f%% smaller)",
+		len(encoded), len(plain), len(withDictionary), len(dictionary), 100*(1-float64(len(withDictionary))/float64(len(plain))),
+	)
+	if len(withDictionary) >= len(plain) {
+		t.Fatal("dictionary didn't improve compression")
+	}
+
+	hash := crypto.Keccak256Hash(dictionary)
+	payload := append([]byte{daprovider.BrotliDictionaryMessageHeaderByte}, hash.Bytes()...)
+	payload = append(payload, withDictionary...)
+	if found, ok := BatchCompressionDictionary(payload); !ok || found != hash {
+		t.Fatal("expected batch to use dictionary", hash, "got", found, ok)
+	}
+	batch := testBatchWithPayload(payload)
+
+	// the batch can't be read until the dictionary is distributed
+	multiplexer := NewInboxMultiplexer(&multiplexerBackend{batch: batch}, 0, nil, daprovider.KeysetValidate)
+	if _, err := multiplexer.Pop(ctx); !errors.Is(err, ErrUnknownCompressionDictionary) {
+		t.Fatal("expected unknown dictionary error, got", err)
+	}
+
+	dictionaries := make(map[common.Hash][]byte)
+	distribution := testBatch(t, append([]byte{BatchSegmentKindCompressionDictionary}, dictionary...), segments[0])
+	messages := readBatch(t, &dictionaryMultiplexerBackend{multiplexerBackend{batch: distribution}, dictionaries})
+	if len(messages) != 1 {
+		t.Fatal("expected only the message after the dictionary, got", len(messages))
+	}
+	if !bytes.Equal(dictionaries[hash], dictionary) {
+		t.Fatal("dictionary wasn't distributed")
+	}
+
+	messages = readBatch(t, &dictionaryMultiplexerBackend{multiplexerBackend{batch: batch}, dictionaries})
+	if len(messages) != len(segments) {
+		t.Fatal("expected", len(segments), "messages, got", len(messages))
+	}
+	for i, msg := range messages {
+		if !bytes.Equal(msg.Message.L2msg, segments[i][1:]) {
+			t.Fatal("message", i, "doesn't match its segment")
+		}
+	}
+}
@@ -88,8 +88,19 @@ const BlobHashesHeaderFlag byte = L1AuthenticatedMessageHeaderFlag | 0x10 // 0x5
 // BrotliMessageHeaderByte indicates that the message is brotli-compressed.
 const BrotliMessageHeaderByte byte = 0
 
+// BrotliDictionaryMessageHeaderByte indicates that the message is brotli-compressed with a dictionary,
+// whose keccak hash follows the header byte.
+// Only replay binaries from this nitro version decode it, so a chain's WASM module root must be upgraded
+// before its owner activates a compression dictionary.
+const BrotliDictionaryMessageHeaderByte byte = 0x04
+
+// UncompressedMessageHeaderByte indicates that the message is its RLP-encoded segments without any compression.
+// Only replay binaries from this nitro version decode it, so a chain's WASM module root must be upgraded
+// before its owner disables batch compression.
+const UncompressedMessageHeaderByte byte = 0x02
+
 // KnownHeaderBits is all header bits with known meaning to this nitro version
-const KnownHeaderBits byte = DASMessageHeaderFlag | TreeDASMessageHeaderFlag | L1AuthenticatedMessageHeaderFlag | ZeroheavyMessageHeaderFlag | BlobHashesHeaderFlag | BrotliMessageHeaderByte
+const KnownHeaderBits byte = DASMessageHeaderFlag | TreeDASMessageHeaderFlag | L1AuthenticatedMessageHeaderFlag | ZeroheavyMessageHeaderFlag | BlobHashesHeaderFlag | BrotliMessageHeaderByte | BrotliDictionaryMessageHeaderByte | UncompressedMessageHeaderByte
 
 // hasBits returns true if `checking` has all `bits`
 func hasBits(checking byte, bits byte) bool {
@@ -120,6 +131,14 @@ func IsBrotliMessageHeaderByte(b uint8) bool {
 	return b == BrotliMessageHeaderByte
 }
 
+func IsBrotliDictionaryMessageHeaderByte(b uint8) bool {
+	return b == BrotliDictionaryMessageHeaderByte
+}
+
+func IsUncompressedMessageHeaderByte(b uint8) bool {
+	return b == UncompressedMessageHeaderByte
+}
+
 // IsKnownHeaderByte returns true if the supplied header byte has only known bits
 func IsKnownHeaderByte(b uint8) bool {
 	return b&^KnownHeaderBits == 0
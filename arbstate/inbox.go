@@ -36,6 +36,119 @@ type InboxBackend interface {
 	ReadDelayedInbox(seqNum uint64) (*arbostypes.L1IncomingMessage, error)
 }
 
+// MalformedBatchClass classifies why a batch, or part of it, couldn't be decoded.
+type MalformedBatchClass uint8
+
+const (
+	MalformedBatchBadZeroheavy         MalformedBatchClass = iota + 1 // the zeroheavy encoding couldn't be decoded
+	MalformedBatchBadCompression                                      // the brotli stream couldn't be decompressed
+	MalformedBatchBadSegment                                          // a segment isn't valid RLP
+	MalformedBatchSegmentOverflow                                     // the batch has more than MaxSegmentsPerSequencerMessage segments
+	MalformedBatchUnknownFormat                                       // the payload has an unknown header byte
+	MalformedBatchEmptyPayload                                        // the batch has no payload after its L1 header
+	MalformedBatchBadAdvance                                          // a timestamp or L1 block number advancing segment isn't a valid number
+	MalformedBatchBadCompressedMessage                                // a brotli compressed L2 message couldn't be decompressed
+	MalformedBatchInvalidSegmentKind                                  // a segment has an unknown kind
+	MalformedBatchEmptySegment                                        // a segment has no kind byte
+)
+
+var malformedBatchClassNames = map[MalformedBatchClass]string{
+	MalformedBatchBadZeroheavy:         "bad_zeroheavy",
+	MalformedBatchBadCompression:       "bad_compression",
+	MalformedBatchBadSegment:           "bad_segment",
+	MalformedBatchSegmentOverflow:      "segment_overflow",
+	MalformedBatchUnknownFormat:        "unknown_format",
+	MalformedBatchEmptyPayload:         "empty_payload",
+	MalformedBatchBadAdvance:           "bad_advance",
+	MalformedBatchBadCompressedMessage: "bad_compressed_message",
+	MalformedBatchInvalidSegmentKind:   "invalid_segment_kind",
+	MalformedBatchEmptySegment:         "empty_segment",
+}
+
+// AllMalformedBatchClasses lists every class, in order.
+func AllMalformedBatchClasses() []MalformedBatchClass {
+	classes := make([]MalformedBatchClass, 0, len(malformedBatchClassNames))
+	for class := MalformedBatchBadZeroheavy; class <= MalformedBatchEmptySegment; class++ {
+		classes = append(classes, class)
+	}
+	return classes
+}
+
+func (c MalformedBatchClass) String() string {
+	if name, ok := malformedBatchClassNames[c]; ok {
+		return name
+	}
+	return fmt.Sprintf("unknown_%d", uint8(c))
+}
+
+// MalformedBatchReporter may be implemented by an InboxBackend to be told about batches that couldn't be fully decoded.
+// The undecodable parts are skipped as the spec requires whether or not they're reported, so reporting never affects derivation.
+type MalformedBatchReporter interface {
+	ReportMalformedBatch(batchNum uint64, class MalformedBatchClass, data []byte, err error)
+}
+
+// ErrUnknownCompressionDictionary means a batch is compressed with a dictionary that hasn't been distributed.
+// Like data that isn't available, it keeps the batch from being read rather than making it malformed.
+var ErrUnknownCompressionDictionary = errors.New("batch compressed with an unknown dictionary")
+
+// CompressionDictionaryReader finds the dictionaries batches are compressed with by their keccak hash.
+type CompressionDictionaryReader interface {
+	// GetCompressionDictionary returns an error wrapping ErrUnknownCompressionDictionary if the dictionary isn't known.
+	GetCompressionDictionary(hash common.Hash) ([]byte, error)
+}
+
+// CompressionDictionaryBackend may be implemented by an InboxBackend to read batches compressed with a dictionary.
+// Each dictionary distributed in a batch is added as the batch is parsed, before any message is read from it.
+type CompressionDictionaryBackend interface {
+	CompressionDictionaryReader
+	AddCompressionDictionary(dictionary []byte) error
+}
+
+func getCompressionDictionary(dictionaries CompressionDictionaryReader, hash common.Hash) ([]byte, error) {
+	if dictionaries == nil {
+		return nil, fmt.Errorf("%w %v: no dictionaries to look it up in", ErrUnknownCompressionDictionary, hash)
+	}
+	return dictionaries.GetCompressionDictionary(hash)
+}
+
+func distributeCompressionDictionaries(seqMsg *sequencerMessage, dictionaries CompressionDictionaryBackend) error {
+	if dictionaries == nil {
+		return nil
+	}
+	for _, segment := range seqMsg.segments {
+		if len(segment) > 0 && segment[0] == BatchSegmentKindCompressionDictionary {
+			if err := dictionaries.AddCompressionDictionary(segment[1:]); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// BatchCompressionDictionary returns the hash of the dictionary a batch's payload is compressed with, if any.
+// The payload is the batch after its L1 header, with any data availability header already resolved.
+func BatchCompressionDictionary(payload []byte) (common.Hash, bool) {
+	if len(payload) > 0 && daprovider.IsZeroheavyEncodedHeaderByte(payload[0]) {
+		header := make([]byte, 1+common.HashLength)
+		if _, err := io.ReadFull(zeroheavy.NewZeroheavyDecoder(bytes.NewReader(payload[1:])), header); err != nil {
+			return common.Hash{}, false
+		}
+		payload = header
+	}
+	if len(payload) <= common.HashLength || !daprovider.IsBrotliDictionaryMessageHeaderByte(payload[0]) {
+		return common.Hash{}, false
+	}
+	return common.BytesToHash(payload[1 : 1+common.HashLength]), true
+}
+
+type malformedBatchReportFunc func(class MalformedBatchClass, err error)
+
+func (f malformedBatchReportFunc) report(class MalformedBatchClass, err error) {
+	if f != nil {
+		f(class, err)
+	}
+}
+
 type sequencerMessage struct {
 	minTimestamp         uint64
 	maxTimestamp         uint64
@@ -43,13 +156,14 @@ type sequencerMessage struct {
 	maxL1Block           uint64
 	afterDelayedMessages uint64
 	segments             [][]byte
+	segmentOffsets       []uint64 // offset of each segment in the decompressed payload
 }
 
 const MaxDecompressedLen int = 1024 * 1024 * 16 // 16 MiB
 const maxZeroheavyDecompressedLen = 101*MaxDecompressedLen/100 + 64
 const MaxSegmentsPerSequencerMessage = 100 * 1024
 
-func parseSequencerMessage(ctx context.Context, batchNum uint64, batchBlockHash common.Hash, data []byte, dapReaders []daprovider.Reader, keysetValidationMode daprovider.KeysetValidationMode) (*sequencerMessage, error) {
+func parseSequencerMessage(ctx context.Context, batchNum uint64, batchBlockHash common.Hash, data []byte, dapReaders []daprovider.Reader, dictionaries CompressionDictionaryReader, keysetValidationMode daprovider.KeysetValidationMode, reportMalformed malformedBatchReportFunc) (*sequencerMessage, error) {
 	if len(data) < 40 {
 		return nil, errors.New("sequencer message missing L1 header")
 	}
@@ -118,42 +232,73 @@ func parseSequencerMessage(ctx context.Context, batchNum uint64, batchBlockHash
 	if len(payload) > 0 && daprovider.IsZeroheavyEncodedHeaderByte(payload[0]) {
 		pl, err := io.ReadAll(io.LimitReader(zeroheavy.NewZeroheavyDecoder(bytes.NewReader(payload[1:])), int64(maxZeroheavyDecompressedLen)))
 		if err != nil {
-			log.Warn("error reading from zeroheavy decoder", err.Error())
+			log.Warn("error reading from zeroheavy decoder", "err", err)
+			reportMalformed.report(MalformedBatchBadZeroheavy, err)
 			return parsedMsg, nil
 		}
 		payload = pl
 	}
 
 	// Stage 3: Decompress the brotli payload and fill the parsedMsg.segments list.
-	if len(payload) > 0 && daprovider.IsBrotliMessageHeaderByte(payload[0]) {
-		decompressed, err := arbcompress.Decompress(payload[1:], MaxDecompressedLen)
+	isBrotli := len(payload) > 0 && daprovider.IsBrotliMessageHeaderByte(payload[0])
+	isBrotliWithDictionary := len(payload) > common.HashLength && daprovider.IsBrotliDictionaryMessageHeaderByte(payload[0])
+	isUncompressed := len(payload) > 0 && daprovider.IsUncompressedMessageHeaderByte(payload[0])
+	if isBrotli || isBrotliWithDictionary || isUncompressed {
+		var decompressed []byte
+		var err error
+		if isBrotliWithDictionary {
+			// like data that isn't available, a dictionary that can't be found is a real error rather than a malformed batch
+			hash := common.BytesToHash(payload[1 : 1+common.HashLength])
+			dictionary, realErr := getCompressionDictionary(dictionaries, hash)
+			if realErr != nil {
+				return nil, realErr
+			}
+			decompressed, err = arbcompress.DecompressWithDictionaryBytes(payload[1+common.HashLength:], MaxDecompressedLen, dictionary)
+		} else if isUncompressed {
+			decompressed = payload[1:]
+			if len(decompressed) > MaxDecompressedLen {
+				err = fmt.Errorf("uncompressed payload of %d bytes exceeds the maximum of %d", len(decompressed), MaxDecompressedLen)
+			}
+		} else {
+			decompressed, err = arbcompress.Decompress(payload[1:], MaxDecompressedLen)
+		}
 		if err == nil {
 			reader := bytes.NewReader(decompressed)
 			stream := rlp.NewStream(reader, uint64(MaxDecompressedLen))
 			for {
 				var segment []byte
+				// #nosec G115
+				offset := uint64(len(decompressed) - reader.Len())
 				err := stream.Decode(&segment)
 				if err != nil {
-					if !errors.Is(err, io.EOF) && !errors.Is(err, io.ErrUnexpectedEOF) {
+					if errors.Is(err, io.ErrUnexpectedEOF) {
+						reportMalformed.report(MalformedBatchBadSegment, err)
+					} else if !errors.Is(err, io.EOF) {
 						log.Warn("error parsing sequencer message segment", "err", err.Error())
+						reportMalformed.report(MalformedBatchBadSegment, err)
 					}
 					break
 				}
 				if len(parsedMsg.segments) >= MaxSegmentsPerSequencerMessage {
 					log.Warn("too many segments in sequence batch")
+					reportMalformed.report(MalformedBatchSegmentOverflow, fmt.Errorf("more than %d segments", MaxSegmentsPerSequencerMessage))
 					break
 				}
 				parsedMsg.segments = append(parsedMsg.segments, segment)
+				parsedMsg.segmentOffsets = append(parsedMsg.segmentOffsets, offset)
 			}
 		} else {
 			log.Warn("sequencer msg decompression failed", "err", err)
+			reportMalformed.report(MalformedBatchBadCompression, err)
 		}
 	} else {
 		length := len(payload)
 		if length == 0 {
 			log.Warn("empty sequencer message")
+			reportMalformed.report(MalformedBatchEmptyPayload, errors.New("empty sequencer message"))
 		} else {
 			log.Warn("unknown sequencer message format", "length", length, "firstByte", payload[0])
+			reportMalformed.report(MalformedBatchUnknownFormat, fmt.Errorf("unknown header byte 0x%02x", payload[0]))
 		}
 
 	}
@@ -161,6 +306,39 @@ func parseSequencerMessage(ctx context.Context, batchNum uint64, batchBlockHash
 	return parsedMsg, nil
 }
 
+// BatchSegment is a segment of a sequencer batch that yields a message.
+type BatchSegment struct {
+	Offset uint64 // byte offset of the RLP-encoded segment within the decompressed batch payload
+	Data   []byte
+}
+
+// ParseMessageSegments parses a serialized sequencer batch and returns the segments which yield messages,
+// in the order the inbox multiplexer reads them, along with the batch's delayed message count.
+// Timestamp and L1 block number advancing segments and compression dictionaries don't yield messages and are omitted.
+// Any messages the batch produces after its last segment are delayed messages, which have no segment of their own.
+// Batches compressed with a dictionary can only be parsed if dictionaries isn't nil.
+func ParseMessageSegments(ctx context.Context, batchNum uint64, batchBlockHash common.Hash, data []byte, dapReaders []daprovider.Reader, dictionaries CompressionDictionaryReader) ([]BatchSegment, uint64, error) {
+	seqMsg, err := parseSequencerMessage(ctx, batchNum, batchBlockHash, data, dapReaders, dictionaries, daprovider.KeysetDontValidate, nil)
+	if err != nil {
+		return nil, 0, err
+	}
+	var segments []BatchSegment
+	for i, segment := range seqMsg.segments {
+		if len(segment) == 0 {
+			continue
+		}
+		kind := segment[0]
+		if kind == BatchSegmentKindAdvanceTimestamp || kind == BatchSegmentKindAdvanceL1BlockNumber || kind == BatchSegmentKindCompressionDictionary {
+			continue
+		}
+		segments = append(segments, BatchSegment{
+			Offset: seqMsg.segmentOffsets[i],
+			Data:   segment,
+		})
+	}
+	return segments, seqMsg.afterDelayedMessages, nil
+}
+
 type inboxMultiplexer struct {
 	backend                   InboxBackend
 	delayedMessagesRead       uint64
@@ -172,6 +350,9 @@ type inboxMultiplexer struct {
 	cachedSegmentBlockNumber  uint64
 	cachedSubMessageNumber    uint64
 	keysetValidationMode      daprovider.KeysetValidationMode
+
+	// reportMalformed is nil unless the backend is a MalformedBatchReporter
+	reportMalformed malformedBatchReportFunc
 }
 
 func NewInboxMultiplexer(backend InboxBackend, delayedMessagesRead uint64, dapReaders []daprovider.Reader, keysetValidationMode daprovider.KeysetValidationMode) arbostypes.InboxMultiplexer {
@@ -189,6 +370,10 @@ const BatchSegmentKindDelayedMessages uint8 = 2
 const BatchSegmentKindAdvanceTimestamp uint8 = 3
 const BatchSegmentKindAdvanceL1BlockNumber uint8 = 4
 
+// BatchSegmentKindCompressionDictionary distributes a dictionary later batches can be compressed with.
+// It doesn't yield a message.
+const BatchSegmentKindCompressionDictionary uint8 = 5
+
 // Pop returns the message from the top of the sequencer inbox and removes it from the queue.
 // Note: this does *not* return parse errors, those are transformed into invalid messages
 func (r *inboxMultiplexer) Pop(ctx context.Context) (*arbostypes.MessageWithMetadata, error) {
@@ -199,11 +384,23 @@ func (r *inboxMultiplexer) Pop(ctx context.Context) (*arbostypes.MessageWithMeta
 			return nil, realErr
 		}
 		r.cachedSequencerMessageNum = r.backend.GetSequencerInboxPosition()
-		var err error
-		r.cachedSequencerMessage, err = parseSequencerMessage(ctx, r.cachedSequencerMessageNum, batchBlockHash, bytes, r.dapReaders, r.keysetValidationMode)
+		r.reportMalformed = nil
+		if reporter, ok := r.backend.(MalformedBatchReporter); ok {
+			batchNum := r.cachedSequencerMessageNum
+			r.reportMalformed = func(class MalformedBatchClass, err error) {
+				reporter.ReportMalformedBatch(batchNum, class, bytes, err)
+			}
+		}
+		// dictionaries is nil unless the backend is a CompressionDictionaryBackend
+		dictionaries, _ := r.backend.(CompressionDictionaryBackend)
+		seqMsg, err := parseSequencerMessage(ctx, r.cachedSequencerMessageNum, batchBlockHash, bytes, r.dapReaders, dictionaries, r.keysetValidationMode, r.reportMalformed)
 		if err != nil {
 			return nil, err
 		}
+		if err := distributeCompressionDictionaries(seqMsg, dictionaries); err != nil {
+			return nil, err
+		}
+		r.cachedSequencerMessage = seqMsg
 	}
 	msg, err := r.getNextMsg()
 	// advance even if there was an error
@@ -282,11 +479,17 @@ func (r *inboxMultiplexer) getNextMsg() (*arbostypes.MessageWithMetadata, error)
 			continue
 		}
 		segmentKind := segment[0]
+		if segmentKind == BatchSegmentKindCompressionDictionary {
+			// dictionaries were distributed when the batch was parsed
+			segmentNum++
+			continue
+		}
 		if segmentKind == BatchSegmentKindAdvanceTimestamp || segmentKind == BatchSegmentKindAdvanceL1BlockNumber {
 			rd := bytes.NewReader(segment[1:])
 			advancing, err := rlp.NewStream(rd, 16).Uint64()
 			if err != nil {
 				log.Warn("error parsing sequencer advancing segment", "err", err)
+				r.reportMalformed.report(MalformedBatchBadAdvance, err)
 				segmentNum++
 				continue
 			}
@@ -326,6 +529,7 @@ func (r *inboxMultiplexer) getNextMsg() (*arbostypes.MessageWithMetadata, error)
 	}
 	if len(segment) == 0 {
 		log.Error("empty sequencer message segment", "sequence", r.cachedSegmentNum, "segmentNum", segmentNum)
+		r.reportMalformed.report(MalformedBatchEmptySegment, fmt.Errorf("segment %d is empty", segmentNum))
 		return nil, nil
 	}
 	kind := segment[0]
@@ -337,6 +541,7 @@ func (r *inboxMultiplexer) getNextMsg() (*arbostypes.MessageWithMetadata, error)
 			decompressed, err := arbcompress.Decompress(segment, arbostypes.MaxL2MessageSize)
 			if err != nil {
 				log.Info("dropping compressed message", "err", err, "delayedMsg", r.delayedMessagesRead)
+				r.reportMalformed.report(MalformedBatchBadCompressedMessage, err)
 				return nil, nil
 			}
 			segment = decompressed
@@ -382,6 +587,7 @@ func (r *inboxMultiplexer) getNextMsg() (*arbostypes.MessageWithMetadata, error)
 		}
 	} else {
 		log.Error("bad sequencer message segment kind", "sequence", r.cachedSegmentNum, "segmentNum", segmentNum, "kind", kind)
+		r.reportMalformed.report(MalformedBatchInvalidSegmentKind, fmt.Errorf("segment %d has kind %d", segmentNum, kind))
 		return nil, nil
 	}
 	return msg, nil
@@ -6,11 +6,15 @@ package arbstate
 import (
 	"bytes"
 	"context"
+	"encoding/binary"
 	"errors"
+	"reflect"
 	"testing"
 
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/rlp"
 
+	"github.com/offchainlabs/nitro/arbcompress"
 	"github.com/offchainlabs/nitro/arbos/arbostypes"
 	"github.com/offchainlabs/nitro/arbstate/daprovider"
 )
@@ -71,8 +75,145 @@ func FuzzInboxMultiplexer(f *testing.F) {
 		}
 		multiplexer := NewInboxMultiplexer(backend, 0, nil, daprovider.KeysetValidate)
 		_, err := multiplexer.Pop(context.TODO())
-		if err != nil {
+		if err != nil && !errors.Is(err, ErrUnknownCompressionDictionary) {
 			panic(err)
 		}
 	})
 }
+
+type malformedBatchReport struct {
+	batchNum uint64
+	class    MalformedBatchClass
+}
+
+// reportingMultiplexerBackend records the malformed batches the multiplexer reports
+type reportingMultiplexerBackend struct {
+	multiplexerBackend
+	reports []malformedBatchReport
+}
+
+func (b *reportingMultiplexerBackend) ReportMalformedBatch(batchNum uint64, class MalformedBatchClass, data []byte, err error) {
+	if err == nil {
+		panic("reported malformed batch without an error")
+	}
+	b.reports = append(b.reports, malformedBatchReport{batchNum, class})
+}
+
+// testBatch builds a batch with no delayed messages whose payload is a brotli compressed list of segments
+func testBatch(t testing.TB, segments ...[]byte) []byte {
+	var encoded []byte
+	for _, segment := range segments {
+		bytes, err := rlp.EncodeToBytes(segment)
+		if err != nil {
+			t.Fatal(err)
+		}
+		encoded = append(encoded, bytes...)
+	}
+	compressed, err := arbcompress.CompressWell(encoded)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return testBatchWithPayload(append([]byte{daprovider.BrotliMessageHeaderByte}, compressed...))
+}
+
+func testBatchWithPayload(payload []byte) []byte {
+	header := make([]byte, 40)
+	binary.BigEndian.PutUint64(header[8:16], ^uint64(0))  // max timestamp
+	binary.BigEndian.PutUint64(header[24:32], ^uint64(0)) // max L1 block
+	return append(header, payload...)
+}
+
+// readBatch pops every message of the first batch
+func readBatch(t testing.TB, backend InboxBackend) []*arbostypes.MessageWithMetadata {
+	multiplexer := NewInboxMultiplexer(backend, 0, nil, daprovider.KeysetValidate)
+	var messages []*arbostypes.MessageWithMetadata
+	for backend.GetSequencerInboxPosition() == 0 {
+		if len(messages) > MaxSegmentsPerSequencerMessage+1 {
+			t.Fatal("multiplexer didn't progress past the batch")
+		}
+		msg, err := multiplexer.Pop(context.Background())
+		if errors.Is(err, ErrUnknownCompressionDictionary) {
+			t.Skip("batch is compressed with a dictionary the backend doesn't have")
+		}
+		if err != nil {
+			t.Fatal("failed to read batch", err)
+		}
+		messages = append(messages, msg)
+	}
+	return messages
+}
+
+func TestMalformedBatchClassification(t *testing.T) {
+	l2Message := []byte{BatchSegmentKindL2Message, 3} // an empty batch of L2 messages
+	encodedL2Message, err := rlp.EncodeToBytes(l2Message)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name     string
+		batch    []byte
+		expected []MalformedBatchClass
+	}{
+		{"valid", testBatch(t, l2Message), nil},
+		{"valid uncompressed", testBatchWithPayload(append([]byte{daprovider.UncompressedMessageHeaderByte}, encodedL2Message...)), nil},
+		{"bad compression", testBatchWithPayload([]byte{daprovider.BrotliMessageHeaderByte, 0xff, 0xff, 0xff}), []MalformedBatchClass{MalformedBatchBadCompression}},
+		{"empty payload", testBatchWithPayload(nil), []MalformedBatchClass{MalformedBatchEmptyPayload}},
+		{"unknown format", testBatchWithPayload([]byte{0x01, 0x02}), []MalformedBatchClass{MalformedBatchUnknownFormat}},
+		{"invalid segment kind", testBatch(t, []byte{0x7f}), []MalformedBatchClass{MalformedBatchInvalidSegmentKind}},
+		{"bad compressed message", testBatch(t, []byte{BatchSegmentKindL2MessageBrotli, 0xff, 0xff}), []MalformedBatchClass{MalformedBatchBadCompressedMessage}},
+		{"bad advance", testBatch(t, []byte{BatchSegmentKindAdvanceTimestamp, 0xff}, l2Message), []MalformedBatchClass{MalformedBatchBadAdvance}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			backend := &reportingMultiplexerBackend{multiplexerBackend: multiplexerBackend{batch: test.batch}}
+			messages := readBatch(t, backend)
+			var classes []MalformedBatchClass
+			for _, report := range backend.reports {
+				if report.batchNum != 0 {
+					t.Error("reported the wrong batch", report.batchNum)
+				}
+				classes = append(classes, report.class)
+			}
+			if !reflect.DeepEqual(classes, test.expected) {
+				t.Fatal("expected reports", test.expected, "got", classes)
+			}
+			// reporting must not change what's derived
+			if !reflect.DeepEqual(messages, readBatch(t, &multiplexerBackend{batch: test.batch})) {
+				t.Fatal("reporting changed the derived messages")
+			}
+		})
+	}
+}
+
+func FuzzInboxMultiplexerMalformedBatch(f *testing.F) {
+	l2Message := []byte{BatchSegmentKindL2Message, 3} // an empty batch of L2 messages
+	f.Add(testBatch(f, l2Message, []byte{BatchSegmentKindAdvanceTimestamp, 0x01}, l2Message), uint16(0), byte(0), uint16(0))
+	f.Add(testBatch(f, l2Message, []byte{BatchSegmentKindDelayedMessages}), uint16(45), byte(0xff), uint16(0))
+	f.Add(testBatch(f, l2Message, l2Message, l2Message), uint16(0), byte(0), uint16(3))
+	f.Fuzz(func(t *testing.T, batch []byte, corruptAt uint16, corruptWith byte, truncateBy uint16) {
+		if len(batch) < 40 {
+			return
+		}
+		// keep the L1 header intact, as the sequencer inbox contract writes it, but mangle the payload
+		batch = bytes.Clone(batch)
+		binary.BigEndian.PutUint64(batch[32:40], 0)
+		if int(corruptAt) >= 40 && int(corruptAt) < len(batch) {
+			batch[corruptAt] ^= corruptWith
+		}
+		if int(truncateBy) <= len(batch)-40 {
+			batch = batch[:len(batch)-int(truncateBy)]
+		}
+
+		reporting := &reportingMultiplexerBackend{multiplexerBackend: multiplexerBackend{batch: batch}}
+		first := readBatch(t, reporting)
+		second := readBatch(t, &multiplexerBackend{batch: batch})
+		if !reflect.DeepEqual(first, second) {
+			t.Fatal("derivation of a malformed batch isn't deterministic")
+		}
+		for _, report := range reporting.reports {
+			if _, ok := malformedBatchClassNames[report.class]; !ok {
+				t.Fatal("reported unknown class", report.class)
+			}
+		}
+	})
+}
@@ -120,6 +120,13 @@ type TransactionStreamerInterface interface {
 	AddBroadcastMessages(feedMessages []*m.BroadcastFeedMessage) error
 }
 
+// InboxCatchupRequester is optionally implemented by a TransactionStreamerInterface
+// to be told when the feed no longer has the messages it requested, which then
+// need to be read from L1.
+type InboxCatchupRequester interface {
+	RequestInboxCatchup(firstAvailable arbutil.MessageIndex)
+}
+
 type BroadcastClient struct {
 	stopwaiter.StopWaiter
 
@@ -434,10 +441,15 @@ func (bc *BroadcastClient) startBackgroundReader(earlyFrameData io.Reader) {
 					log.Debug("received batch item", "count", len(res.Messages), "first seq", res.Messages[0].SequenceNumber)
 				} else if res.ConfirmedSequenceNumberMessage != nil {
 					log.Debug("confirmed sequence number", "seq", res.ConfirmedSequenceNumberMessage.SequenceNumber)
+				} else if res.CatchupNoticeMessage != nil {
+					log.Debug("received catchup notice", "firstAvailable", res.CatchupNoticeMessage.FirstAvailableSequenceNumber)
 				} else {
 					log.Debug("received broadcast with no messages populated", "length", len(msg))
 				}
 				if res.Version == 1 {
+					if res.CatchupNoticeMessage != nil {
+						bc.handleCatchupNotice(res.CatchupNoticeMessage)
+					}
 					if len(res.Messages) > 0 {
 						for _, message := range res.Messages {
 							if message == nil {
@@ -467,6 +479,18 @@ func (bc *BroadcastClient) startBackgroundReader(earlyFrameData io.Reader) {
 	})
 }
 
+func (bc *BroadcastClient) handleCatchupNotice(notice *m.CatchupNoticeMessage) {
+	log.Warn(
+		"feed backlog no longer has requested messages, catching up from L1",
+		"url", bc.websocketUrl,
+		"requestedSeqNum", notice.RequestedSequenceNumber,
+		"firstAvailableSeqNum", notice.FirstAvailableSequenceNumber,
+	)
+	if requester, ok := bc.txStreamer.(InboxCatchupRequester); ok {
+		requester.RequestInboxCatchup(notice.FirstAvailableSequenceNumber)
+	}
+}
+
 func (bc *BroadcastClient) GetRetryCount() int64 {
 	return bc.retryCount.Load()
 }
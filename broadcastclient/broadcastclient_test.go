@@ -845,3 +845,109 @@ func Require(t *testing.T, err error, printables ...interface{}) {
 	t.Helper()
 	testhelpers.RequireImpl(t, err, printables...)
 }
+
+type catchupTransactionStreamer struct {
+	*dummyTransactionStreamer
+	catchupRequests chan arbutil.MessageIndex
+}
+
+func (ts *catchupTransactionStreamer) RequestInboxCatchup(firstAvailable arbutil.MessageIndex) {
+	ts.catchupRequests <- firstAvailable
+}
+
+func TestBroadcasterSendsCatchupNoticeForPrunedMessages(t *testing.T) {
+	t.Parallel()
+	testBroadcasterCatchupNotice(t, 5, true)
+}
+
+func TestBroadcasterSendsNoCatchupNoticeToFreshClient(t *testing.T) {
+	t.Parallel()
+	testBroadcasterCatchupNotice(t, 0, false)
+}
+
+func testBroadcasterCatchupNotice(t *testing.T, requestedSeqNum arbutil.MessageIndex, expectNotice bool) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	settings := wsbroadcastserver.DefaultTestBroadcasterConfig
+	settings.Backlog.CatchupNotice = true
+
+	privateKey, err := crypto.GenerateKey()
+	Require(t, err)
+	sequencerAddr := crypto.PubkeyToAddress(privateKey.PublicKey)
+	dataSigner := signature.DataSignerFromPrivateKey(privateKey)
+
+	feedErrChan := make(chan error, 10)
+	chainId := uint64(8745)
+	b := broadcaster.NewBroadcaster(func() *wsbroadcastserver.BroadcasterConfig { return &settings }, chainId, feedErrChan, dataSigner)
+
+	Require(t, b.Initialize())
+	Require(t, b.Start(ctx))
+	defer b.StopAndWait()
+
+	messageCount := 1000
+	firstAvailable := arbutil.MessageIndex(990)
+	for i := 0; i < messageCount; i++ {
+		// #nosec G115
+		Require(t, b.BroadcastSingle(arbostypes.EmptyTestMessageWithMetadata, arbutil.MessageIndex(i), nil))
+	}
+	b.Confirm(firstAvailable - 1)
+	for b.GetCachedMessageCount() != messageCount-int(firstAvailable) {
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(10 * time.Millisecond):
+		}
+	}
+
+	ts := &catchupTransactionStreamer{
+		dummyTransactionStreamer: NewDummyTransactionStreamer(chainId, nil),
+		catchupRequests:          make(chan arbutil.MessageIndex, 1),
+	}
+	broadcastClient, err := newTestBroadcastClient(
+		DefaultTestConfig,
+		b.ListenerAddr(),
+		chainId,
+		requestedSeqNum,
+		ts,
+		nil,
+		feedErrChan,
+		&sequencerAddr,
+		t,
+	)
+	Require(t, err)
+	broadcastClient.Start(ctx)
+	defer broadcastClient.StopAndWait()
+
+	timer := time.NewTimer(5 * time.Second)
+	defer timer.Stop()
+	if expectNotice {
+		select {
+		case err := <-feedErrChan:
+			t.Fatalf("Broadcaster error: %s\n", err.Error())
+		case seqNum := <-ts.catchupRequests:
+			if seqNum != firstAvailable {
+				t.Fatalf("catchup notice has first available sequence number %v, expected %v", seqNum, firstAvailable)
+			}
+		case receivedMsg := <-ts.messageReceiver:
+			t.Fatalf("client received message %v before catchup notice", receivedMsg.SequenceNumber)
+		case <-timer.C:
+			t.Fatal("client did not receive catchup notice")
+		}
+	}
+
+	// The backlog is sent, after the notice if there is one
+	for expected := firstAvailable; int(expected) < messageCount; expected++ {
+		select {
+		case err := <-feedErrChan:
+			t.Fatalf("Broadcaster error: %s\n", err.Error())
+		case seqNum := <-ts.catchupRequests:
+			t.Fatalf("client received unexpected catchup notice with first available sequence number %v", seqNum)
+		case receivedMsg := <-ts.messageReceiver:
+			if receivedMsg.SequenceNumber != expected {
+				t.Fatalf("received message %v, expected %v", receivedMsg.SequenceNumber, expected)
+			}
+		case <-timer.C:
+			t.Fatal("client did not receive backlog")
+		}
+	}
+}
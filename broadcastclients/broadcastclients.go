@@ -39,6 +39,12 @@ func (r *Router) AddBroadcastMessages(feedMessages []*m.BroadcastFeedMessage) er
 	return nil
 }
 
+func (r *Router) RequestInboxCatchup(firstAvailable arbutil.MessageIndex) {
+	if requester, ok := r.forwardTxStreamer.(broadcastclient.InboxCatchupRequester); ok {
+		requester.RequestInboxCatchup(firstAvailable)
+	}
+}
+
 type BroadcastClients struct {
 	primaryClients   []*broadcastclient.BroadcastClient
 	secondaryClients []*broadcastclient.BroadcastClient
@@ -459,3 +459,34 @@ func TestBacklogRaceCondition(t *testing.T) {
 	// kept, which is why the backlog starts at 46.
 	validateBacklog(t, b, 8, 48, 55, newIndexes[1:])
 }
+
+func TestStartupLimits(t *testing.T) {
+	c := DefaultTestConfig
+	if start := c.StartupStart(10, 1000); start != 10 {
+		t.Errorf("unlimited startup start (%d) does not equal 10", start)
+	}
+	if minTimestamp := c.StartupMinTimestamp(time.Now()); minTimestamp != 0 {
+		t.Errorf("unlimited startup min timestamp (%d) does not equal 0", minTimestamp)
+	}
+
+	c.StartupMaxMessages = 100
+	if start := c.StartupStart(10, 1000); start != 900 {
+		t.Errorf("startup start (%d) does not equal 900", start)
+	}
+	if start := c.StartupStart(950, 1000); start != 950 {
+		t.Errorf("startup start with fewer messages than the limit (%d) does not equal 950", start)
+	}
+	if start := c.StartupStart(10, 10); start != 10 {
+		t.Errorf("startup start with no messages (%d) does not equal 10", start)
+	}
+
+	c.StartupMaxAge = time.Minute
+	now := time.Unix(1_000_000, 0)
+	if minTimestamp := c.StartupMinTimestamp(now); minTimestamp != 1_000_000-60 {
+		t.Errorf("startup min timestamp (%d) does not equal %d", minTimestamp, 1_000_000-60)
+	}
+	c.StartupMaxAge = time.Hour * 24 * 365 * 100
+	if minTimestamp := c.StartupMinTimestamp(now); minTimestamp != 0 {
+		t.Errorf("startup min timestamp before the epoch (%d) does not equal 0", minTimestamp)
+	}
+}
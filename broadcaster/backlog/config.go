@@ -1,24 +1,60 @@
 package backlog
 
 import (
+	"time"
+
 	flag "github.com/spf13/pflag"
 )
 
 type ConfigFetcher func() *Config
 
 type Config struct {
-	SegmentLimit int `koanf:"segment-limit" reload:"hot"`
+	SegmentLimit       int           `koanf:"segment-limit" reload:"hot"`
+	StartupMaxMessages uint64        `koanf:"startup-max-messages"`
+	StartupMaxAge      time.Duration `koanf:"startup-max-age"`
+	CatchupNotice      bool          `koanf:"catchup-notice" reload:"hot"`
 }
 
 func AddOptions(prefix string, f *flag.FlagSet) {
 	f.Int(prefix+".segment-limit", DefaultConfig.SegmentLimit, "the maximum number of messages each segment within the backlog can contain")
+	f.Uint64(prefix+".startup-max-messages", DefaultConfig.StartupMaxMessages, "the maximum number of the most recent messages loaded into the backlog on startup (0 = no limit)")
+	f.Duration(prefix+".startup-max-age", DefaultConfig.StartupMaxAge, "messages older than this aren't loaded into the backlog on startup (0 = no limit)")
+	f.Bool(prefix+".catchup-notice", DefaultConfig.CatchupNotice, "send clients requesting messages older than the backlog a notice to catch up from L1 instead of the whole backlog")
 }
 
 var (
 	DefaultConfig = Config{
-		SegmentLimit: 240,
+		SegmentLimit:       240,
+		StartupMaxMessages: 0,
+		StartupMaxAge:      0,
+		CatchupNotice:      false,
 	}
 	DefaultTestConfig = Config{
-		SegmentLimit: 3,
+		SegmentLimit:       3,
+		StartupMaxMessages: 0,
+		StartupMaxAge:      0,
+		CatchupNotice:      false,
 	}
 )
+
+// StartupStart returns the first message to load into the backlog on startup,
+// given the range [start, end) of messages available to be loaded.
+func (c *Config) StartupStart(start, end uint64) uint64 {
+	if c.StartupMaxMessages > 0 && end > start && end-start > c.StartupMaxMessages {
+		return end - c.StartupMaxMessages
+	}
+	return start
+}
+
+// StartupMinTimestamp returns the minimum message timestamp loaded into the
+// backlog on startup, or zero if messages aren't limited by age.
+func (c *Config) StartupMinTimestamp(now time.Time) uint64 {
+	if c.StartupMaxAge <= 0 {
+		return 0
+	}
+	minTime := now.Add(-c.StartupMaxAge).Unix()
+	if minTime < 0 {
+		return 0
+	}
+	return uint64(minTime)
+}
@@ -23,6 +23,7 @@ import (
 )
 
 type Broadcaster struct {
+	config     wsbroadcastserver.BroadcasterConfigFetcher
 	server     *wsbroadcastserver.WSBroadcastServer
 	backlog    backlog.Backlog
 	chainId    uint64
@@ -32,6 +33,7 @@ type Broadcaster struct {
 func NewBroadcaster(config wsbroadcastserver.BroadcasterConfigFetcher, chainId uint64, feedErrChan chan error, dataSigner signature.DataSignerFunc) *Broadcaster {
 	bklg := backlog.NewBacklog(func() *backlog.Config { return &config().Backlog })
 	return &Broadcaster{
+		config:     config,
 		server:     wsbroadcastserver.NewWSBroadcastServer(config, bklg, chainId, feedErrChan),
 		backlog:    bklg,
 		chainId:    chainId,
@@ -150,6 +152,10 @@ func (b *Broadcaster) GetCachedMessageCount() int {
 	return int(b.backlog.Count())
 }
 
+func (b *Broadcaster) BacklogConfig() *backlog.Config {
+	return &b.config().Backlog
+}
+
 func (b *Broadcaster) Initialize() error {
 	return b.server.Initialize()
 }
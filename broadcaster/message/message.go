@@ -30,6 +30,7 @@ type BroadcastMessage struct {
 	// TODO better name than messages since there are different types of messages
 	Messages                       []*BroadcastFeedMessage         `json:"messages,omitempty"`
 	ConfirmedSequenceNumberMessage *ConfirmedSequenceNumberMessage `json:"confirmedSequenceNumberMessage,omitempty"`
+	CatchupNoticeMessage           *CatchupNoticeMessage           `json:"catchupNoticeMessage,omitempty"`
 }
 
 type BroadcastFeedMessage struct {
@@ -57,3 +58,10 @@ func (m *BroadcastFeedMessage) Hash(chainId uint64) (common.Hash, error) {
 type ConfirmedSequenceNumberMessage struct {
 	SequenceNumber arbutil.MessageIndex `json:"sequenceNumber"`
 }
+
+// CatchupNoticeMessage tells a client that the messages it requested are no
+// longer in the feed backlog and must be read from L1 instead.
+type CatchupNoticeMessage struct {
+	RequestedSequenceNumber      arbutil.MessageIndex `json:"requestedSequenceNumber"`
+	FirstAvailableSequenceNumber arbutil.MessageIndex `json:"firstAvailableSequenceNumber"`
+}
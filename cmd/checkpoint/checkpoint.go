@@ -0,0 +1,221 @@
+// Copyright 2024, Offchain Labs, Inc.
+// For license information, see https://github.com/nitro/blob/master/LICENSE
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	flag "github.com/spf13/pflag"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
+
+	"github.com/offchainlabs/nitro/cmd/util/confighelpers"
+	"github.com/offchainlabs/nitro/execution/gethexec"
+	"github.com/offchainlabs/nitro/staker"
+)
+
+func main() {
+	args := os.Args
+	if len(args) < 2 {
+		panic("Usage: checkpoint [fetch|verify] ...")
+	}
+
+	var err error
+	switch strings.ToLower(args[1]) {
+	case "fetch":
+		err = fetch(args[2:])
+	case "verify":
+		err = verify(args[2:])
+	default:
+		panic(fmt.Sprintf("Unknown tool '%s' specified, valid tools are 'fetch', 'verify'", args[1]))
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+// checkpoint fetch
+
+type FetchConfig struct {
+	URL     string        `koanf:"url"`
+	Block   uint64        `koanf:"block"`
+	Timeout time.Duration `koanf:"timeout"`
+}
+
+func parseFetchConfig(args []string) (*FetchConfig, error) {
+	f := flag.NewFlagSet("checkpoint fetch", flag.ContinueOnError)
+	f.String("url", "", "RPC URL of the node publishing checkpoints")
+	f.Uint64("block", 0, "block number of the checkpoint to fetch (0 = latest)")
+	f.Duration("timeout", time.Minute, "timeout for the request")
+
+	k, err := confighelpers.BeginCommonParse(f, args)
+	if err != nil {
+		return nil, err
+	}
+	var config FetchConfig
+	if err := confighelpers.EndCommonParse(k, &config); err != nil {
+		return nil, err
+	}
+	return &config, nil
+}
+
+// fetch prints a checkpoint as JSON, for storing and later verifying.
+func fetch(args []string) error {
+	config, err := parseFetchConfig(args)
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), config.Timeout)
+	defer cancel()
+	client, err := rpc.DialContext(ctx, config.URL)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+	var checkpoint gethexec.Checkpoint
+	if config.Block == 0 {
+		err = client.CallContext(ctx, &checkpoint, "arb_latestCheckpoint")
+	} else {
+		err = client.CallContext(ctx, &checkpoint, "arb_checkpoint", hexutil.Uint64(config.Block))
+	}
+	if err != nil {
+		return err
+	}
+	encoded, err := json.MarshalIndent(checkpoint, "", "  ")
+	if err != nil {
+		return err
+	}
+	fmt.Println(string(encoded))
+	return nil
+}
+
+// checkpoint verify
+
+type VerifyConfig struct {
+	Checkpoint      string        `koanf:"checkpoint"`
+	Signer          string        `koanf:"signer"`
+	TrustedURL      string        `koanf:"trusted-url"`
+	GenesisBlockNum uint64        `koanf:"genesis-block-num"`
+	ParentChainURL  string        `koanf:"parent-chain-url"`
+	RollupAddress   string        `koanf:"rollup-address"`
+	Timeout         time.Duration `koanf:"timeout"`
+}
+
+func parseVerifyConfig(args []string) (*VerifyConfig, error) {
+	f := flag.NewFlagSet("checkpoint verify", flag.ContinueOnError)
+	f.String("checkpoint", "", "file containing the checkpoint JSON to verify")
+	f.String("signer", "", "address the checkpoint must be signed by")
+	f.String("trusted-url", "", "RPC URL of a trusted node of the chain to check the checkpoint against")
+	f.Uint64("genesis-block-num", 0, "genesis block number of the chain, used to check the checkpoint's message count")
+	f.String("parent-chain-url", "", "RPC URL of the parent chain to check the checkpoint against its latest confirmed assertion")
+	f.String("rollup-address", "", "address of the rollup contract on the parent chain")
+	f.Duration("timeout", time.Minute, "timeout for the verification")
+
+	k, err := confighelpers.BeginCommonParse(f, args)
+	if err != nil {
+		return nil, err
+	}
+	var config VerifyConfig
+	if err := confighelpers.EndCommonParse(k, &config); err != nil {
+		return nil, err
+	}
+	if config.Checkpoint == "" || !common.IsHexAddress(config.Signer) {
+		return nil, errors.New("--checkpoint and a valid --signer address are required")
+	}
+	if config.TrustedURL == "" && config.ParentChainURL == "" {
+		return nil, errors.New("either --trusted-url or --parent-chain-url is required")
+	}
+	if config.ParentChainURL != "" && !common.IsHexAddress(config.RollupAddress) {
+		return nil, errors.New("--parent-chain-url requires a valid --rollup-address")
+	}
+	return &config, nil
+}
+
+func verify(args []string) error {
+	config, err := parseVerifyConfig(args)
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), config.Timeout)
+	defer cancel()
+
+	data, err := os.ReadFile(config.Checkpoint)
+	if err != nil {
+		return err
+	}
+	var checkpoint gethexec.Checkpoint
+	if err := json.Unmarshal(data, &checkpoint); err != nil {
+		return fmt.Errorf("failed to parse checkpoint: %w", err)
+	}
+	signer := common.HexToAddress(config.Signer)
+
+	if config.TrustedURL != "" {
+		trusted, err := ethclient.DialContext(ctx, config.TrustedURL)
+		if err != nil {
+			return err
+		}
+		defer trusted.Close()
+		if err := gethexec.VerifyCheckpoint(ctx, &checkpoint, signer, config.GenesisBlockNum, trusted); err != nil {
+			return err
+		}
+		fmt.Printf("checkpoint of block %v matches trusted RPC\n", checkpoint.BlockNumber)
+	}
+
+	if config.ParentChainURL != "" {
+		parentChain, err := ethclient.DialContext(ctx, config.ParentChainURL)
+		if err != nil {
+			return err
+		}
+		defer parentChain.Close()
+		if err := verifyAgainstConfirmedAssertion(ctx, &checkpoint, signer, parentChain, common.HexToAddress(config.RollupAddress)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// verifyAgainstConfirmedAssertion checks the checkpoint against the rollup's latest confirmed assertion,
+// if that assertion is of the checkpoint's block.
+func verifyAgainstConfirmedAssertion(ctx context.Context, checkpoint *gethexec.Checkpoint, signer common.Address, parentChain *ethclient.Client, rollupAddress common.Address) error {
+	recovered, err := checkpoint.Signer()
+	if err != nil {
+		return err
+	}
+	if recovered != signer {
+		return fmt.Errorf("%w: signed by %v, expected %v", gethexec.ErrCheckpointSigner, recovered, signer)
+	}
+	rollup, err := staker.NewRollupWatcher(rollupAddress, parentChain, bind.CallOpts{Context: ctx})
+	if err != nil {
+		return err
+	}
+	nodeNum, err := rollup.LatestConfirmed(&bind.CallOpts{Context: ctx})
+	if err != nil {
+		return fmt.Errorf("failed to read latest confirmed assertion: %w", err)
+	}
+	node, err := rollup.LookupNode(ctx, nodeNum)
+	if err != nil {
+		return fmt.Errorf("failed to look up confirmed assertion %v: %w", nodeNum, err)
+	}
+	state := node.AfterState().GlobalState
+	if state.BlockHash != checkpoint.BlockHash {
+		fmt.Printf("latest confirmed assertion %v is of block %v, not the checkpoint's block, so the checkpoint can't be checked against it\n", nodeNum, state.BlockHash)
+		return nil
+	}
+	if state.SendRoot != checkpoint.SendRoot {
+		return fmt.Errorf("%w: confirmed assertion %v has send root %v, checkpoint has %v", gethexec.ErrCheckpointMismatch, nodeNum, state.SendRoot, checkpoint.SendRoot)
+	}
+	fmt.Printf("checkpoint of block %v matches confirmed assertion %v\n", checkpoint.BlockNumber, nodeNum)
+	return nil
+}
@@ -15,6 +15,8 @@ import (
 	flag "github.com/spf13/pflag"
 
 	"github.com/ethereum/go-ethereum/ethdb/pebble"
+
+	"github.com/offchainlabs/nitro/util/dbutil"
 )
 
 type PersistentConfig struct {
@@ -229,7 +231,8 @@ func (c *PebbleExperimentalConfig) Validate() error {
 func (c *PebbleConfig) ExtraOptions(namespace string) *pebble.ExtraOptions {
 	var maxConcurrentCompactions func() int
 	if c.MaxConcurrentCompactions > 0 {
-		maxConcurrentCompactions = func() int { return c.MaxConcurrentCompactions }
+		compactionController := dbutil.CompactionControllerFor(namespace)
+		maxConcurrentCompactions = func() int { return compactionController.MaxConcurrentCompactions(c.MaxConcurrentCompactions) }
 	}
 	var walMinSyncInterval func() time.Duration
 	if c.Experimental.WALMinSyncInterval > 0 {
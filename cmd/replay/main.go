@@ -104,6 +104,17 @@ func (i WavmInbox) ReadDelayedInbox(seqNum uint64) (*arbostypes.L1IncomingMessag
 	})
 }
 
+// GetCompressionDictionary resolves the dictionary as a preimage, which the validator records when it sees a batch use it.
+func (i WavmInbox) GetCompressionDictionary(hash common.Hash) ([]byte, error) {
+	log.Info("GetCompressionDictionary", "hash", hash)
+	return wavmio.ResolveTypedPreimage(arbutil.Keccak256PreimageType, hash)
+}
+
+// AddCompressionDictionary does nothing, as dictionaries are looked up as preimages rather than remembered.
+func (i WavmInbox) AddCompressionDictionary(dictionary []byte) error {
+	return nil
+}
+
 type PreimageDASReader struct {
 }
 
@@ -22,21 +22,110 @@ import (
 
 	"github.com/offchainlabs/nitro/arbos/arbosState"
 	"github.com/offchainlabs/nitro/arbos/retryables"
+	"github.com/offchainlabs/nitro/cmd/util/confighelpers"
 	"github.com/offchainlabs/nitro/util/arbmath"
 )
 
 type ArbAPI struct {
 	txPublisher TransactionPublisher
+	blockchain  *core.BlockChain
+	execEngine  *ExecutionEngine
 }
 
-func NewArbAPI(publisher TransactionPublisher) *ArbAPI {
-	return &ArbAPI{publisher}
+func NewArbAPI(publisher TransactionPublisher, blockchain *core.BlockChain, execEngine *ExecutionEngine) *ArbAPI {
+	return &ArbAPI{publisher, blockchain, execEngine}
 }
 
 func (a *ArbAPI) CheckPublisherHealth(ctx context.Context) error {
+	if a.execEngine.safeMode.Active() {
+		return ErrSafeMode
+	}
 	return a.txPublisher.CheckHealth(ctx)
 }
 
+type NodeHealth struct {
+	Status   string          `json:"status"` // "ok", or "degraded" while in safe mode
+	SafeMode *SafeModeStatus `json:"safeMode,omitempty"`
+}
+
+// NodeHealth reports whether the node is degraded, and if so why.
+func (a *ArbAPI) NodeHealth(ctx context.Context) NodeHealth {
+	status := a.execEngine.SafeModeStatus()
+	if !status.Active {
+		return NodeHealth{Status: "ok"}
+	}
+	return NodeHealth{Status: "degraded", SafeMode: &status}
+}
+
+type GasOracle struct {
+	L1BaseFee           *big.Int `json:"l1BaseFee"`           // wei charged per byte of L1 calldata
+	L1BaseFeeEstimate   *big.Int `json:"l1BaseFeeEstimate"`   // ArbOS's estimate of the L1 basefee
+	L1BlockNumber       uint64   `json:"l1BlockNumber"`       // L1 block number as of the current L2 head
+	LastUpdateTimestamp uint64   `json:"lastUpdateTimestamp"` // time of the last L1 pricing update from a batch posting report
+	EstimateInertia     uint64   `json:"estimateInertia"`
+}
+
+// GetGasOracle returns the state of the L1 gas oracle as of the current head.
+func (a *ArbAPI) GetGasOracle(ctx context.Context) (GasOracle, error) {
+	header := a.blockchain.CurrentBlock()
+	if header == nil {
+		return GasOracle{}, errors.New("no current block")
+	}
+	state, _, err := stateAndHeader(a.blockchain, header.Number.Uint64())
+	if err != nil {
+		return GasOracle{}, err
+	}
+	l1Pricing := state.L1PricingState()
+
+	pricePerUnit, err := l1Pricing.PricePerUnit()
+	if err != nil {
+		return GasOracle{}, err
+	}
+	lastUpdateTime, err := l1Pricing.LastUpdateTime()
+	if err != nil {
+		return GasOracle{}, err
+	}
+	inertia, err := l1Pricing.Inertia()
+	if err != nil {
+		return GasOracle{}, err
+	}
+	return GasOracle{
+		L1BaseFee:           arbmath.BigMulByUint(pricePerUnit, params.TxDataNonZeroGasEIP2028),
+		L1BaseFeeEstimate:   pricePerUnit,
+		L1BlockNumber:       types.DeserializeHeaderExtraInformation(header).L1BlockNumber,
+		LastUpdateTimestamp: lastUpdateTime,
+		EstimateInertia:     inertia,
+	}, nil
+}
+
+type NodeVersion struct {
+	Version        string `json:"version"`
+	ArbOSVersion   uint64 `json:"arbOSVersion"`             // as of the current head
+	ChainNamespace string `json:"chainNamespace,omitempty"` // set by the chain owner to identify the chain
+}
+
+// GetNodeVersion returns the version of the node software, and the ArbOS version and namespace of the chain.
+func (a *ArbAPI) GetNodeVersion(ctx context.Context) (NodeVersion, error) {
+	version, _, _ := confighelpers.GetVersion()
+	header := a.blockchain.CurrentBlock()
+	if header == nil {
+		return NodeVersion{}, errors.New("no current block")
+	}
+	state, _, err := stateAndHeader(a.blockchain, header.Number.Uint64())
+	if err != nil {
+		return NodeVersion{}, err
+	}
+	namespace, err := state.ChainNamespace()
+	if err != nil {
+		return NodeVersion{}, err
+	}
+	return NodeVersion{
+		Version:        version,
+		ArbOSVersion:   state.ArbOSVersion(),
+		ChainNamespace: namespace,
+	}, nil
+}
+
 type ArbDebugAPI struct {
 	blockchain        *core.BlockChain
 	blockRangeBound   uint64
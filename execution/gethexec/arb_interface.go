@@ -38,6 +38,9 @@ func (a *ArbInterface) Initialize(node *ExecutionNode) {
 }
 
 func (a *ArbInterface) PublishTransaction(ctx context.Context, tx *types.Transaction, options *arbitrum_types.ConditionalOptions) error {
+	if a.node != nil && a.node.ExecEngine.safeMode.Active() {
+		return ErrSafeMode
+	}
 	return a.txPublisher.PublishTransaction(ctx, tx, options)
 }
 
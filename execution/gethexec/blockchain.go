@@ -135,7 +135,10 @@ func WriteOrTestGenblock(chainDb ethdb.Database, cacheConfig *core.CacheConfig,
 		return err
 	}
 	storedGenHash := rawdb.ReadCanonicalHash(chainDb, blockNumber)
-	timestamp := uint64(0)
+	timestamp, err := initData.GetGenesisTimestamp()
+	if err != nil {
+		return err
+	}
 	if blockNumber > 0 {
 		prevHash = rawdb.ReadCanonicalHash(chainDb, blockNumber-1)
 		if prevHash == EmptyHash {
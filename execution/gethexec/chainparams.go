@@ -0,0 +1,131 @@
+// Copyright 2024, Offchain Labs, Inc.
+// For license information, see https://github.com/nitro/blob/master/LICENSE
+
+package gethexec
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/event"
+	"github.com/ethereum/go-ethereum/log"
+
+	"github.com/offchainlabs/nitro/arbos"
+	"github.com/offchainlabs/nitro/arbos/arbosState"
+	"github.com/offchainlabs/nitro/execution"
+)
+
+// the non-indexed part of ArbOwner's OwnerActs event
+var ownerActsData abi.Arguments
+
+func init() {
+	bytesType, err := abi.NewType("bytes", "", nil)
+	if err != nil {
+		panic(err)
+	}
+	ownerActsData = abi.Arguments{{Name: "data", Type: bytesType}}
+}
+
+// chainParameterChanges finds the ArbOS parameter changes made by chain owners in a block.
+func (s *ExecutionEngine) chainParameterChanges(block *types.Block, receipts types.Receipts) []execution.ChainParameterChange {
+	var changes []execution.ChainParameterChange
+	for _, receipt := range receipts {
+		for _, txLog := range receipt.Logs {
+			if txLog.Address != types.ArbOwnerAddress || len(txLog.Topics) != 3 || txLog.Topics[0] != arbos.OwnerActsEventID {
+				continue
+			}
+			unpacked, err := ownerActsData.Unpack(txLog.Data)
+			if err != nil {
+				log.Warn("failed to decode OwnerActs event", "block", block.NumberU64(), "err", err)
+				continue
+			}
+			data, _ := unpacked[0].([]byte)
+			change := execution.ChainParameterChange{
+				Owner: common.BytesToAddress(txLog.Topics[2][:]),
+				Data:  data,
+			}
+			copy(change.Method[:], txLog.Topics[1][:4])
+			changes = append(changes, change)
+		}
+	}
+	if len(changes) == 0 {
+		return nil
+	}
+	msgIdx, err := s.BlockNumberToMessageIndex(block.NumberU64())
+	if err != nil {
+		log.Warn("failed to find the message of a block with chain parameter changes", "block", block.NumberU64(), "err", err)
+	}
+	for i := range changes {
+		changes[i].MessageIndex = msgIdx
+	}
+	return changes
+}
+
+// notifyChainParameterChanges tells subscribers about the ArbOS parameter changes made in a block
+// that was just appended to the chain.
+func (s *ExecutionEngine) notifyChainParameterChanges(block *types.Block, receipts types.Receipts) {
+	for _, change := range s.chainParameterChanges(block, receipts) {
+		log.Info("chain owner changed an ArbOS parameter", "message", change.MessageIndex, "method", common.Bytes2Hex(change.Method[:]), "owner", change.Owner)
+		s.chainParamsFeed.Send(change)
+	}
+}
+
+// SubscribeChainParameterChanges notifies ch of ArbOS parameter changes made by chain owners,
+// right after execution applies the block that made them.
+func (s *ExecutionEngine) SubscribeChainParameterChanges(ch chan<- execution.ChainParameterChange) event.Subscription {
+	return s.chainParamsFeed.Subscribe(ch)
+}
+
+// BrotliCompressionLevel returns the brotli compression level ArbOS currently uses for pricing.
+func (s *ExecutionEngine) BrotliCompressionLevel() (uint64, error) {
+	head := s.bc.CurrentBlock()
+	if head == nil {
+		return 0, errors.New("no head block")
+	}
+	statedb, err := s.bc.StateAt(head.Root)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get state of head block %v: %w", head.Number, err)
+	}
+	state, err := arbosState.OpenSystemArbosState(statedb, nil, true)
+	if err != nil {
+		return 0, err
+	}
+	return state.BrotliCompressionLevel()
+}
+
+// ActiveCompressionDictionary returns the id and hash of the dictionary ArbOS currently has batches compressed with.
+func (s *ExecutionEngine) ActiveCompressionDictionary() (uint8, common.Hash, error) {
+	head := s.bc.CurrentBlock()
+	if head == nil {
+		return 0, common.Hash{}, errors.New("no head block")
+	}
+	statedb, err := s.bc.StateAt(head.Root)
+	if err != nil {
+		return 0, common.Hash{}, fmt.Errorf("failed to get state of head block %v: %w", head.Number, err)
+	}
+	state, err := arbosState.OpenSystemArbosState(statedb, nil, true)
+	if err != nil {
+		return 0, common.Hash{}, err
+	}
+	return state.ActiveCompressionDictionary()
+}
+
+// L1TxBatchCompressionDisabled returns whether ArbOS currently has batches posted uncompressed.
+func (s *ExecutionEngine) L1TxBatchCompressionDisabled() (bool, error) {
+	head := s.bc.CurrentBlock()
+	if head == nil {
+		return false, errors.New("no head block")
+	}
+	statedb, err := s.bc.StateAt(head.Root)
+	if err != nil {
+		return false, fmt.Errorf("failed to get state of head block %v: %w", head.Number, err)
+	}
+	state, err := arbosState.OpenSystemArbosState(statedb, nil, true)
+	if err != nil {
+		return false, err
+	}
+	return state.L1TxBatchCompressionDisabled()
+}
@@ -0,0 +1,376 @@
+// Copyright 2024, Offchain Labs, Inc.
+// For license information, see https://github.com/nitro/blob/master/LICENSE
+
+package gethexec
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	flag "github.com/spf13/pflag"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/metrics"
+
+	"github.com/offchainlabs/nitro/arbutil"
+	"github.com/offchainlabs/nitro/util/dbutil"
+	"github.com/offchainlabs/nitro/util/signature"
+	"github.com/offchainlabs/nitro/util/stopwaiter"
+)
+
+var checkpointBlockGauge = metrics.NewRegisteredGauge("arb/checkpoint/block", nil)
+
+var (
+	checkpointPrefix    = []byte("_checkpoint")       // maps a big endian block number to the signed checkpoint of that block
+	latestCheckpointKey = []byte("_latestCheckpoint") // contains the block number of the latest checkpoint
+)
+
+var (
+	ErrCheckpointMismatch = errors.New("checkpoint doesn't match the chain")
+	ErrCheckpointSigner   = errors.New("checkpoint not signed by the expected signer")
+	ErrNoCheckpoint       = errors.New("no checkpoint")
+)
+
+type CheckpointerConfig struct {
+	Enable        bool          `koanf:"enable"`
+	Interval      uint64        `koanf:"interval" reload:"hot"`
+	CheckInterval time.Duration `koanf:"check-interval" reload:"hot"`
+	SigningKey    string        `koanf:"signing-key"`
+}
+
+var DefaultCheckpointerConfig = CheckpointerConfig{
+	Enable:        false,
+	Interval:      1000,
+	CheckInterval: time.Second,
+	SigningKey:    "",
+}
+
+func CheckpointerConfigAddOptions(prefix string, f *flag.FlagSet) {
+	f.Bool(prefix+".enable", DefaultCheckpointerConfig.Enable, "enable publishing signed chain state checkpoints")
+	f.Uint64(prefix+".interval", DefaultCheckpointerConfig.Interval, "number of blocks between checkpoints")
+	f.Duration(prefix+".check-interval", DefaultCheckpointerConfig.CheckInterval, "how often to check for new blocks to checkpoint")
+	f.String(prefix+".signing-key", DefaultCheckpointerConfig.SigningKey, "ecdsa private key to sign checkpoints with, treated as a hex string if prefixed with 0x otherwise treated as a file")
+}
+
+func (c *CheckpointerConfig) Validate() error {
+	if !c.Enable {
+		return nil
+	}
+	if c.Interval == 0 {
+		return errors.New("checkpoint interval has to be positive")
+	}
+	if c.CheckInterval <= 0 {
+		return errors.New("checkpoint check interval has to be positive")
+	}
+	if c.SigningKey == "" {
+		return errors.New("checkpointer requires a signing key")
+	}
+	return nil
+}
+
+type CheckpointerConfigFetcher func() *CheckpointerConfig
+
+// Checkpoint is a signed commitment to the chain state at a block, which downstream services can pin against.
+type Checkpoint struct {
+	ChainID      uint64               `json:"chainId"`
+	BlockNumber  uint64               `json:"blockNumber"`
+	BlockHash    common.Hash          `json:"blockHash"`
+	StateRoot    common.Hash          `json:"stateRoot"`
+	SendRoot     common.Hash          `json:"sendRoot"`
+	MessageCount arbutil.MessageIndex `json:"messageCount"`
+	Signature    hexutil.Bytes        `json:"signature"`
+}
+
+// NewCheckpoint makes an unsigned checkpoint of the block with the given header.
+func NewCheckpoint(chainID uint64, genesisBlockNum uint64, header *types.Header) *Checkpoint {
+	return &Checkpoint{
+		ChainID:      chainID,
+		BlockNumber:  header.Number.Uint64(),
+		BlockHash:    header.Hash(),
+		StateRoot:    header.Root,
+		SendRoot:     types.DeserializeHeaderExtraInformation(header).SendRoot,
+		MessageCount: arbutil.BlockNumberToMessageCount(header.Number.Uint64(), genesisBlockNum),
+	}
+}
+
+// SigningHash is the hash the checkpoint's signature signs, covering every field but the signature.
+func (c *Checkpoint) SigningHash() common.Hash {
+	return crypto.Keccak256Hash(
+		[]byte("Arbitrum Nitro checkpoint:"),
+		binary.BigEndian.AppendUint64(nil, c.ChainID),
+		binary.BigEndian.AppendUint64(nil, c.BlockNumber),
+		c.BlockHash.Bytes(),
+		c.StateRoot.Bytes(),
+		c.SendRoot.Bytes(),
+		binary.BigEndian.AppendUint64(nil, uint64(c.MessageCount)),
+	)
+}
+
+func (c *Checkpoint) Sign(signer signature.DataSignerFunc) error {
+	sig, err := signer(c.SigningHash().Bytes())
+	if err != nil {
+		return err
+	}
+	c.Signature = sig
+	return nil
+}
+
+// Signer recovers the address that signed the checkpoint.
+func (c *Checkpoint) Signer() (common.Address, error) {
+	if len(c.Signature) != 65 {
+		return common.Address{}, fmt.Errorf("%w: invalid signature length %v", ErrCheckpointSigner, len(c.Signature))
+	}
+	pubkey, err := crypto.SigToPub(c.SigningHash().Bytes(), c.Signature)
+	if err != nil {
+		return common.Address{}, fmt.Errorf("%w: %w", ErrCheckpointSigner, err)
+	}
+	return crypto.PubkeyToAddress(*pubkey), nil
+}
+
+// CheckpointHeaderReader is the part of a trusted RPC client needed to verify checkpoints.
+type CheckpointHeaderReader interface {
+	HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error)
+}
+
+// VerifyCheckpoint checks that the checkpoint was signed by signer, and that it matches
+// the block with the same number read from a trusted RPC.
+func VerifyCheckpoint(ctx context.Context, checkpoint *Checkpoint, signer common.Address, genesisBlockNum uint64, trusted CheckpointHeaderReader) error {
+	recovered, err := checkpoint.Signer()
+	if err != nil {
+		return err
+	}
+	if recovered != signer {
+		return fmt.Errorf("%w: signed by %v, expected %v", ErrCheckpointSigner, recovered, signer)
+	}
+	header, err := trusted.HeaderByNumber(ctx, new(big.Int).SetUint64(checkpoint.BlockNumber))
+	if err != nil {
+		return fmt.Errorf("failed to read checkpoint block %v from trusted RPC: %w", checkpoint.BlockNumber, err)
+	}
+	expected := NewCheckpoint(checkpoint.ChainID, genesisBlockNum, header)
+	if expected.BlockHash != checkpoint.BlockHash {
+		return fmt.Errorf("%w: block %v has hash %v, checkpoint has %v", ErrCheckpointMismatch, checkpoint.BlockNumber, expected.BlockHash, checkpoint.BlockHash)
+	}
+	if expected.StateRoot != checkpoint.StateRoot {
+		return fmt.Errorf("%w: block %v has state root %v, checkpoint has %v", ErrCheckpointMismatch, checkpoint.BlockNumber, expected.StateRoot, checkpoint.StateRoot)
+	}
+	if expected.SendRoot != checkpoint.SendRoot {
+		return fmt.Errorf("%w: block %v has send root %v, checkpoint has %v", ErrCheckpointMismatch, checkpoint.BlockNumber, expected.SendRoot, checkpoint.SendRoot)
+	}
+	if expected.MessageCount != checkpoint.MessageCount {
+		return fmt.Errorf("%w: block %v is after message count %v, checkpoint has %v", ErrCheckpointMismatch, checkpoint.BlockNumber, expected.MessageCount, checkpoint.MessageCount)
+	}
+	return nil
+}
+
+// CheckpointSignerFromKey parses a hex private key prefixed with 0x, or otherwise loads it from a file.
+func CheckpointSignerFromKey(key string) (signature.DataSignerFunc, common.Address, error) {
+	var privateKey *ecdsa.PrivateKey
+	var err error
+	if strings.HasPrefix(key, "0x") {
+		privateKey, err = crypto.HexToECDSA(key[2:])
+	} else {
+		privateKey, err = crypto.LoadECDSA(key)
+	}
+	if err != nil {
+		return nil, common.Address{}, fmt.Errorf("failed to load checkpoint signing key: %w", err)
+	}
+	return signature.DataSignerFromPrivateKey(privateKey), crypto.PubkeyToAddress(privateKey.PublicKey), nil
+}
+
+func checkpointKey(blockNumber uint64) []byte {
+	return binary.BigEndian.AppendUint64(append([]byte{}, checkpointPrefix...), blockNumber)
+}
+
+// Checkpointer periodically signs and stores checkpoints of every interval'th block.
+type Checkpointer struct {
+	stopwaiter.StopWaiter
+
+	config     CheckpointerConfigFetcher
+	bc         *core.BlockChain
+	db         ethdb.Database
+	signer     signature.DataSignerFunc
+	signerAddr common.Address
+}
+
+func NewCheckpointer(config CheckpointerConfigFetcher, bc *core.BlockChain, db ethdb.Database) (*Checkpointer, error) {
+	signer, signerAddr, err := CheckpointSignerFromKey(config().SigningKey)
+	if err != nil {
+		return nil, err
+	}
+	return &Checkpointer{
+		config:     config,
+		bc:         bc,
+		db:         db,
+		signer:     signer,
+		signerAddr: signerAddr,
+	}, nil
+}
+
+func (c *Checkpointer) Start(ctxIn context.Context) {
+	c.StopWaiter.Start(ctxIn, c)
+	c.CallIteratively(c.update)
+}
+
+// SignerAddress is the address checkpoints are signed by.
+func (c *Checkpointer) SignerAddress() common.Address {
+	return c.signerAddr
+}
+
+// Checkpoint returns the stored checkpoint of the given block.
+func (c *Checkpointer) Checkpoint(blockNumber uint64) (*Checkpoint, error) {
+	data, err := c.db.Get(checkpointKey(blockNumber))
+	if dbutil.IsErrNotFound(err) {
+		return nil, fmt.Errorf("%w at block %v", ErrNoCheckpoint, blockNumber)
+	}
+	if err != nil {
+		return nil, err
+	}
+	var checkpoint Checkpoint
+	if err := json.Unmarshal(data, &checkpoint); err != nil {
+		return nil, err
+	}
+	return &checkpoint, nil
+}
+
+// Latest returns the most recent checkpoint.
+func (c *Checkpointer) Latest() (*Checkpoint, error) {
+	data, err := c.db.Get(latestCheckpointKey)
+	if dbutil.IsErrNotFound(err) {
+		return nil, ErrNoCheckpoint
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(data) != 8 {
+		return nil, fmt.Errorf("invalid latest checkpoint block number %x", data)
+	}
+	return c.Checkpoint(binary.BigEndian.Uint64(data))
+}
+
+// Checkpoints returns up to limit stored checkpoints of blocks from first to last inclusive.
+func (c *Checkpointer) Checkpoints(first, last uint64, limit int) ([]*Checkpoint, error) {
+	var checkpoints []*Checkpoint
+	iter := c.db.NewIterator(checkpointPrefix, binary.BigEndian.AppendUint64(nil, first))
+	defer iter.Release()
+	for len(checkpoints) < limit && iter.Next() {
+		if binary.BigEndian.Uint64(iter.Key()[len(checkpointPrefix):]) > last {
+			break
+		}
+		var checkpoint Checkpoint
+		if err := json.Unmarshal(iter.Value(), &checkpoint); err != nil {
+			return nil, err
+		}
+		checkpoints = append(checkpoints, &checkpoint)
+	}
+	return checkpoints, iter.Error()
+}
+
+func (c *Checkpointer) store(checkpoint *Checkpoint) error {
+	data, err := json.Marshal(checkpoint)
+	if err != nil {
+		return err
+	}
+	batch := c.db.NewBatch()
+	if err := batch.Put(checkpointKey(checkpoint.BlockNumber), data); err != nil {
+		return err
+	}
+	if err := batch.Put(latestCheckpointKey, binary.BigEndian.AppendUint64(nil, checkpoint.BlockNumber)); err != nil {
+		return err
+	}
+	return batch.Write()
+}
+
+// nextBlock returns the next block to checkpoint, redoing the latest checkpoint if its block was reorged out.
+func (c *Checkpointer) nextBlock(interval uint64) (uint64, error) {
+	latest, err := c.Latest()
+	if errors.Is(err, ErrNoCheckpoint) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	if c.bc.GetCanonicalHash(latest.BlockNumber) != latest.BlockHash {
+		log.Warn("latest checkpoint's block was reorged, checkpointing it again", "block", latest.BlockNumber, "hash", latest.BlockHash)
+		return latest.BlockNumber, nil
+	}
+	return (latest.BlockNumber/interval + 1) * interval, nil
+}
+
+func (c *Checkpointer) update(ctx context.Context) time.Duration {
+	config := c.config()
+	next, err := c.nextBlock(config.Interval)
+	if err != nil {
+		log.Error("failed to find the next block to checkpoint", "err", err)
+		return config.CheckInterval
+	}
+	chainConfig := c.bc.Config()
+	genesisBlockNum := chainConfig.ArbitrumChainParams.GenesisBlockNum
+	if next < genesisBlockNum {
+		next = (genesisBlockNum + config.Interval - 1) / config.Interval * config.Interval
+	}
+	head := c.bc.CurrentBlock().Number.Uint64()
+	for ; next <= head && ctx.Err() == nil; next += config.Interval {
+		header := c.bc.GetHeaderByNumber(next)
+		if header == nil {
+			log.Error("missing header to checkpoint", "block", next)
+			break
+		}
+		checkpoint := NewCheckpoint(chainConfig.ChainID.Uint64(), genesisBlockNum, header)
+		if err := checkpoint.Sign(c.signer); err != nil {
+			log.Error("failed to sign checkpoint", "block", next, "err", err)
+			break
+		}
+		if err := c.store(checkpoint); err != nil {
+			log.Error("failed to store checkpoint", "block", next, "err", err)
+			break
+		}
+		// #nosec G115
+		checkpointBlockGauge.Update(int64(next))
+		log.Info("signed checkpoint", "block", next, "hash", checkpoint.BlockHash, "messageCount", checkpoint.MessageCount)
+	}
+	return config.CheckInterval
+}
+
+// maxCheckpointsPerRequest bounds the checkpoints returned by a single range request.
+const maxCheckpointsPerRequest = 1000
+
+// CheckpointAPI serves the latest and historical checkpoints.
+type CheckpointAPI struct {
+	checkpointer *Checkpointer
+}
+
+func NewCheckpointAPI(checkpointer *Checkpointer) *CheckpointAPI {
+	return &CheckpointAPI{checkpointer}
+}
+
+func (a *CheckpointAPI) LatestCheckpoint(ctx context.Context) (*Checkpoint, error) {
+	return a.checkpointer.Latest()
+}
+
+func (a *CheckpointAPI) Checkpoint(ctx context.Context, blockNumber hexutil.Uint64) (*Checkpoint, error) {
+	return a.checkpointer.Checkpoint(uint64(blockNumber))
+}
+
+func (a *CheckpointAPI) Checkpoints(ctx context.Context, first, last hexutil.Uint64) ([]*Checkpoint, error) {
+	if last < first {
+		return nil, errors.New("last block before first block")
+	}
+	return a.checkpointer.Checkpoints(uint64(first), uint64(last), maxCheckpointsPerRequest)
+}
+
+func (a *CheckpointAPI) CheckpointSigner(ctx context.Context) common.Address {
+	return a.checkpointer.SignerAddress()
+}
@@ -0,0 +1,342 @@
+// Copyright 2024, Offchain Labs, Inc.
+// For license information, see https://github.com/nitro/blob/master/LICENSE
+
+package gethexec
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	flag "github.com/spf13/pflag"
+
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/metrics"
+
+	"github.com/offchainlabs/nitro/util/dbutil"
+	"github.com/offchainlabs/nitro/util/stopwaiter"
+)
+
+var (
+	compactionStallBackgroundMeter = metrics.NewRegisteredMeter("arb/db/compaction/stall/background", nil)
+	compactionStallManualMeter     = metrics.NewRegisteredMeter("arb/db/compaction/stall/manual", nil)
+	compactionManualTimer          = metrics.NewRegisteredTimer("arb/db/compaction/manual", nil)
+	compactionThrottledGauge       = metrics.NewRegisteredGauge("arb/db/compaction/throttled", nil)
+	compactionWriteAmpGauge        = metrics.NewRegisteredGaugeFloat64("arb/db/compaction/writeamplification", nil)
+)
+
+type CompactionSchedulerConfig struct {
+	Enable                      bool          `koanf:"enable"`
+	CheckInterval               time.Duration `koanf:"check-interval" reload:"hot"`
+	Schedule                    string        `koanf:"schedule" reload:"hot"`
+	LowTrafficTxRate            float64       `koanf:"low-traffic-tx-rate" reload:"hot"`
+	LoadWindow                  time.Duration `koanf:"load-window" reload:"hot"`
+	SequencerActiveTimeout      time.Duration `koanf:"sequencer-active-timeout" reload:"hot"`
+	ThrottledConcurrency        int           `koanf:"throttled-concurrency" reload:"hot"`
+	WriteAmplificationThreshold float64       `koanf:"write-amplification-threshold" reload:"hot"`
+	StallThreshold              time.Duration `koanf:"stall-threshold" reload:"hot"`
+	MinInterval                 time.Duration `koanf:"min-interval" reload:"hot"`
+	Ranges                      int           `koanf:"ranges"`
+
+	// Generated: the low traffic window, in minutes since the start of the UTC day
+	windowStart int
+	windowEnd   int
+	scheduled   bool
+}
+
+var DefaultCompactionSchedulerConfig = CompactionSchedulerConfig{
+	Enable:                      false,
+	CheckInterval:               100 * time.Millisecond,
+	Schedule:                    "",
+	LowTrafficTxRate:            1,
+	LoadWindow:                  time.Minute,
+	SequencerActiveTimeout:      2 * time.Second,
+	ThrottledConcurrency:        1,
+	WriteAmplificationThreshold: 10,
+	StallThreshold:              time.Second,
+	MinInterval:                 time.Minute,
+	Ranges:                      16,
+}
+
+func CompactionSchedulerConfigAddOptions(prefix string, f *flag.FlagSet) {
+	f.Bool(prefix+".enable", DefaultCompactionSchedulerConfig.Enable, "enable the execution database compaction scheduler")
+	f.Duration(prefix+".check-interval", DefaultCompactionSchedulerConfig.CheckInterval, "how often to check database and sequencer activity")
+	f.String(prefix+".schedule", DefaultCompactionSchedulerConfig.Schedule, "UTC 24-hour low traffic window to compact in (e.g. 02:00-05:00), or empty to detect low traffic from the transaction rate")
+	f.Float64(prefix+".low-traffic-tx-rate", DefaultCompactionSchedulerConfig.LowTrafficTxRate, "transactions per second under which traffic is considered low, if no schedule is set")
+	f.Duration(prefix+".load-window", DefaultCompactionSchedulerConfig.LoadWindow, "time window the transaction rate is measured over")
+	f.Duration(prefix+".sequencer-active-timeout", DefaultCompactionSchedulerConfig.SequencerActiveTimeout, "the sequencer is considered actively building blocks for this long after it last built one")
+	f.Int(prefix+".throttled-concurrency", DefaultCompactionSchedulerConfig.ThrottledConcurrency, "maximum number of concurrent background compactions while the sequencer is building blocks (0 = don't throttle, pebble only)")
+	f.Float64(prefix+".write-amplification-threshold", DefaultCompactionSchedulerConfig.WriteAmplificationThreshold, "write amplification since the last manual compaction that triggers one (0 = ignore)")
+	f.Duration(prefix+".stall-threshold", DefaultCompactionSchedulerConfig.StallThreshold, "write stall time since the last manual compaction that triggers one (0 = ignore)")
+	f.Duration(prefix+".min-interval", DefaultCompactionSchedulerConfig.MinInterval, "minimum time between manual compactions")
+	f.Int(prefix+".ranges", DefaultCompactionSchedulerConfig.Ranges, "number of key ranges the database is split into, of which the least recently compacted is compacted at a time (1-256)")
+}
+
+// parseTimeOfDay parses a UTC 24-hour HH:MM time into minutes since the start of the day.
+func parseTimeOfDay(timeOfDay string) (int, error) {
+	parts := strings.Split(timeOfDay, ":")
+	if len(parts) != 2 {
+		return 0, fmt.Errorf("expected time in 24-hour HH:MM format but got \"%v\"", timeOfDay)
+	}
+	hours, err := strconv.Atoi(parts[0])
+	if err != nil || hours < 0 || hours >= 24 {
+		return 0, fmt.Errorf("invalid hours in \"%v\"", timeOfDay)
+	}
+	minutes, err := strconv.Atoi(parts[1])
+	if err != nil || minutes < 0 || minutes >= 60 {
+		return 0, fmt.Errorf("invalid minutes in \"%v\"", timeOfDay)
+	}
+	return hours*60 + minutes, nil
+}
+
+func (c *CompactionSchedulerConfig) Validate() error {
+	if c.Ranges < 1 || c.Ranges > 256 {
+		return fmt.Errorf("invalid compaction scheduler ranges %v, has to be between 1 and 256", c.Ranges)
+	}
+	if c.ThrottledConcurrency < 0 {
+		return errors.New("compaction scheduler throttled concurrency can't be negative")
+	}
+	if c.Enable && c.CheckInterval <= 0 {
+		return errors.New("compaction scheduler check interval has to be positive")
+	}
+	c.scheduled = false
+	if c.Schedule == "" {
+		return nil
+	}
+	start, end, found := strings.Cut(c.Schedule, "-")
+	if !found {
+		return fmt.Errorf("expected compaction schedule in HH:MM-HH:MM format but got \"%v\"", c.Schedule)
+	}
+	var err error
+	if c.windowStart, err = parseTimeOfDay(start); err != nil {
+		return fmt.Errorf("invalid compaction schedule start: %w", err)
+	}
+	if c.windowEnd, err = parseTimeOfDay(end); err != nil {
+		return fmt.Errorf("invalid compaction schedule end: %w", err)
+	}
+	c.scheduled = true
+	return nil
+}
+
+// inWindow returns whether t is in the scheduled low traffic window, which may wrap past midnight.
+func (c *CompactionSchedulerConfig) inWindow(t time.Time) bool {
+	t = t.UTC()
+	minutes := t.Hour()*60 + t.Minute()
+	if c.windowStart <= c.windowEnd {
+		return minutes >= c.windowStart && minutes < c.windowEnd
+	}
+	return minutes >= c.windowStart || minutes < c.windowEnd
+}
+
+type CompactionSchedulerConfigFetcher func() *CompactionSchedulerConfig
+
+// compactableDatabase is the part of a database backend the compaction scheduler controls.
+type compactableDatabase interface {
+	Compact(start []byte, limit []byte) error
+	CompactionStats() dbutil.CompactionStats
+	SetCompactionConcurrencyLimit(limit int)
+}
+
+// controlledDatabase pairs a database with the controller of its compaction knobs.
+type controlledDatabase struct {
+	ethdb.Compacter
+	controller *dbutil.CompactionController
+}
+
+func (d controlledDatabase) CompactionStats() dbutil.CompactionStats {
+	return d.controller.Stats()
+}
+
+func (d controlledDatabase) SetCompactionConcurrencyLimit(limit int) {
+	d.controller.SetConcurrencyLimit(limit)
+}
+
+// blockActivity is what the compaction scheduler needs to know about block production.
+type blockActivity interface {
+	// LastSequencedBlockTime returns when the sequencer last started building a block.
+	LastSequencedBlockTime() time.Time
+	// AppendedTxCount returns the number of transactions in blocks appended since startup.
+	AppendedTxCount() uint64
+}
+
+type txCountSample struct {
+	time  time.Time
+	count uint64
+}
+
+// CompactionScheduler avoids compaction stalls during block production. It throttles
+// background compactions while the sequencer is building blocks, and manually compacts
+// the database one key range at a time when traffic is low and the database's write
+// amplification or stall time shows compactions are falling behind.
+type CompactionScheduler struct {
+	stopwaiter.StopWaiter
+
+	config   CompactionSchedulerConfigFetcher
+	db       compactableDatabase
+	activity blockActivity
+
+	// whether the database's compaction stats are collected, which requires metrics
+	statsAvailable bool
+	throttled      bool
+	compacting     atomic.Bool
+	// the next key range to compact
+	nextRange int
+
+	txCounts           []txCountSample
+	lastStats          dbutil.CompactionStats
+	statsAtCompaction  dbutil.CompactionStats
+	lastCompactionTime time.Time
+}
+
+func NewCompactionScheduler(config CompactionSchedulerConfigFetcher, db compactableDatabase, activity blockActivity) *CompactionScheduler {
+	return &CompactionScheduler{
+		config:   config,
+		db:       db,
+		activity: activity,
+	}
+}
+
+func (s *CompactionScheduler) Start(ctxIn context.Context) {
+	s.StopWaiter.Start(ctxIn, s)
+	s.initialize(metrics.Enabled)
+	s.CallIteratively(s.update)
+}
+
+func (s *CompactionScheduler) initialize(statsAvailable bool) {
+	s.statsAvailable = statsAvailable
+	if !statsAvailable {
+		log.Warn("metrics are disabled, so the compaction scheduler can't see database stalls or write amplification and will compact whenever traffic is low")
+	}
+	initial := s.db.CompactionStats()
+	s.lastStats = initial
+	s.statsAtCompaction = initial
+	s.lastCompactionTime = time.Now()
+}
+
+func (s *CompactionScheduler) StopAndWait() {
+	s.StopWaiter.StopAndWait()
+	s.setThrottled(false, 0)
+}
+
+func (s *CompactionScheduler) setThrottled(throttled bool, concurrency int) {
+	if throttled == s.throttled {
+		return
+	}
+	s.throttled = throttled
+	if throttled {
+		s.db.SetCompactionConcurrencyLimit(concurrency)
+		compactionThrottledGauge.Update(1)
+	} else {
+		s.db.SetCompactionConcurrencyLimit(0)
+		compactionThrottledGauge.Update(0)
+	}
+}
+
+func (s *CompactionScheduler) sequencerActive(config *CompactionSchedulerConfig, now time.Time) bool {
+	return now.Sub(s.activity.LastSequencedBlockTime()) < config.SequencerActiveTimeout
+}
+
+// txRate records the current transaction count and returns the transaction rate over
+// the load window, or false if the window hasn't been observed for long enough yet.
+func (s *CompactionScheduler) txRate(config *CompactionSchedulerConfig, now time.Time) (float64, bool) {
+	s.txCounts = append(s.txCounts, txCountSample{now, s.activity.AppendedTxCount()})
+	// drop samples older than the window, keeping one to measure from
+	for len(s.txCounts) > 1 && now.Sub(s.txCounts[1].time) >= config.LoadWindow {
+		s.txCounts = s.txCounts[1:]
+	}
+	oldest, latest := s.txCounts[0], s.txCounts[len(s.txCounts)-1]
+	elapsed := latest.time.Sub(oldest.time)
+	if elapsed < config.LoadWindow {
+		return 0, false
+	}
+	if elapsed <= 0 {
+		return 0, true
+	}
+	return float64(latest.count-oldest.count) / elapsed.Seconds(), true
+}
+
+func (s *CompactionScheduler) lowTraffic(config *CompactionSchedulerConfig, now time.Time, rate float64, rateKnown bool) bool {
+	if config.scheduled {
+		return config.inWindow(now)
+	}
+	return rateKnown && rate < config.LowTrafficTxRate && !s.sequencerActive(config, now)
+}
+
+// needsCompaction returns whether the database's compaction activity since the last
+// manual compaction shows background compactions falling behind.
+func (s *CompactionScheduler) needsCompaction(config *CompactionSchedulerConfig, sinceCompaction dbutil.CompactionStats) bool {
+	if !s.statsAvailable {
+		return true
+	}
+	if config.WriteAmplificationThreshold == 0 && config.StallThreshold == 0 {
+		return true
+	}
+	if config.StallThreshold > 0 && sinceCompaction.WriteStallTime >= config.StallThreshold {
+		return true
+	}
+	return config.WriteAmplificationThreshold > 0 && sinceCompaction.WriteAmplification() >= config.WriteAmplificationThreshold
+}
+
+// keyRange returns the bounds of the index'th of n ranges the key space is split into,
+// by the first byte of the key.
+func keyRange(index, n int) ([]byte, []byte) {
+	var start, limit []byte
+	if index > 0 {
+		start = []byte{byte(index * 256 / n)}
+	}
+	if index < n-1 {
+		limit = []byte{byte((index + 1) * 256 / n)}
+	}
+	return start, limit
+}
+
+func (s *CompactionScheduler) update(ctx context.Context) time.Duration {
+	config := s.config()
+	now := time.Now()
+
+	active := s.sequencerActive(config, now)
+	s.setThrottled(active && config.ThrottledConcurrency > 0, config.ThrottledConcurrency)
+
+	stats := s.db.CompactionStats()
+	interval := stats.Sub(s.lastStats)
+	s.lastStats = stats
+	if s.compacting.Load() {
+		compactionStallManualMeter.Mark(int64(interval.WriteStallTime))
+	} else {
+		compactionStallBackgroundMeter.Mark(int64(interval.WriteStallTime))
+	}
+	sinceCompaction := stats.Sub(s.statsAtCompaction)
+	compactionWriteAmpGauge.Update(sinceCompaction.WriteAmplification())
+
+	rate, rateKnown := s.txRate(config, now)
+	if s.compacting.Load() || now.Sub(s.lastCompactionTime) < config.MinInterval {
+		return config.CheckInterval
+	}
+	if !s.lowTraffic(config, now, rate, rateKnown) || !s.needsCompaction(config, sinceCompaction) {
+		return config.CheckInterval
+	}
+
+	ranges := config.Ranges
+	index := s.nextRange % ranges
+	s.nextRange = (index + 1) % ranges
+	s.lastCompactionTime = now
+	s.statsAtCompaction = stats
+	s.compacting.Store(true)
+	s.LaunchThread(func(ctx context.Context) {
+		defer s.compacting.Store(false)
+		start, limit := keyRange(index, ranges)
+		log.Info("compacting execution database range during low traffic", "range", index, "of", ranges, "writeAmplification", sinceCompaction.WriteAmplification(), "stallTime", sinceCompaction.WriteStallTime)
+		compactionStart := time.Now()
+		if err := s.db.Compact(start, limit); err != nil {
+			log.Warn("failed to compact execution database range", "range", index, "err", err)
+			return
+		}
+		compactionManualTimer.UpdateSince(compactionStart)
+	})
+	return config.CheckInterval
+}
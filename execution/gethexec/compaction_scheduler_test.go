@@ -0,0 +1,197 @@
+// Copyright 2024, Offchain Labs, Inc.
+// For license information, see https://github.com/nitro/blob/master/LICENSE
+
+package gethexec
+
+import (
+	"bytes"
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/offchainlabs/nitro/util/dbutil"
+)
+
+type compactedRange struct {
+	start []byte
+	limit []byte
+}
+
+type mockCompactableDatabase struct {
+	mutex     sync.Mutex
+	stats     dbutil.CompactionStats
+	limit     int
+	compacted []compactedRange
+}
+
+func (d *mockCompactableDatabase) Compact(start []byte, limit []byte) error {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	d.compacted = append(d.compacted, compactedRange{start, limit})
+	return nil
+}
+
+func (d *mockCompactableDatabase) CompactionStats() dbutil.CompactionStats {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	return d.stats
+}
+
+func (d *mockCompactableDatabase) SetCompactionConcurrencyLimit(limit int) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	d.limit = limit
+}
+
+func (d *mockCompactableDatabase) addStall(stall time.Duration) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	d.stats.WriteStallTime += stall
+	d.stats.WriteStalls++
+}
+
+func (d *mockCompactableDatabase) state() (int, []compactedRange) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	return d.limit, append([]compactedRange{}, d.compacted...)
+}
+
+type mockBlockActivity struct {
+	lastSequenced time.Time
+	txCount       uint64
+}
+
+func (a *mockBlockActivity) LastSequencedBlockTime() time.Time { return a.lastSequenced }
+func (a *mockBlockActivity) AppendedTxCount() uint64           { return a.txCount }
+
+func newTestCompactionScheduler(t *testing.T, ctx context.Context, config *CompactionSchedulerConfig) (*CompactionScheduler, *mockCompactableDatabase, *mockBlockActivity) {
+	t.Helper()
+	if err := config.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	db := &mockCompactableDatabase{}
+	activity := &mockBlockActivity{}
+	s := NewCompactionScheduler(func() *CompactionSchedulerConfig { return config }, db, activity)
+	// drive updates from the test instead of a background thread
+	s.StopWaiter.Start(ctx, s)
+	s.initialize(true)
+	return s, db, activity
+}
+
+// waitForCompactions runs scheduler updates until no manual compaction is running.
+func waitForCompactions(t *testing.T, s *CompactionScheduler) {
+	t.Helper()
+	for i := 0; s.compacting.Load(); i++ {
+		if i == 1000 {
+			t.Fatal("manual compaction didn't finish")
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestCompactionSchedulerThrottlesWhileSequencing(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	config := DefaultCompactionSchedulerConfig
+	config.ThrottledConcurrency = 2
+	s, db, activity := newTestCompactionScheduler(t, ctx, &config)
+	defer s.StopAndWait()
+
+	activity.lastSequenced = time.Now()
+	s.update(ctx)
+	if limit, _ := db.state(); limit != 2 {
+		t.Fatalf("expected compactions to be throttled to 2 while sequencing, got limit %v", limit)
+	}
+
+	activity.lastSequenced = time.Now().Add(-config.SequencerActiveTimeout)
+	s.update(ctx)
+	if limit, _ := db.state(); limit != 0 {
+		t.Fatalf("expected throttling to stop once the sequencer went idle, got limit %v", limit)
+	}
+}
+
+func TestCompactionSchedulerCompactsColdRangesWhenIdle(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	config := DefaultCompactionSchedulerConfig
+	config.LoadWindow = 0
+	config.MinInterval = 0
+	config.Ranges = 4
+	config.StallThreshold = time.Second
+	config.WriteAmplificationThreshold = 0
+	s, db, activity := newTestCompactionScheduler(t, ctx, &config)
+	defer s.StopAndWait()
+
+	// no stalls, so no compaction is needed
+	s.update(ctx)
+	waitForCompactions(t, s)
+	if _, compacted := db.state(); len(compacted) != 0 {
+		t.Fatalf("compacted %v ranges without stalls", len(compacted))
+	}
+
+	// stalls while the sequencer is busy don't trigger compactions until it's idle
+	db.addStall(2 * time.Second)
+	activity.lastSequenced = time.Now()
+	activity.txCount += 1000
+	s.update(ctx)
+	waitForCompactions(t, s)
+	if _, compacted := db.state(); len(compacted) != 0 {
+		t.Fatalf("compacted %v ranges while the sequencer was busy", len(compacted))
+	}
+
+	activity.lastSequenced = time.Time{}
+	for i := 0; i < config.Ranges+1; i++ {
+		db.addStall(2 * time.Second)
+		s.update(ctx)
+		waitForCompactions(t, s)
+	}
+	_, compacted := db.state()
+	if len(compacted) != config.Ranges+1 {
+		t.Fatalf("expected %v compactions, got %v", config.Ranges+1, len(compacted))
+	}
+	// the ranges are compacted least recently compacted first, and together cover the key space
+	for i, r := range compacted {
+		start, limit := keyRange(i%config.Ranges, config.Ranges)
+		if !bytes.Equal(r.start, start) || !bytes.Equal(r.limit, limit) {
+			t.Errorf("compaction %v was of range %x-%x, expected %x-%x", i, r.start, r.limit, start, limit)
+		}
+	}
+	if compacted[0].start != nil || compacted[config.Ranges-1].limit != nil {
+		t.Error("compacted ranges don't cover the whole key space")
+	}
+	for i := 1; i < config.Ranges; i++ {
+		if !bytes.Equal(compacted[i-1].limit, compacted[i].start) {
+			t.Errorf("range %v doesn't start where range %v ends", i, i-1)
+		}
+	}
+}
+
+func TestCompactionSchedule(t *testing.T) {
+	config := DefaultCompactionSchedulerConfig
+	config.Schedule = "23:30-01:15"
+	if err := config.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	day := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	for _, tc := range []struct {
+		offset time.Duration
+		in     bool
+	}{
+		{23*time.Hour + 29*time.Minute, false},
+		{23*time.Hour + 30*time.Minute, true},
+		{time.Hour, true},
+		{time.Hour + 15*time.Minute, false},
+		{12 * time.Hour, false},
+	} {
+		if in := config.inWindow(day.Add(tc.offset)); in != tc.in {
+			t.Errorf("expected %v in window %v to be %v", tc.offset, config.Schedule, tc.in)
+		}
+	}
+	for _, invalid := range []string{"02:00", "25:00-03:00", "02:00-03:60", "a:b-c:d"} {
+		config.Schedule = invalid
+		if err := config.Validate(); err == nil {
+			t.Errorf("expected schedule %v to be invalid", invalid)
+		}
+	}
+}
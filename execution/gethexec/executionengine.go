@@ -24,6 +24,7 @@ import (
 	"runtime/pprof"
 	"runtime/trace"
 	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -33,6 +34,7 @@ import (
 	"github.com/ethereum/go-ethereum/core/rawdb"
 	"github.com/ethereum/go-ethereum/core/state"
 	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/event"
 	"github.com/ethereum/go-ethereum/log"
 	"github.com/ethereum/go-ethereum/metrics"
 
@@ -97,6 +99,17 @@ type ExecutionEngine struct {
 	prefetchBlock bool
 
 	cachedL1PriceData *L1PriceData
+
+	safeMode safeMode
+
+	chainParamsFeed event.Feed
+	l1PricingFeed   event.Feed
+	// blocks with batch posting reports waiting to be traced, off the path of appending blocks
+	l1PricingTraceQueue chan *types.Block
+
+	// unix nanoseconds of when the sequencer last started building a block
+	lastSequencedBlock atomic.Int64
+	appendedTxCount    atomic.Uint64
 }
 
 func NewL1PriceData() *L1PriceData {
@@ -107,10 +120,11 @@ func NewL1PriceData() *L1PriceData {
 
 func NewExecutionEngine(bc *core.BlockChain) (*ExecutionEngine, error) {
 	return &ExecutionEngine{
-		bc:                bc,
-		resequenceChan:    make(chan []*arbostypes.MessageWithMetadata),
-		newBlockNotifier:  make(chan struct{}, 1),
-		cachedL1PriceData: NewL1PriceData(),
+		bc:                  bc,
+		resequenceChan:      make(chan []*arbostypes.MessageWithMetadata),
+		newBlockNotifier:    make(chan struct{}, 1),
+		cachedL1PriceData:   NewL1PriceData(),
+		l1PricingTraceQueue: make(chan *types.Block, l1PricingTraceQueueSize),
 	}, nil
 }
 
@@ -305,6 +319,49 @@ func (s *ExecutionEngine) Reorg(count arbutil.MessageIndex, newMessages []arbost
 	return newMessagesResults, nil
 }
 
+// RepairConsistency rolls the chain back to the latest block passing the consistency check,
+// looking back at most maxConsistencyRepairBlocks blocks. Consensus then re-executes the
+// messages after it.
+func (s *ExecutionEngine) RepairConsistency() error {
+	s.createBlocksMutex.Lock()
+	defer s.createBlocksMutex.Unlock()
+	head := s.bc.CurrentBlock()
+	if head == nil {
+		return errors.New("no head block")
+	}
+	target := head
+	for i := 0; s.checkBlockConsistency(target) != nil; i++ {
+		targetNum := target.Number.Uint64()
+		if targetNum == 0 || i >= maxConsistencyRepairBlocks {
+			return fmt.Errorf("no consistent block within %v blocks of head block %v", i, head.Number)
+		}
+		target = s.bc.GetHeader(target.ParentHash, targetNum-1)
+		if target == nil {
+			return fmt.Errorf("block %v has no parent to roll back to", targetNum)
+		}
+	}
+	if target.Hash() == head.Hash() {
+		return nil
+	}
+	targetBlock := s.bc.GetBlock(target.Hash(), target.Number.Uint64())
+	if targetBlock == nil {
+		return fmt.Errorf("block %v is missing its body", target.Number)
+	}
+	log.Warn("rolling back to the latest consistent block", "head", head.Number, "target", target.Number)
+
+	tag := s.bc.StateCache().WasmCacheTag()
+	// reorg Rust-side VM state
+	C.stylus_reorg_vm(C.uint64_t(target.Number.Uint64()), C.uint32_t(tag))
+
+	if err := s.bc.ReorgToOldBlock(targetBlock); err != nil {
+		return err
+	}
+	if s.recorder != nil {
+		s.recorder.ReorgTo(target)
+	}
+	return nil
+}
+
 func (s *ExecutionEngine) getCurrentHeader() (*types.Header, error) {
 	currentBlock := s.bc.CurrentBlock()
 	if currentBlock == nil {
@@ -499,6 +556,7 @@ func writeAndLog(pprof, trace *bytes.Buffer) {
 }
 
 func (s *ExecutionEngine) sequenceTransactionsWithBlockMutex(header *arbostypes.L1IncomingMessageHeader, txes types.Transactions, hooks *arbos.SequencingHooks) (*types.Block, error) {
+	s.lastSequencedBlock.Store(time.Now().UnixNano())
 	lastBlockHeader, err := s.getCurrentHeader()
 	if err != nil {
 		return nil, err
@@ -718,6 +776,7 @@ func (s *ExecutionEngine) appendBlock(block *types.Block, statedb *state.StateDB
 	blockWriteToDbTimer.Update(time.Since(startTime))
 	baseFeeGauge.Update(block.BaseFee().Int64())
 	txCountHistogram.Update(int64(len(block.Transactions()) - 1))
+	s.appendedTxCount.Add(uint64(len(block.Transactions())))
 	var blockGasused uint64
 	for i := 1; i < len(receipts); i++ {
 		val := arbmath.SaturatingUSub(receipts[i].GasUsed, receipts[i].GasUsedForL1)
@@ -727,9 +786,21 @@ func (s *ExecutionEngine) appendBlock(block *types.Block, statedb *state.StateDB
 	blockGasUsedHistogram.Update(int64(blockGasused))
 	gasUsedSinceStartupCounter.Inc(int64(blockGasused))
 	s.updateL1GasPriceEstimateMetric()
+	s.notifyChainParameterChanges(block, receipts)
+	s.queueL1PricingUpdates(block)
 	return nil
 }
 
+// LastSequencedBlockTime returns when the sequencer last started building a block.
+func (s *ExecutionEngine) LastSequencedBlockTime() time.Time {
+	return time.Unix(0, s.lastSequencedBlock.Load())
+}
+
+// AppendedTxCount returns the number of transactions in blocks appended since startup.
+func (s *ExecutionEngine) AppendedTxCount() uint64 {
+	return s.appendedTxCount.Load()
+}
+
 func (s *ExecutionEngine) resultFromHeader(header *types.Header) (*execution.MessageResult, error) {
 	if header == nil {
 		return nil, fmt.Errorf("result not found")
@@ -969,6 +1040,16 @@ func (s *ExecutionEngine) Start(ctx_in context.Context) {
 			}
 		}
 	})
+	s.LaunchThread(func(ctx context.Context) {
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case block := <-s.l1PricingTraceQueue:
+				s.notifyL1PricingUpdates(block)
+			}
+		}
+	})
 	s.LaunchThread(func(ctx context.Context) {
 		var lastBlock *types.Block
 		for {
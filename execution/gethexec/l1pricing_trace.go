@@ -0,0 +1,137 @@
+// Copyright 2024, Offchain Labs, Inc.
+// For license information, see https://github.com/nitro/blob/master/LICENSE
+
+package gethexec
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/event"
+	"github.com/ethereum/go-ethereum/log"
+
+	"github.com/offchainlabs/nitro/arbos"
+	"github.com/offchainlabs/nitro/arbos/arbosState"
+	"github.com/offchainlabs/nitro/execution"
+)
+
+func isBatchPostingReport(tx *types.Transaction) bool {
+	inner, ok := tx.GetInner().(*types.ArbitrumInternalTx)
+	return ok && len(inner.Data) >= 4 && [4]byte(inner.Data[:4]) == arbos.InternalTxBatchPostingReportMethodID
+}
+
+func hasBatchPostingReport(block *types.Block) bool {
+	for _, tx := range block.Transactions() {
+		if isBatchPostingReport(tx) {
+			return true
+		}
+	}
+	return false
+}
+
+// l1PricingUpdateTraces explains how the batch posting reports in a block changed the L1 pricing model.
+// ArbOS doesn't keep this around, so the internal txs leading up to and including each report are
+// re-applied on top of the parent block's state, this time tracing the update.
+// Reports arrive in blocks of their own, so this only needs to replay internal txs.
+func (s *ExecutionEngine) l1PricingUpdateTraces(block *types.Block) ([]execution.L1PricingUpdateTrace, error) {
+	if !hasBatchPostingReport(block) {
+		return nil, nil
+	}
+	parent := s.bc.GetHeader(block.ParentHash(), block.NumberU64()-1)
+	if parent == nil {
+		return nil, errors.New("parent block not found")
+	}
+	statedb, err := s.bc.StateAt(parent.Root)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get state of parent block %v: %w", parent.Number, err)
+	}
+	blockContext := core.NewEVMBlockContext(block.Header(), s.bc, nil)
+	evm := vm.NewEVM(blockContext, vm.TxContext{}, statedb, s.bc.Config(), vm.Config{})
+
+	var traces []execution.L1PricingUpdateTrace
+	for i, tx := range block.Transactions() {
+		inner, ok := tx.GetInner().(*types.ArbitrumInternalTx)
+		if !ok {
+			break
+		}
+		statedb.SetTxContext(tx.Hash(), i)
+		state, err := arbosState.OpenSystemArbosState(statedb, nil, false)
+		if err != nil {
+			return nil, err
+		}
+		if !isBatchPostingReport(tx) {
+			if err := arbos.ApplyInternalTxUpdate(inner, state, evm); err != nil {
+				return nil, fmt.Errorf("failed to replay internal tx %v: %w", i, err)
+			}
+			statedb.Finalise(true)
+			continue
+		}
+		trace, err := arbos.TraceBatchPostingReport(inner, state, evm)
+		if err != nil {
+			return nil, fmt.Errorf("failed to trace batch posting report %v: %w", i, err)
+		}
+		statedb.Finalise(true)
+		// #nosec G115
+		txIndex := hexutil.Uint64(i)
+		traces = append(traces, execution.L1PricingUpdateTrace{
+			BlockNumber:    hexutil.Uint64(block.NumberU64()),
+			BlockHash:      block.Hash(),
+			TxIndex:        txIndex,
+			BatchPoster:    trace.BatchPoster,
+			BatchTimestamp: hexutil.Uint64(trace.UpdateTime),
+			BatchDataGas:   hexutil.Uint64(trace.BatchDataGas),
+			PerBatchGas:    trace.PerBatchGas,
+			L1BaseFee:      (*hexutil.Big)(trace.L1BaseFee),
+			WeiSpent:       (*hexutil.Big)(trace.WeiSpent),
+			WeiAllocated:   (*hexutil.Big)(trace.WeiAllocated),
+			UnitsAllocated: hexutil.Uint64(trace.UnitsAllocated),
+			PriceBefore:    (*hexutil.Big)(trace.PriceBefore),
+			PriceAfter:     (*hexutil.Big)(trace.PriceAfter),
+			SurplusBefore:  trace.SurplusBefore,
+			SurplusAfter:   trace.SurplusAfter,
+			Rules:          trace.Rules,
+		})
+	}
+	return traces, nil
+}
+
+// l1PricingTraceQueueSize bounds how many blocks can wait to be traced before further ones are dropped.
+const l1PricingTraceQueueSize = 64
+
+// queueL1PricingUpdates hands a block that was just appended to the chain to the tracing thread,
+// if it has batch posting reports. Tracing replays the block's internal txs, so rather than
+// hold up appending blocks, the traces of blocks that don't fit in the queue are dropped.
+func (s *ExecutionEngine) queueL1PricingUpdates(block *types.Block) {
+	if !hasBatchPostingReport(block) {
+		return
+	}
+	select {
+	case s.l1PricingTraceQueue <- block:
+	default:
+		log.Warn("dropping L1 pricing update traces, too many blocks are waiting to be traced", "block", block.NumberU64())
+	}
+}
+
+// notifyL1PricingUpdates tells subscribers how the batch posting reports in a block
+// that was appended to the chain changed the L1 pricing model.
+// The traces are only diagnostics, so failing to produce them doesn't fail the block.
+func (s *ExecutionEngine) notifyL1PricingUpdates(block *types.Block) {
+	traces, err := s.l1PricingUpdateTraces(block)
+	if err != nil {
+		log.Warn("failed to trace L1 pricing updates", "block", block.NumberU64(), "err", err)
+		return
+	}
+	for _, trace := range traces {
+		s.l1PricingFeed.Send(trace)
+	}
+}
+
+// SubscribeL1PricingUpdates notifies ch of how each batch posting report changed the L1 pricing model,
+// shortly after execution applies the block that contains it. Traces are dropped if they fall too far behind.
+func (s *ExecutionEngine) SubscribeL1PricingUpdates(ch chan<- execution.L1PricingUpdateTrace) event.Subscription {
+	return s.l1PricingFeed.Subscribe(ch)
+}
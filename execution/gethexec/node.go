@@ -8,6 +8,7 @@ import (
 	"sort"
 	"sync/atomic"
 	"testing"
+	"time"
 
 	flag "github.com/spf13/pflag"
 
@@ -20,6 +21,7 @@ import (
 	"github.com/ethereum/go-ethereum/eth/filters"
 	"github.com/ethereum/go-ethereum/ethclient"
 	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/event"
 	"github.com/ethereum/go-ethereum/log"
 	"github.com/ethereum/go-ethereum/node"
 	"github.com/ethereum/go-ethereum/rpc"
@@ -84,19 +86,22 @@ func StylusTargetConfigAddOptions(prefix string, f *flag.FlagSet) {
 }
 
 type Config struct {
-	ParentChainReader         headerreader.Config `koanf:"parent-chain-reader" reload:"hot"`
-	Sequencer                 SequencerConfig     `koanf:"sequencer" reload:"hot"`
-	RecordingDatabase         BlockRecorderConfig `koanf:"recording-database"`
-	TxPreChecker              TxPreCheckerConfig  `koanf:"tx-pre-checker" reload:"hot"`
-	Forwarder                 ForwarderConfig     `koanf:"forwarder"`
-	ForwardingTarget          string              `koanf:"forwarding-target"`
-	SecondaryForwardingTarget []string            `koanf:"secondary-forwarding-target"`
-	Caching                   CachingConfig       `koanf:"caching"`
-	RPC                       arbitrum.Config     `koanf:"rpc"`
-	TxLookupLimit             uint64              `koanf:"tx-lookup-limit"`
-	EnablePrefetchBlock       bool                `koanf:"enable-prefetch-block"`
-	SyncMonitor               SyncMonitorConfig   `koanf:"sync-monitor"`
-	StylusTarget              StylusTargetConfig  `koanf:"stylus-target"`
+	ParentChainReader         headerreader.Config       `koanf:"parent-chain-reader" reload:"hot"`
+	Sequencer                 SequencerConfig           `koanf:"sequencer" reload:"hot"`
+	RecordingDatabase         BlockRecorderConfig       `koanf:"recording-database"`
+	TxPreChecker              TxPreCheckerConfig        `koanf:"tx-pre-checker" reload:"hot"`
+	Forwarder                 ForwarderConfig           `koanf:"forwarder"`
+	ForwardingTarget          string                    `koanf:"forwarding-target"`
+	SecondaryForwardingTarget []string                  `koanf:"secondary-forwarding-target"`
+	Caching                   CachingConfig             `koanf:"caching"`
+	RPC                       arbitrum.Config           `koanf:"rpc"`
+	TxLookupLimit             uint64                    `koanf:"tx-lookup-limit"`
+	EnablePrefetchBlock       bool                      `koanf:"enable-prefetch-block"`
+	SyncMonitor               SyncMonitorConfig         `koanf:"sync-monitor"`
+	StylusTarget              StylusTargetConfig        `koanf:"stylus-target"`
+	CompactionScheduler       CompactionSchedulerConfig `koanf:"compaction-scheduler" reload:"hot"`
+	Checkpointer              CheckpointerConfig        `koanf:"checkpointer" reload:"hot"`
+	SafeModeCheckInterval     time.Duration             `koanf:"safe-mode-check-interval" reload:"hot"`
 
 	forwardingTarget string
 }
@@ -122,6 +127,15 @@ func (c *Config) Validate() error {
 	if err := c.StylusTarget.Validate(); err != nil {
 		return err
 	}
+	if err := c.CompactionScheduler.Validate(); err != nil {
+		return err
+	}
+	if err := c.Checkpointer.Validate(); err != nil {
+		return err
+	}
+	if c.SafeModeCheckInterval <= 0 {
+		return errors.New("safe mode check interval has to be positive")
+	}
 	return nil
 }
 
@@ -139,6 +153,9 @@ func ConfigAddOptions(prefix string, f *flag.FlagSet) {
 	f.Uint64(prefix+".tx-lookup-limit", ConfigDefault.TxLookupLimit, "retain the ability to lookup transactions by hash for the past N blocks (0 = all blocks)")
 	f.Bool(prefix+".enable-prefetch-block", ConfigDefault.EnablePrefetchBlock, "enable prefetching of blocks")
 	StylusTargetConfigAddOptions(prefix+".stylus-target", f)
+	CompactionSchedulerConfigAddOptions(prefix+".compaction-scheduler", f)
+	CheckpointerConfigAddOptions(prefix+".checkpointer", f)
+	f.Duration(prefix+".safe-mode-check-interval", ConfigDefault.SafeModeCheckInterval, "how often to rerun the execution consistency check after startup, entering safe mode if it fails")
 }
 
 var ConfigDefault = Config{
@@ -154,6 +171,9 @@ var ConfigDefault = Config{
 	Forwarder:                 DefaultNodeForwarderConfig,
 	EnablePrefetchBlock:       true,
 	StylusTarget:              DefaultStylusTargetConfig,
+	CompactionScheduler:       DefaultCompactionSchedulerConfig,
+	Checkpointer:              DefaultCheckpointerConfig,
+	SafeModeCheckInterval:     10 * time.Minute,
 }
 
 type ConfigFetcher func() *Config
@@ -171,7 +191,11 @@ type ExecutionNode struct {
 	SyncMonitor       *SyncMonitor
 	ParentChainReader *headerreader.HeaderReader
 	ClassicOutbox     *ClassicOutboxRetriever
-	started           atomic.Bool
+	// nil unless the compaction scheduler is enabled
+	CompactionScheduler *CompactionScheduler
+	// nil unless the checkpointer is enabled
+	Checkpointer *Checkpointer
+	started      atomic.Bool
 }
 
 func CreateExecutionNode(
@@ -244,6 +268,20 @@ func CreateExecutionNode(
 
 	syncMon := NewSyncMonitor(&config.SyncMonitor, execEngine)
 
+	var compactionScheduler *CompactionScheduler
+	if config.CompactionScheduler.Enable {
+		chainDBController := controlledDatabase{chainDB, dbutil.CompactionControllerFor("l2chaindata")}
+		compactionScheduler = NewCompactionScheduler(func() *CompactionSchedulerConfig { return &configFetcher().CompactionScheduler }, chainDBController, execEngine)
+	}
+
+	var checkpointer *Checkpointer
+	if config.Checkpointer.Enable {
+		checkpointer, err = NewCheckpointer(func() *CheckpointerConfig { return &configFetcher().Checkpointer }, l2BlockChain, chainDB)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	var classicOutbox *ClassicOutboxRetriever
 
 	if l2BlockChain.Config().ArbitrumChainParams.GenesisBlockNum > 0 {
@@ -264,7 +302,7 @@ func CreateExecutionNode(
 	apis := []rpc.API{{
 		Namespace: "arb",
 		Version:   "1.0",
-		Service:   NewArbAPI(txPublisher),
+		Service:   NewArbAPI(txPublisher, l2BlockChain, execEngine),
 		Public:    false,
 	}}
 	apis = append(apis, rpc.API{
@@ -287,29 +325,62 @@ func CreateExecutionNode(
 		),
 		Public: false,
 	})
+	apis = append(apis, rpc.API{
+		Namespace: "arb",
+		Version:   "1.0",
+		Service:   NewArbTraceAPI(backend.APIBackend()),
+		Public:    false,
+	})
 	apis = append(apis, rpc.API{
 		Namespace: "debug",
 		Service:   eth.NewDebugAPI(eth.NewArbEthereum(l2BlockChain, chainDB)),
 		Public:    false,
 	})
+	if sequencer != nil && config.Sequencer.WarmStandby.Enable {
+		apis = append(apis, rpc.API{
+			Namespace: "arbsequencer",
+			Version:   "1.0",
+			Service:   NewArbSequencerAPI(sequencer),
+			Public:    false,
+		})
+	}
 
-	stack.RegisterAPIs(apis)
+	if checkpointer != nil {
+		apis = append(apis, rpc.API{
+			Namespace: "arb",
+			Version:   "1.0",
+			Service:   NewCheckpointAPI(checkpointer),
+			Public:    false,
+		})
+	}
+
+	execNode := &ExecutionNode{
+		ChainDB:             chainDB,
+		Backend:             backend,
+		FilterSystem:        filterSystem,
+		ArbInterface:        arbInterface,
+		ExecEngine:          execEngine,
+		Recorder:            recorder,
+		Sequencer:           sequencer,
+		TxPublisher:         txPublisher,
+		ConfigFetcher:       configFetcher,
+		SyncMonitor:         syncMon,
+		ParentChainReader:   parentChainReader,
+		ClassicOutbox:       classicOutbox,
+		CompactionScheduler: compactionScheduler,
+		Checkpointer:        checkpointer,
+	}
+
+	apis = append(apis, rpc.API{
+		Namespace: "arbadmin",
+		Version:   "1.0",
+		Service:   NewArbAdminAPI(execNode),
+		Public:    false,
+	})
 
-	return &ExecutionNode{
-		ChainDB:           chainDB,
-		Backend:           backend,
-		FilterSystem:      filterSystem,
-		ArbInterface:      arbInterface,
-		ExecEngine:        execEngine,
-		Recorder:          recorder,
-		Sequencer:         sequencer,
-		TxPublisher:       txPublisher,
-		ConfigFetcher:     configFetcher,
-		SyncMonitor:       syncMon,
-		ParentChainReader: parentChainReader,
-		ClassicOutbox:     classicOutbox,
-	}, nil
+	stack.RegisterAPIs(apis)
 
+	return execNode, nil
 }
 
 func (n *ExecutionNode) MarkFeedStart(to arbutil.MessageIndex) {
@@ -356,6 +427,13 @@ func (n *ExecutionNode) Start(ctx context.Context) error {
 	if n.ParentChainReader != nil {
 		n.ParentChainReader.Start(ctx)
 	}
+	if n.CompactionScheduler != nil {
+		n.CompactionScheduler.Start(ctx)
+	}
+	if n.Checkpointer != nil {
+		n.Checkpointer.Start(ctx)
+	}
+	n.ExecEngine.CallIteratively(n.checkConsistencyIteratively)
 	return nil
 }
 
@@ -369,6 +447,12 @@ func (n *ExecutionNode) StopAndWait() {
 		n.TxPublisher.StopAndWait()
 	}
 	n.Recorder.OrderlyShutdown()
+	if n.CompactionScheduler != nil && n.CompactionScheduler.Started() {
+		n.CompactionScheduler.StopAndWait()
+	}
+	if n.Checkpointer != nil && n.Checkpointer.Started() {
+		n.Checkpointer.StopAndWait()
+	}
 	if n.ParentChainReader != nil && n.ParentChainReader.Started() {
 		n.ParentChainReader.StopAndWait()
 	}
@@ -410,6 +494,26 @@ func (n *ExecutionNode) ArbOSVersionForMessageNumber(messageNum arbutil.MessageI
 	return n.ExecEngine.ArbOSVersionForMessageNumber(messageNum)
 }
 
+func (n *ExecutionNode) BrotliCompressionLevel() (uint64, error) {
+	return n.ExecEngine.BrotliCompressionLevel()
+}
+
+func (n *ExecutionNode) ActiveCompressionDictionary() (uint8, common.Hash, error) {
+	return n.ExecEngine.ActiveCompressionDictionary()
+}
+
+func (n *ExecutionNode) L1TxBatchCompressionDisabled() (bool, error) {
+	return n.ExecEngine.L1TxBatchCompressionDisabled()
+}
+
+func (n *ExecutionNode) SubscribeChainParameterChanges(ch chan<- execution.ChainParameterChange) event.Subscription {
+	return n.ExecEngine.SubscribeChainParameterChanges(ch)
+}
+
+func (n *ExecutionNode) SubscribeL1PricingUpdates(ch chan<- execution.L1PricingUpdateTrace) event.Subscription {
+	return n.ExecEngine.SubscribeL1PricingUpdates(ch)
+}
+
 func (n *ExecutionNode) RecordBlockCreation(
 	ctx context.Context,
 	pos arbutil.MessageIndex,
@@ -431,6 +535,10 @@ func (n *ExecutionNode) Pause() {
 }
 
 func (n *ExecutionNode) Activate() {
+	if n.ExecEngine.safeMode.deferActivate() {
+		log.Warn("not activating sequencer while in safe mode")
+		return
+	}
 	if n.Sequencer != nil {
 		n.Sequencer.Activate()
 	}
@@ -0,0 +1,161 @@
+// Copyright 2024, Offchain Labs, Inc.
+// For license information, see https://github.com/OffchainLabs/nitro/blob/master/LICENSE
+
+package gethexec
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/tracing"
+	"github.com/ethereum/go-ethereum/eth/tracers"
+
+	"github.com/offchainlabs/nitro/gethhook"
+)
+
+const (
+	precompileTracerName = "arbPrecompileTracer"
+	// the tracer arb_traceTransaction runs alongside the precompile tracer when none is given
+	defaultArbTraceTracer = "callTracer"
+)
+
+func init() {
+	tracers.DefaultDirectory.Register(precompileTracerName, newPrecompileTracer, false)
+}
+
+// PrecompileCallTrace is a call to an ArbOS precompile, which EVM traces only show as a black box.
+type PrecompileCallTrace struct {
+	// Type is always arbPrecompileCall, to tell these entries apart from those of other traces.
+	Type string `json:"type"`
+
+	Address common.Address `json:"address"`
+
+	// Precompile is the name of the precompile, like ArbGasInfo.
+	Precompile string `json:"precompile"`
+
+	// Method is the solidity name of the method called, or empty if the calldata didn't select one.
+	Method string `json:"method"`
+
+	Input hexutil.Bytes `json:"input"`
+
+	// GasCharged is the gas the call used, including that of the arguments and results.
+	GasCharged uint64 `json:"gasCharged"`
+
+	// ReturnValue is the abi encoded result, or the revert data if the call reverted.
+	ReturnValue hexutil.Bytes `json:"returnValue"`
+
+	Reverted bool `json:"reverted"`
+}
+
+// precompileTracer captures the calls made to ArbOS precompiles.
+type precompileTracer struct {
+	calls []*PrecompileCallTrace
+	// for each open call frame, the precompile call it traces, or nil if it isn't one
+	frames []*PrecompileCallTrace
+}
+
+func newPrecompileTracer(ctx *tracers.Context, _ json.RawMessage) (*tracers.Tracer, error) {
+	t := &precompileTracer{calls: []*PrecompileCallTrace{}}
+	return &tracers.Tracer{
+		Hooks: &tracing.Hooks{
+			OnEnter: t.OnEnter,
+			OnExit:  t.OnExit,
+		},
+		GetResult: t.GetResult,
+		Stop:      func(error) {},
+	}, nil
+}
+
+func (t *precompileTracer) OnEnter(depth int, typ byte, from common.Address, to common.Address, input []byte, gas uint64, value *big.Int) {
+	precompile, ok := gethhook.ArbosPrecompile(to)
+	if !ok {
+		t.frames = append(t.frames, nil)
+		return
+	}
+	method, _ := precompile.Precompile().MethodName(input)
+	call := &PrecompileCallTrace{
+		Type:       "arbPrecompileCall",
+		Address:    to,
+		Precompile: precompile.Precompile().Name(),
+		Method:     method,
+		Input:      common.CopyBytes(input),
+	}
+	t.calls = append(t.calls, call)
+	t.frames = append(t.frames, call)
+}
+
+func (t *precompileTracer) OnExit(depth int, output []byte, gasUsed uint64, _ error, reverted bool) {
+	if len(t.frames) == 0 {
+		return
+	}
+	call := t.frames[len(t.frames)-1]
+	t.frames = t.frames[:len(t.frames)-1]
+	if call != nil {
+		call.GasCharged = gasUsed
+		call.ReturnValue = common.CopyBytes(output)
+		call.Reverted = reverted
+	}
+}
+
+func (t *precompileTracer) GetResult() (json.RawMessage, error) {
+	return json.Marshal(t.calls)
+}
+
+// TransactionTrace is a standard trace of a transaction, along with the precompile calls it made.
+type TransactionTrace struct {
+	Trace              json.RawMessage        `json:"trace"`
+	ArbPrecompileCalls []*PrecompileCallTrace `json:"arbPrecompileCalls"`
+}
+
+type ArbTraceAPI struct {
+	tracers *tracers.API
+}
+
+func NewArbTraceAPI(backend tracers.Backend) *ArbTraceAPI {
+	return &ArbTraceAPI{tracers.NewAPI(backend)}
+}
+
+// TraceTransaction traces a transaction with the given tracer, defaulting to the call tracer,
+// and extends the trace with the calls the transaction made to ArbOS precompiles.
+func (api *ArbTraceAPI) TraceTransaction(ctx context.Context, hash common.Hash, tracer *string) (*TransactionTrace, error) {
+	name := defaultArbTraceTracer
+	if tracer != nil && *tracer != "" {
+		name = *tracer
+	}
+	if name == precompileTracerName {
+		return nil, fmt.Errorf("%v is already included in the trace", precompileTracerName)
+	}
+	muxConfig, err := json.Marshal(map[string]json.RawMessage{
+		name:                 json.RawMessage("{}"),
+		precompileTracerName: json.RawMessage("{}"),
+	})
+	if err != nil {
+		return nil, err
+	}
+	muxTracer := "muxTracer"
+	result, err := api.tracers.TraceTransaction(ctx, hash, &tracers.TraceConfig{
+		Tracer:       &muxTracer,
+		TracerConfig: muxConfig,
+	})
+	if err != nil {
+		return nil, err
+	}
+	raw, ok := result.(json.RawMessage)
+	if !ok {
+		return nil, errors.New("unexpected trace result type")
+	}
+	var traces map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &traces); err != nil {
+		return nil, err
+	}
+	trace := &TransactionTrace{Trace: traces[name]}
+	if err := json.Unmarshal(traces[precompileTracerName], &trace.ArbPrecompileCalls); err != nil {
+		return nil, err
+	}
+	return trace, nil
+}
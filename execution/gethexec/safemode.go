@@ -0,0 +1,229 @@
+// Copyright 2024, Offchain Labs, Inc.
+// For license information, see https://github.com/nitro/blob/master/LICENSE
+
+package gethexec
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/node"
+)
+
+var ErrSafeMode = errors.New("node is in safe mode after a failed consistency check, not accepting transactions")
+
+// SafeModeStatus describes why the node entered safe mode.
+type SafeModeStatus struct {
+	Active bool       `json:"active"`
+	Reason string     `json:"reason,omitempty"`
+	Since  *time.Time `json:"since,omitempty"`
+}
+
+// safeMode is entered when the node suspects its database is corrupt. While it's
+// active, reads are still served but transactions are rejected and sequencing
+// stops, until an admin acknowledges it and the consistency check passes again.
+type safeMode struct {
+	mutex  sync.Mutex
+	active bool
+	reason error
+	since  time.Time
+	// whether the sequencer should be activated when leaving safe mode
+	resumeSequencer bool
+}
+
+func (m *safeMode) enter(reason error, sequencerActive bool) bool {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	if m.active {
+		return false
+	}
+	m.active = true
+	m.reason = reason
+	m.since = time.Now()
+	m.resumeSequencer = sequencerActive
+	return true
+}
+
+// exit leaves safe mode, returning whether the sequencer should be activated.
+func (m *safeMode) exit() bool {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.active = false
+	m.reason = nil
+	return m.resumeSequencer
+}
+
+// deferActivate returns true if the sequencer can't be activated because of
+// safe mode, in which case it will be activated when leaving safe mode.
+func (m *safeMode) deferActivate() bool {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	if m.active {
+		m.resumeSequencer = true
+	}
+	return m.active
+}
+
+func (m *safeMode) Active() bool {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	return m.active
+}
+
+func (m *safeMode) Status() SafeModeStatus {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	if !m.active {
+		return SafeModeStatus{}
+	}
+	since := m.since
+	return SafeModeStatus{
+		Active: true,
+		Reason: m.reason.Error(),
+		Since:  &since,
+	}
+}
+
+// maxConsistencyRepairBlocks bounds how far back RepairConsistency looks for a consistent block.
+const maxConsistencyRepairBlocks = 1024
+
+// CheckConsistency checks that the head block is canonical and its state is available.
+func (s *ExecutionEngine) CheckConsistency() error {
+	s.createBlocksMutex.Lock()
+	defer s.createBlocksMutex.Unlock()
+	head := s.bc.CurrentBlock()
+	if head == nil {
+		return errors.New("no head block")
+	}
+	return s.checkBlockConsistency(head)
+}
+
+func (s *ExecutionEngine) checkBlockConsistency(header *types.Header) error {
+	num := header.Number.Uint64()
+	if canonical := s.bc.GetCanonicalHash(num); canonical != header.Hash() {
+		return fmt.Errorf("block %v hash %v doesn't match canonical hash %v", num, header.Hash(), canonical)
+	}
+	if num > 0 && s.bc.GetHeader(header.ParentHash, num-1) == nil {
+		return fmt.Errorf("parent %v of block %v is missing", header.ParentHash, num)
+	}
+	if _, err := s.bc.StateAt(header.Root); err != nil {
+		return fmt.Errorf("state of block %v is unavailable: %w", num, err)
+	}
+	return nil
+}
+
+func (s *ExecutionEngine) SafeModeStatus() SafeModeStatus {
+	return s.safeMode.Status()
+}
+
+// EnterSafeMode puts the node into safe mode, stopping the sequencer and
+// rejecting transactions while still serving reads.
+func (n *ExecutionNode) EnterSafeMode(reason error) {
+	sequencerActive := false
+	if n.Sequencer != nil {
+		pauseChan, forwarder := n.Sequencer.GetPauseAndForwarder()
+		sequencerActive = pauseChan == nil && forwarder == nil
+	}
+	if !n.ExecEngine.safeMode.enter(reason, sequencerActive) {
+		return
+	}
+	log.Error("entering safe mode, transactions will be rejected until acknowledged by an admin", "reason", reason)
+	n.Pause()
+}
+
+// ExitSafeMode reruns the consistency check, and if it fails tries to repair the chain by
+// rolling it back to the latest consistent block. Once the check passes it leaves safe mode
+// and resumes sequencing.
+func (n *ExecutionNode) ExitSafeMode() error {
+	if !n.ExecEngine.safeMode.Active() {
+		return nil
+	}
+	if err := n.ExecEngine.CheckConsistency(); err != nil {
+		log.Warn("consistency check still failing, attempting repair", "err", err)
+		if err := n.ExecEngine.RepairConsistency(); err != nil {
+			return fmt.Errorf("failed to repair consistency: %w", err)
+		}
+		if err := n.ExecEngine.CheckConsistency(); err != nil {
+			return fmt.Errorf("consistency check still failing after repair: %w", err)
+		}
+	}
+	resumeSequencer := n.ExecEngine.safeMode.exit()
+	log.Warn("leaving safe mode after admin acknowledgement")
+	if resumeSequencer {
+		n.Activate()
+	}
+	return nil
+}
+
+// CheckConsistency runs the execution engine's consistency check, entering safe mode if it fails.
+func (n *ExecutionNode) CheckConsistency() error {
+	err := n.ExecEngine.CheckConsistency()
+	if err != nil {
+		n.EnterSafeMode(err)
+	}
+	return err
+}
+
+// checkConsistencyIteratively runs the consistency check at startup and then every
+// SafeModeCheckInterval, as the database can also be corrupted while the node runs,
+// e.g. by a failing disk. It's skipped while already in safe mode.
+func (n *ExecutionNode) checkConsistencyIteratively(ctx context.Context) time.Duration {
+	if !n.ExecEngine.safeMode.Active() {
+		// a failed check enters safe mode, which logs why
+		_ = n.CheckConsistency()
+	}
+	return n.ConfigFetcher().SafeModeCheckInterval
+}
+
+// SafeModeHeader is set on HTTP RPC responses while the node is in safe mode, as reads are
+// then served from a database that failed its consistency check.
+const SafeModeHeader = "X-Nitro-Health"
+
+type safeModeHTTPHandler struct {
+	inner    http.Handler
+	safeMode *safeMode
+}
+
+func (h *safeModeHTTPHandler) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if h.safeMode.Active() {
+		w.Header().Set(SafeModeHeader, "degraded")
+	}
+	h.inner.ServeHTTP(w, req)
+}
+
+// MarkHTTPResponsesInSafeMode chains onto go-ethereum's node.WrapHTTPHandler hook, so the HTTP
+// RPC responses of stacks started afterwards carry SafeModeHeader while this node is in safe mode.
+// As the hook is global, it's meant for processes running a single execution node.
+func (n *ExecutionNode) MarkHTTPResponsesInSafeMode() {
+	wrap := node.WrapHTTPHandler
+	node.WrapHTTPHandler = func(srv http.Handler) (http.Handler, error) {
+		if wrap != nil {
+			var err error
+			srv, err = wrap(srv)
+			if err != nil {
+				return nil, err
+			}
+		}
+		return &safeModeHTTPHandler{srv, &n.ExecEngine.safeMode}, nil
+	}
+}
+
+type ArbAdminAPI struct {
+	execNode *ExecutionNode
+}
+
+func NewArbAdminAPI(execNode *ExecutionNode) *ArbAdminAPI {
+	return &ArbAdminAPI{execNode}
+}
+
+// AcknowledgeSafeMode reruns the consistency check, repairing the chain if it fails, and resumes
+// normal operation once it passes.
+func (a *ArbAdminAPI) AcknowledgeSafeMode(ctx context.Context) error {
+	return a.execNode.ExitSafeMode()
+}
@@ -0,0 +1,38 @@
+// Copyright 2024, Offchain Labs, Inc.
+// For license information, see https://github.com/nitro/blob/master/LICENSE
+
+package gethexec
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestSafeModeHTTPHandler(t *testing.T) {
+	var mode safeMode
+	handler := &safeModeHTTPHandler{
+		inner: http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			w.WriteHeader(http.StatusOK)
+		}),
+		safeMode: &mode,
+	}
+	serve := func() *httptest.ResponseRecorder {
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, "/", nil))
+		return recorder
+	}
+
+	if header := serve().Header().Get(SafeModeHeader); header != "" {
+		t.Errorf("expected no %v header outside safe mode, got %q", SafeModeHeader, header)
+	}
+	mode.enter(errors.New("simulated database corruption"), false)
+	if header := serve().Header().Get(SafeModeHeader); header != "degraded" {
+		t.Errorf("expected %v header to be degraded in safe mode, got %q", SafeModeHeader, header)
+	}
+	mode.exit()
+	if header := serve().Header().Get(SafeModeHeader); header != "" {
+		t.Errorf("expected no %v header after leaving safe mode, got %q", SafeModeHeader, header)
+	}
+}
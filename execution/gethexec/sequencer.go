@@ -62,21 +62,22 @@ var (
 )
 
 type SequencerConfig struct {
-	Enable                       bool            `koanf:"enable"`
-	MaxBlockSpeed                time.Duration   `koanf:"max-block-speed" reload:"hot"`
-	MaxRevertGasReject           uint64          `koanf:"max-revert-gas-reject" reload:"hot"`
-	MaxAcceptableTimestampDelta  time.Duration   `koanf:"max-acceptable-timestamp-delta" reload:"hot"`
-	SenderWhitelist              []string        `koanf:"sender-whitelist"`
-	Forwarder                    ForwarderConfig `koanf:"forwarder"`
-	QueueSize                    int             `koanf:"queue-size"`
-	QueueTimeout                 time.Duration   `koanf:"queue-timeout" reload:"hot"`
-	NonceCacheSize               int             `koanf:"nonce-cache-size" reload:"hot"`
-	MaxTxDataSize                int             `koanf:"max-tx-data-size" reload:"hot"`
-	NonceFailureCacheSize        int             `koanf:"nonce-failure-cache-size" reload:"hot"`
-	NonceFailureCacheExpiry      time.Duration   `koanf:"nonce-failure-cache-expiry" reload:"hot"`
-	ExpectedSurplusSoftThreshold string          `koanf:"expected-surplus-soft-threshold" reload:"hot"`
-	ExpectedSurplusHardThreshold string          `koanf:"expected-surplus-hard-threshold" reload:"hot"`
-	EnableProfiling              bool            `koanf:"enable-profiling" reload:"hot"`
+	Enable                       bool              `koanf:"enable"`
+	MaxBlockSpeed                time.Duration     `koanf:"max-block-speed" reload:"hot"`
+	MaxRevertGasReject           uint64            `koanf:"max-revert-gas-reject" reload:"hot"`
+	MaxAcceptableTimestampDelta  time.Duration     `koanf:"max-acceptable-timestamp-delta" reload:"hot"`
+	SenderWhitelist              []string          `koanf:"sender-whitelist"`
+	Forwarder                    ForwarderConfig   `koanf:"forwarder"`
+	QueueSize                    int               `koanf:"queue-size"`
+	QueueTimeout                 time.Duration     `koanf:"queue-timeout" reload:"hot"`
+	NonceCacheSize               int               `koanf:"nonce-cache-size" reload:"hot"`
+	MaxTxDataSize                int               `koanf:"max-tx-data-size" reload:"hot"`
+	NonceFailureCacheSize        int               `koanf:"nonce-failure-cache-size" reload:"hot"`
+	NonceFailureCacheExpiry      time.Duration     `koanf:"nonce-failure-cache-expiry" reload:"hot"`
+	ExpectedSurplusSoftThreshold string            `koanf:"expected-surplus-soft-threshold" reload:"hot"`
+	ExpectedSurplusHardThreshold string            `koanf:"expected-surplus-hard-threshold" reload:"hot"`
+	EnableProfiling              bool              `koanf:"enable-profiling" reload:"hot"`
+	WarmStandby                  WarmStandbyConfig `koanf:"warm-standby"`
 	expectedSurplusSoftThreshold int
 	expectedSurplusHardThreshold int
 }
@@ -107,6 +108,9 @@ func (c *SequencerConfig) Validate() error {
 	if c.MaxTxDataSize > arbostypes.MaxL2MessageSize-50000 {
 		return errors.New("max-tx-data-size too large for MaxL2MessageSize")
 	}
+	if c.WarmStandby.Enable && c.WarmStandby.RelaySize <= 0 {
+		return errors.New("warm-standby.relay-size must be positive when warm standby is enabled")
+	}
 	return nil
 }
 
@@ -130,6 +134,7 @@ var DefaultSequencerConfig = SequencerConfig{
 	ExpectedSurplusSoftThreshold: "default",
 	ExpectedSurplusHardThreshold: "default",
 	EnableProfiling:              false,
+	WarmStandby:                  DefaultWarmStandbyConfig,
 }
 
 func SequencerConfigAddOptions(prefix string, f *flag.FlagSet) {
@@ -148,6 +153,7 @@ func SequencerConfigAddOptions(prefix string, f *flag.FlagSet) {
 	f.String(prefix+".expected-surplus-soft-threshold", DefaultSequencerConfig.ExpectedSurplusSoftThreshold, "if expected surplus is lower than this value, warnings are posted")
 	f.String(prefix+".expected-surplus-hard-threshold", DefaultSequencerConfig.ExpectedSurplusHardThreshold, "if expected surplus is lower than this value, new incoming transactions will be denied")
 	f.Bool(prefix+".enable-profiling", DefaultSequencerConfig.EnableProfiling, "enable CPU profiling and tracing")
+	WarmStandbyConfigAddOptions(prefix+".warm-standby", f)
 }
 
 type txQueueItem struct {
@@ -316,6 +322,10 @@ type Sequencer struct {
 	expectedSurplusMutex   sync.RWMutex
 	expectedSurplus        int64
 	expectedSurplusUpdated bool
+
+	// queueRelay and warmStandby are nil unless warm standby is enabled
+	queueRelay  *queueRelay
+	warmStandby *warmStandby
 }
 
 func NewSequencer(execEngine *ExecutionEngine, l1Reader *headerreader.HeaderReader, configFetcher SequencerConfigFetcher) (*Sequencer, error) {
@@ -345,6 +355,10 @@ func NewSequencer(execEngine *ExecutionEngine, l1Reader *headerreader.HeaderRead
 		containers.NewLruCacheWithOnEvict(config.NonceCacheSize, s.onNonceFailureEvict),
 		func() time.Duration { return configFetcher().NonceFailureCacheExpiry },
 	}
+	if config.WarmStandby.Enable {
+		s.queueRelay = newQueueRelay(config.WarmStandby.RelaySize)
+		s.warmStandby = newWarmStandby(s)
+	}
 	s.Pause()
 	execEngine.EnableReorgSequencing()
 	return s, nil
@@ -454,6 +468,9 @@ func (s *Sequencer) PublishTransaction(parentCtx context.Context, tx *types.Tran
 	case <-queueCtx.Done():
 		return queueCtx.Err()
 	}
+	if s.queueRelay != nil {
+		s.queueRelay.add(txBytes)
+	}
 
 	select {
 	case res := <-resultChan:
@@ -1128,10 +1145,26 @@ func (s *Sequencer) Start(ctxIn context.Context) error {
 		return 0
 	})
 
+	if s.warmStandby != nil {
+		s.warmStandby.Start(ctxIn)
+	}
+
 	return nil
 }
 
+// WarmStandbyState returns how many transactions relayed from the chosen sequencer are pending,
+// and how many of those are executed speculatively. Both are zero if warm standby is disabled.
+func (s *Sequencer) WarmStandbyState() (pending int, executed int) {
+	if s.warmStandby == nil {
+		return 0, 0
+	}
+	return s.warmStandby.SpeculativeState()
+}
+
 func (s *Sequencer) StopAndWait() {
+	if s.warmStandby != nil {
+		s.warmStandby.StopAndWait()
+	}
 	s.StopWaiter.StopAndWait()
 	if s.txRetryQueue.Len() == 0 && len(s.txQueue) == 0 && s.nonceFailures.Len() == 0 {
 		return
@@ -20,6 +20,7 @@ import (
 
 	"github.com/offchainlabs/nitro/arbos/arbosState"
 	"github.com/offchainlabs/nitro/arbos/l1pricing"
+	"github.com/offchainlabs/nitro/arbos/util"
 	"github.com/offchainlabs/nitro/util/arbmath"
 	"github.com/offchainlabs/nitro/util/headerreader"
 )
@@ -134,7 +135,7 @@ func PreCheckTx(bc *core.BlockChain, chainConfig *params.ChainConfig, header *ty
 			return err
 		}
 	}
-	if arbmath.BigLessThan(tx.GasFeeCap(), baseFee) {
+	if arbmath.BigLessThan(tx.GasFeeCap(), baseFee) && !sponsoredByGasPaymaster(arbos, tx, sender) {
 		return fmt.Errorf("%w: address %v, maxFeePerGas: %s baseFee: %s", core.ErrFeeCapTooLow, sender, tx.GasFeeCap(), header.BaseFee)
 	}
 	stateNonce := statedb.GetNonce(sender)
@@ -208,6 +209,20 @@ func PreCheckTx(bc *core.BlockChain, chainConfig *params.ChainConfig, header *ty
 	return nil
 }
 
+// sponsoredByGasPaymaster returns whether the gas paymaster will try to pay for a transaction offering no fee,
+// which it only does for the senders it sponsors. Whether it has the funds to do so is left to the state transition.
+func sponsoredByGasPaymaster(arbos *arbosState.ArbosState, tx *types.Transaction, sender common.Address) bool {
+	if arbos.ArbOSVersion() < util.ArbosVersion_40 || tx.GasFeeCap().Sign() != 0 || tx.GasTipCap().Sign() != 0 {
+		return false
+	}
+	paymaster, err := arbos.GasPaymaster()
+	if err != nil || paymaster == (common.Address{}) {
+		return false
+	}
+	sponsored, err := arbos.GasPaymasterSponsoredSenders().IsMember(sender)
+	return err == nil && sponsored
+}
+
 func (c *TxPreChecker) PublishTransaction(ctx context.Context, tx *types.Transaction, options *arbitrum_types.ConditionalOptions) error {
 	block := c.bc.CurrentBlock()
 	statedb, err := c.bc.StateAt(block.Root)
@@ -0,0 +1,403 @@
+// Copyright 2024, Offchain Labs, Inc.
+// For license information, see https://github.com/nitro/blob/master/LICENSE
+
+package gethexec
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	flag "github.com/spf13/pflag"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/metrics"
+	"github.com/ethereum/go-ethereum/rpc"
+
+	"github.com/offchainlabs/nitro/arbos"
+	"github.com/offchainlabs/nitro/arbos/arbostypes"
+	"github.com/offchainlabs/nitro/arbos/l1pricing"
+	"github.com/offchainlabs/nitro/util/arbmath"
+	"github.com/offchainlabs/nitro/util/stopwaiter"
+)
+
+var (
+	warmStandbyRelayedCounter    = metrics.NewRegisteredCounter("arb/sequencer/warmstandby/relayed", nil)
+	warmStandbyMissedCounter     = metrics.NewRegisteredCounter("arb/sequencer/warmstandby/missed", nil)
+	warmStandbyMismatchCounter   = metrics.NewRegisteredCounter("arb/sequencer/warmstandby/mismatch", nil)
+	warmStandbyPendingGauge      = metrics.NewRegisteredGauge("arb/sequencer/warmstandby/pending", nil)
+	warmStandbyExecutionTimer    = metrics.NewRegisteredTimer("arb/sequencer/warmstandby/execution", nil)
+	warmStandbyPollFailedCounter = metrics.NewRegisteredCounter("arb/sequencer/warmstandby/pollfailed", nil)
+)
+
+type WarmStandbyConfig struct {
+	Enable       bool          `koanf:"enable"`
+	RelaySize    int           `koanf:"relay-size"`
+	MaxPending   int           `koanf:"max-pending" reload:"hot"`
+	PollInterval time.Duration `koanf:"poll-interval" reload:"hot"`
+	PollTimeout  time.Duration `koanf:"poll-timeout" reload:"hot"`
+}
+
+var DefaultWarmStandbyConfig = WarmStandbyConfig{
+	Enable:       false,
+	RelaySize:    4096,
+	MaxPending:   1024,
+	PollInterval: 50 * time.Millisecond,
+	PollTimeout:  time.Second,
+}
+
+func WarmStandbyConfigAddOptions(prefix string, f *flag.FlagSet) {
+	f.Bool(prefix+".enable", DefaultWarmStandbyConfig.Enable, "while not the chosen sequencer, speculatively execute the chosen sequencer's queue to keep the state cache warm for a failover (the chosen sequencer must serve the arbsequencer RPC namespace to its forwarders)")
+	f.Int(prefix+".relay-size", DefaultWarmStandbyConfig.RelaySize, "number of recently queued transactions kept for standby sequencers")
+	f.Int(prefix+".max-pending", DefaultWarmStandbyConfig.MaxPending, "maximum number of relayed transactions to speculatively execute")
+	f.Duration(prefix+".poll-interval", DefaultWarmStandbyConfig.PollInterval, "how often to poll the chosen sequencer for newly queued transactions")
+	f.Duration(prefix+".poll-timeout", DefaultWarmStandbyConfig.PollTimeout, "timeout for polling the chosen sequencer for newly queued transactions")
+}
+
+// QueuedTransactions is a page of the transactions a sequencer queued, as relayed to standby sequencers.
+type QueuedTransactions struct {
+	// Epoch identifies the relay, and changes if the sequencer restarts
+	Epoch hexutil.Uint64 `json:"epoch"`
+	// First is the index of the first transaction returned, which is later than requested if some were dropped
+	First        hexutil.Uint64  `json:"first"`
+	Transactions []hexutil.Bytes `json:"transactions"`
+}
+
+// queueRelay keeps the transactions most recently accepted into the sequencer's queue.
+type queueRelay struct {
+	mutex sync.Mutex
+	epoch uint64
+	// index of the first transaction in txs
+	first uint64
+	txs   [][]byte
+	size  int
+}
+
+func newQueueRelay(size int) *queueRelay {
+	return &queueRelay{
+		epoch: uint64(time.Now().UnixNano()),
+		size:  size,
+	}
+}
+
+func (r *queueRelay) add(txBytes []byte) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.txs = append(r.txs, txBytes)
+	if excess := len(r.txs) - r.size; excess > 0 {
+		r.txs = r.txs[excess:]
+		r.first += uint64(excess)
+	}
+}
+
+func (r *queueRelay) since(index uint64, limit int) *QueuedTransactions {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	end := r.first + uint64(len(r.txs))
+	index = arbmath.MinInt(arbmath.MaxInt(index, r.first), end)
+	start := index - r.first
+	count := arbmath.MinInt(uint64(len(r.txs))-start, uint64(limit))
+	result := &QueuedTransactions{
+		Epoch:        hexutil.Uint64(r.epoch),
+		First:        hexutil.Uint64(index),
+		Transactions: make([]hexutil.Bytes, 0, count),
+	}
+	for _, txBytes := range r.txs[start : start+count] {
+		result.Transactions = append(result.Transactions, txBytes)
+	}
+	return result
+}
+
+// ArbSequencerAPI serves the sequencer's queue to standby sequencers. It should only be exposed to them.
+type ArbSequencerAPI struct {
+	sequencer *Sequencer
+}
+
+func NewArbSequencerAPI(sequencer *Sequencer) *ArbSequencerAPI {
+	return &ArbSequencerAPI{sequencer}
+}
+
+const maxQueuedTransactionsPerRequest = 1024
+
+// QueuedTransactions returns the transactions queued since the given index.
+func (a *ArbSequencerAPI) QueuedTransactions(ctx context.Context, index hexutil.Uint64) (*QueuedTransactions, error) {
+	if a.sequencer.queueRelay == nil {
+		return nil, errors.New("warm standby relay not enabled")
+	}
+	return a.sequencer.queueRelay.since(uint64(index), maxQueuedTransactionsPerRequest), nil
+}
+
+// speculativeBlock is the result of executing the relayed transactions on top of a block.
+type speculativeBlock struct {
+	parent  common.Hash
+	txes    types.Transactions
+	statedb *state.StateDB
+}
+
+// warmStandby runs on a sequencer that isn't chosen. It mirrors the chosen sequencer's queue and keeps a
+// speculative block executing it, so the state a failover will need is already cached.
+type warmStandby struct {
+	stopwaiter.StopWaiter
+	sequencer *Sequencer
+	config    func() *WarmStandbyConfig
+
+	client    *rpc.Client
+	clientUrl string
+	epoch     uint64
+	next      uint64
+	// the latest block seen, used to find what was sequenced since
+	head common.Hash
+	// relayed transactions not yet seen in a block, in queue order
+	pending     types.Transactions
+	speculative *speculativeBlock
+
+	// for observing the standby from other threads
+	pendingCount  atomic.Int64
+	executedCount atomic.Int64
+}
+
+func newWarmStandby(sequencer *Sequencer) *warmStandby {
+	return &warmStandby{
+		sequencer: sequencer,
+		config:    func() *WarmStandbyConfig { return &sequencer.config().WarmStandby },
+	}
+}
+
+func (w *warmStandby) Start(ctxIn context.Context) {
+	w.StopWaiter.Start(ctxIn, w)
+	w.CallIteratively(w.update)
+}
+
+func (w *warmStandby) StopAndWait() {
+	w.StopWaiter.StopAndWait()
+	w.reset()
+}
+
+// reset discards the relayed queue and any speculative state
+func (w *warmStandby) reset() {
+	if w.client != nil {
+		w.client.Close()
+		w.client = nil
+	}
+	w.clientUrl = ""
+	w.epoch = 0
+	w.next = 0
+	w.head = common.Hash{}
+	w.pending = nil
+	w.speculative = nil
+	w.publishState()
+}
+
+func (w *warmStandby) publishState() {
+	executed := 0
+	if w.speculative != nil {
+		executed = len(w.speculative.txes)
+	}
+	w.pendingCount.Store(int64(len(w.pending)))
+	w.executedCount.Store(int64(executed))
+	warmStandbyPendingGauge.Update(int64(len(w.pending)))
+}
+
+func (w *warmStandby) update(ctx context.Context) time.Duration {
+	config := w.config()
+	target := w.sequencer.ForwardTarget()
+	if target == "" {
+		// we're either the chosen sequencer or don't know who is
+		if w.clientUrl != "" || w.pending != nil {
+			w.reset()
+		}
+		return config.PollInterval
+	}
+	if target != w.clientUrl {
+		w.reset()
+		client, err := rpc.DialContext(ctx, target)
+		if err != nil {
+			log.Warn("warm standby failed to connect to the chosen sequencer", "url", target, "err", err)
+			return config.PollInterval
+		}
+		w.client = client
+		w.clientUrl = target
+	}
+	if err := w.poll(ctx, config); err != nil {
+		warmStandbyPollFailedCounter.Inc(1)
+		log.Debug("warm standby failed to poll the chosen sequencer's queue", "url", target, "err", err)
+	}
+	if err := w.speculate(); err != nil {
+		log.Warn("warm standby failed to execute the relayed queue", "err", err)
+		w.speculative = nil
+	}
+	w.publishState()
+	return config.PollInterval
+}
+
+// poll fetches the transactions the chosen sequencer queued since the last poll
+func (w *warmStandby) poll(ctx context.Context, config *WarmStandbyConfig) error {
+	ctx, cancel := ctxWithTimeout(ctx, config.PollTimeout)
+	defer cancel()
+	var queued QueuedTransactions
+	if err := w.client.CallContext(ctx, &queued, "arbsequencer_queuedTransactions", hexutil.Uint64(w.next)); err != nil {
+		return err
+	}
+	if uint64(queued.Epoch) != w.epoch {
+		// the chosen sequencer restarted, so its queue was lost
+		w.epoch = uint64(queued.Epoch)
+		w.pending = nil
+		w.speculative = nil
+	} else if uint64(queued.First) > w.next {
+		warmStandbyMissedCounter.Inc(int64(uint64(queued.First) - w.next))
+	}
+	for _, txBytes := range queued.Transactions {
+		var tx types.Transaction
+		if err := tx.UnmarshalBinary(txBytes); err != nil {
+			return fmt.Errorf("failed to decode relayed transaction: %w", err)
+		}
+		w.pending = append(w.pending, &tx)
+	}
+	w.next = uint64(queued.First) + uint64(len(queued.Transactions))
+	warmStandbyRelayedCounter.Inc(int64(len(queued.Transactions)))
+	if excess := len(w.pending) - config.MaxPending; excess > 0 {
+		w.pending = w.pending[excess:]
+		w.speculative = nil
+	}
+	return nil
+}
+
+// speculate makes sure the speculative block executes the pending queue on top of the latest block
+func (w *warmStandby) speculate() error {
+	bc := w.sequencer.execEngine.bc
+	head := bc.CurrentBlock()
+	if head == nil {
+		return errors.New("failed to get current block")
+	}
+	if w.head != (common.Hash{}) && w.head != head.Hash() {
+		var speculated types.Transactions
+		if w.speculative != nil {
+			speculated = w.speculative.txes
+		}
+		sequenced := w.sequencedSince(w.head, head)
+		var matched bool
+		w.pending, matched = pruneSequenced(w.pending, speculated, sequenced)
+		if !matched {
+			// the chosen sequencer sequenced something other than we speculated
+			warmStandbyMismatchCounter.Inc(1)
+		}
+		w.speculative = nil
+	}
+	w.head = head.Hash()
+	if len(w.pending) == 0 {
+		w.speculative = nil
+		return nil
+	}
+	if w.speculative != nil && len(w.speculative.txes) == len(w.pending) {
+		return nil
+	}
+
+	statedb, err := bc.StateAt(head.Root)
+	if err != nil {
+		return err
+	}
+	statedb.StartPrefetcher("WarmStandby")
+	defer statedb.StopPrefetcher()
+	w.sequencer.L1BlockAndTimeMutex.Lock()
+	l1Block := w.sequencer.l1BlockNumber.Load()
+	w.sequencer.L1BlockAndTimeMutex.Unlock()
+	header := &arbostypes.L1IncomingMessageHeader{
+		Kind:        arbostypes.L1MessageType_L2Message,
+		Poster:      l1pricing.BatchPosterAddress,
+		BlockNumber: l1Block,
+		Timestamp:   arbmath.SaturatingUCast[uint64](time.Now().Unix()),
+	}
+	hooks := arbos.NoopSequencingHooks()
+	start := time.Now()
+	_, _, err = arbos.ProduceBlockAdvanced(header, w.pending, head.Nonce.Uint64(), head, statedb, bc, bc.Config(), hooks, true, core.MessageReplayMode)
+	if err != nil {
+		return err
+	}
+	warmStandbyExecutionTimer.Update(time.Since(start))
+
+	// transactions that can never be included won't be seen in a block, so stop executing them
+	var executable types.Transactions
+	for i, tx := range w.pending {
+		if i < len(hooks.TxErrors) && hooks.TxErrors[i] != nil && !errors.Is(hooks.TxErrors[i], core.ErrNonceTooHigh) {
+			continue
+		}
+		executable = append(executable, tx)
+	}
+	w.pending = executable
+	w.speculative = &speculativeBlock{
+		parent:  head.Hash(),
+		txes:    executable,
+		statedb: statedb,
+	}
+	return nil
+}
+
+// maxSpeculativeReorgDepth limits how far back sequencedSince looks for the speculative block's parent
+const maxSpeculativeReorgDepth = 64
+
+// sequencedSince returns the user transactions in the blocks after parent up to head, in order
+func (w *warmStandby) sequencedSince(parent common.Hash, head *types.Header) types.Transactions {
+	bc := w.sequencer.execEngine.bc
+	var blocks []*types.Block
+	for header := head; header != nil && header.Hash() != parent && len(blocks) < maxSpeculativeReorgDepth; {
+		block := bc.GetBlock(header.Hash(), header.Number.Uint64())
+		if block == nil {
+			break
+		}
+		blocks = append(blocks, block)
+		header = bc.GetHeader(header.ParentHash, header.Number.Uint64()-1)
+	}
+	var txes types.Transactions
+	for i := len(blocks) - 1; i >= 0; i-- {
+		for _, tx := range blocks[i].Transactions() {
+			if tx.Type() < types.ArbitrumDepositTxType {
+				txes = append(txes, tx)
+			}
+		}
+	}
+	return txes
+}
+
+// pruneSequenced removes the sequenced transactions from pending. It also reports whether they were sequenced
+// as speculated, that is whether the sequenced transactions from pending are a prefix of speculated.
+func pruneSequenced(pending types.Transactions, speculated types.Transactions, sequenced types.Transactions) (types.Transactions, bool) {
+	sequencedHashes := make(map[common.Hash]struct{}, len(sequenced))
+	for _, tx := range sequenced {
+		sequencedHashes[tx.Hash()] = struct{}{}
+	}
+	matched := true
+	matching := 0
+	for _, tx := range sequenced {
+		if matching < len(speculated) && speculated[matching].Hash() == tx.Hash() {
+			matching++
+		} else if matching < len(speculated) {
+			matched = false
+		}
+	}
+	for _, tx := range speculated[matching:] {
+		if _, ok := sequencedHashes[tx.Hash()]; ok {
+			matched = false
+		}
+	}
+	var remaining types.Transactions
+	for _, tx := range pending {
+		if _, ok := sequencedHashes[tx.Hash()]; !ok {
+			remaining = append(remaining, tx)
+		}
+	}
+	return remaining, matched
+}
+
+// SpeculativeState returns how many relayed transactions the standby is tracking, and how many of them
+// it executed on top of the current block.
+func (w *warmStandby) SpeculativeState() (pending int, executed int) {
+	return int(w.pendingCount.Load()), int(w.executedCount.Load())
+}
@@ -0,0 +1,74 @@
+// Copyright 2024, Offchain Labs, Inc.
+// For license information, see https://github.com/nitro/blob/master/LICENSE
+
+package gethexec
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+func TestQueueRelay(t *testing.T) {
+	relay := newQueueRelay(3)
+	for i := byte(0); i < 5; i++ {
+		relay.add([]byte{i})
+	}
+
+	queued := relay.since(0, 10)
+	if queued.First != 2 {
+		t.Fatal("expected dropped transactions to be skipped, got first", queued.First)
+	}
+	if len(queued.Transactions) != 3 || queued.Transactions[0][0] != 2 || queued.Transactions[2][0] != 4 {
+		t.Fatal("unexpected transactions", queued.Transactions)
+	}
+
+	queued = relay.since(3, 1)
+	if queued.First != 3 || len(queued.Transactions) != 1 || queued.Transactions[0][0] != 3 {
+		t.Fatal("unexpected limited page", queued.First, queued.Transactions)
+	}
+
+	queued = relay.since(7, 10)
+	if queued.First != 5 || len(queued.Transactions) != 0 {
+		t.Fatal("expected an empty page at the end of the relay", queued.First, queued.Transactions)
+	}
+
+	if newQueueRelay(3).epoch == relay.epoch {
+		t.Fatal("expected relays to have distinct epochs")
+	}
+}
+
+func TestPruneSequenced(t *testing.T) {
+	txes := make(types.Transactions, 5)
+	for i := range txes {
+		txes[i] = types.NewTx(&types.LegacyTx{Nonce: uint64(i), GasPrice: big.NewInt(1), Gas: 21000})
+	}
+
+	check := func(pending, speculated, sequenced types.Transactions, expected types.Transactions, expectedMatch bool) {
+		t.Helper()
+		remaining, matched := pruneSequenced(pending, speculated, sequenced)
+		if matched != expectedMatch {
+			t.Fatal("expected match", expectedMatch, "got", matched)
+		}
+		if len(remaining) != len(expected) {
+			t.Fatal("expected", len(expected), "remaining transactions, got", len(remaining))
+		}
+		for i := range remaining {
+			if remaining[i].Hash() != expected[i].Hash() {
+				t.Fatal("unexpected remaining transaction at", i)
+			}
+		}
+	}
+
+	// sequenced a prefix of what we speculated
+	check(txes, txes[:4], txes[:2], txes[2:], true)
+	// sequenced everything we speculated and more
+	check(txes, txes[:2], txes[:3], txes[3:], true)
+	// sequenced transactions we never heard of in between ours
+	check(txes, txes, types.Transactions{txes[0], txes[4], txes[1]}, txes[2:4], false)
+	// sequenced a later transaction while skipping an earlier one
+	check(txes, txes, txes[1:2], types.Transactions{txes[0], txes[2], txes[3], txes[4]}, false)
+	// nothing speculated yet
+	check(txes, nil, txes[:1], txes[1:], true)
+}
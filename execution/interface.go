@@ -3,10 +3,13 @@ package execution
 import (
 	"context"
 	"errors"
+	"math/big"
 	"testing"
 
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/event"
 
 	"github.com/offchainlabs/nitro/arbos/arbostypes"
 	"github.com/offchainlabs/nitro/arbutil"
@@ -24,6 +27,34 @@ type RecordResult struct {
 	UserWasms state.UserWasms
 }
 
+// ChainParameterChange describes a call a chain owner made to ArbOwner to change an ArbOS parameter.
+type ChainParameterChange struct {
+	MessageIndex arbutil.MessageIndex // the message whose block made the change
+	Method       [4]byte              // the selector of the ArbOwner method called
+	Owner        common.Address
+	Data         []byte // the calldata of the call, including the selector
+}
+
+// L1PricingUpdateTrace explains how a batch posting report in a block changed the L1 price per unit.
+type L1PricingUpdateTrace struct {
+	BlockNumber    hexutil.Uint64 `json:"blockNumber"`
+	BlockHash      common.Hash    `json:"blockHash"`
+	TxIndex        hexutil.Uint64 `json:"txIndex"` // the index of the batch posting report in the block
+	BatchPoster    common.Address `json:"batchPoster"`
+	BatchTimestamp hexutil.Uint64 `json:"batchTimestamp"`
+	BatchDataGas   hexutil.Uint64 `json:"batchDataGas"`
+	PerBatchGas    int64          `json:"perBatchGas"`
+	L1BaseFee      *hexutil.Big   `json:"l1BaseFee"`
+	WeiSpent       *hexutil.Big   `json:"weiSpent"`     // the cost the batch poster reported
+	WeiAllocated   *hexutil.Big   `json:"weiAllocated"` // the cost assigned to the poster, after any cap
+	UnitsAllocated hexutil.Uint64 `json:"unitsAllocated"`
+	PriceBefore    *hexutil.Big   `json:"priceBefore"`
+	PriceAfter     *hexutil.Big   `json:"priceAfter"`
+	SurplusBefore  *big.Int       `json:"surplusBefore"` // may be negative, which hexutil can't decode
+	SurplusAfter   *big.Int       `json:"surplusAfter"`
+	Rules          []string       `json:"rules"` // the adjustment rules that fired, see l1pricing.Rule*
+}
+
 var ErrRetrySequencer = errors.New("please retry transaction")
 var ErrSequencerInsertLockTaken = errors.New("insert lock taken")
 
@@ -71,6 +102,11 @@ type FullExecutionClient interface {
 	Maintenance() error
 
 	ArbOSVersionForMessageNumber(messageNum arbutil.MessageIndex) (uint64, error)
+	BrotliCompressionLevel() (uint64, error)
+	ActiveCompressionDictionary() (uint8, common.Hash, error)
+	L1TxBatchCompressionDisabled() (bool, error)
+	SubscribeChainParameterChanges(ch chan<- ChainParameterChange) event.Subscription
+	SubscribeL1PricingUpdates(ch chan<- L1PricingUpdateTrace) event.Subscription
 }
 
 // not implemented in execution, used as input
@@ -5,8 +5,12 @@ package gethhook
 
 import (
 	"errors"
+	"fmt"
+	"math/big"
+	"sync"
 
 	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core"
 	"github.com/ethereum/go-ethereum/core/vm"
 	"github.com/ethereum/go-ethereum/log"
@@ -16,8 +20,25 @@ import (
 	"github.com/offchainlabs/nitro/precompiles"
 )
 
+// arbosPrecompiles are the ArbOS precompiles by address, which are installed into geth on init
+var arbosPrecompiles map[common.Address]precompiles.ArbosPrecompile
+
+// PrecompileHandler handles calls to a precompile, as the ArbOS precompiles do
+type PrecompileHandler interface {
+	Call(
+		input []byte,
+		precompileAddress common.Address,
+		actingAsAddress common.Address,
+		caller common.Address,
+		value *big.Int,
+		readOnly bool,
+		gasSupplied uint64,
+		evm *vm.EVM,
+	) (output []byte, gasLeft uint64, err error)
+}
+
 type ArbosPrecompileWrapper struct {
-	inner precompiles.ArbosPrecompile
+	inner PrecompileHandler
 }
 
 func (p ArbosPrecompileWrapper) RequiredGas(input []byte) uint64 {
@@ -49,6 +70,7 @@ func init() {
 	core.ReadyEVMForL2 = func(evm *vm.EVM, msg *core.Message) {
 		if evm.ChainConfig().IsArbitrum() {
 			evm.ProcessingHook = arbos.NewTxProcessor(evm, msg)
+			installExtraPrecompiles(evm)
 		}
 	}
 
@@ -63,7 +85,8 @@ func init() {
 	}
 
 	precompileErrors := make(map[[4]byte]abi.Error)
-	for addr, precompile := range precompiles.Precompiles() {
+	arbosPrecompiles = precompiles.Precompiles()
+	for addr, precompile := range arbosPrecompiles {
 		for _, errABI := range precompile.Precompile().GetErrorABIs() {
 			precompileErrors[[4]byte(errABI.ID.Bytes())] = errABI
 		}
@@ -105,5 +128,51 @@ func init() {
 	}
 }
 
+// ArbosPrecompile gets the ArbOS precompile at an address, if there is one
+func ArbosPrecompile(address common.Address) (precompiles.ArbosPrecompile, bool) {
+	precompile, ok := arbosPrecompiles[address]
+	return precompile, ok
+}
+
+var (
+	extraPrecompiles     = make(map[common.Address]vm.PrecompiledContract)
+	extraPrecompilesLock sync.RWMutex
+)
+
+// AddExtraPrecompile adds a precompile to every L2 EVM until the returned function removes it.
+// It lets tests simulate precompiles that don't exist yet. Replay and validation don't know of it,
+// so blocks calling it can't be validated.
+func AddExtraPrecompile(address common.Address, handler PrecompileHandler) (func(), error) {
+	if _, ok := arbosPrecompiles[address]; ok {
+		return nil, fmt.Errorf("address %v already has an ArbOS precompile", address)
+	}
+	extraPrecompilesLock.Lock()
+	defer extraPrecompilesLock.Unlock()
+	if _, ok := extraPrecompiles[address]; ok {
+		return nil, fmt.Errorf("address %v already has an extra precompile", address)
+	}
+	extraPrecompiles[address] = ArbosPrecompileWrapper{handler}
+	return func() {
+		extraPrecompilesLock.Lock()
+		defer extraPrecompilesLock.Unlock()
+		delete(extraPrecompiles, address)
+	}, nil
+}
+
+func installExtraPrecompiles(evm *vm.EVM) {
+	extraPrecompilesLock.RLock()
+	defer extraPrecompilesLock.RUnlock()
+	if len(extraPrecompiles) == 0 {
+		return
+	}
+	context := evm.Context
+	rules := evm.ChainConfig().Rules(context.BlockNumber, context.Random != nil, context.Time, context.ArbOSVersion)
+	contracts := vm.ActivePrecompiledContracts(rules)
+	for address, precompile := range extraPrecompiles {
+		contracts[address] = precompile
+	}
+	evm.SetPrecompiles(contracts)
+}
+
 // RequireHookedGeth does nothing, but forces an import to let the init function run
 func RequireHookedGeth() {}
@@ -6,11 +6,16 @@ package precompiles
 import (
 	"errors"
 	"math/big"
+
+	"github.com/offchainlabs/nitro/arbos/addressTable"
+	"github.com/offchainlabs/nitro/arbos/util"
 )
 
 // ArbAddressTable precompile provides the ability to create short-hands for commonly used accounts.
 type ArbAddressTable struct {
 	Address addr // 0x66
+
+	DecompressionFailedError func(huge, string) error
 }
 
 // AddressExists checks if an address exists in the table
@@ -25,17 +30,28 @@ func (con ArbAddressTable) Compress(c ctx, evm mech, addr addr) ([]uint8, error)
 
 // Decompress the compressed bytes at the given offset with those of the corresponding account
 func (con ArbAddressTable) Decompress(c ctx, evm mech, buf []uint8, offset huge) (addr, huge, error) {
-	if !offset.IsInt64() {
-		return addr{}, nil, errors.New("invalid offset in ArbAddressTable.Decompress")
+	if !offset.IsInt64() || offset.Int64() > int64(len(buf)) {
+		old := errors.New("invalid offset in ArbAddressTable.Decompress")
+		return addr{}, nil, con.decompressionFailed(c, offset, "offset out of range", old)
+	}
+	result, nbytes, err := c.State.AddressTable().Decompress(buf[offset.Int64():])
+	if errors.Is(err, addressTable.ErrInvalidEncoding) {
+		return addr{}, nil, con.decompressionFailed(c, offset, "invalid encoding", err)
 	}
-	ioffset := offset.Int64()
-	if ioffset > int64(len(buf)) {
-		return addr{}, nil, errors.New("invalid offset in ArbAddressTable.Decompress")
+	if errors.Is(err, addressTable.ErrInvalidIndex) {
+		return addr{}, nil, con.decompressionFailed(c, offset, "index not in table", err)
 	}
-	result, nbytes, err := c.State.AddressTable().Decompress(buf[ioffset:])
 	return result, new(big.Int).SetUint64(nbytes), err
 }
 
+// decompressionFailed reverts with the typed error starting with ArbOS 40, and with the untyped one before it
+func (con ArbAddressTable) decompressionFailed(c ctx, offset huge, reason string, old error) error {
+	if c.State.ArbOSVersion() >= util.ArbosVersion_40 {
+		return con.DecompressionFailedError(offset, reason)
+	}
+	return old
+}
+
 // Lookup the index of an address in the table
 func (con ArbAddressTable) Lookup(c ctx, evm mech, addr addr) (huge, error) {
 	result, exists, err := c.State.AddressTable().Lookup(addr)
@@ -48,6 +64,15 @@ func (con ArbAddressTable) Lookup(c ctx, evm mech, addr addr) (huge, error) {
 	return new(big.Int).SetUint64(result), nil
 }
 
+// LookupMany looks up the indices of addresses in the table, which are 0 for addresses not in it
+func (con ArbAddressTable) LookupMany(c ctx, evm mech, addrs []addr) ([]huge, []bool, error) {
+	indices, exists, err := c.State.AddressTable().LookupMany(addrs)
+	if err != nil {
+		return nil, nil, err
+	}
+	return bigIndices(indices), exists, nil
+}
+
 // LookupIndex for  an address in the table by index
 func (con ArbAddressTable) LookupIndex(c ctx, evm mech, index huge) (addr, error) {
 	if !index.IsUint64() {
@@ -69,6 +94,23 @@ func (con ArbAddressTable) Register(c ctx, evm mech, addr addr) (huge, error) {
 	return new(big.Int).SetUint64(slot), err
 }
 
+// RegisterMany adds the accounts not yet in the table to it, returning the indices of all of them
+func (con ArbAddressTable) RegisterMany(c ctx, evm mech, addrs []addr) ([]huge, error) {
+	indices, err := c.State.AddressTable().RegisterMany(addrs)
+	if err != nil {
+		return nil, err
+	}
+	return bigIndices(indices), nil
+}
+
+func bigIndices(indices []uint64) []huge {
+	result := make([]huge, 0, len(indices))
+	for _, index := range indices {
+		result = append(result, new(big.Int).SetUint64(index))
+	}
+	return result
+}
+
 // Size gets the number of addresses in the table
 func (con ArbAddressTable) Size(c ctx, evm mech) (huge, error) {
 	size, err := c.State.AddressTable().Size()
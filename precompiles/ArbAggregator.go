@@ -59,6 +59,28 @@ func (con ArbAggregator) AddBatchPoster(c ctx, evm mech, newBatchPoster addr) er
 	return nil
 }
 
+// RemoveBatchPoster removes a batch poster, which must not be owed any funds (caller must be an owner)
+func (con ArbAggregator) RemoveBatchPoster(c ctx, evm mech, batchPoster addr) error {
+	isOwner, err := c.State.ChainOwners().IsMember(c.caller)
+	if err != nil {
+		return err
+	}
+	if !isOwner {
+		return ErrNotOwner
+	}
+	return c.State.L1PricingState().BatchPosterTable().RemovePoster(batchPoster, c.State.ArbOSVersion())
+}
+
+// GetBatchPosterStats gets the units a batch poster has posted, the wei it has been paid,
+// and when they were last updated, all of which are only tracked since ArbOS 40
+func (con ArbAggregator) GetBatchPosterStats(c ctx, evm mech, batchPoster addr) (uint64, huge, uint64, error) {
+	posterInfo, err := c.State.L1PricingState().BatchPosterTable().OpenPoster(batchPoster, false)
+	if err != nil {
+		return 0, nil, 0, err
+	}
+	return posterInfo.Stats()
+}
+
 // GetFeeCollector gets a batch poster's fee collector
 func (con ArbAggregator) GetFeeCollector(c ctx, evm mech, batchPoster addr) (addr, error) {
 	posterInfo, err := c.State.L1PricingState().BatchPosterTable().OpenPoster(batchPoster, false)
@@ -90,6 +112,42 @@ func (con ArbAggregator) SetFeeCollector(c ctx, evm mech, batchPoster addr, newF
 	return posterInfo.SetPayTo(newFeeCollector)
 }
 
+// GetAllFeeCollectors gets every batch poster along with its fee collector, as parallel arrays
+func (con ArbAggregator) GetAllFeeCollectors(c ctx, evm mech) ([]addr, []addr, error) {
+	batchPosterTable := c.State.L1PricingState().BatchPosterTable()
+	batchPosters, err := batchPosterTable.AllPosters(65536)
+	if err != nil {
+		return nil, nil, err
+	}
+	feeCollectors := make([]addr, 0, len(batchPosters))
+	for _, batchPoster := range batchPosters {
+		posterInfo, err := batchPosterTable.OpenPoster(batchPoster, false)
+		if err != nil {
+			return nil, nil, err
+		}
+		feeCollector, err := posterInfo.PayTo()
+		if err != nil {
+			return nil, nil, err
+		}
+		feeCollectors = append(feeCollectors, feeCollector)
+	}
+	return batchPosters, feeCollectors, nil
+}
+
+// SetFeeCollectors sets the fee collectors of several batch posters at once
+// (for each batch poster, caller must be the batch poster, its fee collector, or an owner)
+func (con ArbAggregator) SetFeeCollectors(c ctx, evm mech, batchPosters []addr, newFeeCollectors []addr) error {
+	if len(batchPosters) != len(newFeeCollectors) {
+		return errors.New("batch posters and fee collectors must have the same length")
+	}
+	for i, batchPoster := range batchPosters {
+		if err := con.SetFeeCollector(c, evm, batchPoster, newFeeCollectors[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // GetTxBaseFee gets an aggregator's current fixed fee to submit a tx
 // Deprecated: always returns zero
 func (con ArbAggregator) GetTxBaseFee(c ctx, evm mech, aggregator addr) (huge, error) {
@@ -4,9 +4,15 @@
 package precompiles
 
 import (
+	"encoding/binary"
 	"errors"
+	"fmt"
 
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/params"
+
+	"github.com/offchainlabs/nitro/util/arbmath"
 )
 
 // All calls to this precompile are authorized by the DebugPrecompile wrapper,
@@ -20,6 +26,9 @@ type ArbDebug struct {
 	MixedGasCost func(bool, bool, bytes32, addr, addr) (uint64, error)
 	StoreGasCost func(bool, addr, huge, bytes32, []byte) (uint64, error)
 
+	PrecompileGasUsage        func(ctx, mech, addr, bytes4, uint64, uint64, uint64) error
+	PrecompileGasUsageGasCost func(addr, bytes4, uint64, uint64, uint64) (uint64, error)
+
 	CustomError func(uint64, string, bool) error
 	UnusedError func() error
 }
@@ -49,6 +58,42 @@ func (con ArbDebug) EventsView(c ctx, evm mech) error {
 	return err
 }
 
+// Emits count logs, each with the given number of topics and dataSize bytes of data, to stress test log handling.
+// Each topic holds the log's index, and each byte of data is the log's index plus the byte's offset.
+func (con ArbDebug) EmitLogs(c ctx, evm mech, count uint64, dataSize uint64, topics uint8) error {
+	if topics > 4 {
+		return fmt.Errorf("a log has at most 4 topics, not %v", topics)
+	}
+	// charged like the LOG opcodes, except for memory expansion
+	costPerLog := arbmath.SaturatingUAdd(
+		params.LogGas+params.LogTopicGas*uint64(topics),
+		arbmath.SaturatingUMul(params.LogDataGas, dataSize),
+	)
+	if err := c.Burn(arbmath.SaturatingUMul(costPerLog, count)); err != nil {
+		return err
+	}
+	for i := uint64(0); i < count; i++ {
+		var topic common.Hash
+		binary.BigEndian.PutUint64(topic[24:], i)
+		logTopics := make([]common.Hash, topics)
+		for j := range logTopics {
+			logTopics[j] = topic
+		}
+		data := make([]byte, dataSize)
+		for j := range data {
+			// #nosec G115
+			data[j] = byte(i + uint64(j))
+		}
+		evm.StateDB.AddLog(&types.Log{
+			Address:     con.Address,
+			Topics:      logTopics,
+			Data:        data,
+			BlockNumber: evm.Context.BlockNumber.Uint64(),
+		})
+	}
+	return nil
+}
+
 // Throws a custom error
 func (con ArbDebug) CustomRevert(c ctx, number uint64) error {
 	return con.CustomError(number, "This spider family wards off bugs: /\\oo/\\ //\\(oo)//\\ /\\oo/\\", true)
@@ -59,6 +104,19 @@ func (con ArbDebug) BecomeChainOwner(c ctx, evm mech) error {
 	return c.State.ChainOwners().Add(c.caller)
 }
 
+// Has each successful precompile call in a transaction log a PrecompileGasUsage event with the gas it charged
+// and the storage it accessed, to audit that the gas covers the storage (caller must be a chain owner)
+func (con ArbDebug) SetPrecompileGasAudit(c ctx, evm mech, enabled bool) error {
+	isOwner, err := c.State.ChainOwners().IsMember(c.caller)
+	if err != nil {
+		return err
+	}
+	if !isOwner {
+		return ErrNotOwner
+	}
+	return c.State.SetPrecompileGasAudit(enabled)
+}
+
 // Halts the chain by panicking in the STF
 func (con ArbDebug) Panic(c ctx, evm mech) error {
 	panic("called ArbDebug's debug-only Panic method")
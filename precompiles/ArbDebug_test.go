@@ -0,0 +1,68 @@
+// Copyright 2024, Offchain Labs, Inc.
+// For license information, see https://github.com/nitro/blob/master/LICENSE
+
+package precompiles
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/params"
+
+	"github.com/offchainlabs/nitro/arbos/arbosState"
+	"github.com/offchainlabs/nitro/arbos/burn"
+	"github.com/offchainlabs/nitro/cmd/chaininfo"
+)
+
+func TestBecomeChainOwnerDisabledInProduction(t *testing.T) {
+	contract := Precompiles()[types.ArbDebugAddress]
+	method, ok := contract.Precompile().methodsByName["BecomeChainOwner"]
+	if !ok {
+		Fail(t, "ArbDebug has no BecomeChainOwner method")
+	}
+	caller := common.HexToAddress("0xdeadbeef")
+
+	becomeChainOwner := func(chainConfig *params.ChainConfig) (uint64, bool, error) {
+		t.Helper()
+		evm := newMockEVMForTestingWithConfig(chainConfig)
+		gas := uint64(1_000_000)
+		_, gasLeft, callErr := contract.Call(method.template.ID, types.ArbDebugAddress, types.ArbDebugAddress, caller, big.NewInt(0), false, gas, evm)
+		state, err := arbosState.OpenArbosState(evm.StateDB, burn.NewSystemBurner(nil, false))
+		Require(t, err)
+		isOwner, err := state.ChainOwners().IsMember(caller)
+		Require(t, err)
+		return gas - gasLeft, isOwner, callErr
+	}
+
+	devChainConfig := chaininfo.ArbitrumDevTestChainConfig()
+	devChainConfig.ArbitrumChainParams.AllowDebugPrecompiles = false
+	productionConfigs := map[string]*params.ChainConfig{
+		"arb1":                                   chaininfo.ArbitrumOneChainConfig(),
+		"nova":                                   chaininfo.ArbitrumNovaChainConfig(),
+		"goerli-rollup":                          chaininfo.ArbitrumRollupGoerliTestnetChainConfig(),
+		"arb-dev-test without debug precompiles": devChainConfig,
+	}
+	for name, chainConfig := range productionConfigs {
+		if chainConfig.DebugMode() {
+			Fail(t, name, "has debug precompiles enabled")
+		}
+		gasUsed, isOwner, err := becomeChainOwner(chainConfig)
+		if err == nil || err.Error() != "debug precompiles are disabled" {
+			Fail(t, "expected BecomeChainOwner to be disabled on", name, "got", err)
+		}
+		if isOwner {
+			Fail(t, "caller became a chain owner on", name)
+		}
+		if gasUsed != 1_000_000 {
+			Fail(t, "expected BecomeChainOwner to take all gas on", name, "used", gasUsed)
+		}
+	}
+
+	_, isOwner, err := becomeChainOwner(chaininfo.ArbitrumDevTestChainConfig())
+	Require(t, err, "BecomeChainOwner failed with debug precompiles enabled")
+	if !isOwner {
+		Fail(t, "caller didn't become a chain owner with debug precompiles enabled")
+	}
+}
@@ -178,7 +178,8 @@ func (con ArbGasInfo) GetL1BaseFeeEstimate(c ctx, evm mech) (huge, error) {
 	return c.State.L1PricingState().PricePerUnit()
 }
 
-// GetL1BaseFeeEstimateInertia gets how slowly ArbOS updates its estimate of the L1 basefee
+// GetL1BaseFeeEstimateInertia gets how slowly ArbOS updates its estimate of the L1 basefee.
+// Not to be confused with GetPricingInertia, which is the inertia of the L2 basefee.
 func (con ArbGasInfo) GetL1BaseFeeEstimateInertia(c ctx, evm mech) (uint64, error) {
 	return c.State.L1PricingState().Inertia()
 }
@@ -193,6 +194,27 @@ func (con ArbGasInfo) GetL1RewardRecipient(c ctx, evm mech) (common.Address, err
 	return c.State.L1PricingState().PayRewardsTo()
 }
 
+// GetL1BatchEthPaymentAddress gets the account paying batch posters what the L1 fees collected can't cover
+func (con ArbGasInfo) GetL1BatchEthPaymentAddress(c ctx, evm mech) (common.Address, error) {
+	return c.State.L1PricingState().BatchEthPaymentAddress()
+}
+
+// GetL1BlockNumberStaleness gets how many seconds the L1 block number's expected timestamp was behind the
+// last L2 block's, estimating L1 blocks to be GetL1BlockTimeSeconds apart
+func (con ArbGasInfo) GetL1BlockNumberStaleness(c ctx, evm mech) (uint64, error) {
+	return c.State.Blockhashes().L1BlockStaleness()
+}
+
+// GetL1BlockTimeSeconds gets the expected time between L1 blocks
+func (con ArbGasInfo) GetL1BlockTimeSeconds(c ctx, evm mech) (uint64, error) {
+	return c.State.L1PricingState().L1BlockTimeSeconds()
+}
+
+// GetL1GasUsedLastBatch gets the L1 gas used by the last batch posted, including the per batch gas cost
+func (con ArbGasInfo) GetL1GasUsedLastBatch(c ctx, evm mech) (uint64, error) {
+	return c.State.L1PricingState().L1GasUsedLastBatch()
+}
+
 // GetL1GasPriceEstimate gets the current estimate of the L1 basefee
 func (con ArbGasInfo) GetL1GasPriceEstimate(c ctx, evm mech) (huge, error) {
 	return con.GetL1BaseFeeEstimate(c, evm)
@@ -208,7 +230,8 @@ func (con ArbGasInfo) GetGasBacklog(c ctx, evm mech) (uint64, error) {
 	return c.State.L2PricingState().GasBacklog()
 }
 
-// GetPricingInertia gets how slowly ArbOS updates the L2 basefee in response to backlogged gas
+// GetPricingInertia gets how slowly ArbOS updates the L2 basefee in response to backlogged gas.
+// Despite the name, this is unrelated to GetL1BaseFeeEstimateInertia, which is the inertia of L1 pricing.
 func (con ArbGasInfo) GetPricingInertia(c ctx, evm mech) (uint64, error) {
 	return c.State.L2PricingState().PricingInertia()
 }
@@ -218,6 +241,11 @@ func (con ArbGasInfo) GetGasBacklogTolerance(c ctx, evm mech) (uint64, error) {
 	return c.State.L2PricingState().BacklogTolerance()
 }
 
+// GetGasPriceForBacklog gets the basefee the L2 pricing model would set for a hypothetical gas backlog
+func (con ArbGasInfo) GetGasPriceForBacklog(c ctx, evm mech, hypotheticalBacklog uint64) (huge, error) {
+	return c.State.L2PricingState().BaseFeeForBacklog(hypotheticalBacklog)
+}
+
 // GetL1PricingSurplus gets the surplus of funds for L1 batch posting payments (may be negative)
 func (con ArbGasInfo) GetL1PricingSurplus(c ctx, evm mech) (*big.Int, error) {
 	if c.State.ArbOSVersion() < params.ArbosVersion_10 {
@@ -252,6 +280,24 @@ func (con ArbGasInfo) GetAmortizedCostCapBips(c ctx, evm mech) (uint64, error) {
 	return c.State.L1PricingState().AmortizedCostCapBips()
 }
 
+// GetL1PricingDataGasFactor gets the fraction, in basis points, of each tx's calldata units charged for,
+// with 0 meaning all of them
+func (con ArbGasInfo) GetL1PricingDataGasFactor(c ctx, evm mech) (uint64, error) {
+	return c.State.L1PricingState().DataGasFactorBips()
+}
+
+// GetL1PricingThrottlePercent gets the bound on each L1 pricing update's change to the L1 base fee estimate,
+// as a percentage of the previous estimate, with 0 meaning it's unbounded
+func (con ArbGasInfo) GetL1PricingThrottlePercent(c ctx, evm mech) (uint64, error) {
+	return c.State.L1PricingState().ThrottlePercent()
+}
+
+// GetTipDistribution gets the recipients tips are split among and their shares in basis points,
+// which are empty if tips follow the default fee routing
+func (con ArbGasInfo) GetTipDistribution(c ctx, evm mech) ([]addr, []uint64, error) {
+	return c.State.TipDistribution().Table()
+}
+
 // GetL1FeesAvailable gets the available funds from L1 fees
 func (con ArbGasInfo) GetL1FeesAvailable(c ctx, evm mech) (huge, error) {
 	return c.State.L1PricingState().L1FeesAvailable()
@@ -281,3 +327,11 @@ func (con ArbGasInfo) GetL1PricingUnitsSinceUpdate(c ctx, evm mech) (uint64, err
 func (con ArbGasInfo) GetLastL1PricingSurplus(c ctx, evm mech) (*big.Int, error) {
 	return c.State.L1PricingState().LastSurplus()
 }
+
+// GetArbGasToWeiRate gets the current price of ArbGas in wei, which is the L2 base fee
+func (con ArbGasInfo) GetArbGasToWeiRate(c ctx, evm mech) (huge, error) {
+	if evm.Context.BaseFeeInBlock != nil {
+		return evm.Context.BaseFeeInBlock, nil
+	}
+	return evm.Context.BaseFee, nil
+}
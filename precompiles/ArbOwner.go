@@ -13,8 +13,10 @@ import (
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/params"
 
+	"github.com/offchainlabs/nitro/arbos/arbosState"
 	"github.com/offchainlabs/nitro/arbos/l1pricing"
 	"github.com/offchainlabs/nitro/arbos/programs"
+	"github.com/offchainlabs/nitro/arbos/util"
 	"github.com/offchainlabs/nitro/util/arbmath"
 	am "github.com/offchainlabs/nitro/util/arbmath"
 )
@@ -27,6 +29,21 @@ type ArbOwner struct {
 	Address          addr // 0x70
 	OwnerActs        func(ctx, mech, bytes4, addr, []byte) error
 	OwnerActsGasCost func(bytes4, addr, []byte) (uint64, error)
+
+	ChainOwnerAdded           func(ctx, mech, addr) error
+	ChainOwnerAddedGasCost    func(addr) (uint64, error)
+	ChainOwnerRemoved         func(ctx, mech, addr) error
+	ChainOwnerRemovedGasCost  func(addr) (uint64, error)
+	TipDistributionSet        func(ctx, mech, []addr, []uint64) error
+	TipDistributionSetGasCost func([]addr, []uint64) (uint64, error)
+
+	ArbOSUpgradeNotIncreasingError func(newVersion uint64, scheduledVersion uint64) error
+	BaseFeeUnderMinimumError       func(requested huge, minimum huge) error
+	InvalidChainConfigError        func(reason string) error
+
+	// used by Multicall to dispatch calls to this precompile, set once it's created
+	precompile    *Precompile
+	emitOwnerActs func(mech, bytes4, addr, []byte) error
 }
 
 var (
@@ -35,7 +52,13 @@ var (
 
 // AddChainOwner adds account as a chain owner
 func (con ArbOwner) AddChainOwner(c ctx, evm mech, newOwner addr) error {
-	return c.State.ChainOwners().Add(newOwner)
+	if err := c.State.ChainOwners().Add(newOwner); err != nil {
+		return err
+	}
+	if c.State.ArbOSVersion() < util.ArbosVersion_40 {
+		return nil
+	}
+	return con.ChainOwnerAdded(c, evm, newOwner)
 }
 
 // RemoveChainOwner removes account from the list of chain owners
@@ -44,7 +67,13 @@ func (con ArbOwner) RemoveChainOwner(c ctx, evm mech, addr addr) error {
 	if !member {
 		return errors.New("tried to remove non-owner")
 	}
-	return c.State.ChainOwners().Remove(addr, c.State.ArbOSVersion())
+	if err := c.State.ChainOwners().Remove(addr, c.State.ArbOSVersion()); err != nil {
+		return err
+	}
+	if c.State.ArbOSVersion() < util.ArbosVersion_40 {
+		return nil
+	}
+	return con.ChainOwnerRemoved(c, evm, addr)
 }
 
 // IsChainOwner checks if the account is a chain owner
@@ -62,8 +91,18 @@ func (con ArbOwner) SetL1BaseFeeEstimateInertia(c ctx, evm mech, inertia uint64)
 	return c.State.L1PricingState().SetInertia(inertia)
 }
 
-// SetL2BaseFee sets the L2 gas price directly, bypassing the pool calculus
+// SetL2BaseFee sets the L2 gas price directly, bypassing the pool calculus.
+// Since ArbOS 40 the price can't be below the minimum base fee.
 func (con ArbOwner) SetL2BaseFee(c ctx, evm mech, priceInWei huge) error {
+	if c.State.ArbOSVersion() >= util.ArbosVersion_40 {
+		minimum, err := c.State.L2PricingState().MinBaseFeeWei()
+		if err != nil {
+			return err
+		}
+		if priceInWei.Cmp(minimum) < 0 {
+			return con.BaseFeeUnderMinimumError(priceInWei, minimum)
+		}
+	}
 	return c.State.L2PricingState().SetBaseFeeWei(priceInWei)
 }
 
@@ -115,9 +154,39 @@ func (con ArbOwner) SetInfraFeeAccount(c ctx, evm mech, newNetworkFeeAccount add
 	return c.State.SetInfraFeeAccount(newNetworkFeeAccount)
 }
 
-// ScheduleArbOSUpgrade to the requested version at the requested timestamp
+// ScheduleArbOSUpgrade to the requested version at the requested timestamp.
+// Since ArbOS 40 the upgrade is queued after any pending ones, and must be to a higher version than them.
+// Scheduling a pending version again reschedules it, and scheduling version 0 cancels all pending upgrades.
 func (con ArbOwner) ScheduleArbOSUpgrade(c ctx, evm mech, newVersion uint64, timestamp uint64) error {
-	return c.State.ScheduleArbOSUpgrade(newVersion, timestamp)
+	if c.State.ArbOSVersion() < util.ArbosVersion_40 {
+		return c.State.ScheduleArbOSUpgrade(newVersion, timestamp)
+	}
+	pending, err := c.State.PendingArbOSUpgrades()
+	if err != nil {
+		return err
+	}
+	if newVersion == 0 {
+		return c.State.ReplacePendingArbOSUpgrades(nil)
+	}
+	for i := range pending {
+		if pending[i].Version == newVersion {
+			pending[i].Timestamp = timestamp
+			return c.State.ReplacePendingArbOSUpgrades(pending)
+		}
+	}
+	if len(pending) > 0 {
+		last := pending[len(pending)-1]
+		if newVersion <= last.Version {
+			return con.ArbOSUpgradeNotIncreasingError(newVersion, last.Version)
+		}
+	}
+	return c.State.QueueArbOSUpgrade(newVersion, timestamp)
+}
+
+// SetL1BatchEthPaymentAddress sets an account to pay batch posters whatever the L1 fees collected can't cover.
+// Setting it to the zero address leaves batch posters to wait on future fees.
+func (con ArbOwner) SetL1BatchEthPaymentAddress(c ctx, evm mech, paymentAddress addr) error {
+	return c.State.L1PricingState().SetBatchEthPaymentAddress(paymentAddress)
 }
 
 // Sets equilibration units parameter for L1 price adjustment algorithm
@@ -155,12 +224,104 @@ func (con ArbOwner) SetAmortizedCostCapBips(c ctx, evm mech, cap uint64) error {
 	return c.State.L1PricingState().SetAmortizedCostCapBips(cap)
 }
 
+// SetL1PricingDataGasFactor sets the fraction, in basis points, of each tx's calldata units charged for,
+// so chains posting data to blobs can pass on the amortised cost; 0 charges for all of them.
+// Batch posters are reimbursed the same fraction of their cost, so the discount doesn't raise the price per unit
+func (con ArbOwner) SetL1PricingDataGasFactor(c ctx, evm mech, factor uint64) error {
+	// #nosec G115
+	if factor > uint64(arbmath.OneInBips) {
+		return ErrOutOfBounds
+	}
+	return c.State.L1PricingState().SetDataGasFactorBips(factor)
+}
+
+// SetL1PricingThrottlePercent bounds how much each L1 pricing update may change the L1 base fee estimate,
+// as a percentage of the previous estimate, to smooth out sudden L1 fee spikes; 0 removes the bound
+func (con ArbOwner) SetL1PricingThrottlePercent(c ctx, evm mech, percent uint64) error {
+	return c.State.L1PricingState().SetThrottlePercent(percent)
+}
+
+// SetL1BlockTimeSeconds sets the expected time between L1 blocks, such as for L3s settling to a faster
+// parent chain; 0 restores the default of 12 seconds
+func (con ArbOwner) SetL1BlockTimeSeconds(c ctx, evm mech, seconds uint64) error {
+	return c.State.L1PricingState().SetL1BlockTimeSeconds(seconds)
+}
+
+// SetTipDistribution sets the recipients tips are split among, with shares in basis points summing to 10000.
+// Each share is rounded down, with the remainder going to the first recipient. An empty table restores the
+// default fee routing.
+func (con ArbOwner) SetTipDistribution(c ctx, evm mech, recipients []addr, shares []uint64) error {
+	if err := c.State.TipDistribution().SetTable(recipients, shares); err != nil {
+		return err
+	}
+	return con.TipDistributionSet(c, evm, recipients, shares)
+}
+
+// SetL2ChainNamespace sets the human-readable identifier of the chain, of at most 32 bytes,
+// so chains sharing infrastructure can be told apart
+func (con ArbOwner) SetL2ChainNamespace(c ctx, evm mech, namespace string) error {
+	return c.State.SetChainNamespace(namespace)
+}
+
 // Sets the Brotli compression level used for fast compression
 // Available in ArbOS version 12 with default level as 1
 func (con ArbOwner) SetBrotliCompressionLevel(c ctx, evm mech, level uint64) error {
 	return c.State.SetBrotliCompressionLevel(level)
 }
 
+// SetL1TxBatchCompressionDisabled sets whether batches should be posted uncompressed, for debugging and benchmarking
+func (con ArbOwner) SetL1TxBatchCompressionDisabled(c ctx, evm mech, disabled bool) error {
+	return c.State.SetL1TxBatchCompressionDisabled(disabled)
+}
+
+// SetCompressionDictionary sets the dictionary with the id and makes it the one batches should be compressed with.
+// An empty dictionary removes the id's dictionary. Only the dictionary's hash is stored, as the batch poster
+// distributes the dictionary itself.
+func (con ArbOwner) SetCompressionDictionary(c ctx, evm mech, id uint8, dictionary []byte) error {
+	if id == 0 {
+		return errors.New("compression dictionary id 0 is reserved")
+	}
+	if len(dictionary) > arbosState.MaxCompressionDictionarySize {
+		return fmt.Errorf("compression dictionary of %d bytes is larger than the maximum of %d", len(dictionary), arbosState.MaxCompressionDictionarySize)
+	}
+	if len(dictionary) == 0 {
+		return c.State.SetCompressionDictionary(id, common.Hash{})
+	}
+	hash, err := c.State.KeccakHash(dictionary)
+	if err != nil {
+		return err
+	}
+	return c.State.SetCompressionDictionary(id, hash)
+}
+
+// SetRetryableAutoRedeemGasLimit caps the gas given to a retryable's auto-redeem, with 0 meaning no cap
+func (con ArbOwner) SetRetryableAutoRedeemGasLimit(c ctx, evm mech, limit uint64) error {
+	return c.State.SetRetryableAutoRedeemGasLimit(limit)
+}
+
+// SetGasPaymaster sets the account that pays for the gas of transactions offering no fee, with 0 meaning none
+func (con ArbOwner) SetGasPaymaster(c ctx, evm mech, paymaster addr) error {
+	return c.State.SetGasPaymaster(paymaster)
+}
+
+// AddGasPaymasterSponsoredSender lets the gas paymaster pay for the sender's transactions offering no fee
+func (con ArbOwner) AddGasPaymasterSponsoredSender(c ctx, evm mech, sender addr) error {
+	return c.State.GasPaymasterSponsoredSenders().Add(sender)
+}
+
+// RemoveGasPaymasterSponsoredSender stops the gas paymaster from paying for the sender's transactions
+func (con ArbOwner) RemoveGasPaymasterSponsoredSender(c ctx, evm mech, sender addr) error {
+	sponsored := c.State.GasPaymasterSponsoredSenders()
+	isMember, err := sponsored.IsMember(sender)
+	if err != nil {
+		return err
+	}
+	if !isMember {
+		return errors.New("tried to remove a sender the gas paymaster doesn't sponsor")
+	}
+	return sponsored.Remove(sender, c.State.ArbOSVersion())
+}
+
 // Releases surplus funds from L1PricerFundsPoolAddress for use
 func (con ArbOwner) ReleaseL1PricerSurplusFunds(c ctx, evm mech, maxWeiToRelease huge) (huge, error) {
 	balance := evm.StateDB.GetBalance(l1pricing.L1PricerFundsPoolAddress)
@@ -313,43 +474,136 @@ func (con ArbOwner) SetChainConfig(c ctx, evm mech, serializedChainConfig []byte
 	if c.txProcessor == nil {
 		return errors.New("uninitialized tx processor")
 	}
-	if c.txProcessor.MsgIsNonMutating() {
-		var newConfig params.ChainConfig
-		err := json.Unmarshal(serializedChainConfig, &newConfig)
-		if err != nil {
-			return fmt.Errorf("invalid chain config, can't deserialize: %w", err)
+	// Before ArbOS 40 the config was only validated in calls that don't mutate state,
+	// so chains that accepted a config the validation would reject replay the same way.
+	if c.txProcessor.MsgIsNonMutating() || c.State.ArbOSVersion() >= util.ArbosVersion_40 {
+		if err := con.validateChainConfig(c, evm, serializedChainConfig); err != nil {
+			return err
 		}
-		if newConfig.ChainID == nil {
-			return errors.New("invalid chain config, missing chain id")
+	}
+	// Checks against the node's own config and binary may differ between nodes, so they can't decide whether
+	// a transaction reverts. They only run in calls that don't mutate state, like gas estimation.
+	if c.txProcessor.MsgIsNonMutating() {
+		if err := con.validateChainConfigForNode(evm, serializedChainConfig); err != nil {
+			return err
 		}
-		chainId, err := c.State.ChainId()
+	}
+	return c.State.SetChainConfig(serializedChainConfig)
+}
+
+// validateChainConfig checks a new config against the chain id and the previous config in ArbOS state,
+// reverting with InvalidChainConfig if it can't replace the previous one
+func (con ArbOwner) validateChainConfig(c ctx, evm mech, serializedChainConfig []byte) error {
+	var newConfig params.ChainConfig
+	if err := json.Unmarshal(serializedChainConfig, &newConfig); err != nil {
+		return con.InvalidChainConfigError(fmt.Sprintf("can't deserialize: %v", err))
+	}
+	if !newConfig.IsArbitrum() {
+		return con.InvalidChainConfigError("ArbOS isn't enabled")
+	}
+	if newConfig.ChainID == nil {
+		return con.InvalidChainConfigError("missing chain id")
+	}
+	chainId, err := c.State.ChainId()
+	if err != nil {
+		return fmt.Errorf("failed to get chain id from ArbOS state: %w", err)
+	}
+	if newConfig.ChainID.Cmp(chainId) != 0 {
+		return con.InvalidChainConfigError(fmt.Sprintf("chain id mismatch, want: %v, have: %v", chainId, newConfig.ChainID))
+	}
+	oldSerializedConfig, err := c.State.ChainConfig()
+	if err != nil {
+		return fmt.Errorf("failed to get old chain config from ArbOS state: %w", err)
+	}
+	if bytes.Equal(oldSerializedConfig, serializedChainConfig) {
+		return con.InvalidChainConfigError("same as the old one in ArbOS state")
+	}
+	if len(oldSerializedConfig) != 0 {
+		var oldConfig params.ChainConfig
+		err = json.Unmarshal(oldSerializedConfig, &oldConfig)
 		if err != nil {
-			return fmt.Errorf("failed to get chain id from ArbOS state: %w", err)
+			return fmt.Errorf("failed to deserialize old chain config: %w", err)
 		}
-		if newConfig.ChainID.Cmp(chainId) != 0 {
-			return fmt.Errorf("invalid chain config, chain id mismatch, want: %v, have: %v", chainId, newConfig.ChainID)
+		if err := oldConfig.CheckCompatible(&newConfig, evm.Context.BlockNumber.Uint64(), evm.Context.Time); err != nil {
+			return con.InvalidChainConfigError(fmt.Sprintf("not compatible with previous: %v", err))
 		}
-		oldSerializedConfig, err := c.State.ChainConfig()
-		if err != nil {
-			return fmt.Errorf("failed to get old chain config from ArbOS state: %w", err)
+		if err := checkMaxCodeSize(&oldConfig, &newConfig); err != nil {
+			return con.InvalidChainConfigError(err.Error())
+		}
+	}
+	return nil
+}
+
+// validateChainConfigForNode checks a new config is compatible with the node's chain config,
+// and that this node knows all of its arbitrum params, so it won't brick the node on restart
+func (con ArbOwner) validateChainConfigForNode(evm mech, serializedChainConfig []byte) error {
+	var newConfig params.ChainConfig
+	if err := json.Unmarshal(serializedChainConfig, &newConfig); err != nil {
+		return con.InvalidChainConfigError(fmt.Sprintf("can't deserialize: %v", err))
+	}
+	if err := checkArbitrumParamsFields(serializedChainConfig); err != nil {
+		return con.InvalidChainConfigError(fmt.Sprintf("unknown arbitrum params: %v", err))
+	}
+	currentConfig := evm.ChainConfig()
+	if err := currentConfig.CheckCompatible(&newConfig, evm.Context.BlockNumber.Uint64(), evm.Context.Time); err != nil {
+		return con.InvalidChainConfigError(fmt.Sprintf("not compatible with EVM's chain config: %v", err))
+	}
+	if err := checkMaxCodeSize(currentConfig, &newConfig); err != nil {
+		return con.InvalidChainConfigError(err.Error())
+	}
+	return nil
+}
+
+// checkArbitrumParamsFields rejects arbitrum params this node doesn't know, which would otherwise be silently dropped
+func checkArbitrumParamsFields(serializedChainConfig []byte) error {
+	var config struct {
+		Arbitrum json.RawMessage `json:"arbitrum"`
+	}
+	if err := json.Unmarshal(serializedChainConfig, &config); err != nil || len(config.Arbitrum) == 0 {
+		return err
+	}
+	decoder := json.NewDecoder(bytes.NewReader(config.Arbitrum))
+	decoder.DisallowUnknownFields()
+	var arbitrumParams params.ArbitrumChainParams
+	return decoder.Decode(&arbitrumParams)
+}
+
+// checkMaxCodeSize ensures a new chain config doesn't lower the maximum code size below the limit of a previous
+// config, as contracts up to that size may have been deployed and could become uncallable
+func checkMaxCodeSize(previous *params.ChainConfig, newConfig *params.ChainConfig) error {
+	if newConfig.MaxCodeSize() < previous.MaxCodeSize() {
+		return fmt.Errorf(
+			"max code size %v is below %v, the size contracts may have been deployed with",
+			newConfig.MaxCodeSize(), previous.MaxCodeSize(),
+		)
+	}
+	return nil
+}
+
+// Multicall runs several owner calls to this precompile in order, reverting all of them if any fails
+func (con ArbOwner) Multicall(c ctx, evm mech, calls [][]byte) error {
+	multicallID := con.precompile.GetMethodID("Multicall")
+	for i, input := range calls {
+		if len(input) < 4 {
+			return fmt.Errorf("multicall call %v has no method selector", i)
 		}
-		if bytes.Equal(oldSerializedConfig, serializedChainConfig) {
-			return errors.New("new chain config is the same as old one in ArbOS state")
+		method := *(*bytes4)(input)
+		if method == multicallID {
+			return errors.New("multicalls can't be nested")
 		}
-		if len(oldSerializedConfig) != 0 {
-			var oldConfig params.ChainConfig
-			err = json.Unmarshal(oldSerializedConfig, &oldConfig)
-			if err != nil {
-				return fmt.Errorf("failed to deserialize old chain config: %w", err)
-			}
-			if err := oldConfig.CheckCompatible(&newConfig, evm.Context.BlockNumber.Uint64(), evm.Context.Time); err != nil {
-				return fmt.Errorf("invalid chain config, not compatible with previous: %w", err)
+		// the caller's ownership was checked when entering the multicall
+		output, gasLeft, err := con.precompile.Call(input, con.Address, con.Address, c.caller, common.Big0, false, c.gasLeft, evm)
+		c.gasLeft = gasLeft
+		if err != nil {
+			// revert with the call's solidity error, if any, so callers can decode it like any other revert
+			if solErr := con.precompile.solErrorFromRevert(output); solErr != nil {
+				return solErr
 			}
+			return fmt.Errorf("multicall call %v failed: %w", i, err)
 		}
-		currentConfig := evm.ChainConfig()
-		if err := currentConfig.CheckCompatible(&newConfig, evm.Context.BlockNumber.Uint64(), evm.Context.Time); err != nil {
-			return fmt.Errorf("invalid chain config, not compatible with EVM's chain config: %w", err)
+		if err := con.emitOwnerActs(evm, method, c.caller, input); err != nil {
+			return err
 		}
 	}
-	return c.State.SetChainConfig(serializedChainConfig)
+	return nil
 }
@@ -49,11 +49,42 @@ func (con ArbOwnerPublic) GetInfraFeeAccount(c ctx, evm mech) (addr, error) {
 	return c.State.InfraFeeAccount()
 }
 
+// GetL2BaseFeeMinimum gets the minimum base fee needed for a transaction to succeed, as set by ArbOwner.SetMinimumL2BaseFee
+func (con ArbOwnerPublic) GetL2BaseFeeMinimum(c ctx, evm mech) (huge, error) {
+	return c.State.L2PricingState().MinBaseFeeWei()
+}
+
+// GetGasPaymaster gets the account that pays for the gas of transactions offering no fee, or 0 if there's none
+func (con ArbOwnerPublic) GetGasPaymaster(c ctx, evm mech) (addr, error) {
+	return c.State.GasPaymaster()
+}
+
+// IsGasPaymasterSponsoredSender checks if the gas paymaster pays for the sender's transactions offering no fee
+func (con ArbOwnerPublic) IsGasPaymasterSponsoredSender(c ctx, evm mech, sender addr) (bool, error) {
+	return c.State.GasPaymasterSponsoredSenders().IsMember(sender)
+}
+
+// GetAllGasPaymasterSponsoredSenders gets the senders the gas paymaster pays for
+func (con ArbOwnerPublic) GetAllGasPaymasterSponsoredSenders(c ctx, evm mech) ([]addr, error) {
+	return c.State.GasPaymasterSponsoredSenders().AllMembers(65536)
+}
+
+// GetCompressionDictionary gets the id and hash of the dictionary batches should be compressed with,
+// or zeros if there's none
+func (con ArbOwnerPublic) GetCompressionDictionary(c ctx, evm mech) (uint8, bytes32, error) {
+	return c.State.ActiveCompressionDictionary()
+}
+
 // GetBrotliCompressionLevel gets the current brotli compression level used for fast compression
 func (con ArbOwnerPublic) GetBrotliCompressionLevel(c ctx, evm mech) (uint64, error) {
 	return c.State.BrotliCompressionLevel()
 }
 
+// GetL1TxBatchCompressionDisabled gets whether batches should be posted uncompressed
+func (con ArbOwnerPublic) GetL1TxBatchCompressionDisabled(c ctx, evm mech) (bool, error) {
+	return c.State.L1TxBatchCompressionDisabled()
+}
+
 // GetScheduledUpgrade gets the next scheduled ArbOS version upgrade and its activation timestamp.
 // Returns (0, 0, nil) if no ArbOS upgrade is scheduled.
 func (con ArbOwnerPublic) GetScheduledUpgrade(c ctx, evm mech) (uint64, uint64, error) {
@@ -66,3 +97,28 @@ func (con ArbOwnerPublic) GetScheduledUpgrade(c ctx, evm mech) (uint64, uint64,
 	}
 	return version, timestamp, nil
 }
+
+// GetAllScheduledUpgrades gets the pending ArbOS version upgrades and their activation timestamps, in the order they'll happen.
+func (con ArbOwnerPublic) GetAllScheduledUpgrades(c ctx, evm mech) ([]uint64, []uint64, error) {
+	pending, err := c.State.PendingArbOSUpgrades()
+	if err != nil {
+		return nil, nil, err
+	}
+	versions := make([]uint64, 0, len(pending))
+	timestamps := make([]uint64, 0, len(pending))
+	for _, upgrade := range pending {
+		versions = append(versions, upgrade.Version)
+		timestamps = append(timestamps, upgrade.Timestamp)
+	}
+	return versions, timestamps, nil
+}
+
+// GetL2ChainNamespace gets the human-readable identifier of the chain, which is empty if the chain owner hasn't set one
+func (con ArbOwnerPublic) GetL2ChainNamespace(c ctx, evm mech) (string, error) {
+	return c.State.ChainNamespace()
+}
+
+// GetChainConfig gets the serialized chain config stored in ArbOS state
+func (con ArbOwnerPublic) GetChainConfig(c ctx, evm mech) ([]byte, error) {
+	return c.State.ChainConfig()
+}
@@ -21,8 +21,10 @@ import (
 	"github.com/offchainlabs/nitro/arbos/arbosState"
 	"github.com/offchainlabs/nitro/arbos/burn"
 	"github.com/offchainlabs/nitro/arbos/l1pricing"
+	"github.com/offchainlabs/nitro/arbos/l2pricing"
 	"github.com/offchainlabs/nitro/arbos/util"
 	"github.com/offchainlabs/nitro/cmd/chaininfo"
+	templates "github.com/offchainlabs/nitro/solgen/go/precompilesgen"
 	"github.com/offchainlabs/nitro/util/testhelpers"
 )
 
@@ -39,6 +41,7 @@ func TestArbOwner(t *testing.T) {
 	addr3 := common.BytesToAddress(crypto.Keccak256([]byte{3})[:20])
 
 	prec := &ArbOwner{}
+	MakePrecompile(templates.ArbOwnerMetaData, prec) // binds the events AddChainOwner emits
 	gasInfo := &ArbGasInfo{}
 	callCtx := testContext(caller, evm)
 
@@ -163,7 +166,7 @@ func TestArbOwner(t *testing.T) {
 		Fail(t, "Expected", addr1, "got", retrievedNetworkFeeAccount)
 	}
 
-	l2BaseFee := big.NewInt(123)
+	l2BaseFee := big.NewInt(l2pricing.InitialMinimumBaseFeeWei + 123)
 	err = prec.SetL2BaseFee(callCtx, evm, l2BaseFee)
 	Require(t, err)
 	retrievedL2BaseFee, err := state.L2PricingState().BaseFeeWei()
@@ -8,6 +8,7 @@ import (
 	"math/big"
 
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/params"
 
@@ -91,14 +92,10 @@ func (con ArbRetryableTx) Redeem(c ctx, evm mech, ticketId bytes32) (bytes32, er
 
 	// figure out how much gas the event issuance will cost, and reduce the donated gas amount in the event
 	//     by that much, so that we'll donate the correct amount of gas
-	eventCost, err := con.RedeemScheduledGasCost(hash{}, hash{}, 0, 0, addr{}, common.Big0, common.Big0)
+	futureGasCosts, err := con.redeemFutureGasCosts()
 	if err != nil {
 		return hash{}, err
 	}
-	// Result is 32 bytes long which is 1 word
-	gasCostToReturnResult := params.CopyGas
-	gasPoolUpdateCost := storage.StorageReadCost + storage.StorageWriteCost
-	futureGasCosts := eventCost + gasCostToReturnResult + gasPoolUpdateCost
 	if c.gasLeft < futureGasCosts {
 		return hash{}, c.Burn(futureGasCosts) // this will error
 	}
@@ -130,11 +127,86 @@ func (con ArbRetryableTx) Redeem(c ctx, evm mech, ticketId bytes32) (bytes32, er
 	return retryTxHash, c.State.L2PricingState().AddToGasPool(arbmath.SaturatingCast[int64](gasToDonate))
 }
 
+// redeemFutureGasCosts is the gas Redeem holds back from the retry it schedules,
+// to pay for emitting its event, returning its result, and updating the gas pool
+func (con ArbRetryableTx) redeemFutureGasCosts() (uint64, error) {
+	eventCost, err := con.RedeemScheduledGasCost(hash{}, hash{}, 0, 0, addr{}, common.Big0, common.Big0)
+	if err != nil {
+		return 0, err
+	}
+	// Result is 32 bytes long which is 1 word
+	gasCostToReturnResult := params.CopyGas
+	gasPoolUpdateCost := storage.StorageReadCost + storage.StorageWriteCost
+	return eventCost + gasCostToReturnResult + gasPoolUpdateCost, nil
+}
+
+// EstimateRedeemGas gets the gas a call to Redeem needs for the retry it schedules to be able to start.
+// This is what Redeem charges before donating the rest of its gas, plus the retry's intrinsic gas.
+// It doesn't include whatever the retry spends executing its calldata, which only running it can tell.
+func (con ArbRetryableTx) EstimateRedeemGas(c ctx, evm mech, ticketId bytes32) (uint64, error) {
+	retryableState := c.State.RetryableState()
+	byteCount, err := retryableState.RetryableSizeBytes(ticketId, evm.Context.Time)
+	if err != nil {
+		return 0, err
+	}
+	retryable, err := retryableState.OpenRetryable(ticketId, evm.Context.Time)
+	if err != nil {
+		return 0, err
+	}
+	if retryable == nil {
+		return 0, con.NoTicketWithIDError()
+	}
+	to, err := retryable.To()
+	if err != nil {
+		return 0, err
+	}
+	calldata, err := retryable.Calldata()
+	if err != nil {
+		return 0, err
+	}
+	futureGasCosts, err := con.redeemFutureGasCosts()
+	if err != nil {
+		return 0, err
+	}
+
+	// account for everything Redeem charges, in the order it does so
+	readCost := storage.StorageReadCost
+	calldataWords := arbmath.WordsForBytes(uint64(len(calldata)))
+	gas := params.CopyGas                                                // copying in the ticket id
+	gas += readCost                                                      // opening the ArbOS state
+	gas += 2*readCost + params.SloadGas*arbmath.WordsForBytes(byteCount) // sizing the retryable
+	gas += readCost                                                      // opening the retryable
+	gas += readCost + storage.StorageWriteCost                           // incrementing its number of tries
+	gas += 3*readCost + (1+calldataWords)*readCost                       // reading its sender, destination, callvalue, and calldata
+	gas += futureGasCosts
+
+	chainConfig := evm.ChainConfig()
+	blockNumber := evm.Context.BlockNumber
+	intrinsicGas, err := core.IntrinsicGas(
+		calldata,
+		nil,
+		to == nil,
+		chainConfig.IsHomestead(blockNumber),
+		chainConfig.IsIstanbul(blockNumber),
+		chainConfig.IsShanghai(blockNumber, evm.Context.Time, evm.Context.ArbOSVersion),
+	)
+	if err != nil {
+		return 0, err
+	}
+	return gas + intrinsicGas, nil
+}
+
 // GetLifetime gets the default lifetime period a retryable has at creation
 func (con ArbRetryableTx) GetLifetime(c ctx, evm mech) (huge, error) {
 	return big.NewInt(retryables.RetryableLifetimeSeconds), nil
 }
 
+// GetLifetimeAndCurrentTime gets the default lifetime period a retryable has at creation, along with the current time,
+// so callers can compute a ticket's remaining lifetime in one call
+func (con ArbRetryableTx) GetLifetimeAndCurrentTime(c ctx, evm mech) (huge, uint64, error) {
+	return big.NewInt(retryables.RetryableLifetimeSeconds), evm.Context.Time, nil
+}
+
 // GetTimeout gets the timestamp for when ticket will expire
 func (con ArbRetryableTx) GetTimeout(c ctx, evm mech, ticketId bytes32) (huge, error) {
 	retryableState := c.State.RetryableState()
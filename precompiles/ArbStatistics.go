@@ -5,6 +5,8 @@ package precompiles
 
 import (
 	"math/big"
+
+	"github.com/offchainlabs/nitro/util/arbmath"
 )
 
 // ArbStatistics provides statistics about the rollup right before the Nitro upgrade.
@@ -24,3 +26,9 @@ func (con ArbStatistics) GetStats(c ctx, evm mech) (huge, huge, huge, huge, huge
 	classicNumContracts := big.NewInt(0) // TODO: hardcode the final value from Arbitrum Classic
 	return blockNum, classicNumAccounts, classicStorageSum, classicGasSum, classicNumTxes, classicNumContracts, nil
 }
+
+// GetTotalGasUsed returns the cumulative L2 gas used by transactions since genesis
+func (con ArbStatistics) GetTotalGasUsed(c ctx, evm mech) (huge, error) {
+	total, err := c.State.TotalGasUsed()
+	return arbmath.UintToBig(total), err
+}
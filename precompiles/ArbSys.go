@@ -187,6 +187,31 @@ func (con *ArbSys) SendTxToL1(c ctx, evm mech, value huge, destination addr, cal
 	return sendHash.Big(), err
 }
 
+// SendTxToL1WithProofInfo sends a transaction to L1 like SendTxToL1, additionally returning the send's
+// position in the outbox Merkle tree so that callers can build outbox proofs without parsing events
+func (con *ArbSys) SendTxToL1WithProofInfo(
+	c ctx, evm mech, value huge, destination addr, calldataForL1 []byte,
+) (huge, huge, []bytes32, error) {
+	leafNum, err := con.SendTxToL1(c, evm, value, destination, calldataForL1)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	merkleAcc := c.State.SendMerkleAccumulator()
+	size, err := merkleAcc.Size()
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	rawPartials, err := merkleAcc.GetPartials()
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	partials := make([]bytes32, len(rawPartials))
+	for i, par := range rawPartials {
+		partials[i] = *par
+	}
+	return leafNum, new(big.Int).SetUint64(size), partials, nil
+}
+
 // SendMerkleTreeState gets the root, size, and partials of the outbox Merkle tree state (caller must be the 0 address)
 func (con ArbSys) SendMerkleTreeState(c ctx, evm mech) (huge, bytes32, []bytes32, error) {
 	if c.caller != (addr{}) {
@@ -203,11 +228,22 @@ func (con ArbSys) SendMerkleTreeState(c ctx, evm mech) (huge, bytes32, []bytes32
 	return new(big.Int).SetUint64(size), rootHash, partials, nil
 }
 
+// GetL2ToL1MerkleTreeSize gets the number of sends in the outbox Merkle tree, which bounds the valid send indices
+func (con ArbSys) GetL2ToL1MerkleTreeSize(c ctx, evm mech) (uint64, error) {
+	return c.State.SendMerkleAccumulator().Size()
+}
+
 // WithdrawEth send paid eth to the destination on L1
 func (con ArbSys) WithdrawEth(c ctx, evm mech, value huge, destination addr) (huge, error) {
 	return con.SendTxToL1(c, evm, value, destination, []byte{})
 }
 
+// WithdrawEthToContract sends paid eth to the destination on L1 along with calldata for it to be called with.
+// Like SendTxToL1, the calldata is paid for per word as it's copied in and hashed into the send.
+func (con ArbSys) WithdrawEthToContract(c ctx, evm mech, value huge, destination addr, data []byte) (huge, error) {
+	return con.SendTxToL1(c, evm, value, destination, data)
+}
+
 func (con ArbSys) isTopLevel(c ctx, evm mech) bool {
 	depth := evm.Depth()
 	return depth < 2 || evm.Origin == c.txProcessor.Contracts[depth-2].Caller()
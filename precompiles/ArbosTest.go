@@ -1,13 +1,19 @@
-// Copyright 2021-2022, Offchain Labs, Inc.
+// Copyright 2021-2024, Offchain Labs, Inc.
 // For license information, see https://github.com/nitro/blob/master/LICENSE
 
 package precompiles
 
 import (
+	"encoding/binary"
 	"errors"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/params"
 )
 
 // ArbosTest provides a method of burning arbitrary amounts of gas, which exists for historical reasons.
+// On dev chains it can also grow and shrink the state for testing pruning and snapshots.
 type ArbosTest struct {
 	Address addr // 0x69
 }
@@ -21,3 +27,79 @@ func (con ArbosTest) BurnArbGas(c ctx, gasAmount huge) error {
 	c.Burn(gasAmount.Uint64()) // burn the amount, even if it's more than the user has
 	return nil
 }
+
+// WriteStorageSlots fills count pseudorandom storage slots derived from the seed, charging the usual SSTORE gas
+func (con ArbosTest) WriteStorageSlots(c ctx, evm mech, count uint64, seed bytes32) error {
+	if err := con.checkStateTestingAllowed(c, evm); err != nil {
+		return err
+	}
+	for i := uint64(0); i < count; i++ {
+		slot := storageTestSlot(seed, i)
+		if err := con.setState(c, evm, slot, crypto.Keccak256Hash(slot[:])); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ClearStorageSlots deletes the slots written by WriteStorageSlots with the same count and seed
+func (con ArbosTest) ClearStorageSlots(c ctx, evm mech, count uint64, seed bytes32) error {
+	if err := con.checkStateTestingAllowed(c, evm); err != nil {
+		return err
+	}
+	for i := uint64(0); i < count; i++ {
+		if err := con.setState(c, evm, storageTestSlot(seed, i), common.Hash{}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (con ArbosTest) checkStateTestingAllowed(c ctx, evm mech) error {
+	if !evm.ChainConfig().DebugMode() {
+		return errors.New("state testing is only available on dev chains")
+	}
+	isOwner, err := c.State.ChainOwners().IsMember(c.caller)
+	if err != nil {
+		return err
+	}
+	if !isOwner {
+		return ErrNotOwner
+	}
+	return nil
+}
+
+// setState writes a slot of this precompile's storage, charging and refunding gas as an EIP-2929 and
+// EIP-3529 SSTORE would if the slot's value at the start of the transaction were its current value
+func (con ArbosTest) setState(c ctx, evm mech, slot common.Hash, value common.Hash) error {
+	cost := uint64(0)
+	if _, slotWarm := evm.StateDB.SlotInAccessList(con.Address, slot); !slotWarm {
+		cost += params.ColdSloadCostEIP2929
+		evm.StateDB.AddSlotToAccessList(con.Address, slot)
+	}
+	current := evm.StateDB.GetState(con.Address, slot)
+	refund := uint64(0)
+	switch {
+	case current == value:
+		cost += params.WarmStorageReadCostEIP2929
+	case current == (common.Hash{}):
+		cost += params.SstoreSetGasEIP2200
+	default:
+		cost += params.SstoreResetGasEIP2200 - params.ColdSloadCostEIP2929
+		if value == (common.Hash{}) {
+			refund = params.SstoreClearsScheduleRefundEIP3529
+		}
+	}
+	if err := c.Burn(cost); err != nil {
+		return err
+	}
+	evm.StateDB.SetState(con.Address, slot, value)
+	if refund > 0 {
+		evm.StateDB.AddRefund(refund)
+	}
+	return nil
+}
+
+func storageTestSlot(seed bytes32, index uint64) common.Hash {
+	return crypto.Keccak256Hash(seed[:], binary.BigEndian.AppendUint64(nil, index))
+}
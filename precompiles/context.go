@@ -32,6 +32,10 @@ type Context struct {
 	State       *arbosState.ArbosState
 	tracingInfo *util.TracingInfo
 	readOnly    bool
+
+	// the storage accesses charged for, counted to audit precompile gas costs
+	storageReads  uint64
+	storageWrites uint64
 }
 
 func (c *Context) Burn(amount uint64) error {
@@ -42,11 +46,18 @@ func (c *Context) Burn(amount uint64) error {
 	return nil
 }
 
-//nolint:unused
 func (c *Context) Burned() uint64 {
 	return c.gasSupplied - c.gasLeft
 }
 
+func (c *Context) CountStorageAccess(write bool) {
+	if write {
+		c.storageWrites++
+	} else {
+		c.storageReads++
+	}
+}
+
 func (c *Context) BurnOut() error {
 	c.gasLeft = 0
 	return vm.ErrOutOfGas
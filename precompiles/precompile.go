@@ -7,6 +7,7 @@ import (
 	"bytes"
 	"errors"
 	"fmt"
+	"math"
 	"math/big"
 	"reflect"
 	"sort"
@@ -115,6 +116,20 @@ func (e *SolError) Error() string {
 	return rendered
 }
 
+// solErrorFromRevert recovers the solidity error a call to the precompile reverted with,
+// or returns nil if the revert data isn't one of the precompile's errors.
+func (p *Precompile) solErrorFromRevert(data []byte) *SolError {
+	if len(data) < 4 {
+		return nil
+	}
+	for _, precompileErr := range p.errors {
+		if bytes.Equal(precompileErr.template.ID[:4], data[:4]) {
+			return &SolError{data: data, solErr: precompileErr.template}
+		}
+	}
+	return nil
+}
+
 // MakePrecompile makes a precompile for the given hardhat-to-geth bindings, ensuring that the implementer
 // supports each method.
 func MakePrecompile(metadata *bind.MetaData, implementer interface{}) (addr, *Precompile) {
@@ -487,7 +502,9 @@ func MakePrecompile(metadata *bind.MetaData, implementer interface{}) (addr, *Pr
 				return []reflect.Value{reflect.ValueOf(err)}
 			}
 
-			customErr := &SolError{data: append(capturedSolErr.ID[:4], data...), solErr: capturedSolErr}
+			// copy the selector so the revert data never shares the ID's backing array
+			revertData := append(common.CopyBytes(capturedSolErr.ID[:4]), data...)
+			customErr := &SolError{data: revertData, solErr: capturedSolErr}
 
 			return []reflect.Value{reflect.ValueOf(customErr)}
 		}
@@ -500,6 +517,25 @@ func MakePrecompile(metadata *bind.MetaData, implementer interface{}) (addr, *Pr
 		}
 	}
 
+	// fields without a solidity event or error are never set, so fail now rather than mid-block
+	for i := 0; i < implementerType.Elem().NumField(); i++ {
+		field := implementerType.Elem().Field(i)
+		if field.Type.Kind() != reflect.Func {
+			continue
+		}
+		if name, ok := strings.CutSuffix(field.Name, "GasCost"); ok {
+			if _, ok := events[name]; !ok {
+				panic(contract + " is missing a solidity interface for event " + name)
+			}
+		} else if name, ok := strings.CutSuffix(field.Name, "Error"); ok {
+			if _, ok := errors[name]; !ok {
+				panic(contract + " is missing a solidity interface for error " + name)
+			}
+		} else if _, ok := events[field.Name]; !ok {
+			panic(contract + " is missing a solidity interface for event " + field.Name)
+		}
+	}
+
 	return address, &Precompile{
 		methods,
 		methodsByName,
@@ -521,10 +557,14 @@ func Precompiles() map[addr]ArbosPrecompile {
 	}
 
 	insert(MakePrecompile(pgen.ArbInfoMetaData, &ArbInfo{Address: types.ArbInfoAddress}))
-	insert(MakePrecompile(pgen.ArbAddressTableMetaData, &ArbAddressTable{Address: types.ArbAddressTableAddress}))
+	ArbAddressTable := insert(MakePrecompile(pgen.ArbAddressTableMetaData, &ArbAddressTable{Address: types.ArbAddressTableAddress}))
+	ArbAddressTable.methodsByName["RegisterMany"].arbosVersion = util.ArbosVersion_40
+	ArbAddressTable.methodsByName["LookupMany"].arbosVersion = util.ArbosVersion_40
 	insert(MakePrecompile(pgen.ArbBLSMetaData, &ArbBLS{Address: types.ArbBLSAddress}))
 	insert(MakePrecompile(pgen.ArbFunctionTableMetaData, &ArbFunctionTable{Address: types.ArbFunctionTableAddress}))
-	insert(MakePrecompile(pgen.ArbosTestMetaData, &ArbosTest{Address: types.ArbosTestAddress}))
+	ArbosTest := insert(MakePrecompile(pgen.ArbosTestMetaData, &ArbosTest{Address: types.ArbosTestAddress}))
+	ArbosTest.methodsByName["WriteStorageSlots"].arbosVersion = util.ArbosVersion_40
+	ArbosTest.methodsByName["ClearStorageSlots"].arbosVersion = util.ArbosVersion_40
 	ArbGasInfo := insert(MakePrecompile(pgen.ArbGasInfoMetaData, &ArbGasInfo{Address: types.ArbGasInfoAddress}))
 	ArbGasInfo.methodsByName["GetL1FeesAvailable"].arbosVersion = params.ArbosVersion_10
 	ArbGasInfo.methodsByName["GetL1RewardRate"].arbosVersion = params.ArbosVersion_11
@@ -534,8 +574,22 @@ func Precompiles() map[addr]ArbosPrecompile {
 	ArbGasInfo.methodsByName["GetL1PricingFundsDueForRewards"].arbosVersion = params.ArbosVersion_20
 	ArbGasInfo.methodsByName["GetL1PricingUnitsSinceUpdate"].arbosVersion = params.ArbosVersion_20
 	ArbGasInfo.methodsByName["GetLastL1PricingSurplus"].arbosVersion = params.ArbosVersion_20
-	insert(MakePrecompile(pgen.ArbAggregatorMetaData, &ArbAggregator{Address: types.ArbAggregatorAddress}))
-	insert(MakePrecompile(pgen.ArbStatisticsMetaData, &ArbStatistics{Address: types.ArbStatisticsAddress}))
+	ArbGasInfo.methodsByName["GetArbGasToWeiRate"].arbosVersion = util.ArbosVersion_40
+	ArbGasInfo.methodsByName["GetGasPriceForBacklog"].arbosVersion = util.ArbosVersion_40
+	ArbGasInfo.methodsByName["GetL1BatchEthPaymentAddress"].arbosVersion = util.ArbosVersion_40
+	ArbGasInfo.methodsByName["GetL1GasUsedLastBatch"].arbosVersion = util.ArbosVersion_40
+	ArbGasInfo.methodsByName["GetL1BlockNumberStaleness"].arbosVersion = util.ArbosVersion_40
+	ArbGasInfo.methodsByName["GetL1BlockTimeSeconds"].arbosVersion = util.ArbosVersion_40
+	ArbGasInfo.methodsByName["GetL1PricingDataGasFactor"].arbosVersion = util.ArbosVersion_40
+	ArbGasInfo.methodsByName["GetTipDistribution"].arbosVersion = util.ArbosVersion_40
+	ArbGasInfo.methodsByName["GetL1PricingThrottlePercent"].arbosVersion = util.ArbosVersion_40
+	ArbAggregator := insert(MakePrecompile(pgen.ArbAggregatorMetaData, &ArbAggregator{Address: types.ArbAggregatorAddress}))
+	ArbAggregator.methodsByName["GetAllFeeCollectors"].arbosVersion = util.ArbosVersion_40
+	ArbAggregator.methodsByName["SetFeeCollectors"].arbosVersion = util.ArbosVersion_40
+	ArbAggregator.methodsByName["RemoveBatchPoster"].arbosVersion = util.ArbosVersion_40
+	ArbAggregator.methodsByName["GetBatchPosterStats"].arbosVersion = util.ArbosVersion_40
+	ArbStatistics := insert(MakePrecompile(pgen.ArbStatisticsMetaData, &ArbStatistics{Address: types.ArbStatisticsAddress}))
+	ArbStatistics.methodsByName["GetTotalGasUsed"].arbosVersion = util.ArbosVersion_40
 
 	eventCtx := func(gasLimit uint64, err error) *Context {
 		if err != nil {
@@ -553,6 +607,15 @@ func Precompiles() map[addr]ArbosPrecompile {
 	ArbOwnerPublic.methodsByName["RectifyChainOwner"].arbosVersion = params.ArbosVersion_11
 	ArbOwnerPublic.methodsByName["GetBrotliCompressionLevel"].arbosVersion = params.ArbosVersion_20
 	ArbOwnerPublic.methodsByName["GetScheduledUpgrade"].arbosVersion = params.ArbosVersion_20
+	ArbOwnerPublic.methodsByName["GetAllScheduledUpgrades"].arbosVersion = util.ArbosVersion_40
+	ArbOwnerPublic.methodsByName["GetGasPaymaster"].arbosVersion = util.ArbosVersion_40
+	ArbOwnerPublic.methodsByName["IsGasPaymasterSponsoredSender"].arbosVersion = util.ArbosVersion_40
+	ArbOwnerPublic.methodsByName["GetAllGasPaymasterSponsoredSenders"].arbosVersion = util.ArbosVersion_40
+	ArbOwnerPublic.methodsByName["GetCompressionDictionary"].arbosVersion = util.ArbosVersion_40
+	ArbOwnerPublic.methodsByName["GetL2BaseFeeMinimum"].arbosVersion = util.ArbosVersion_40
+	ArbOwnerPublic.methodsByName["GetL2ChainNamespace"].arbosVersion = util.ArbosVersion_40
+	ArbOwnerPublic.methodsByName["GetChainConfig"].arbosVersion = util.ArbosVersion_40
+	ArbOwnerPublic.methodsByName["GetL1TxBatchCompressionDisabled"].arbosVersion = util.ArbosVersion_40
 
 	ArbWasmImpl := &ArbWasm{Address: types.ArbWasmAddress}
 	ArbWasm := insert(MakePrecompile(pgen.ArbWasmMetaData, ArbWasmImpl))
@@ -579,6 +642,8 @@ func Precompiles() map[addr]ArbosPrecompile {
 	ArbRetryableImpl := &ArbRetryableTx{Address: types.ArbRetryableTxAddress}
 	ArbRetryable := insert(MakePrecompile(pgen.ArbRetryableTxMetaData, ArbRetryableImpl))
 	arbos.ArbRetryableTxAddress = ArbRetryable.address
+	ArbRetryable.methodsByName["EstimateRedeemGas"].arbosVersion = util.ArbosVersion_40
+	ArbRetryable.methodsByName["GetLifetimeAndCurrentTime"].arbosVersion = util.ArbosVersion_40
 	arbos.RedeemScheduledEventID = ArbRetryable.events["RedeemScheduled"].template.ID
 	arbos.EmitReedeemScheduledEvent = func(
 		evm mech, gas, nonce uint64, ticketId, retryTxHash bytes32,
@@ -596,6 +661,9 @@ func Precompiles() map[addr]ArbosPrecompile {
 	}
 
 	ArbSys := insert(MakePrecompile(pgen.ArbSysMetaData, &ArbSys{Address: types.ArbSysAddress}))
+	ArbSys.methodsByName["SendTxToL1WithProofInfo"].arbosVersion = util.ArbosVersion_40
+	ArbSys.methodsByName["WithdrawEthToContract"].arbosVersion = util.ArbosVersion_40
+	ArbSys.methodsByName["GetL2ToL1MerkleTreeSize"].arbosVersion = util.ArbosVersion_40
 	arbos.ArbSysAddress = ArbSys.address
 	arbos.L2ToL1TransactionEventID = ArbSys.events["L2ToL1Transaction"].template.ID
 	arbos.L2ToL1TxEventID = ArbSys.events["L2ToL1Tx"].template.ID
@@ -606,11 +674,27 @@ func Precompiles() map[addr]ArbosPrecompile {
 		return ArbOwnerImpl.OwnerActs(context, evm, method, owner, data)
 	}
 	_, ArbOwner := MakePrecompile(pgen.ArbOwnerMetaData, ArbOwnerImpl)
+	ArbOwnerImpl.precompile = ArbOwner
+	ArbOwnerImpl.emitOwnerActs = emitOwnerActs
+	arbos.OwnerActsEventID = ArbOwner.events["OwnerActs"].template.ID
 	ArbOwner.methodsByName["GetInfraFeeAccount"].arbosVersion = params.ArbosVersion_5
 	ArbOwner.methodsByName["SetInfraFeeAccount"].arbosVersion = params.ArbosVersion_5
 	ArbOwner.methodsByName["ReleaseL1PricerSurplusFunds"].arbosVersion = params.ArbosVersion_10
 	ArbOwner.methodsByName["SetChainConfig"].arbosVersion = params.ArbosVersion_11
 	ArbOwner.methodsByName["SetBrotliCompressionLevel"].arbosVersion = params.ArbosVersion_20
+	ArbOwner.methodsByName["SetRetryableAutoRedeemGasLimit"].arbosVersion = util.ArbosVersion_40
+	ArbOwner.methodsByName["Multicall"].arbosVersion = util.ArbosVersion_40
+	ArbOwner.methodsByName["SetGasPaymaster"].arbosVersion = util.ArbosVersion_40
+	ArbOwner.methodsByName["AddGasPaymasterSponsoredSender"].arbosVersion = util.ArbosVersion_40
+	ArbOwner.methodsByName["RemoveGasPaymasterSponsoredSender"].arbosVersion = util.ArbosVersion_40
+	ArbOwner.methodsByName["SetL1BatchEthPaymentAddress"].arbosVersion = util.ArbosVersion_40
+	ArbOwner.methodsByName["SetCompressionDictionary"].arbosVersion = util.ArbosVersion_40
+	ArbOwner.methodsByName["SetL1PricingDataGasFactor"].arbosVersion = util.ArbosVersion_40
+	ArbOwner.methodsByName["SetTipDistribution"].arbosVersion = util.ArbosVersion_40
+	ArbOwner.methodsByName["SetL2ChainNamespace"].arbosVersion = util.ArbosVersion_40
+	ArbOwner.methodsByName["SetL1PricingThrottlePercent"].arbosVersion = util.ArbosVersion_40
+	ArbOwner.methodsByName["SetL1BlockTimeSeconds"].arbosVersion = util.ArbosVersion_40
+	ArbOwner.methodsByName["SetL1TxBatchCompressionDisabled"].arbosVersion = util.ArbosVersion_40
 	stylusMethods := []string{
 		"SetInkPrice", "SetWasmMaxStackDepth", "SetWasmFreePages", "SetWasmPageGas",
 		"SetWasmPageLimit", "SetWasmMinInitGas", "SetWasmInitCostScalar",
@@ -622,8 +706,12 @@ func Precompiles() map[addr]ArbosPrecompile {
 	}
 
 	insert(ownerOnly(ArbOwnerImpl.Address, ArbOwner, emitOwnerActs))
-	_, arbDebug := MakePrecompile(pgen.ArbDebugMetaData, &ArbDebug{Address: types.ArbDebugAddress})
+	ArbDebugImpl := &ArbDebug{Address: types.ArbDebugAddress}
+	_, arbDebug := MakePrecompile(pgen.ArbDebugMetaData, ArbDebugImpl)
 	arbDebug.methodsByName["Panic"].arbosVersion = params.ArbosVersion_Stylus
+	arbDebug.methodsByName["EmitLogs"].arbosVersion = util.ArbosVersion_40
+	arbDebug.methodsByName["SetPrecompileGasAudit"].arbosVersion = util.ArbosVersion_40
+	gasAuditLogger = ArbDebugImpl
 	insert(debugOnly(arbDebug.address, arbDebug))
 
 	ArbosActs := insert(MakePrecompile(pgen.ArbosActsMetaData, &ArbosActs{Address: types.ArbosAddress}))
@@ -656,6 +744,22 @@ func (p *Precompile) ArbosVersion() uint64 {
 	return p.arbosVersion
 }
 
+func (p *Precompile) Name() string {
+	return p.name
+}
+
+// MethodName gets the solidity name of the method the calldata selects, if any
+func (p *Precompile) MethodName(input []byte) (string, bool) {
+	if len(input) < 4 {
+		return "", false
+	}
+	method, ok := p.methods[[4]byte(input[:4])]
+	if !ok {
+		return "", false
+	}
+	return method.template.RawName, true
+}
+
 // Call a precompile in typed form, deserializing its inputs and serializing its outputs
 func (p *Precompile) Call(
 	input []byte,
@@ -667,6 +771,8 @@ func (p *Precompile) Call(
 	gasSupplied uint64,
 	evm *vm.EVM,
 ) (output []byte, gasLeft uint64, err error) {
+	// the version is that of the state being executed against, so calls against historical blocks
+	// dispatch to the methods and formulas that were active at the time
 	arbosVersion := arbosState.ArbOSVersion(evm.StateDB)
 
 	if arbosVersion < p.arbosVersion {
@@ -816,9 +922,36 @@ func (p *Precompile) Call(
 		return nil, 0, vm.ErrExecutionReverted
 	}
 
+	if !readOnly && evm.ChainConfig().DebugMode() && arbosState.PrecompileGasAuditEnabled(evm.StateDB) {
+		logGasUsage(callerCtx, evm, precompileAddress, id)
+	}
+
 	return encoded, callerCtx.gasLeft, nil
 }
 
+// gasAuditLogger emits the events of precompile gas audits
+var gasAuditLogger *ArbDebug
+
+// logGasUsage logs the gas a precompile call charged and the storage it accessed.
+// The log isn't charged for, so auditing doesn't change the gas used.
+func logGasUsage(callerCtx *Context, evm mech, precompileAddress addr, method bytes4) {
+	if gasAuditLogger == nil {
+		return
+	}
+	auditCtx := &Context{
+		caller:      callerCtx.caller,
+		gasSupplied: math.MaxUint64,
+		gasLeft:     math.MaxUint64,
+		tracingInfo: callerCtx.tracingInfo,
+	}
+	err := gasAuditLogger.PrecompileGasUsage(
+		auditCtx, evm, precompileAddress, method, callerCtx.Burned(), callerCtx.storageReads, callerCtx.storageWrites,
+	)
+	if err != nil {
+		log.Error("failed to log precompile gas usage", "precompile", precompileAddress, "err", err)
+	}
+}
+
 func (p *Precompile) Precompile() *Precompile {
 	return p
 }
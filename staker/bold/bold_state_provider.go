@@ -4,6 +4,7 @@
 package bold
 
 import (
+	"bytes"
 	"context"
 	"errors"
 	"fmt"
@@ -510,7 +511,11 @@ func (s *BOLDStateProvider) CollectProof(
 			"messageNum", messageNum,
 			"machineIndex", machineIndex,
 		)
-		return m.ProveNextStep(), nil
+		var proof bytes.Buffer
+		if _, err := m.ProveNextStep(&proof); err != nil {
+			return nil, err
+		}
+		return proof.Bytes(), nil
 	}
 	entry, err := s.statelessValidator.CreateReadyValidationEntry(ctx, messageNum)
 	if err != nil {
@@ -163,7 +163,7 @@ func runChallengeTest(
 
 	asserterRun, err := server_arb.NewExecutionRun(ctx,
 		func(context.Context) (server_arb.MachineInterface, error) { return asserterMachine, nil },
-		&server_arb.DefaultMachineCacheConfig)
+	)
 	Require(t, err)
 
 	asserterManager, err := NewExecutionChallengeManager(
@@ -179,7 +179,7 @@ func runChallengeTest(
 
 	challengerRun, err := server_arb.NewExecutionRun(ctx,
 		func(context.Context) (server_arb.MachineInterface, error) { return challengerMachine, nil },
-		&server_arb.DefaultMachineCacheConfig)
+	)
 	Require(t, err)
 	challengerManager, err := NewExecutionChallengeManager(
 		backend,
@@ -5,6 +5,7 @@ package legacystaker
 
 import (
 	"context"
+	"io"
 
 	"github.com/ethereum/go-ethereum/common"
 
@@ -87,6 +88,13 @@ func (m *IncorrectMachine) Status() uint8 {
 	return m.inner.Status()
 }
 
+func (m *IncorrectMachine) GetErrorContext() *validator.MachineErrorContext {
+	if !m.IsErrored() {
+		return nil
+	}
+	return m.inner.GetErrorContext()
+}
+
 func (m *IncorrectMachine) Hash() common.Hash {
 	if m.GetStepCount() >= m.incorrectStep {
 		if m.inner.IsErrored() {
@@ -102,8 +110,8 @@ func (m *IncorrectMachine) Hash() common.Hash {
 	return m.inner.Hash()
 }
 
-func (m *IncorrectMachine) ProveNextStep() []byte {
-	return m.inner.ProveNextStep()
+func (m *IncorrectMachine) ProveNextStep(w io.Writer) (int, error) {
+	return m.inner.ProveNextStep(w)
 }
 
 func (m *IncorrectMachine) Freeze() {
@@ -0,0 +1,212 @@
+// Copyright 2024, Offchain Labs, Inc.
+// For license information, see https://github.com/nitro/blob/master/LICENSE
+
+package legacystaker
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"math/big"
+	"strings"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/metrics"
+
+	"github.com/offchainlabs/nitro/solgen/go/rollupgen"
+)
+
+var rollupIncompatibleGauge = metrics.NewRegisteredGauge("arb/staker/rollup/incompatible", nil)
+
+// The rollup proxy dispatches admin calls to its primary implementation and everything else to its secondary one.
+var (
+	rollupAdminLogicSlot = eip1967Slot("eip1967.proxy.implementation")
+	rollupUserLogicSlot  = eip1967Slot("eip1967.proxy.implementation.secondary")
+)
+
+func eip1967Slot(name string) common.Hash {
+	slot := new(big.Int).SetBytes(crypto.Keccak256([]byte(name)))
+	return common.BigToHash(slot.Sub(slot, common.Big1))
+}
+
+// requiredRollupMethods are the rollup user logic methods the staker sends transactions to,
+// and that must therefore exist in whatever implementation the rollup has been upgraded to.
+var requiredRollupMethods = []string{
+	"stakeOnNewNode",
+	"stakeOnExistingNode",
+	"returnOldDeposit",
+	"withdrawStakerFunds",
+	"confirmNextNode",
+	"rejectNextNode",
+	"createChallenge",
+}
+
+var requiredRollupSelectors = make(map[string][]byte)
+
+func init() {
+	parsedRollup, err := rollupgen.RollupUserLogicMetaData.GetAbi()
+	if err != nil {
+		panic(err)
+	}
+	for _, name := range requiredRollupMethods {
+		method, ok := parsedRollup.Methods[name]
+		if !ok {
+			panic(fmt.Sprintf("rollup user logic abi is missing method %v", name))
+		}
+		requiredRollupSelectors[name] = method.ID
+	}
+}
+
+// RollupContractVersion identifies the rollup contracts the staker is acting against.
+type RollupContractVersion struct {
+	AdminLogic          common.Address
+	UserLogic           common.Address
+	ChallengeManager    common.Address
+	ConfirmPeriodBlocks uint64
+}
+
+func (v *RollupContractVersion) String() string {
+	return fmt.Sprintf(
+		"adminLogic=%v userLogic=%v challengeManager=%v confirmPeriodBlocks=%v",
+		v.AdminLogic, v.UserLogic, v.ChallengeManager, v.ConfirmPeriodBlocks,
+	)
+}
+
+type RollupVersionL1Interface interface {
+	bind.ContractCaller
+	StorageAt(ctx context.Context, account common.Address, key common.Hash, blockNumber *big.Int) ([]byte, error)
+}
+
+// RollupVersionWatcher notices when the rollup contracts are upgraded underneath the staker,
+// and whether the staker can still safely send transactions to the new implementation.
+type RollupVersionWatcher struct {
+	client        RollupVersionL1Interface
+	rollupAddress common.Address
+	rollup        *rollupgen.RollupUserLogicCaller
+	callOpts      bind.CallOpts
+
+	mutex            sync.Mutex
+	challengeManager common.Address // the challenge manager the staker was set up with
+	current          *RollupContractVersion
+	incompatibility  error
+}
+
+func NewRollupVersionWatcher(client RollupVersionL1Interface, rollupAddress common.Address, callOpts bind.CallOpts) (*RollupVersionWatcher, error) {
+	rollup, err := rollupgen.NewRollupUserLogicCaller(rollupAddress, client)
+	if err != nil {
+		return nil, err
+	}
+	return &RollupVersionWatcher{
+		client:        client,
+		rollupAddress: rollupAddress,
+		rollup:        rollup,
+		callOpts:      callOpts,
+	}, nil
+}
+
+func (w *RollupVersionWatcher) readVersion(ctx context.Context) (*RollupContractVersion, error) {
+	callOpts := w.callOpts
+	callOpts.Context = ctx
+	adminLogic, err := w.client.StorageAt(ctx, w.rollupAddress, rollupAdminLogicSlot, callOpts.BlockNumber)
+	if err != nil {
+		return nil, fmt.Errorf("error reading rollup admin logic: %w", err)
+	}
+	userLogic, err := w.client.StorageAt(ctx, w.rollupAddress, rollupUserLogicSlot, callOpts.BlockNumber)
+	if err != nil {
+		return nil, fmt.Errorf("error reading rollup user logic: %w", err)
+	}
+	challengeManager, err := w.rollup.ChallengeManager(&callOpts)
+	if err != nil {
+		return nil, fmt.Errorf("error reading rollup challenge manager: %w", err)
+	}
+	confirmPeriodBlocks, err := w.rollup.ConfirmPeriodBlocks(&callOpts)
+	if err != nil {
+		return nil, fmt.Errorf("error reading rollup confirm period: %w", err)
+	}
+	return &RollupContractVersion{
+		AdminLogic:          common.BytesToAddress(adminLogic),
+		UserLogic:           common.BytesToAddress(userLogic),
+		ChallengeManager:    challengeManager,
+		ConfirmPeriodBlocks: confirmPeriodBlocks,
+	}, nil
+}
+
+// rollupIncompatibility returns why the staker can't act against the given version, if it can't.
+func rollupIncompatibility(version *RollupContractVersion, challengeManager common.Address, userLogicCode []byte) error {
+	if version.ChallengeManager != challengeManager {
+		return fmt.Errorf("rollup challenge manager changed from %v to %v", challengeManager, version.ChallengeManager)
+	}
+	if len(userLogicCode) == 0 {
+		return fmt.Errorf("rollup user logic %v has no code", version.UserLogic)
+	}
+	var missing []string
+	for _, name := range requiredRollupMethods {
+		// the dispatcher pushes each function's selector onto the stack to compare against the calldata
+		if !bytes.Contains(userLogicCode, append([]byte{byte(vm.PUSH4)}, requiredRollupSelectors[name]...)) {
+			missing = append(missing, name)
+		}
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("rollup user logic %v is missing methods %v", version.UserLogic, strings.Join(missing, ", "))
+	}
+	return nil
+}
+
+// Check reads the rollup's current version and, if it has changed, whether the staker supports it.
+// The first version checked is taken as the one the staker was set up against.
+func (w *RollupVersionWatcher) Check(ctx context.Context) (*RollupContractVersion, error) {
+	version, err := w.readVersion(ctx)
+	if err != nil {
+		return nil, err
+	}
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	if w.current == nil {
+		w.challengeManager = version.ChallengeManager
+	} else if *w.current == *version {
+		return version, nil
+	}
+	userLogicCode, err := w.client.CodeAt(ctx, version.UserLogic, w.callOpts.BlockNumber)
+	if err != nil {
+		return nil, fmt.Errorf("error reading rollup user logic code: %w", err)
+	}
+	incompatibility := rollupIncompatibility(version, w.challengeManager, userLogicCode)
+	if w.current != nil {
+		log.Warn("rollup contracts upgraded", "from", w.current, "to", version)
+	}
+	if incompatibility != nil {
+		log.Error(
+			"rollup contracts upgraded to a version this node doesn't support, pausing all but defensive staker actions until the node is upgraded",
+			"version", version,
+			"err", incompatibility,
+		)
+		rollupIncompatibleGauge.Update(1)
+	} else {
+		if w.incompatibility != nil {
+			log.Info("rollup contracts are supported again, resuming staker actions", "version", version)
+		}
+		rollupIncompatibleGauge.Update(0)
+	}
+	w.current = version
+	w.incompatibility = incompatibility
+	return version, nil
+}
+
+// Version returns the last version checked, or nil if none has been.
+func (w *RollupVersionWatcher) Version() *RollupContractVersion {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	return w.current
+}
+
+// Incompatibility returns why the staker doesn't support the last version checked, or nil if it does.
+func (w *RollupVersionWatcher) Incompatibility() error {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	return w.incompatibility
+}
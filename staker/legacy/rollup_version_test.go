@@ -0,0 +1,190 @@
+// Copyright 2024, Offchain Labs, Inc.
+// For license information, see https://github.com/nitro/blob/master/LICENSE
+
+package legacystaker
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/vm"
+
+	"github.com/offchainlabs/nitro/solgen/go/rollupgen"
+)
+
+// mockRollupProxy stands in for a rollup proxy on L1 whose implementations can be swapped out.
+type mockRollupProxy struct {
+	mutex               sync.Mutex
+	address             common.Address
+	slots               map[common.Hash]common.Hash
+	code                map[common.Address][]byte
+	challengeManager    common.Address
+	confirmPeriodBlocks uint64
+}
+
+func newMockRollupProxy() *mockRollupProxy {
+	return &mockRollupProxy{
+		address:             common.HexToAddress("0x1000"),
+		slots:               make(map[common.Hash]common.Hash),
+		code:                make(map[common.Address][]byte),
+		challengeManager:    common.HexToAddress("0x2000"),
+		confirmPeriodBlocks: 45818,
+	}
+}
+
+// deploy places a fake implementation at the given address whose dispatcher knows the given methods.
+func (p *mockRollupProxy) deploy(implementation common.Address, methods []string) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	code := []byte{byte(vm.PUSH1), 0x80, byte(vm.PUSH1), 0x40, byte(vm.MSTORE)}
+	for _, name := range methods {
+		code = append(code, byte(vm.PUSH4))
+		code = append(code, requiredRollupSelectors[name]...)
+		code = append(code, byte(vm.EQ))
+	}
+	p.code[implementation] = code
+}
+
+func (p *mockRollupProxy) upgrade(adminLogic common.Address, userLogic common.Address) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	p.slots[rollupAdminLogicSlot] = common.BytesToHash(adminLogic.Bytes())
+	p.slots[rollupUserLogicSlot] = common.BytesToHash(userLogic.Bytes())
+}
+
+func (p *mockRollupProxy) setChallengeManager(challengeManager common.Address) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	p.challengeManager = challengeManager
+}
+
+func (p *mockRollupProxy) setConfirmPeriodBlocks(blocks uint64) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	p.confirmPeriodBlocks = blocks
+}
+
+func (p *mockRollupProxy) StorageAt(ctx context.Context, account common.Address, key common.Hash, blockNumber *big.Int) ([]byte, error) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	if account != p.address {
+		return make([]byte, 32), nil
+	}
+	value := p.slots[key]
+	return value.Bytes(), nil
+}
+
+func (p *mockRollupProxy) CodeAt(ctx context.Context, contract common.Address, blockNumber *big.Int) ([]byte, error) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	return p.code[contract], nil
+}
+
+func (p *mockRollupProxy) CallContract(ctx context.Context, call ethereum.CallMsg, blockNumber *big.Int) ([]byte, error) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	if call.To == nil || *call.To != p.address || len(call.Data) < 4 {
+		return nil, nil
+	}
+	parsedRollup, err := rollupgen.RollupUserLogicMetaData.GetAbi()
+	if err != nil {
+		return nil, err
+	}
+	method, err := parsedRollup.MethodById(call.Data[:4])
+	if err != nil {
+		return nil, err
+	}
+	switch method.Name {
+	case "challengeManager":
+		return method.Outputs.Pack(p.challengeManager)
+	case "confirmPeriodBlocks":
+		return method.Outputs.Pack(p.confirmPeriodBlocks)
+	default:
+		return nil, fmt.Errorf("unexpected call to %v", method.Name)
+	}
+}
+
+func checkRollupVersion(t *testing.T, watcher *RollupVersionWatcher, userLogic common.Address, expectedIncompatibility string) {
+	t.Helper()
+	version, err := watcher.Check(context.Background())
+	Require(t, err)
+	if version.UserLogic != userLogic {
+		Fail(t, "expected user logic", userLogic, "got", version.UserLogic)
+	}
+	incompatibility := watcher.Incompatibility()
+	if expectedIncompatibility == "" {
+		if incompatibility != nil {
+			Fail(t, "unexpected incompatibility with", version, incompatibility)
+		}
+		return
+	}
+	if incompatibility == nil || !strings.Contains(incompatibility.Error(), expectedIncompatibility) {
+		Fail(t, "expected incompatibility", expectedIncompatibility, "with", version, "got", incompatibility)
+	}
+}
+
+func TestRollupVersionWatcher(t *testing.T) {
+	proxy := newMockRollupProxy()
+	adminLogic := common.HexToAddress("0x3000")
+	userLogic := common.HexToAddress("0x3001")
+	upgradedUserLogic := common.HexToAddress("0x3002")
+	brokenUserLogic := common.HexToAddress("0x3003")
+	missingUserLogic := common.HexToAddress("0x3004")
+	proxy.deploy(adminLogic, nil)
+	proxy.deploy(userLogic, requiredRollupMethods)
+	proxy.deploy(upgradedUserLogic, requiredRollupMethods)
+	proxy.deploy(brokenUserLogic, requiredRollupMethods[:len(requiredRollupMethods)-1])
+	proxy.upgrade(adminLogic, userLogic)
+
+	watcher, err := NewRollupVersionWatcher(proxy, proxy.address, bind.CallOpts{})
+	Require(t, err)
+	if watcher.Version() != nil {
+		Fail(t, "expected no version before checking")
+	}
+	checkRollupVersion(t, watcher, userLogic, "")
+
+	// upgrades that keep the methods the staker relies on are supported
+	proxy.upgrade(adminLogic, upgradedUserLogic)
+	checkRollupVersion(t, watcher, upgradedUserLogic, "")
+	proxy.setConfirmPeriodBlocks(100)
+	checkRollupVersion(t, watcher, upgradedUserLogic, "")
+	if blocks := watcher.Version().ConfirmPeriodBlocks; blocks != 100 {
+		Fail(t, "expected confirm period change to be noticed, got", blocks)
+	}
+
+	// swap in an implementation without a method the staker calls
+	proxy.upgrade(adminLogic, brokenUserLogic)
+	checkRollupVersion(t, watcher, brokenUserLogic, requiredRollupMethods[len(requiredRollupMethods)-1])
+	// it stays unsupported while the rollup is unchanged
+	checkRollupVersion(t, watcher, brokenUserLogic, requiredRollupMethods[len(requiredRollupMethods)-1])
+
+	proxy.upgrade(adminLogic, missingUserLogic)
+	checkRollupVersion(t, watcher, missingUserLogic, "has no code")
+
+	// rolling back the upgrade resumes normal operation
+	proxy.upgrade(adminLogic, userLogic)
+	checkRollupVersion(t, watcher, userLogic, "")
+
+	// the staker's wallet still points at the old challenge manager
+	proxy.setChallengeManager(common.HexToAddress("0x2001"))
+	checkRollupVersion(t, watcher, userLogic, "challenge manager changed")
+	proxy.setChallengeManager(common.HexToAddress("0x2000"))
+	checkRollupVersion(t, watcher, userLogic, "")
+}
+
+func TestEip1967Slots(t *testing.T) {
+	// as hardcoded in OpenZeppelin's and the rollup's proxies
+	if rollupAdminLogicSlot != common.HexToHash("0x360894a13ba1a3210667c828492db98dca3e2076cc3735a920a3ca505d382bbc") {
+		Fail(t, "wrong implementation slot", rollupAdminLogicSlot)
+	}
+	if rollupUserLogicSlot != common.HexToHash("0x2b1dbce74324248c222f0ec2d5ed7bd323cfc425b336f0253c5ccfda7265546d") {
+		Fail(t, "wrong secondary implementation slot", rollupUserLogicSlot)
+	}
+}
@@ -278,6 +278,7 @@ type Staker struct {
 	statelessBlockValidator *staker.StatelessBlockValidator
 	fatalErr                chan<- error
 	fastConfirmSafe         *FastConfirmSafe
+	rollupVersion           *RollupVersionWatcher
 }
 
 type ValidatorWalletInterface interface {
@@ -322,6 +323,10 @@ func NewStaker(
 	if err != nil {
 		return nil, err
 	}
+	rollupVersion, err := NewRollupVersionWatcher(client, val.rollupAddress, callOpts)
+	if err != nil {
+		return nil, err
+	}
 	stakerLastSuccessfulActionGauge.Update(time.Now().Unix())
 	inactiveValidatedNodes := btree.NewG(2, func(a, b validatedNode) bool {
 		return a.number < b.number || (a.number == b.number && a.hash.Cmp(b.hash) < 0)
@@ -339,6 +344,7 @@ func NewStaker(
 		statelessBlockValidator: statelessBlockValidator,
 		fatalErr:                fatalErr,
 		inactiveValidatedNodes:  inactiveValidatedNodes,
+		rollupVersion:           rollupVersion,
 	}, nil
 }
 
@@ -347,6 +353,10 @@ func (s *Staker) Initialize(ctx context.Context) error {
 	if err != nil {
 		return err
 	}
+	rollupVersion, err := s.rollupVersion.Check(ctx)
+	if err != nil {
+		return fmt.Errorf("error checking rollup contract version: %w", err)
+	}
 	walletAddressOrZero := s.wallet.AddressOrZero()
 	if walletAddressOrZero != (common.Address{}) {
 		s.updateStakerBalanceMetric(ctx)
@@ -365,6 +375,7 @@ func (s *Staker) Initialize(ctx context.Context) error {
 		"actingAsWallet", walletAddressOrZero,
 		"whitelisted", whiteListed,
 		"strategy", s.Strategy(),
+		"rollupVersion", rollupVersion,
 	)
 	if s.blockValidator != nil && s.config().StartValidationFromStaked {
 		latestStaked, _, err := s.validatorUtils.LatestStaked(&s.baseCallOpts, s.rollupAddress, walletAddressOrZero)
@@ -551,6 +562,10 @@ func (s *Staker) Start(ctxIn context.Context) {
 		if err != nil {
 			log.Warn("error updating latest wasm module root", "err", err)
 		}
+		_, err = s.rollupVersion.Check(ctx)
+		if err != nil {
+			log.Warn("error checking rollup contract version", "err", err)
+		}
 		arbTx, err := s.Act(ctx)
 		if err == nil && arbTx != nil {
 			_, err = s.l1Reader.WaitForTxApproval(ctx, arbTx)
@@ -765,6 +780,12 @@ func (s *Staker) Act(ctx context.Context) (*types.Transaction, error) {
 	}
 
 	effectiveStrategy := cfg.StrategyType()
+	rollupIncompatibility := s.rollupVersion.Incompatibility()
+	if rollupIncompatibility != nil && effectiveStrategy > DefensiveStrategy {
+		// transactions built against an unsupported rollup would likely revert, so only act if we must defend the chain
+		log.Warn("only taking defensive staker actions as the rollup contracts aren't supported", "version", s.rollupVersion.Version(), "err", rollupIncompatibility)
+		effectiveStrategy = DefensiveStrategy
+	}
 	nodesLinear, err := s.validatorUtils.AreUnresolvedNodesLinear(callOpts, s.rollupAddress)
 	if err != nil {
 		return nil, fmt.Errorf("error checking for rollup assertion fork: %w", err)
@@ -792,7 +813,7 @@ func (s *Staker) Act(ctx context.Context) (*types.Transaction, error) {
 		info.LatestStakedNodeHash = s.inactiveLastCheckedNode.hash
 	}
 
-	if cfg.EnableFastConfirmation {
+	if cfg.EnableFastConfirmation && rollupIncompatibility == nil {
 		firstUnresolvedNode, err := s.rollup.FirstUnresolvedNode(callOpts)
 		if err != nil {
 			return nil, err
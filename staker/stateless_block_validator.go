@@ -17,6 +17,7 @@ import (
 	"github.com/ethereum/go-ethereum/params"
 
 	"github.com/offchainlabs/nitro/arbos/arbostypes"
+	"github.com/offchainlabs/nitro/arbstate"
 	"github.com/offchainlabs/nitro/arbstate/daprovider"
 	"github.com/offchainlabs/nitro/arbutil"
 	"github.com/offchainlabs/nitro/execution"
@@ -54,6 +55,7 @@ type InboxTrackerInterface interface {
 	GetBatchAcc(seqNum uint64) (common.Hash, error)
 	GetBatchCount() (uint64, error)
 	FindInboxBatchContainingMessage(pos arbutil.MessageIndex) (uint64, bool, error)
+	GetCompressionDictionary(hash common.Hash) ([]byte, error)
 }
 
 type TransactionStreamerInterface interface {
@@ -316,11 +318,12 @@ func (v *StatelessBlockValidator) readFullBatch(ctx context.Context, batchNum ui
 	}
 	preimages := make(map[arbutil.PreimageType]map[common.Hash][]byte)
 	if len(postedData) > 40 {
+		payload := postedData[40:]
 		foundDA := false
 		for _, dapReader := range v.dapReaders {
 			if dapReader != nil && dapReader.IsValidHeaderByte(postedData[40]) {
 				preimageRecorder := daprovider.RecordPreimagesTo(preimages)
-				_, err := dapReader.RecoverPayloadFromBatch(ctx, batchNum, batchBlockHash, postedData, preimageRecorder, true)
+				payload, err = dapReader.RecoverPayloadFromBatch(ctx, batchNum, batchBlockHash, postedData, preimageRecorder, true)
 				if err != nil {
 					// Matches the way keyset validation was done inside DAS readers i.e logging the error
 					//  But other daproviders might just want to return the error
@@ -339,6 +342,17 @@ func (v *StatelessBlockValidator) readFullBatch(ctx context.Context, batchNum ui
 				log.Error("No DAS Reader configured, but sequencer message found with DAS header")
 			}
 		}
+		// replay looks up the dictionary the batch is compressed with as a preimage
+		if hash, ok := arbstate.BatchCompressionDictionary(payload); ok {
+			dictionary, err := v.inboxTracker.GetCompressionDictionary(hash)
+			if err != nil {
+				return false, nil, err
+			}
+			if preimages[arbutil.Keccak256PreimageType] == nil {
+				preimages[arbutil.Keccak256PreimageType] = make(map[common.Hash][]byte)
+			}
+			preimages[arbutil.Keccak256PreimageType][hash] = dictionary
+		}
 	}
 	fullInfo := FullBatchInfo{
 		Number:     batchNum,
@@ -11,6 +11,7 @@ import (
 
 type ArbosInitializationInfo struct {
 	NextBlockNumber      uint64
+	GenesisTimestamp     uint64 // only used if NextBlockNumber is 0, as later genesis blocks follow their parent
 	AddressTableContents []common.Address
 	RetryableData        []InitializationDataForRetryable
 	Accounts             []AccountInitializationInfo
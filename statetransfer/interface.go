@@ -15,6 +15,7 @@ type InitDataReader interface {
 	Close() error
 	GetAddressTableReader() (AddressReader, error)
 	GetNextBlockNumber() (uint64, error)
+	GetGenesisTimestamp() (uint64, error)
 	GetRetryableDataReader() (RetryableDataReader, error)
 	GetAccountDataReader() (AccountDataReader, error)
 	GetChainOwner() (common.Address, error)
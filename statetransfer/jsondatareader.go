@@ -15,6 +15,7 @@ import (
 
 type ArbosInitFileContents struct {
 	NextBlockNumber          uint64 `json:"NextBlockNumber"`
+	GenesisTimestamp         uint64 `json:"GenesisTimestamp"`
 	AddressTableContentsPath string `json:"AddressTableContentsPath"`
 	RetryableDataPath        string `json:"RetryableDataPath"`
 	AccountsPath             string `json:"AccountsPath"`
@@ -29,6 +30,10 @@ func (r *JsonInitDataReader) GetNextBlockNumber() (uint64, error) {
 	return r.data.NextBlockNumber, nil
 }
 
+func (r *JsonInitDataReader) GetGenesisTimestamp() (uint64, error) {
+	return r.data.GenesisTimestamp, nil
+}
+
 type JsonListReader struct {
 	input *json.Decoder
 	file  *os.File
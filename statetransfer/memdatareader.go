@@ -21,6 +21,10 @@ func (r *MemoryInitDataReader) GetNextBlockNumber() (uint64, error) {
 	return r.d.NextBlockNumber, nil
 }
 
+func (r *MemoryInitDataReader) GetGenesisTimestamp() (uint64, error) {
+	return r.d.GenesisTimestamp, nil
+}
+
 type FieldReader struct {
 	m      *MemoryInitDataReader
 	count  int
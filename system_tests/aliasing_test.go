@@ -36,7 +36,7 @@ func TestAliasing(t *testing.T) {
 	Require(t, err)
 
 	// Test direct calls
-	arbsys, err := precompilesgen.NewArbSys(types.ArbSysAddress, builder.L2.Client)
+	arbsys, err := precompilesgen.NewArbSys(types.ArbSysAddress, builder.L2Client())
 	Require(t, err)
 	top, err := arbsys.IsTopLevelCall(nil)
 	Require(t, err)
@@ -64,7 +64,7 @@ func TestAliasing(t *testing.T) {
 		data, err := simpleContract.Pack("checkCalls", top, direct, static, delegate, callcode, call)
 		Require(t, err)
 		tx = builder.L2Info.PrepareTxTo("Owner", &simpleAddr, 500000, big.NewInt(0), data)
-		builder.L1.SendSignedTx(t, builder.L2.Client, tx, builder.L1Info)
+		builder.L1.SendSignedTx(t, builder.L2Client(), tx, builder.L1Info)
 	}
 
 	testUnsigned := func(top, direct, static, delegate, callcode, call bool) {
@@ -74,7 +74,7 @@ func TestAliasing(t *testing.T) {
 		data, err := simpleContract.Pack("checkCalls", top, direct, static, delegate, callcode, call)
 		Require(t, err)
 		tx := builder.L2Info.PrepareTxTo("Owner", &simpleAddr, 500000, big.NewInt(0), data)
-		builder.L1.SendUnsignedTx(t, builder.L2.Client, tx, builder.L1Info)
+		builder.L1.SendUnsignedTx(t, builder.L2Client(), tx, builder.L1Info)
 	}
 
 	testL2Signed(true, true, false, false, false, false)
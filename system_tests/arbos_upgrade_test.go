@@ -6,17 +6,22 @@ package arbtest
 import (
 	"context"
 	"math/big"
+	"slices"
 	"strings"
+	"sync"
 	"testing"
 	"time"
 
 	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/params"
 
 	"github.com/offchainlabs/nitro/arbnode"
 	"github.com/offchainlabs/nitro/arbos/arbosState"
+	"github.com/offchainlabs/nitro/arbos/util"
 	"github.com/offchainlabs/nitro/execution/gethexec"
+	"github.com/offchainlabs/nitro/precompiles"
 	"github.com/offchainlabs/nitro/solgen/go/mocksgen"
 	"github.com/offchainlabs/nitro/solgen/go/precompilesgen"
 )
@@ -31,10 +36,10 @@ func TestScheduleArbosUpgrade(t *testing.T) {
 
 	auth := builder.L2Info.GetDefaultTransactOpts("Owner", ctx)
 
-	arbOwnerPublic, err := precompilesgen.NewArbOwnerPublic(common.HexToAddress("0x6b"), builder.L2.Client)
+	arbOwnerPublic, err := precompilesgen.NewArbOwnerPublic(common.HexToAddress("0x6b"), builder.L2Client())
 	Require(t, err, "could not bind ArbOwner contract")
 
-	arbOwner, err := precompilesgen.NewArbOwner(common.HexToAddress("0x70"), builder.L2.Client)
+	arbOwner, err := precompilesgen.NewArbOwner(common.HexToAddress("0x70"), builder.L2Client())
 	Require(t, err, "could not bind ArbOwner contract")
 
 	callOpts := &bind.CallOpts{Context: ctx}
@@ -69,6 +74,293 @@ func TestScheduleArbosUpgrade(t *testing.T) {
 	if scheduled.ArbosVersion != testVersion || scheduled.ScheduledForTimestamp != testTimestamp {
 		t.Errorf("expected upgrade to be scheduled for version %v timestamp %v, got version %v timestamp %v", testVersion, testTimestamp, scheduled.ArbosVersion, scheduled.ScheduledForTimestamp)
 	}
+
+	// queue a second upgrade behind the first
+	tx, err = arbOwner.ScheduleArbOSUpgrade(&auth, testVersion+1, testTimestamp+1)
+	Require(t, err)
+	_, err = builder.L2.EnsureTxSucceeded(tx)
+	Require(t, err)
+
+	all, err := arbOwnerPublic.GetAllScheduledUpgrades(callOpts)
+	Require(t, err, "failed to call GetAllScheduledUpgrades")
+	expectedVersions := []uint64{testVersion, testVersion + 1}
+	expectedTimestamps := []uint64{testTimestamp, testTimestamp + 1}
+	if !slices.Equal(all.Versions, expectedVersions) || !slices.Equal(all.Timestamps, expectedTimestamps) {
+		t.Errorf("expected upgrades %v at %v to be queued, got %v at %v", expectedVersions, expectedTimestamps, all.Versions, all.Timestamps)
+	}
+	scheduled, err = arbOwnerPublic.GetScheduledUpgrade(callOpts)
+	Require(t, err)
+	if scheduled.ArbosVersion != testVersion || scheduled.ScheduledForTimestamp != testTimestamp {
+		t.Errorf("expected the first queued upgrade to be next, got version %v timestamp %v", scheduled.ArbosVersion, scheduled.ScheduledForTimestamp)
+	}
+
+	// versions must increase along the queue
+	auth.GasLimit = 1_000_000
+	tx, err = arbOwner.ScheduleArbOSUpgrade(&auth, testVersion-1, testTimestamp+2)
+	Require(t, err)
+	_, err = builder.L2.EnsureTxSucceeded(tx)
+	if err == nil {
+		t.Error("expected scheduling a lower version than the queued ones to revert")
+	}
+	all, err = arbOwnerPublic.GetAllScheduledUpgrades(callOpts)
+	Require(t, err)
+	if !slices.Equal(all.Versions, expectedVersions) {
+		t.Errorf("expected a rejected upgrade to leave the queue alone, got %v", all.Versions)
+	}
+
+	// scheduling an already queued version again reschedules it in place
+	tx, err = arbOwner.ScheduleArbOSUpgrade(&auth, testVersion, testTimestamp-1)
+	Require(t, err)
+	_, err = builder.L2.EnsureTxSucceeded(tx)
+	Require(t, err)
+	all, err = arbOwnerPublic.GetAllScheduledUpgrades(callOpts)
+	Require(t, err)
+	expectedTimestamps = []uint64{testTimestamp - 1, testTimestamp + 1}
+	if !slices.Equal(all.Versions, expectedVersions) || !slices.Equal(all.Timestamps, expectedTimestamps) {
+		t.Errorf("expected upgrades %v at %v after rescheduling, got %v at %v", expectedVersions, expectedTimestamps, all.Versions, all.Timestamps)
+	}
+
+	// scheduling version 0 cancels every pending upgrade
+	tx, err = arbOwner.ScheduleArbOSUpgrade(&auth, 0, 0)
+	Require(t, err)
+	_, err = builder.L2.EnsureTxSucceeded(tx)
+	Require(t, err)
+	all, err = arbOwnerPublic.GetAllScheduledUpgrades(callOpts)
+	Require(t, err)
+	if len(all.Versions) != 0 || len(all.Timestamps) != 0 {
+		t.Errorf("expected canceling to clear the queue, got %v at %v", all.Versions, all.Timestamps)
+	}
+	scheduled, err = arbOwnerPublic.GetScheduledUpgrade(callOpts)
+	Require(t, err)
+	if scheduled.ArbosVersion != 0 || scheduled.ScheduledForTimestamp != 0 {
+		t.Errorf("expected no upgrade to be scheduled after canceling, got version %v timestamp %v", scheduled.ArbosVersion, scheduled.ScheduledForTimestamp)
+	}
+}
+
+func TestScheduleArbOSUpgradeOverwritesBeforeArbOS40(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	builder := NewNodeBuilder(ctx).DefaultConfig(t, false).WithArbOSVersion(params.ArbosVersion_32)
+	cleanup := builder.Build(t)
+	defer cleanup()
+
+	auth := builder.L2Info.GetDefaultTransactOpts("Owner", ctx)
+	arbOwnerPublic, err := precompilesgen.NewArbOwnerPublic(types.ArbOwnerPublicAddress, builder.L2Client())
+	Require(t, err)
+	arbOwner, err := precompilesgen.NewArbOwner(types.ArbOwnerAddress, builder.L2Client())
+	Require(t, err)
+
+	// before ArbOS 40 a second upgrade replaces the first rather than queueing behind it,
+	// and a lower version is accepted
+	var testTimestamp uint64 = 1 << 62
+	for _, version := range []uint64{100, 99} {
+		tx, err := arbOwner.ScheduleArbOSUpgrade(&auth, version, testTimestamp)
+		Require(t, err)
+		_, err = builder.L2.EnsureTxSucceeded(tx)
+		Require(t, err)
+	}
+
+	scheduled, err := arbOwnerPublic.GetScheduledUpgrade(&bind.CallOpts{Context: ctx})
+	Require(t, err)
+	if scheduled.ArbosVersion != 99 || scheduled.ScheduledForTimestamp != testTimestamp {
+		t.Errorf("expected the last upgrade to replace the first, got version %v timestamp %v", scheduled.ArbosVersion, scheduled.ScheduledForTimestamp)
+	}
+}
+
+func TestArbosUpgradeAtScheduledTimestamp(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	initialVersion := params.ArbosVersion_32
+	finalVersion := util.ArbosVersion_40
+	// well before the timestamps of the messages the sequencer will create
+	genesisTimestamp := uint64(time.Now().Add(-time.Hour).Unix())
+
+	builder := NewNodeBuilder(ctx).
+		DefaultConfig(t, false).
+		WithArbOSVersion(initialVersion).
+		WithGenesisTimestamp(genesisTimestamp)
+	cleanup := builder.Build(t)
+	defer cleanup()
+
+	genesis, err := builder.L2Client().HeaderByNumber(ctx, common.Big0)
+	Require(t, err)
+	if genesis.Time != genesisTimestamp {
+		Fatal(t, "expected the genesis block at", genesisTimestamp, "got", genesis.Time)
+	}
+
+	auth := builder.L2Info.GetDefaultTransactOpts("Owner", ctx)
+	arbOwner, err := precompilesgen.NewArbOwner(types.ArbOwnerAddress, builder.L2Client())
+	Require(t, err)
+	tx, err := arbOwner.ScheduleArbOSUpgrade(&auth, finalVersion, genesisTimestamp+1)
+	Require(t, err)
+	_, err = builder.L2.EnsureTxSucceeded(tx)
+	Require(t, err)
+	checkArbOSVersion(t, builder.L2, initialVersion, "before the next block")
+
+	// the next block is past the scheduled timestamp, so it upgrades ArbOS
+	builder.L2.TransferBalance(t, "Owner", "Owner", common.Big1, builder.L2Info)
+	checkArbOSVersion(t, builder.L2, finalVersion, "after the upgrade")
+}
+
+func TestScheduleArbOSUpgradePastTimestamp(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	initialVersion := params.ArbosVersion_32
+	finalVersion := util.ArbosVersion_40
+	builder := NewNodeBuilder(ctx).DefaultConfig(t, false).WithArbOSVersion(initialVersion)
+	cleanup := builder.Build(t)
+	defer cleanup()
+
+	arbSys, err := precompilesgen.NewArbSys(types.ArbSysAddress, builder.L2Client())
+	Require(t, err)
+	arbOwner, err := precompilesgen.NewArbOwner(types.ArbOwnerAddress, builder.L2Client())
+	Require(t, err)
+	auth := builder.L2Info.GetDefaultTransactOpts("Owner", ctx)
+	expectVersion := func(expected uint64, scenario string) {
+		t.Helper()
+		version, err := arbSys.ArbOSVersion(&bind.CallOpts{Context: ctx})
+		Require(t, err)
+		if version.Uint64() != 55+expected { // Nitro versions start at 56
+			Fatal(t, scenario, "expected ArbOS version", expected, "got", version.Uint64()-55)
+		}
+	}
+
+	// the upgrade is due as of the current block's time
+	head, err := builder.L2Client().HeaderByNumber(ctx, nil)
+	Require(t, err)
+	tx, err := arbOwner.ScheduleArbOSUpgrade(&auth, finalVersion, head.Time)
+	Require(t, err)
+	_, err = builder.L2.EnsureTxSucceeded(tx)
+	Require(t, err)
+	expectVersion(initialVersion, "before the next block")
+
+	builder.L2.TransferBalance(t, "Owner", "Owner", common.Big1, builder.L2Info)
+	expectVersion(finalVersion, "after the next block")
+}
+
+func TestArbGasInfoPricesAtPreUpgradeBlock(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	initialVersion := params.ArbosVersion_3
+	finalVersion := params.ArbosVersion_4
+	builder := NewNodeBuilder(ctx).DefaultConfig(t, false).WithArbOSVersion(initialVersion)
+	cleanup := builder.Build(t)
+	defer cleanup()
+
+	arbOwner, err := precompilesgen.NewArbOwner(types.ArbOwnerAddress, builder.L2Client())
+	Require(t, err)
+	arbGasInfo, err := precompilesgen.NewArbGasInfo(types.ArbGasInfoAddress, builder.L2Client())
+	Require(t, err)
+	auth := builder.L2Info.GetDefaultTransactOpts("Owner", ctx)
+
+	builder.L2.TransferBalance(t, "Owner", "Owner", common.Big1, builder.L2Info)
+	preUpgradeBlock, err := builder.L2Client().BlockNumber(ctx)
+	Require(t, err)
+
+	tx, err := arbOwner.ScheduleArbOSUpgrade(&auth, finalVersion, 0)
+	Require(t, err)
+	_, err = builder.L2.EnsureTxSucceeded(tx)
+	Require(t, err)
+	builder.L2.TransferBalance(t, "Owner", "Owner", common.Big1, builder.L2Info)
+	checkArbOSVersion(t, builder.L2, finalVersion, "after the upgrade")
+
+	// before ArbOS 4, the per tx price in ArbGas was the assumed size of a simple tx
+	opts := &bind.CallOpts{Context: ctx, BlockNumber: new(big.Int).SetUint64(preUpgradeBlock)}
+	perL2Tx, _, _, err := arbGasInfo.GetPricesInArbGas(opts)
+	Require(t, err)
+	if perL2Tx.Cmp(big.NewInt(precompiles.AssumedSimpleTxSize)) != 0 {
+		Fatal(t, "expected the pre-upgrade per tx price of", precompiles.AssumedSimpleTxSize, "got", perL2Tx)
+	}
+
+	// afterwards, it's the L1 cost of a simple tx converted to ArbGas
+	perL2Tx, _, _, err = arbGasInfo.GetPricesInArbGas(&bind.CallOpts{Context: ctx})
+	Require(t, err)
+	if perL2Tx.Cmp(big.NewInt(precompiles.AssumedSimpleTxSize)) == 0 {
+		Fatal(t, "expected the post-upgrade per tx price to account for the L1 cost, got", perL2Tx)
+	}
+}
+
+// TestArbOwnerPublicGetScheduledUpgradeRace reads the scheduled upgrade while upgrades are being scheduled.
+// Run it with -race to also check the node for data races between the two.
+func TestArbOwnerPublicGetScheduledUpgradeRace(t *testing.T) {
+	t.Parallel()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	builder := NewNodeBuilder(ctx).DefaultConfig(t, false)
+	cleanup := builder.Build(t)
+	defer cleanup()
+
+	auth := builder.L2Info.GetDefaultTransactOpts("Owner", ctx)
+	arbOwnerPublic, err := precompilesgen.NewArbOwnerPublic(types.ArbOwnerPublicAddress, builder.L2Client())
+	Require(t, err)
+	arbOwner, err := precompilesgen.NewArbOwner(types.ArbOwnerAddress, builder.L2Client())
+	Require(t, err)
+
+	const upgrades = 10
+	const readers = 4
+	var firstVersion uint64 = 100
+	var firstTimestamp uint64 = 1 << 62
+
+	var wg sync.WaitGroup
+	done := make(chan struct{})
+	for i := 0; i < readers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			callOpts := &bind.CallOpts{Context: ctx}
+			for {
+				select {
+				case <-done:
+					return
+				default:
+				}
+				scheduled, err := arbOwnerPublic.GetScheduledUpgrade(callOpts)
+				if err != nil {
+					t.Errorf("failed to call GetScheduledUpgrade: %v", err)
+					return
+				}
+				// later upgrades queue up behind the first, so it's the only one that can be read
+				none := scheduled.ArbosVersion == 0 && scheduled.ScheduledForTimestamp == 0
+				first := scheduled.ArbosVersion == firstVersion && scheduled.ScheduledForTimestamp == firstTimestamp
+				if !none && !first {
+					t.Errorf("read inconsistent scheduled upgrade: version %v timestamp %v", scheduled.ArbosVersion, scheduled.ScheduledForTimestamp)
+					return
+				}
+			}
+		}()
+	}
+
+	for i := uint64(0); i < upgrades; i++ {
+		tx, err := arbOwner.ScheduleArbOSUpgrade(&auth, firstVersion+i, firstTimestamp+i)
+		Require(t, err)
+		_, err = builder.L2.EnsureTxSucceeded(tx)
+		Require(t, err)
+	}
+	close(done)
+	wg.Wait()
+
+	all, err := arbOwnerPublic.GetAllScheduledUpgrades(&bind.CallOpts{Context: ctx})
+	Require(t, err)
+	if len(all.Versions) != upgrades || len(all.Timestamps) != upgrades {
+		Fatal(t, "expected", upgrades, "scheduled upgrades, got versions", all.Versions, "timestamps", all.Timestamps)
+	}
+	for i := range all.Versions {
+		// #nosec G115
+		if all.Versions[i] != firstVersion+uint64(i) || all.Timestamps[i] != firstTimestamp+uint64(i) {
+			Fatal(t, "unexpected scheduled upgrade", i, "version", all.Versions[i], "timestamp", all.Timestamps[i])
+		}
+	}
 }
 
 func checkArbOSVersion(t *testing.T, testClient *TestClient, expectedVersion uint64, scenario string) {
@@ -0,0 +1,254 @@
+// Copyright 2024, Offchain Labs, Inc.
+// For license information, see https://github.com/nitro/blob/master/LICENSE
+
+package arbtest
+
+import (
+	"bytes"
+	"context"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+
+	"github.com/offchainlabs/nitro/arbos"
+	"github.com/offchainlabs/nitro/arbos/arbostypes"
+	"github.com/offchainlabs/nitro/arbos/util"
+	"github.com/offchainlabs/nitro/solgen/go/precompilesgen"
+	"github.com/offchainlabs/nitro/util/arbmath"
+)
+
+func TestL1BatchEthPaymentAddress(t *testing.T) {
+	t.Parallel()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	builder := NewNodeBuilder(ctx).DefaultConfig(t, true)
+	builder.nodeConfig.DelayedSequencer.FinalizeDistance = 1
+	cleanup := builder.Build(t)
+	defer cleanup()
+	// SimulatedBeacon produces blocks in the future, so don't hold back batches for appearing to be from the future
+	builder.nodeConfig.BatchPoster.MaxDelay = -time.Hour
+
+	ownerAuth := builder.L2Info.GetDefaultTransactOpts("Owner", ctx)
+	callOpts := &bind.CallOpts{Context: ctx}
+	arbDebug, err := precompilesgen.NewArbDebug(types.ArbDebugAddress, builder.L2Client())
+	Require(t, err)
+	tx, err := arbDebug.BecomeChainOwner(&ownerAuth)
+	Require(t, err)
+	_, err = builder.L2.EnsureTxSucceeded(tx)
+	Require(t, err)
+	arbOwner, err := precompilesgen.NewArbOwner(types.ArbOwnerAddress, builder.L2Client())
+	Require(t, err)
+	arbGasInfo, err := precompilesgen.NewArbGasInfo(types.ArbGasInfoAddress, builder.L2Client())
+	Require(t, err)
+
+	builder.L2Info.GenerateAccount("Treasury")
+	treasuryFunds := big.NewInt(1e18)
+	builder.L2.TransferBalance(t, "Owner", "Treasury", treasuryFunds, builder.L2Info)
+	treasury := builder.L2Info.GetAddress("Treasury")
+
+	tx, err = arbOwner.SetL1BatchEthPaymentAddress(&ownerAuth, treasury)
+	Require(t, err)
+	_, err = builder.L2.EnsureTxSucceeded(tx)
+	Require(t, err)
+	paymentAddress, err := arbGasInfo.GetL1BatchEthPaymentAddress(callOpts)
+	Require(t, err)
+	if paymentAddress != treasury {
+		Fatal(t, "expected batch payment address", treasury, "got", paymentAddress)
+	}
+
+	// stop collecting L1 fees, so batch posting soon costs more than has been collected
+	tx, err = arbOwner.SetL1PricePerUnit(&ownerAuth, common.Big0)
+	Require(t, err)
+	_, err = builder.L2.EnsureTxSucceeded(tx)
+	Require(t, err)
+
+	for i := 0; ; i++ {
+		if i == 256 {
+			Fatal(t, "batch posting was never paid for by the batch payment address")
+		}
+		builder.L2.TransferBalance(t, "Owner", "Owner", common.Big1, builder.L2Info)
+		// generate L1 traffic so batches and their reports make it into L2
+		builder.L1.TransferBalance(t, "Faucet", "Faucet", common.Big1, builder.L1Info)
+		if arbmath.BigLessThan(builder.L2.GetBalance(t, treasury), treasuryFunds) {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	// without a payment address, the treasury is left alone
+	tx, err = arbOwner.SetL1BatchEthPaymentAddress(&ownerAuth, common.Address{})
+	Require(t, err)
+	receipt, err := builder.L2.EnsureTxSucceeded(tx)
+	Require(t, err)
+	treasuryBalance := builder.L2.GetBalance(t, treasury)
+	batchCount, err := builder.L2.ConsensusNode.InboxTracker.GetBatchCount()
+	Require(t, err)
+	for i := 0; ; i++ {
+		if i == 256 {
+			Fatal(t, "batches stopped being posted")
+		}
+		builder.L2.TransferBalance(t, "Owner", "Owner", common.Big1, builder.L2Info)
+		builder.L1.TransferBalance(t, "Faucet", "Faucet", common.Big1, builder.L1Info)
+		newBatchCount, err := builder.L2.ConsensusNode.InboxTracker.GetBatchCount()
+		Require(t, err)
+		if newBatchCount > batchCount+2 {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	if balance := builder.L2.GetBalance(t, treasury); !arbmath.BigEquals(balance, treasuryBalance) {
+		Fatal(t, "batch payment address paid for batch posting after being unset at block", receipt.BlockNumber, "balance", treasuryBalance, "->", balance)
+	}
+}
+
+func TestL1GasUsedLastBatch(t *testing.T) {
+	t.Parallel()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	builder := NewNodeBuilder(ctx).DefaultConfig(t, true)
+	cleanup := builder.Build(t)
+	defer cleanup()
+	// SimulatedBeacon produces blocks in the future, so don't hold back batches for appearing to be from the future
+	builder.nodeConfig.BatchPoster.MaxDelay = -time.Hour
+
+	arbGasInfo, err := precompilesgen.NewArbGasInfo(types.ArbGasInfoAddress, builder.L2Client())
+	Require(t, err)
+
+	// find the first batch posting report processed
+	var report *types.Transaction
+	var reportBlock *big.Int
+	nextBlock := uint64(1)
+	for i := 0; report == nil; i++ {
+		if i == 256 {
+			Fatal(t, "no batch posting report was processed")
+		}
+		builder.L2.TransferBalance(t, "Owner", "Owner", common.Big1, builder.L2Info)
+		// generate L1 traffic so batches and their reports make it into L2
+		builder.L1.TransferBalance(t, "Faucet", "Faucet", common.Big1, builder.L1Info)
+		latest, err := builder.L2Client().BlockNumber(ctx)
+		Require(t, err)
+		for ; nextBlock <= latest && report == nil; nextBlock++ {
+			block, err := builder.L2Client().BlockByNumber(ctx, new(big.Int).SetUint64(nextBlock))
+			Require(t, err)
+			for _, tx := range block.Transactions() {
+				if tx.Type() == types.ArbitrumInternalTxType && bytes.HasPrefix(tx.Data(), arbos.InternalTxBatchPostingReportMethodID[:]) {
+					report = tx
+					reportBlock = block.Number()
+				}
+			}
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	inputs, err := util.UnpackInternalTxDataBatchPostingReport(report.Data())
+	Require(t, err)
+	batchNum := util.SafeMapGet[uint64](inputs, "batchNumber")
+
+	// the L1 gas used is the calldata cost of the batch, plus the per batch gas cost
+	batchData, _, err := builder.L2.ConsensusNode.InboxReader.GetSequencerMessageBytes(ctx, batchNum)
+	Require(t, err)
+	callOpts := &bind.CallOpts{Context: ctx, BlockNumber: reportBlock}
+	perBatchGas, err := arbGasInfo.GetPerBatchGasCharge(callOpts)
+	Require(t, err)
+	expected := arbostypes.ComputeBatchGasCost(batchData) + arbmath.SaturatingUCast[uint64](perBatchGas)
+	l1GasUsed, err := arbGasInfo.GetL1GasUsedLastBatch(callOpts)
+	Require(t, err)
+	if l1GasUsed != expected {
+		Fatal(t, "expected batch", batchNum, "to use", expected, "L1 gas, got", l1GasUsed)
+	}
+}
+
+func TestBatchPosterStats(t *testing.T) {
+	t.Parallel()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	builder := NewNodeBuilder(ctx).DefaultConfig(t, true)
+	cleanup := builder.Build(t)
+	defer cleanup()
+	// SimulatedBeacon produces blocks in the future, so don't hold back batches for appearing to be from the future
+	builder.nodeConfig.BatchPoster.MaxDelay = -time.Hour
+
+	arbAggregator, err := precompilesgen.NewArbAggregator(types.ArbAggregatorAddress, builder.L2Client())
+	Require(t, err)
+	callOpts := &bind.CallOpts{Context: ctx}
+	poster := builder.L1Info.GetAddress("Sequencer")
+
+	lastUnits, lastFees, lastUpdate := uint64(0), new(big.Int), uint64(0)
+	for i, updates := 0, 0; updates < 2; i++ {
+		if i == 256 {
+			Fatal(t, "only saw", updates, "batch posting reports update the batch poster stats")
+		}
+		builder.L2.TransferBalance(t, "Owner", "Owner", common.Big1, builder.L2Info)
+		// generate L1 traffic so batches and their reports make it into L2
+		builder.L1.TransferBalance(t, "Faucet", "Faucet", common.Big1, builder.L1Info)
+		time.Sleep(10 * time.Millisecond)
+
+		units, fees, updateTime, err := arbAggregator.GetBatchPosterStats(callOpts, poster)
+		if err != nil {
+			// the poster only joins the table with its first batch posting report
+			continue
+		}
+		if units < lastUnits || fees.Cmp(lastFees) < 0 || updateTime < lastUpdate {
+			Fatal(t, "batch poster stats went from", lastUnits, lastFees, lastUpdate, "to", units, fees, updateTime)
+		}
+		if updateTime > lastUpdate {
+			updates++
+		}
+		lastUnits, lastFees, lastUpdate = units, fees, updateTime
+	}
+	if lastUnits == 0 {
+		Fatal(t, "batch posting reports didn't add to the units posted")
+	}
+}
+
+func TestL1BlockNumberStaleness(t *testing.T) {
+	t.Parallel()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	builder := NewNodeBuilder(ctx).DefaultConfig(t, true)
+	// batches would advance L1, which the test needs to control
+	builder.nodeConfig.BatchPoster.Enable = false
+	cleanup := builder.Build(t)
+	defer cleanup()
+
+	arbGasInfo, err := precompilesgen.NewArbGasInfo(types.ArbGasInfoAddress, builder.L2Client())
+	Require(t, err)
+	staleness := func() uint64 {
+		t.Helper()
+		staleness, err := arbGasInfo.GetL1BlockNumberStaleness(&bind.CallOpts{Context: ctx})
+		Require(t, err)
+		return staleness
+	}
+
+	// while L1 stands still, each L2 block finds its L1 block number staler
+	builder.L2.TransferBalance(t, "Owner", "Owner", common.Big1, builder.L2Info)
+	initial := staleness()
+	for i := 0; i < 2; i++ {
+		time.Sleep(1100 * time.Millisecond)
+		builder.L2.TransferBalance(t, "Owner", "Owner", common.Big1, builder.L2Info)
+	}
+	grown := staleness()
+	if grown < initial+2 {
+		Fatal(t, "expected the staleness to grow by at least 2 seconds from", initial, "got", grown)
+	}
+
+	// once L1 advances, the next L2 block reports a fresh L1 block number
+	for i := 0; ; i++ {
+		if i == 100 {
+			Fatal(t, "L1 block number staleness never dropped from", grown)
+		}
+		builder.L1.TransferBalance(t, "Faucet", "Faucet", common.Big1, builder.L1Info)
+		time.Sleep(10 * time.Millisecond)
+		builder.L2.TransferBalance(t, "Owner", "Owner", common.Big1, builder.L2Info)
+		if staleness() < grown {
+			break
+		}
+	}
+}
@@ -24,7 +24,10 @@ import (
 	"github.com/offchainlabs/nitro/arbnode"
 	"github.com/offchainlabs/nitro/arbnode/dataposter"
 	"github.com/offchainlabs/nitro/arbnode/dataposter/externalsignertest"
+	"github.com/offchainlabs/nitro/arbstate/daprovider"
+	"github.com/offchainlabs/nitro/arbutil"
 	"github.com/offchainlabs/nitro/solgen/go/bridgegen"
+	"github.com/offchainlabs/nitro/solgen/go/precompilesgen"
 	"github.com/offchainlabs/nitro/solgen/go/upgrade_executorgen"
 	"github.com/offchainlabs/nitro/util/redisutil"
 )
@@ -39,7 +42,7 @@ func TestRedisBatchPosterParallel(t *testing.T) {
 
 func addNewBatchPoster(ctx context.Context, t *testing.T, builder *NodeBuilder, address common.Address) {
 	t.Helper()
-	upgradeExecutor, err := upgrade_executorgen.NewUpgradeExecutor(builder.L2.ConsensusNode.DeployInfo.UpgradeExecutor, builder.L1.Client)
+	upgradeExecutor, err := upgrade_executorgen.NewUpgradeExecutor(builder.L2.ConsensusNode.DeployInfo.UpgradeExecutor, builder.L1Client())
 	if err != nil {
 		t.Fatal("Failed to get new upgrade executor", err)
 	}
@@ -113,7 +116,7 @@ func testBatchPosterParallel(t *testing.T, useRedis bool) {
 		tx := builder.L2Info.PrepareTx("Owner", "User2", builder.L2Info.TransferGas, common.Big1, nil)
 		txs = append(txs, tx)
 
-		err := builder.L2.Client.SendTransaction(ctx, tx)
+		err := builder.L2Client().SendTransaction(ctx, tx)
 		Require(t, err)
 	}
 
@@ -127,9 +130,9 @@ func testBatchPosterParallel(t *testing.T, useRedis bool) {
 	seqTxOpts := builder.L1Info.GetDefaultTransactOpts("Sequencer", ctx)
 	builder.nodeConfig.BatchPoster.Enable = true
 	builder.nodeConfig.BatchPoster.MaxSize = len(firstTxData) * 2
-	startL1Block, err := builder.L1.Client.BlockNumber(ctx)
+	startL1Block, err := builder.L1Client().BlockNumber(ctx)
 	Require(t, err)
-	parentChainID, err := builder.L1.Client.ChainID(ctx)
+	parentChainID, err := builder.L1Client().ChainID(ctx)
 	if err != nil {
 		t.Fatalf("Failed to get parent chain id: %v", err)
 	}
@@ -177,9 +180,9 @@ func testBatchPosterParallel(t *testing.T, useRedis bool) {
 	// However, setting the clique period to 1 slows everything else (including the L1 deployment for this test) down to a crawl.
 	if false {
 		// Make sure the batch poster is able to post multiple batches in one block
-		endL1Block, err := builder.L1.Client.BlockNumber(ctx)
+		endL1Block, err := builder.L1Client().BlockNumber(ctx)
 		Require(t, err)
-		seqInbox, err := arbnode.NewSequencerInbox(builder.L1.Client, builder.L2.ConsensusNode.DeployInfo.SequencerInbox, 0)
+		seqInbox, err := arbnode.NewSequencerInbox(builder.L1Client(), builder.L2.ConsensusNode.DeployInfo.SequencerInbox, 0)
 		Require(t, err)
 		batches, err := seqInbox.LookupBatchesInRange(ctx, new(big.Int).SetUint64(startL1Block), new(big.Int).SetUint64(endL1Block))
 		Require(t, err)
@@ -226,7 +229,7 @@ func TestBatchPosterLargeTx(t *testing.T) {
 	faucetAddr := builder.L2Info.GetAddress("Faucet")
 	gas := builder.L2Info.TransferGas + 20000*uint64(len(data))
 	tx := builder.L2Info.PrepareTxTo("Faucet", &faucetAddr, gas, common.Big0, data)
-	err = builder.L2.Client.SendTransaction(ctx, tx)
+	err = builder.L2Client().SendTransaction(ctx, tx)
 	Require(t, err)
 	receiptA, err := builder.L2.EnsureTxSucceeded(tx)
 	Require(t, err)
@@ -258,7 +261,7 @@ func TestBatchPosterKeepsUp(t *testing.T) {
 		for {
 			gas := builder.L2Info.TransferGas + 20000*uint64(len(data))
 			tx := builder.L2Info.PrepareTx("Faucet", "Faucet", gas, common.Big0, data)
-			err = builder.L2.Client.SendTransaction(ctx, tx)
+			err = builder.L2Client().SendTransaction(ctx, tx)
 			Require(t, err)
 			_, err := builder.L2.EnsureTxSucceeded(tx)
 			Require(t, err)
@@ -280,6 +283,124 @@ func TestBatchPosterKeepsUp(t *testing.T) {
 	}
 }
 
+func TestBatchPosterFollowsArbOSCompressionLevel(t *testing.T) {
+	t.Parallel()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	builder := NewNodeBuilder(ctx).DefaultConfig(t, true)
+	builder.nodeConfig.BatchPoster.UseArbOSCompressionLevel = true
+	cleanup := builder.Build(t)
+	defer cleanup()
+
+	batchPoster := builder.L2.ConsensusNode.BatchPoster
+	arbOwnerPublic, err := precompilesgen.NewArbOwnerPublic(types.ArbOwnerPublicAddress, builder.L2Client())
+	Require(t, err)
+	arbOwner, err := precompilesgen.NewArbOwner(types.ArbOwnerAddress, builder.L2Client())
+	Require(t, err)
+
+	initialLevel, err := arbOwnerPublic.GetBrotliCompressionLevel(&bind.CallOpts{Context: ctx})
+	Require(t, err)
+	// #nosec G115
+	if level := batchPoster.CompressionLevel(); level != int(initialLevel) {
+		Fatal(t, "batch poster compression level", level, "doesn't match ArbOS level", initialLevel)
+	}
+
+	newLevel := initialLevel + 1
+	auth := builder.L2Info.GetDefaultTransactOpts("Owner", ctx)
+	tx, err := arbOwner.SetBrotliCompressionLevel(&auth, newLevel)
+	Require(t, err)
+	_, err = builder.L2.EnsureTxSucceeded(tx)
+	Require(t, err)
+
+	// the cached level is invalidated by the change notification, not by polling
+	// #nosec G115
+	for i := 0; batchPoster.CompressionLevel() != int(newLevel); i++ {
+		if i == 100 {
+			Fatal(t, "batch poster didn't pick up ArbOS compression level", newLevel, "got", batchPoster.CompressionLevel())
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	// the next batch is posted with the new level
+	batchCount := GetBatchCount(t, builder)
+	builder.L2Info.GenerateAccount("User2")
+	tx = builder.L2Info.PrepareTx("Owner", "User2", builder.L2Info.TransferGas, big.NewInt(1e12), nil)
+	err = builder.L2Client().SendTransaction(ctx, tx)
+	Require(t, err)
+	_, err = builder.L2.EnsureTxSucceeded(tx)
+	Require(t, err)
+	for i := 0; GetBatchCount(t, builder) <= batchCount; i++ {
+		if i == 100 {
+			Fatal(t, "no batch posted after changing the compression level")
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+}
+
+func TestBatchPosterUncompressedBatches(t *testing.T) {
+	t.Parallel()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	builder := NewNodeBuilder(ctx).DefaultConfig(t, true)
+	cleanup := builder.Build(t)
+	defer cleanup()
+	// the second node only learns of the chain from the batches posted to L1
+	testClientB, cleanupB := builder.Build2ndNode(t, &SecondNodeParams{})
+	defer cleanupB()
+
+	arbOwner, err := precompilesgen.NewArbOwner(types.ArbOwnerAddress, builder.L2Client())
+	Require(t, err)
+	arbOwnerPublic, err := precompilesgen.NewArbOwnerPublic(types.ArbOwnerPublicAddress, builder.L2Client())
+	Require(t, err)
+	auth := builder.L2Info.GetDefaultTransactOpts("Owner", ctx)
+
+	builder.L2Info.GenerateAccount("User2")
+	expectSyncedFromBatch := func(headerByte byte, scenario string) {
+		t.Helper()
+		tx := builder.L2Info.PrepareTx("Owner", "User2", builder.L2Info.TransferGas, big.NewInt(1e12), nil)
+		Require(t, builder.L2Client().SendTransaction(ctx, tx))
+		receipt, err := builder.L2.EnsureTxSucceeded(tx)
+		Require(t, err)
+		receiptB, err := WaitForTx(ctx, testClientB.Client, tx.Hash(), time.Second*30)
+		Require(t, err, scenario)
+		if receiptB.BlockHash != receipt.BlockHash {
+			Fatal(t, scenario, "second node synced block", receiptB.BlockHash, "expected", receipt.BlockHash)
+		}
+		// as the chain starts at genesis, the tx's block is made from the message of the same index
+		batchNum, found, err := testClientB.ConsensusNode.InboxTracker.FindInboxBatchContainingMessage(arbutil.MessageIndex(receipt.BlockNumber.Uint64()))
+		Require(t, err)
+		if !found {
+			Fatal(t, scenario, "no batch found with the tx of block", receipt.BlockNumber)
+		}
+		batch, _, err := testClientB.ConsensusNode.InboxReader.GetSequencerMessageBytes(ctx, batchNum)
+		Require(t, err)
+		if len(batch) <= 40 || batch[40] != headerByte {
+			Fatal(t, scenario, "expected batch", batchNum, "to have header byte", headerByte, "got batch", batch)
+		}
+	}
+
+	expectSyncedFromBatch(daprovider.BrotliMessageHeaderByte, "compressed:")
+
+	tx, err := arbOwner.SetL1TxBatchCompressionDisabled(&auth, true)
+	Require(t, err)
+	_, err = builder.L2.EnsureTxSucceeded(tx)
+	Require(t, err)
+	disabled, err := arbOwnerPublic.GetL1TxBatchCompressionDisabled(&bind.CallOpts{Context: ctx})
+	Require(t, err)
+	if !disabled {
+		Fatal(t, "expected batch compression to be disabled")
+	}
+	expectSyncedFromBatch(daprovider.UncompressedMessageHeaderByte, "uncompressed:")
+
+	tx, err = arbOwner.SetL1TxBatchCompressionDisabled(&auth, false)
+	Require(t, err)
+	_, err = builder.L2.EnsureTxSucceeded(tx)
+	Require(t, err)
+	expectSyncedFromBatch(daprovider.BrotliMessageHeaderByte, "compressed again:")
+}
+
 func testAllowPostingFirstBatchWhenSequencerMessageCountMismatch(t *testing.T, enabled bool) {
 	t.Parallel()
 	ctx, cancel := context.WithCancel(context.Background())
@@ -294,7 +415,7 @@ func testAllowPostingFirstBatchWhenSequencerMessageCountMismatch(t *testing.T, e
 
 	// adds a batch to the sequencer inbox with a wrong next message count,
 	// should be 2 but it is set to 10
-	seqInbox, err := bridgegen.NewSequencerInbox(builder.L1Info.GetAddress("SequencerInbox"), builder.L1.Client)
+	seqInbox, err := bridgegen.NewSequencerInbox(builder.L1Info.GetAddress("SequencerInbox"), builder.L1Client())
 	Require(t, err)
 	seqOpts := builder.L1Info.GetDefaultTransactOpts("Sequencer", ctx)
 	tx, err := seqInbox.AddSequencerL2Batch(&seqOpts, big.NewInt(1), nil, big.NewInt(1), common.Address{}, big.NewInt(1), big.NewInt(10))
@@ -354,7 +475,7 @@ func TestAllowPostingFirstBatchWhenSequencerMessageCountMismatchDisabled(t *test
 
 func GetBatchCount(t *testing.T, builder *NodeBuilder) uint64 {
 	t.Helper()
-	sequenceInbox, err := bridgegen.NewSequencerInbox(builder.L1Info.GetAddress("SequencerInbox"), builder.L1.Client)
+	sequenceInbox, err := bridgegen.NewSequencerInbox(builder.L1Info.GetAddress("SequencerInbox"), builder.L1Client())
 	Require(t, err)
 	batchCount, err := sequenceInbox.BatchCount(&bind.CallOpts{Context: builder.ctx})
 	Require(t, err)
@@ -395,7 +516,7 @@ func testBatchPosterDelayBuffer(t *testing.T, delayBufferEnabled bool) {
 		for i := range txs {
 			txs[i] = builder.L2Info.PrepareTx("Owner", "User2", builder.L2Info.TransferGas, common.Big1, nil)
 		}
-		SendSignedTxesInBatchViaL1(t, ctx, builder.L1Info, builder.L1.Client, builder.L2.Client, txs)
+		SendSignedTxesInBatchViaL1(t, ctx, builder.L1Info, builder.L1Client(), builder.L2Client(), txs)
 
 		// Check batch wasn't sent
 		_, err := WaitForTx(ctx, testClientB.Client, txs[0].Hash(), 100*time.Millisecond)
@@ -405,7 +526,7 @@ func testBatchPosterDelayBuffer(t *testing.T, delayBufferEnabled bool) {
 		CheckBatchCount(t, builder, initialBatchCount+batch)
 
 		// Advance L1 to force a batch given the delay buffer threshold
-		AdvanceL1(t, ctx, builder.L1.Client, builder.L1Info, int(threshold)) // #nosec G115
+		AdvanceL1(t, ctx, builder.L1Client(), builder.L1Info, int(threshold)) // #nosec G115
 		if !delayBufferEnabled {
 			// If the delay buffer is disabled, set max delay to zero to force it
 			CheckBatchCount(t, builder, initialBatchCount+batch)
@@ -454,7 +575,7 @@ func TestBatchPosterDelayBufferDontForceNonDelayedMessages(t *testing.T) {
 		txs[i] = builder.L2Info.PrepareTx("Owner", "User2", builder.L2Info.TransferGas, common.Big1, nil)
 	}
 	builder.L2.SendWaitTestTransactions(t, txs)
-	AdvanceL1(t, ctx, builder.L1.Client, builder.L1Info, threshold)
+	AdvanceL1(t, ctx, builder.L1Client(), builder.L1Info, threshold)
 
 	// Even advancing the L1, the batch won't be posted because it doesn't contain a delayed message
 	CheckBatchCount(t, builder, initialBatchCount)
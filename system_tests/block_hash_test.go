@@ -23,7 +23,7 @@ func TestBlockHash(t *testing.T) {
 
 	auth := builder.L2Info.GetDefaultTransactOpts("Faucet", ctx)
 
-	_, _, simple, err := mocksgen.DeploySimple(&auth, builder.L2.Client)
+	_, _, simple, err := mocksgen.DeploySimple(&auth, builder.L2Client())
 	Require(t, err)
 
 	_, err = simple.CheckBlockHashes(&bind.CallOpts{Context: ctx})
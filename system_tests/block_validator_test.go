@@ -77,7 +77,7 @@ func testBlockValidatorSimple(t *testing.T, opts Options) {
 	cleanup := builder.Build(t)
 	defer cleanup()
 
-	authorizeDASKeyset(t, ctx, dasSignerKey, builder.L1Info, builder.L1.Client)
+	authorizeDASKeyset(t, ctx, dasSignerKey, builder.L1Info, builder.L1Client())
 
 	validatorConfig := arbnode.ConfigDefaultL1NonSequencerTest()
 	validatorConfig.BlockValidator.Enable = true
@@ -123,7 +123,7 @@ func testBlockValidatorSimple(t *testing.T, opts Options) {
 					contractCode = append(contractCode, byte(vm.RETURN))
 					basefee := builder.L2.GetBaseFee(t)
 					var err error
-					gas, err = builder.L2.Client.EstimateGas(ctx, ethereum.CallMsg{
+					gas, err = builder.L2Client().EstimateGas(ctx, ethereum.CallMsg{
 						From:     builder.L2Info.GetAddress("Owner"),
 						GasPrice: basefee,
 						Value:    big.NewInt(0),
@@ -141,7 +141,7 @@ func testBlockValidatorSimple(t *testing.T, opts Options) {
 				tx = builder.L2Info.PrepareTxTo("Owner", nil, gas, common.Big0, contractCode)
 			}
 
-			err := builder.L2.Client.SendTransaction(ctx, tx)
+			err := builder.L2Client().SendTransaction(ctx, tx)
 			Require(t, err)
 			_, err = builder.L2.EnsureTxSucceeded(tx)
 			if opts.workload != depleteGas {
@@ -155,12 +155,12 @@ func testBlockValidatorSimple(t *testing.T, opts Options) {
 		auth := builder.L2Info.GetDefaultTransactOpts("Owner", ctx)
 		// deploy a test contract
 		var err error
-		_, _, simple, err = mocksgen.DeploySimple(&auth, builder.L2.Client)
+		_, _, simple, err = mocksgen.DeploySimple(&auth, builder.L2Client())
 		Require(t, err, "could not deploy contract")
 
 		tx, err := simple.StoreDifficulty(&auth)
 		Require(t, err)
-		_, err = EnsureTxSucceeded(ctx, builder.L2.Client, tx)
+		_, err = EnsureTxSucceeded(ctx, builder.L2Client(), tx)
 		Require(t, err)
 		difficulty, err := simple.GetBlockDifficulty(&bind.CallOpts{})
 		Require(t, err)
@@ -168,13 +168,13 @@ func testBlockValidatorSimple(t *testing.T, opts Options) {
 			Fatal(t, "Expected difficulty to be 1 but got:", difficulty)
 		}
 		// make auth a chain owner
-		arbDebug, err := precompilesgen.NewArbDebug(common.HexToAddress("0xff"), builder.L2.Client)
+		arbDebug, err := precompilesgen.NewArbDebug(common.HexToAddress("0xff"), builder.L2Client())
 		Require(t, err)
 		tx, err = arbDebug.BecomeChainOwner(&auth)
 		Require(t, err)
 		_, err = builder.L2.EnsureTxSucceeded(tx)
 		Require(t, err)
-		arbOwner, err := precompilesgen.NewArbOwner(common.HexToAddress("0x70"), builder.L2.Client)
+		arbOwner, err := precompilesgen.NewArbOwner(common.HexToAddress("0x70"), builder.L2Client())
 		Require(t, err)
 		tx, err = arbOwner.ScheduleArbOSUpgrade(&auth, 11, 0)
 		Require(t, err)
@@ -183,7 +183,7 @@ func testBlockValidatorSimple(t *testing.T, opts Options) {
 
 		tx, err = simple.StoreDifficulty(&auth)
 		Require(t, err)
-		_, err = EnsureTxSucceeded(ctx, builder.L2.Client, tx)
+		_, err = EnsureTxSucceeded(ctx, builder.L2Client(), tx)
 		Require(t, err)
 		difficulty, err = simple.GetBlockDifficulty(&bind.CallOpts{})
 		Require(t, err)
@@ -192,7 +192,7 @@ func testBlockValidatorSimple(t *testing.T, opts Options) {
 		}
 
 		tx = builder.L2Info.PrepareTxTo("Owner", nil, builder.L2Info.TransferGas, perTransfer, []byte{byte(vm.PUSH0)})
-		err = builder.L2.Client.SendTransaction(ctx, tx)
+		err = builder.L2Client().SendTransaction(ctx, tx)
 		Require(t, err)
 		_, err = builder.L2.EnsureTxSucceeded(tx)
 		Require(t, err)
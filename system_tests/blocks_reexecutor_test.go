@@ -20,7 +20,7 @@ func TestBlocksReExecutorModes(t *testing.T) {
 	defer cleanup()
 
 	l2info := builder.L2Info
-	client := builder.L2.Client
+	client := builder.L2Client()
 	blockchain := builder.L2.ExecNode.Backend.ArbInterface().BlockChain()
 	feedErrChan := make(chan error, 10)
 
@@ -99,14 +99,14 @@ func TestBloom(t *testing.T) {
 		}
 		<-time.After(time.Second)
 	}
-	lastHeader, err := builder.L2.Client.HeaderByNumber(ctx, nil)
+	lastHeader, err := builder.L2Client().HeaderByNumber(ctx, nil)
 	Require(t, err)
 	nullEventQuery := ethereum.FilterQuery{
 		FromBlock: big.NewInt(0),
 		ToBlock:   lastHeader.Number,
 		Topics:    [][]common.Hash{{simpleABI.Events["NullEvent"].ID}},
 	}
-	logs, err := builder.L2.Client.FilterLogs(ctx, nullEventQuery)
+	logs, err := builder.L2Client().FilterLogs(ctx, nullEventQuery)
 	Require(t, err)
 	if len(logs) != len(nullEventCounts) {
 		Fatal(t, "expected ", len(nullEventCounts), " logs, got ", len(logs))
@@ -114,7 +114,7 @@ func TestBloom(t *testing.T) {
 	incrementEventQuery := ethereum.FilterQuery{
 		Topics: [][]common.Hash{{simpleABI.Events["CounterEvent"].ID}},
 	}
-	logs, err = builder.L2.Client.FilterLogs(ctx, incrementEventQuery)
+	logs, err = builder.L2Client().FilterLogs(ctx, incrementEventQuery)
 	Require(t, err)
 	if len(logs) != len(eventCounts) {
 		Fatal(t, "expected ", len(eventCounts), " logs, got ", len(logs))
@@ -58,6 +58,7 @@ import (
 	"github.com/offchainlabs/nitro/util/testhelpers"
 	"github.com/offchainlabs/nitro/validator/server_arb"
 	"github.com/offchainlabs/nitro/validator/server_common"
+	"github.com/offchainlabs/nitro/validator/testutil"
 	"github.com/offchainlabs/nitro/validator/valnode"
 )
 
@@ -71,7 +72,7 @@ func TestChallengeProtocolBOLDStartStepChallenge(t *testing.T) {
 			// This wrapper is applied after the BOLD wrapper, so step 0 is the finished machine.
 			// Modifying its hash results in invalid inclusion proofs for the evil validator,
 			// so we start modifying hashes at step 1 (the first machine step in the running state).
-			return NewIncorrectIntermediateMachine(inner, 1)
+			return testutil.NewIncorrectIntermediateMachine(inner, 1)
 		}),
 	}
 	testChallengeProtocolBOLD(t, opts...)
@@ -150,7 +150,7 @@ func testChallengeProtocolBOLDVirtualBlocks(t *testing.T, wrongAtFirstVirtual bo
 	})
 	defer cleanupEvilNode()
 
-	go keepChainMoving(t, ctx, builder.L1Info, builder.L1.Client)
+	go keepChainMoving(t, ctx, builder.L1Info, builder.L1Client())
 
 	builder.L1Info.GenerateAccount("HonestAsserter")
 	fundBoldStaker(t, ctx, builder, "HonestAsserter")
@@ -173,12 +173,12 @@ func testChallengeProtocolBOLDVirtualBlocks(t *testing.T, wrongAtFirstVirtual bo
 	})
 	defer cleanupEvilChallengeManager()
 
-	TransferBalance(t, "Faucet", "Faucet", common.Big0, builder.L2Info, builder.L2.Client, ctx)
+	TransferBalance(t, "Faucet", "Faucet", common.Big0, builder.L2Info, builder.L2Client(), ctx)
 
 	// Everything's setup, now just wait for the challenge to complete and ensure the honest party won
 
 	chalManager := assertionChain.SpecChallengeManager()
-	filterer, err := challengeV2gen.NewEdgeChallengeManagerFilterer(chalManager.Address(), builder.L1.Client)
+	filterer, err := challengeV2gen.NewEdgeChallengeManagerFilterer(chalManager.Address(), builder.L1Client())
 	Require(t, err)
 
 	fromBlock := uint64(0)
@@ -187,7 +187,7 @@ func testChallengeProtocolBOLDVirtualBlocks(t *testing.T, wrongAtFirstVirtual bo
 	for {
 		select {
 		case <-ticker.C:
-			latestBlock, err := builder.L1.Client.HeaderByNumber(ctx, nil)
+			latestBlock, err := builder.L1Client().HeaderByNumber(ctx, nil)
 			Require(t, err)
 			toBlock := latestBlock.Number.Uint64()
 			if fromBlock == toBlock {
@@ -205,7 +205,7 @@ func testChallengeProtocolBOLDVirtualBlocks(t *testing.T, wrongAtFirstVirtual bo
 					t.Fatalf("Error in filter iterator: %v", it.Error())
 				}
 				t.Log("Received event of OSP confirmation!")
-				tx, _, err := builder.L1.Client.TransactionByHash(ctx, it.Event.Raw.TxHash)
+				tx, _, err := builder.L1Client().TransactionByHash(ctx, it.Event.Raw.TxHash)
 				Require(t, err)
 				signer := types.NewCancunSigner(tx.ChainId())
 				address, err := signer.Sender(tx)
@@ -226,13 +226,13 @@ func testChallengeProtocolBOLDVirtualBlocks(t *testing.T, wrongAtFirstVirtual bo
 func fundBoldStaker(t *testing.T, ctx context.Context, builder *NodeBuilder, name string) {
 	balance := big.NewInt(params.Ether)
 	balance.Mul(balance, big.NewInt(100))
-	TransferBalance(t, "Faucet", name, balance, builder.L1Info, builder.L1.Client, ctx)
+	TransferBalance(t, "Faucet", name, balance, builder.L1Info, builder.L1Client(), ctx)
 
-	rollupUserLogic, err := rollupgen.NewRollupUserLogic(builder.addresses.Rollup, builder.L1.Client)
+	rollupUserLogic, err := rollupgen.NewRollupUserLogic(builder.addresses.Rollup, builder.L1Client())
 	Require(t, err)
 	stakeToken, err := rollupUserLogic.StakeToken(&bind.CallOpts{Context: ctx})
 	Require(t, err)
-	stakeTokenWeth, err := mocksgen.NewTestWETH9(stakeToken, builder.L1.Client)
+	stakeTokenWeth, err := mocksgen.NewTestWETH9(stakeToken, builder.L1Client())
 	Require(t, err)
 
 	txOpts := builder.L1Info.GetDefaultTransactOpts(name, ctx)
@@ -310,7 +310,7 @@ func startBoldChallengeManager(t *testing.T, ctx context.Context, builder *NodeB
 		nil, // Api db
 	)
 
-	rollupUserLogic, err := rollupgen.NewRollupUserLogic(builder.addresses.Rollup, builder.L1.Client)
+	rollupUserLogic, err := rollupgen.NewRollupUserLogic(builder.addresses.Rollup, builder.L1Client())
 	Require(t, err)
 	chalManagerAddr, err := rollupUserLogic.ChallengeManager(&bind.CallOpts{})
 	Require(t, err)
@@ -333,7 +333,7 @@ func startBoldChallengeManager(t *testing.T, ctx context.Context, builder *NodeB
 		builder.addresses.Rollup,
 		chalManagerAddr,
 		&txOpts,
-		butil.NewBackendWrapper(builder.L1.Client, rpc.LatestBlockNumber),
+		butil.NewBackendWrapper(builder.L1Client(), rpc.LatestBlockNumber),
 		bold.NewDataPosterTransactor(dp),
 	)
 	Require(t, err)
@@ -0,0 +1,117 @@
+// Copyright 2024, Offchain Labs, Inc.
+// For license information, see https://github.com/nitro/blob/master/LICENSE
+
+package arbtest
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+
+	"github.com/offchainlabs/nitro/execution/gethexec"
+)
+
+func TestCheckpoints(t *testing.T) {
+	t.Parallel()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	signingKey, err := crypto.GenerateKey()
+	Require(t, err)
+	signer := crypto.PubkeyToAddress(signingKey.PublicKey)
+
+	builder := NewNodeBuilder(ctx).DefaultConfig(t, false)
+	builder.execConfig.Checkpointer.Enable = true
+	builder.execConfig.Checkpointer.Interval = 2
+	builder.execConfig.Checkpointer.CheckInterval = 50 * time.Millisecond
+	builder.execConfig.Checkpointer.SigningKey = hexutil.Encode(crypto.FromECDSA(signingKey))
+	cleanup := builder.Build(t)
+	defer cleanup()
+
+	builder.L2Info.GenerateAccount("User2")
+	for i := 0; i < 6; i++ {
+		tx := builder.L2Info.PrepareTx("Owner", "User2", builder.L2Info.TransferGas, big.NewInt(1e12), nil)
+		Require(t, builder.L2Client().SendTransaction(ctx, tx))
+		_, err := builder.L2.EnsureTxSucceeded(tx)
+		Require(t, err)
+	}
+	head, err := builder.L2Client().BlockNumber(ctx)
+	Require(t, err)
+	lastCheckpointed := head / 2 * 2
+
+	rpcClient := builder.L2Client().Client()
+	var latest gethexec.Checkpoint
+	for i := 0; ; i++ {
+		err := rpcClient.CallContext(ctx, &latest, "arb_latestCheckpoint")
+		if err == nil && latest.BlockNumber == lastCheckpointed {
+			break
+		}
+		if i == 100 {
+			Fatal(t, "checkpointer didn't reach block", lastCheckpointed, "err", err, "latest", latest.BlockNumber)
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+
+	var signerFromNode common.Address
+	Require(t, rpcClient.CallContext(ctx, &signerFromNode, "arb_checkpointSigner"))
+	if signerFromNode != signer {
+		Fatal(t, "node reports signer", signerFromNode, "expected", signer)
+	}
+
+	var checkpoints []*gethexec.Checkpoint
+	Require(t, rpcClient.CallContext(ctx, &checkpoints, "arb_checkpoints", hexutil.Uint64(0), hexutil.Uint64(head)))
+	// #nosec G115
+	if len(checkpoints) != int(lastCheckpointed/2+1) {
+		Fatal(t, "expected a checkpoint every 2 blocks up to", lastCheckpointed, "got", len(checkpoints))
+	}
+	genesisBlockNum := builder.chainConfig.ArbitrumChainParams.GenesisBlockNum
+	for i, checkpoint := range checkpoints {
+		if checkpoint.BlockNumber != uint64(i)*2 {
+			Fatal(t, "checkpoint", i, "is of block", checkpoint.BlockNumber)
+		}
+		Require(t, gethexec.VerifyCheckpoint(ctx, checkpoint, signer, genesisBlockNum, builder.L2Client()))
+	}
+	var historical gethexec.Checkpoint
+	Require(t, rpcClient.CallContext(ctx, &historical, "arb_checkpoint", hexutil.Uint64(2)))
+	if historical.BlockHash != checkpoints[1].BlockHash {
+		Fatal(t, "checkpoint of block 2 differs between requests")
+	}
+
+	expectMismatch := func(description string, tamper func(*gethexec.Checkpoint), expected error) {
+		t.Helper()
+		tampered := latest
+		tampered.Signature = append(hexutil.Bytes{}, latest.Signature...)
+		tamper(&tampered)
+		err := gethexec.VerifyCheckpoint(ctx, &tampered, signer, genesisBlockNum, builder.L2Client())
+		if !errors.Is(err, expected) {
+			Fatal(t, "expected", description, "to fail with", expected, "got", err)
+		}
+	}
+	resign := func(c *gethexec.Checkpoint) {
+		Require(t, c.Sign(func(data []byte) ([]byte, error) { return crypto.Sign(data, signingKey) }))
+	}
+	expectMismatch("tampered state root", func(c *gethexec.Checkpoint) { c.StateRoot[0] ^= 1 }, gethexec.ErrCheckpointSigner)
+	expectMismatch("tampered and re-signed state root", func(c *gethexec.Checkpoint) {
+		c.StateRoot[0] ^= 1
+		resign(c)
+	}, gethexec.ErrCheckpointMismatch)
+	expectMismatch("tampered and re-signed send root", func(c *gethexec.Checkpoint) {
+		c.SendRoot[0] ^= 1
+		resign(c)
+	}, gethexec.ErrCheckpointMismatch)
+	expectMismatch("tampered and re-signed message count", func(c *gethexec.Checkpoint) {
+		c.MessageCount++
+		resign(c)
+	}, gethexec.ErrCheckpointMismatch)
+	expectMismatch("checkpoint signed by another key", func(c *gethexec.Checkpoint) {
+		otherKey, err := crypto.GenerateKey()
+		Require(t, err)
+		Require(t, c.Sign(func(data []byte) ([]byte, error) { return crypto.Sign(data, otherKey) }))
+	}, gethexec.ErrCheckpointSigner)
+}
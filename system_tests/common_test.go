@@ -10,6 +10,7 @@ import (
 	"encoding/hex"
 	"encoding/json"
 	"flag"
+	"fmt"
 	"io"
 	"log/slog"
 	"math/big"
@@ -69,6 +70,7 @@ import (
 	"github.com/offchainlabs/nitro/deploy"
 	"github.com/offchainlabs/nitro/execution/gethexec"
 	_ "github.com/offchainlabs/nitro/execution/nodeInterface"
+	"github.com/offchainlabs/nitro/gethhook"
 	"github.com/offchainlabs/nitro/solgen/go/bridgegen"
 	"github.com/offchainlabs/nitro/solgen/go/mocksgen"
 	"github.com/offchainlabs/nitro/solgen/go/precompilesgen"
@@ -161,6 +163,47 @@ func (tc *TestClient) EnsureTxSucceededWithTimeout(transaction *types.Transactio
 	return EnsureTxSucceededWithTimeout(tc.ctx, tc.Client, transaction, timeout)
 }
 
+// ReplicaTestClient is the client of a node following the L2 chain through the sequencer's feed.
+// Its helpers wait for the replica to catch up with the sequencer.
+type ReplicaTestClient struct {
+	*TestClient
+	sequencer *TestClient
+}
+
+// EnsureTxSucceeded waits for the transaction to succeed on the sequencer,
+// and then for the replica to have it in the same block.
+func (r *ReplicaTestClient) EnsureTxSucceeded(transaction *types.Transaction) (*types.Receipt, error) {
+	sequencerReceipt, err := r.sequencer.EnsureTxSucceeded(transaction)
+	if err != nil {
+		return nil, err
+	}
+	receipt, err := r.TestClient.EnsureTxSucceeded(transaction)
+	if err != nil {
+		return nil, err
+	}
+	if receipt.BlockHash != sequencerReceipt.BlockHash {
+		return nil, fmt.Errorf("replica included tx %v in block %v but the sequencer did in %v", transaction.Hash(), receipt.BlockHash, sequencerReceipt.BlockHash)
+	}
+	return receipt, nil
+}
+
+// WaitForSequencerHead waits for the replica to have the sequencer's latest block.
+func (r *ReplicaTestClient) WaitForSequencerHead(t *testing.T) {
+	t.Helper()
+	head, err := r.sequencer.Client.HeaderByNumber(r.ctx, nil)
+	Require(t, err)
+	for i := 0; ; i++ {
+		header, err := r.Client.HeaderByNumber(r.ctx, head.Number)
+		if err == nil && header.Hash() == head.Hash() {
+			return
+		}
+		if i == 500 {
+			Fatal(t, "replica didn't reach the sequencer's head", head.Number, "err", err)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
 var TestCachingConfig = gethexec.CachingConfig{
 	Archive:                             false,
 	BlockCount:                          128,
@@ -203,6 +246,7 @@ var TestSequencerConfig = gethexec.SequencerConfig{
 	ExpectedSurplusSoftThreshold: "default",
 	ExpectedSurplusHardThreshold: "default",
 	EnableProfiling:              false,
+	WarmStandby:                  gethexec.DefaultWarmStandbyConfig,
 }
 
 func ExecConfigDefaultNonSequencerTest(t *testing.T) *gethexec.Config {
@@ -259,11 +303,17 @@ type NodeBuilder struct {
 	withProdConfirmPeriodBlocks bool
 	wasmCacheTag                uint32
 	delayBufferThreshold        uint64
+	arbOSVersion                uint64 // if set, checked to be active after Build
+	externalSignerURL           string
+	withReplica                 bool
+	replicaNodeConfig           *arbnode.Config
+	extraPrecompiles            map[common.Address]gethhook.PrecompileHandler
 
 	// Created nodes
-	L1 *TestClient
-	L2 *TestClient
-	L3 *TestClient
+	L1        *TestClient
+	L2        *TestClient
+	L3        *TestClient
+	L2Replica *ReplicaTestClient
 }
 
 type NitroConfig struct {
@@ -344,6 +394,7 @@ func (b *NodeBuilder) WithArbOSVersion(arbosVersion uint64) *NodeBuilder {
 	newChainConfig := *b.chainConfig
 	newChainConfig.ArbitrumChainParams.InitialArbOSVersion = arbosVersion
 	b.chainConfig = &newChainConfig
+	b.arbOSVersion = arbosVersion
 	return b
 }
 
@@ -384,13 +435,96 @@ func (b *NodeBuilder) WithDelayBuffer(threshold uint64) *NodeBuilder {
 	return b
 }
 
+// WithExternalSigner has the external signer at url sign for the L2 Owner account, instead of a key in memory.
+func (b *NodeBuilder) WithExternalSigner(url string) *NodeBuilder {
+	b.externalSignerURL = url
+	return b
+}
+
+// WithGenesisTimestamp sets the timestamp of the L2 genesis block, which is otherwise 0,
+// so tests can schedule ArbOS upgrades relative to it. It must be called after DefaultConfig.
+func (b *NodeBuilder) WithGenesisTimestamp(timestamp uint64) *NodeBuilder {
+	b.L2Info.ArbInitData.GenesisTimestamp = timestamp
+	return b
+}
+
+// WithReplica makes Build also start a replica of the L2 node, a non-sequencer node following the chain
+// through the sequencer's feed, as L2Replica. A nil nodeConfig uses the default non-sequencer test config.
+// It must be called after DefaultConfig.
+func (b *NodeBuilder) WithReplica(nodeConfig *arbnode.Config) *NodeBuilder {
+	b.withReplica = true
+	b.replicaNodeConfig = nodeConfig
+	b.nodeConfig.Feed.Output = *newBroadcasterConfigTest()
+	return b
+}
+
+// WithExtraPrecompile makes Build add a precompile at the address, to simulate one that doesn't exist in ArbOS yet.
+// The precompile is added to every L2 EVM in the process until the test finishes,
+// so the address must not be used by tests running in parallel. Blocks calling it can't be validated.
+func (b *NodeBuilder) WithExtraPrecompile(addr common.Address, handler gethhook.PrecompileHandler) *NodeBuilder {
+	if b.extraPrecompiles == nil {
+		b.extraPrecompiles = make(map[common.Address]gethhook.PrecompileHandler)
+	}
+	b.extraPrecompiles[addr] = handler
+	return b
+}
+
+// L1Client returns the client of the L1 node, or nil if it hasn't been built.
+func (b *NodeBuilder) L1Client() *ethclient.Client {
+	if b.L1 == nil {
+		return nil
+	}
+	return b.L1.Client
+}
+
+// L2Client returns the client of the L2 node, or nil if it hasn't been built.
+func (b *NodeBuilder) L2Client() *ethclient.Client {
+	if b.L2 == nil {
+		return nil
+	}
+	return b.L2.Client
+}
+
 func (b *NodeBuilder) Build(t *testing.T) func() {
 	b.CheckConfig(t)
+	for addr, handler := range b.extraPrecompiles {
+		remove, err := gethhook.AddExtraPrecompile(addr, handler)
+		Require(t, err)
+		t.Cleanup(remove)
+	}
+	var cleanup func()
 	if b.withL1 {
 		b.BuildL1(t)
-		return b.BuildL2OnL1(t)
+		cleanup = b.BuildL2OnL1(t)
+	} else {
+		cleanup = b.BuildL2(t)
+	}
+	if b.arbOSVersion != 0 {
+		b.requireArbOSVersion(t, cleanup)
+	}
+	if b.withReplica {
+		cleanupL2 := cleanup
+		cleanupReplica := b.buildL2Replica(t)
+		cleanup = func() {
+			cleanupReplica()
+			cleanupL2()
+		}
+	}
+	return cleanup
+}
+
+// requireArbOSVersion fails the test if the L2 chain isn't running the ArbOS version set by WithArbOSVersion.
+func (b *NodeBuilder) requireArbOSVersion(t *testing.T, cleanup func()) {
+	t.Helper()
+	arbSys, err := precompilesgen.NewArbSys(types.ArbSysAddress, b.L2Client())
+	Require(t, err)
+	version, err := arbSys.ArbOSVersion(&bind.CallOpts{Context: b.ctx})
+	Require(t, err)
+	expected := 55 + b.arbOSVersion // Nitro versions start at 56
+	if version.Uint64() != expected {
+		cleanup()
+		Fatal(t, "expected ArbOS version", b.arbOSVersion, "to be active, got", version.Uint64()-55)
 	}
-	return b.BuildL2(t)
 }
 
 func (b *NodeBuilder) CheckConfig(t *testing.T) {
@@ -409,6 +543,9 @@ func (b *NodeBuilder) CheckConfig(t *testing.T) {
 	if b.L2Info == nil {
 		b.L2Info = NewArbTestInfo(t, b.chainConfig.ChainID)
 	}
+	if b.externalSignerURL != "" && b.L2Info.Accounts["Owner"].ExternalSigner == nil {
+		b.L2Info.UseExternalSigner(b.ctx, "Owner", b.externalSignerURL)
+	}
 	if b.execConfig.RPC.MaxRecreateStateDepth == arbitrum.UninitializedMaxRecreateStateDepth {
 		if b.execConfig.Caching.Archive {
 			b.execConfig.RPC.MaxRecreateStateDepth = arbitrum.DefaultArchiveNodeMaxRecreateStateDepth
@@ -777,6 +914,64 @@ func (b *NodeBuilder) Build2ndNode(t *testing.T, params *SecondNodeParams) (*Tes
 	)
 }
 
+// buildL2Replica starts the replica set up by WithReplica, returning its cleanup.
+func (b *NodeBuilder) buildL2Replica(t *testing.T) func() {
+	var nodeConfig arbnode.Config
+	switch {
+	case b.replicaNodeConfig != nil:
+		nodeConfig = *b.replicaNodeConfig
+	case b.withL1:
+		nodeConfig = *arbnode.ConfigDefaultL1NonSequencerTest()
+	default:
+		nodeConfig = *arbnode.ConfigDefaultL2Test()
+	}
+	port := testhelpers.AddrTCPPort(b.L2.ConsensusNode.BroadcastServer.ListenerAddr(), t)
+	nodeConfig.Feed.Input = *newBroadcastClientConfigTest(port)
+	execConfig := ExecConfigDefaultNonSequencerTest(t)
+
+	var replica *TestClient
+	var cleanup func()
+	if b.withL1 {
+		replica, cleanup = b.Build2ndNode(t, &SecondNodeParams{nodeConfig: &nodeConfig, execConfig: execConfig})
+	} else {
+		replica, cleanup = b.build2ndL2OnlyNode(t, &nodeConfig, execConfig)
+	}
+	b.L2Replica = &ReplicaTestClient{TestClient: replica, sequencer: b.L2}
+	return cleanup
+}
+
+// build2ndL2OnlyNode starts a second node of an L2 chain without an L1, which can only follow the chain through a feed.
+func (b *NodeBuilder) build2ndL2OnlyNode(t *testing.T, nodeConfig *arbnode.Config, execConfig *gethexec.Config) (*TestClient, func()) {
+	testClient := NewTestClient(b.ctx)
+
+	var chainDb ethdb.Database
+	var arbDb ethdb.Database
+	var blockchain *core.BlockChain
+	_, testClient.Stack, chainDb, arbDb, blockchain = createL2BlockChain(
+		t, b.L2Info, t.TempDir(), b.chainConfig, execConfig, b.wasmCacheTag)
+
+	execConfigFetcher := func() *gethexec.Config { return execConfig }
+	execNode, err := gethexec.CreateExecutionNode(b.ctx, testClient.Stack, chainDb, blockchain, nil, execConfigFetcher)
+	Require(t, err)
+
+	fatalErrChan := make(chan error, 10)
+	testClient.ConsensusNode, err = arbnode.CreateNode(
+		b.ctx, testClient.Stack, execNode, arbDb, NewFetcherFromConfig(nodeConfig), blockchain.Config(),
+		nil, nil, nil, nil, nil, fatalErrChan, big.NewInt(1337), nil)
+	Require(t, err)
+
+	// the init message isn't part of the feed
+	Require(t, testClient.ConsensusNode.TxStreamer.AddFakeInitMessage())
+	Require(t, testClient.ConsensusNode.Start(b.ctx))
+	testClient.Client = ClientForStack(t, testClient.Stack)
+
+	StartWatchChanErr(t, b.ctx, fatalErrChan, testClient.ConsensusNode)
+
+	testClient.ExecNode = getExecNode(t, testClient.ConsensusNode)
+	testClient.cleanup = func() { testClient.ConsensusNode.StopAndWait() }
+	return testClient, func() { testClient.cleanup() }
+}
+
 func (b *NodeBuilder) Build2ndNodeOnL3(t *testing.T, params *SecondNodeParams) (*TestClient, func()) {
 	if b.L3 == nil {
 		t.Fatal("builder did not previously built an L3 Node")
@@ -803,7 +998,7 @@ func (b *NodeBuilder) Build2ndNodeOnL3(t *testing.T, params *SecondNodeParams) (
 }
 
 func (b *NodeBuilder) BridgeBalance(t *testing.T, account string, amount *big.Int) (*types.Transaction, *types.Receipt) {
-	return BridgeBalance(t, account, amount, b.L1Info, b.L2Info, b.L1.Client, b.L2.Client, b.ctx)
+	return BridgeBalance(t, account, amount, b.L1Info, b.L2Info, b.L1Client(), b.L2Client(), b.ctx)
 }
 
 func SendWaitTestTransactions(t *testing.T, ctx context.Context, client *ethclient.Client, txs []*types.Transaction) []*types.Receipt {
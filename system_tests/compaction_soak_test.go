@@ -0,0 +1,79 @@
+// Copyright 2024, Offchain Labs, Inc.
+// For license information, see https://github.com/nitro/blob/master/LICENSE
+
+//go:build benchmarks
+// +build benchmarks
+
+package arbtest
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/metrics"
+)
+
+// runCompactionSoakLoad sends bursts of state-growing transfers separated by idle periods,
+// returning the latency of every transaction from submission to receipt.
+func runCompactionSoakLoad(t *testing.T, schedulerEnabled bool) []time.Duration {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	builder := NewNodeBuilder(ctx).DefaultConfig(t, false)
+	builder.execConfig.CompactionScheduler.Enable = schedulerEnabled
+	builder.execConfig.CompactionScheduler.LoadWindow = time.Second
+	builder.execConfig.CompactionScheduler.SequencerActiveTimeout = 500 * time.Millisecond
+	builder.execConfig.CompactionScheduler.MinInterval = time.Second
+	builder.execConfig.CompactionScheduler.StallThreshold = 100 * time.Millisecond
+	builder.execConfig.CompactionScheduler.WriteAmplificationThreshold = 4
+	cleanup := builder.Build(t)
+	defer cleanup()
+
+	const bursts = 20
+	const burstSize = 250
+	var latencies []time.Duration
+	for burst := 0; burst < bursts; burst++ {
+		for i := 0; i < burstSize; i++ {
+			// every transfer creates an account, growing the state the database has to compact
+			name := fmt.Sprintf("Soak%v-%v", burst, i)
+			builder.L2Info.GenerateAccount(name)
+			tx := builder.L2Info.PrepareTx("Owner", name, builder.L2Info.TransferGas, big.NewInt(1e12), nil)
+			start := time.Now()
+			err := builder.L2Client().SendTransaction(ctx, tx)
+			Require(t, err)
+			_, err = builder.L2.EnsureTxSucceeded(tx)
+			Require(t, err)
+			latencies = append(latencies, time.Since(start))
+		}
+		// a low traffic window for the scheduler to compact in
+		time.Sleep(3 * time.Second)
+	}
+	return latencies
+}
+
+func percentile(latencies []time.Duration, p float64) time.Duration {
+	sorted := slices.Clone(latencies)
+	slices.Sort(sorted)
+	return sorted[int(float64(len(sorted)-1)*p)]
+}
+
+func TestCompactionSchedulerSoak(t *testing.T) {
+	// the scheduler needs the database metrics to see stalls and write amplification
+	metricsEnabled := metrics.Enabled
+	metrics.Enabled = true
+	defer func() { metrics.Enabled = metricsEnabled }()
+
+	unscheduled := runCompactionSoakLoad(t, false)
+	scheduled := runCompactionSoakLoad(t, true)
+
+	unscheduledP99, scheduledP99 := percentile(unscheduled, 0.99), percentile(scheduled, 0.99)
+	fmt.Printf("Transaction latency p50 %v -> %v, p99 %v -> %v with the compaction scheduler\n",
+		percentile(unscheduled, 0.5), percentile(scheduled, 0.5), unscheduledP99, scheduledP99)
+	if scheduledP99 > unscheduledP99 {
+		t.Errorf("compaction scheduler didn't reduce p99 latency: %v without, %v with", unscheduledP99, scheduledP99)
+	}
+}
@@ -236,12 +236,12 @@ func TestSendRawTransactionConditionalBasic(t *testing.T) {
 		testConditionalTxThatShouldSucceed(t, ctx, i, builder.L2Info, rpcClient, options)
 	}
 
-	block, err := builder.L1.Client.BlockByNumber(ctx, nil)
+	block, err := builder.L1Client().BlockByNumber(ctx, nil)
 	Require(t, err)
 	blockNumber := block.NumberU64()
 
 	currentL2BlockTime := func() uint64 {
-		l2Block, err := builder.L2.Client.BlockByNumber(ctx, nil)
+		l2Block, err := builder.L2Client().BlockByNumber(ctx, nil)
 		Require(t, err)
 		return l2Block.Time()
 	}
@@ -278,7 +278,7 @@ func TestSendRawTransactionConditionalBasic(t *testing.T) {
 	}
 	currentSlotValueMap2 = getStorageSlotValue(t, builder.L2.ExecNode, contractAddress2)
 
-	block, err = builder.L1.Client.BlockByNumber(ctx, nil)
+	block, err = builder.L1Client().BlockByNumber(ctx, nil)
 	Require(t, err)
 	blockNumber = block.NumberU64()
 
@@ -293,7 +293,7 @@ func TestSendRawTransactionConditionalBasic(t *testing.T) {
 	for i, options := range options1 {
 		testConditionalTxThatShouldFail(t, ctx, i, builder.L2Info, rpcClient, options, -32003)
 	}
-	block, err = builder.L1.Client.BlockByNumber(ctx, nil)
+	block, err = builder.L1Client().BlockByNumber(ctx, nil)
 	Require(t, err)
 	blockNumber = block.NumberU64()
 	options3 := optionsDedupProduct(t, options2, getUnfulfillableBlockTimeLimits(t, blockNumber, currentL2BlockTime()))
@@ -330,7 +330,7 @@ func TestSendRawTransactionConditionalMultiRoutine(t *testing.T) {
 		account := fmt.Sprintf("User%v", i)
 		builder.L2Info.GenerateAccount(account)
 		tx := builder.L2Info.PrepareTx("Owner", account, builder.L2Info.TransferGas, big.NewInt(1e16), nil)
-		err := builder.L2.Client.SendTransaction(ctx, tx)
+		err := builder.L2Client().SendTransaction(ctx, tx)
 		Require(t, err)
 		_, err = builder.L2.EnsureTxSucceeded(tx)
 		Require(t, err)
@@ -456,12 +456,12 @@ func TestSendRawTransactionConditionalPreCheck(t *testing.T) {
 	}
 	testConditionalTxThatShouldFail(t, ctx, 2, builder.L2Info, rpcClient, options, -32003)
 	tx = builder.L2Info.PrepareTx("Owner", "User2", builder.L2Info.TransferGas, big.NewInt(1e12), nil)
-	Require(t, builder.L2.Client.SendTransaction(ctx, tx))
+	Require(t, builder.L2Client().SendTransaction(ctx, tx))
 	_, err = builder.L2.EnsureTxSucceeded(tx)
 	Require(t, err)
 	testConditionalTxThatShouldFail(t, ctx, 3, builder.L2Info, rpcClient, options, -32003)
 	tx = builder.L2Info.PrepareTx("Owner", "User2", builder.L2Info.TransferGas, big.NewInt(1e12), nil)
-	Require(t, builder.L2.Client.SendTransaction(ctx, tx))
+	Require(t, builder.L2Client().SendTransaction(ctx, tx))
 	_, err = builder.L2.EnsureTxSucceeded(tx)
 	Require(t, err)
 	testConditionalTxThatShouldSucceed(t, ctx, 4, builder.L2Info, rpcClient, options)
@@ -0,0 +1,104 @@
+// Copyright 2024, Offchain Labs, Inc.
+// For license information, see https://github.com/nitro/blob/master/LICENSE
+
+package arbtest
+
+import (
+	"context"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+
+	"github.com/offchainlabs/nitro/arbnode"
+)
+
+func TestL1ConfirmationStatus(t *testing.T) {
+	t.Parallel()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	builder := NewNodeBuilder(ctx).DefaultConfig(t, true)
+	builder.nodeConfig.BatchPoster.Enable = false
+	builder.nodeConfig.ParentChainReader.UseFinalityData = true
+	cleanup := builder.Build(t)
+	defer cleanup()
+
+	builder.L2Info.GenerateAccount("User2")
+	tx := builder.L2Info.PrepareTx("Owner", "User2", builder.L2Info.TransferGas, big.NewInt(1e12), nil)
+	err := builder.L2Client().SendTransaction(ctx, tx)
+	Require(t, err)
+	receipt, err := builder.L2.EnsureTxSucceeded(tx)
+	Require(t, err)
+
+	rpcClient := builder.L2.ConsensusNode.Stack.Attach()
+	getStatus := func() arbnode.ConfirmationStatus {
+		t.Helper()
+		var status arbnode.ConfirmationStatus
+		err := rpcClient.CallContext(ctx, &status, "arb_getL1ConfirmationStatus", hexutil.Uint64(receipt.BlockNumber.Uint64()))
+		Require(t, err)
+		if (status.L1BatchConfirmed && !status.BatchPosted) || (status.L1BatchFinalized && !status.L1BatchConfirmed) {
+			Fatal(t, "inconsistent confirmation status", status)
+		}
+		return status
+	}
+
+	status := getStatus()
+	if status.BatchPosted || status.L1BatchBlock != 0 {
+		Fatal(t, "block reported as posted before the batch poster ran", status)
+	}
+
+	batchPosterConfig := builder.nodeConfig.BatchPoster
+	batchPosterConfig.Enable = true
+	seqTxOpts := builder.L1Info.GetDefaultTransactOpts("Sequencer", ctx)
+	parentChainID, err := builder.L1Client().ChainID(ctx)
+	Require(t, err)
+	batchPoster, err := arbnode.NewBatchPoster(ctx,
+		&arbnode.BatchPosterOpts{
+			DataPosterDB:  nil,
+			L1Reader:      builder.L2.ConsensusNode.L1Reader,
+			Inbox:         builder.L2.ConsensusNode.InboxTracker,
+			Streamer:      builder.L2.ConsensusNode.TxStreamer,
+			VersionGetter: builder.L2.ExecNode,
+			SyncMonitor:   builder.L2.ConsensusNode.SyncMonitor,
+			Config:        func() *arbnode.BatchPosterConfig { return &batchPosterConfig },
+			DeployInfo:    builder.L2.ConsensusNode.DeployInfo,
+			TransactOpts:  &seqTxOpts,
+			DAPWriter:     nil,
+			ParentChainID: parentChainID,
+		},
+	)
+	Require(t, err)
+	batchPoster.Start(ctx)
+	defer batchPoster.StopAndWait()
+
+	// advance L1 until the batch is finalized, checking the stages never go backwards
+	var posted, confirmed bool
+	for i := 0; ; i++ {
+		status = getStatus()
+		if (posted && !status.BatchPosted) || (confirmed && !status.L1BatchConfirmed) {
+			Fatal(t, "confirmation status went backwards", status)
+		}
+		if status.BatchPosted && !posted {
+			posted = true
+			if status.L1BatchBlock == 0 {
+				Fatal(t, "posted batch has no parent chain block", status)
+			}
+			_, err := builder.L1Client().HeaderByNumber(ctx, new(big.Int).SetUint64(status.L1BatchBlock))
+			Require(t, err, "parent chain block of batch not found")
+		}
+		confirmed = status.L1BatchConfirmed
+		if status.L1BatchFinalized {
+			break
+		}
+		if i == 200 {
+			Fatal(t, "batch wasn't finalized", status)
+		}
+		builder.L1.SendWaitTestTransactions(t, []*types.Transaction{
+			builder.L1Info.PrepareTx("Faucet", "Faucet", 30000, big.NewInt(1e12), nil),
+		})
+		time.Sleep(50 * time.Millisecond)
+	}
+}
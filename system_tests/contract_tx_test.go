@@ -92,7 +92,7 @@ func TestContractTxDeploy(t *testing.T) {
 
 		txHash := types.NewTx(contractTx).Hash()
 		t.Log("made contract tx", contractTx, "with hash", txHash)
-		receipt, err := WaitForTx(ctx, builder.L2.Client, txHash, time.Second*10)
+		receipt, err := WaitForTx(ctx, builder.L2Client(), txHash, time.Second*10)
 		Require(t, err)
 		if receipt.Status != types.ReceiptStatusSuccessful {
 			Fatal(t, "Receipt has non-successful status", receipt.Status)
@@ -105,7 +105,7 @@ func TestContractTxDeploy(t *testing.T) {
 		t.Log("deployed contract", receipt.ContractAddress, "from address", from, "with nonce", stateNonce)
 		stateNonce++
 
-		code, err := builder.L2.Client.CodeAt(ctx, receipt.ContractAddress, nil)
+		code, err := builder.L2Client().CodeAt(ctx, receipt.ContractAddress, nil)
 		Require(t, err)
 		if !bytes.Equal(code, []byte{0xFE}) {
 			Fatal(t, "expected contract", receipt.ContractAddress, "code of 0xFE but got", hex.EncodeToString(code))
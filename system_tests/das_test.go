@@ -104,10 +104,10 @@ func TestDASRekey(t *testing.T) {
 
 	// Setup DAS servers
 	dasDataDir := t.TempDir()
-	dasRpcServerA, pubkeyA, backendConfigA, _, restServerUrlA := startLocalDASServer(t, ctx, dasDataDir, builder.L1.Client, builder.addresses.SequencerInbox)
+	dasRpcServerA, pubkeyA, backendConfigA, _, restServerUrlA := startLocalDASServer(t, ctx, dasDataDir, builder.L1Client(), builder.addresses.SequencerInbox)
 	l1NodeConfigB := arbnode.ConfigDefaultL1NonSequencerTest()
 	{
-		authorizeDASKeyset(t, ctx, pubkeyA, builder.L1Info, builder.L1.Client)
+		authorizeDASKeyset(t, ctx, pubkeyA, builder.L1Info, builder.L1Client())
 
 		// Setup DAS config
 		builder.nodeConfig.DataAvailability.Enable = true
@@ -133,7 +133,7 @@ func TestDASRekey(t *testing.T) {
 			initData:   &builder.L2Info.ArbInitData,
 		}
 		l2B, cleanupB := builder.Build2ndNode(t, &nodeBParams)
-		checkBatchPosting(t, ctx, builder.L1.Client, builder.L2.Client, builder.L1Info, builder.L2Info, big.NewInt(1e12), l2B.Client)
+		checkBatchPosting(t, ctx, builder.L1Client(), builder.L2Client(), builder.L1Info, builder.L2Info, big.NewInt(1e12), l2B.Client)
 
 		builder.L2.cleanup()
 		cleanupB()
@@ -141,12 +141,12 @@ func TestDASRekey(t *testing.T) {
 
 	err := dasRpcServerA.Shutdown(ctx)
 	Require(t, err)
-	dasRpcServerB, pubkeyB, backendConfigB, _, _ := startLocalDASServer(t, ctx, dasDataDir, builder.L1.Client, builder.addresses.SequencerInbox)
+	dasRpcServerB, pubkeyB, backendConfigB, _, _ := startLocalDASServer(t, ctx, dasDataDir, builder.L1Client(), builder.addresses.SequencerInbox)
 	defer func() {
 		err = dasRpcServerB.Shutdown(ctx)
 		Require(t, err)
 	}()
-	authorizeDASKeyset(t, ctx, pubkeyB, builder.L1Info, builder.L1.Client)
+	authorizeDASKeyset(t, ctx, pubkeyB, builder.L1Info, builder.L1Client())
 
 	// Restart the node on the new keyset against the new DAS server running on the same disk as the first with new keys
 	builder.nodeConfig.DataAvailability.RPCAggregator = aggConfigForBackend(backendConfigB)
@@ -160,7 +160,7 @@ func TestDASRekey(t *testing.T) {
 	}
 	l2B, cleanup := builder.Build2ndNode(t, &nodeBParams)
 	defer cleanup()
-	checkBatchPosting(t, ctx, builder.L1.Client, builder.L2.Client, builder.L1Info, builder.L2Info, big.NewInt(2e12), l2B.Client)
+	checkBatchPosting(t, ctx, builder.L1Client(), builder.L2Client(), builder.L1Info, builder.L2Info, big.NewInt(2e12), l2B.Client)
 }
 
 func checkBatchPosting(t *testing.T, ctx context.Context, l1client, l2clientA *ethclient.Client, l1info, l2info info, expectedBalance *big.Int, l2ClientsToCheck ...*ethclient.Client) {
@@ -205,8 +205,8 @@ func TestDASComplexConfigAndRestMirror(t *testing.T) {
 	builder.chainConfig = chaininfo.ArbitrumDevTestDASChainConfig()
 	builder.BuildL1(t)
 
-	arbSys, _ := precompilesgen.NewArbSys(types.ArbSysAddress, builder.L1.Client)
-	l1Reader, err := headerreader.New(ctx, builder.L1.Client, func() *headerreader.Config { return &headerreader.TestConfig }, arbSys)
+	arbSys, _ := precompilesgen.NewArbSys(types.ArbSysAddress, builder.L1Client())
+	l1Reader, err := headerreader.New(ctx, builder.L1Client(), func() *headerreader.Config { return &headerreader.TestConfig }, arbSys)
 	Require(t, err)
 	l1Reader.Start(ctx)
 	defer l1Reader.StopAndWait()
@@ -251,7 +251,7 @@ func TestDASComplexConfigAndRestMirror(t *testing.T) {
 	Require(t, err)
 
 	pubkeyA := pubkey
-	authorizeDASKeyset(t, ctx, pubkeyA, builder.L1Info, builder.L1.Client)
+	authorizeDASKeyset(t, ctx, pubkeyA, builder.L1Info, builder.L1Client())
 
 	//
 	builder.nodeConfig.DataAvailability = das.DataAvailabilityConfig{
@@ -300,7 +300,7 @@ func TestDASComplexConfigAndRestMirror(t *testing.T) {
 	l2B, cleanupB := builder.Build2ndNode(t, &nodeBParams)
 	defer cleanupB()
 
-	checkBatchPosting(t, ctx, builder.L1.Client, builder.L2.Client, builder.L1Info, builder.L2Info, big.NewInt(1e12), l2B.Client)
+	checkBatchPosting(t, ctx, builder.L1Client(), builder.L2Client(), builder.L1Info, builder.L2Info, big.NewInt(1e12), l2B.Client)
 
 	err = restServer.Shutdown()
 	Require(t, err)
@@ -332,7 +332,7 @@ func TestDASBatchPosterFallback(t *testing.T) {
 	builder := NewNodeBuilder(ctx).DefaultConfig(t, true)
 	builder.chainConfig = chaininfo.ArbitrumDevTestDASChainConfig()
 	builder.BuildL1(t)
-	l1client := builder.L1.Client
+	l1client := builder.L1Client()
 	l1info := builder.L1Info
 
 	// Setup DAS server
@@ -354,7 +354,7 @@ func TestDASBatchPosterFallback(t *testing.T) {
 	builder.L2Info.GenerateAccount("User2")
 	cleanup := builder.BuildL2OnL1(t)
 	defer cleanup()
-	l2client := builder.L2.Client
+	l2client := builder.L2Client()
 	l2info := builder.L2Info
 
 	// Setup secondary L2 node
@@ -40,14 +40,14 @@ func TestDatabaseConversion(t *testing.T) {
 	for i := uint64(0); i < 200; i++ {
 		tx := builder.L2Info.PrepareTx("Owner", "User2", builder.L2Info.TransferGas, common.Big1, nil)
 		txs = append(txs, tx)
-		err := builder.L2.Client.SendTransaction(ctx, tx)
+		err := builder.L2Client().SendTransaction(ctx, tx)
 		Require(t, err)
 	}
 	for _, tx := range txs {
 		_, err := builder.L2.EnsureTxSucceeded(tx)
 		Require(t, err)
 	}
-	block, err := builder.L2.Client.BlockByNumber(ctx, nil)
+	block, err := builder.L2Client().BlockByNumber(ctx, nil)
 	Require(t, err)
 	user2Balance := builder.L2.GetBalance(t, builder.L2Info.GetAddress("User2"))
 	ownerBalance := builder.L2.GetBalance(t, builder.L2Info.GetAddress("Owner"))
@@ -76,7 +76,7 @@ func TestDatabaseConversion(t *testing.T) {
 	builder.RestartL2Node(t)
 	t.Log("restarted the node")
 
-	blockAfterRestart, err := builder.L2.Client.BlockByNumber(ctx, nil)
+	blockAfterRestart, err := builder.L2Client().BlockByNumber(ctx, nil)
 	Require(t, err)
 	user2BalanceAfterRestart := builder.L2.GetBalance(t, builder.L2Info.GetAddress("User2"))
 	ownerBalanceAfterRestart := builder.L2.GetBalance(t, builder.L2Info.GetAddress("Owner"))
@@ -118,7 +118,7 @@ func TestDatabaseConversion(t *testing.T) {
 	t.Log("visited nodes:", visited)
 
 	tx := builder.L2Info.PrepareTx("Owner", "User2", builder.L2Info.TransferGas, common.Big1, nil)
-	err = builder.L2.Client.SendTransaction(ctx, tx)
+	err = builder.L2Client().SendTransaction(ctx, tx)
 	Require(t, err)
 	_, err = builder.L2.EnsureTxSucceeded(tx)
 	Require(t, err)
@@ -36,7 +36,7 @@ func TestDebugTraceCallForRecentBlock(t *testing.T) {
 		defer close(senderDone)
 		for ctx.Err() == nil {
 			tx := builder.L2Info.PrepareTx("Owner", "User2", builder.L2Info.TransferGas, new(big.Int).Lsh(big.NewInt(1), 128), nil)
-			err := builder.L2.Client.SendTransaction(ctx, tx)
+			err := builder.L2Client().SendTransaction(ctx, tx)
 			if ctx.Err() != nil {
 				return
 			}
@@ -19,6 +19,7 @@ import (
 
 	"github.com/offchainlabs/nitro/arbos/l2pricing"
 	"github.com/offchainlabs/nitro/arbos/retryables"
+	"github.com/offchainlabs/nitro/execution/gethexec"
 	"github.com/offchainlabs/nitro/solgen/go/node_interfacegen"
 	"github.com/offchainlabs/nitro/solgen/go/precompilesgen"
 	"github.com/offchainlabs/nitro/util/arbmath"
@@ -49,7 +50,7 @@ func TestDebugAPI(t *testing.T) {
 	err = l2rpc.CallContext(ctx, &dumpIt, "debug_accountRange", rpc.PendingBlockNumber, hexutil.Bytes{}, 10, true, true, false)
 	Require(t, err)
 
-	arbSys, err := precompilesgen.NewArbSys(types.ArbSysAddress, builder.L2.Client)
+	arbSys, err := precompilesgen.NewArbSys(types.ArbSysAddress, builder.L2Client())
 	Require(t, err)
 	auth := builder.L2Info.GetDefaultTransactOpts("Owner", ctx)
 	tx, err := arbSys.SendTxToL1(&auth, common.Address{}, []byte{})
@@ -66,6 +67,49 @@ func TestDebugAPI(t *testing.T) {
 	Require(t, err)
 }
 
+func TestArbTraceTransactionPrecompileCalls(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	builder := NewNodeBuilder(ctx).DefaultConfig(t, false)
+	cleanup := builder.Build(t)
+	defer cleanup()
+
+	arbGasInfoABI, err := precompilesgen.ArbGasInfoMetaData.GetAbi()
+	Require(t, err)
+	data, err := arbGasInfoABI.Pack("getPricesInWei")
+	Require(t, err)
+	arbGasInfoAddress := types.ArbGasInfoAddress
+	tx := builder.L2Info.PrepareTxTo("Owner", &arbGasInfoAddress, 500000, common.Big0, data)
+	Require(t, builder.L2Client().SendTransaction(ctx, tx))
+	_, err = builder.L2.EnsureTxSucceeded(tx)
+	Require(t, err)
+
+	var result gethexec.TransactionTrace
+	l2rpc := builder.L2.Stack.Attach()
+	Require(t, l2rpc.CallContext(ctx, &result, "arb_traceTransaction", tx.Hash(), nil))
+	if len(result.Trace) == 0 {
+		Fatal(t, "expected the standard trace to be included")
+	}
+	if len(result.ArbPrecompileCalls) != 1 {
+		Fatal(t, "expected a single precompile call, got", len(result.ArbPrecompileCalls))
+	}
+	call := result.ArbPrecompileCalls[0]
+	if call.Type != "arbPrecompileCall" || call.Address != arbGasInfoAddress || call.Precompile != "ArbGasInfo" || call.Method != "getPricesInWei" {
+		Fatal(t, "unexpected precompile call", call.Type, call.Address, call.Precompile, call.Method)
+	}
+	if call.GasCharged == 0 || call.Reverted {
+		Fatal(t, "expected the call to succeed and be charged gas, got", call.GasCharged, "gas and reverted", call.Reverted)
+	}
+	prices, err := arbGasInfoABI.Unpack("getPricesInWei", call.ReturnValue)
+	Require(t, err)
+	if len(prices) != 6 {
+		Fatal(t, "expected the traced return value to hold 6 prices, got", len(prices))
+	}
+	if perArbGasTotal, ok := prices[5].(*big.Int); !ok || perArbGasTotal.Sign() <= 0 {
+		Fatal(t, "expected a positive traced price per ArbGas, got", prices[5])
+	}
+}
+
 type account struct {
 	Balance *hexutil.Big                `json:"balance,omitempty"`
 	Code    []byte                      `json:"code,omitempty"`
@@ -87,14 +131,14 @@ func TestPrestateTracingSimple(t *testing.T) {
 	builder.L2Info.GenerateAccount("User2")
 	sender := builder.L2Info.GetAddress("Owner")
 	receiver := builder.L2Info.GetAddress("User2")
-	ownerOldBalance, err := builder.L2.Client.BalanceAt(ctx, sender, nil)
+	ownerOldBalance, err := builder.L2Client().BalanceAt(ctx, sender, nil)
 	Require(t, err)
-	user2OldBalance, err := builder.L2.Client.BalanceAt(ctx, receiver, nil)
+	user2OldBalance, err := builder.L2Client().BalanceAt(ctx, receiver, nil)
 	Require(t, err)
 
 	value := big.NewInt(1e6)
 	tx := builder.L2Info.PrepareTx("Owner", "User2", builder.L2Info.TransferGas, value, nil)
-	Require(t, builder.L2.Client.SendTransaction(ctx, tx))
+	Require(t, builder.L2Client().SendTransaction(ctx, tx))
 	_, err = builder.L2.EnsureTxSucceeded(tx)
 	Require(t, err)
 
@@ -136,7 +180,7 @@ func TestPrestateTracingComplex(t *testing.T) {
 
 	// Test prestate tracing of a ArbitrumDepositTx type tx
 	faucetAddr := builder.L1Info.GetAddress("Faucet")
-	oldBalance, err := builder.L2.Client.BalanceAt(ctx, faucetAddr, nil)
+	oldBalance, err := builder.L2Client().BalanceAt(ctx, faucetAddr, nil)
 	Require(t, err)
 
 	txOpts := builder.L1Info.GetDefaultTransactOpts("Faucet", ctx)
@@ -155,7 +199,7 @@ func TestPrestateTracingComplex(t *testing.T) {
 	l2Tx := lookupL2Tx(l1Receipt)
 	l2Receipt, err := builder.L2.EnsureTxSucceeded(l2Tx)
 	Require(t, err)
-	newBalance, err := builder.L2.Client.BalanceAt(ctx, faucetAddr, l2Receipt.BlockNumber)
+	newBalance, err := builder.L2Client().BalanceAt(ctx, faucetAddr, l2Receipt.BlockNumber)
 	Require(t, err)
 	if got := new(big.Int); got.Sub(newBalance, oldBalance).Cmp(txOpts.Value) != 0 {
 		t.Errorf("Got transferred: %v, want: %v", got, txOpts.Value)
@@ -193,7 +237,7 @@ func TestPrestateTracingComplex(t *testing.T) {
 	deposit := arbmath.BigMul(big.NewInt(1e12), big.NewInt(1e12))
 	callValue := big.NewInt(1e6)
 
-	nodeInterface, err := node_interfacegen.NewNodeInterface(types.NodeInterfaceAddress, builder.L2.Client)
+	nodeInterface, err := node_interfacegen.NewNodeInterface(types.NodeInterfaceAddress, builder.L2Client())
 	Require(t, err, "failed to deploy NodeInterface")
 
 	// estimate the gas needed to auto redeem the retryable
@@ -252,7 +296,7 @@ func TestPrestateTracingComplex(t *testing.T) {
 		Fatal(t)
 	}
 
-	l2balance, err := builder.L2.Client.BalanceAt(ctx, builder.L2Info.GetAddress("User2"), nil)
+	l2balance, err := builder.L2Client().BalanceAt(ctx, builder.L2Info.GetAddress("User2"), nil)
 	Require(t, err)
 	if !arbmath.BigEquals(l2balance, callValue) {
 		Fatal(t, "Unexpected balance:", l2balance)
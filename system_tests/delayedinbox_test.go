@@ -47,9 +47,9 @@ func TestDelayInboxSimple(t *testing.T) {
 	builder.L2Info.GenerateAccount("User2")
 
 	delayedTx := builder.L2Info.PrepareTx("Owner", "User2", 50001, big.NewInt(1e6), nil)
-	builder.L1.SendSignedTx(t, builder.L2.Client, delayedTx, builder.L1Info)
+	builder.L1.SendSignedTx(t, builder.L2Client(), delayedTx, builder.L1Info)
 
-	l2balance, err := builder.L2.Client.BalanceAt(ctx, builder.L2Info.GetAddress("User2"), nil)
+	l2balance, err := builder.L2Client().BalanceAt(ctx, builder.L2Info.GetAddress("User2"), nil)
 	Require(t, err)
 	if l2balance.Cmp(big.NewInt(1e6)) != 0 {
 		Fatal(t, "Unexpected balance:", l2balance)
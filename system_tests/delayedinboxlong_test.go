@@ -58,7 +58,7 @@ func TestDelayInboxLong(t *testing.T) {
 			Require(t, err)
 		}
 		// Checking every tx is expensive, so we just check the last, assuming that the others succeeded too
-		confirmLatestBlock(ctx, t, builder.L1Info, builder.L1.Client)
+		confirmLatestBlock(ctx, t, builder.L1Info, builder.L1Client())
 		_, err := builder.L1.EnsureTxSucceeded(l1Txs[len(l1Txs)-1])
 		Require(t, err)
 	}
@@ -75,9 +75,9 @@ func TestDelayInboxLong(t *testing.T) {
 		})
 	}
 
-	_, err := WaitForTx(ctx, builder.L2.Client, lastDelayedMessage.Hash(), time.Second*5)
+	_, err := WaitForTx(ctx, builder.L2Client(), lastDelayedMessage.Hash(), time.Second*5)
 	Require(t, err)
-	l2balance, err := builder.L2.Client.BalanceAt(ctx, builder.L2Info.GetAddress("User2"), nil)
+	l2balance, err := builder.L2Client().BalanceAt(ctx, builder.L2Info.GetAddress("User2"), nil)
 	Require(t, err)
 	if l2balance.Cmp(big.NewInt(fundsPerDelayed*delayedMessages)) != 0 {
 		Fatal(t, "Unexpected balance:", "balance", l2balance, "expected", fundsPerDelayed*delayedMessages)
@@ -6,10 +6,13 @@ package arbtest
 import (
 	"bytes"
 	"context"
+	"encoding/json"
+	"math/big"
 	"strings"
 	"testing"
 
 	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core"
 	"github.com/ethereum/go-ethereum/core/types"
@@ -17,6 +20,8 @@ import (
 	"github.com/ethereum/go-ethereum/ethclient"
 	"github.com/ethereum/go-ethereum/params"
 
+	"github.com/offchainlabs/nitro/cmd/chaininfo"
+	"github.com/offchainlabs/nitro/solgen/go/precompilesgen"
 	"github.com/offchainlabs/nitro/util/arbmath"
 )
 
@@ -116,11 +121,11 @@ func TestContractDeployment(t *testing.T) {
 
 	account := builder.L2Info.GetInfoWithPrivKey("Faucet")
 	for _, size := range []int{0, 1, 1000, 20000, params.DefaultMaxCodeSize} {
-		testContractDeployment(t, ctx, builder.L2.Client, makeContractOfLength(size), account, nil)
+		testContractDeployment(t, ctx, builder.L2Client(), makeContractOfLength(size), account, nil)
 	}
 
-	testContractDeployment(t, ctx, builder.L2.Client, makeContractOfLength(40000), account, vm.ErrMaxCodeSizeExceeded)
-	testContractDeployment(t, ctx, builder.L2.Client, makeContractOfLength(60000), account, core.ErrMaxInitCodeSizeExceeded)
+	testContractDeployment(t, ctx, builder.L2Client(), makeContractOfLength(40000), account, vm.ErrMaxCodeSizeExceeded)
+	testContractDeployment(t, ctx, builder.L2Client(), makeContractOfLength(60000), account, core.ErrMaxInitCodeSizeExceeded)
 }
 
 func TestExtendedContractDeployment(t *testing.T) {
@@ -135,9 +140,206 @@ func TestExtendedContractDeployment(t *testing.T) {
 
 	account := builder.L2Info.GetInfoWithPrivKey("Faucet")
 	for _, size := range []int{0, 1, 1000, 20000, 30000, 40000, 60000, params.DefaultMaxCodeSize * 3} {
-		testContractDeployment(t, ctx, builder.L2.Client, makeContractOfLength(size), account, nil)
+		testContractDeployment(t, ctx, builder.L2Client(), makeContractOfLength(size), account, nil)
 	}
 
-	testContractDeployment(t, ctx, builder.L2.Client, makeContractOfLength(100000), account, vm.ErrMaxCodeSizeExceeded)
-	testContractDeployment(t, ctx, builder.L2.Client, makeContractOfLength(200000), account, core.ErrMaxInitCodeSizeExceeded)
+	testContractDeployment(t, ctx, builder.L2Client(), makeContractOfLength(100000), account, vm.ErrMaxCodeSizeExceeded)
+	testContractDeployment(t, ctx, builder.L2Client(), makeContractOfLength(200000), account, core.ErrMaxInitCodeSizeExceeded)
+}
+
+func TestMaxCodeSizeCantBeReducedBelowDeployedContracts(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	builder := NewNodeBuilder(ctx).DefaultConfig(t, false)
+	builder.chainConfig.ArbitrumChainParams.MaxCodeSize = params.DefaultMaxCodeSize * 3
+	builder.chainConfig.ArbitrumChainParams.MaxInitCodeSize = params.DefaultMaxInitCodeSize * 3
+	cleanup := builder.Build(t)
+	defer cleanup()
+
+	account := builder.L2Info.GetInfoWithPrivKey("Faucet")
+	testContractDeployment(t, ctx, builder.L2Client(), makeContractOfLength(params.DefaultMaxCodeSize*2), account, nil)
+
+	auth := builder.L2Info.GetDefaultTransactOpts("Owner", ctx)
+	arbOwner, err := precompilesgen.NewArbOwner(types.ArbOwnerAddress, builder.L2Client())
+	Require(t, err)
+	setMaxCodeSize := func(maxCodeSize uint64) error {
+		chainConfig := chaininfo.CopyChainConfig(builder.chainConfig)
+		chainConfig.ArbitrumChainParams.MaxCodeSize = maxCodeSize
+		serializedChainConfig, err := json.Marshal(chainConfig)
+		Require(t, err)
+		tx, err := arbOwner.SetChainConfig(&auth, string(serializedChainConfig))
+		if err != nil {
+			return err
+		}
+		_, err = builder.L2.EnsureTxSucceeded(tx)
+		return err
+	}
+
+	// the deployed contract would no longer fit
+	if err := setMaxCodeSize(params.DefaultMaxCodeSize); err == nil {
+		Fatal(t, "expected reducing the max code size below a deployed contract's size to fail")
+	} else if !strings.Contains(err.Error(), "max code size") {
+		Fatal(t, "unexpected error reducing the max code size:", err)
+	}
+	Require(t, setMaxCodeSize(params.DefaultMaxCodeSize*4))
+}
+
+// Makes a factory contract which deploys its calldata as init code with CREATE or CREATE2,
+// returning the new contract's address or reverting if the deployment failed
+func makeFactoryContract(create2 bool) []byte {
+	code := []byte{
+		byte(vm.CALLDATASIZE),
+		byte(vm.PUSH1), 0,
+		byte(vm.PUSH1), 0,
+		byte(vm.CALLDATACOPY),
+	}
+	if create2 {
+		code = append(code, byte(vm.PUSH1), 0) // salt
+	}
+	code = append(code,
+		byte(vm.CALLDATASIZE),
+		byte(vm.PUSH1), 0,
+		byte(vm.PUSH1), 0, // value
+	)
+	if create2 {
+		code = append(code, byte(vm.CREATE2))
+	} else {
+		code = append(code, byte(vm.CREATE))
+	}
+	deployed := byte(len(code) + 9)
+	code = append(code,
+		byte(vm.DUP1),
+		byte(vm.PUSH1), deployed,
+		byte(vm.JUMPI),
+		byte(vm.PUSH1), 0,
+		byte(vm.PUSH1), 0,
+		byte(vm.REVERT),
+		byte(vm.JUMPDEST),
+		byte(vm.PUSH1), 0,
+		byte(vm.MSTORE),
+		byte(vm.PUSH1), 32,
+		byte(vm.PUSH1), 0,
+		byte(vm.RETURN),
+	)
+	return code
+}
+
+func TestInitCodeSizeLimitForCreateOpcodes(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	builder := NewNodeBuilder(ctx).DefaultConfig(t, false)
+	cleanup := builder.Build(t)
+	defer cleanup()
+
+	auth := builder.L2Info.GetDefaultTransactOpts("Faucet", ctx)
+	maxInitCodeSize := builder.chainConfig.MaxInitCodeSize()
+	for _, create2 := range []bool{false, true} {
+		factory := deployContract(t, ctx, auth, builder.L2Client(), makeFactoryContract(create2))
+
+		// init code of exactly the max size deploys, but one more byte is too much
+		atLimit := makeContractOfLength(int(maxInitCodeSize))
+		tx := builder.L2Info.PrepareTxTo("Faucet", &factory, 10_000_000, nil, atLimit)
+		Require(t, builder.L2Client().SendTransaction(ctx, tx))
+		_, err := builder.L2.EnsureTxSucceeded(tx)
+		Require(t, err, "create2", create2)
+
+		overLimit := makeContractOfLength(int(maxInitCodeSize) + 1)
+		_, err = builder.L2Client().CallContract(ctx, ethereum.CallMsg{To: &factory, Gas: 10_000_000, Data: overLimit}, nil)
+		if err == nil {
+			Fatal(t, "deployed init code over the max size", "create2", create2)
+		}
+	}
+}
+
+// Deploys a contract whose constructor calls precompiles, checking the calls see the deployment's context
+func TestPrecompileCallsInInitCode(t *testing.T) {
+	t.Parallel()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	builder := NewNodeBuilder(ctx).DefaultConfig(t, false)
+	cleanup := builder.Build(t)
+	defer cleanup()
+
+	arbSysABI, err := precompilesgen.ArbSysMetaData.GetAbi()
+	Require(t, err)
+	arbGasInfoABI, err := precompilesgen.ArbGasInfoMetaData.GetAbi()
+	Require(t, err)
+	arbOwnerPublicABI, err := precompilesgen.ArbOwnerPublicMetaData.GetAbi()
+	Require(t, err)
+	deployer := builder.L2Info.GetAddress("Owner")
+
+	// the constructor stores whether each call succeeded, followed by the word of its result we care about
+	var initCode []byte
+	staticCall := func(precompile common.Address, calldata []byte, resultWord uint64, slot byte) {
+		for offset := 0; offset < len(calldata); offset += 32 {
+			chunk := common.RightPadBytes(calldata[offset:min(offset+32, len(calldata))], 32)
+			initCode = append(initCode, byte(vm.PUSH32))
+			initCode = append(initCode, chunk...)
+			initCode = append(initCode, byte(vm.PUSH1), byte(offset), byte(vm.MSTORE))
+		}
+		resultOffset := 0x100 + 32*resultWord
+		initCode = append(initCode,
+			byte(vm.PUSH1), byte(32*(resultWord+1)), // return size
+			byte(vm.PUSH2), 0x01, 0x00, // return offset
+			byte(vm.PUSH1), byte(len(calldata)),
+			byte(vm.PUSH1), 0,
+			byte(vm.PUSH20),
+		)
+		initCode = append(initCode, precompile.Bytes()...)
+		initCode = append(initCode,
+			byte(vm.GAS),
+			byte(vm.STATICCALL),
+			byte(vm.PUSH1), slot,
+			byte(vm.SSTORE),
+			byte(vm.PUSH2), byte(resultOffset>>8), byte(resultOffset),
+			byte(vm.MLOAD),
+			byte(vm.PUSH1), slot+1,
+			byte(vm.SSTORE),
+		)
+	}
+	calldata, err := arbSysABI.Pack("arbChainID")
+	Require(t, err)
+	staticCall(types.ArbSysAddress, calldata, 0, 0)
+	calldata, err = arbGasInfoABI.Pack("getPricesInWei")
+	Require(t, err)
+	staticCall(types.ArbGasInfoAddress, calldata, 5, 2) // the total price per ArbGas
+	calldata, err = arbOwnerPublicABI.Pack("isChainOwner", deployer)
+	Require(t, err)
+	staticCall(types.ArbOwnerPublicAddress, calldata, 0, 4)
+	initCode = append(initCode, byte(vm.STOP)) // deploy no code
+
+	gas, err := builder.L2Client().EstimateGas(ctx, ethereum.CallMsg{From: deployer, Data: initCode})
+	Require(t, err)
+	tx := builder.L2Info.PrepareTxTo("Owner", nil, gas, common.Big0, initCode)
+	Require(t, builder.L2Client().SendTransaction(ctx, tx))
+	receipt, err := builder.L2.EnsureTxSucceeded(tx)
+	Require(t, err)
+
+	slot := func(index int64) *big.Int {
+		t.Helper()
+		value, err := builder.L2Client().StorageAt(ctx, receipt.ContractAddress, common.BigToHash(big.NewInt(index)), nil)
+		Require(t, err)
+		return new(big.Int).SetBytes(value)
+	}
+	for _, successSlot := range []int64{0, 2, 4} {
+		if slot(successSlot).Uint64() != 1 {
+			Fatal(t, "precompile call", successSlot/2, "failed in init code")
+		}
+	}
+	if chainID := slot(1); chainID.Cmp(builder.chainConfig.ChainID) != 0 {
+		Fatal(t, "expected chain id", builder.chainConfig.ChainID, "in init code, got", chainID)
+	}
+	arbGasInfo, err := precompilesgen.NewArbGasInfo(types.ArbGasInfoAddress, builder.L2Client())
+	Require(t, err)
+	_, _, _, _, _, perArbGasTotal, err := arbGasInfo.GetPricesInWei(&bind.CallOpts{Context: ctx, BlockNumber: receipt.BlockNumber})
+	Require(t, err)
+	if price := slot(3); price.Cmp(perArbGasTotal) != 0 || price.Sign() == 0 {
+		Fatal(t, "expected price per ArbGas", perArbGasTotal, "in init code, got", price)
+	}
+	if slot(5).Uint64() != 1 {
+		Fatal(t, "expected the deployer to be a chain owner in init code")
+	}
 }
@@ -0,0 +1,360 @@
+// Copyright 2024, Offchain Labs, Inc.
+// For license information, see https://github.com/nitro/blob/master/LICENSE
+
+package arbtest
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+
+	"github.com/offchainlabs/nitro/arbos/arbostypes"
+	"github.com/offchainlabs/nitro/arbos/l1pricing"
+	"github.com/offchainlabs/nitro/solgen/go/precompilesgen"
+)
+
+var (
+	recordDeterminismFixture = flag.Bool("determinism.record", false, "Whether to record the determinism test's fixture on this platform instead of checking against it")
+	determinismBlocks        = flag.Uint64("determinism.blocks", 8, "Number of blocks of calls in the determinism test's workload, each size having its own fixture")
+)
+
+// the opcode trace of each tx is hashed into a checkpoint every this many steps,
+// so a divergence can be narrowed down to a window of opcodes
+const determinismTraceCheckpointInterval = 256
+
+// determinismFixture is what executing the determinism workload produced on the platform that recorded it
+type determinismFixture struct {
+	Platform     string             `json:"platform"`
+	ArbOSVersion uint64             `json:"arbosVersion"`
+	Blocks       []determinismBlock `json:"blocks"`
+}
+
+type determinismBlock struct {
+	Number       uint64          `json:"number"`
+	Hash         common.Hash     `json:"hash"`
+	StateRoot    common.Hash     `json:"stateRoot"`
+	ReceiptsRoot common.Hash     `json:"receiptsRoot"`
+	SendRoot     common.Hash     `json:"sendRoot"`
+	ModuleHashes []common.Hash   `json:"moduleHashes,omitempty"` // of the Stylus programs activated in the block
+	Txs          []determinismTx `json:"txs"`
+}
+
+type determinismTx struct {
+	Hash             common.Hash   `json:"hash"`
+	Status           uint64        `json:"status"`
+	GasUsed          uint64        `json:"gasUsed"`
+	TraceSteps       uint64        `json:"traceSteps"`
+	TraceCheckpoints []common.Hash `json:"traceCheckpoints"`
+}
+
+type determinismTraceStep struct {
+	Pc    uint64 `json:"pc"`
+	Op    string `json:"op"`
+	Gas   uint64 `json:"gas"`
+	Depth uint64 `json:"depth"`
+}
+
+func (s determinismTraceStep) String() string {
+	return fmt.Sprintf("pc %v %v gas %v depth %v", s.Pc, s.Op, s.Gas, s.Depth)
+}
+
+// TestExecutionDeterminism checks executing a fixed workload, including Stylus programs, produces exactly what the
+// recorded fixture says. Record a fixture for a workload size on a reference platform with -determinism.record.
+func TestExecutionDeterminism(t *testing.T) {
+	t.Parallel()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	builder := NewNodeBuilder(ctx).DefaultConfig(t, false)
+	// every block comes from the workload, so nothing depends on when the test runs
+	builder.takeOwnership = false
+	cleanup := builder.Build(t)
+	defer cleanup()
+
+	fixturePath := filepath.Join("testdata", "determinism", fmt.Sprintf("%v-blocks.json", *determinismBlocks))
+	var recorded determinismFixture
+	if !*recordDeterminismFixture {
+		data, err := os.ReadFile(fixturePath)
+		if errors.Is(err, os.ErrNotExist) {
+			t.Skip("no determinism fixture at", fixturePath, "- record one on the reference platform with -determinism.record")
+		}
+		Require(t, err)
+		Require(t, json.Unmarshal(data, &recorded))
+	}
+
+	runDeterminismWorkload(t, builder, *determinismBlocks)
+	local, traces := collectDeterminismFixture(t, builder)
+
+	if *recordDeterminismFixture {
+		data, err := json.MarshalIndent(local, "", "  ")
+		Require(t, err)
+		Require(t, os.MkdirAll(filepath.Dir(fixturePath), 0o755))
+		Require(t, os.WriteFile(fixturePath, append(data, '\n'), 0o600))
+		t.Log("recorded determinism fixture of", len(local.Blocks), "blocks to", fixturePath)
+		return
+	}
+	if recorded.ArbOSVersion != local.ArbOSVersion {
+		Fatal(t, "fixture was recorded with ArbOS version", recorded.ArbOSVersion, "but the chain runs", local.ArbOSVersion, "- re-record it with -determinism.record")
+	}
+	if divergence := compareDeterminismFixtures(&recorded, local, traces); divergence != "" {
+		Fatal(t, "execution on", local.Platform, "diverges from the fixture recorded on", recorded.Platform+"\n"+divergence)
+	}
+}
+
+// runDeterminismWorkload feeds the node messages with fixed timestamps and signed txs, so the same blocks
+// are built on every platform: funding transfers, an EVM contract, a Stylus program, then calls to both.
+func runDeterminismWorkload(t *testing.T, builder *NodeBuilder, callBlocks uint64) {
+	t.Helper()
+	ctx := builder.ctx
+	nonces := make(map[string]uint64)
+	signTx := func(from string, to *common.Address, value *big.Int, gas uint64, data []byte) *types.Transaction {
+		t.Helper()
+		tx := builder.L2Info.SignTxAs(from, &types.DynamicFeeTx{
+			ChainID:   builder.chainConfig.ChainID,
+			Nonce:     nonces[from],
+			To:        to,
+			Value:     value,
+			Gas:       gas,
+			GasTipCap: common.Big0,
+			GasFeeCap: big.NewInt(1e9),
+			Data:      data,
+		})
+		nonces[from]++
+		return tx
+	}
+	deploy := func(from string, code []byte) (*types.Transaction, common.Address) {
+		t.Helper()
+		address := crypto.CreateAddress(builder.L2Info.GetAddress(from), nonces[from])
+		return signTx(from, nil, common.Big0, 32_000_000, deployContractInitCode(code, false)), address
+	}
+
+	var blocks []types.Transactions
+	recipients := make([]common.Address, callBlocks)
+	var funding types.Transactions
+	for i := range recipients {
+		recipients[i] = GetTestAddressForAccountName(t, fmt.Sprintf("Determinism%v", i))
+		funding = append(funding, signTx("Faucet", &recipients[i], big.NewInt(1e15), 100_000, nil))
+	}
+	blocks = append(blocks, funding)
+
+	// stores the hash of its calldata at the block number
+	evmCode := []byte{
+		0x36, 0x60, 0x00, 0x60, 0x00, 0x37, // CALLDATACOPY(0, 0, CALLDATASIZE)
+		0x36, 0x60, 0x00, 0x20, // KECCAK256(0, CALLDATASIZE)
+		0x43, 0x55, // SSTORE(NUMBER, hash)
+		0x00, // STOP
+	}
+	evmDeploy, evmContract := deploy("Owner", evmCode)
+	wasm, _ := readWasmFile(t, watFile("timings/keccak"))
+	wasmDeploy, program := deploy("Owner", wasm)
+	blocks = append(blocks, types.Transactions{evmDeploy, wasmDeploy})
+
+	arbWasmABI, err := precompilesgen.ArbWasmMetaData.GetAbi()
+	Require(t, err)
+	activate, err := arbWasmABI.Pack("activateProgram", program)
+	Require(t, err)
+	blocks = append(blocks, types.Transactions{signTx("Owner", &types.ArbWasmAddress, oneEth, 32_000_000, activate)})
+
+	for i := uint64(0); i < callBlocks; i++ {
+		// #nosec G115
+		keccakArgs := binary.LittleEndian.AppendUint32(nil, uint32(10*(i+1)))
+		keccakArgs = append(keccakArgs, []byte(fmt.Sprintf("stylus call %v", i))...)
+		blocks = append(blocks, types.Transactions{
+			signTx("Faucet", &evmContract, common.Big0, 1_000_000, []byte(fmt.Sprintf("evm call %v", i))),
+			signTx("Owner", &program, common.Big0, 1_000_000, keccakArgs),
+			signTx("Faucet", &recipients[i], big.NewInt(1e12), 100_000, nil),
+		})
+	}
+
+	streamer := builder.L2.ConsensusNode.TxStreamer
+	pos, err := streamer.GetMessageCount()
+	Require(t, err)
+	lastMessage, err := streamer.GetMessage(pos - 1)
+	Require(t, err)
+	var messages []arbostypes.MessageWithMetadata
+	for i, txes := range blocks {
+		// #nosec G115
+		index := uint64(i)
+		l2Message, err := l2MessageBatchDataFromTxes(txes)
+		Require(t, err)
+		messages = append(messages, arbostypes.MessageWithMetadata{
+			Message: &arbostypes.L1IncomingMessage{
+				Header: &arbostypes.L1IncomingMessageHeader{
+					Kind:        arbostypes.L1MessageType_L2Message,
+					Poster:      l1pricing.BatchPosterAddress,
+					BlockNumber: index + 1,
+					Timestamp:   1_700_000_000 + index*12,
+					L1BaseFee:   big.NewInt(1e9),
+				},
+				L2msg:        l2Message,
+				BatchGasCost: new(uint64),
+			},
+			DelayedMessagesRead: lastMessage.DelayedMessagesRead,
+		})
+	}
+	Require(t, streamer.AddMessages(pos, true, messages))
+
+	lastBlock := blocks[len(blocks)-1]
+	_, err = WaitForTx(ctx, builder.L2Client(), lastBlock[len(lastBlock)-1].Hash(), time.Second*30)
+	Require(t, err)
+}
+
+// collectDeterminismFixture records every block of the chain, returning the opcode traces of its txs for reporting
+func collectDeterminismFixture(t *testing.T, builder *NodeBuilder) (*determinismFixture, map[common.Hash][]determinismTraceStep) {
+	t.Helper()
+	ctx := builder.ctx
+	client := builder.L2Client()
+	arbWasm, err := precompilesgen.NewArbWasm(types.ArbWasmAddress, client)
+	Require(t, err)
+	arbSys, err := precompilesgen.NewArbSys(types.ArbSysAddress, client)
+	Require(t, err)
+	version, err := arbSys.ArbOSVersion(nil)
+	Require(t, err)
+
+	fixture := &determinismFixture{
+		Platform:     testPlatform(),
+		ArbOSVersion: version.Uint64() - 55, // Nitro versions start at 56
+	}
+	traces := make(map[common.Hash][]determinismTraceStep)
+	head, err := client.BlockNumber(ctx)
+	Require(t, err)
+	for number := uint64(0); number <= head; number++ {
+		block, err := client.BlockByNumber(ctx, new(big.Int).SetUint64(number))
+		Require(t, err)
+		recorded := determinismBlock{
+			Number:       number,
+			Hash:         block.Hash(),
+			StateRoot:    block.Root(),
+			ReceiptsRoot: block.ReceiptHash(),
+			SendRoot:     types.DeserializeHeaderExtraInformation(block.Header()).SendRoot,
+		}
+		for _, tx := range block.Transactions() {
+			receipt, err := client.TransactionReceipt(ctx, tx.Hash())
+			Require(t, err)
+			if tx.Type() != types.ArbitrumInternalTxType && receipt.Status != types.ReceiptStatusSuccessful {
+				Fatal(t, "workload tx", tx.Hash(), "in block", number, "failed")
+			}
+			for _, log := range receipt.Logs {
+				if activated, err := arbWasm.ParseProgramActivated(*log); err == nil {
+					recorded.ModuleHashes = append(recorded.ModuleHashes, activated.ModuleHash)
+				}
+			}
+			var trace struct {
+				StructLogs []determinismTraceStep `json:"structLogs"`
+			}
+			err = client.Client().CallContext(ctx, &trace, "debug_traceTransaction", tx.Hash())
+			Require(t, err)
+			traces[tx.Hash()] = trace.StructLogs
+			recorded.Txs = append(recorded.Txs, determinismTx{
+				Hash:             tx.Hash(),
+				Status:           receipt.Status,
+				GasUsed:          receipt.GasUsed,
+				TraceSteps:       uint64(len(trace.StructLogs)),
+				TraceCheckpoints: determinismTraceCheckpoints(trace.StructLogs),
+			})
+		}
+		fixture.Blocks = append(fixture.Blocks, recorded)
+	}
+	return fixture, traces
+}
+
+// determinismTraceCheckpoints hashes the trace, taking a checkpoint of the running hash every interval and at the end
+func determinismTraceCheckpoints(steps []determinismTraceStep) []common.Hash {
+	var checkpoints []common.Hash
+	var running common.Hash
+	for i, step := range steps {
+		running = crypto.Keccak256Hash(
+			running.Bytes(),
+			binary.BigEndian.AppendUint64(nil, step.Pc),
+			[]byte(step.Op),
+			binary.BigEndian.AppendUint64(nil, step.Gas),
+			binary.BigEndian.AppendUint64(nil, step.Depth),
+		)
+		if (i+1)%determinismTraceCheckpointInterval == 0 || i == len(steps)-1 {
+			checkpoints = append(checkpoints, running)
+		}
+	}
+	return checkpoints
+}
+
+// compareDeterminismFixtures describes the first divergence of the local execution from the recorded one,
+// or returns an empty string if they match
+func compareDeterminismFixtures(recorded, local *determinismFixture, traces map[common.Hash][]determinismTraceStep) string {
+	if len(recorded.Blocks) != len(local.Blocks) {
+		return fmt.Sprintf("fixture has %v blocks but the workload produced %v", len(recorded.Blocks), len(local.Blocks))
+	}
+	for i := range recorded.Blocks {
+		want, got := &recorded.Blocks[i], &local.Blocks[i]
+		var diffs []string
+		diffHash := func(field string, want, got common.Hash) {
+			if want != got {
+				diffs = append(diffs, fmt.Sprintf("  %v: recorded %v, got %v", field, want, got))
+			}
+		}
+		diffHash("block hash", want.Hash, got.Hash)
+		diffHash("state root", want.StateRoot, got.StateRoot)
+		diffHash("receipts root", want.ReceiptsRoot, got.ReceiptsRoot)
+		diffHash("send root", want.SendRoot, got.SendRoot)
+		if fmt.Sprint(want.ModuleHashes) != fmt.Sprint(got.ModuleHashes) {
+			diffs = append(diffs, fmt.Sprintf("  Stylus module hashes: recorded %v, got %v", want.ModuleHashes, got.ModuleHashes))
+		}
+		if len(diffs) == 0 {
+			continue
+		}
+		report := fmt.Sprintf("first divergent block is %v:\n%v", want.Number, strings.Join(diffs, "\n"))
+		return report + "\n" + describeTxDivergence(want.Txs, got.Txs, traces)
+	}
+	return ""
+}
+
+func describeTxDivergence(recorded, local []determinismTx, traces map[common.Hash][]determinismTraceStep) string {
+	if len(recorded) != len(local) {
+		return fmt.Sprintf("block has %v txs but the fixture's has %v", len(local), len(recorded))
+	}
+	for i := range recorded {
+		want, got := &recorded[i], &local[i]
+		if want.Hash != got.Hash {
+			return fmt.Sprintf("tx %v is %v but the fixture's is %v, so the workload itself differs", i, got.Hash, want.Hash)
+		}
+		if want.Status == got.Status && want.GasUsed == got.GasUsed && fmt.Sprint(want.TraceCheckpoints) == fmt.Sprint(got.TraceCheckpoints) {
+			continue
+		}
+		report := fmt.Sprintf(
+			"first divergent tx is %v (%v): status %v gas used %v, recorded status %v gas used %v",
+			i, got.Hash, got.Status, got.GasUsed, want.Status, want.GasUsed,
+		)
+		for checkpoint := range got.TraceCheckpoints {
+			if checkpoint < len(want.TraceCheckpoints) && want.TraceCheckpoints[checkpoint] == got.TraceCheckpoints[checkpoint] {
+				continue
+			}
+			steps := traces[got.Hash]
+			start := checkpoint * determinismTraceCheckpointInterval
+			end := min(start+determinismTraceCheckpointInterval, len(steps))
+			report += fmt.Sprintf("\nopcode traces (%v steps, recorded %v) diverge within steps %v to %v, which ran here as:", len(steps), want.TraceSteps, start, end-1)
+			for step := start; step < end; step++ {
+				report += fmt.Sprintf("\n  %v: %v", step, steps[step])
+			}
+			break
+		}
+		return report
+	}
+	return "the block's txs executed identically, so the divergence is in the block's own state changes"
+}
+
+func testPlatform() string {
+	return fmt.Sprintf("%v/%v", runtime.GOOS, runtime.GOARCH)
+}
@@ -0,0 +1,123 @@
+// Copyright 2024, Offchain Labs, Inc.
+// For license information, see https://github.com/nitro/blob/master/LICENSE
+
+// race detection makes things slow and miss timeouts
+//go:build !race
+// +build !race
+
+package arbtest
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/core/rawdb"
+
+	"github.com/offchainlabs/nitro/arbnode"
+	"github.com/offchainlabs/nitro/arbutil"
+	"github.com/offchainlabs/nitro/validator/client/redis"
+	"github.com/offchainlabs/nitro/validator/valnode"
+)
+
+func TestDualValidationAgreesOnHonestTraffic(t *testing.T) {
+	t.Parallel()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	builder := NewNodeBuilder(ctx).DefaultConfig(t, true)
+	// For now PathDB is not supported when using block validation
+	builder.execConfig.Caching.StateScheme = rawdb.HashScheme
+	cleanup := builder.Build(t)
+	defer cleanup()
+
+	reportDir := t.TempDir()
+	valConfig := valnode.TestValidationConfig
+	valConfig.UseJit = true
+	valConfig.DualValidation.Enable = true
+	valConfig.DualValidation.SampleRate = 1
+	valConfig.DualValidation.ReportDir = reportDir
+	valNode, valStack := createTestValidationNode(t, ctx, &valConfig)
+
+	validatorConfig := arbnode.ConfigDefaultL1NonSequencerTest()
+	validatorConfig.BlockValidator.Enable = true
+	validatorConfig.BlockValidator.RedisValidationClientConfig = redis.ValidationClientConfig{}
+	configByValidationNode(validatorConfig, valStack)
+	testClientB, cleanupB := builder.Build2ndNode(t, &SecondNodeParams{nodeConfig: validatorConfig})
+	defer cleanupB()
+
+	builder.L2Info.GenerateAccount("User2")
+	for i := 0; i < 3; i++ {
+		tx := builder.L2Info.PrepareTx("Owner", "User2", builder.L2Info.TransferGas, big.NewInt(1e12), nil)
+		Require(t, builder.L2Client().SendTransaction(ctx, tx))
+		_, err := builder.L2.EnsureTxSucceeded(tx)
+		Require(t, err)
+		_, err = WaitForTx(ctx, testClientB.Client, tx.Hash(), time.Second*30)
+		Require(t, err)
+	}
+
+	lastBlock, err := testClientB.Client.BlockNumber(ctx)
+	Require(t, err)
+	timeout := getDeadlineTimeout(t, time.Minute*10)
+	if !testClientB.ConsensusNode.BlockValidator.WaitForPos(t, ctx, arbutil.MessageIndex(lastBlock), timeout) {
+		Fatal(t, "did not validate all blocks")
+	}
+
+	// the arbitrator finishes after jit, so its comparisons may still be running
+	dual := valNode.DualValidation()
+	err = tryWithTimeout(func() error {
+		summary := dual.Summary()
+		if summary.Compared+summary.Failed < lastBlock {
+			time.Sleep(100 * time.Millisecond)
+			return fmt.Errorf("only compared %d of %d validations", summary.Compared+summary.Failed, lastBlock)
+		}
+		return nil
+	}, timeout)
+	Require(t, err)
+
+	summary := dual.Summary()
+	if summary.Failed != 0 || summary.Disagreed != 0 || summary.AgreementRate != 1 {
+		Fatal(t, "expected total agreement, got", summary)
+	}
+	if summary.SpeedupP50 <= 0 {
+		Fatal(t, "expected a positive median speedup, got", summary.SpeedupP50)
+	}
+
+	var reported valnode.DualValidationSummary
+	summaryFile, err := os.ReadFile(filepath.Join(reportDir, valnode.DualValidationSummaryFile))
+	Require(t, err)
+	Require(t, json.Unmarshal(summaryFile, &reported))
+	if reported.Compared < lastBlock || reported.AgreementRate != 1 {
+		Fatal(t, "unexpected reported summary", reported)
+	}
+
+	recordsFile, err := os.Open(filepath.Join(reportDir, valnode.DualValidationRecordsFile))
+	Require(t, err)
+	defer recordsFile.Close()
+	records := 0
+	scanner := bufio.NewScanner(recordsFile)
+	for scanner.Scan() {
+		var record valnode.DualValidationRecord
+		Require(t, json.Unmarshal(scanner.Bytes(), &record))
+		if !record.Agree || record.JitEnd != record.ArbitratorEnd || record.InputPath != "" {
+			Fatal(t, "unexpected disagreement in report", record)
+		}
+		records++
+	}
+	Require(t, scanner.Err())
+	// #nosec G115
+	if uint64(records) < lastBlock {
+		Fatal(t, "expected at least", lastBlock, "records, got", records)
+	}
+
+	if _, err := os.Stat(filepath.Join(reportDir, valnode.DualValidationInputsSubdir)); !errors.Is(err, os.ErrNotExist) {
+		Fatal(t, "expected no validation inputs to be captured, got", err)
+	}
+}
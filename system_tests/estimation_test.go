@@ -65,7 +65,7 @@ func TestEstimate(t *testing.T) {
 	gasPrice := big.NewInt(params.GWei / 10)
 
 	// set the gas price
-	arbOwner, err := precompilesgen.NewArbOwner(common.HexToAddress("0x70"), builder.L2.Client)
+	arbOwner, err := precompilesgen.NewArbOwner(common.HexToAddress("0x70"), builder.L2Client())
 	Require(t, err, "could not deploy ArbOwner contract")
 	tx, err := arbOwner.SetMinimumL2BaseFee(&auth, gasPrice)
 	Require(t, err, "could not set L2 gas price")
@@ -73,7 +73,7 @@ func TestEstimate(t *testing.T) {
 	Require(t, err)
 
 	// connect to arbGasInfo precompile
-	arbGasInfo, err := precompilesgen.NewArbGasInfo(common.HexToAddress("0x6c"), builder.L2.Client)
+	arbGasInfo, err := precompilesgen.NewArbGasInfo(common.HexToAddress("0x6c"), builder.L2Client())
 	Require(t, err, "could not deploy contract")
 
 	// wait for price to come to equilibrium
@@ -96,22 +96,22 @@ func TestEstimate(t *testing.T) {
 		Fatal(t, "L2 gas price did not converge", gasPrice)
 	}
 
-	initialBalance, err := builder.L2.Client.BalanceAt(ctx, auth.From, nil)
+	initialBalance, err := builder.L2Client().BalanceAt(ctx, auth.From, nil)
 	Require(t, err, "could not get balance")
 
 	// deploy a test contract
-	_, tx, simple, err := mocksgen.DeploySimple(&auth, builder.L2.Client)
+	_, tx, simple, err := mocksgen.DeploySimple(&auth, builder.L2Client())
 	Require(t, err, "could not deploy contract")
 	receipt, err := builder.L2.EnsureTxSucceeded(tx)
 	Require(t, err)
 
-	header, err := builder.L2.Client.HeaderByNumber(ctx, receipt.BlockNumber)
+	header, err := builder.L2Client().HeaderByNumber(ctx, receipt.BlockNumber)
 	Require(t, err, "could not get header")
 	if header.BaseFee.Cmp(gasPrice) != 0 {
 		Fatal(t, "Header has wrong basefee", header.BaseFee, gasPrice)
 	}
 
-	balance, err := builder.L2.Client.BalanceAt(ctx, auth.From, nil)
+	balance, err := builder.L2Client().BalanceAt(ctx, auth.From, nil)
 	Require(t, err, "could not get balance")
 	expectedCost := receipt.GasUsed * gasPrice.Uint64()
 	observedCost := initialBalance.Uint64() - balance.Uint64()
@@ -143,12 +143,12 @@ func TestDifficultyForLatestArbOS(t *testing.T) {
 	auth := builder.L2Info.GetDefaultTransactOpts("Owner", ctx)
 
 	// deploy a test contract
-	_, _, simple, err := mocksgen.DeploySimple(&auth, builder.L2.Client)
+	_, _, simple, err := mocksgen.DeploySimple(&auth, builder.L2Client())
 	Require(t, err, "could not deploy contract")
 
 	tx, err := simple.StoreDifficulty(&auth)
 	Require(t, err)
-	_, err = EnsureTxSucceeded(ctx, builder.L2.Client, tx)
+	_, err = EnsureTxSucceeded(ctx, builder.L2Client(), tx)
 	Require(t, err)
 	difficulty, err := simple.GetBlockDifficulty(&bind.CallOpts{})
 	Require(t, err)
@@ -169,12 +169,12 @@ func TestDifficultyForArbOSTen(t *testing.T) {
 	auth := builder.L2Info.GetDefaultTransactOpts("Owner", ctx)
 
 	// deploy a test contract
-	_, _, simple, err := mocksgen.DeploySimple(&auth, builder.L2.Client)
+	_, _, simple, err := mocksgen.DeploySimple(&auth, builder.L2Client())
 	Require(t, err, "could not deploy contract")
 
 	tx, err := simple.StoreDifficulty(&auth)
 	Require(t, err)
-	_, err = EnsureTxSucceeded(ctx, builder.L2.Client, tx)
+	_, err = EnsureTxSucceeded(ctx, builder.L2Client(), tx)
 	Require(t, err)
 	difficulty, err := simple.GetBlockDifficulty(&bind.CallOpts{})
 	Require(t, err)
@@ -191,7 +191,7 @@ func TestBlobBasefeeReverts(t *testing.T) {
 	cleanup := builder.Build(t)
 	defer cleanup()
 
-	_, err := builder.L2.Client.CallContract(ctx, ethereum.CallMsg{
+	_, err := builder.L2Client().CallContract(ctx, ethereum.CallMsg{
 		Data: []byte{byte(vm.BLOBBASEFEE)},
 	}, nil)
 	if err == nil {
@@ -244,7 +244,7 @@ func TestComponentEstimate(t *testing.T) {
 		Value:     value,
 		Data:      estimateCalldata,
 	}
-	returnData, err := builder.L2.Client.CallContract(ctx, msg, nil)
+	returnData, err := builder.L2Client().CallContract(ctx, msg, nil)
 	Require(t, err)
 
 	outputs, err := nodeMethod.Outputs.Unpack(returnData)
@@ -280,7 +280,7 @@ func TestComponentEstimate(t *testing.T) {
 		Fatal(t, baseFee, l2BaseFee.Uint64())
 	}
 
-	Require(t, builder.L2.Client.SendTransaction(ctx, tx))
+	Require(t, builder.L2Client().SendTransaction(ctx, tx))
 	receipt, err := builder.L2.EnsureTxSucceeded(tx)
 	Require(t, err)
 
@@ -301,10 +301,10 @@ func TestDisableL1Charging(t *testing.T) {
 	defer cleanup()
 	addr := common.HexToAddress("0x12345678")
 
-	gasWithL1Charging, err := builder.L2.Client.EstimateGas(ctx, ethereum.CallMsg{To: &addr})
+	gasWithL1Charging, err := builder.L2Client().EstimateGas(ctx, ethereum.CallMsg{To: &addr})
 	Require(t, err)
 
-	gasWithoutL1Charging, err := builder.L2.Client.EstimateGas(ctx, ethereum.CallMsg{To: &addr, SkipL1Charging: true})
+	gasWithoutL1Charging, err := builder.L2Client().EstimateGas(ctx, ethereum.CallMsg{To: &addr, SkipL1Charging: true})
 	Require(t, err)
 
 	if gasWithL1Charging <= gasWithoutL1Charging {
@@ -314,15 +314,15 @@ func TestDisableL1Charging(t *testing.T) {
 		Fatal(t, "Incorrect gas estimate with disabled L1 charging")
 	}
 
-	_, err = builder.L2.Client.CallContract(ctx, ethereum.CallMsg{To: &addr, Gas: gasWithL1Charging}, nil)
+	_, err = builder.L2Client().CallContract(ctx, ethereum.CallMsg{To: &addr, Gas: gasWithL1Charging}, nil)
 	Require(t, err)
 
-	_, err = builder.L2.Client.CallContract(ctx, ethereum.CallMsg{To: &addr, Gas: gasWithoutL1Charging}, nil)
+	_, err = builder.L2Client().CallContract(ctx, ethereum.CallMsg{To: &addr, Gas: gasWithoutL1Charging}, nil)
 	if err == nil {
 		Fatal(t, "CallContract passed with insufficient gas")
 	}
 
-	_, err = builder.L2.Client.CallContract(ctx, ethereum.CallMsg{To: &addr, Gas: gasWithoutL1Charging, SkipL1Charging: true}, nil)
+	_, err = builder.L2Client().CallContract(ctx, ethereum.CallMsg{To: &addr, Gas: gasWithoutL1Charging, SkipL1Charging: true}, nil)
 	Require(t, err)
 }
 
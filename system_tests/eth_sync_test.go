@@ -32,7 +32,7 @@ func TestEthSyncing(t *testing.T) {
 
 	tx := builder.L2Info.PrepareTx("Owner", "User2", builder.L2Info.TransferGas, big.NewInt(1e12), nil)
 
-	err = builder.L2.Client.SendTransaction(ctx, tx)
+	err = builder.L2Client().SendTransaction(ctx, tx)
 	Require(t, err)
 
 	_, err = builder.L2.EnsureTxSucceeded(tx)
@@ -82,10 +82,10 @@ func TestFastConfirmation(t *testing.T) {
 	builder.L1.TransferBalance(t, "Faucet", "Validator", balance, builder.L1Info)
 	l1auth := builder.L1Info.GetDefaultTransactOpts("Validator", ctx)
 
-	rollup, err := rollupgen.NewRollupAdminLogic(l2node.DeployInfo.Rollup, builder.L1.Client)
+	rollup, err := rollupgen.NewRollupAdminLogic(l2node.DeployInfo.Rollup, builder.L1Client())
 	Require(t, err)
 
-	upgradeExecutor, err := upgrade_executorgen.NewUpgradeExecutor(l2node.DeployInfo.UpgradeExecutor, builder.L1.Client)
+	upgradeExecutor, err := upgrade_executorgen.NewUpgradeExecutor(l2node.DeployInfo.UpgradeExecutor, builder.L1Client())
 	Require(t, err, "unable to bind upgrade executor")
 	rollupABI, err := abi.JSON(strings.NewReader(rollupgen.RollupAdminLogicABI))
 	Require(t, err, "unable to parse rollup ABI")
@@ -99,7 +99,7 @@ func TestFastConfirmation(t *testing.T) {
 
 	valConfig := legacystaker.TestL1ValidatorConfig
 	valConfig.EnableFastConfirmation = true
-	parentChainID, err := builder.L1.Client.ChainID(ctx)
+	parentChainID, err := builder.L1Client().ChainID(ctx)
 	if err != nil {
 		t.Fatalf("Failed to get parent chain id: %v", err)
 	}
@@ -183,7 +183,7 @@ func TestFastConfirmation(t *testing.T) {
 
 	builder.L2Info.GenerateAccount("BackgroundUser")
 	tx = builder.L2Info.PrepareTx("Faucet", "BackgroundUser", builder.L2Info.TransferGas, balance, nil)
-	err = builder.L2.Client.SendTransaction(ctx, tx)
+	err = builder.L2Client().SendTransaction(ctx, tx)
 	Require(t, err)
 	_, err = builder.L2.EnsureTxSucceeded(tx)
 	Require(t, err)
@@ -281,10 +281,10 @@ func TestFastConfirmationWithSafe(t *testing.T) {
 	builder.L1.TransferBalance(t, "Faucet", "ValidatorB", balance, builder.L1Info)
 	l1authB := builder.L1Info.GetDefaultTransactOpts("ValidatorB", ctx)
 
-	rollup, err := rollupgen.NewRollupAdminLogic(l2nodeA.DeployInfo.Rollup, builder.L1.Client)
+	rollup, err := rollupgen.NewRollupAdminLogic(l2nodeA.DeployInfo.Rollup, builder.L1Client())
 	Require(t, err)
 
-	upgradeExecutor, err := upgrade_executorgen.NewUpgradeExecutor(l2nodeA.DeployInfo.UpgradeExecutor, builder.L1.Client)
+	upgradeExecutor, err := upgrade_executorgen.NewUpgradeExecutor(l2nodeA.DeployInfo.UpgradeExecutor, builder.L1Client())
 	Require(t, err, "unable to bind upgrade executor")
 	rollupABI, err := abi.JSON(strings.NewReader(rollupgen.RollupAdminLogicABI))
 	Require(t, err, "unable to parse rollup ABI")
@@ -299,7 +299,7 @@ func TestFastConfirmationWithSafe(t *testing.T) {
 	valConfigA := legacystaker.TestL1ValidatorConfig
 	valConfigA.EnableFastConfirmation = true
 
-	parentChainID, err := builder.L1.Client.ChainID(ctx)
+	parentChainID, err := builder.L1Client().ChainID(ctx)
 	if err != nil {
 		t.Fatalf("Failed to get parent chain id: %v", err)
 	}
@@ -327,7 +327,7 @@ func TestFastConfirmationWithSafe(t *testing.T) {
 		Require(t, err, "didn't cache validator wallet address", valWalletAddrA.String(), "vs", valWalletAddrCheck.String())
 	}
 
-	safeAddress := deploySafe(t, builder.L1, builder.L1.Client, deployAuth, []common.Address{valWalletAddrA, srv.Address})
+	safeAddress := deploySafe(t, builder.L1, builder.L1Client(), deployAuth, []common.Address{valWalletAddrA, srv.Address})
 	setValidatorCalldata, err := rollupABI.Pack("setValidator", []common.Address{valWalletAddrA, l1authB.From, srv.Address, safeAddress}, []bool{true, true, true, true})
 	Require(t, err, "unable to generate setValidator calldata")
 	tx, err = upgradeExecutor.ExecuteCall(&deployAuth, l2nodeA.DeployInfo.Rollup, setValidatorCalldata)
@@ -430,7 +430,7 @@ func TestFastConfirmationWithSafe(t *testing.T) {
 
 	builder.L2Info.GenerateAccount("BackgroundUser")
 	tx = builder.L2Info.PrepareTx("Faucet", "BackgroundUser", builder.L2Info.TransferGas, balance, nil)
-	err = builder.L2.Client.SendTransaction(ctx, tx)
+	err = builder.L2Client().SendTransaction(ctx, tx)
 	Require(t, err)
 	_, err = builder.L2.EnsureTxSucceeded(tx)
 	Require(t, err)
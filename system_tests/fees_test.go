@@ -43,11 +43,11 @@ func TestSequencerFeePaid(t *testing.T) {
 	callOpts := builder.L2Info.GetDefaultCallOpts("Owner", ctx)
 
 	// get the network fee account
-	arbOwnerPublic, err := precompilesgen.NewArbOwnerPublic(common.HexToAddress("0x6b"), builder.L2.Client)
+	arbOwnerPublic, err := precompilesgen.NewArbOwnerPublic(common.HexToAddress("0x6b"), builder.L2Client())
 	Require(t, err, "failed to deploy contract")
-	arbGasInfo, err := precompilesgen.NewArbGasInfo(common.HexToAddress("0x6c"), builder.L2.Client)
+	arbGasInfo, err := precompilesgen.NewArbGasInfo(common.HexToAddress("0x6c"), builder.L2Client())
 	Require(t, err, "failed to deploy contract")
-	arbDebug, err := precompilesgen.NewArbDebug(common.HexToAddress("0xff"), builder.L2.Client)
+	arbDebug, err := precompilesgen.NewArbDebug(common.HexToAddress("0xff"), builder.L2Client())
 	Require(t, err, "failed to deploy contract")
 	networkFeeAccount, err := arbOwnerPublic.GetNetworkFeeAccount(callOpts)
 	Require(t, err, "could not get the network fee account")
@@ -150,7 +150,7 @@ func testSequencerPriceAdjustsFrom(t *testing.T, initialEstimate uint64) {
 	ownerAuth := builder.L2Info.GetDefaultTransactOpts("Owner", ctx)
 
 	// make ownerAuth a chain owner
-	arbdebug, err := precompilesgen.NewArbDebug(common.HexToAddress("0xff"), builder.L2.Client)
+	arbdebug, err := precompilesgen.NewArbDebug(common.HexToAddress("0xff"), builder.L2Client())
 	Require(t, err)
 	tx, err := arbdebug.BecomeChainOwner(&ownerAuth)
 	Require(t, err)
@@ -158,20 +158,20 @@ func testSequencerPriceAdjustsFrom(t *testing.T, initialEstimate uint64) {
 
 	// use ownerAuth to set the L1 price per unit
 	Require(t, err)
-	arbOwner, err := precompilesgen.NewArbOwner(common.HexToAddress("0x70"), builder.L2.Client)
+	arbOwner, err := precompilesgen.NewArbOwner(common.HexToAddress("0x70"), builder.L2Client())
 	Require(t, err)
 	tx, err = arbOwner.SetL1PricePerUnit(&ownerAuth, arbmath.UintToBig(initialEstimate))
 	Require(t, err)
-	_, err = WaitForTx(ctx, builder.L2.Client, tx.Hash(), time.Second*5)
+	_, err = WaitForTx(ctx, builder.L2Client(), tx.Hash(), time.Second*5)
 	Require(t, err)
 
-	arbGasInfo, err := precompilesgen.NewArbGasInfo(common.HexToAddress("0x6c"), builder.L2.Client)
+	arbGasInfo, err := precompilesgen.NewArbGasInfo(common.HexToAddress("0x6c"), builder.L2Client())
 	Require(t, err)
 	lastEstimate, err := arbGasInfo.GetL1BaseFeeEstimate(&bind.CallOpts{Context: ctx})
 	Require(t, err)
 	lastBatchCount, err := builder.L2.ConsensusNode.InboxTracker.GetBatchCount()
 	Require(t, err)
-	l1Header, err := builder.L1.Client.HeaderByNumber(ctx, nil)
+	l1Header, err := builder.L1Client().HeaderByNumber(ctx, nil)
 	Require(t, err)
 
 	rewardRecipientBalanceBefore := builder.L2.GetBalance(t, l1pricing.BatchPosterAddress)
@@ -184,7 +184,7 @@ func testSequencerPriceAdjustsFrom(t *testing.T, initialEstimate uint64) {
 	numRetrogradeMoves := 0
 	for i := 0; i < 256; i++ {
 		tx, receipt := builder.L2.TransferBalance(t, "Owner", "Owner", common.Big1, builder.L2Info)
-		header, err := builder.L2.Client.HeaderByHash(ctx, receipt.BlockHash)
+		header, err := builder.L2Client().HeaderByHash(ctx, receipt.BlockHash)
 		Require(t, err)
 
 		builder.L1.TransferBalance(t, "Faucet", "Faucet", common.Big1, builder.L1Info) // generate l1 traffic
@@ -193,7 +193,7 @@ func testSequencerPriceAdjustsFrom(t *testing.T, initialEstimate uint64) {
 		estimatedL1FeePerUnit := arbmath.BigDivByUint(arbmath.BigMulByUint(header.BaseFee, receipt.GasUsedForL1), units)
 
 		if !arbmath.BigEquals(lastEstimate, estimatedL1FeePerUnit) {
-			l1Header, err = builder.L1.Client.HeaderByNumber(ctx, nil)
+			l1Header, err = builder.L1Client().HeaderByNumber(ctx, nil)
 			Require(t, err)
 
 			callOpts := &bind.CallOpts{Context: ctx, BlockNumber: receipt.BlockNumber}
@@ -269,7 +269,7 @@ func testSequencerPriceAdjustsFrom(t *testing.T, initialEstimate uint64) {
 		Fatal(t, "reward recipient didn't get paid")
 	}
 
-	arbAggregator, err := precompilesgen.NewArbAggregator(common.HexToAddress("0x6d"), builder.L2.Client)
+	arbAggregator, err := precompilesgen.NewArbAggregator(common.HexToAddress("0x6d"), builder.L2Client())
 	Require(t, err)
 	batchPosterAddresses, err := arbAggregator.GetBatchPosters(&bind.CallOpts{Context: ctx})
 	Require(t, err)
@@ -277,7 +277,7 @@ func testSequencerPriceAdjustsFrom(t *testing.T, initialEstimate uint64) {
 	for _, bpAddr := range batchPosterAddresses {
 		if bpAddr != l1pricing.BatchPosterAddress && bpAddr != l1pricing.L1PricerFundsPoolAddress {
 			numReimbursed++
-			bal, err := builder.L1.Client.BalanceAt(ctx, bpAddr, nil)
+			bal, err := builder.L1Client().BalanceAt(ctx, bpAddr, nil)
 			Require(t, err)
 			if bal.Sign() == 0 {
 				Fatal(t, "Batch poster balance is zero for", bpAddr)
@@ -37,7 +37,7 @@ func TestStaticForwarder(t *testing.T) {
 	cleanupA := builder.Build(t)
 	defer cleanupA()
 
-	clientA := builder.L2.Client
+	clientA := builder.L2Client()
 
 	nodeConfigB := arbnode.ConfigDefaultL1Test()
 	execConfigB := ExecConfigDefaultTest(t)
@@ -230,7 +230,7 @@ func TestRedisForwarder(t *testing.T) {
 		})
 	cleanup := builder.Build(t)
 	defer cleanup()
-	fallbackNode, fallbackClient := builder.L2.ConsensusNode, builder.L2.Client
+	fallbackNode, fallbackClient := builder.L2.ConsensusNode, builder.L2Client()
 
 	TestClientForwarding, cleanupForwarding := createForwardingNode(t, builder, "", redisUrl, fbNodePath)
 	defer cleanupForwarding()
@@ -301,7 +301,7 @@ func TestRedisForwarderFallbackNoRedis(t *testing.T) {
 		})
 	cleanup := builder.Build(t)
 	defer cleanup()
-	fallbackClient := builder.L2.Client
+	fallbackClient := builder.L2Client()
 
 	TestClientForwarding, cleanupForwarding := createForwardingNode(t, builder, "", redisUrl, fallbackIpcPath)
 	defer cleanupForwarding()
@@ -270,7 +270,7 @@ func RunChallengeTest(t *testing.T, asserterIsCorrect bool, useStubs bool, chall
 	configByValidationNode(conf, valStack)
 
 	builder.BuildL1(t)
-	l1Backend := builder.L1.Client
+	l1Backend := builder.L1Client()
 
 	deployerTxOpts := l1Info.GetDefaultTransactOpts("deployer", ctx)
 	sequencerTxOpts := l1Info.GetDefaultTransactOpts("sequencer", ctx)
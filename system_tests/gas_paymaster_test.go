@@ -0,0 +1,145 @@
+// Copyright 2024, Offchain Labs, Inc.
+// For license information, see https://github.com/nitro/blob/master/LICENSE
+
+package arbtest
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+
+	"github.com/offchainlabs/nitro/solgen/go/precompilesgen"
+	"github.com/offchainlabs/nitro/util/arbmath"
+)
+
+func TestGasPaymaster(t *testing.T) {
+	t.Parallel()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	builder := NewNodeBuilder(ctx).DefaultConfig(t, false)
+	cleanup := builder.Build(t)
+	defer cleanup()
+
+	ownerTxOpts := builder.L2Info.GetDefaultTransactOpts("Owner", ctx)
+	callOpts := builder.L2Info.GetDefaultCallOpts("Owner", ctx)
+	arbOwner, err := precompilesgen.NewArbOwner(types.ArbOwnerAddress, builder.L2Client())
+	Require(t, err)
+	arbOwnerPublic, err := precompilesgen.NewArbOwnerPublic(types.ArbOwnerPublicAddress, builder.L2Client())
+	Require(t, err)
+
+	builder.L2Info.GenerateAccount("Paymaster")
+	builder.L2Info.GenerateAccount("BrokePaymaster")
+	builder.L2Info.GenerateAccount("User")
+	builder.L2Info.GenerateAccount("User2")
+	builder.L2.TransferBalance(t, "Owner", "Paymaster", big.NewInt(1e18), builder.L2Info)
+	paymaster := builder.L2Info.GetAddress("Paymaster")
+	user := builder.L2Info.GetAddress("User")
+
+	setPaymaster := func(account common.Address) {
+		t.Helper()
+		tx, err := arbOwner.SetGasPaymaster(&ownerTxOpts, account)
+		Require(t, err)
+		_, err = builder.L2.EnsureTxSucceeded(tx)
+		Require(t, err)
+		configured, err := arbOwnerPublic.GetGasPaymaster(callOpts)
+		Require(t, err)
+		if configured != account {
+			Fatal(t, "expected gas paymaster", account, "got", configured)
+		}
+	}
+	// sendFreeTx sends a transaction from User offering no fee, only using up its nonce if it's accepted
+	sendFreeTx := func() (*types.Receipt, error) {
+		t.Helper()
+		info := builder.L2Info.GetInfoWithPrivKey("User")
+		to := builder.L2Info.GetAddress("User2")
+		tx := builder.L2Info.SignTxAs("User", &types.DynamicFeeTx{
+			To:        &to,
+			Gas:       builder.L2Info.TransferGas,
+			GasFeeCap: common.Big0,
+			GasTipCap: common.Big0,
+			Value:     common.Big0,
+			Nonce:     info.Nonce.Load(),
+		})
+		if err := builder.L2Client().SendTransaction(ctx, tx); err != nil {
+			return nil, err
+		}
+		info.Nonce.Add(1)
+		return builder.L2.EnsureTxSucceeded(tx)
+	}
+	balance := func(account common.Address) *big.Int {
+		t.Helper()
+		balance, err := builder.L2Client().BalanceAt(ctx, account, nil)
+		Require(t, err)
+		return balance
+	}
+
+	if _, err := sendFreeTx(); err == nil {
+		Fatal(t, "transaction offering no fee accepted without a gas paymaster")
+	}
+
+	setPaymaster(paymaster)
+	if _, err := sendFreeTx(); err == nil {
+		Fatal(t, "transaction offering no fee accepted from a sender the gas paymaster doesn't sponsor")
+	}
+	if userBalance := balance(user); userBalance.Sign() != 0 {
+		Fatal(t, "expected the paymaster not to fund an unsponsored sender, got balance", userBalance)
+	}
+
+	tx, err := arbOwner.AddGasPaymasterSponsoredSender(&ownerTxOpts, user)
+	Require(t, err)
+	_, err = builder.L2.EnsureTxSucceeded(tx)
+	Require(t, err)
+	sponsored, err := arbOwnerPublic.GetAllGasPaymasterSponsoredSenders(callOpts)
+	Require(t, err)
+	if len(sponsored) != 1 || sponsored[0] != user {
+		Fatal(t, "expected the paymaster to sponsor only", user, "got", sponsored)
+	}
+	paymasterBalanceBefore := balance(paymaster)
+	receipt, err := sendFreeTx()
+	Require(t, err)
+	header, err := builder.L2Client().HeaderByNumber(ctx, receipt.BlockNumber)
+	Require(t, err)
+	fee := arbmath.BigMulByUint(header.BaseFee, receipt.GasUsed)
+	if paid := arbmath.BigSub(paymasterBalanceBefore, balance(paymaster)); !arbmath.BigEquals(paid, fee) {
+		Fatal(t, "expected paymaster to pay", fee, "but it paid", paid)
+	}
+	if userBalance := balance(user); userBalance.Sign() != 0 {
+		Fatal(t, "expected sender to keep no funds from the paymaster, got", userBalance)
+	}
+
+	// transactions offering a fee are still paid for by their sender
+	paymasterBalanceBefore = balance(paymaster)
+	builder.L2.TransferBalance(t, "Owner", "User2", big.NewInt(1), builder.L2Info)
+	if paymasterBalance := balance(paymaster); !arbmath.BigEquals(paymasterBalance, paymasterBalanceBefore) {
+		Fatal(t, "paymaster paid for a transaction offering a fee")
+	}
+
+	// a paymaster without the funds to cover the transaction can't sponsor it
+	setPaymaster(builder.L2Info.GetAddress("BrokePaymaster"))
+	if _, err := sendFreeTx(); err == nil {
+		Fatal(t, "transaction offering no fee accepted with an unfunded gas paymaster")
+	}
+
+	setPaymaster(paymaster)
+	tx, err = arbOwner.RemoveGasPaymasterSponsoredSender(&ownerTxOpts, user)
+	Require(t, err)
+	_, err = builder.L2.EnsureTxSucceeded(tx)
+	Require(t, err)
+	isSponsored, err := arbOwnerPublic.IsGasPaymasterSponsoredSender(callOpts, user)
+	Require(t, err)
+	if isSponsored {
+		Fatal(t, "sender still sponsored after being removed")
+	}
+	if _, err := sendFreeTx(); err == nil {
+		Fatal(t, "transaction offering no fee accepted from a sender no longer sponsored")
+	}
+
+	setPaymaster(common.Address{})
+	if _, err := sendFreeTx(); err == nil {
+		Fatal(t, "transaction offering no fee accepted after removing the gas paymaster")
+	}
+}
@@ -0,0 +1,114 @@
+// Copyright 2024, Offchain Labs, Inc.
+// For license information, see https://github.com/nitro/blob/master/LICENSE
+
+package arbtest
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+
+	"github.com/offchainlabs/nitro/arbstate/daprovider"
+	"github.com/offchainlabs/nitro/solgen/go/bridgegen"
+	"github.com/offchainlabs/nitro/util/inboxproof"
+)
+
+func TestTransactionInclusionProof(t *testing.T) {
+	t.Parallel()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	builder := NewNodeBuilder(ctx).DefaultConfig(t, true)
+	cleanup := builder.Build(t)
+	defer cleanup()
+
+	builder.L2Info.GenerateAccount("User2")
+
+	// several sequencer messages so that batches hold more than one message
+	var sequencedTxs []*types.Transaction
+	for i := 0; i < 5; i++ {
+		tx := builder.L2Info.PrepareTx("Owner", "User2", builder.L2Info.TransferGas, big.NewInt(1e12), nil)
+		sequencedTxs = append(sequencedTxs, tx)
+	}
+	SendWaitTestTransactions(t, ctx, builder.L2Client(), sequencedTxs)
+
+	delayedTx := builder.L2Info.PrepareTx("Owner", "User2", builder.L2Info.TransferGas, big.NewInt(1e12), nil)
+	builder.L1.SendSignedTx(t, builder.L2Client(), delayedTx, builder.L1Info)
+
+	var delayedBatchTxs types.Transactions
+	for i := 0; i < 3; i++ {
+		tx := builder.L2Info.PrepareTx("Owner", "User2", builder.L2Info.TransferGas, big.NewInt(1e12), nil)
+		delayedBatchTxs = append(delayedBatchTxs, tx)
+	}
+	SendSignedTxesInBatchViaL1(t, ctx, builder.L1Info, builder.L1Client(), builder.L2Client(), delayedBatchTxs)
+
+	seqInbox, err := bridgegen.NewSequencerInbox(builder.L1Info.GetAddress("SequencerInbox"), builder.L1Client())
+	Require(t, err)
+	rpcClient := builder.L2.ConsensusNode.Stack.Attach()
+
+	getProof := func(tx *types.Transaction) *inboxproof.InclusionProof {
+		var proof *inboxproof.InclusionProof
+		for i := 0; ; i++ {
+			err := rpcClient.CallContext(ctx, &proof, "arb_getTransactionInclusionProof", tx.Hash())
+			if err == nil {
+				return proof
+			}
+			if i == 100 {
+				Fatal(t, "failed to get inclusion proof for", tx.Hash(), err)
+			}
+			AdvanceL1(t, ctx, builder.L1Client(), builder.L1Info, 1)
+			time.Sleep(time.Millisecond * 100)
+		}
+	}
+
+	checkProof := func(tx *types.Transaction, expectDelayed bool) *inboxproof.InclusionProof {
+		proof := getProof(tx)
+		if (proof.DelayedMessage != nil) != expectDelayed {
+			Fatal(t, "unexpected delayed message proof for", tx.Hash(), "expected delayed", expectDelayed)
+		}
+		if proof.BatchData[40] != daprovider.BrotliMessageHeaderByte {
+			Fatal(t, "expected brotli-compressed batch, got header byte", proof.BatchData[40])
+		}
+		inboxAcc, err := seqInbox.InboxAccs(&bind.CallOpts{Context: ctx}, new(big.Int).SetUint64(proof.BatchNumber))
+		Require(t, err)
+		txBytes, err := tx.MarshalBinary()
+		Require(t, err)
+		Require(t, inboxproof.Verify(ctx, proof, txBytes, inboxAcc))
+
+		err = inboxproof.Verify(ctx, proof, txBytes, common.Hash{})
+		if !errors.Is(err, inboxproof.ErrInboxAccMismatch) {
+			Fatal(t, "proof verified against wrong accumulator", err)
+		}
+		return proof
+	}
+
+	sawMultiMessageBatch := false
+	for _, tx := range sequencedTxs {
+		proof := checkProof(tx, false)
+		if proof.MessageIndexInBatch > 0 {
+			sawMultiMessageBatch = true
+		}
+		otherTxBytes, err := delayedTx.MarshalBinary()
+		Require(t, err)
+		inboxAcc, err := seqInbox.InboxAccs(&bind.CallOpts{Context: ctx}, new(big.Int).SetUint64(proof.BatchNumber))
+		Require(t, err)
+		err = inboxproof.Verify(ctx, proof, otherTxBytes, inboxAcc)
+		if !errors.Is(err, inboxproof.ErrTxNotInSegment) {
+			Fatal(t, "proof verified for the wrong transaction", err)
+		}
+	}
+	if !sawMultiMessageBatch {
+		Fatal(t, "no batch contained more than one message")
+	}
+
+	checkProof(delayedTx, true)
+	for _, tx := range delayedBatchTxs {
+		checkProof(tx, true)
+	}
+}
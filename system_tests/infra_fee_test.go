@@ -34,9 +34,9 @@ func TestInfraFee(t *testing.T) {
 	ownerTxOpts.Context = ctx
 	ownerCallOpts := builder.L2Info.GetDefaultCallOpts("Owner", ctx)
 
-	arbowner, err := precompilesgen.NewArbOwner(common.HexToAddress("70"), builder.L2.Client)
+	arbowner, err := precompilesgen.NewArbOwner(common.HexToAddress("70"), builder.L2Client())
 	Require(t, err)
-	arbownerPublic, err := precompilesgen.NewArbOwnerPublic(common.HexToAddress("6b"), builder.L2.Client)
+	arbownerPublic, err := precompilesgen.NewArbOwnerPublic(common.HexToAddress("6b"), builder.L2Client())
 	Require(t, err)
 	networkFeeAddr, err := arbownerPublic.GetNetworkFeeAccount(ownerCallOpts)
 	Require(t, err)
@@ -48,9 +48,9 @@ func TestInfraFee(t *testing.T) {
 
 	_, simple := builder.L2.DeploySimple(t, ownerTxOpts)
 
-	netFeeBalanceBefore, err := builder.L2.Client.BalanceAt(ctx, networkFeeAddr, nil)
+	netFeeBalanceBefore, err := builder.L2Client().BalanceAt(ctx, networkFeeAddr, nil)
 	Require(t, err)
-	infraFeeBalanceBefore, err := builder.L2.Client.BalanceAt(ctx, infraFeeAddr, nil)
+	infraFeeBalanceBefore, err := builder.L2Client().BalanceAt(ctx, infraFeeAddr, nil)
 	Require(t, err)
 
 	tx, err = simple.Increment(&ownerTxOpts)
@@ -61,9 +61,9 @@ func TestInfraFee(t *testing.T) {
 	expectedFunds := arbmath.BigMulByUint(arbmath.UintToBig(l2pricing.InitialBaseFeeWei), l2GasUsed)
 	expectedBalanceAfter := arbmath.BigAdd(infraFeeBalanceBefore, expectedFunds)
 
-	netFeeBalanceAfter, err := builder.L2.Client.BalanceAt(ctx, networkFeeAddr, nil)
+	netFeeBalanceAfter, err := builder.L2Client().BalanceAt(ctx, networkFeeAddr, nil)
 	Require(t, err)
-	infraFeeBalanceAfter, err := builder.L2.Client.BalanceAt(ctx, infraFeeAddr, nil)
+	infraFeeBalanceAfter, err := builder.L2Client().BalanceAt(ctx, infraFeeAddr, nil)
 	Require(t, err)
 
 	if !arbmath.BigEquals(netFeeBalanceBefore, netFeeBalanceAfter) {
@@ -73,7 +73,7 @@ func TestInitContract(t *testing.T) {
 		msg := ethereum.CallMsg{
 			To: &accountAddress,
 		}
-		res, err := builder.L2.Client.CallContract(ctx, msg, big.NewInt(0))
+		res, err := builder.L2Client().CallContract(ctx, msg, big.NewInt(0))
 		Require(t, err)
 		resBig := new(big.Int).SetBytes(res)
 		if resBig.Cmp(sum) != 0 {
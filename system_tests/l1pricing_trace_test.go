@@ -0,0 +1,212 @@
+// Copyright 2024, Offchain Labs, Inc.
+// For license information, see https://github.com/nitro/blob/master/LICENSE
+
+package arbtest
+
+import (
+	"context"
+	"math/big"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+
+	"github.com/offchainlabs/nitro/arbos/l1pricing"
+	"github.com/offchainlabs/nitro/execution"
+	"github.com/offchainlabs/nitro/solgen/go/precompilesgen"
+	"github.com/offchainlabs/nitro/util/arbmath"
+	"github.com/offchainlabs/nitro/util/testhelpers"
+)
+
+func TestL1PricingUpdateTrace(t *testing.T) {
+	t.Parallel()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	builder := NewNodeBuilder(ctx).DefaultConfig(t, true)
+	builder.nodeConfig.DelayedSequencer.FinalizeDistance = 1
+	cleanup := builder.Build(t)
+	defer cleanup()
+	// SimulatedBeacon produces blocks in the future, so don't hold back batches for appearing to be from the future
+	builder.nodeConfig.BatchPoster.MaxDelay = -time.Hour
+
+	arbGasInfo, err := precompilesgen.NewArbGasInfo(types.ArbGasInfoAddress, builder.L2Client())
+	Require(t, err)
+	rpcClient := builder.L2.ConsensusNode.Stack.Attach()
+	getTraces := func(from, to uint64) []execution.L1PricingUpdateTrace {
+		t.Helper()
+		var traces []execution.L1PricingUpdateTrace
+		err := rpcClient.CallContext(ctx, &traces, "arb_getL1PricingUpdateTrace", hexutil.Uint64(from), hexutil.Uint64(to))
+		Require(t, err)
+		return traces
+	}
+
+	// post batches of varying sizes until several have been reported
+	var traces []execution.L1PricingUpdateTrace
+	for i := 0; ; i++ {
+		if i == 256 {
+			Fatal(t, "not enough batch posting reports were traced, got", len(traces))
+		}
+		// random data doesn't compress, and the poster is charged for it at the L1 price, so leave plenty of gas
+		// #nosec G115
+		data := testhelpers.RandomSlice(uint64(i%4) * 100)
+		tx := builder.L2Info.PrepareTx("Owner", "Owner", 5_000_000, common.Big1, data)
+		Require(t, builder.L2Client().SendTransaction(ctx, tx))
+		_, err := builder.L2.EnsureTxSucceeded(tx)
+		Require(t, err)
+		// generate L1 traffic so batches and their reports make it into L2
+		builder.L1.TransferBalance(t, "Faucet", "Faucet", common.Big1, builder.L1Info)
+		head, err := builder.L2Client().BlockNumber(ctx)
+		Require(t, err)
+		traces = getTraces(0, head)
+		if len(traces) >= 4 {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	batchDataGas := make(map[uint64]bool)
+	for _, trace := range traces {
+		blockNumber := uint64(trace.BlockNumber)
+		block, err := builder.L2Client().BlockByNumber(ctx, new(big.Int).SetUint64(blockNumber))
+		Require(t, err)
+		if block.Hash() != trace.BlockHash {
+			Fatal(t, "trace of block", blockNumber, "has hash", trace.BlockHash, "but block has hash", block.Hash())
+		}
+		batchDataGas[uint64(trace.BatchDataGas)] = true
+
+		// the report charged for the batch's data at the basefee the poster observed
+		gasSpent := arbmath.SaturatingAdd(trace.PerBatchGas, arbmath.SaturatingCast[int64](uint64(trace.BatchDataGas)))
+		expectedWeiSpent := arbmath.BigMulByUint(trace.L1BaseFee.ToInt(), arbmath.SaturatingUCast[uint64](gasSpent))
+		if !arbmath.BigEquals(trace.WeiSpent.ToInt(), expectedWeiSpent) {
+			Fatal(t, "trace of block", blockNumber, "spent", trace.WeiSpent, "but expected", expectedWeiSpent)
+		}
+		if slices.Contains(trace.Rules, l1pricing.RuleAmortizedCostCap) != (trace.WeiAllocated.ToInt().Cmp(trace.WeiSpent.ToInt()) < 0) {
+			Fatal(t, "trace of block", blockNumber, "doesn't explain the cost allocated", trace.WeiAllocated, "of", trace.WeiSpent, trace.Rules)
+		}
+
+		// the trace explains the price movement the block made
+		priceBefore, err := arbGasInfo.GetL1BaseFeeEstimate(&bind.CallOpts{Context: ctx, BlockNumber: new(big.Int).SetUint64(blockNumber - 1)})
+		Require(t, err)
+		priceAfter, err := arbGasInfo.GetL1BaseFeeEstimate(&bind.CallOpts{Context: ctx, BlockNumber: new(big.Int).SetUint64(blockNumber)})
+		Require(t, err)
+		if !arbmath.BigEquals(trace.PriceBefore.ToInt(), priceBefore) || !arbmath.BigEquals(trace.PriceAfter.ToInt(), priceAfter) {
+			Fatal(t, "trace of block", blockNumber, "has price", trace.PriceBefore, "->", trace.PriceAfter, "but price moved", priceBefore, "->", priceAfter)
+		}
+		switch {
+		case slices.Contains(trace.Rules, l1pricing.RuleNoUnitsAllocated):
+			if trace.UnitsAllocated != 0 || !arbmath.BigEquals(priceBefore, priceAfter) {
+				Fatal(t, "trace of block", blockNumber, "allocated", trace.UnitsAllocated, "units, with price", priceBefore, "->", priceAfter)
+			}
+		case slices.Contains(trace.Rules, l1pricing.RulePriceAdjusted):
+			if trace.UnitsAllocated == 0 {
+				Fatal(t, "trace of block", blockNumber, "adjusted the price without allocating units")
+			}
+			surplus, err := arbGasInfo.GetL1PricingSurplus(&bind.CallOpts{Context: ctx, BlockNumber: new(big.Int).SetUint64(blockNumber)})
+			Require(t, err)
+			if !arbmath.BigEquals(trace.SurplusAfter, surplus) {
+				Fatal(t, "trace of block", blockNumber, "has surplus after", trace.SurplusAfter, "but surplus is", surplus)
+			}
+		default:
+			Fatal(t, "trace of block", blockNumber, "doesn't say whether the price was adjusted", trace.Rules)
+		}
+	}
+	if len(batchDataGas) < 2 {
+		Fatal(t, "expected batches of varying sizes, got", batchDataGas)
+	}
+
+	// ranges are inclusive
+	last := uint64(traces[len(traces)-1].BlockNumber)
+	if got := getTraces(last, last); len(got) != 1 || got[0].BlockHash != traces[len(traces)-1].BlockHash {
+		Fatal(t, "expected the last trace when querying its block alone, got", got)
+	}
+	var ignored []execution.L1PricingUpdateTrace
+	if err := rpcClient.CallContext(ctx, &ignored, "arb_getL1PricingUpdateTrace", hexutil.Uint64(last), hexutil.Uint64(last-1)); err == nil {
+		Fatal(t, "expected an error querying a backwards range")
+	}
+}
+
+func TestL1PricingThrottle(t *testing.T) {
+	t.Parallel()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	builder := NewNodeBuilder(ctx).DefaultConfig(t, true)
+	builder.nodeConfig.DelayedSequencer.FinalizeDistance = 1
+	cleanup := builder.Build(t)
+	defer cleanup()
+	// SimulatedBeacon produces blocks in the future, so don't hold back batches for appearing to be from the future
+	builder.nodeConfig.BatchPoster.MaxDelay = -time.Hour
+
+	auth := builder.L2Info.GetDefaultTransactOpts("Owner", ctx)
+	arbDebug, err := precompilesgen.NewArbDebug(types.ArbDebugAddress, builder.L2Client())
+	Require(t, err)
+	tx, err := arbDebug.BecomeChainOwner(&auth)
+	Require(t, err)
+	_, err = builder.L2.EnsureTxSucceeded(tx)
+	Require(t, err)
+	arbOwner, err := precompilesgen.NewArbOwner(types.ArbOwnerAddress, builder.L2Client())
+	Require(t, err)
+	arbGasInfo, err := precompilesgen.NewArbGasInfo(types.ArbGasInfoAddress, builder.L2Client())
+	Require(t, err)
+
+	const throttlePercent = 1
+	tx, err = arbOwner.SetL1PricingThrottlePercent(&auth, throttlePercent)
+	Require(t, err)
+	_, err = builder.L2.EnsureTxSucceeded(tx)
+	Require(t, err)
+	percent, err := arbGasInfo.GetL1PricingThrottlePercent(&bind.CallOpts{Context: ctx})
+	Require(t, err)
+	if percent != throttlePercent {
+		Fatal(t, "expected throttle percent", throttlePercent, "got", percent)
+	}
+
+	// simulate L1 fees spiking to 10x the estimate by dropping the estimate to a tenth
+	estimate, err := arbGasInfo.GetL1BaseFeeEstimate(&bind.CallOpts{Context: ctx})
+	Require(t, err)
+	tx, err = arbOwner.SetL1PricePerUnit(&auth, arbmath.BigDivByUint(estimate, 10))
+	Require(t, err)
+	receipt, err := builder.L2.EnsureTxSucceeded(tx)
+	Require(t, err)
+	spikeBlock := receipt.BlockNumber.Uint64()
+
+	rpcClient := builder.L2.ConsensusNode.Stack.Attach()
+	var adjusted []execution.L1PricingUpdateTrace
+	throttled := false
+	for i := 0; len(adjusted) < 4 || !throttled; i++ {
+		if i == 256 {
+			Fatal(t, "not enough throttled L1 pricing updates, got", len(adjusted), "adjustments, throttled:", throttled)
+		}
+		data := testhelpers.RandomSlice(200)
+		tx := builder.L2Info.PrepareTx("Owner", "Owner", 5_000_000, common.Big1, data)
+		Require(t, builder.L2Client().SendTransaction(ctx, tx))
+		_, err := builder.L2.EnsureTxSucceeded(tx)
+		Require(t, err)
+		builder.L1.TransferBalance(t, "Faucet", "Faucet", common.Big1, builder.L1Info)
+		head, err := builder.L2Client().BlockNumber(ctx)
+		Require(t, err)
+		var traces []execution.L1PricingUpdateTrace
+		err = rpcClient.CallContext(ctx, &traces, "arb_getL1PricingUpdateTrace", hexutil.Uint64(spikeBlock+1), hexutil.Uint64(head))
+		Require(t, err)
+		adjusted = adjusted[:0]
+		for _, trace := range traces {
+			if slices.Contains(trace.Rules, l1pricing.RulePriceAdjusted) {
+				adjusted = append(adjusted, trace)
+			}
+			throttled = throttled || slices.Contains(trace.Rules, l1pricing.RulePriceThrottled)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	for _, trace := range adjusted {
+		before, after := trace.PriceBefore.ToInt(), trace.PriceAfter.ToInt()
+		maxChange := arbmath.BigDivByUint(arbmath.BigMulByUint(before, throttlePercent), 100)
+		if arbmath.BigGreaterThan(arbmath.BigAbs(arbmath.BigSub(after, before)), maxChange) {
+			Fatal(t, "update in block", trace.BlockNumber, "moved the estimate", before, "->", after, "by more than", throttlePercent, "percent")
+		}
+	}
+}
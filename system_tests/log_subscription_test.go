@@ -25,11 +25,11 @@ func TestLogSubscription(t *testing.T) {
 	defer cleanup()
 
 	auth := builder.L2Info.GetDefaultTransactOpts("Owner", ctx)
-	arbSys, err := precompilesgen.NewArbSys(types.ArbSysAddress, builder.L2.Client)
+	arbSys, err := precompilesgen.NewArbSys(types.ArbSysAddress, builder.L2Client())
 	Require(t, err)
 
 	logChan := make(chan types.Log, 128)
-	subscription, err := builder.L2.Client.SubscribeFilterLogs(ctx, ethereum.FilterQuery{}, logChan)
+	subscription, err := builder.L2Client().SubscribeFilterLogs(ctx, ethereum.FilterQuery{}, logChan)
 	Require(t, err)
 	defer subscription.Unsubscribe()
 
@@ -54,6 +54,6 @@ func TestLogSubscription(t *testing.T) {
 	if !reflect.DeepEqual(receiptLog, subscriptionLog) {
 		Fatal(t, "Receipt log", receiptLog, "is different than subscription log", subscriptionLog)
 	}
-	_, err = builder.L2.Client.BlockByHash(ctx, subscriptionLog.BlockHash)
+	_, err = builder.L2Client().BlockByHash(ctx, subscriptionLog.BlockHash)
 	Require(t, err)
 }
@@ -24,7 +24,7 @@ func TestMeaninglessBatchReorg(t *testing.T) {
 	cleanup := builder.Build(t)
 	defer cleanup()
 
-	seqInbox, err := bridgegen.NewSequencerInbox(builder.L1Info.GetAddress("SequencerInbox"), builder.L1.Client)
+	seqInbox, err := bridgegen.NewSequencerInbox(builder.L1Info.GetAddress("SequencerInbox"), builder.L1Client())
 	Require(t, err)
 	seqOpts := builder.L1Info.GetDefaultTransactOpts("Sequencer", ctx)
 
@@ -101,7 +101,7 @@ func TestMeaninglessBatchReorg(t *testing.T) {
 	_, _, err = builder.L2.ConsensusNode.InboxReader.GetSequencerMessageBytes(ctx, 1)
 	Require(t, err)
 
-	l2Header, err := builder.L2.Client.HeaderByNumber(ctx, l2Receipt.BlockNumber)
+	l2Header, err := builder.L2Client().HeaderByNumber(ctx, l2Receipt.BlockNumber)
 	Require(t, err)
 
 	if l2Header.Hash() != l2Receipt.BlockHash {
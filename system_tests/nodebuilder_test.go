@@ -0,0 +1,69 @@
+// Copyright 2024, Offchain Labs, Inc.
+// For license information, see https://github.com/nitro/blob/master/LICENSE
+
+package arbtest
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/core/types"
+
+	"github.com/offchainlabs/nitro/arbos/arbosState"
+	"github.com/offchainlabs/nitro/arbos/l2pricing"
+	"github.com/offchainlabs/nitro/solgen/go/precompilesgen"
+	"github.com/offchainlabs/nitro/util/arbmath"
+)
+
+func TestNodeBuilderClientAccessors(t *testing.T) {
+	t.Parallel()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	builder := NewNodeBuilder(ctx).DefaultConfig(t, true)
+	if builder.L1Client() != nil || builder.L2Client() != nil {
+		Fatal(t, "expected no clients before the nodes are built")
+	}
+	cleanup := builder.Build(t)
+	defer cleanup()
+
+	if builder.L1Client() == nil || builder.L1Client() != builder.L1.Client {
+		Fatal(t, "L1Client returned", builder.L1Client(), "instead of the L1 node's client", builder.L1.Client)
+	}
+	if builder.L2Client() == nil || builder.L2Client() != builder.L2.Client {
+		Fatal(t, "L2Client returned", builder.L2Client(), "instead of the L2 node's client", builder.L2.Client)
+	}
+}
+
+func TestNodeBuilderReplicaSeesPrecompileStateChange(t *testing.T) {
+	t.Parallel()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	builder := NewNodeBuilder(ctx).DefaultConfig(t, false).WithReplica(nil)
+	cleanup := builder.Build(t)
+	defer cleanup()
+
+	arbOwner, err := precompilesgen.NewArbOwner(types.ArbOwnerAddress, builder.L2Client())
+	Require(t, err)
+	auth := builder.L2Info.GetDefaultTransactOpts("Owner", ctx)
+	baseFee := big.NewInt(l2pricing.InitialMinimumBaseFeeWei * 2)
+	tx, err := arbOwner.SetL2BaseFee(&auth, baseFee)
+	Require(t, err)
+	_, err = builder.L2Replica.EnsureTxSucceeded(tx)
+	Require(t, err)
+	builder.L2Replica.WaitForSequencerHead(t)
+
+	for _, node := range []*TestClient{builder.L2, builder.L2Replica.TestClient} {
+		statedb, err := node.ExecNode.Backend.ArbInterface().BlockChain().State()
+		Require(t, err)
+		state, err := arbosState.OpenSystemArbosState(statedb, nil, true)
+		Require(t, err)
+		stored, err := state.L2PricingState().BaseFeeWei()
+		Require(t, err)
+		if !arbmath.BigEquals(stored, baseFee) {
+			Fatal(t, "expected base fee", baseFee, "got", stored, "on replica", node == builder.L2Replica.TestClient)
+		}
+	}
+}
@@ -38,21 +38,21 @@ func TestFindBatch(t *testing.T) {
 
 	builder.BuildL1(t)
 
-	bridgeAddr, seqInbox, seqInboxAddr := setupSequencerInboxStub(ctx, t, builder.L1Info, builder.L1.Client, builder.chainConfig)
+	bridgeAddr, seqInbox, seqInboxAddr := setupSequencerInboxStub(ctx, t, builder.L1Info, builder.L1Client(), builder.chainConfig)
 	builder.addresses.Bridge = bridgeAddr
 	builder.addresses.SequencerInbox = seqInboxAddr
 
 	cleanup := builder.BuildL2OnL1(t)
 	defer cleanup()
 
-	nodeInterface, err := node_interfacegen.NewNodeInterface(types.NodeInterfaceAddress, builder.L2.Client)
+	nodeInterface, err := node_interfacegen.NewNodeInterface(types.NodeInterfaceAddress, builder.L2Client())
 	Require(t, err)
 	sequencerTxOpts := builder.L1Info.GetDefaultTransactOpts("sequencer", ctx)
 
 	builder.L2Info.GenerateAccount("Destination")
 	const numBatches = 3
 	for i := 0; i < numBatches; i++ {
-		makeBatch(t, builder.L2.ConsensusNode, builder.L2Info, builder.L1.Client, &sequencerTxOpts, seqInbox, seqInboxAddr, -1)
+		makeBatch(t, builder.L2.ConsensusNode, builder.L2Info, builder.L1Client(), &sequencerTxOpts, seqInbox, seqInboxAddr, -1)
 	}
 
 	for blockNum := uint64(0); blockNum < uint64(makeBatch_MsgsPerBatch)*3; blockNum++ {
@@ -68,15 +68,15 @@ func TestFindBatch(t *testing.T) {
 		}
 		batchL1Block, err := builder.L2.ConsensusNode.InboxTracker.GetBatchParentChainBlock(gotBatchNum)
 		Require(t, err)
-		blockHeader, err := builder.L2.Client.HeaderByNumber(ctx, new(big.Int).SetUint64(blockNum))
+		blockHeader, err := builder.L2Client().HeaderByNumber(ctx, new(big.Int).SetUint64(blockNum))
 		Require(t, err)
 		blockHash := blockHeader.Hash()
 
-		minCurrentL1Block, err := builder.L1.Client.BlockNumber(ctx)
+		minCurrentL1Block, err := builder.L1Client().BlockNumber(ctx)
 		Require(t, err)
 		gotConfirmations, err := nodeInterface.GetL1Confirmations(&callOpts, blockHash)
 		Require(t, err)
-		maxCurrentL1Block, err := builder.L1.Client.BlockNumber(ctx)
+		maxCurrentL1Block, err := builder.L1Client().BlockNumber(ctx)
 		Require(t, err)
 
 		if gotConfirmations > (maxCurrentL1Block-batchL1Block) || gotConfirmations < (minCurrentL1Block-batchL1Block) {
@@ -100,13 +100,13 @@ func TestL2BlockRangeForL1(t *testing.T) {
 		builder.L2.TransferBalanceTo(t, "Owner", util.RemapL1Address(user.From), big.NewInt(1e18), builder.L2Info)
 	}
 
-	nodeInterface, err := node_interfacegen.NewNodeInterface(types.NodeInterfaceAddress, builder.L2.Client)
+	nodeInterface, err := node_interfacegen.NewNodeInterface(types.NodeInterfaceAddress, builder.L2Client())
 	if err != nil {
 		t.Fatalf("Error creating node interface: %v", err)
 	}
 
 	l1BlockNums := map[uint64]*[2]uint64{}
-	latestL2, err := builder.L2.Client.BlockNumber(ctx)
+	latestL2, err := builder.L2Client().BlockNumber(ctx)
 	if err != nil {
 		t.Fatalf("Error querying most recent l2 block: %v", err)
 	}
@@ -154,10 +154,10 @@ func TestGetL1Confirmations(t *testing.T) {
 	cleanup := builder.Build(t)
 	defer cleanup()
 
-	nodeInterface, err := node_interfacegen.NewNodeInterface(types.NodeInterfaceAddress, builder.L2.Client)
+	nodeInterface, err := node_interfacegen.NewNodeInterface(types.NodeInterfaceAddress, builder.L2Client())
 	Require(t, err)
 
-	genesisBlock, err := builder.L2.Client.BlockByNumber(ctx, big.NewInt(0))
+	genesisBlock, err := builder.L2Client().BlockByNumber(ctx, big.NewInt(0))
 	Require(t, err)
 	l1Confs, err := nodeInterface.GetL1Confirmations(&bind.CallOpts{}, genesisBlock.Hash())
 	Require(t, err)
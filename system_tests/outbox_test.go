@@ -4,6 +4,7 @@
 package arbtest
 
 import (
+	"bytes"
 	"context"
 	"encoding/hex"
 	"math/big"
@@ -14,11 +15,13 @@ import (
 	"github.com/ethereum/go-ethereum"
 	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/core/vm"
 	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/params"
 
+	"github.com/offchainlabs/nitro/arbos/merkleAccumulator"
 	"github.com/offchainlabs/nitro/gethhook"
 	"github.com/offchainlabs/nitro/solgen/go/node_interfacegen"
 	"github.com/offchainlabs/nitro/solgen/go/precompilesgen"
@@ -68,9 +71,9 @@ func TestOutboxProofs(t *testing.T) {
 
 	auth := builder.L2Info.GetDefaultTransactOpts("Owner", ctx)
 
-	arbSys, err := precompilesgen.NewArbSys(types.ArbSysAddress, builder.L2.Client)
+	arbSys, err := precompilesgen.NewArbSys(types.ArbSysAddress, builder.L2Client())
 	Require(t, err)
-	nodeInterface, err := node_interfacegen.NewNodeInterface(types.NodeInterfaceAddress, builder.L2.Client)
+	nodeInterface, err := node_interfacegen.NewNodeInterface(types.NodeInterfaceAddress, builder.L2Client())
 	Require(t, err)
 
 	txnCount := int64(1 + rand.Intn(16))
@@ -99,7 +102,7 @@ func TestOutboxProofs(t *testing.T) {
 		txns = append(txns, tx.Hash())
 
 		time.Sleep(4 * time.Millisecond) // Geth takes a few ms for the receipt to show up
-		_, err = builder.L2.Client.TransactionReceipt(ctx, tx.Hash())
+		_, err = builder.L2Client().TransactionReceipt(ctx, tx.Hash())
 		if err == nil {
 			merkleState, err := arbSys.SendMerkleTreeState(&bind.CallOpts{})
 			Require(t, err, "could not get merkle root")
@@ -114,7 +117,7 @@ func TestOutboxProofs(t *testing.T) {
 
 	for _, tx := range txns {
 		var receipt *types.Receipt
-		receipt, err = builder.L2.Client.TransactionReceipt(ctx, tx)
+		receipt, err = builder.L2Client().TransactionReceipt(ctx, tx)
 		Require(t, err, "No receipt for txn")
 
 		if receipt.Status != types.ReceiptStatusSuccessful {
@@ -218,7 +221,7 @@ func TestOutboxProofs(t *testing.T) {
 			// in one lookup, query geth for all the data we need to construct a proof
 			var logs []types.Log
 			if len(query) > 0 {
-				logs, err = builder.L2.Client.FilterLogs(ctx, ethereum.FilterQuery{
+				logs, err = builder.L2Client().FilterLogs(ctx, ethereum.FilterQuery{
 					Addresses: []common.Address{
 						types.ArbSysAddress,
 					},
@@ -384,3 +387,186 @@ func TestOutboxProofs(t *testing.T) {
 		}
 	}
 }
+
+func TestSendTxToL1WithProofInfo(t *testing.T) {
+	t.Parallel()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	builder := NewNodeBuilder(ctx).DefaultConfig(t, false)
+	cleanup := builder.Build(t)
+	defer cleanup()
+
+	arbSysAbi, err := precompilesgen.ArbSysMetaData.GetAbi()
+	Require(t, err)
+	arbSys, err := precompilesgen.NewArbSys(types.ArbSysAddress, builder.L2Client())
+	Require(t, err)
+	auth := builder.L2Info.GetDefaultTransactOpts("Owner", ctx)
+
+	for i := int64(0); i < 5; i++ {
+		auth.Value = big.NewInt(i * 1000000000)
+		tx, err := arbSys.SendTxToL1WithProofInfo(&auth, common.Address{}, []byte{byte(i)})
+		Require(t, err)
+		receipt, err := builder.L2.EnsureTxSucceeded(tx)
+		Require(t, err)
+
+		// read the method's return value from the trace
+		var trace struct {
+			Output hexutil.Bytes `json:"output"`
+		}
+		traceConfig := map[string]interface{}{"tracer": "callTracer"}
+		err = builder.L2Client().Client().CallContext(ctx, &trace, "debug_traceTransaction", tx.Hash(), traceConfig)
+		Require(t, err)
+		returned, err := arbSysAbi.Methods["sendTxToL1WithProofInfo"].Outputs.Unpack(trace.Output)
+		Require(t, err)
+		leafNum, _ := returned[0].(*big.Int)
+		size, _ := returned[1].(*big.Int)
+		partials, _ := returned[2].([][32]byte)
+
+		var position *big.Int
+		for _, log := range receipt.Logs {
+			if log.Topics[0] == arbSysAbi.Events["L2ToL1Tx"].ID {
+				parsedLog, err := arbSys.ParseL2ToL1Tx(*log)
+				Require(t, err)
+				position = parsedLog.Position
+			}
+		}
+		if position == nil || leafNum == nil || leafNum.Cmp(position) != 0 {
+			Fatal(t, "returned leaf", leafNum, "doesn't match the L2ToL1Tx position", position)
+		}
+
+		merkleState, err := arbSys.SendMerkleTreeState(&bind.CallOpts{Context: ctx, BlockNumber: receipt.BlockNumber})
+		Require(t, err)
+		if size == nil || size.Cmp(merkleState.Size) != 0 || size.Uint64() != leafNum.Uint64()+1 {
+			Fatal(t, "returned size", size, "doesn't match the tree size", merkleState.Size)
+		}
+		if len(partials) != len(merkleState.Partials) {
+			Fatal(t, "returned", len(partials), "partials but the tree has", len(merkleState.Partials))
+		}
+		for j := range partials {
+			if partials[j] != merkleState.Partials[j] {
+				Fatal(t, "partial", j, "is", common.Hash(partials[j]), "expected", common.Hash(merkleState.Partials[j]))
+			}
+		}
+	}
+}
+
+func TestWithdrawEthToContract(t *testing.T) {
+	t.Parallel()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	builder := NewNodeBuilder(ctx).DefaultConfig(t, false)
+	cleanup := builder.Build(t)
+	defer cleanup()
+
+	arbSys, err := precompilesgen.NewArbSys(types.ArbSysAddress, builder.L2Client())
+	Require(t, err)
+	auth := builder.L2Info.GetDefaultTransactOpts("Owner", ctx)
+	destination := common.HexToAddress("0x1234")
+
+	withdraw := func(data []byte) uint64 {
+		t.Helper()
+		value := big.NewInt(1e12)
+		auth.Value = value
+		tx, err := arbSys.WithdrawEthToContract(&auth, destination, data)
+		Require(t, err)
+		receipt, err := builder.L2.EnsureTxSucceeded(tx)
+		Require(t, err)
+		header, err := builder.L2Client().HeaderByHash(ctx, receipt.BlockHash)
+		Require(t, err)
+		parentBlock := new(big.Int).Sub(receipt.BlockNumber, common.Big1)
+		stateBefore, err := arbSys.SendMerkleTreeState(&bind.CallOpts{Context: ctx, BlockNumber: parentBlock})
+		Require(t, err)
+		stateAfter, err := arbSys.SendMerkleTreeState(&bind.CallOpts{Context: ctx, BlockNumber: receipt.BlockNumber})
+		Require(t, err)
+		leaf := stateBefore.Size.Uint64()
+
+		var event *precompilesgen.ArbSysL2ToL1Tx
+		for _, log := range receipt.Logs {
+			if parsed, err := arbSys.ParseL2ToL1Tx(*log); err == nil {
+				event = parsed
+			}
+		}
+		if event == nil {
+			Fatal(t, "withdrawal didn't emit an L2ToL1Tx event")
+		}
+		if !bytes.Equal(event.Data, data) {
+			Fatal(t, "sent", len(data), "bytes of data, event has", len(event.Data), "bytes", event.Data)
+		}
+		if event.Caller != auth.From || event.Destination != destination || !arbmath.BigEquals(event.Callvalue, value) {
+			Fatal(t, "expected", value, "from", auth.From, "to", destination, "got", event.Callvalue, "from", event.Caller, "to", event.Destination)
+		}
+		if event.Position.Uint64() != leaf {
+			Fatal(t, "expected outbox position", leaf, "got", event.Position)
+		}
+		packed := crypto.Keccak256Hash(
+			auth.From.Bytes(),
+			destination.Bytes(),
+			arbmath.U256Bytes(event.ArbBlockNum),
+			arbmath.U256Bytes(event.EthBlockNum),
+			arbmath.U256Bytes(new(big.Int).SetUint64(header.Time)),
+			common.BigToHash(value).Bytes(),
+			data,
+		)
+		if common.BigToHash(event.Hash) != packed {
+			Fatal(t, "expected send hash", packed, "got", common.BigToHash(event.Hash))
+		}
+
+		// the tree should be exactly the one before with the packed message appended
+		partials := make([]*common.Hash, len(stateBefore.Partials))
+		for i := range stateBefore.Partials {
+			partial := common.Hash(stateBefore.Partials[i])
+			partials[i] = &partial
+		}
+		expectedTree, err := merkleAccumulator.NewNonpersistentMerkleAccumulatorFromPartials(partials)
+		Require(t, err)
+		_, err = expectedTree.Append(packed)
+		Require(t, err)
+		expectedRoot, err := expectedTree.Root()
+		Require(t, err)
+		if stateAfter.Size.Uint64() != leaf+1 || common.Hash(stateAfter.Root) != expectedRoot {
+			Fatal(t, "expected the send merkle tree to grow to", leaf+1, "with root", expectedRoot, "got", stateAfter.Size, common.Hash(stateAfter.Root))
+		}
+		return receipt.GasUsed - receipt.GasUsedForL1
+	}
+
+	shortGas := withdraw([]byte("call me on L1"))
+	longGas := withdraw(bytes.Repeat([]byte("call me on L1 with a lot more calldata"), 64))
+	if longGas <= shortGas {
+		Fatal(t, "expected more calldata to cost more gas, got", shortGas, "then", longGas)
+	}
+}
+
+func TestGetL2ToL1MerkleTreeSize(t *testing.T) {
+	t.Parallel()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	builder := NewNodeBuilder(ctx).DefaultConfig(t, false)
+	cleanup := builder.Build(t)
+	defer cleanup()
+
+	arbSys, err := precompilesgen.NewArbSys(types.ArbSysAddress, builder.L2Client())
+	Require(t, err)
+	auth := builder.L2Info.GetDefaultTransactOpts("Owner", ctx)
+	callOpts := &bind.CallOpts{Context: ctx}
+
+	size, err := arbSys.GetL2ToL1MerkleTreeSize(callOpts)
+	Require(t, err)
+	if size != 0 {
+		Fatal(t, "expected an empty outbox tree, got size", size)
+	}
+	for i := uint64(1); i <= 3; i++ {
+		auth.Value = big.NewInt(1e12)
+		tx, err := arbSys.WithdrawEth(&auth, common.HexToAddress("0x1234"))
+		Require(t, err)
+		_, err = builder.L2.EnsureTxSucceeded(tx)
+		Require(t, err)
+		size, err := arbSys.GetL2ToL1MerkleTreeSize(callOpts)
+		Require(t, err)
+		if size != i {
+			Fatal(t, "expected the outbox tree size to be", i, "after", i, "withdrawals, got", size)
+		}
+	}
+}
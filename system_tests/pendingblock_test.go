@@ -22,7 +22,7 @@ func TestPendingBlockTimeAndNumberAdvance(t *testing.T) {
 
 	auth := builder.L2Info.GetDefaultTransactOpts("Faucet", ctx)
 
-	_, _, testTimeAndNr, err := mocksgen.DeployPendingBlkTimeAndNrAdvanceCheck(&auth, builder.L2.Client)
+	_, _, testTimeAndNr, err := mocksgen.DeployPendingBlkTimeAndNrAdvanceCheck(&auth, builder.L2Client())
 	Require(t, err)
 
 	time.Sleep(1 * time.Second)
@@ -42,10 +42,10 @@ func TestPendingBlockArbBlockHashReturnsLatest(t *testing.T) {
 
 	auth := builder.L2Info.GetDefaultTransactOpts("Faucet", ctx)
 
-	_, _, pendingBlk, err := mocksgen.DeployPendingBlkTimeAndNrAdvanceCheck(&auth, builder.L2.Client)
+	_, _, pendingBlk, err := mocksgen.DeployPendingBlkTimeAndNrAdvanceCheck(&auth, builder.L2Client())
 	Require(t, err)
 
-	header, err := builder.L2.Client.HeaderByNumber(ctx, nil)
+	header, err := builder.L2Client().HeaderByNumber(ctx, nil)
 	Require(t, err)
 
 	_, err = pendingBlk.CheckArbBlockHashReturnsLatest(&auth, header.Hash())
@@ -13,8 +13,10 @@ import (
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/params"
 
 	"github.com/offchainlabs/nitro/arbos/l1pricing"
+	"github.com/offchainlabs/nitro/arbos/l2pricing"
 	"github.com/offchainlabs/nitro/cmd/chaininfo"
 	"github.com/offchainlabs/nitro/solgen/go/precompilesgen"
 )
@@ -36,7 +38,7 @@ func TestArbAddressTableDoesntRevert(t *testing.T) {
 	auth := builder.L2Info.GetDefaultTransactOpts("Owner", ctx)
 	callOpts := &bind.CallOpts{Context: ctx}
 
-	arbAddressTable, err := precompilesgen.NewArbAddressTable(types.ArbAddressTableAddress, builder.L2.Client)
+	arbAddressTable, err := precompilesgen.NewArbAddressTable(types.ArbAddressTableAddress, builder.L2Client())
 	Require(t, err)
 
 	addr := common.BytesToAddress(crypto.Keccak256([]byte{})[:20])
@@ -75,6 +77,49 @@ func TestArbAddressTableDoesntRevert(t *testing.T) {
 	res := []uint8{128}
 	_, _, err = arbAddressTable.Decompress(callOpts, res, big.NewInt(0))
 	Require(t, err)
+
+	var addrs []common.Address
+	for i := byte(0); i < 50; i++ {
+		addrs = append(addrs, common.BytesToAddress(crypto.Keccak256([]byte{i})[:20]))
+	}
+	tx, err = arbAddressTable.RegisterMany(&auth, addrs)
+	Require(t, err)
+	_, err = builder.L2.EnsureTxSucceeded(tx)
+	Require(t, err)
+
+	// already registered addresses are skipped
+	tx, err = arbAddressTable.RegisterMany(&auth, []common.Address{addr, addrs[0]})
+	Require(t, err)
+	_, err = builder.L2.EnsureTxSucceeded(tx)
+	Require(t, err)
+
+	size, err = arbAddressTable.Size(callOpts)
+	Require(t, err)
+	if size.Cmp(big.NewInt(int64(len(addrs)+1))) != 0 {
+		Fatal(t, "expected size to be", len(addrs)+1, "got", size)
+	}
+
+	unregistered := common.BytesToAddress(crypto.Keccak256([]byte("unregistered"))[:20])
+	looked, err := arbAddressTable.LookupMany(callOpts, append(addrs, unregistered))
+	Require(t, err)
+	if len(looked.Indices) != len(addrs)+1 || len(looked.Exists) != len(addrs)+1 {
+		Fatal(t, "expected", len(addrs)+1, "lookups, got", len(looked.Indices), len(looked.Exists))
+	}
+	for i, addr := range addrs {
+		if !looked.Exists[i] || looked.Indices[i].Cmp(big.NewInt(int64(i+1))) != 0 {
+			Fatal(t, "expected", addr, "at index", i+1, "got", looked.Indices[i], looked.Exists[i])
+		}
+		idx, err := arbAddressTable.Lookup(callOpts, addr)
+		Require(t, err)
+		retrievedAddr, err := arbAddressTable.LookupIndex(callOpts, idx)
+		Require(t, err)
+		if retrievedAddr != addr {
+			Fatal(t, "expected retrieved address to be", addr, "got", retrievedAddr)
+		}
+	}
+	if looked.Exists[len(addrs)] {
+		Fatal(t, "expected", unregistered, "to not exist")
+	}
 }
 
 func TestArbAggregatorDoesntRevert(t *testing.T) {
@@ -90,7 +135,7 @@ func TestArbAggregatorDoesntRevert(t *testing.T) {
 	auth := builder.L2Info.GetDefaultTransactOpts("Owner", ctx)
 	callOpts := &bind.CallOpts{Context: ctx}
 
-	arbAggregator, err := precompilesgen.NewArbAggregator(types.ArbAggregatorAddress, builder.L2.Client)
+	arbAggregator, err := precompilesgen.NewArbAggregator(types.ArbAggregatorAddress, builder.L2Client())
 	Require(t, err)
 
 	tx, err := arbAggregator.SetFeeCollector(&auth, l1pricing.BatchPosterAddress, common.Address{})
@@ -114,7 +159,7 @@ func TestArbosTestDoesntRevert(t *testing.T) {
 
 	callOpts := &bind.CallOpts{Context: ctx}
 
-	arbosTest, err := precompilesgen.NewArbosTest(types.ArbosTestAddress, builder.L2.Client)
+	arbosTest, err := precompilesgen.NewArbosTest(types.ArbosTestAddress, builder.L2Client())
 	Require(t, err)
 
 	err = arbosTest.BurnArbGas(callOpts, big.NewInt(1))
@@ -133,7 +178,7 @@ func TestArbSysDoesntRevert(t *testing.T) {
 
 	callOpts := &bind.CallOpts{Context: ctx}
 
-	arbSys, err := precompilesgen.NewArbSys(types.ArbSysAddress, builder.L2.Client)
+	arbSys, err := precompilesgen.NewArbSys(types.ArbSysAddress, builder.L2Client())
 	Require(t, err)
 
 	addr1 := common.BytesToAddress(crypto.Keccak256([]byte{})[:20])
@@ -154,11 +199,11 @@ func TestArbOwnerDoesntRevert(t *testing.T) {
 
 	auth := builder.L2Info.GetDefaultTransactOpts("Owner", ctx)
 
-	arbOwner, err := precompilesgen.NewArbOwner(types.ArbOwnerAddress, builder.L2.Client)
+	arbOwner, err := precompilesgen.NewArbOwner(types.ArbOwnerAddress, builder.L2Client())
 	Require(t, err)
 
 	chainConfig := chaininfo.ArbitrumDevTestChainConfig()
-	chainConfig.ArbitrumChainParams.MaxCodeSize = 100
+	chainConfig.ArbitrumChainParams.MaxCodeSize = params.DefaultMaxCodeSize * 2
 	serializedChainConfig, err := json.Marshal(chainConfig)
 	Require(t, err)
 	tx, err := arbOwner.SetChainConfig(&auth, string(serializedChainConfig))
@@ -176,7 +221,7 @@ func TestArbOwnerDoesntRevert(t *testing.T) {
 	_, err = builder.L2.EnsureTxSucceeded(tx)
 	Require(t, err)
 
-	tx, err = arbOwner.SetL2BaseFee(&auth, big.NewInt(1))
+	tx, err = arbOwner.SetL2BaseFee(&auth, big.NewInt(l2pricing.InitialMinimumBaseFeeWei))
 	Require(t, err)
 	_, err = builder.L2.EnsureTxSucceeded(tx)
 	Require(t, err)
@@ -195,7 +240,7 @@ func TestArbGasInfoDoesntRevert(t *testing.T) {
 	callOpts := &bind.CallOpts{Context: ctx}
 	addr := common.BytesToAddress(crypto.Keccak256([]byte{})[:20])
 
-	arbGasInfo, err := precompilesgen.NewArbGasInfo(types.ArbGasInfoAddress, builder.L2.Client)
+	arbGasInfo, err := precompilesgen.NewArbGasInfo(types.ArbGasInfoAddress, builder.L2Client())
 	Require(t, err)
 
 	_, err = arbGasInfo.GetGasBacklog(callOpts)
@@ -241,7 +286,7 @@ func TestArbRetryableTxDoesntRevert(t *testing.T) {
 
 	callOpts := &bind.CallOpts{Context: ctx}
 
-	arbRetryableTx, err := precompilesgen.NewArbRetryableTx(common.HexToAddress("6e"), builder.L2.Client)
+	arbRetryableTx, err := precompilesgen.NewArbRetryableTx(common.HexToAddress("6e"), builder.L2Client())
 	Require(t, err)
 
 	_, err = arbRetryableTx.GetCurrentRedeemer(callOpts)
@@ -4,38 +4,63 @@
 package arbtest
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"math/big"
+	"reflect"
+	"slices"
 	"sort"
+	"strings"
+	"sync"
 	"testing"
 
+	"github.com/ethereum/go-ethereum"
 	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/txpool"
 	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
 	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/log"
 	"github.com/ethereum/go-ethereum/params"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/ethereum/go-ethereum/rpc"
 
+	"github.com/offchainlabs/nitro/arbnode/dataposter/externalsignertest"
 	"github.com/offchainlabs/nitro/arbos"
+	"github.com/offchainlabs/nitro/arbos/arbosState"
 	"github.com/offchainlabs/nitro/arbos/l1pricing"
+	"github.com/offchainlabs/nitro/arbos/l2pricing"
+	"github.com/offchainlabs/nitro/arbos/storage"
+	"github.com/offchainlabs/nitro/arbos/tipdistribution"
+	"github.com/offchainlabs/nitro/arbos/util"
 	"github.com/offchainlabs/nitro/cmd/chaininfo"
+	"github.com/offchainlabs/nitro/execution/gethexec"
 	"github.com/offchainlabs/nitro/solgen/go/mocksgen"
 	"github.com/offchainlabs/nitro/solgen/go/precompilesgen"
 	"github.com/offchainlabs/nitro/util/arbmath"
+	"github.com/offchainlabs/nitro/util/testhelpers"
 )
 
 func TestPurePrecompileMethodCalls(t *testing.T) {
+	testPurePrecompileMethodCalls(t, params.ArbosVersion_31)
+}
+
+func testPurePrecompileMethodCalls(t *testing.T, arbosVersion uint64) {
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 
-	arbosVersion := params.ArbosVersion_31
 	builder := NewNodeBuilder(ctx).
 		DefaultConfig(t, false).
 		WithArbOSVersion(arbosVersion)
 	cleanup := builder.Build(t)
 	defer cleanup()
 
-	arbSys, err := precompilesgen.NewArbSys(common.HexToAddress("0x64"), builder.L2.Client)
+	arbSys, err := precompilesgen.NewArbSys(common.HexToAddress("0x64"), builder.L2Client())
 	Require(t, err, "could not deploy ArbSys contract")
 	chainId, err := arbSys.ArbChainID(&bind.CallOpts{})
 	Require(t, err, "failed to get the ChainID")
@@ -43,13 +68,6 @@ func TestPurePrecompileMethodCalls(t *testing.T) {
 		Fatal(t, "Wrong ChainID", chainId.Uint64())
 	}
 
-	expectedArbosVersion := 55 + arbosVersion // Nitro versions start at 56
-	arbSysArbosVersion, err := arbSys.ArbOSVersion(&bind.CallOpts{})
-	Require(t, err)
-	if arbSysArbosVersion.Uint64() != expectedArbosVersion {
-		Fatal(t, "Expected ArbOS version", expectedArbosVersion, "got", arbSysArbosVersion)
-	}
-
 	storageGasAvailable, err := arbSys.GetStorageGasAvailable(&bind.CallOpts{})
 	Require(t, err)
 	if storageGasAvailable.Cmp(big.NewInt(0)) != 0 {
@@ -65,7 +83,7 @@ func TestViewLogReverts(t *testing.T) {
 	cleanup := builder.Build(t)
 	defer cleanup()
 
-	arbDebug, err := precompilesgen.NewArbDebug(common.HexToAddress("0xff"), builder.L2.Client)
+	arbDebug, err := precompilesgen.NewArbDebug(common.HexToAddress("0xff"), builder.L2Client())
 	Require(t, err, "could not deploy ArbSys contract")
 
 	err = arbDebug.EventsView(nil)
@@ -84,7 +102,7 @@ func TestArbDebugPanic(t *testing.T) {
 
 	auth := builder.L2Info.GetDefaultTransactOpts("Owner", ctx)
 
-	arbDebug, err := precompilesgen.NewArbDebug(types.ArbDebugAddress, builder.L2.Client)
+	arbDebug, err := precompilesgen.NewArbDebug(types.ArbDebugAddress, builder.L2Client())
 	Require(t, err)
 
 	_, err = arbDebug.Panic(&auth)
@@ -106,7 +124,7 @@ func TestArbDebugLegacyError(t *testing.T) {
 
 	callOpts := &bind.CallOpts{Context: ctx}
 
-	arbDebug, err := precompilesgen.NewArbDebug(common.HexToAddress("0xff"), builder.L2.Client)
+	arbDebug, err := precompilesgen.NewArbDebug(common.HexToAddress("0xff"), builder.L2Client())
 	Require(t, err)
 
 	err = arbDebug.LegacyError(callOpts)
@@ -115,7 +133,7 @@ func TestArbDebugLegacyError(t *testing.T) {
 	}
 }
 
-func TestCustomSolidityErrors(t *testing.T) {
+func TestArbDebugEmitLogs(t *testing.T) {
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 
@@ -123,43 +141,240 @@ func TestCustomSolidityErrors(t *testing.T) {
 	cleanup := builder.Build(t)
 	defer cleanup()
 
+	auth := builder.L2Info.GetDefaultTransactOpts("Owner", ctx)
+	arbDebug, err := precompilesgen.NewArbDebug(types.ArbDebugAddress, builder.L2Client())
+	Require(t, err)
+
+	const count = 5000
+	const dataSize = 40
+	tx, err := arbDebug.EmitLogs(&auth, count, dataSize, 1)
+	Require(t, err)
+	receipt, err := builder.L2.EnsureTxSucceeded(tx)
+	Require(t, err)
+	if len(receipt.Logs) != count {
+		Fatal(t, "expected", count, "logs in the receipt, got", len(receipt.Logs))
+	}
+	topicOf := func(i uint64) common.Hash {
+		return common.BigToHash(new(big.Int).SetUint64(i))
+	}
+	for i, log := range receipt.Logs {
+		// #nosec G115
+		index := uint64(i)
+		if log.Address != types.ArbDebugAddress || len(log.Topics) != 1 || log.Topics[0] != topicOf(index) {
+			Fatal(t, "log", i, "has unexpected address or topics", log.Address, log.Topics)
+		}
+		if len(log.Data) != dataSize || log.Data[0] != byte(index) || log.Data[dataSize-1] != byte(index+dataSize-1) {
+			Fatal(t, "log", i, "has unexpected data", log.Data)
+		}
+	}
+
+	// page through the block's logs by topic
+	const pageSize = 1000
+	for page := uint64(0); page < count/pageSize; page++ {
+		topics := make([]common.Hash, 0, pageSize)
+		for i := page * pageSize; i < (page+1)*pageSize; i++ {
+			topics = append(topics, topicOf(i))
+		}
+		logs, err := builder.L2Client().FilterLogs(ctx, ethereum.FilterQuery{
+			BlockHash: &receipt.BlockHash,
+			Addresses: []common.Address{types.ArbDebugAddress},
+			Topics:    [][]common.Hash{topics},
+		})
+		Require(t, err)
+		if len(logs) != pageSize {
+			Fatal(t, "expected", pageSize, "logs in page", page, "got", len(logs))
+		}
+		for i, log := range logs {
+			// #nosec G115
+			if log.Topics[0] != topicOf(page*pageSize+uint64(i)) || log.TxHash != tx.Hash() {
+				Fatal(t, "log", i, "of page", page, "is out of order or from another tx")
+			}
+		}
+	}
+
+	if _, err := arbDebug.EmitLogs(&auth, 1, 0, 5); err == nil {
+		Fatal(t, "expected logs with more than 4 topics to be rejected")
+	}
+}
+
+func TestCustomSolidityErrors(t *testing.T) {
+	testCustomSolidityErrors(t, chaininfo.ArbitrumDevTestChainConfig().ArbitrumChainParams.InitialArbOSVersion)
+}
+
+func testCustomSolidityErrors(t *testing.T, arbosVersion uint64) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	builder := NewNodeBuilder(ctx).
+		DefaultConfig(t, false).
+		WithArbOSVersion(arbosVersion)
+	cleanup := builder.Build(t)
+	defer cleanup()
+
 	callOpts := &bind.CallOpts{Context: ctx}
 	auth := builder.L2Info.GetDefaultTransactOpts("Owner", ctx)
 
+	// ensure decodes the revert data of customError with the contract's ABI and
+	// checks it's the named error with the expected arguments.
 	ensure := func(
 		customError error,
-		expectedError string,
+		metaData *bind.MetaData,
+		errorName string,
+		expectedArgs []interface{},
 		scenario string,
 	) {
 		if customError == nil {
 			Fatal(t, "should have errored", "scenario", scenario)
 		}
-		observedMessage := customError.Error()
-		// The first error is server side. The second error is client side ABI decoding.
-		expectedMessage := fmt.Sprintf("execution reverted: error %v: %v", expectedError, expectedError)
-		if observedMessage != expectedMessage {
-			Fatal(t, observedMessage, "scenario", scenario)
+		var dataError rpc.DataError
+		if !errors.As(customError, &dataError) {
+			Fatal(t, "error has no revert data", customError, "scenario", scenario)
+		}
+		revertHex, ok := dataError.ErrorData().(string)
+		if !ok {
+			Fatal(t, "unexpected revert data", dataError.ErrorData(), "scenario", scenario)
+		}
+		revertData, err := hexutil.Decode(revertHex)
+		Require(t, err, "scenario", scenario)
+
+		contractAbi, err := metaData.GetAbi()
+		Require(t, err, "scenario", scenario)
+		abiError, ok := contractAbi.Errors[errorName]
+		if !ok {
+			Fatal(t, "no error named", errorName, "in ABI", "scenario", scenario)
+		}
+		if len(revertData) < 4 || !bytes.Equal(revertData[:4], abiError.ID[:4]) {
+			Fatal(t, "revert data", revertHex, "isn't a", errorName, "error", "scenario", scenario)
+		}
+		args, err := abiError.Inputs.Unpack(revertData[4:])
+		Require(t, err, "scenario", scenario)
+		if len(args) != len(expectedArgs) {
+			Fatal(t, "expected", len(expectedArgs), "error arguments, got", args, "scenario", scenario)
+		}
+		for i, arg := range args {
+			name := abiError.Inputs[i].Name
+			if expected, isBig := expectedArgs[i].(*big.Int); isBig {
+				if actual, ok := arg.(*big.Int); !ok || actual.Cmp(expected) != 0 {
+					Fatal(t, "error argument", name, "is", arg, "expected", expected, "scenario", scenario)
+				}
+			} else if !reflect.DeepEqual(arg, expectedArgs[i]) {
+				Fatal(t, "error argument", name, "is", arg, "expected", expectedArgs[i], "scenario", scenario)
+			}
 		}
 	}
 
-	arbDebug, err := precompilesgen.NewArbDebug(types.ArbDebugAddress, builder.L2.Client)
+	arbDebug, err := precompilesgen.NewArbDebug(types.ArbDebugAddress, builder.L2Client())
 	Require(t, err, "could not bind ArbDebug contract")
+	customArgs := []interface{}{uint64(1024), "This spider family wards off bugs: /\\oo/\\ //\\(oo)//\\ /\\oo/\\", true}
 	ensure(
 		arbDebug.CustomRevert(callOpts, 1024),
-		"Custom(1024, This spider family wards off bugs: /\\oo/\\ //\\(oo)//\\ /\\oo/\\, true)",
+		precompilesgen.ArbDebugMetaData,
+		"Custom",
+		customArgs,
 		"arbDebug.CustomRevert",
 	)
 
-	arbSys, err := precompilesgen.NewArbSys(arbos.ArbSysAddress, builder.L2.Client)
+	arbSys, err := precompilesgen.NewArbSys(arbos.ArbSysAddress, builder.L2Client())
 	Require(t, err, "could not bind ArbSys contract")
+	currentBlock, err := builder.L2Client().BlockNumber(ctx)
+	Require(t, err)
 	_, customError := arbSys.ArbBlockHash(callOpts, big.NewInt(1e9))
 	ensure(
 		customError,
-		"InvalidBlockNumber(1000000000, 1)",
+		precompilesgen.ArbSysMetaData,
+		"InvalidBlockNumber",
+		[]interface{}{big.NewInt(1e9), new(big.Int).SetUint64(currentBlock)},
 		"arbSys.ArbBlockHash",
 	)
 
-	arbRetryableTx, err := precompilesgen.NewArbRetryableTx(types.ArbRetryableTxAddress, builder.L2.Client)
+	// gas estimation reports a precompile's revert data just like a call does
+	arbDebugABI, err := precompilesgen.ArbDebugMetaData.GetAbi()
+	Require(t, err)
+	customRevertData, err := arbDebugABI.Pack("customRevert", uint64(1024))
+	Require(t, err)
+	arbDebugAddress := types.ArbDebugAddress
+	customRevertMsg := ethereum.CallMsg{From: auth.From, To: &arbDebugAddress, Data: customRevertData}
+	_, customError = builder.L2Client().EstimateGas(ctx, customRevertMsg)
+	ensure(customError, precompilesgen.ArbDebugMetaData, "Custom", customArgs, "eth_estimateGas arbDebug.CustomRevert")
+
+	// receipts don't carry revert data, but replaying a failed transaction on the state before its block recovers it
+	tx := builder.L2Info.PrepareTxTo("Owner", &arbDebugAddress, 500_000, nil, customRevertData)
+	Require(t, builder.L2Client().SendTransaction(ctx, tx))
+	receipt := EnsureTxFailed(t, ctx, builder.L2Client(), tx)
+	customRevertMsg.Gas = tx.Gas()
+	_, customError = builder.L2Client().CallContract(ctx, customRevertMsg, new(big.Int).Sub(receipt.BlockNumber, common.Big1))
+	ensure(customError, precompilesgen.ArbDebugMetaData, "Custom", customArgs, "replayed arbDebug.CustomRevert transaction")
+
+	// no addresses are registered, so every index is missing from the table
+	arbAddressTable, err := precompilesgen.NewArbAddressTable(types.ArbAddressTableAddress, builder.L2Client())
+	Require(t, err)
+	hugeOffset := new(big.Int).Lsh(common.Big1, 64)
+	for _, invalid := range []struct {
+		buf    []byte
+		offset *big.Int
+		reason string
+	}{
+		{[]byte{1, 2}, big.NewInt(3), "offset out of range"},
+		{[]byte{1, 2}, hugeOffset, "offset out of range"},
+		{[]byte{}, big.NewInt(0), "invalid encoding"},
+		{[]byte{0xaa, 0x94, 0x01}, big.NewInt(1), "invalid encoding"}, // truncated address
+		{[]byte{0x81, 0x05}, big.NewInt(0), "invalid encoding"},       // non-canonical index
+		{[]byte{0x95}, big.NewInt(0), "invalid encoding"},             // longer than an address
+		{[]byte{0xaa, 0x05}, big.NewInt(1), "index not in table"},
+	} {
+		scenario := fmt.Sprintf("arbAddressTable.Decompress(%x, %v)", invalid.buf, invalid.offset)
+		_, _, customError = arbAddressTable.Decompress(callOpts, invalid.buf, invalid.offset)
+		if arbosVersion < util.ArbosVersion_40 {
+			if customError == nil {
+				Fatal(t, "should have errored", "scenario", scenario)
+			}
+			continue
+		}
+		ensure(
+			customError,
+			precompilesgen.ArbAddressTableMetaData,
+			"DecompressionFailed",
+			[]interface{}{invalid.offset, invalid.reason},
+			scenario,
+		)
+	}
+
+	if arbosVersion >= util.ArbosVersion_40 {
+		// a multicall reverts with the solidity error of the call that failed
+		arbOwner, err := precompilesgen.NewArbOwner(types.ArbOwnerAddress, builder.L2Client())
+		Require(t, err)
+		arbOwnerABI, err := precompilesgen.ArbOwnerMetaData.GetAbi()
+		Require(t, err)
+		scheduleUpgrade := func(version uint64) []byte {
+			data, err := arbOwnerABI.Pack("scheduleArbOSUpgrade", version, uint64(1)<<62)
+			Require(t, err)
+			return data
+		}
+		_, customError = arbOwner.Multicall(&auth, [][]byte{scheduleUpgrade(1000), scheduleUpgrade(999)})
+		ensure(
+			customError,
+			precompilesgen.ArbOwnerMetaData,
+			"ArbOSUpgradeNotIncreasing",
+			[]interface{}{uint64(999), uint64(1000)},
+			"arbOwner.Multicall",
+		)
+
+		arbGasInfo, err := precompilesgen.NewArbGasInfo(types.ArbGasInfoAddress, builder.L2Client())
+		Require(t, err)
+		minimum, err := arbGasInfo.GetMinimumGasPrice(callOpts)
+		Require(t, err)
+		tooLow := new(big.Int).Sub(minimum, common.Big1)
+		_, customError = arbOwner.SetL2BaseFee(&auth, tooLow)
+		ensure(
+			customError,
+			precompilesgen.ArbOwnerMetaData,
+			"BaseFeeUnderMinimum",
+			[]interface{}{tooLow, minimum},
+			"arbOwner.SetL2BaseFee",
+		)
+	}
+
+	arbRetryableTx, err := precompilesgen.NewArbRetryableTx(types.ArbRetryableTxAddress, builder.L2Client())
 	Require(t, err)
 	_, customError = arbRetryableTx.SubmitRetryable(
 		&auth,
@@ -177,23 +392,29 @@ func TestCustomSolidityErrors(t *testing.T) {
 	)
 	ensure(
 		customError,
-		"NotCallable()",
+		precompilesgen.ArbRetryableTxMetaData,
+		"NotCallable",
+		nil,
 		"arbRetryableTx.SubmitRetryable",
 	)
 
-	arbosActs, err := precompilesgen.NewArbosActs(types.ArbosAddress, builder.L2.Client)
+	arbosActs, err := precompilesgen.NewArbosActs(types.ArbosAddress, builder.L2Client())
 	Require(t, err)
 	_, customError = arbosActs.StartBlock(&auth, big.NewInt(0), 0, 0, 0)
 	ensure(
 		customError,
-		"CallerNotArbOS()",
+		precompilesgen.ArbosActsMetaData,
+		"CallerNotArbOS",
+		nil,
 		"arbosActs.StartBlock",
 	)
 
 	_, customError = arbosActs.BatchPostingReport(&auth, big.NewInt(0), common.Address{}, 0, 0, big.NewInt(0))
 	ensure(
 		customError,
-		"CallerNotArbOS()",
+		precompilesgen.ArbosActsMetaData,
+		"CallerNotArbOS",
+		nil,
 		"arbosActs.BatchPostingReport",
 	)
 }
@@ -207,7 +428,7 @@ func TestPrecompileErrorGasLeft(t *testing.T) {
 	defer cleanup()
 
 	auth := builder.L2Info.GetDefaultTransactOpts("Faucet", ctx)
-	_, _, simple, err := mocksgen.DeploySimple(&auth, builder.L2.Client)
+	_, _, simple, err := mocksgen.DeploySimple(&auth, builder.L2Client())
 	Require(t, err)
 
 	assertNotAllGasConsumed := func(to common.Address, input []byte) {
@@ -234,6 +455,99 @@ func TestPrecompileErrorGasLeft(t *testing.T) {
 	assertNotAllGasConsumed(common.HexToAddress("0xff"), arbDebug.Methods["legacyError"].ID)
 }
 
+func TestPrecompileGasCostConsistency(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	builder := NewNodeBuilder(ctx).DefaultConfig(t, false)
+	cleanup := builder.Build(t)
+	defer cleanup()
+
+	// without L1 pricing, the gas estimated is only the intrinsic gas and the gas the precompile charges
+	auth := builder.L2Info.GetDefaultTransactOpts("Owner", ctx)
+	arbOwner, err := precompilesgen.NewArbOwner(types.ArbOwnerAddress, builder.L2Client())
+	Require(t, err)
+	tx, err := arbOwner.SetL1PricePerUnit(&auth, common.Big0)
+	Require(t, err)
+	receipt, err := builder.L2.EnsureTxSucceeded(tx)
+	Require(t, err)
+
+	// reads is the fewest ArbOS storage reads a method does, which for view methods includes opening the ArbOS state
+	type gasBounds struct {
+		args    []interface{}
+		reads   uint64
+		ceiling uint64
+	}
+	defaultBounds := map[string]gasBounds{
+		"ArbGasInfo":     {reads: 2, ceiling: 100_000},
+		"ArbSys":         {reads: 1, ceiling: 100_000},
+		"ArbOwnerPublic": {reads: 2, ceiling: 100_000},
+	}
+	methodBounds := map[string]gasBounds{
+		"ArbGasInfo.getCurrentTxL1GasFees":           {reads: 1, ceiling: 100_000},
+		"ArbSys.arbBlockHash":                        {args: []interface{}{receipt.BlockNumber}, reads: 1, ceiling: 100_000},
+		"ArbSys.mapL1SenderContractAddressToL2Alias": {reads: 0, ceiling: 100_000}, // pure
+	}
+	precompiles := []struct {
+		name     string
+		address  common.Address
+		metaData *bind.MetaData
+	}{
+		{"ArbGasInfo", types.ArbGasInfoAddress, precompilesgen.ArbGasInfoMetaData},
+		{"ArbSys", types.ArbSysAddress, precompilesgen.ArbSysMetaData},
+		{"ArbOwnerPublic", types.ArbOwnerPublicAddress, precompilesgen.ArbOwnerPublicMetaData},
+	}
+	for _, precompile := range precompiles {
+		contractAbi, err := precompile.metaData.GetAbi()
+		Require(t, err)
+		for _, method := range contractAbi.Methods {
+			if !method.IsConstant() {
+				continue
+			}
+			name := precompile.name + "." + method.RawName
+			bounds, ok := methodBounds[name]
+			if !ok {
+				bounds = defaultBounds[precompile.name]
+			}
+			args := bounds.args
+			if args == nil {
+				for _, input := range method.Inputs {
+					arg := reflect.New(input.Type.GetType()).Elem().Interface()
+					if _, isBig := arg.(*big.Int); isBig {
+						arg = new(big.Int)
+					}
+					args = append(args, arg)
+				}
+			}
+			data, err := contractAbi.Pack(method.RawName, args...)
+			Require(t, err, "method", name)
+			gas, err := builder.L2Client().EstimateGas(ctx, ethereum.CallMsg{To: &precompile.address, Data: data})
+			Require(t, err, "method", name)
+
+			intrinsic := params.TxGas
+			for _, b := range data {
+				if b == 0 {
+					intrinsic += params.TxDataZeroGas
+				} else {
+					intrinsic += params.TxDataNonZeroGasEIP2028
+				}
+			}
+			if gas < intrinsic {
+				Fatal(t, name, "estimated", gas, "gas, less than the intrinsic gas", intrinsic)
+			}
+			used := gas - intrinsic
+			if floor := bounds.reads * storage.StorageReadCost; used <= floor {
+				Fatal(t, name, "used", used, "gas, no more than the floor", floor, "for reading storage", bounds.reads, "times")
+			}
+			if used > bounds.ceiling {
+				Fatal(t, name, "used", used, "gas, more than the ceiling", bounds.ceiling)
+			}
+		}
+	}
+}
+
 func setupArbOwnerAndArbGasInfo(
 	t *testing.T,
 ) (
@@ -255,9 +569,9 @@ func setupArbOwnerAndArbGasInfo(
 
 	auth := builder.L2Info.GetDefaultTransactOpts("Owner", ctx)
 
-	arbOwner, err := precompilesgen.NewArbOwner(common.HexToAddress("0x70"), builder.L2.Client)
+	arbOwner, err := precompilesgen.NewArbOwner(common.HexToAddress("0x70"), builder.L2Client())
 	Require(t, err)
-	arbGasInfo, err := precompilesgen.NewArbGasInfo(common.HexToAddress("0x6c"), builder.L2.Client)
+	arbGasInfo, err := precompilesgen.NewArbGasInfo(common.HexToAddress("0x6c"), builder.L2Client())
 	Require(t, err)
 
 	return builder, cleanup, auth, arbOwner, arbGasInfo
@@ -302,6 +616,36 @@ func TestL1PricingInertia(t *testing.T) {
 	}
 }
 
+func TestL1BlockTimeSeconds(t *testing.T) {
+	t.Parallel()
+
+	builder, cleanup, auth, arbOwner, arbGasInfo := setupArbOwnerAndArbGasInfo(t)
+	defer cleanup()
+	ctx := builder.ctx
+
+	expectBlockTime := func(expected uint64) {
+		t.Helper()
+		blockTime, err := arbGasInfo.GetL1BlockTimeSeconds(&bind.CallOpts{Context: ctx})
+		Require(t, err)
+		if blockTime != expected {
+			Fatal(t, "expected L1 block time to be", expected, "got", blockTime)
+		}
+	}
+	expectBlockTime(l1pricing.DefaultL1BlockTimeSeconds)
+
+	for _, seconds := range []uint64{2, 0} {
+		tx, err := arbOwner.SetL1BlockTimeSeconds(&auth, seconds)
+		Require(t, err)
+		_, err = builder.L2.EnsureTxSucceeded(tx)
+		Require(t, err)
+		if seconds == 0 {
+			expectBlockTime(l1pricing.DefaultL1BlockTimeSeconds)
+		} else {
+			expectBlockTime(seconds)
+		}
+	}
+}
+
 func TestL1PricingRewardRate(t *testing.T) {
 	t.Parallel()
 
@@ -359,6 +703,38 @@ func TestL2GasPricingInertia(t *testing.T) {
 	}
 }
 
+// GetPricingInertia and GetL1BaseFeeEstimateInertia read the L2 and L1 pricing inertias, which are set independently
+func TestL1AndL2PricingInertiaAreIndependent(t *testing.T) {
+	t.Parallel()
+
+	builder, cleanup, auth, arbOwner, arbGasInfo := setupArbOwnerAndArbGasInfo(t)
+	defer cleanup()
+	ctx := builder.ctx
+	callOpts := &bind.CallOpts{Context: ctx}
+
+	l1Inertia := uint64(21)
+	l2Inertia := uint64(22)
+	tx, err := arbOwner.SetL1PricingInertia(&auth, l1Inertia)
+	Require(t, err)
+	_, err = builder.L2.EnsureTxSucceeded(tx)
+	Require(t, err)
+	tx, err = arbOwner.SetL2GasPricingInertia(&auth, l2Inertia)
+	Require(t, err)
+	_, err = builder.L2.EnsureTxSucceeded(tx)
+	Require(t, err)
+
+	arbGasInfoL1Inertia, err := arbGasInfo.GetL1BaseFeeEstimateInertia(callOpts)
+	Require(t, err)
+	if arbGasInfoL1Inertia != l1Inertia {
+		Fatal(t, "expected L1 inertia to be", l1Inertia, "got", arbGasInfoL1Inertia)
+	}
+	arbGasInfoL2Inertia, err := arbGasInfo.GetPricingInertia(callOpts)
+	Require(t, err)
+	if arbGasInfoL2Inertia != l2Inertia {
+		Fatal(t, "expected L2 inertia to be", l2Inertia, "got", arbGasInfoL2Inertia)
+	}
+}
+
 func TestL2GasBacklogTolerance(t *testing.T) {
 	t.Parallel()
 
@@ -451,15 +827,20 @@ func TestGasAccountingParams(t *testing.T) {
 
 func TestCurrentTxL1GasFees(t *testing.T) {
 	t.Parallel()
+	testCurrentTxL1GasFees(t, chaininfo.ArbitrumDevTestChainConfig().ArbitrumChainParams.InitialArbOSVersion)
+}
 
+func testCurrentTxL1GasFees(t *testing.T, arbosVersion uint64) {
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 
-	builder := NewNodeBuilder(ctx).DefaultConfig(t, false)
+	builder := NewNodeBuilder(ctx).
+		DefaultConfig(t, false).
+		WithArbOSVersion(arbosVersion)
 	cleanup := builder.Build(t)
 	defer cleanup()
 
-	arbGasInfo, err := precompilesgen.NewArbGasInfo(types.ArbGasInfoAddress, builder.L2.Client)
+	arbGasInfo, err := precompilesgen.NewArbGasInfo(types.ArbGasInfoAddress, builder.L2Client())
 	Require(t, err)
 
 	currTxL1GasFees, err := arbGasInfo.GetCurrentTxL1GasFees(&bind.CallOpts{Context: ctx})
@@ -472,6 +853,40 @@ func TestCurrentTxL1GasFees(t *testing.T) {
 	}
 }
 
+// ArbOS versions that chains may be launched with, oldest first.
+var precompileCompatibilityVersions = []uint64{
+	params.ArbosVersion_11,
+	params.ArbosVersion_20,
+	params.ArbosVersion_30,
+	params.ArbosVersion_31,
+	params.ArbosVersion_32,
+	util.ArbosVersion_40,
+}
+
+func TestPrecompileUpgradeCompatibility(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name string
+		run  func(*testing.T, uint64)
+	}{
+		{"PurePrecompileMethodCalls", testPurePrecompileMethodCalls},
+		{"CustomSolidityErrors", testCustomSolidityErrors},
+		{"CurrentTxL1GasFees", testCurrentTxL1GasFees},
+	}
+	for _, arbosVersion := range precompileCompatibilityVersions {
+		// the subtest name identifies the ArbOS version on failure
+		t.Run(fmt.Sprintf("ArbOS%d", arbosVersion), func(t *testing.T) {
+			t.Parallel()
+			for _, test := range tests {
+				t.Run(test.name, func(t *testing.T) {
+					t.Parallel()
+					test.run(t, arbosVersion)
+				})
+			}
+		})
+	}
+}
+
 func TestGetBrotliCompressionLevel(t *testing.T) {
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
@@ -482,10 +897,10 @@ func TestGetBrotliCompressionLevel(t *testing.T) {
 
 	auth := builder.L2Info.GetDefaultTransactOpts("Owner", ctx)
 
-	arbOwnerPublic, err := precompilesgen.NewArbOwnerPublic(types.ArbOwnerPublicAddress, builder.L2.Client)
+	arbOwnerPublic, err := precompilesgen.NewArbOwnerPublic(types.ArbOwnerPublicAddress, builder.L2Client())
 	Require(t, err)
 
-	arbOwner, err := precompilesgen.NewArbOwner(types.ArbOwnerAddress, builder.L2.Client)
+	arbOwner, err := precompilesgen.NewArbOwner(types.ArbOwnerAddress, builder.L2Client())
 	Require(t, err)
 
 	brotliCompressionLevel := uint64(11)
@@ -515,14 +930,14 @@ func TestArbStatistics(t *testing.T) {
 	cleanup := builder.Build(t)
 	defer cleanup()
 
-	arbStatistics, err := precompilesgen.NewArbStatistics(types.ArbStatisticsAddress, builder.L2.Client)
+	arbStatistics, err := precompilesgen.NewArbStatistics(types.ArbStatisticsAddress, builder.L2Client())
 	Require(t, err)
 
 	callOpts := &bind.CallOpts{Context: ctx}
 	blockNum, _, _, _, _, _, err := arbStatistics.GetStats(callOpts)
 	Require(t, err)
 
-	expectedBlockNum, err := builder.L2.Client.BlockNumber(ctx)
+	expectedBlockNum, err := builder.L2Client().BlockNumber(ctx)
 	Require(t, err)
 
 	if blockNum.Uint64() != expectedBlockNum {
@@ -530,6 +945,249 @@ func TestArbStatistics(t *testing.T) {
 	}
 }
 
+func TestArbStatisticsGasUsed(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	builder := NewNodeBuilder(ctx).DefaultConfig(t, false)
+	cleanup := builder.Build(t)
+	defer cleanup()
+
+	arbStatistics, err := precompilesgen.NewArbStatistics(types.ArbStatisticsAddress, builder.L2Client())
+	Require(t, err)
+
+	blockBefore, err := builder.L2Client().BlockNumber(ctx)
+	Require(t, err)
+	totalBefore, err := arbStatistics.GetTotalGasUsed(&bind.CallOpts{Context: ctx, BlockNumber: arbmath.UintToBig(blockBefore)})
+	Require(t, err)
+
+	builder.L2Info.GenerateAccount("User2")
+	gasUsed := uint64(0)
+	var lastReceipt *types.Receipt
+	for i := 0; i < 5; i++ {
+		_, lastReceipt = builder.L2.TransferBalance(t, "Owner", "User2", big.NewInt(1e12), builder.L2Info)
+		gasUsed += lastReceipt.GasUsed
+	}
+
+	totalAfter, err := arbStatistics.GetTotalGasUsed(&bind.CallOpts{Context: ctx, BlockNumber: lastReceipt.BlockNumber})
+	Require(t, err)
+
+	delta := arbmath.BigSub(totalAfter, totalBefore)
+	if !arbmath.BigEquals(delta, arbmath.UintToBig(gasUsed)) {
+		Fatal(t, "expected total gas used to grow by", gasUsed, "got", delta)
+	}
+}
+
+// Before ArbOS 40 the total isn't recorded, as writing it would change the state roots of existing blocks.
+func TestTotalGasUsedNotRecordedBeforeArbOS40(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	builder := NewNodeBuilder(ctx).DefaultConfig(t, false).WithArbOSVersion(params.ArbosVersion_32)
+	cleanup := builder.Build(t)
+	defer cleanup()
+
+	builder.L2Info.GenerateAccount("User2")
+	for i := 0; i < 3; i++ {
+		builder.L2.TransferBalance(t, "Owner", "User2", big.NewInt(1e12), builder.L2Info)
+	}
+
+	statedb, err := builder.L2.ExecNode.Backend.ArbInterface().BlockChain().State()
+	Require(t, err)
+	state, err := arbosState.OpenSystemArbosState(statedb, nil, true)
+	Require(t, err)
+	total, err := state.TotalGasUsed()
+	Require(t, err)
+	if total != 0 {
+		Fatal(t, "expected an ArbOS 32 chain to leave the total gas used unset, got", total)
+	}
+}
+
+func TestArbSysWithdrawEth(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	builder := NewNodeBuilder(ctx).DefaultConfig(t, false)
+	cleanup := builder.Build(t)
+	defer cleanup()
+
+	arbSys, err := precompilesgen.NewArbSys(types.ArbSysAddress, builder.L2Client())
+	Require(t, err)
+	callOpts := &bind.CallOpts{Context: ctx}
+	auth := builder.L2Info.GetDefaultTransactOpts("Owner", ctx)
+	destination := common.HexToAddress("0x1234")
+
+	for i := int64(0); i < 2; i++ {
+		merkleState, err := arbSys.SendMerkleTreeState(callOpts)
+		Require(t, err)
+		sizeBefore := merkleState.Size.Uint64()
+
+		value := big.NewInt((i + 1) * 1e15)
+		auth.Value = value
+		tx, err := arbSys.WithdrawEth(&auth, destination)
+		Require(t, err)
+		receipt, err := builder.L2.EnsureTxSucceeded(tx)
+		Require(t, err)
+		header, err := builder.L2Client().HeaderByHash(ctx, receipt.BlockHash)
+		Require(t, err)
+
+		var event *precompilesgen.ArbSysL2ToL1Tx
+		for _, log := range receipt.Logs {
+			if parsed, err := arbSys.ParseL2ToL1Tx(*log); err == nil {
+				if event != nil {
+					Fatal(t, "withdrawal emitted more than one L2ToL1Tx event")
+				}
+				event = parsed
+			}
+		}
+		if event == nil {
+			Fatal(t, "withdrawal didn't emit an L2ToL1Tx event")
+		}
+		if event.Caller != auth.From || event.Destination != destination {
+			Fatal(t, "expected withdrawal from", auth.From, "to", destination, "got", event.Caller, "to", event.Destination)
+		}
+		if !arbmath.BigEquals(event.Callvalue, value) || len(event.Data) != 0 {
+			Fatal(t, "expected withdrawal of", value, "with no data, got", event.Callvalue, "with data", event.Data)
+		}
+		if !arbmath.BigEquals(event.ArbBlockNum, receipt.BlockNumber) || event.Timestamp.Uint64() != header.Time {
+			Fatal(t, "expected withdrawal in block", receipt.BlockNumber, "at", header.Time, "got", event.ArbBlockNum, "at", event.Timestamp)
+		}
+		// each withdrawal is identified by its position in the outbox
+		if event.Position.Uint64() != sizeBefore {
+			Fatal(t, "expected withdrawal to be at outbox position", sizeBefore, "got", event.Position)
+		}
+		expectedHash := crypto.Keccak256Hash(
+			auth.From.Bytes(),
+			destination.Bytes(),
+			arbmath.U256Bytes(event.ArbBlockNum),
+			arbmath.U256Bytes(event.EthBlockNum),
+			arbmath.U256Bytes(event.Timestamp),
+			common.BigToHash(value).Bytes(),
+		)
+		if common.BigToHash(event.Hash) != expectedHash {
+			Fatal(t, "expected withdrawal hash", expectedHash, "got", common.BigToHash(event.Hash))
+		}
+
+		merkleState, err = arbSys.SendMerkleTreeState(callOpts)
+		Require(t, err)
+		if merkleState.Size.Uint64() != sizeBefore+1 {
+			Fatal(t, "expected the withdrawal to add an outbox entry, size went from", sizeBefore, "to", merkleState.Size)
+		}
+	}
+	if balance := builder.L2.GetBalance(t, types.ArbSysAddress); balance.Sign() != 0 {
+		Fatal(t, "expected withdrawn funds to be burned, ArbSys holds", balance)
+	}
+}
+
+func TestArbSysSendTxToL1(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	builder := NewNodeBuilder(ctx).DefaultConfig(t, false)
+	cleanup := builder.Build(t)
+	defer cleanup()
+
+	arbSys, err := precompilesgen.NewArbSys(types.ArbSysAddress, builder.L2Client())
+	Require(t, err)
+	arbSysAbi, err := precompilesgen.ArbSysMetaData.GetAbi()
+	Require(t, err)
+	auth := builder.L2Info.GetDefaultTransactOpts("Owner", ctx)
+	destination := common.HexToAddress("0x1234")
+
+	// the largest payload the sequencer accepts, leaving a few bytes for the length prefixes to grow
+	sizingOpts := auth
+	sizingOpts.NoSend = true
+	sizingOpts.GasLimit = 10_000_000
+	emptyTx, err := arbSys.SendTxToL1(&sizingOpts, destination, []byte{})
+	Require(t, err)
+	emptyTxBytes, err := emptyTx.MarshalBinary()
+	Require(t, err)
+	maxPayloadSize := (builder.execConfig.Sequencer.MaxTxDataSize - len(emptyTxBytes) - 8) &^ 31
+	payload := func(size int) []byte {
+		data := make([]byte, size)
+		for i := range data {
+			data[i] = byte(i % 251)
+		}
+		return data
+	}
+
+	// a call the destination could make on L1, with its own dynamic arguments
+	nestedCall, err := arbSysAbi.Pack("sendTxToL1", common.HexToAddress("0x5678"), []byte("nested calldata"))
+	Require(t, err)
+
+	for _, test := range []struct {
+		name string
+		data []byte
+	}{
+		{"non-empty", []byte("hello from L2")},
+		{"empty", []byte{}},
+		{"ABI encoded", nestedCall},
+		{"maximum size", payload(maxPayloadSize)},
+	} {
+		merkleState, err := arbSys.SendMerkleTreeState(&bind.CallOpts{Context: ctx})
+		Require(t, err)
+		sizeBefore := merkleState.Size.Uint64()
+
+		value := big.NewInt(1e12)
+		auth.Value = value
+		tx, err := arbSys.SendTxToL1(&auth, destination, test.data)
+		Require(t, err, "case", test.name)
+		receipt, err := builder.L2.EnsureTxSucceeded(tx)
+		Require(t, err, "case", test.name)
+		header, err := builder.L2Client().HeaderByHash(ctx, receipt.BlockHash)
+		Require(t, err)
+
+		var event *precompilesgen.ArbSysL2ToL1Tx
+		for _, log := range receipt.Logs {
+			if parsed, err := arbSys.ParseL2ToL1Tx(*log); err == nil {
+				if event != nil {
+					Fatal(t, "case", test.name, "emitted more than one L2ToL1Tx event")
+				}
+				event = parsed
+			}
+		}
+		if event == nil {
+			Fatal(t, "case", test.name, "didn't emit an L2ToL1Tx event")
+		}
+		if !bytes.Equal(event.Data, test.data) {
+			Fatal(t, "case", test.name, "sent", len(test.data), "bytes of data, event has", len(event.Data), "bytes", event.Data)
+		}
+		if event.Caller != auth.From || event.Destination != destination || !arbmath.BigEquals(event.Callvalue, value) {
+			Fatal(t, "case", test.name, "expected", value, "from", auth.From, "to", destination, "got", event.Callvalue, "from", event.Caller, "to", event.Destination)
+		}
+		if event.Position.Uint64() != sizeBefore {
+			Fatal(t, "case", test.name, "expected outbox position", sizeBefore, "got", event.Position)
+		}
+		// the send's hash commits to its data, so L1 executes exactly what was sent
+		expectedHash := crypto.Keccak256Hash(
+			auth.From.Bytes(),
+			destination.Bytes(),
+			arbmath.U256Bytes(event.ArbBlockNum),
+			arbmath.U256Bytes(event.EthBlockNum),
+			arbmath.U256Bytes(new(big.Int).SetUint64(header.Time)),
+			common.BigToHash(value).Bytes(),
+			test.data,
+		)
+		if common.BigToHash(event.Hash) != expectedHash {
+			Fatal(t, "case", test.name, "expected hash", expectedHash, "got", common.BigToHash(event.Hash))
+		}
+	}
+
+	auth.Value = nil
+	_, err = arbSys.SendTxToL1(&auth, destination, payload(maxPayloadSize+64))
+	if err == nil || !strings.Contains(err.Error(), txpool.ErrOversizedData.Error()) {
+		Fatal(t, "expected calldata beyond the maximum to be rejected as oversized, got", err)
+	}
+}
+
 func TestArbFunctionTable(t *testing.T) {
 	t.Parallel()
 
@@ -543,7 +1201,7 @@ func TestArbFunctionTable(t *testing.T) {
 	auth := builder.L2Info.GetDefaultTransactOpts("Owner", ctx)
 	callOpts := &bind.CallOpts{Context: ctx}
 
-	arbFunctionTable, err := precompilesgen.NewArbFunctionTable(types.ArbFunctionTableAddress, builder.L2.Client)
+	arbFunctionTable, err := precompilesgen.NewArbFunctionTable(types.ArbFunctionTableAddress, builder.L2Client())
 	Require(t, err)
 
 	addr := common.BytesToAddress(crypto.Keccak256([]byte{})[:20])
@@ -566,7 +1224,9 @@ func TestArbFunctionTable(t *testing.T) {
 	}
 }
 
-func TestArbAggregatorBaseFee(t *testing.T) {
+// Nitro stubbed out function tables, so even a table encoded as Arbitrum Classic expected
+// (an rlp list of function selector, payability, and gas limit entries) is accepted and dropped.
+func TestArbFunctionTableUploadValid(t *testing.T) {
 	t.Parallel()
 
 	ctx, cancel := context.WithCancel(context.Background())
@@ -579,22 +1239,32 @@ func TestArbAggregatorBaseFee(t *testing.T) {
 	auth := builder.L2Info.GetDefaultTransactOpts("Owner", ctx)
 	callOpts := &bind.CallOpts{Context: ctx}
 
-	arbAggregator, err := precompilesgen.NewArbAggregator(types.ArbAggregatorAddress, builder.L2.Client)
+	arbFunctionTable, err := precompilesgen.NewArbFunctionTable(types.ArbFunctionTableAddress, builder.L2Client())
 	Require(t, err)
 
-	tx, err := arbAggregator.SetTxBaseFee(&auth, common.Address{}, big.NewInt(1))
+	type functionTableEntry struct {
+		Selector uint32
+		Payable  bool
+		GasLimit uint64
+	}
+	table, err := rlp.EncodeToBytes([]functionTableEntry{{Selector: 0xa9059cbb, Payable: false, GasLimit: 100000}})
+	Require(t, err)
+	tx, err := arbFunctionTable.Upload(&auth, table)
 	Require(t, err)
 	_, err = builder.L2.EnsureTxSucceeded(tx)
 	Require(t, err)
 
-	fee, err := arbAggregator.GetTxBaseFee(callOpts, common.Address{})
+	size, err := arbFunctionTable.Size(callOpts, auth.From)
 	Require(t, err)
-	if fee.Cmp(big.NewInt(0)) != 0 {
-		Fatal(t, "expected fee to be 0, got", fee)
+	if size.Sign() != 0 {
+		Fatal(t, "expected the uploaded table to be dropped, got size", size)
+	}
+	if _, _, _, err := arbFunctionTable.Get(callOpts, auth.From, big.NewInt(0)); err == nil {
+		Fatal(t, "expected getting an entry of the uploaded table to revert")
 	}
 }
 
-func TestFeeAccounts(t *testing.T) {
+func TestArbAddressTableCompressReturnValue(t *testing.T) {
 	t.Parallel()
 
 	ctx, cancel := context.WithCancel(context.Background())
@@ -607,36 +1277,61 @@ func TestFeeAccounts(t *testing.T) {
 	auth := builder.L2Info.GetDefaultTransactOpts("Owner", ctx)
 	callOpts := &bind.CallOpts{Context: ctx}
 
-	arbOwner, err := precompilesgen.NewArbOwner(types.ArbOwnerAddress, builder.L2.Client)
+	arbAddressTableAbi, err := precompilesgen.ArbAddressTableMetaData.GetAbi()
+	Require(t, err)
+	arbAddressTable, err := precompilesgen.NewArbAddressTable(types.ArbAddressTableAddress, builder.L2Client())
 	Require(t, err)
 
-	builder.L2Info.GenerateAccount("User2")
-	addr := builder.L2Info.GetAddress("User2")
+	addr := common.BytesToAddress(crypto.Keccak256([]byte("compressed"))[:20])
 
-	tx, err := arbOwner.SetNetworkFeeAccount(&auth, addr)
-	Require(t, err)
-	_, err = builder.L2.EnsureTxSucceeded(tx)
-	Require(t, err)
+	compressRoundTrip := func() []byte {
+		t.Helper()
+		tx, err := arbAddressTable.Compress(&auth, addr)
+		Require(t, err)
+		_, err = builder.L2.EnsureTxSucceeded(tx)
+		Require(t, err)
 
-	feeAccount, err := arbOwner.GetNetworkFeeAccount(callOpts)
-	Require(t, err)
-	if feeAccount.Cmp(addr) != 0 {
-		Fatal(t, "expected fee account to be", addr, "got", feeAccount)
+		// read the method's return value from the trace
+		var trace struct {
+			Output hexutil.Bytes `json:"output"`
+		}
+		traceConfig := map[string]interface{}{"tracer": "callTracer"}
+		err = builder.L2Client().Client().CallContext(ctx, &trace, "debug_traceTransaction", tx.Hash(), traceConfig)
+		Require(t, err)
+		returned, err := arbAddressTableAbi.Methods["compress"].Outputs.Unpack(trace.Output)
+		Require(t, err)
+		compressed, _ := returned[0].([]byte)
+		if len(compressed) == 0 {
+			Fatal(t, "Compress returned no bytes")
+		}
+
+		decompressed, read, err := arbAddressTable.Decompress(callOpts, compressed, common.Big0)
+		Require(t, err)
+		if decompressed != addr {
+			Fatal(t, "decompressed", decompressed, "instead of", addr)
+		}
+		if read.Uint64() != uint64(len(compressed)) {
+			Fatal(t, "Decompress read", read, "of", len(compressed), "compressed bytes")
+		}
+		return compressed
 	}
 
-	tx, err = arbOwner.SetInfraFeeAccount(&auth, addr)
+	// an unregistered address is compressed to the full address
+	unregistered := compressRoundTrip()
+
+	tx, err := arbAddressTable.Register(&auth, addr)
 	Require(t, err)
 	_, err = builder.L2.EnsureTxSucceeded(tx)
 	Require(t, err)
 
-	feeAccount, err = arbOwner.GetInfraFeeAccount(callOpts)
-	Require(t, err)
-	if feeAccount.Cmp(addr) != 0 {
-		Fatal(t, "expected fee account to be", addr, "got", feeAccount)
+	// a registered one to its index in the table
+	registered := compressRoundTrip()
+	if len(registered) >= len(unregistered) {
+		Fatal(t, "registering the address didn't shorten its compression from", len(unregistered), "to", len(registered), "bytes")
 	}
 }
 
-func TestChainOwners(t *testing.T) {
+func TestArbAggregatorBaseFee(t *testing.T) {
 	t.Parallel()
 
 	ctx, cancel := context.WithCancel(context.Background())
@@ -649,25 +1344,179 @@ func TestChainOwners(t *testing.T) {
 	auth := builder.L2Info.GetDefaultTransactOpts("Owner", ctx)
 	callOpts := &bind.CallOpts{Context: ctx}
 
-	arbOwnerPublic, err := precompilesgen.NewArbOwnerPublic(types.ArbOwnerPublicAddress, builder.L2.Client)
-	Require(t, err)
-	arbOwner, err := precompilesgen.NewArbOwner(types.ArbOwnerAddress, builder.L2.Client)
+	arbAggregator, err := precompilesgen.NewArbAggregator(types.ArbAggregatorAddress, builder.L2Client())
 	Require(t, err)
 
-	builder.L2Info.GenerateAccount("Owner2")
-	chainOwnerAddr2 := builder.L2Info.GetAddress("Owner2")
-	tx, err := arbOwner.AddChainOwner(&auth, chainOwnerAddr2)
+	tx, err := arbAggregator.SetTxBaseFee(&auth, common.Address{}, big.NewInt(1))
 	Require(t, err)
 	_, err = builder.L2.EnsureTxSucceeded(tx)
 	Require(t, err)
-	isChainOwner, err := arbOwnerPublic.IsChainOwner(callOpts, chainOwnerAddr2)
+
+	fee, err := arbAggregator.GetTxBaseFee(callOpts, common.Address{})
 	Require(t, err)
-	if !isChainOwner {
-		Fatal(t, "expected owner2 to be a chain owner")
+	if fee.Cmp(big.NewInt(0)) != 0 {
+		Fatal(t, "expected fee to be 0, got", fee)
 	}
+}
 
-	// check that the chain owners retrieved from arbOwnerPublic and arbOwner are the same
-	chainOwnersArbOwnerPublic, err := arbOwnerPublic.GetAllChainOwners(callOpts)
+func TestFeeAccounts(t *testing.T) {
+	t.Parallel()
+	testFeeAccounts(t, false)
+}
+
+func TestFeeAccountsWithExternalSigner(t *testing.T) {
+	// not parallel, as test signer servers share a keystore directory
+	testFeeAccounts(t, true)
+}
+
+func testFeeAccounts(t *testing.T, useExternalSigner bool) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	builder := NewNodeBuilder(ctx).DefaultConfig(t, false)
+	var srv *externalsignertest.SignerServer
+	if useExternalSigner {
+		srv = externalsignertest.NewServer(t)
+		go func() {
+			if err := srv.Start(); err != nil {
+				log.Error("Failed to start external signer server:", err)
+				return
+			}
+		}()
+		builder.WithExternalSigner(srv.URL())
+	}
+	cleanup := builder.Build(t)
+	defer cleanup()
+
+	auth := builder.L2Info.GetDefaultTransactOpts("Owner", ctx)
+	if useExternalSigner && auth.From != srv.Address {
+		Fatal(t, "expected Owner to be the external signer", srv.Address, "got", auth.From)
+	}
+	callOpts := &bind.CallOpts{Context: ctx}
+
+	arbOwner, err := precompilesgen.NewArbOwner(types.ArbOwnerAddress, builder.L2Client())
+	Require(t, err)
+	arbOwnerPublic, err := precompilesgen.NewArbOwnerPublic(types.ArbOwnerPublicAddress, builder.L2Client())
+	Require(t, err)
+
+	builder.L2Info.GenerateAccount("User2")
+	addr := builder.L2Info.GetAddress("User2")
+
+	tx, err := arbOwner.SetNetworkFeeAccount(&auth, addr)
+	Require(t, err)
+	_, err = builder.L2.EnsureTxSucceeded(tx)
+	Require(t, err)
+
+	feeAccount, err := arbOwner.GetNetworkFeeAccount(callOpts)
+	Require(t, err)
+	if feeAccount.Cmp(addr) != 0 {
+		Fatal(t, "expected fee account to be", addr, "got", feeAccount)
+	}
+	publicFeeAccount, err := arbOwnerPublic.GetNetworkFeeAccount(callOpts)
+	Require(t, err)
+	if publicFeeAccount != feeAccount {
+		Fatal(t, "ArbOwnerPublic has network fee account", publicFeeAccount, "but ArbOwner has", feeAccount)
+	}
+
+	tx, err = arbOwner.SetInfraFeeAccount(&auth, addr)
+	Require(t, err)
+	_, err = builder.L2.EnsureTxSucceeded(tx)
+	Require(t, err)
+
+	feeAccount, err = arbOwner.GetInfraFeeAccount(callOpts)
+	Require(t, err)
+	if feeAccount.Cmp(addr) != 0 {
+		Fatal(t, "expected fee account to be", addr, "got", feeAccount)
+	}
+	publicFeeAccount, err = arbOwnerPublic.GetInfraFeeAccount(callOpts)
+	Require(t, err)
+	if publicFeeAccount != feeAccount {
+		Fatal(t, "ArbOwnerPublic has infra fee account", publicFeeAccount, "but ArbOwner has", feeAccount)
+	}
+}
+
+func TestL2BaseFeeMinimum(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	builder := NewNodeBuilder(ctx).DefaultConfig(t, false)
+	cleanup := builder.Build(t)
+	defer cleanup()
+
+	auth := builder.L2Info.GetDefaultTransactOpts("Owner", ctx)
+	callOpts := &bind.CallOpts{Context: ctx}
+
+	arbOwner, err := precompilesgen.NewArbOwner(types.ArbOwnerAddress, builder.L2Client())
+	Require(t, err)
+	arbOwnerPublic, err := precompilesgen.NewArbOwnerPublic(types.ArbOwnerPublicAddress, builder.L2Client())
+	Require(t, err)
+	arbGasInfo, err := precompilesgen.NewArbGasInfo(types.ArbGasInfoAddress, builder.L2Client())
+	Require(t, err)
+
+	minimum := big.NewInt(l2pricing.InitialMinimumBaseFeeWei * 2)
+	tx, err := arbOwner.SetMinimumL2BaseFee(&auth, minimum)
+	Require(t, err)
+	_, err = builder.L2.EnsureTxSucceeded(tx)
+	Require(t, err)
+
+	publicMinimum, err := arbOwnerPublic.GetL2BaseFeeMinimum(callOpts)
+	Require(t, err)
+	if !arbmath.BigEquals(publicMinimum, minimum) {
+		Fatal(t, "expected ArbOwnerPublic's minimum base fee to be", minimum, "got", publicMinimum)
+	}
+	gasInfoMinimum, err := arbGasInfo.GetMinimumGasPrice(callOpts)
+	Require(t, err)
+	if !arbmath.BigEquals(gasInfoMinimum, publicMinimum) {
+		Fatal(t, "ArbGasInfo has minimum base fee", gasInfoMinimum, "but ArbOwnerPublic has", publicMinimum)
+	}
+}
+
+func TestChainOwners(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	builder := NewNodeBuilder(ctx).DefaultConfig(t, false)
+	cleanup := builder.Build(t)
+	defer cleanup()
+
+	auth := builder.L2Info.GetDefaultTransactOpts("Owner", ctx)
+	callOpts := &bind.CallOpts{Context: ctx}
+
+	arbOwnerPublic, err := precompilesgen.NewArbOwnerPublic(types.ArbOwnerPublicAddress, builder.L2Client())
+	Require(t, err)
+	arbOwner, err := precompilesgen.NewArbOwner(types.ArbOwnerAddress, builder.L2Client())
+	Require(t, err)
+
+	builder.L2Info.GenerateAccount("Owner2")
+	chainOwnerAddr2 := builder.L2Info.GetAddress("Owner2")
+	tx, err := arbOwner.AddChainOwner(&auth, chainOwnerAddr2)
+	Require(t, err)
+	receipt, err := builder.L2.EnsureTxSucceeded(tx)
+	Require(t, err)
+	var added []*precompilesgen.ArbOwnerChainOwnerAdded
+	for _, log := range receipt.Logs {
+		if event, err := arbOwner.ParseChainOwnerAdded(*log); err == nil {
+			added = append(added, event)
+		}
+	}
+	if len(added) != 1 {
+		Fatal(t, "expected one ChainOwnerAdded event, got", len(added))
+	}
+	if added[0].NewOwner != chainOwnerAddr2 {
+		Fatal(t, "expected ChainOwnerAdded for", chainOwnerAddr2, "got", added[0].NewOwner)
+	}
+	isChainOwner, err := arbOwnerPublic.IsChainOwner(callOpts, chainOwnerAddr2)
+	Require(t, err)
+	if !isChainOwner {
+		Fatal(t, "expected owner2 to be a chain owner")
+	}
+
+	// check that the chain owners retrieved from arbOwnerPublic and arbOwner are the same
+	chainOwnersArbOwnerPublic, err := arbOwnerPublic.GetAllChainOwners(callOpts)
 	Require(t, err)
 	chainOwnersArbOwner, err := arbOwner.GetAllChainOwners(callOpts)
 	Require(t, err)
@@ -711,6 +1560,93 @@ func TestChainOwners(t *testing.T) {
 	}
 }
 
+func TestArbOwnerRemoveChainOwnerEvent(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	builder := NewNodeBuilder(ctx).DefaultConfig(t, false)
+	cleanup := builder.Build(t)
+	defer cleanup()
+
+	auth := builder.L2Info.GetDefaultTransactOpts("Owner", ctx)
+	arbOwner, err := precompilesgen.NewArbOwner(types.ArbOwnerAddress, builder.L2Client())
+	Require(t, err)
+
+	builder.L2Info.GenerateAccount("Owner2")
+	chainOwnerAddr2 := builder.L2Info.GetAddress("Owner2")
+	tx, err := arbOwner.AddChainOwner(&auth, chainOwnerAddr2)
+	Require(t, err)
+	_, err = builder.L2.EnsureTxSucceeded(tx)
+	Require(t, err)
+
+	tx, err = arbOwner.RemoveChainOwner(&auth, chainOwnerAddr2)
+	Require(t, err)
+	receipt, err := builder.L2.EnsureTxSucceeded(tx)
+	Require(t, err)
+	var removed []*precompilesgen.ArbOwnerChainOwnerRemoved
+	for _, log := range receipt.Logs {
+		if event, err := arbOwner.ParseChainOwnerRemoved(*log); err == nil {
+			removed = append(removed, event)
+		}
+	}
+	if len(removed) != 1 {
+		Fatal(t, "expected one ChainOwnerRemoved event, got", len(removed))
+	}
+	if removed[0].OwnerToRemove != chainOwnerAddr2 {
+		Fatal(t, "expected ChainOwnerRemoved for", chainOwnerAddr2, "got", removed[0].OwnerToRemove)
+	}
+}
+
+func TestArbOwnerPublicGetAllChainOwners(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	builder := NewNodeBuilder(ctx).DefaultConfig(t, false)
+	cleanup := builder.Build(t)
+	defer cleanup()
+
+	auth := builder.L2Info.GetDefaultTransactOpts("Owner", ctx)
+
+	arbOwnerPublic, err := precompilesgen.NewArbOwnerPublic(types.ArbOwnerPublicAddress, builder.L2Client())
+	Require(t, err)
+	arbOwner, err := precompilesgen.NewArbOwner(types.ArbOwnerAddress, builder.L2Client())
+	Require(t, err)
+
+	// add enough owners that a nondeterministic order would show
+	for i := 0; i < 8; i++ {
+		owner := common.BytesToAddress(crypto.Keccak256([]byte{byte(i)})[:20])
+		tx, err := arbOwner.AddChainOwner(&auth, owner)
+		Require(t, err)
+		_, err = builder.L2.EnsureTxSucceeded(tx)
+		Require(t, err)
+	}
+	head, err := builder.L2Client().BlockNumber(ctx)
+	Require(t, err)
+	callOpts := &bind.CallOpts{Context: ctx, BlockNumber: new(big.Int).SetUint64(head)}
+
+	first, err := arbOwnerPublic.GetAllChainOwners(callOpts)
+	Require(t, err)
+	if len(first) != 9 {
+		Fatal(t, "expected 9 chain owners, got", len(first))
+	}
+	for i := 0; i < 4; i++ {
+		again, err := arbOwnerPublic.GetAllChainOwners(callOpts)
+		Require(t, err)
+		if !slices.Equal(first, again) {
+			Fatal(t, "chain owners changed order between calls", first, again)
+		}
+	}
+	fromArbOwner, err := arbOwner.GetAllChainOwners(callOpts)
+	Require(t, err)
+	if !slices.Equal(first, fromArbOwner) {
+		Fatal(t, "ArbOwner and ArbOwnerPublic list the chain owners in different orders", fromArbOwner, first)
+	}
+}
+
 func TestArbAggregatorBatchPosters(t *testing.T) {
 	t.Parallel()
 
@@ -724,10 +1660,10 @@ func TestArbAggregatorBatchPosters(t *testing.T) {
 	auth := builder.L2Info.GetDefaultTransactOpts("Owner", ctx)
 	callOpts := &bind.CallOpts{Context: ctx}
 
-	arbAggregator, err := precompilesgen.NewArbAggregator(types.ArbAggregatorAddress, builder.L2.Client)
+	arbAggregator, err := precompilesgen.NewArbAggregator(types.ArbAggregatorAddress, builder.L2Client())
 	Require(t, err)
 
-	arbDebug, err := precompilesgen.NewArbDebug(types.ArbDebugAddress, builder.L2.Client)
+	arbDebug, err := precompilesgen.NewArbDebug(types.ArbDebugAddress, builder.L2Client())
 	Require(t, err)
 
 	addr := common.BytesToAddress(crypto.Keccak256([]byte{})[:20])
@@ -758,6 +1694,103 @@ func TestArbAggregatorBatchPosters(t *testing.T) {
 	if bps[0] != addr && bps[1] != addr {
 		Fatal(t, "expected addr to be a batch poster")
 	}
+
+	// add a second batch poster
+	addr2 := common.BytesToAddress(crypto.Keccak256([]byte{2})[:20])
+	tx, err = arbAggregator.AddBatchPoster(&auth, addr2)
+	Require(t, err)
+	_, err = builder.L2.EnsureTxSucceeded(tx)
+	Require(t, err)
+
+	// rotate the fee collectors of both new batch posters in one call
+	collector := common.BytesToAddress(crypto.Keccak256([]byte{3})[:20])
+	collector2 := common.BytesToAddress(crypto.Keccak256([]byte{4})[:20])
+	tx, err = arbAggregator.SetFeeCollectors(&auth, []common.Address{addr, addr2}, []common.Address{collector, collector2})
+	Require(t, err)
+	_, err = builder.L2.EnsureTxSucceeded(tx)
+	Require(t, err)
+
+	posters, collectors, err := arbAggregator.GetAllFeeCollectors(callOpts)
+	Require(t, err)
+	if len(posters) != 3 || len(collectors) != 3 {
+		Fatal(t, "expected three batch posters and fee collectors, got", len(posters), len(collectors))
+	}
+	expectedCollectors := map[common.Address]common.Address{
+		l1pricing.BatchPosterAddress: l1pricing.BatchPosterPayToAddress,
+		addr:                         collector,
+		addr2:                        collector2,
+	}
+	for i, poster := range posters {
+		expected, ok := expectedCollectors[poster]
+		if !ok {
+			Fatal(t, "unexpected batch poster", poster)
+		}
+		if collectors[i] != expected {
+			Fatal(t, "expected batch poster", poster, "to have fee collector", expected, "got", collectors[i])
+		}
+	}
+
+	// a mismatched number of fee collectors is rejected
+	_, err = arbAggregator.SetFeeCollectors(&auth, []common.Address{addr, addr2}, []common.Address{collector})
+	if err == nil {
+		Fatal(t, "expected SetFeeCollectors with mismatched lengths to fail")
+	}
+}
+
+func TestBatchPosterRemoval(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	builder := NewNodeBuilder(ctx).DefaultConfig(t, false)
+	cleanup := builder.Build(t)
+	defer cleanup()
+
+	auth := builder.L2Info.GetDefaultTransactOpts("Owner", ctx)
+	callOpts := &bind.CallOpts{Context: ctx}
+
+	arbAggregator, err := precompilesgen.NewArbAggregator(types.ArbAggregatorAddress, builder.L2Client())
+	Require(t, err)
+	arbDebug, err := precompilesgen.NewArbDebug(types.ArbDebugAddress, builder.L2Client())
+	Require(t, err)
+
+	tx, err := arbDebug.BecomeChainOwner(&auth)
+	Require(t, err)
+	_, err = builder.L2.EnsureTxSucceeded(tx)
+	Require(t, err)
+
+	addr := common.BytesToAddress(crypto.Keccak256([]byte{5})[:20])
+	tx, err = arbAggregator.AddBatchPoster(&auth, addr)
+	Require(t, err)
+	_, err = builder.L2.EnsureTxSucceeded(tx)
+	Require(t, err)
+	bps, err := arbAggregator.GetBatchPosters(callOpts)
+	Require(t, err)
+	if len(bps) != 2 {
+		Fatal(t, "expected two batch posters, got", bps)
+	}
+
+	tx, err = arbAggregator.RemoveBatchPoster(&auth, addr)
+	Require(t, err)
+	_, err = builder.L2.EnsureTxSucceeded(tx)
+	Require(t, err)
+
+	bps, err = arbAggregator.GetBatchPosters(callOpts)
+	Require(t, err)
+	if len(bps) != 1 || bps[0] != l1pricing.BatchPosterAddress {
+		Fatal(t, "expected only the default batch poster after removal, got", bps)
+	}
+	feeCollector, err := arbAggregator.GetFeeCollector(callOpts, addr)
+	if err == nil {
+		Fatal(t, "expected the removed batch poster to have no fee collector, got", feeCollector)
+	}
+
+	// removing a batch poster that isn't one fails
+	_, err = arbAggregator.RemoveBatchPoster(&auth, addr)
+	if err == nil {
+		Fatal(t, "expected removing a batch poster twice to fail")
+	}
 }
 
 func TestArbAggregatorGetPreferredAggregator(t *testing.T) {
@@ -772,7 +1805,7 @@ func TestArbAggregatorGetPreferredAggregator(t *testing.T) {
 
 	callOpts := &bind.CallOpts{Context: ctx}
 
-	arbAggregator, err := precompilesgen.NewArbAggregator(types.ArbAggregatorAddress, builder.L2.Client)
+	arbAggregator, err := precompilesgen.NewArbAggregator(types.ArbAggregatorAddress, builder.L2Client())
 	Require(t, err)
 
 	addr := common.BytesToAddress(crypto.Keccak256([]byte{})[:20])
@@ -792,3 +1825,770 @@ func TestArbAggregatorGetPreferredAggregator(t *testing.T) {
 		Fatal(t, "expected default preferred aggregator to be", l1pricing.BatchPosterAddress, "got", prefAgg)
 	}
 }
+
+func TestGetArbGasToWeiRate(t *testing.T) {
+	t.Parallel()
+
+	builder, cleanup, _, _, arbGasInfo := setupArbOwnerAndArbGasInfo(t)
+	defer cleanup()
+	ctx := builder.ctx
+
+	checkRate := func() {
+		t.Helper()
+		header, err := builder.L2Client().HeaderByNumber(ctx, nil)
+		Require(t, err)
+		callOpts := &bind.CallOpts{Context: ctx, BlockNumber: header.Number}
+		rate, err := arbGasInfo.GetArbGasToWeiRate(callOpts)
+		Require(t, err)
+		_, _, _, _, _, perArbGasTotal, err := arbGasInfo.GetPricesInWei(callOpts)
+		Require(t, err)
+		if rate.Cmp(perArbGasTotal) != 0 {
+			Fatal(t, "expected ArbGas to wei rate", rate, "to match the total price per ArbGas", perArbGasTotal)
+		}
+		if rate.Cmp(header.BaseFee) != 0 {
+			Fatal(t, "expected ArbGas to wei rate", rate, "to match the block's base fee", header.BaseFee)
+		}
+	}
+
+	checkRate()
+	builder.L2.TransferBalance(t, "Owner", "Owner", common.Big1, builder.L2Info)
+	checkRate()
+}
+
+func TestArbGasInfoL2GasPrice(t *testing.T) {
+	t.Parallel()
+
+	builder, cleanup, auth, arbOwner, arbGasInfo := setupArbOwnerAndArbGasInfo(t)
+	defer cleanup()
+	ctx := builder.ctx
+
+	// above the initial base fee, but below the gas price test transactions offer
+	minimumBaseFee := arbmath.BigMulByFrac(big.NewInt(l2pricing.InitialBaseFeeWei), 3, 2)
+	tx, err := arbOwner.SetMinimumL2BaseFee(&auth, minimumBaseFee)
+	Require(t, err)
+	_, err = builder.L2.EnsureTxSucceeded(tx)
+	Require(t, err)
+	// mine a block priced with the new minimum
+	builder.L2.TransferBalance(t, "Owner", "Owner", common.Big1, builder.L2Info)
+
+	callOpts := &bind.CallOpts{Context: ctx}
+	minimumGasPrice, err := arbGasInfo.GetMinimumGasPrice(callOpts)
+	Require(t, err)
+	if minimumGasPrice.Cmp(minimumBaseFee) != 0 {
+		Fatal(t, "expected minimum gas price", minimumBaseFee, "got", minimumGasPrice)
+	}
+	l2GasPrice, err := arbGasInfo.GetArbGasToWeiRate(callOpts)
+	Require(t, err)
+	if l2GasPrice.Cmp(minimumBaseFee) < 0 {
+		Fatal(t, "expected L2 gas price", l2GasPrice, "to be at least the minimum", minimumBaseFee)
+	}
+	_, _, _, perArbGasBase, _, perArbGasTotal, err := arbGasInfo.GetPricesInWei(callOpts)
+	Require(t, err)
+	if perArbGasTotal.Cmp(minimumBaseFee) < 0 || perArbGasBase.Cmp(minimumBaseFee) != 0 {
+		Fatal(t, "expected prices in wei at or above the minimum", minimumBaseFee, "got base", perArbGasBase, "total", perArbGasTotal)
+	}
+}
+
+func TestArbGasInfoPricesInWei(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	builder := NewNodeBuilder(ctx).DefaultConfig(t, false)
+	cleanup := builder.Build(t)
+	defer cleanup()
+
+	arbGasInfo, err := precompilesgen.NewArbGasInfo(types.ArbGasInfoAddress, builder.L2Client())
+	Require(t, err)
+
+	// query a single block so every call sees the same prices
+	builder.L2.TransferBalance(t, "Owner", "Owner", common.Big1, builder.L2Info)
+	head, err := builder.L2Client().BlockNumber(ctx)
+	Require(t, err)
+	callOpts := &bind.CallOpts{Context: ctx, BlockNumber: new(big.Int).SetUint64(head)}
+
+	perL2Tx, perL1CalldataByte, perStorageAllocation, perArbGasBase, perArbGasCongestion, perArbGasTotal, err := arbGasInfo.GetPricesInWei(callOpts)
+	Require(t, err)
+	prices := map[string]*big.Int{
+		"perL2Tx":              perL2Tx,
+		"perL1CalldataByte":    perL1CalldataByte,
+		"perStorageAllocation": perStorageAllocation,
+		"perArbGasBase":        perArbGasBase,
+		"perArbGasCongestion":  perArbGasCongestion,
+		"perArbGasTotal":       perArbGasTotal,
+	}
+	for name, price := range prices {
+		if price == nil || price.Sign() < 0 {
+			Fatal(t, "expected", name, "to be non-negative, got", price)
+		}
+	}
+
+	l2GasPrice, err := arbGasInfo.GetArbGasToWeiRate(callOpts)
+	Require(t, err)
+	if perArbGasTotal.Cmp(l2GasPrice) < 0 {
+		Fatal(t, "expected the L2 price per ArbGas", perArbGasTotal, "to be at least the L2 gas price", l2GasPrice)
+	}
+	minimumGasPrice, err := arbGasInfo.GetMinimumGasPrice(callOpts)
+	Require(t, err)
+	if perArbGasTotal.Cmp(minimumGasPrice) < 0 {
+		Fatal(t, "expected the L2 price per ArbGas", perArbGasTotal, "to be at least the minimum gas price", minimumGasPrice)
+	}
+	if arbmath.BigAdd(perArbGasBase, perArbGasCongestion).Cmp(perArbGasTotal) != 0 {
+		Fatal(t, "expected base", perArbGasBase, "plus congestion", perArbGasCongestion, "to be the total", perArbGasTotal)
+	}
+}
+
+func TestPrecompileGasAudit(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	builder := NewNodeBuilder(ctx).DefaultConfig(t, false)
+	cleanup := builder.Build(t)
+	defer cleanup()
+
+	auth := builder.L2Info.GetDefaultTransactOpts("Owner", ctx)
+	arbDebug, err := precompilesgen.NewArbDebug(types.ArbDebugAddress, builder.L2Client())
+	Require(t, err)
+	arbGasInfoABI, err := precompilesgen.ArbGasInfoMetaData.GetAbi()
+	Require(t, err)
+	data, err := arbGasInfoABI.Pack("getPricesInWei")
+	Require(t, err)
+	arbGasInfoAddress := types.ArbGasInfoAddress
+
+	gasUsages := func() []*precompilesgen.ArbDebugPrecompileGasUsage {
+		t.Helper()
+		tx := builder.L2Info.PrepareTxTo("Owner", &arbGasInfoAddress, 500000, common.Big0, data)
+		Require(t, builder.L2Client().SendTransaction(ctx, tx))
+		receipt, err := builder.L2.EnsureTxSucceeded(tx)
+		Require(t, err)
+		var usages []*precompilesgen.ArbDebugPrecompileGasUsage
+		for _, log := range receipt.Logs {
+			if usage, err := arbDebug.ParsePrecompileGasUsage(*log); err == nil {
+				usages = append(usages, usage)
+			}
+		}
+		return usages
+	}
+	if usages := gasUsages(); len(usages) != 0 {
+		Fatal(t, "expected no gas usage logs before auditing is enabled, got", len(usages))
+	}
+
+	// only chain owners may enable auditing
+	builder.L2Info.GenerateAccount("User")
+	builder.L2.TransferBalance(t, "Owner", "User", big.NewInt(1e18), builder.L2Info)
+	userAuth := builder.L2Info.GetDefaultTransactOpts("User", ctx)
+	userAuth.GasLimit = 1_000_000
+	tx, err := arbDebug.SetPrecompileGasAudit(&userAuth, true)
+	Require(t, err)
+	EnsureTxFailed(t, ctx, builder.L2Client(), tx)
+
+	tx, err = arbDebug.BecomeChainOwner(&auth)
+	Require(t, err)
+	_, err = builder.L2.EnsureTxSucceeded(tx)
+	Require(t, err)
+	tx, err = arbDebug.SetPrecompileGasAudit(&auth, true)
+	Require(t, err)
+	_, err = builder.L2.EnsureTxSucceeded(tx)
+	Require(t, err)
+
+	usages := gasUsages()
+	if len(usages) != 1 {
+		Fatal(t, "expected a single gas usage log, got", len(usages))
+	}
+	usage := usages[0]
+	if usage.Precompile != arbGasInfoAddress || !bytes.Equal(usage.Method[:], data[:4]) {
+		Fatal(t, "unexpected gas usage log for", usage.Precompile, usage.Method)
+	}
+	if usage.StorageReads == 0 || usage.StorageWrites != 0 {
+		Fatal(t, "expected getPricesInWei to only read storage, got", usage.StorageReads, "reads and", usage.StorageWrites, "writes")
+	}
+	if usage.GasCharged < usage.StorageReads*params.SloadGas {
+		Fatal(t, "getPricesInWei charged", usage.GasCharged, "gas for", usage.StorageReads, "storage reads")
+	}
+
+	tx, err = arbDebug.SetPrecompileGasAudit(&auth, false)
+	Require(t, err)
+	_, err = builder.L2.EnsureTxSucceeded(tx)
+	Require(t, err)
+	if usages := gasUsages(); len(usages) != 0 {
+		Fatal(t, "expected no gas usage logs after auditing is disabled, got", len(usages))
+	}
+}
+
+func TestArbGasInfoGetGasPriceForBacklog(t *testing.T) {
+	t.Parallel()
+
+	builder, cleanup, auth, arbOwner, arbGasInfo := setupArbOwnerAndArbGasInfo(t)
+	defer cleanup()
+	ctx := builder.ctx
+
+	inertia := uint64(50)
+	tolerance := uint64(5)
+	tx, err := arbOwner.SetL2GasPricingInertia(&auth, inertia)
+	Require(t, err)
+	_, err = builder.L2.EnsureTxSucceeded(tx)
+	Require(t, err)
+	tx, err = arbOwner.SetL2GasBacklogTolerance(&auth, tolerance)
+	Require(t, err)
+	_, err = builder.L2.EnsureTxSucceeded(tx)
+	Require(t, err)
+
+	callOpts := &bind.CallOpts{Context: ctx}
+	speedLimitBig, _, _, err := arbGasInfo.GetGasAccountingParams(callOpts)
+	Require(t, err)
+	speedLimit := speedLimitBig.Uint64()
+	minBaseFee, err := arbGasInfo.GetMinimumGasPrice(callOpts)
+	Require(t, err)
+
+	expectedPrice := func(backlog uint64) *big.Int {
+		if backlog <= tolerance*speedLimit {
+			return minBaseFee
+		}
+		excess := arbmath.SaturatingCast[int64](backlog - tolerance*speedLimit)
+		exponentBips := arbmath.NaturalToBips(excess) / arbmath.SaturatingCast[arbmath.Bips](inertia*speedLimit)
+		return arbmath.BigMulByBips(minBaseFee, arbmath.ApproxExpBasisPoints(exponentBips, 4))
+	}
+
+	backlogs := []uint64{
+		0,
+		tolerance * speedLimit,
+		tolerance*speedLimit + 1,
+		(tolerance + 1) * speedLimit,
+		(tolerance + inertia) * speedLimit,
+		(tolerance + 4*inertia) * speedLimit,
+	}
+	for _, backlog := range backlogs {
+		price, err := arbGasInfo.GetGasPriceForBacklog(callOpts, backlog)
+		Require(t, err)
+		if expected := expectedPrice(backlog); !arbmath.BigEquals(price, expected) {
+			Fatal(t, "wrong gas price for backlog", backlog, "expected", expected, "got", price)
+		}
+	}
+	price, err := arbGasInfo.GetGasPriceForBacklog(callOpts, (tolerance+inertia)*speedLimit)
+	Require(t, err)
+	if !arbmath.BigGreaterThan(price, minBaseFee) {
+		Fatal(t, "expected a backlog beyond the tolerance to raise the price above", minBaseFee, "got", price)
+	}
+}
+
+func TestL1PricingDataGasFactor(t *testing.T) {
+	t.Parallel()
+
+	builder, cleanup, auth, arbOwner, arbGasInfo := setupArbOwnerAndArbGasInfo(t)
+	defer cleanup()
+	ctx := builder.ctx
+	callOpts := &bind.CallOpts{Context: ctx}
+
+	// fix the price per unit at the minimum base fee, so fees only change with the factor
+	minBaseFee, err := arbGasInfo.GetMinimumGasPrice(callOpts)
+	Require(t, err)
+	tx, err := arbOwner.SetL1PricePerUnit(&auth, minBaseFee)
+	Require(t, err)
+	_, err = builder.L2.EnsureTxSucceeded(tx)
+	Require(t, err)
+
+	setFactor := func(factor uint64) {
+		t.Helper()
+		tx, err := arbOwner.SetL1PricingDataGasFactor(&auth, factor)
+		Require(t, err)
+		_, err = builder.L2.EnsureTxSucceeded(tx)
+		Require(t, err)
+		got, err := arbGasInfo.GetL1PricingDataGasFactor(callOpts)
+		Require(t, err)
+		if got != factor {
+			Fatal(t, "set the data gas factor to", factor, "but got", got)
+		}
+	}
+	// random data doesn't compress, so every tx is charged for about the same units
+	data := testhelpers.RandomSlice(1024)
+	l1Fee := func() *big.Int {
+		t.Helper()
+		tx := builder.L2Info.PrepareTx("Faucet", "Faucet", 1e6, common.Big0, data)
+		Require(t, builder.L2Client().SendTransaction(ctx, tx))
+		receipt, err := builder.L2.EnsureTxSucceeded(tx)
+		Require(t, err)
+		header, err := builder.L2Client().HeaderByHash(ctx, receipt.BlockHash)
+		Require(t, err)
+		return arbmath.BigMulByUint(header.BaseFee, receipt.GasUsedForL1)
+	}
+
+	fullFee := l1Fee()
+	if fullFee.Sign() <= 0 {
+		Fatal(t, "expected an L1 fee without a data gas factor, got", fullFee)
+	}
+	for _, factor := range []uint64{10000, 5000, 2500, 0} {
+		setFactor(factor)
+		fee := l1Fee()
+		expected := fullFee
+		if factor != 0 {
+			// #nosec G115
+			expected = arbmath.BigMulByBips(fullFee, arbmath.Bips(factor))
+		}
+		// allow for the txs differing slightly in size
+		tolerance := arbmath.BigDivByUint(expected, 100)
+		if arbmath.BigGreaterThan(arbmath.BigAbs(arbmath.BigSub(fee, expected)), tolerance) {
+			Fatal(t, "with a data gas factor of", factor, "expected an L1 fee of about", expected, "got", fee)
+		}
+	}
+
+	_, err = arbOwner.SetL1PricingDataGasFactor(&auth, uint64(arbmath.OneInBips)+1)
+	if err == nil {
+		Fatal(t, "expected a data gas factor above one in bips to be rejected")
+	}
+}
+
+func TestTipDistribution(t *testing.T) {
+	t.Parallel()
+
+	builder, cleanup, auth, arbOwner, arbGasInfo := setupArbOwnerAndArbGasInfo(t)
+	defer cleanup()
+	ctx := builder.ctx
+	callOpts := &bind.CallOpts{Context: ctx}
+
+	recipients := []common.Address{testhelpers.RandomAddress(), testhelpers.RandomAddress(), testhelpers.RandomAddress()}
+	shares := []uint64{5000, 3333, 1667}
+	tx, err := arbOwner.SetTipDistribution(&auth, recipients, shares)
+	Require(t, err)
+	receipt, err := builder.L2.EnsureTxSucceeded(tx)
+	Require(t, err)
+	var setEvent *precompilesgen.ArbOwnerTipDistributionSet
+	for _, log := range receipt.Logs {
+		if event, err := arbOwner.ParseTipDistributionSet(*log); err == nil {
+			setEvent = event
+		}
+	}
+	if setEvent == nil || !reflect.DeepEqual(setEvent.Recipients, recipients) || !reflect.DeepEqual(setEvent.Bips, shares) {
+		Fatal(t, "expected a TipDistributionSet event with the new table, got", setEvent)
+	}
+	table, err := arbGasInfo.GetTipDistribution(callOpts)
+	Require(t, err)
+	if !reflect.DeepEqual(table.Recipients, recipients) || !reflect.DeepEqual(table.Bips, shares) {
+		Fatal(t, "ArbGasInfo reports table", table.Recipients, table.Bips, "expected", recipients, shares)
+	}
+
+	_, err = arbOwner.SetTipDistribution(&auth, recipients, []uint64{5000, 3333, 1666})
+	if err == nil {
+		Fatal(t, "expected shares not summing to 10000 bips to be rejected")
+	}
+
+	// send a tx paying a tip
+	baseFee := builder.L2.GetBaseFee(t)
+	tipCap := arbmath.BigMulByUint(baseFee, 2)
+	faucet := builder.L2Info.GetAddress("Faucet")
+	nonce, err := builder.L2Client().PendingNonceAt(ctx, faucet)
+	Require(t, err)
+	destination := testhelpers.RandomAddress()
+	tx = builder.L2Info.SignTxAs("Faucet", &types.DynamicFeeTx{
+		ChainID:   builder.chainConfig.ChainID,
+		Nonce:     nonce,
+		To:        &destination,
+		Value:     big.NewInt(1e12),
+		Gas:       builder.L2Info.TransferGas,
+		GasTipCap: tipCap,
+		GasFeeCap: arbmath.BigMulByUint(baseFee, 4),
+	})
+	Require(t, builder.L2Client().SendTransaction(ctx, tx))
+	receipt, err = builder.L2.EnsureTxSucceeded(tx)
+	Require(t, err)
+	header, err := builder.L2Client().HeaderByHash(ctx, receipt.BlockHash)
+	Require(t, err)
+
+	tipPerGas := arbmath.BigSub(receipt.EffectiveGasPrice, header.BaseFee)
+	tips := arbmath.BigMulByUint(tipPerGas, receipt.GasUsed)
+	if tips.Sign() <= 0 {
+		Fatal(t, "expected the tx to pay a tip, paid", receipt.EffectiveGasPrice, "per gas with base fee", header.BaseFee)
+	}
+	paid := new(big.Int)
+	for i, recipient := range recipients {
+		balance := builder.L2.GetBalance(t, recipient)
+		// #nosec G115
+		expected := arbmath.BigMulByBips(tips, arbmath.Bips(shares[i]))
+		// the first recipient also gets the rounding remainder, which is less than a wei per recipient
+		if arbmath.BigLessThan(balance, expected) || arbmath.BigGreaterThan(balance, arbmath.BigAddByUint(expected, uint64(len(recipients)))) {
+			Fatal(t, "recipient", i, "with a share of", shares[i], "bips of", tips, "expected", expected, "got", balance)
+		}
+		paid.Add(paid, balance)
+	}
+	if !arbmath.BigEquals(paid, tips) {
+		Fatal(t, "expected all", tips, "of the tips to be paid out, paid", paid)
+	}
+	if pool := builder.L2.GetBalance(t, tipdistribution.PoolAddress); pool.Sign() != 0 {
+		Fatal(t, "expected the tip pool to be emptied, has", pool)
+	}
+}
+
+func TestL2ChainNamespace(t *testing.T) {
+	t.Parallel()
+
+	builder, cleanup, auth, arbOwner, _ := setupArbOwnerAndArbGasInfo(t)
+	defer cleanup()
+	ctx := builder.ctx
+	callOpts := &bind.CallOpts{Context: ctx}
+	arbOwnerPublic, err := precompilesgen.NewArbOwnerPublic(types.ArbOwnerPublicAddress, builder.L2Client())
+	Require(t, err)
+
+	namespace, err := arbOwnerPublic.GetL2ChainNamespace(callOpts)
+	Require(t, err)
+	if namespace != "" {
+		Fatal(t, "expected no namespace before one is set, got", namespace)
+	}
+
+	const expected = "l3.example"
+	tx, err := arbOwner.SetL2ChainNamespace(&auth, expected)
+	Require(t, err)
+	_, err = builder.L2.EnsureTxSucceeded(tx)
+	Require(t, err)
+	namespace, err = arbOwnerPublic.GetL2ChainNamespace(callOpts)
+	Require(t, err)
+	if namespace != expected {
+		Fatal(t, "expected namespace", expected, "got", namespace)
+	}
+
+	var version gethexec.NodeVersion
+	Require(t, builder.L2Client().Client().CallContext(ctx, &version, "arb_getNodeVersion"))
+	if version.ChainNamespace != expected {
+		Fatal(t, "arb_getNodeVersion reports namespace", version.ChainNamespace, "expected", expected)
+	}
+	if version.Version == "" || version.ArbOSVersion != builder.chainConfig.ArbitrumChainParams.InitialArbOSVersion {
+		Fatal(t, "unexpected arb_getNodeVersion response", version)
+	}
+
+	_, err = arbOwner.SetL2ChainNamespace(&auth, strings.Repeat("a", 33))
+	if err == nil {
+		Fatal(t, "expected a namespace longer than 32 bytes to be rejected")
+	}
+}
+
+func TestArbOwnerSetChainConfig(t *testing.T) {
+	t.Parallel()
+
+	builder, cleanup, auth, arbOwner, _ := setupArbOwnerAndArbGasInfo(t)
+	defer cleanup()
+	callOpts := &bind.CallOpts{Context: builder.ctx}
+	arbOwnerPublic, err := precompilesgen.NewArbOwnerPublic(types.ArbOwnerPublicAddress, builder.L2Client())
+	Require(t, err)
+
+	serialize := func(modify func(*params.ChainConfig)) string {
+		chainConfig := chaininfo.CopyChainConfig(builder.chainConfig)
+		modify(chainConfig)
+		serialized, err := json.Marshal(chainConfig)
+		Require(t, err)
+		return string(serialized)
+	}
+	expectInvalid := func(description string, serialized string, reason string) {
+		t.Helper()
+		_, err := arbOwner.SetChainConfig(&auth, serialized)
+		if err == nil || !strings.Contains(err.Error(), "InvalidChainConfig") || !strings.Contains(err.Error(), reason) {
+			Fatal(t, "expected", description, "to revert with InvalidChainConfig for", reason, "got", err)
+		}
+	}
+
+	expectInvalid("malformed json", "{\"chainId\":", "can't deserialize")
+	expectInvalid("a chain id mismatch", serialize(func(config *params.ChainConfig) {
+		config.ChainID = arbmath.BigAddByUint(config.ChainID, 1)
+	}), "chain id mismatch")
+	withMaxCodeSize := serialize(func(config *params.ChainConfig) {
+		config.ArbitrumChainParams.MaxCodeSize = params.DefaultMaxCodeSize * 2
+	})
+	unknownParam := strings.Replace(withMaxCodeSize, `"arbitrum":{`, `"arbitrum":{"UnknownParam":true,`, 1)
+	expectInvalid("an unknown arbitrum param", unknownParam, "unknown arbitrum params")
+
+	tx, err := arbOwner.SetChainConfig(&auth, withMaxCodeSize)
+	Require(t, err)
+	_, err = builder.L2.EnsureTxSucceeded(tx)
+	Require(t, err)
+	stored, err := arbOwnerPublic.GetChainConfig(callOpts)
+	Require(t, err)
+	if string(stored) != withMaxCodeSize {
+		Fatal(t, "ArbOwnerPublic reports chain config", stored, "expected", withMaxCodeSize)
+	}
+
+	// without gas estimation, only the checks against ArbOS state decide whether the transaction reverts
+	auth.GasLimit = 1_000_000
+	tx, err = arbOwner.SetChainConfig(&auth, serialize(func(config *params.ChainConfig) {
+		config.ChainID = arbmath.BigAddByUint(config.ChainID, 1)
+	}))
+	Require(t, err)
+	EnsureTxFailed(t, builder.ctx, builder.L2Client(), tx)
+	unknownParam = strings.Replace(withMaxCodeSize, `"arbitrum":{`, `"arbitrum":{"UnknownParam":false,`, 1)
+	tx, err = arbOwner.SetChainConfig(&auth, unknownParam)
+	Require(t, err)
+	_, err = builder.L2.EnsureTxSucceeded(tx)
+	Require(t, err)
+}
+
+func TestArbOwnerSetChainConfigRejectedWithWrongChainID(t *testing.T) {
+	t.Parallel()
+
+	builder, cleanup, _, arbOwner, _ := setupArbOwnerAndArbGasInfo(t)
+	defer cleanup()
+	ctx := builder.ctx
+	callOpts := &bind.CallOpts{Context: ctx}
+	arbOwnerPublic, err := precompilesgen.NewArbOwnerPublic(types.ArbOwnerPublicAddress, builder.L2Client())
+	Require(t, err)
+	storedBefore, err := arbOwnerPublic.GetChainConfig(callOpts)
+	Require(t, err)
+
+	chainConfig := chaininfo.CopyChainConfig(builder.chainConfig)
+	chainConfig.ChainID = big.NewInt(999999)
+	if chainConfig.ChainID.Cmp(builder.chainConfig.ChainID) == 0 {
+		Fatal(t, "test chain unexpectedly has chain id", chainConfig.ChainID)
+	}
+	serialized, err := json.Marshal(chainConfig)
+	Require(t, err)
+
+	// gas estimation surfaces the revert reason
+	auth := builder.L2Info.GetDefaultTransactOpts("Owner", ctx)
+	_, err = arbOwner.SetChainConfig(&auth, string(serialized))
+	if err == nil || !strings.Contains(err.Error(), "chain id mismatch") {
+		Fatal(t, "expected setting a config of another chain to revert with a chain id mismatch, got", err)
+	}
+
+	// skip gas estimation to get the failed tx on chain
+	auth.GasLimit = 1_000_000
+	tx, err := arbOwner.SetChainConfig(&auth, string(serialized))
+	Require(t, err)
+	EnsureTxFailed(t, ctx, builder.L2Client(), tx)
+
+	storedAfter, err := arbOwnerPublic.GetChainConfig(callOpts)
+	Require(t, err)
+	if !bytes.Equal(storedAfter, storedBefore) {
+		Fatal(t, "rejected chain config changed the stored one to", string(storedAfter))
+	}
+}
+
+func TestArbOwnerMulticall(t *testing.T) {
+	t.Parallel()
+
+	builder, cleanup, auth, arbOwner, arbGasInfo := setupArbOwnerAndArbGasInfo(t)
+	defer cleanup()
+	ctx := builder.ctx
+	callOpts := &bind.CallOpts{Context: ctx}
+
+	arbOwnerABI, err := precompilesgen.ArbOwnerMetaData.GetAbi()
+	Require(t, err)
+	pack := func(method string, args ...interface{}) []byte {
+		data, err := arbOwnerABI.Pack(method, args...)
+		Require(t, err)
+		return data
+	}
+
+	speedLimit := uint64(18)
+	txGasLimit := uint64(19)
+	inertia := uint64(20)
+	calls := [][]byte{
+		pack("setSpeedLimit", speedLimit),
+		pack("setMaxTxGasLimit", txGasLimit),
+		pack("setL2GasPricingInertia", inertia),
+	}
+	tx, err := arbOwner.Multicall(&auth, calls)
+	Require(t, err)
+	receipt, err := builder.L2.EnsureTxSucceeded(tx)
+	Require(t, err)
+
+	arbGasInfoSpeedLimit, _, arbGasInfoTxGasLimit, err := arbGasInfo.GetGasAccountingParams(callOpts)
+	Require(t, err)
+	// #nosec G115
+	if arbGasInfoSpeedLimit.Cmp(big.NewInt(int64(speedLimit))) != 0 {
+		Fatal(t, "expected speed limit to be", speedLimit, "got", arbGasInfoSpeedLimit)
+	}
+	// #nosec G115
+	if arbGasInfoTxGasLimit.Cmp(big.NewInt(int64(txGasLimit))) != 0 {
+		Fatal(t, "expected tx gas limit to be", txGasLimit, "got", arbGasInfoTxGasLimit)
+	}
+	arbGasInfoInertia, err := arbGasInfo.GetPricingInertia(callOpts)
+	Require(t, err)
+	if arbGasInfoInertia != inertia {
+		Fatal(t, "expected inertia to be", inertia, "got", arbGasInfoInertia)
+	}
+
+	// each inner call emits its OwnerActs event in order, followed by the multicall's own
+	expectedCalls := [][]byte{calls[0], calls[1], calls[2], pack("multicall", calls)}
+	var events []*precompilesgen.ArbOwnerOwnerActs
+	for _, log := range receipt.Logs {
+		event, err := arbOwner.ParseOwnerActs(*log)
+		if err == nil {
+			events = append(events, event)
+		}
+	}
+	if len(events) != len(expectedCalls) {
+		Fatal(t, "expected", len(expectedCalls), "OwnerActs events, got", len(events))
+	}
+	for i, event := range events {
+		if event.Owner != auth.From {
+			Fatal(t, "OwnerActs event", i, "has owner", event.Owner, "expected", auth.From)
+		}
+		if !bytes.Equal(event.Data, expectedCalls[i]) {
+			Fatal(t, "OwnerActs event", i, "has data", event.Data, "expected", expectedCalls[i])
+		}
+		if !bytes.Equal(event.Method[:], expectedCalls[i][:4]) {
+			Fatal(t, "OwnerActs event", i, "has method", event.Method, "expected", expectedCalls[i][:4])
+		}
+	}
+
+	// a failing call reverts the calls before it
+	nonOwner := common.BytesToAddress(crypto.Keccak256([]byte{})[:20])
+	failingCalls := [][]byte{
+		pack("setSpeedLimit", speedLimit+1),
+		pack("removeChainOwner", nonOwner),
+	}
+	auth.GasLimit = 1_000_000
+	tx, err = arbOwner.Multicall(&auth, failingCalls)
+	Require(t, err)
+	EnsureTxFailed(t, ctx, builder.L2Client(), tx)
+	arbGasInfoSpeedLimit, _, _, err = arbGasInfo.GetGasAccountingParams(callOpts)
+	Require(t, err)
+	// #nosec G115
+	if arbGasInfoSpeedLimit.Cmp(big.NewInt(int64(speedLimit))) != 0 {
+		Fatal(t, "expected failed multicall to leave speed limit at", speedLimit, "got", arbGasInfoSpeedLimit)
+	}
+
+	// multicalls can't be nested
+	tx, err = arbOwner.Multicall(&auth, [][]byte{pack("multicall", calls)})
+	Require(t, err)
+	EnsureTxFailed(t, ctx, builder.L2Client(), tx)
+}
+
+func TestArbosTestStorageSlots(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	builder := NewNodeBuilder(ctx).DefaultConfig(t, false)
+	cleanup := builder.Build(t)
+	defer cleanup()
+
+	auth := builder.L2Info.GetDefaultTransactOpts("Owner", ctx)
+	arbosTest, err := precompilesgen.NewArbosTest(types.ArbosTestAddress, builder.L2Client())
+	Require(t, err)
+
+	// 10k slots don't fit in a single transaction, so they're written in batches with different seeds
+	const batches = 10
+	const slotsPerBatch = 1000
+	seed := func(batch int) [32]byte {
+		return crypto.Keccak256Hash([]byte{byte(batch)})
+	}
+
+	emptyRoot := getStorageRootHash(t, builder.L2.ExecNode, types.ArbosTestAddress)
+	for batch := 0; batch < batches; batch++ {
+		tx, err := arbosTest.WriteStorageSlots(&auth, slotsPerBatch, seed(batch))
+		Require(t, err)
+		receipt, err := builder.L2.EnsureTxSucceeded(tx)
+		Require(t, err)
+		l2Used := receipt.GasUsed - receipt.GasUsedForL1
+		if minCost := slotsPerBatch * (params.ColdSloadCostEIP2929 + params.SstoreSetGasEIP2200); l2Used < minCost {
+			Fatal(t, "writing", slotsPerBatch, "slots used", l2Used, "gas, expected at least", minCost)
+		}
+	}
+	filledRoot := getStorageRootHash(t, builder.L2.ExecNode, types.ArbosTestAddress)
+	if filledRoot == emptyRoot {
+		Fatal(t, "writing storage slots didn't change the storage root")
+	}
+	slots := getStorageSlotValue(t, builder.L2.ExecNode, types.ArbosTestAddress)
+	if len(slots) != batches*slotsPerBatch {
+		Fatal(t, "expected", batches*slotsPerBatch, "storage slots, got", len(slots))
+	}
+
+	// only chain owners may write slots
+	builder.L2Info.GenerateAccount("User")
+	builder.L2.TransferBalance(t, "Owner", "User", big.NewInt(1e18), builder.L2Info)
+	userAuth := builder.L2Info.GetDefaultTransactOpts("User", ctx)
+	_, err = arbosTest.WriteStorageSlots(&userAuth, 1, seed(batches))
+	if err == nil {
+		Fatal(t, "expected WriteStorageSlots from a non-owner to fail")
+	}
+
+	for batch := 0; batch < batches; batch++ {
+		tx, err := arbosTest.ClearStorageSlots(&auth, slotsPerBatch, seed(batch))
+		Require(t, err)
+		receipt, err := builder.L2.EnsureTxSucceeded(tx)
+		Require(t, err)
+		// clearing refunds gas, capped at a fifth of the gas used
+		clearCost := slotsPerBatch * params.SstoreResetGasEIP2200
+		l2Used := receipt.GasUsed - receipt.GasUsedForL1
+		if l2Used >= clearCost || l2Used < clearCost*4/5 {
+			Fatal(t, "clearing", slotsPerBatch, "slots used", l2Used, "gas, expected a refund of at most a fifth of", clearCost)
+		}
+	}
+	clearedRoot := getStorageRootHash(t, builder.L2.ExecNode, types.ArbosTestAddress)
+	if clearedRoot != emptyRoot {
+		Fatal(t, "expected clearing every written slot to restore the storage root", emptyRoot, "got", clearedRoot)
+	}
+}
+
+// mockPrecompile returns the hash of its input, recording who called it with what
+type mockPrecompile struct {
+	mutex   sync.Mutex
+	callers []common.Address
+	inputs  [][]byte
+}
+
+func (p *mockPrecompile) Call(
+	input []byte,
+	precompileAddress common.Address,
+	actingAsAddress common.Address,
+	caller common.Address,
+	value *big.Int,
+	readOnly bool,
+	gasSupplied uint64,
+	evm *vm.EVM,
+) ([]byte, uint64, error) {
+	const cost = 1000
+	if gasSupplied < cost {
+		return nil, 0, vm.ErrOutOfGas
+	}
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	p.callers = append(p.callers, caller)
+	p.inputs = append(p.inputs, common.CopyBytes(input))
+	return crypto.Keccak256(input), gasSupplied - cost, nil
+}
+
+func (p *mockPrecompile) calledBy(caller common.Address, input []byte) bool {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	for i := range p.callers {
+		if p.callers[i] == caller && bytes.Equal(p.inputs[i], input) {
+			return true
+		}
+	}
+	return false
+}
+
+func TestExtraPrecompile(t *testing.T) {
+	t.Parallel()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	precompileAddr := common.HexToAddress("0x0000000000000000000000000000000000a4b999")
+	precompile := &mockPrecompile{}
+	builder := NewNodeBuilder(ctx).DefaultConfig(t, false).WithExtraPrecompile(precompileAddr, precompile)
+	cleanup := builder.Build(t)
+	defer cleanup()
+
+	input := []byte("a precompile from the future")
+	output, err := builder.L2Client().CallContract(ctx, ethereum.CallMsg{To: &precompileAddr, Data: input}, nil)
+	Require(t, err)
+	if !bytes.Equal(output, crypto.Keccak256(input)) {
+		Fatal(t, "expected the precompile to return", hexutil.Bytes(crypto.Keccak256(input)), "got", hexutil.Bytes(output))
+	}
+
+	auth := builder.L2Info.GetDefaultTransactOpts("Owner", ctx)
+	multiCallAddr, tx, _, err := mocksgen.DeployMultiCallTest(&auth, builder.L2Client())
+	Require(t, err)
+	_, err = builder.L2.EnsureTxSucceeded(tx)
+	Require(t, err)
+
+	args := argsForMulticall(vm.CALL, precompileAddr, nil, input)
+	tx = builder.L2Info.PrepareTxTo("Owner", &multiCallAddr, 500000, big.NewInt(0), args)
+	Require(t, builder.L2Client().SendTransaction(ctx, tx))
+	_, err = builder.L2.EnsureTxSucceeded(tx)
+	Require(t, err)
+	if !precompile.calledBy(multiCallAddr, input) {
+		Fatal(t, "expected the contract to have called the extra precompile")
+	}
+}
@@ -53,12 +53,12 @@ func checkInkUsage(
 	const txGas uint64 = 32_000_000
 	tx := builder.L2Info.PrepareTxTo("Owner", &stylusProgram, txGas, nil, data)
 
-	err := builder.L2.Client.SendTransaction(builder.ctx, tx)
+	err := builder.L2Client().SendTransaction(builder.ctx, tx)
 	Require(t, err, "testName", testName)
 	_, err = builder.L2.EnsureTxSucceeded(tx)
 	Require(t, err, "testName", testName)
 
-	stylusGasUsage, err := stylusHostiosGasUsage(builder.ctx, builder.L2.Client.Client(), tx)
+	stylusGasUsage, err := stylusHostiosGasUsage(builder.ctx, builder.L2Client().Client(), tx)
 	Require(t, err, "testName", testName)
 
 	_, ok := stylusGasUsage[hostio]
@@ -82,7 +82,7 @@ func TestWriteResultGasUsage(t *testing.T) {
 
 	builder := setupGasCostTest(t)
 	auth := builder.L2Info.GetDefaultTransactOpts("Owner", builder.ctx)
-	stylusProgram := deployWasm(t, builder.ctx, auth, builder.L2.Client, rustFile("hostio-test"))
+	stylusProgram := deployWasm(t, builder.ctx, auth, builder.L2Client(), rustFile("hostio-test"))
 
 	hostio := "write_result"
 
@@ -112,7 +112,7 @@ func TestReadArgsGasUsage(t *testing.T) {
 
 	builder := setupGasCostTest(t)
 	auth := builder.L2Info.GetDefaultTransactOpts("Owner", builder.ctx)
-	stylusProgram := deployWasm(t, builder.ctx, auth, builder.L2.Client, rustFile("hostio-test"))
+	stylusProgram := deployWasm(t, builder.ctx, auth, builder.L2Client(), rustFile("hostio-test"))
 
 	hostio := "read_args"
 
@@ -138,7 +138,7 @@ func TestMsgReentrantGasUsage(t *testing.T) {
 
 	builder := setupGasCostTest(t)
 	auth := builder.L2Info.GetDefaultTransactOpts("Owner", builder.ctx)
-	stylusProgram := deployWasm(t, builder.ctx, auth, builder.L2.Client, rustFile("hostio-test"))
+	stylusProgram := deployWasm(t, builder.ctx, auth, builder.L2Client(), rustFile("hostio-test"))
 
 	hostio := "msg_reentrant"
 
@@ -153,7 +153,7 @@ func TestStorageCacheBytes32GasUsage(t *testing.T) {
 
 	builder := setupGasCostTest(t)
 	auth := builder.L2Info.GetDefaultTransactOpts("Owner", builder.ctx)
-	stylusProgram := deployWasm(t, builder.ctx, auth, builder.L2.Client, rustFile("hostio-test"))
+	stylusProgram := deployWasm(t, builder.ctx, auth, builder.L2Client(), rustFile("hostio-test"))
 
 	hostio := "storage_cache_bytes32"
 
@@ -168,7 +168,7 @@ func TestPayForMemoryGrowGasUsage(t *testing.T) {
 
 	builder := setupGasCostTest(t)
 	auth := builder.L2Info.GetDefaultTransactOpts("Owner", builder.ctx)
-	stylusProgram := deployWasm(t, builder.ctx, auth, builder.L2.Client, rustFile("hostio-test"))
+	stylusProgram := deployWasm(t, builder.ctx, auth, builder.L2Client(), rustFile("hostio-test"))
 
 	hostio := "pay_for_memory_grow"
 	signature := "payForMemoryGrow(uint256)"
@@ -185,9 +185,9 @@ func TestPayForMemoryGrowGasUsage(t *testing.T) {
 func TestProgramSimpleCost(t *testing.T) {
 	builder := setupGasCostTest(t)
 	auth := builder.L2Info.GetDefaultTransactOpts("Owner", builder.ctx)
-	stylusProgram := deployWasm(t, builder.ctx, auth, builder.L2.Client, rustFile("hostio-test"))
-	evmProgram := deployEvmContract(t, builder.ctx, auth, builder.L2.Client, mocksgen.HostioTestMetaData)
-	otherProgram := deployWasm(t, builder.ctx, auth, builder.L2.Client, rustFile("storage"))
+	stylusProgram := deployWasm(t, builder.ctx, auth, builder.L2Client(), rustFile("hostio-test"))
+	evmProgram := deployEvmContract(t, builder.ctx, auth, builder.L2Client(), mocksgen.HostioTestMetaData)
+	otherProgram := deployWasm(t, builder.ctx, auth, builder.L2Client(), rustFile("storage"))
 	matchSnake := regexp.MustCompile("_[a-z]")
 
 	for _, tc := range []struct {
@@ -238,8 +238,8 @@ func TestProgramSimpleCost(t *testing.T) {
 func TestProgramPowCost(t *testing.T) {
 	builder := setupGasCostTest(t)
 	auth := builder.L2Info.GetDefaultTransactOpts("Owner", builder.ctx)
-	stylusProgram := deployWasm(t, builder.ctx, auth, builder.L2.Client, rustFile("hostio-test"))
-	evmProgram := deployEvmContract(t, builder.ctx, auth, builder.L2.Client, mocksgen.HostioTestMetaData)
+	stylusProgram := deployWasm(t, builder.ctx, auth, builder.L2Client(), rustFile("hostio-test"))
+	evmProgram := deployEvmContract(t, builder.ctx, auth, builder.L2Client(), mocksgen.HostioTestMetaData)
 	packer, _ := util.NewCallParser(mocksgen.HostioTestABI, "mathPow")
 
 	for _, exponentNumBytes := range []uint{1, 2, 10, 32} {
@@ -264,8 +264,8 @@ func TestProgramPowCost(t *testing.T) {
 func TestProgramStorageCost(t *testing.T) {
 	builder := setupGasCostTest(t)
 	auth := builder.L2Info.GetDefaultTransactOpts("Owner", builder.ctx)
-	stylusMulticall := deployWasm(t, builder.ctx, auth, builder.L2.Client, rustFile("multicall"))
-	evmMulticall := deployEvmContract(t, builder.ctx, auth, builder.L2.Client, mocksgen.MultiCallTestMetaData)
+	stylusMulticall := deployWasm(t, builder.ctx, auth, builder.L2Client(), rustFile("multicall"))
+	evmMulticall := deployEvmContract(t, builder.ctx, auth, builder.L2Client(), mocksgen.MultiCallTestMetaData)
 
 	const numSlots = 42
 	rander := testhelpers.NewPseudoRandomDataSource(t, 0)
@@ -305,8 +305,8 @@ func TestProgramStorageCost(t *testing.T) {
 func TestProgramLogCost(t *testing.T) {
 	builder := setupGasCostTest(t)
 	auth := builder.L2Info.GetDefaultTransactOpts("Owner", builder.ctx)
-	stylusProgram := deployWasm(t, builder.ctx, auth, builder.L2.Client, rustFile("hostio-test"))
-	evmProgram := deployEvmContract(t, builder.ctx, auth, builder.L2.Client, mocksgen.HostioTestMetaData)
+	stylusProgram := deployWasm(t, builder.ctx, auth, builder.L2Client(), rustFile("hostio-test"))
+	evmProgram := deployEvmContract(t, builder.ctx, auth, builder.L2Client(), mocksgen.HostioTestMetaData)
 	packer, _ := util.NewCallParser(mocksgen.HostioTestABI, "emitLog")
 
 	for ntopics := int8(0); ntopics < 5; ntopics++ {
@@ -333,10 +333,10 @@ func TestProgramLogCost(t *testing.T) {
 func TestProgramCallCost(t *testing.T) {
 	builder := setupGasCostTest(t)
 	auth := builder.L2Info.GetDefaultTransactOpts("Owner", builder.ctx)
-	stylusMulticall := deployWasm(t, builder.ctx, auth, builder.L2.Client, rustFile("multicall"))
-	evmMulticall := deployEvmContract(t, builder.ctx, auth, builder.L2.Client, mocksgen.MultiCallTestMetaData)
-	otherStylusProgram := deployWasm(t, builder.ctx, auth, builder.L2.Client, rustFile("hostio-test"))
-	otherEvmProgram := deployEvmContract(t, builder.ctx, auth, builder.L2.Client, mocksgen.HostioTestMetaData)
+	stylusMulticall := deployWasm(t, builder.ctx, auth, builder.L2Client(), rustFile("multicall"))
+	evmMulticall := deployEvmContract(t, builder.ctx, auth, builder.L2Client(), mocksgen.MultiCallTestMetaData)
+	otherStylusProgram := deployWasm(t, builder.ctx, auth, builder.L2Client(), rustFile("hostio-test"))
+	otherEvmProgram := deployEvmContract(t, builder.ctx, auth, builder.L2Client(), mocksgen.HostioTestMetaData)
 	packer, _ := util.NewCallParser(mocksgen.HostioTestABI, "msgValue")
 	otherData, err := packer()
 	Require(t, err)
@@ -386,8 +386,8 @@ func TestProgramCallCost(t *testing.T) {
 func TestProgramCreateCost(t *testing.T) {
 	builder := setupGasCostTest(t)
 	auth := builder.L2Info.GetDefaultTransactOpts("Owner", builder.ctx)
-	stylusCreate := deployWasm(t, builder.ctx, auth, builder.L2.Client, rustFile("create"))
-	evmCreate := deployEvmContract(t, builder.ctx, auth, builder.L2.Client, mocksgen.CreateTestMetaData)
+	stylusCreate := deployWasm(t, builder.ctx, auth, builder.L2Client(), rustFile("create"))
+	evmCreate := deployEvmContract(t, builder.ctx, auth, builder.L2Client(), mocksgen.CreateTestMetaData)
 	deployCode := common.FromHex(mocksgen.ProgramTestMetaData.Bin)
 
 	t.Run("create1", func(t *testing.T) {
@@ -409,8 +409,8 @@ func TestProgramCreateCost(t *testing.T) {
 func TestProgramKeccakCost(t *testing.T) {
 	builder := setupGasCostTest(t)
 	auth := builder.L2Info.GetDefaultTransactOpts("Owner", builder.ctx)
-	stylusProgram := deployWasm(t, builder.ctx, auth, builder.L2.Client, rustFile("hostio-test"))
-	evmProgram := deployEvmContract(t, builder.ctx, auth, builder.L2.Client, mocksgen.HostioTestMetaData)
+	stylusProgram := deployWasm(t, builder.ctx, auth, builder.L2Client(), rustFile("hostio-test"))
+	evmProgram := deployEvmContract(t, builder.ctx, auth, builder.L2Client(), mocksgen.HostioTestMetaData)
 	packer, _ := util.NewCallParser(mocksgen.HostioTestABI, "keccak")
 
 	for i := 1; i < 5; i++ {
@@ -465,11 +465,11 @@ func measureGasUsage(
 	receipts := builder.L2.SendWaitTestTransactions(t, txs)
 
 	evmGas := receipts[0].GasUsedForL2()
-	evmGasUsage, err := evmOpcodesGasUsage(builder.ctx, builder.L2.Client.Client(), txs[0])
+	evmGasUsage, err := evmOpcodesGasUsage(builder.ctx, builder.L2Client().Client(), txs[0])
 	Require(t, err)
 
 	stylusGas := receipts[1].GasUsedForL2()
-	stylusGasUsage, err := stylusHostiosGasUsage(builder.ctx, builder.L2.Client.Client(), txs[1])
+	stylusGasUsage, err := stylusHostiosGasUsage(builder.ctx, builder.L2Client().Client(), txs[1])
 	Require(t, err)
 
 	t.Logf("evm total usage: %v - stylus total usage: %v", evmGas, stylusGas)
@@ -36,11 +36,11 @@ func blockIsEmpty(block *types.Block) bool {
 }
 
 func nonEmptyBlockHeight(t *testing.T, builder *NodeBuilder) uint64 {
-	latestBlock, err := builder.L2.Client.BlockByNumber(builder.ctx, nil)
+	latestBlock, err := builder.L2Client().BlockByNumber(builder.ctx, nil)
 	Require(t, err)
 	for blockIsEmpty(latestBlock) {
 		prior := arbmath.BigSubByUint(latestBlock.Number(), 1)
-		latestBlock, err = builder.L2.Client.BlockByNumber(builder.ctx, prior)
+		latestBlock, err = builder.L2Client().BlockByNumber(builder.ctx, prior)
 		Require(t, err)
 	}
 	return latestBlock.NumberU64()
@@ -114,7 +114,7 @@ func testEvmData(t *testing.T, jit bool) {
 	builder, auth, cleanup := setupProgramTest(t, jit)
 	ctx := builder.ctx
 	l2info := builder.L2Info
-	l2client := builder.L2.Client
+	l2client := builder.L2Client()
 	defer cleanup()
 	evmDataAddr := deployWasm(t, ctx, auth, l2client, rustFile("evm-data"))
 
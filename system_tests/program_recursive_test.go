@@ -63,19 +63,19 @@ func testProgramRecursiveCall(t *testing.T, builder *NodeBuilder, slotVals map[s
 		// send event from caller on sload
 		args[5] = args[5] | 0x8
 	}
-	multiCaller, err := mocksgen.NewMultiCallTest(builder.L2Info.GetAddress(recurse[len(recurse)-1].Name), builder.L2.Client)
+	multiCaller, err := mocksgen.NewMultiCallTest(builder.L2Info.GetAddress(recurse[len(recurse)-1].Name), builder.L2Client())
 	Require(t, err)
 	ownerTransact := builder.L2Info.GetDefaultTransactOpts("Owner", ctx)
 	ownerTransact.GasLimit = 10000000
 	tx, err := multiCaller.Fallback(&ownerTransact, args)
 	Require(t, err)
-	receipt, err := WaitForTx(ctx, builder.L2.Client, tx.Hash(), time.Second*3)
+	receipt, err := WaitForTx(ctx, builder.L2Client(), tx.Hash(), time.Second*3)
 	Require(t, err)
 
 	if shouldSucceed {
 		if receipt.Status != types.ReceiptStatusSuccessful {
 			log.Error("error when shouldn't", "case", printRecurse(recurse))
-			Fatal(t, arbutil.DetailTxError(ctx, builder.L2.Client, tx, receipt))
+			Fatal(t, arbutil.DetailTxError(ctx, builder.L2Client(), tx, receipt))
 		}
 		if len(receipt.Logs) != 1 {
 			Fatal(t, "incorrect number of logs: ", len(receipt.Logs))
@@ -101,7 +101,7 @@ func testProgramRecursiveCall(t *testing.T, builder *NodeBuilder, slotVals map[s
 		Fatal(t, "should have failed")
 	}
 	for contract, expected := range slotVals {
-		found, err := builder.L2.Client.StorageAt(ctx, builder.L2Info.GetAddress(contract), slot, receipt.BlockNumber)
+		found, err := builder.L2Client().StorageAt(ctx, builder.L2Info.GetAddress(contract), slot, receipt.BlockNumber)
 		Require(t, err)
 		foundHash := common.BytesToHash(found)
 		if expected != foundHash {
@@ -114,7 +114,7 @@ func testProgramRecursiveCall(t *testing.T, builder *NodeBuilder, slotVals map[s
 func testProgramResursiveCalls(t *testing.T, tests [][]multiCallRecurse, jit bool) {
 	builder, auth, cleanup := setupProgramTest(t, jit)
 	ctx := builder.ctx
-	l2client := builder.L2.Client
+	l2client := builder.L2Client()
 	defer cleanup()
 
 	// set-up contracts
@@ -124,10 +124,10 @@ func testProgramResursiveCalls(t *testing.T, tests [][]multiCallRecurse, jit boo
 	auth.GasLimit = 32000000 // skip gas estimation
 	multicallB := deployContract(t, ctx, auth, l2client, multiCallWasm)
 	builder.L2Info.SetContract("multicall-rust-b", multicallB)
-	multiAddr, tx, _, err := mocksgen.DeployMultiCallTest(&auth, builder.L2.Client)
+	multiAddr, tx, _, err := mocksgen.DeployMultiCallTest(&auth, builder.L2Client())
 	builder.L2Info.SetContract("multicall-evm", multiAddr)
 	Require(t, err)
-	_, err = EnsureTxSucceeded(ctx, builder.L2.Client, tx)
+	_, err = EnsureTxSucceeded(ctx, builder.L2Client(), tx)
 	Require(t, err)
 	slotVals := make(map[string]common.Hash)
 	rander := testhelpers.NewPseudoRandomDataSource(t, 0)
@@ -70,7 +70,7 @@ func TestProgramKeccak(t *testing.T) {
 func keccakTest(t *testing.T, jit bool, builderOpts ...func(*NodeBuilder)) {
 	builder, auth, cleanup := setupProgramTest(t, jit, builderOpts...)
 	ctx := builder.ctx
-	l2client := builder.L2.Client
+	l2client := builder.L2Client()
 	defer cleanup()
 	programAddress := deployWasm(t, ctx, auth, l2client, rustFile("keccak"))
 
@@ -181,7 +181,7 @@ func testActivateTwice(t *testing.T, jit bool, builderOpts ...func(*NodeBuilder)
 	builder, auth, cleanup := setupProgramTest(t, jit, builderOpts...)
 	ctx := builder.ctx
 	l2info := builder.L2Info
-	l2client := builder.L2.Client
+	l2client := builder.L2Client()
 	defer cleanup()
 
 	ensure := func(tx *types.Transaction, err error) *types.Receipt {
@@ -279,7 +279,7 @@ func testStylusUpgrade(t *testing.T, jit bool) {
 	ctx := builder.ctx
 
 	l2info := builder.L2Info
-	l2client := builder.L2.Client
+	l2client := builder.L2Client()
 
 	ensure := func(tx *types.Transaction, err error) *types.Receipt {
 		t.Helper()
@@ -352,7 +352,7 @@ func testStylusUpgrade(t *testing.T, jit bool) {
 	Require(t, err)
 
 	// generate traffic to perform the upgrade
-	TransferBalance(t, "Owner", "Owner", big.NewInt(1), builder.L2Info, builder.L2.Client, ctx)
+	TransferBalance(t, "Owner", "Owner", big.NewInt(1), builder.L2Info, builder.L2Client(), ctx)
 
 	blockFail2 := checkFailWith("ProgramNeedsUpgrade")
 
@@ -372,7 +372,7 @@ func errorTest(t *testing.T, jit bool) {
 	builder, auth, cleanup := setupProgramTest(t, jit)
 	ctx := builder.ctx
 	l2info := builder.L2Info
-	l2client := builder.L2.Client
+	l2client := builder.L2Client()
 	defer cleanup()
 
 	programAddress := deployWasm(t, ctx, auth, l2client, rustFile("fallible"))
@@ -414,7 +414,7 @@ func storageTest(t *testing.T, jit bool) {
 	builder, auth, cleanup := setupProgramTest(t, jit)
 	ctx := builder.ctx
 	l2info := builder.L2Info
-	l2client := builder.L2.Client
+	l2client := builder.L2Client()
 	defer cleanup()
 	programAddress := deployWasm(t, ctx, auth, l2client, rustFile("storage"))
 
@@ -448,7 +448,7 @@ func transientStorageTest(t *testing.T, jit bool) {
 	builder, auth, cleanup := setupProgramTest(t, jit)
 	ctx := builder.ctx
 	l2info := builder.L2Info
-	l2client := builder.L2.Client
+	l2client := builder.L2Client()
 	defer cleanup()
 
 	storage := deployWasm(t, ctx, auth, l2client, rustFile("storage"))
@@ -514,7 +514,7 @@ func TestProgramMath(t *testing.T) {
 func fastMathTest(t *testing.T, jit bool) {
 	builder, auth, cleanup := setupProgramTest(t, jit)
 	ctx := builder.ctx
-	l2client := builder.L2.Client
+	l2client := builder.L2Client()
 	defer cleanup()
 
 	ensure := func(tx *types.Transaction, err error) *types.Receipt {
@@ -543,7 +543,7 @@ func testCalls(t *testing.T, jit bool) {
 	builder, auth, cleanup := setupProgramTest(t, jit)
 	ctx := builder.ctx
 	l2info := builder.L2Info
-	l2client := builder.L2.Client
+	l2client := builder.L2Client()
 	defer cleanup()
 	callsAddr := deployWasm(t, ctx, auth, l2client, rustFile("multicall"))
 
@@ -758,7 +758,7 @@ func testReturnData(t *testing.T, jit bool) {
 	builder, auth, cleanup := setupProgramTest(t, jit)
 	ctx := builder.ctx
 	l2info := builder.L2Info
-	l2client := builder.L2.Client
+	l2client := builder.L2Client()
 	defer cleanup()
 
 	ensure := func(tx *types.Transaction, err error) {
@@ -816,7 +816,7 @@ func testLogs(t *testing.T, jit, tracing bool) {
 	builder, auth, cleanup := setupProgramTest(t, jit)
 	ctx := builder.ctx
 	l2info := builder.L2Info
-	l2client := builder.L2.Client
+	l2client := builder.L2Client()
 	defer cleanup()
 	logAddr := deployWasm(t, ctx, auth, l2client, rustFile("log"))
 	multiAddr := deployWasm(t, ctx, auth, l2client, rustFile("multicall"))
@@ -925,7 +925,7 @@ func testCreate(t *testing.T, jit bool) {
 	builder, auth, cleanup := setupProgramTest(t, jit)
 	ctx := builder.ctx
 	l2info := builder.L2Info
-	l2client := builder.L2.Client
+	l2client := builder.L2Client()
 	defer cleanup()
 	createAddr := deployWasm(t, ctx, auth, l2client, rustFile("create"))
 	activateAuth := auth
@@ -997,6 +997,25 @@ func testCreate(t *testing.T, jit bool) {
 	create(create1Args, create1Addr)
 	create(create2Args, create2Addr)
 
+	// init code over the max size can't be deployed, as with the EVM's create opcodes
+	maxInitCodeSize := int(builder.chainConfig.MaxInitCodeSize())
+	for _, kind := range []byte{0x01, 0x02} {
+		for _, size := range []int{maxInitCodeSize, maxInitCodeSize + 1} {
+			args := []byte{kind}
+			args = append(args, common.Hash{}.Bytes()...)
+			if kind == 0x02 {
+				args = append(args, salt[:]...)
+			}
+			args = append(args, makeContractOfLength(size)...)
+			tx := l2info.PrepareTxTo("Owner", &createAddr, 1e9, nil, args)
+			Require(t, l2client.SendTransaction(ctx, tx))
+			_, err := EnsureTxSucceeded(ctx, l2client, tx)
+			if fits := size <= maxInitCodeSize; fits != (err == nil) {
+				Fatal(t, "unexpected result deploying init code of size", size, "with kind", kind, "err", err)
+			}
+		}
+	}
+
 	revertData := []byte("✌(✰‿✰)✌ ┏(✰‿✰)┛ ┗(✰‿✰)┓ ┗(✰‿✰)┛ ┏(✰‿✰)┓ ✌(✰‿✰)✌")
 	revertArgs := []byte{0x01}
 	revertArgs = append(revertArgs, common.BigToHash(startValue).Bytes()...)
@@ -1022,7 +1041,7 @@ func testInfiniteLoopCausesErrOutOfGas(t *testing.T, jit bool) {
 	builder, auth, cleanup := setupProgramTest(t, jit)
 	ctx := builder.ctx
 	l2info := builder.L2Info
-	l2client := builder.L2.Client
+	l2client := builder.L2Client()
 	defer cleanup()
 
 	userWasm := deployWasm(t, ctx, auth, l2client, "../arbitrator/prover/test-cases/user.wat")
@@ -1046,7 +1065,7 @@ func testMemory(t *testing.T, jit bool) {
 	builder, auth, cleanup := setupProgramTest(t, jit)
 	ctx := builder.ctx
 	l2info := builder.L2Info
-	l2client := builder.L2.Client
+	l2client := builder.L2Client()
 	defer cleanup()
 
 	ensure := func(tx *types.Transaction, err error) *types.Receipt {
@@ -1203,7 +1222,7 @@ func TestProgramActivateFails(t *testing.T) {
 func testActivateFails(t *testing.T, jit bool) {
 	builder, auth, cleanup := setupProgramTest(t, jit)
 	ctx := builder.ctx
-	l2client := builder.L2.Client
+	l2client := builder.L2Client()
 	defer cleanup()
 
 	arbWasm, err := pgen.NewArbWasm(types.ArbWasmAddress, l2client)
@@ -1243,7 +1262,7 @@ func testSdkStorage(t *testing.T, jit bool) {
 	builder, auth, cleanup := setupProgramTest(t, jit)
 	ctx := builder.ctx
 	l2info := builder.L2Info
-	l2client := builder.L2.Client
+	l2client := builder.L2Client()
 	defer cleanup()
 
 	rust := deployWasm(t, ctx, auth, l2client, rustFile("sdk-storage"))
@@ -1304,17 +1323,17 @@ func TestStylusPrecompileMethodsSimple(t *testing.T) {
 	cleanup := builder.Build(t)
 	defer cleanup()
 
-	arbOwner, err := pgen.NewArbOwner(types.ArbOwnerAddress, builder.L2.Client)
+	arbOwner, err := pgen.NewArbOwner(types.ArbOwnerAddress, builder.L2Client())
 	Require(t, err)
-	arbDebug, err := pgen.NewArbDebug(types.ArbDebugAddress, builder.L2.Client)
+	arbDebug, err := pgen.NewArbDebug(types.ArbDebugAddress, builder.L2Client())
 	Require(t, err)
-	arbWasm, err := pgen.NewArbWasm(types.ArbWasmAddress, builder.L2.Client)
+	arbWasm, err := pgen.NewArbWasm(types.ArbWasmAddress, builder.L2Client())
 	Require(t, err)
 
 	ensure := func(tx *types.Transaction, err error) *types.Receipt {
 		t.Helper()
 		Require(t, err)
-		receipt, err := EnsureTxSucceeded(ctx, builder.L2.Client, tx)
+		receipt, err := EnsureTxSucceeded(ctx, builder.L2Client(), tx)
 		Require(t, err)
 		return receipt
 	}
@@ -1323,7 +1342,7 @@ func TestStylusPrecompileMethodsSimple(t *testing.T) {
 	ensure(arbDebug.BecomeChainOwner(&ownerAuth))
 
 	wasm, _ := readWasmFile(t, rustFile("keccak"))
-	programAddress := deployContract(t, ctx, ownerAuth, builder.L2.Client, wasm)
+	programAddress := deployContract(t, ctx, ownerAuth, builder.L2Client(), wasm)
 
 	activateAuth := ownerAuth
 	activateAuth.Value = oneEth
@@ -1433,7 +1452,7 @@ func TestStylusPrecompileMethodsSimple(t *testing.T) {
 func TestProgramActivationLogs(t *testing.T) {
 	t.Parallel()
 	builder, auth, cleanup := setupProgramTest(t, true)
-	l2client := builder.L2.Client
+	l2client := builder.L2Client()
 	ctx := builder.ctx
 	defer cleanup()
 
@@ -1478,7 +1497,7 @@ func TestProgramEarlyExit(t *testing.T) {
 func testEarlyExit(t *testing.T, jit bool) {
 	builder, auth, cleanup := setupProgramTest(t, jit)
 	ctx := builder.ctx
-	l2client := builder.L2.Client
+	l2client := builder.L2Client()
 	defer cleanup()
 
 	earlyAddress := deployWasm(t, ctx, auth, l2client, "../arbitrator/stylus/tests/exit-early/exit-early.wat")
@@ -1506,7 +1525,7 @@ func testEarlyExit(t *testing.T, jit bool) {
 func TestProgramCacheManager(t *testing.T) {
 	builder, ownerAuth, cleanup := setupProgramTest(t, true)
 	ctx := builder.ctx
-	l2client := builder.L2.Client
+	l2client := builder.L2Client()
 	l2info := builder.L2Info
 	defer cleanup()
 
@@ -1543,11 +1562,11 @@ func TestProgramCacheManager(t *testing.T) {
 	}
 
 	// precompiles we plan to use
-	arbWasm, err := pgen.NewArbWasm(types.ArbWasmAddress, builder.L2.Client)
+	arbWasm, err := pgen.NewArbWasm(types.ArbWasmAddress, builder.L2Client())
 	Require(t, err)
-	arbWasmCache, err := pgen.NewArbWasmCache(types.ArbWasmCacheAddress, builder.L2.Client)
+	arbWasmCache, err := pgen.NewArbWasmCache(types.ArbWasmCacheAddress, builder.L2Client())
 	Require(t, err)
-	arbOwner, err := pgen.NewArbOwner(types.ArbOwnerAddress, builder.L2.Client)
+	arbOwner, err := pgen.NewArbOwner(types.ArbOwnerAddress, builder.L2Client())
 	Require(t, err)
 	ensure(arbOwner.SetInkPrice(&ownerAuth, 10_000))
 	parseLog := logParser[pgen.ArbWasmCacheUpdateProgramCache](t, pgen.ArbWasmCacheABI, "UpdateProgramCache")
@@ -1624,7 +1643,7 @@ func TestProgramCacheManager(t *testing.T) {
 func testReturnDataCost(t *testing.T, arbosVersion uint64) {
 	builder, auth, cleanup := setupProgramTest(t, false, func(b *NodeBuilder) { b.WithArbOSVersion(arbosVersion) })
 	ctx := builder.ctx
-	l2client := builder.L2.Client
+	l2client := builder.L2Client()
 	defer cleanup()
 
 	// use a consistent ink price
@@ -1632,7 +1651,7 @@ func testReturnDataCost(t *testing.T, arbosVersion uint64) {
 	Require(t, err)
 	tx, err := arbOwner.SetInkPrice(&auth, 10000)
 	Require(t, err)
-	_, err = EnsureTxSucceeded(ctx, builder.L2.Client, tx)
+	_, err = EnsureTxSucceeded(ctx, builder.L2Client(), tx)
 	Require(t, err)
 
 	returnSize := big.NewInt(1024 * 1024) // 1MiB
@@ -1722,15 +1741,15 @@ func setupProgramTest(t *testing.T, jit bool, builderOpts ...func(*NodeBuilder))
 
 	auth := builder.L2Info.GetDefaultTransactOpts("Owner", ctx)
 
-	arbOwner, err := pgen.NewArbOwner(types.ArbOwnerAddress, builder.L2.Client)
+	arbOwner, err := pgen.NewArbOwner(types.ArbOwnerAddress, builder.L2Client())
 	Require(t, err)
-	arbDebug, err := pgen.NewArbDebug(types.ArbDebugAddress, builder.L2.Client)
+	arbDebug, err := pgen.NewArbDebug(types.ArbDebugAddress, builder.L2Client())
 	Require(t, err)
 
 	ensure := func(tx *types.Transaction, err error) *types.Receipt {
 		t.Helper()
 		Require(t, err)
-		receipt, err := EnsureTxSucceeded(ctx, builder.L2.Client, tx)
+		receipt, err := EnsureTxSucceeded(ctx, builder.L2Client(), tx)
 		Require(t, err)
 		return receipt
 	}
@@ -1928,7 +1947,7 @@ func formatTime(duration time.Duration) string {
 func testWasmRecreate(t *testing.T, builder *NodeBuilder, storeTx *types.Transaction, loadTx *types.Transaction, want []byte) {
 	ctx := builder.ctx
 	l2info := builder.L2Info
-	l2client := builder.L2.Client
+	l2client := builder.L2Client()
 
 	// do an onchain call - store value
 	Require(t, l2client.SendTransaction(ctx, storeTx))
@@ -1997,7 +2016,7 @@ func TestWasmRecreate(t *testing.T) {
 	builder, auth, cleanup := setupProgramTest(t, true)
 	ctx := builder.ctx
 	l2info := builder.L2Info
-	l2client := builder.L2.Client
+	l2client := builder.L2Client()
 	defer cleanup()
 
 	storage := deployWasm(t, ctx, auth, l2client, rustFile("storage"))
@@ -2015,7 +2034,7 @@ func TestWasmRecreateWithDelegatecall(t *testing.T) {
 	builder, auth, cleanup := setupProgramTest(t, true)
 	ctx := builder.ctx
 	l2info := builder.L2Info
-	l2client := builder.L2.Client
+	l2client := builder.L2Client()
 	defer cleanup()
 
 	storage := deployWasm(t, ctx, auth, l2client, rustFile("storage"))
@@ -2060,7 +2079,7 @@ func TestWasmStoreRebuilding(t *testing.T) {
 	})
 	ctx := builder.ctx
 	l2info := builder.L2Info
-	l2client := builder.L2.Client
+	l2client := builder.L2Client()
 	defer cleanup()
 
 	storage := deployWasm(t, ctx, auth, l2client, rustFile("storage"))
@@ -2226,7 +2245,7 @@ func deployWasmAndGetEntrySizeEstimateBytes(
 	wasmName string,
 ) (common.Address, uint64) {
 	ctx := builder.ctx
-	l2client := builder.L2.Client
+	l2client := builder.L2Client()
 
 	wasm, _ := readWasmFile(t, rustFile(wasmName))
 	arbWasm, err := pgen.NewArbWasm(types.ArbWasmAddress, l2client)
@@ -2262,7 +2281,7 @@ func TestWasmLruCache(t *testing.T) {
 	builder, auth, cleanup := setupProgramTest(t, true)
 	ctx := builder.ctx
 	l2info := builder.L2Info
-	l2client := builder.L2.Client
+	l2client := builder.L2Client()
 	defer cleanup()
 
 	ensure := func(tx *types.Transaction, err error) *types.Receipt {
@@ -2360,7 +2379,7 @@ func TestWasmLongTermCache(t *testing.T) {
 	})
 	ctx := builder.ctx
 	l2info := builder.L2Info
-	l2client := builder.L2.Client
+	l2client := builder.L2Client()
 	defer cleanup()
 
 	ensure := func(tx *types.Transaction, err error) *types.Receipt {
@@ -2371,9 +2390,9 @@ func TestWasmLongTermCache(t *testing.T) {
 		return receipt
 	}
 
-	arbWasmCache, err := pgen.NewArbWasmCache(types.ArbWasmCacheAddress, builder.L2.Client)
+	arbWasmCache, err := pgen.NewArbWasmCache(types.ArbWasmCacheAddress, builder.L2Client())
 	Require(t, err)
-	arbOwner, err := pgen.NewArbOwner(types.ArbOwnerAddress, builder.L2.Client)
+	arbOwner, err := pgen.NewArbOwner(types.ArbOwnerAddress, builder.L2Client())
 	Require(t, err)
 	ensure(arbOwner.SetInkPrice(&ownerAuth, 10_000))
 
@@ -2498,7 +2517,7 @@ func TestRepopulateWasmLongTermCacheFromLru(t *testing.T) {
 	})
 	ctx := builder.ctx
 	l2info := builder.L2Info
-	l2client := builder.L2.Client
+	l2client := builder.L2Client()
 	defer cleanup()
 
 	ensure := func(tx *types.Transaction, err error) *types.Receipt {
@@ -2509,9 +2528,9 @@ func TestRepopulateWasmLongTermCacheFromLru(t *testing.T) {
 		return receipt
 	}
 
-	arbWasmCache, err := pgen.NewArbWasmCache(types.ArbWasmCacheAddress, builder.L2.Client)
+	arbWasmCache, err := pgen.NewArbWasmCache(types.ArbWasmCacheAddress, builder.L2Client())
 	Require(t, err)
-	arbOwner, err := pgen.NewArbOwner(types.ArbOwnerAddress, builder.L2.Client)
+	arbOwner, err := pgen.NewArbOwner(types.ArbOwnerAddress, builder.L2Client())
 	Require(t, err)
 	ensure(arbOwner.SetInkPrice(&ownerAuth, 10_000))
 
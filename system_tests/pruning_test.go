@@ -51,14 +51,14 @@ func TestPruning(t *testing.T) {
 	for i := uint64(0); i < 200; i++ {
 		tx := builder.L2Info.PrepareTx("Owner", "User2", builder.L2Info.TransferGas, common.Big1, nil)
 		txs = append(txs, tx)
-		err := builder.L2.Client.SendTransaction(ctx, tx)
+		err := builder.L2Client().SendTransaction(ctx, tx)
 		Require(t, err)
 	}
 	for _, tx := range txs {
 		_, err := builder.L2.EnsureTxSucceeded(tx)
 		Require(t, err)
 	}
-	lastBlock, err := builder.L2.Client.BlockNumber(ctx)
+	lastBlock, err := builder.L2Client().BlockNumber(ctx)
 	Require(t, err)
 	l2cleanupDone = true
 	builder.L2.cleanup()
@@ -93,7 +93,7 @@ func TestPruning(t *testing.T) {
 		initConfig.Prune = "full"
 		coreCacheConfig := gethexec.DefaultCacheConfigFor(stack, &builder.execConfig.Caching)
 		persistentConfig := conf.PersistentConfigDefault
-		err = pruning.PruneChainDb(ctx, chainDb, stack, &initConfig, coreCacheConfig, &persistentConfig, builder.L1.Client, *builder.L2.ConsensusNode.DeployInfo, false)
+		err = pruning.PruneChainDb(ctx, chainDb, stack, &initConfig, coreCacheConfig, &persistentConfig, builder.L1Client(), *builder.L2.ConsensusNode.DeployInfo, false)
 		Require(t, err)
 
 		for _, key := range testKeys {
@@ -31,7 +31,7 @@ func makeSomeTransfers(t *testing.T, ctx context.Context, builder *NodeBuilder,
 	for i := uint64(0); i < txCount; i++ {
 		tx := builder.L2Info.PrepareTx("Owner", "User2", builder.L2Info.TransferGas, common.Big1, nil)
 		txs = append(txs, tx)
-		err := builder.L2.Client.SendTransaction(ctx, tx)
+		err := builder.L2Client().SendTransaction(ctx, tx)
 		Require(t, err)
 	}
 	for _, tx := range txs {
@@ -111,7 +111,7 @@ func TestRecreateStateForRPCNoDepthLimit(t *testing.T) {
 	execConfig.Caching.MaxAmountOfGasToSkipStateSaving = 0
 	builder, cancelNode := prepareNodeWithHistory(t, ctx, execConfig, 32)
 	defer cancelNode()
-	execNode, l2client := builder.L2.ExecNode, builder.L2.Client
+	execNode, l2client := builder.L2.ExecNode, builder.L2Client()
 	bc := execNode.Backend.ArbInterface().BlockChain()
 	db := execNode.Backend.ChainDb()
 
@@ -149,7 +149,7 @@ func TestRecreateStateForRPCBigEnoughDepthLimit(t *testing.T) {
 	execConfig.Caching.MaxAmountOfGasToSkipStateSaving = 0
 	builder, cancelNode := prepareNodeWithHistory(t, ctx, execConfig, 32)
 	defer cancelNode()
-	execNode, l2client := builder.L2.ExecNode, builder.L2.Client
+	execNode, l2client := builder.L2.ExecNode, builder.L2Client()
 	bc := execNode.Backend.ArbInterface().BlockChain()
 	db := execNode.Backend.ChainDb()
 
@@ -186,7 +186,7 @@ func TestRecreateStateForRPCDepthLimitExceeded(t *testing.T) {
 	execConfig.Caching.MaxAmountOfGasToSkipStateSaving = 0
 	builder, cancelNode := prepareNodeWithHistory(t, ctx, execConfig, 32)
 	defer cancelNode()
-	execNode, l2client := builder.L2.ExecNode, builder.L2.Client
+	execNode, l2client := builder.L2.ExecNode, builder.L2Client()
 	bc := execNode.Backend.ArbInterface().BlockChain()
 	db := execNode.Backend.ChainDb()
 
@@ -223,7 +223,7 @@ func TestRecreateStateForRPCMissingBlockParent(t *testing.T) {
 	execConfig.Caching.MaxAmountOfGasToSkipStateSaving = 0
 	builder, cancelNode := prepareNodeWithHistory(t, ctx, execConfig, headerCacheLimit+5)
 	defer cancelNode()
-	execNode, l2client := builder.L2.ExecNode, builder.L2.Client
+	execNode, l2client := builder.L2.ExecNode, builder.L2Client()
 	bc := execNode.Backend.ArbInterface().BlockChain()
 	db := execNode.Backend.ChainDb()
 
@@ -270,7 +270,7 @@ func TestRecreateStateForRPCBeyondGenesis(t *testing.T) {
 	execConfig.Caching.MaxNumberOfBlocksToSkipStateSaving = 0
 	execConfig.Caching.MaxAmountOfGasToSkipStateSaving = 0
 	builder, cancelNode := prepareNodeWithHistory(t, ctx, execConfig, 32)
-	execNode, l2client := builder.L2.ExecNode, builder.L2.Client
+	execNode, l2client := builder.L2.ExecNode, builder.L2Client()
 	defer cancelNode()
 	bc := execNode.Backend.ArbInterface().BlockChain()
 	db := execNode.Backend.ChainDb()
@@ -309,7 +309,7 @@ func TestRecreateStateForRPCBlockNotFoundWhileRecreating(t *testing.T) {
 	execConfig.Caching.MaxNumberOfBlocksToSkipStateSaving = 0
 	execConfig.Caching.MaxAmountOfGasToSkipStateSaving = 0
 	builder, cancelNode := prepareNodeWithHistory(t, ctx, execConfig, blockCacheLimit+4)
-	execNode, l2client := builder.L2.ExecNode, builder.L2.Client
+	execNode, l2client := builder.L2.ExecNode, builder.L2Client()
 	defer cancelNode()
 	bc := execNode.Backend.ArbInterface().BlockChain()
 	db := execNode.Backend.ChainDb()
@@ -359,7 +359,7 @@ func testSkippingSavingStateAndRecreatingAfterRestart(t *testing.T, cacheConfig
 	cleanup := builder.Build(t)
 	defer cleanup()
 
-	client := builder.L2.Client
+	client := builder.L2Client()
 	l2info := builder.L2Info
 	genesis, err := client.BlockNumber(ctx)
 	Require(t, err)
@@ -393,7 +393,7 @@ func testSkippingSavingStateAndRecreatingAfterRestart(t *testing.T, cacheConfig
 	builder.RestartL2Node(t)
 	t.Log("restarted the node")
 
-	client = builder.L2.Client
+	client = builder.L2Client()
 	bc = builder.L2.ExecNode.Backend.ArbInterface().BlockChain()
 	gas := skipGas
 	blocks := skipBlocks
@@ -594,7 +594,7 @@ func TestStateAndHeaderForRecentBlock(t *testing.T) {
 		defer close(senderDone)
 		for ctx.Err() == nil {
 			tx := builder.L2Info.PrepareTx("Owner", "User2", builder.L2Info.TransferGas, new(big.Int).Lsh(big.NewInt(1), 128), nil)
-			err := builder.L2.Client.SendTransaction(ctx, tx)
+			err := builder.L2Client().SendTransaction(ctx, tx)
 			if ctx.Err() != nil {
 				return
 			}
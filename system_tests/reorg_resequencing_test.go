@@ -53,7 +53,7 @@ func TestReorgResequencing(t *testing.T) {
 	accountsWithBalance := []string{"User1", "User2", "User3"}
 	verifyBalances := func(scenario string) {
 		for _, account := range accountsWithBalance {
-			balance, err := builder.L2.Client.BalanceAt(ctx, builder.L2Info.GetAddress(account), nil)
+			balance, err := builder.L2Client().BalanceAt(ctx, builder.L2Info.GetAddress(account), nil)
 			Require(t, err)
 			if balance.Int64() != params.Ether {
 				Fatal(t, "expected account", account, "to have a balance of 1 ether but instead it has", balance, "wei "+scenario)
@@ -13,6 +13,7 @@ import (
 	"github.com/ethereum/go-ethereum/accounts/abi"
 	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/eth/gasestimator"
 	"github.com/ethereum/go-ethereum/params"
@@ -32,6 +33,7 @@ import (
 	"github.com/offchainlabs/nitro/solgen/go/precompilesgen"
 	"github.com/offchainlabs/nitro/util/arbmath"
 	"github.com/offchainlabs/nitro/util/colors"
+	"github.com/offchainlabs/nitro/util/testhelpers"
 )
 
 func retryableSetup(t *testing.T, modifyNodeConfig ...func(*NodeBuilder)) (
@@ -52,9 +54,9 @@ func retryableSetup(t *testing.T, modifyNodeConfig ...func(*NodeBuilder)) (
 	builder.L2Info.GenerateAccount("Beneficiary")
 	builder.L2Info.GenerateAccount("Burn")
 
-	delayedInbox, err := bridgegen.NewInbox(builder.L1Info.GetAddress("Inbox"), builder.L1.Client)
+	delayedInbox, err := bridgegen.NewInbox(builder.L1Info.GetAddress("Inbox"), builder.L1Client())
 	Require(t, err)
-	delayedBridge, err := arbnode.NewDelayedBridge(builder.L1.Client, builder.L1Info.GetAddress("Bridge"), 0)
+	delayedBridge, err := arbnode.NewDelayedBridge(builder.L1Client(), builder.L1Info.GetAddress("Bridge"), 0)
 	Require(t, err)
 
 	lookupL2Tx := func(l1Receipt *types.Receipt) *types.Transaction {
@@ -99,10 +101,10 @@ func retryableSetup(t *testing.T, modifyNodeConfig ...func(*NodeBuilder)) (
 	teardown := func() {
 
 		// check the integrity of the RPC
-		blockNum, err := builder.L2.Client.BlockNumber(ctx)
+		blockNum, err := builder.L2Client().BlockNumber(ctx)
 		Require(t, err, "failed to get L2 block number")
 		for number := uint64(0); number < blockNum; number++ {
-			block, err := builder.L2.Client.BlockByNumber(ctx, arbmath.UintToBig(number))
+			block, err := builder.L2Client().BlockByNumber(ctx, arbmath.UintToBig(number))
 			Require(t, err, "failed to get L2 block", number, "of", blockNum)
 			if block.Number().Uint64() != number {
 				Fatal(t, "block number mismatch", number, block.Number().Uint64())
@@ -124,7 +126,7 @@ func TestRetryableNoExist(t *testing.T) {
 	cleanup := builder.Build(t)
 	defer cleanup()
 
-	arbRetryableTx, err := precompilesgen.NewArbRetryableTx(common.HexToAddress("6e"), builder.L2.Client)
+	arbRetryableTx, err := precompilesgen.NewArbRetryableTx(common.HexToAddress("6e"), builder.L2Client())
 	Require(t, err)
 	_, err = arbRetryableTx.GetTimeout(&bind.CallOpts{}, common.Hash{})
 	// The first error is server side. The second error is client side ABI decoding.
@@ -144,7 +146,7 @@ func TestEstimateRetryableTicketWithNoFundsAndZeroGasPrice(t *testing.T) {
 	deposit := arbmath.BigMul(big.NewInt(1e12), big.NewInt(1e12))
 	callValue := big.NewInt(1e6)
 
-	nodeInterface, err := node_interfacegen.NewNodeInterface(types.NodeInterfaceAddress, builder.L2.Client)
+	nodeInterface, err := node_interfacegen.NewNodeInterface(types.NodeInterfaceAddress, builder.L2Client())
 	Require(t, err, "failed to deploy NodeInterface")
 
 	builder.L2Info.GenerateAccount("zerofunds")
@@ -176,7 +178,7 @@ func TestSubmitRetryableImmediateSuccess(t *testing.T) {
 	deposit := arbmath.BigMul(big.NewInt(1e12), big.NewInt(1e12))
 	callValue := big.NewInt(1e6)
 
-	nodeInterface, err := node_interfacegen.NewNodeInterface(types.NodeInterfaceAddress, builder.L2.Client)
+	nodeInterface, err := node_interfacegen.NewNodeInterface(types.NodeInterfaceAddress, builder.L2Client())
 	Require(t, err, "failed to deploy NodeInterface")
 
 	// estimate the gas needed to auto redeem the retryable
@@ -234,7 +236,7 @@ func TestSubmitRetryableImmediateSuccess(t *testing.T) {
 		Fatal(t)
 	}
 
-	l2balance, err := builder.L2.Client.BalanceAt(ctx, builder.L2Info.GetAddress("User2"), nil)
+	l2balance, err := builder.L2Client().BalanceAt(ctx, builder.L2Info.GetAddress("User2"), nil)
 	Require(t, err)
 
 	if !arbmath.BigEquals(l2balance, callValue) {
@@ -242,6 +244,96 @@ func TestSubmitRetryableImmediateSuccess(t *testing.T) {
 	}
 }
 
+func TestRetryableAutoRedeemGasLimit(t *testing.T) {
+	t.Parallel()
+	builder, delayedInbox, lookupL2Tx, ctx, teardown := retryableSetup(t)
+	defer teardown()
+
+	user2Address := builder.L2Info.GetAddress("User2")
+	beneficiaryAddress := builder.L2Info.GetAddress("Beneficiary")
+
+	arbOwner, err := precompilesgen.NewArbOwner(types.ArbOwnerAddress, builder.L2Client())
+	Require(t, err)
+	arbRetryableTx, err := precompilesgen.NewArbRetryableTx(types.ArbRetryableTxAddress, builder.L2Client())
+	Require(t, err)
+	ownerTxOpts := builder.L2Info.GetDefaultTransactOpts("Owner", ctx)
+
+	// limits below the intrinsic gas of a transaction are rejected
+	_, err = arbOwner.SetRetryableAutoRedeemGasLimit(&ownerTxOpts, params.TxGas-1)
+	if err == nil {
+		Fatal(t, "set an auto-redeem gas limit below the minimum transaction gas")
+	}
+
+	autoRedeemGasLimit := uint64(100_000)
+	tx, err := arbOwner.SetRetryableAutoRedeemGasLimit(&ownerTxOpts, autoRedeemGasLimit)
+	Require(t, err)
+	_, err = builder.L2.EnsureTxSucceeded(tx)
+	Require(t, err)
+
+	submitRetryable := func(gasLimit uint64) (donatedGas uint64, retryGas uint64) {
+		t.Helper()
+		usertxoptsL1 := builder.L1Info.GetDefaultTransactOpts("Faucet", ctx)
+		usertxoptsL1.Value = arbmath.BigMul(big.NewInt(1e12), big.NewInt(1e12))
+		l1tx, err := delayedInbox.CreateRetryableTicket(
+			&usertxoptsL1,
+			user2Address,
+			common.Big0,
+			big.NewInt(1e16),
+			beneficiaryAddress,
+			beneficiaryAddress,
+			arbmath.UintToBig(gasLimit),
+			big.NewInt(l2pricing.InitialBaseFeeWei*2),
+			[]byte{},
+		)
+		Require(t, err)
+		l1Receipt, err := builder.L1.EnsureTxSucceeded(l1tx)
+		Require(t, err)
+
+		waitForL1DelayBlocks(t, builder)
+
+		receipt, err := builder.L2.EnsureTxSucceeded(lookupL2Tx(l1Receipt))
+		Require(t, err)
+		if len(receipt.Logs) != 2 {
+			Fatal(t, "expected 2 logs from the submission, got", len(receipt.Logs))
+		}
+		redeemScheduled, err := arbRetryableTx.ParseRedeemScheduled(*receipt.Logs[1])
+		Require(t, err)
+
+		retryReceipt, err := WaitForTx(ctx, builder.L2Client(), redeemScheduled.RetryTxHash, time.Second*5)
+		Require(t, err)
+		if retryReceipt.Status != types.ReceiptStatusSuccessful {
+			Fatal(t, "auto-redeem failed")
+		}
+		retryTx, _, err := builder.L2Client().TransactionByHash(ctx, redeemScheduled.RetryTxHash)
+		Require(t, err)
+		return redeemScheduled.DonatedGas, retryTx.Gas()
+	}
+
+	// gas above the limit is capped
+	donatedGas, retryGas := submitRetryable(autoRedeemGasLimit * 10)
+	if donatedGas != autoRedeemGasLimit || retryGas != autoRedeemGasLimit {
+		Fatal(t, "expected auto-redeem gas to be capped at", autoRedeemGasLimit, "got donated", donatedGas, "retry gas", retryGas)
+	}
+
+	// gas below the limit is unchanged
+	gasLimit := autoRedeemGasLimit / 2
+	donatedGas, retryGas = submitRetryable(gasLimit)
+	if donatedGas != gasLimit || retryGas != gasLimit {
+		Fatal(t, "expected auto-redeem gas to be", gasLimit, "got donated", donatedGas, "retry gas", retryGas)
+	}
+
+	// a limit of 0 removes the cap
+	tx, err = arbOwner.SetRetryableAutoRedeemGasLimit(&ownerTxOpts, 0)
+	Require(t, err)
+	_, err = builder.L2.EnsureTxSucceeded(tx)
+	Require(t, err)
+	gasLimit = autoRedeemGasLimit * 10
+	donatedGas, retryGas = submitRetryable(gasLimit)
+	if donatedGas != gasLimit || retryGas != gasLimit {
+		Fatal(t, "expected uncapped auto-redeem gas to be", gasLimit, "got donated", donatedGas, "retry gas", retryGas)
+	}
+}
+
 func testSubmitRetryableEmptyEscrow(t *testing.T, arbosVersion uint64) {
 	t.Parallel()
 	builder, delayedInbox, lookupL2Tx, ctx, teardown := retryableSetup(t, func(builder *NodeBuilder) {
@@ -255,7 +347,7 @@ func testSubmitRetryableEmptyEscrow(t *testing.T, arbosVersion uint64) {
 	deposit := arbmath.BigMul(big.NewInt(1e12), big.NewInt(1e12))
 	callValue := common.Big0
 
-	nodeInterface, err := node_interfacegen.NewNodeInterface(types.NodeInterfaceAddress, builder.L2.Client)
+	nodeInterface, err := node_interfacegen.NewNodeInterface(types.NodeInterfaceAddress, builder.L2Client())
 	Require(t, err, "failed to deploy NodeInterface")
 
 	// estimate the gas needed to auto redeem the retryable
@@ -307,7 +399,7 @@ func testSubmitRetryableEmptyEscrow(t *testing.T, arbosVersion uint64) {
 		Fatal(t)
 	}
 
-	l2balance, err := builder.L2.Client.BalanceAt(ctx, builder.L2Info.GetAddress("User2"), nil)
+	l2balance, err := builder.L2Client().BalanceAt(ctx, builder.L2Info.GetAddress("User2"), nil)
 	Require(t, err)
 
 	if !arbmath.BigEquals(l2balance, callValue) {
@@ -376,13 +468,13 @@ func TestSubmitRetryableFailThenRetry(t *testing.T) {
 	firstRetryTxId := receipt.Logs[1].Topics[2]
 
 	// get receipt for the auto redeem, make sure it failed
-	receipt, err = WaitForTx(ctx, builder.L2.Client, firstRetryTxId, time.Second*5)
+	receipt, err = WaitForTx(ctx, builder.L2Client(), firstRetryTxId, time.Second*5)
 	Require(t, err)
 	if receipt.Status != types.ReceiptStatusFailed {
 		Fatal(t, receipt.GasUsed)
 	}
 
-	arbRetryableTx, err := precompilesgen.NewArbRetryableTx(common.HexToAddress("6e"), builder.L2.Client)
+	arbRetryableTx, err := precompilesgen.NewArbRetryableTx(common.HexToAddress("6e"), builder.L2Client())
 	Require(t, err)
 	tx, err := arbRetryableTx.Redeem(&ownerTxOpts, ticketId)
 	Require(t, err)
@@ -398,7 +490,7 @@ func TestSubmitRetryableFailThenRetry(t *testing.T) {
 	retryTxId := receipt.Logs[0].Topics[2]
 
 	// check the receipt for the retry
-	receipt, err = WaitForTx(ctx, builder.L2.Client, retryTxId, time.Second*1)
+	receipt, err = WaitForTx(ctx, builder.L2Client(), retryTxId, time.Second*1)
 	Require(t, err)
 	if receipt.Status != types.ReceiptStatusSuccessful {
 		Fatal(t, receipt.Status)
@@ -426,6 +518,111 @@ func TestSubmitRetryableFailThenRetry(t *testing.T) {
 	}
 }
 
+func TestEstimateRedeemGas(t *testing.T) {
+	t.Parallel()
+	builder, delayedInbox, lookupL2Tx, ctx, teardown := retryableSetup(t, func(builder *NodeBuilder) {
+		// keep batch posting reports from raising the L1 price once it's been zeroed below
+		builder.nodeConfig.BatchPoster.Enable = false
+	})
+	defer teardown()
+
+	ownerTxOpts := builder.L2Info.GetDefaultTransactOpts("Owner", ctx)
+	usertxopts := builder.L1Info.GetDefaultTransactOpts("Faucet", ctx)
+	usertxopts.Value = arbmath.BigMul(big.NewInt(1e12), big.NewInt(1e12))
+
+	// without an L1 price the redeem's gas limit only needs to cover L2 execution
+	arbDebug, err := precompilesgen.NewArbDebug(types.ArbDebugAddress, builder.L2Client())
+	Require(t, err)
+	tx, err := arbDebug.BecomeChainOwner(&ownerTxOpts)
+	Require(t, err)
+	_, err = builder.L2.EnsureTxSucceeded(tx)
+	Require(t, err)
+	arbOwner, err := precompilesgen.NewArbOwner(types.ArbOwnerAddress, builder.L2Client())
+	Require(t, err)
+	tx, err = arbOwner.SetL1PricePerUnit(&ownerTxOpts, common.Big0)
+	Require(t, err)
+	_, err = builder.L2.EnsureTxSucceeded(tx)
+	Require(t, err)
+
+	// a retryable without gas for an auto-redeem, whose retry only needs its intrinsic gas
+	destination := builder.L2Info.GetAddress("User2")
+	beneficiaryAddress := builder.L2Info.GetAddress("Beneficiary")
+	callValue := big.NewInt(1e6)
+	calldata := []byte{0, 1, 2, 3, 0, 0, 4}
+	l1tx, err := delayedInbox.CreateRetryableTicket(
+		&usertxopts,
+		destination,
+		callValue,
+		big.NewInt(1e16),
+		beneficiaryAddress,
+		beneficiaryAddress,
+		common.Big0,
+		big.NewInt(l2pricing.InitialBaseFeeWei*2),
+		calldata,
+	)
+	Require(t, err)
+	l1Receipt, err := builder.L1.EnsureTxSucceeded(l1tx)
+	Require(t, err)
+	if l1Receipt.Status != types.ReceiptStatusSuccessful {
+		Fatal(t, "l1Receipt indicated failure")
+	}
+
+	waitForL1DelayBlocks(t, builder)
+
+	receipt, err := builder.L2.EnsureTxSucceeded(lookupL2Tx(l1Receipt))
+	Require(t, err)
+	if len(receipt.Logs) != 1 {
+		Fatal(t, "expected the ticket to be created without an auto-redeem, got", len(receipt.Logs), "logs")
+	}
+	ticketId := receipt.Logs[0].Topics[1]
+
+	arbRetryableTx, err := precompilesgen.NewArbRetryableTx(types.ArbRetryableTxAddress, builder.L2Client())
+	Require(t, err)
+	_, err = arbRetryableTx.EstimateRedeemGas(&bind.CallOpts{Context: ctx}, common.Hash{})
+	if err == nil || !strings.Contains(err.Error(), "NoTicketWithID") {
+		Fatal(t, "expected NoTicketWithID estimating the gas of a ticket that doesn't exist, got", err)
+	}
+	redeemGas, err := arbRetryableTx.EstimateRedeemGas(&bind.CallOpts{Context: ctx}, ticketId)
+	Require(t, err)
+
+	// redeem with exactly the estimated gas, on top of the redeem tx's own intrinsic gas
+	retryableABI, err := precompilesgen.ArbRetryableTxMetaData.GetAbi()
+	Require(t, err)
+	redeemCalldata, err := retryableABI.Pack("redeem", ticketId)
+	Require(t, err)
+	redeemIntrinsicGas, err := core.IntrinsicGas(redeemCalldata, nil, false, true, true, true)
+	Require(t, err)
+	redeemTxOpts := ownerTxOpts
+	redeemTxOpts.GasLimit = redeemIntrinsicGas + redeemGas
+	balanceBefore := builder.L2.GetBalance(t, destination)
+	tx, err = arbRetryableTx.Redeem(&redeemTxOpts, ticketId)
+	Require(t, err)
+	receipt, err = builder.L2.EnsureTxSucceeded(tx)
+	Require(t, err)
+	if receipt.GasUsedForL1 != 0 {
+		Fatal(t, "expected no L1 gas to be charged, got", receipt.GasUsedForL1)
+	}
+	if len(receipt.Logs) != 1 {
+		Fatal(t, "expected the redeem to schedule a retry, got", len(receipt.Logs), "logs")
+	}
+	redeemScheduled, err := arbRetryableTx.ParseRedeemScheduled(*receipt.Logs[0])
+	Require(t, err)
+
+	retryIntrinsicGas, err := core.IntrinsicGas(calldata, nil, false, true, true, true)
+	Require(t, err)
+	if redeemScheduled.DonatedGas != retryIntrinsicGas {
+		Fatal(t, "expected the retry to be donated", retryIntrinsicGas, "gas, got", redeemScheduled.DonatedGas)
+	}
+	receipt, err = WaitForTx(ctx, builder.L2Client(), redeemScheduled.RetryTxHash, time.Second*5)
+	Require(t, err)
+	if receipt.Status != types.ReceiptStatusSuccessful {
+		Fatal(t, "retry failed with", receipt.GasUsed, "gas used")
+	}
+	if balance := builder.L2.GetBalance(t, destination); !arbmath.BigEquals(balance, arbmath.BigAdd(balanceBefore, callValue)) {
+		Fatal(t, "expected the retry to transfer", callValue, "got", balanceBefore, "->", balance)
+	}
+}
+
 func TestGetLifetime(t *testing.T) {
 	t.Parallel()
 
@@ -436,9 +633,11 @@ func TestGetLifetime(t *testing.T) {
 	cleanup := builder.Build(t)
 	defer cleanup()
 
-	callOpts := &bind.CallOpts{Context: ctx}
+	header, err := builder.L2Client().HeaderByNumber(ctx, nil)
+	Require(t, err)
+	callOpts := &bind.CallOpts{Context: ctx, BlockNumber: header.Number}
 
-	arbRetryableTx, err := precompilesgen.NewArbRetryableTx(common.HexToAddress("6e"), builder.L2.Client)
+	arbRetryableTx, err := precompilesgen.NewArbRetryableTx(common.HexToAddress("6e"), builder.L2Client())
 	Require(t, err)
 
 	lifetime, err := arbRetryableTx.GetLifetime(callOpts)
@@ -446,11 +645,160 @@ func TestGetLifetime(t *testing.T) {
 	if lifetime.Cmp(big.NewInt(retryables.RetryableLifetimeSeconds)) != 0 {
 		t.Fatal("Expected to be ", retryables.RetryableLifetimeSeconds, " but got ", lifetime)
 	}
+
+	lifetime, currentTime, err := arbRetryableTx.GetLifetimeAndCurrentTime(callOpts)
+	Require(t, err)
+	if lifetime.Cmp(big.NewInt(retryables.RetryableLifetimeSeconds)) != 0 {
+		t.Fatal("Expected to be ", retryables.RetryableLifetimeSeconds, " but got ", lifetime)
+	}
+	if currentTime != header.Time {
+		t.Fatal("Expected current time to be ", header.Time, " but got ", currentTime)
+	}
+}
+
+// submitRetryableWithoutRedeem submits a retryable with no L2 gas, so it isn't auto-redeemed and stays around,
+// returning its ticket id and the receipt of its submission
+func submitRetryableWithoutRedeem(
+	t *testing.T,
+	ctx context.Context,
+	builder *NodeBuilder,
+	delayedInbox *bridgegen.Inbox,
+	lookupL2Tx func(*types.Receipt) *types.Transaction,
+) (common.Hash, *types.Receipt) {
+	t.Helper()
+	usertxopts := builder.L1Info.GetDefaultTransactOpts("Faucet", ctx)
+	usertxopts.Value = arbmath.BigMul(big.NewInt(1e12), big.NewInt(1e12))
+
+	beneficiaryAddress := builder.L2Info.GetAddress("Beneficiary")
+	l1tx, err := delayedInbox.CreateRetryableTicket(
+		&usertxopts,
+		beneficiaryAddress,
+		common.Big0,
+		big.NewInt(1e16),
+		beneficiaryAddress,
+		beneficiaryAddress,
+		common.Big0,
+		common.Big0,
+		nil,
+	)
+	Require(t, err)
+	l1Receipt, err := builder.L1.EnsureTxSucceeded(l1tx)
+	Require(t, err)
+	if l1Receipt.Status != types.ReceiptStatusSuccessful {
+		Fatal(t, "l1Receipt indicated failure")
+	}
+
+	waitForL1DelayBlocks(t, builder)
+
+	receipt, err := builder.L2.EnsureTxSucceeded(lookupL2Tx(l1Receipt))
+	Require(t, err)
+	if len(receipt.Logs) == 0 {
+		Fatal(t, "retryable submission didn't emit a TicketCreated event")
+	}
+	return receipt.Logs[0].Topics[1], receipt
+}
+
+func TestRetryableTimeout(t *testing.T) {
+	t.Parallel()
+	builder, delayedInbox, lookupL2Tx, ctx, teardown := retryableSetup(t)
+	defer teardown()
+
+	ticketId, receipt := submitRetryableWithoutRedeem(t, ctx, builder, delayedInbox, lookupL2Tx)
+	submissionHeader, err := builder.L2Client().HeaderByNumber(ctx, receipt.BlockNumber)
+	Require(t, err)
+
+	arbRetryableTx, err := precompilesgen.NewArbRetryableTx(common.HexToAddress("6e"), builder.L2Client())
+	Require(t, err)
+	timeout, err := arbRetryableTx.GetTimeout(&bind.CallOpts{Context: ctx}, ticketId)
+	Require(t, err)
+
+	expectedTimeout := new(big.Int).SetUint64(submissionHeader.Time + retryables.RetryableLifetimeSeconds)
+	if !arbmath.BigEquals(timeout, expectedTimeout) {
+		Fatal(t, "expected the retryable submitted at", submissionHeader.Time, "to time out at", expectedTimeout, "got", timeout)
+	}
+}
+
+func TestRetryableCancel(t *testing.T) {
+	t.Parallel()
+	builder, delayedInbox, lookupL2Tx, ctx, teardown := retryableSetup(t)
+	defer teardown()
+
+	ticketId, _ := submitRetryableWithoutRedeem(t, ctx, builder, delayedInbox, lookupL2Tx)
+
+	arbRetryableTx, err := precompilesgen.NewArbRetryableTx(common.HexToAddress("6e"), builder.L2Client())
+	Require(t, err)
+	_, err = arbRetryableTx.GetTimeout(&bind.CallOpts{Context: ctx}, ticketId)
+	Require(t, err, "retryable doesn't exist before being canceled")
+
+	beneficiaryTxOpts := builder.L2Info.GetDefaultTransactOpts("Beneficiary", ctx)
+	tx, err := arbRetryableTx.Cancel(&beneficiaryTxOpts, ticketId)
+	Require(t, err)
+	receipt, err := builder.L2.EnsureTxSucceeded(tx)
+	Require(t, err)
+	canceled := false
+	for _, log := range receipt.Logs {
+		if event, err := arbRetryableTx.ParseCanceled(*log); err == nil && event.TicketId == ticketId {
+			canceled = true
+		}
+	}
+	if !canceled {
+		Fatal(t, "cancel didn't emit a Canceled event for ticket", ticketId)
+	}
+
+	_, err = arbRetryableTx.GetTimeout(&bind.CallOpts{Context: ctx}, ticketId)
+	if (err == nil) || (err.Error() != "execution reverted: error NoTicketWithID(): NoTicketWithID()") {
+		Fatal(t, "expected the canceled retryable to no longer exist, got", err)
+	}
+}
+
+func TestRetryableKeepAlive(t *testing.T) {
+	t.Parallel()
+	builder, delayedInbox, lookupL2Tx, ctx, teardown := retryableSetup(t)
+	defer teardown()
+
+	ticketId, _ := submitRetryableWithoutRedeem(t, ctx, builder, delayedInbox, lookupL2Tx)
+
+	arbRetryableTx, err := precompilesgen.NewArbRetryableTx(common.HexToAddress("6e"), builder.L2Client())
+	Require(t, err)
+	callOpts := &bind.CallOpts{Context: ctx}
+	timeoutBefore, err := arbRetryableTx.GetTimeout(callOpts, ticketId)
+	Require(t, err)
+
+	ownerTxOpts := builder.L2Info.GetDefaultTransactOpts("Owner", ctx)
+	tx, err := arbRetryableTx.Keepalive(&ownerTxOpts, ticketId)
+	Require(t, err)
+	receipt, err := builder.L2.EnsureTxSucceeded(tx)
+	Require(t, err)
+	var extended *precompilesgen.ArbRetryableTxLifetimeExtended
+	for _, log := range receipt.Logs {
+		if event, err := arbRetryableTx.ParseLifetimeExtended(*log); err == nil && event.TicketId == ticketId {
+			extended = event
+		}
+	}
+	if extended == nil {
+		Fatal(t, "keepalive didn't emit a LifetimeExtended event for ticket", ticketId)
+	}
+
+	timeoutAfter, err := arbRetryableTx.GetTimeout(callOpts, ticketId)
+	Require(t, err)
+	if !arbmath.BigEquals(timeoutAfter, extended.NewTimeout) {
+		Fatal(t, "LifetimeExtended reports timeout", extended.NewTimeout, "but the retryable's timeout is", timeoutAfter)
+	}
+	// the timeout is extended by one lifetime
+	expected := arbmath.BigAddByUint(timeoutBefore, retryables.RetryableLifetimeSeconds)
+	if !arbmath.BigEquals(timeoutAfter, expected) {
+		Fatal(t, "expected keepalive to extend the timeout from", timeoutBefore, "to", expected, "got", timeoutAfter)
+	}
+
+	_, err = arbRetryableTx.Keepalive(&ownerTxOpts, testhelpers.RandomHash())
+	if err == nil || !strings.Contains(err.Error(), "NoTicketWithID") {
+		Fatal(t, "expected keepalive of a nonexistent ticket to revert with NoTicketWithID, got", err)
+	}
 }
 
 func warpL1Time(t *testing.T, builder *NodeBuilder, ctx context.Context, currentL1time, advanceTime uint64) uint64 {
 	t.Log("Warping L1 time...")
-	l1LatestHeader, err := builder.L1.Client.HeaderByNumber(ctx, big.NewInt(int64(rpc.LatestBlockNumber)))
+	l1LatestHeader, err := builder.L1Client().HeaderByNumber(ctx, big.NewInt(int64(rpc.LatestBlockNumber)))
 	Require(t, err)
 	if currentL1time == 0 {
 		currentL1time = l1LatestHeader.Time
@@ -516,13 +864,13 @@ func TestRetryableExpiry(t *testing.T) {
 	firstRetryTxId := receipt.Logs[1].Topics[2]
 
 	// make sure it failed
-	receipt, err = WaitForTx(ctx, builder.L2.Client, firstRetryTxId, time.Second*5)
+	receipt, err = WaitForTx(ctx, builder.L2Client(), firstRetryTxId, time.Second*5)
 	Require(t, err)
 	if receipt.Status != types.ReceiptStatusFailed {
 		Fatal(t, receipt.GasUsed)
 	}
 
-	arbRetryableTx, err := precompilesgen.NewArbRetryableTx(common.HexToAddress("6e"), builder.L2.Client)
+	arbRetryableTx, err := precompilesgen.NewArbRetryableTx(common.HexToAddress("6e"), builder.L2Client())
 	Require(t, err)
 
 	// check that the ticket exists
@@ -583,13 +931,13 @@ func TestKeepaliveAndRetryableExpiry(t *testing.T) {
 	firstRetryTxId := receipt.Logs[1].Topics[2]
 
 	// make sure it failed
-	receipt, err = WaitForTx(ctx, builder.L2.Client, firstRetryTxId, time.Second*5)
+	receipt, err = WaitForTx(ctx, builder.L2Client(), firstRetryTxId, time.Second*5)
 	Require(t, err)
 	if receipt.Status != types.ReceiptStatusFailed {
 		Fatal(t, receipt.GasUsed)
 	}
 
-	arbRetryableTx, err := precompilesgen.NewArbRetryableTx(common.HexToAddress("6e"), builder.L2.Client)
+	arbRetryableTx, err := precompilesgen.NewArbRetryableTx(common.HexToAddress("6e"), builder.L2Client())
 	Require(t, err)
 
 	// checks that the ticket exists and gets current timeout
@@ -673,13 +1021,13 @@ func TestKeepaliveAndCancelRetryable(t *testing.T) {
 	firstRetryTxId := receipt.Logs[1].Topics[2]
 
 	// make sure it failed
-	receipt, err = WaitForTx(ctx, builder.L2.Client, firstRetryTxId, time.Second*5)
+	receipt, err = WaitForTx(ctx, builder.L2Client(), firstRetryTxId, time.Second*5)
 	Require(t, err)
 	if receipt.Status != types.ReceiptStatusFailed {
 		Fatal(t, receipt.GasUsed)
 	}
 
-	arbRetryableTx, err := precompilesgen.NewArbRetryableTx(common.HexToAddress("6e"), builder.L2.Client)
+	arbRetryableTx, err := precompilesgen.NewArbRetryableTx(common.HexToAddress("6e"), builder.L2Client())
 	Require(t, err)
 
 	// checks that the ticket exists and gets current timeout
@@ -739,12 +1087,12 @@ func TestSubmissionGasCosts(t *testing.T) {
 	colors.PrintBlue("Beneficiary ", beneficiaryAddress)
 	colors.PrintBlue("Fee Refund  ", feeRefundAddress)
 
-	fundsBeforeSubmit, err := builder.L2.Client.BalanceAt(ctx, faucetAddress, nil)
+	fundsBeforeSubmit, err := builder.L2Client().BalanceAt(ctx, faucetAddress, nil)
 	Require(t, err)
 
-	infraBalanceBefore, err := builder.L2.Client.BalanceAt(ctx, infraFeeAddr, nil)
+	infraBalanceBefore, err := builder.L2Client().BalanceAt(ctx, infraFeeAddr, nil)
 	Require(t, err)
-	networkBalanceBefore, err := builder.L2.Client.BalanceAt(ctx, networkFeeAddr, nil)
+	networkBalanceBefore, err := builder.L2Client().BalanceAt(ctx, networkFeeAddr, nil)
 	Require(t, err)
 
 	usefulGas := params.TxGas
@@ -784,12 +1132,12 @@ func TestSubmissionGasCosts(t *testing.T) {
 	}
 	firstRetryTxId := submissionReceipt.Logs[1].Topics[2]
 	// get receipt for the auto redeem
-	redeemReceipt, err := WaitForTx(ctx, builder.L2.Client, firstRetryTxId, time.Second*5)
+	redeemReceipt, err := WaitForTx(ctx, builder.L2Client(), firstRetryTxId, time.Second*5)
 	Require(t, err)
 	if redeemReceipt.Status != types.ReceiptStatusSuccessful {
 		Fatal(t, "first retry tx failed")
 	}
-	redeemBlock, err := builder.L2.Client.HeaderByNumber(ctx, redeemReceipt.BlockNumber)
+	redeemBlock, err := builder.L2Client().HeaderByNumber(ctx, redeemReceipt.BlockNumber)
 	Require(t, err)
 
 	l2BaseFee := redeemBlock.BaseFee
@@ -797,18 +1145,18 @@ func TestSubmissionGasCosts(t *testing.T) {
 	excessWei := arbmath.BigMulByUint(l2BaseFee, excessGasLimit)
 	excessWei.Add(excessWei, arbmath.BigMul(excessGasPrice, retryableGas))
 
-	fundsAfterSubmit, err := builder.L2.Client.BalanceAt(ctx, faucetAddress, nil)
+	fundsAfterSubmit, err := builder.L2Client().BalanceAt(ctx, faucetAddress, nil)
 	Require(t, err)
-	beneficiaryFunds, err := builder.L2.Client.BalanceAt(ctx, beneficiaryAddress, nil)
+	beneficiaryFunds, err := builder.L2Client().BalanceAt(ctx, beneficiaryAddress, nil)
 	Require(t, err)
-	refundFunds, err := builder.L2.Client.BalanceAt(ctx, feeRefundAddress, nil)
+	refundFunds, err := builder.L2Client().BalanceAt(ctx, feeRefundAddress, nil)
 	Require(t, err)
-	receiveFunds, err := builder.L2.Client.BalanceAt(ctx, receiveAddress, nil)
+	receiveFunds, err := builder.L2Client().BalanceAt(ctx, receiveAddress, nil)
 	Require(t, err)
 
-	infraBalanceAfter, err := builder.L2.Client.BalanceAt(ctx, infraFeeAddr, nil)
+	infraBalanceAfter, err := builder.L2Client().BalanceAt(ctx, infraFeeAddr, nil)
 	Require(t, err)
-	networkBalanceAfter, err := builder.L2.Client.BalanceAt(ctx, networkFeeAddr, nil)
+	networkBalanceAfter, err := builder.L2Client().BalanceAt(ctx, networkFeeAddr, nil)
 	Require(t, err)
 
 	colors.PrintBlue("CallGas    ", retryableGas)
@@ -855,7 +1203,7 @@ func TestSubmissionGasCosts(t *testing.T) {
 		Fatal(t, "Supplied gas was improperly deducted\n", fundsBeforeSubmit, "\n", fundsAfterSubmit)
 	}
 
-	arbGasInfo, err := precompilesgen.NewArbGasInfo(common.HexToAddress("0x6c"), builder.L2.Client)
+	arbGasInfo, err := precompilesgen.NewArbGasInfo(common.HexToAddress("0x6c"), builder.L2Client())
 	Require(t, err)
 	minimumBaseFee, err := arbGasInfo.GetMinimumGasPrice(&bind.CallOpts{Context: ctx})
 	Require(t, err)
@@ -896,7 +1244,7 @@ func TestDepositETH(t *testing.T) {
 
 	faucetAddr := builder.L1Info.GetAddress("Faucet")
 
-	oldBalance, err := builder.L2.Client.BalanceAt(ctx, faucetAddr, nil)
+	oldBalance, err := builder.L2Client().BalanceAt(ctx, faucetAddr, nil)
 	if err != nil {
 		t.Fatalf("BalanceAt(%v) unexpected error: %v", faucetAddr, err)
 	}
@@ -922,7 +1270,7 @@ func TestDepositETH(t *testing.T) {
 	if err != nil {
 		t.Fatalf("EnsureTxSucceeded unexpected error: %v", err)
 	}
-	newBalance, err := builder.L2.Client.BalanceAt(ctx, faucetAddr, l2Receipt.BlockNumber)
+	newBalance, err := builder.L2Client().BalanceAt(ctx, faucetAddr, l2Receipt.BlockNumber)
 	if err != nil {
 		t.Fatalf("BalanceAt(%v) unexpected error: %v", faucetAddr, err)
 	}
@@ -1004,7 +1352,7 @@ func TestL1FundedUnsignedTransaction(t *testing.T) {
 	if err != nil {
 		t.Fatalf("Error packing method's call data: %v", err)
 	}
-	nonce, err := builder.L2.Client.NonceAt(ctx, faucetL2Addr, nil)
+	nonce, err := builder.L2Client().NonceAt(ctx, faucetL2Addr, nil)
 	if err != nil {
 		t.Fatalf("Error getting nonce at address: %v, error: %v", faucetL2Addr, err)
 	}
@@ -1019,7 +1367,7 @@ func TestL1FundedUnsignedTransaction(t *testing.T) {
 		Data:      data,
 	})
 
-	delayedInbox, err := bridgegen.NewInbox(builder.L1Info.GetAddress("Inbox"), builder.L1.Client)
+	delayedInbox, err := bridgegen.NewInbox(builder.L1Info.GetAddress("Inbox"), builder.L1Client())
 	if err != nil {
 		t.Fatalf("Error getting Go binding of L1 Inbox contract: %v", err)
 	}
@@ -1066,9 +1414,9 @@ func TestRetryableSubmissionAndRedeemFees(t *testing.T) {
 
 	elevateL2Basefee(t, ctx, builder)
 
-	infraBalanceBefore, err := builder.L2.Client.BalanceAt(ctx, infraFeeAddr, nil)
+	infraBalanceBefore, err := builder.L2Client().BalanceAt(ctx, infraFeeAddr, nil)
 	Require(t, err)
-	networkBalanceBefore, err := builder.L2.Client.BalanceAt(ctx, networkFeeAddr, nil)
+	networkBalanceBefore, err := builder.L2Client().BalanceAt(ctx, networkFeeAddr, nil)
 	Require(t, err)
 
 	beneficiaryAddress := builder.L2Info.GetAddress("Beneficiary")
@@ -1107,19 +1455,19 @@ func TestRetryableSubmissionAndRedeemFees(t *testing.T) {
 	ticketId := submissionReceipt.Logs[0].Topics[1]
 	firstRetryTxId := submissionReceipt.Logs[1].Topics[2]
 	// get receipt for the auto redeem, make sure it failed
-	autoRedeemReceipt, err := WaitForTx(ctx, builder.L2.Client, firstRetryTxId, time.Second*5)
+	autoRedeemReceipt, err := WaitForTx(ctx, builder.L2Client(), firstRetryTxId, time.Second*5)
 	Require(t, err)
 	if autoRedeemReceipt.Status != types.ReceiptStatusFailed {
 		Fatal(t, "first retry tx shouldn't have succeeded")
 	}
 
-	infraBalanceAfterSubmission, err := builder.L2.Client.BalanceAt(ctx, infraFeeAddr, nil)
+	infraBalanceAfterSubmission, err := builder.L2Client().BalanceAt(ctx, infraFeeAddr, nil)
 	Require(t, err)
-	networkBalanceAfterSubmission, err := builder.L2.Client.BalanceAt(ctx, networkFeeAddr, nil)
+	networkBalanceAfterSubmission, err := builder.L2Client().BalanceAt(ctx, networkFeeAddr, nil)
 	Require(t, err)
 
 	usertxoptsL2 := builder.L2Info.GetDefaultTransactOpts("Faucet", ctx)
-	arbRetryableTx, err := precompilesgen.NewArbRetryableTx(common.HexToAddress("6e"), builder.L2.Client)
+	arbRetryableTx, err := precompilesgen.NewArbRetryableTx(common.HexToAddress("6e"), builder.L2Client())
 	Require(t, err)
 	tx, err := arbRetryableTx.Redeem(&usertxoptsL2, ticketId)
 	Require(t, err)
@@ -1128,15 +1476,15 @@ func TestRetryableSubmissionAndRedeemFees(t *testing.T) {
 	retryTxId := redeemReceipt.Logs[0].Topics[2]
 
 	// check the receipt for the retry
-	retryReceipt, err := WaitForTx(ctx, builder.L2.Client, retryTxId, time.Second*1)
+	retryReceipt, err := WaitForTx(ctx, builder.L2Client(), retryTxId, time.Second*1)
 	Require(t, err)
 	if retryReceipt.Status != types.ReceiptStatusSuccessful {
 		Fatal(t, "retry failed")
 	}
 
-	infraBalanceAfterRedeem, err := builder.L2.Client.BalanceAt(ctx, infraFeeAddr, nil)
+	infraBalanceAfterRedeem, err := builder.L2Client().BalanceAt(ctx, infraFeeAddr, nil)
 	Require(t, err)
-	networkBalanceAfterRedeem, err := builder.L2.Client.BalanceAt(ctx, networkFeeAddr, nil)
+	networkBalanceAfterRedeem, err := builder.L2Client().BalanceAt(ctx, networkFeeAddr, nil)
 	Require(t, err)
 
 	// verify that the increment happened, so we know the retry succeeded
@@ -1165,7 +1513,7 @@ func TestRetryableSubmissionAndRedeemFees(t *testing.T) {
 	infraRedeemFee := arbmath.BigSub(infraBalanceAfterRedeem, infraBalanceAfterSubmission)
 	networkRedeemFee := arbmath.BigSub(networkBalanceAfterRedeem, networkBalanceAfterSubmission)
 
-	arbGasInfo, err := precompilesgen.NewArbGasInfo(common.HexToAddress("0x6c"), builder.L2.Client)
+	arbGasInfo, err := precompilesgen.NewArbGasInfo(common.HexToAddress("0x6c"), builder.L2Client())
 	Require(t, err)
 	minimumBaseFee, err := arbGasInfo.GetMinimumGasPrice(&bind.CallOpts{Context: ctx})
 	Require(t, err)
@@ -1183,7 +1531,7 @@ func TestRetryableSubmissionAndRedeemFees(t *testing.T) {
 		retryableSubmissionFee,
 	)
 
-	retryTxOuter, _, err := builder.L2.Client.TransactionByHash(ctx, retryTxId)
+	retryTxOuter, _, err := builder.L2Client().TransactionByHash(ctx, retryTxId)
 	Require(t, err)
 	retryTx, ok := retryTxOuter.GetInner().(*types.ArbitrumRetryTx)
 	if !ok {
@@ -1227,9 +1575,9 @@ func TestRetryableSubmissionAndRedeemFees(t *testing.T) {
 func TestRetryableRedeemBlockGasUsage(t *testing.T) {
 	builder, delayedInbox, lookupL2Tx, ctx, teardown := retryableSetup(t)
 	defer teardown()
-	l2client := builder.L2.Client
+	l2client := builder.L2Client()
 	l2info := builder.L2Info
-	l1client := builder.L1.Client
+	l1client := builder.L1Client()
 	l1info := builder.L1Info
 
 	_, err := precompilesgen.NewArbosTest(common.HexToAddress("0x69"), l2client)
@@ -1333,7 +1681,7 @@ func elevateL2Basefee(t *testing.T, ctx context.Context, builder *NodeBuilder) {
 	colors.PrintBlue("Elevating base fee...")
 	arbosTestAbi, err := precompilesgen.ArbosTestMetaData.GetAbi()
 	Require(t, err)
-	_, err = precompilesgen.NewArbosTest(common.HexToAddress("0x69"), builder.L2.Client)
+	_, err = precompilesgen.NewArbosTest(common.HexToAddress("0x69"), builder.L2Client())
 	Require(t, err, "failed to deploy ArbosTest")
 
 	burnAmount := ExecConfigDefaultTest(t).RPC.RPCGasCap
@@ -1346,7 +1694,7 @@ func elevateL2Basefee(t *testing.T, ctx context.Context, builder *NodeBuilder) {
 		data = append(data, input...)
 		to := common.HexToAddress("0x69")
 		tx := builder.L2Info.PrepareTxTo("Faucet", &to, burnAmount, big.NewInt(0), data)
-		Require(t, builder.L2.Client.SendTransaction(ctx, tx))
+		Require(t, builder.L2Client().SendTransaction(ctx, tx))
 		_, err = builder.L2.EnsureTxSucceeded(tx)
 		Require(t, err)
 	}
@@ -1358,15 +1706,15 @@ func setupFeeAddresses(t *testing.T, ctx context.Context, builder *NodeBuilder)
 	ownerTxOpts := builder.L2Info.GetDefaultTransactOpts("Owner", ctx)
 	ownerCallOpts := builder.L2Info.GetDefaultCallOpts("Owner", ctx)
 	// make "Owner" a chain owner
-	arbdebug, err := precompilesgen.NewArbDebug(common.HexToAddress("0xff"), builder.L2.Client)
+	arbdebug, err := precompilesgen.NewArbDebug(common.HexToAddress("0xff"), builder.L2Client())
 	Require(t, err, "failed to deploy ArbDebug")
 	tx, err := arbdebug.BecomeChainOwner(&ownerTxOpts)
 	Require(t, err, "failed to deploy ArbDebug")
 	_, err = builder.L2.EnsureTxSucceeded(tx)
 	Require(t, err)
-	arbowner, err := precompilesgen.NewArbOwner(common.HexToAddress("70"), builder.L2.Client)
+	arbowner, err := precompilesgen.NewArbOwner(common.HexToAddress("70"), builder.L2Client())
 	Require(t, err)
-	arbownerPublic, err := precompilesgen.NewArbOwnerPublic(common.HexToAddress("6b"), builder.L2.Client)
+	arbownerPublic, err := precompilesgen.NewArbOwnerPublic(common.HexToAddress("6b"), builder.L2Client())
 	Require(t, err)
 	builder.L2Info.GenerateAccount("InfraFee")
 	builder.L2Info.GenerateAccount("NetworkFee")
@@ -7,8 +7,13 @@ import (
 	"context"
 	"path/filepath"
 	"testing"
+	"time"
 
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/ethclient"
+
+	"github.com/offchainlabs/nitro/execution/gethexec"
+	"github.com/offchainlabs/nitro/solgen/go/precompilesgen"
 )
 
 func TestIpcRpc(t *testing.T) {
@@ -25,3 +30,58 @@ func TestIpcRpc(t *testing.T) {
 	_, err := ethclient.Dial(ipcPath)
 	Require(t, err)
 }
+
+func TestArbGetGasOracle(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	builder := NewNodeBuilder(ctx).DefaultConfig(t, true)
+	builder.nodeConfig.DelayedSequencer.FinalizeDistance = 1
+	cleanup := builder.Build(t)
+	defer cleanup()
+
+	// SimulatedBeacon running in OnDemand block production mode
+	// produces blocks in the future so we need this to avoid the batch poster
+	// not posting because the txs appear to be in the future.
+	builder.nodeConfig.BatchPoster.MaxDelay = -time.Hour
+
+	rpcClient := builder.L2.ConsensusNode.Stack.Attach()
+
+	// the L1 pricer only records an update time once a batch posting report is processed
+	var oracle gethexec.GasOracle
+	for i := 0; ; i++ {
+		builder.L2.TransferBalance(t, "Owner", "Owner", common.Big1, builder.L2Info)
+		builder.L1.TransferBalance(t, "Faucet", "Faucet", common.Big1, builder.L1Info) // generate l1 traffic
+
+		err := rpcClient.CallContext(ctx, &oracle, "arb_getGasOracle")
+		Require(t, err)
+		if oracle.LastUpdateTimestamp != 0 {
+			break
+		}
+		if i == 256 {
+			Fatal(t, "L1 pricing never updated")
+		}
+		time.Sleep(time.Millisecond * 100)
+	}
+
+	if oracle.L1BaseFee == nil || oracle.L1BaseFee.Sign() == 0 {
+		Fatal(t, "zero L1 base fee", oracle.L1BaseFee)
+	}
+	if oracle.L1BaseFeeEstimate == nil || oracle.L1BaseFeeEstimate.Sign() == 0 {
+		Fatal(t, "zero L1 base fee estimate", oracle.L1BaseFeeEstimate)
+	}
+	if oracle.L1BlockNumber == 0 {
+		Fatal(t, "zero L1 block number")
+	}
+	if oracle.EstimateInertia == 0 {
+		Fatal(t, "zero estimate inertia")
+	}
+
+	arbGasInfo, err := precompilesgen.NewArbGasInfo(common.HexToAddress("0x6c"), builder.L2Client())
+	Require(t, err)
+	inertia, err := arbGasInfo.GetL1BaseFeeEstimateInertia(builder.L2Info.GetDefaultCallOpts("Owner", ctx))
+	Require(t, err)
+	if inertia != oracle.EstimateInertia {
+		Fatal(t, "unexpected estimate inertia", oracle.EstimateInertia, "expected", inertia)
+	}
+}
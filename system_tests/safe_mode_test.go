@@ -0,0 +1,142 @@
+// Copyright 2024, Offchain Labs, Inc.
+// For license information, see https://github.com/nitro/blob/master/LICENSE
+
+package arbtest
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+
+	"github.com/offchainlabs/nitro/execution/gethexec"
+)
+
+func TestExecutionSafeMode(t *testing.T) {
+	t.Parallel()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	builder := NewNodeBuilder(ctx).DefaultConfig(t, false)
+	cleanup := builder.Build(t)
+	defer cleanup()
+
+	rpcClient := builder.L2.ConsensusNode.Stack.Attach()
+	builder.L2Info.GenerateAccount("User2")
+
+	getHealth := func() gethexec.NodeHealth {
+		var health gethexec.NodeHealth
+		err := rpcClient.CallContext(ctx, &health, "arb_nodeHealth")
+		Require(t, err)
+		return health
+	}
+
+	health := getHealth()
+	if health.Status != "ok" || health.SafeMode != nil {
+		Fatal(t, "unexpected health before safe mode", health)
+	}
+	Require(t, builder.L2.ExecNode.CheckConsistency())
+
+	// simulate a failed consistency check
+	reason := errors.New("simulated database corruption")
+	builder.L2.ExecNode.EnterSafeMode(reason)
+
+	health = getHealth()
+	if health.Status != "degraded" || health.SafeMode == nil || !health.SafeMode.Active {
+		Fatal(t, "expected degraded health in safe mode", health)
+	}
+	if health.SafeMode.Reason != reason.Error() || health.SafeMode.Since == nil {
+		Fatal(t, "unexpected safe mode status", health.SafeMode)
+	}
+
+	// transactions are rejected
+	tx := builder.L2Info.PrepareTx("Owner", "User2", builder.L2Info.TransferGas, big.NewInt(1e12), nil)
+	err := builder.L2Client().SendTransaction(ctx, tx)
+	if err == nil || !strings.Contains(err.Error(), gethexec.ErrSafeMode.Error()) {
+		Fatal(t, "expected transaction to be rejected in safe mode, got", err)
+	}
+	err = rpcClient.CallContext(ctx, nil, "arb_checkPublisherHealth")
+	if err == nil {
+		Fatal(t, "expected publisher to be unhealthy in safe mode")
+	}
+
+	// reads are still served
+	_, err = builder.L2Client().BlockNumber(ctx)
+	Require(t, err)
+	_, err = builder.L2Client().BalanceAt(ctx, builder.L2Info.GetAddress("Owner"), nil)
+	Require(t, err)
+
+	// the sequencer can't be reactivated without acknowledging safe mode
+	builder.L2.ExecNode.Activate()
+	if getHealth().Status != "degraded" {
+		Fatal(t, "activating the sequencer left safe mode")
+	}
+
+	err = rpcClient.CallContext(ctx, nil, "arbadmin_acknowledgeSafeMode")
+	Require(t, err)
+	health = getHealth()
+	if health.Status != "ok" || health.SafeMode != nil {
+		Fatal(t, "unexpected health after acknowledging safe mode", health)
+	}
+
+	// the sequencer resumed, so the same transaction goes through
+	err = builder.L2Client().SendTransaction(ctx, tx)
+	Require(t, err)
+	_, err = builder.L2.EnsureTxSucceeded(tx)
+	Require(t, err)
+}
+
+func TestExecutionSafeModeRepair(t *testing.T) {
+	t.Parallel()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	builder := NewNodeBuilder(ctx).DefaultConfig(t, false)
+	cleanup := builder.Build(t)
+	defer cleanup()
+
+	rpcClient := builder.L2.ConsensusNode.Stack.Attach()
+	builder.L2Info.GenerateAccount("User2")
+	builder.L2.TransferBalance(t, "Owner", "User2", big.NewInt(1e12), builder.L2Info)
+
+	// simulate a corrupt database by pointing the head's canonical hash elsewhere
+	bc := builder.L2.ExecNode.Backend.ArbInterface().BlockChain()
+	head := bc.CurrentBlock()
+	rawdb.WriteCanonicalHash(builder.L2.ExecNode.ChainDB, common.Hash{1}, head.Number.Uint64())
+	if err := builder.L2.ExecNode.CheckConsistency(); err == nil {
+		Fatal(t, "expected the consistency check to fail")
+	}
+
+	// acknowledging rolls back to the head's parent, which is consistent
+	err := rpcClient.CallContext(ctx, nil, "arbadmin_acknowledgeSafeMode")
+	Require(t, err)
+	var health gethexec.NodeHealth
+	err = rpcClient.CallContext(ctx, &health, "arb_nodeHealth")
+	Require(t, err)
+	if health.Status != "ok" {
+		Fatal(t, "expected acknowledging to repair the chain and leave safe mode, got", health)
+	}
+
+	// consensus re-executes the rolled back block
+	for bc.CurrentBlock().Number.Cmp(head.Number) < 0 {
+		select {
+		case <-ctx.Done():
+			Fatal(t, "rolled back block wasn't re-executed")
+		case <-time.After(10 * time.Millisecond):
+		}
+	}
+	if bc.GetCanonicalHash(head.Number.Uint64()) != head.Hash() {
+		Fatal(t, "expected re-executing to recreate block", head.Number, head.Hash())
+	}
+
+	tx := builder.L2Info.PrepareTx("Owner", "User2", builder.L2Info.TransferGas, big.NewInt(1e12), nil)
+	err = builder.L2Client().SendTransaction(ctx, tx)
+	Require(t, err)
+	_, err = builder.L2.EnsureTxSucceeded(tx)
+	Require(t, err)
+}
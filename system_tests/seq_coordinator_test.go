@@ -338,7 +338,7 @@ func testCoordinatorMessageSync(t *testing.T, successCase bool) {
 
 	tx := builder.L2Info.PrepareTx("Owner", "User2", builder.L2Info.TransferGas, big.NewInt(1e12), nil)
 
-	err = builder.L2.Client.SendTransaction(ctx, tx)
+	err = builder.L2Client().SendTransaction(ctx, tx)
 	Require(t, err)
 
 	_, err = builder.L2.EnsureTxSucceeded(tx)
@@ -440,7 +440,7 @@ func TestRedisSwitchover(t *testing.T) {
 func verifyTxIsProcessed(t *testing.T, ctx context.Context, builder *NodeBuilder, testClientB *TestClient, balance int64) {
 	tx := builder.L2Info.PrepareTx("Owner", "User2", builder.L2Info.TransferGas, big.NewInt(1e12), nil)
 
-	err := builder.L2.Client.SendTransaction(ctx, tx)
+	err := builder.L2Client().SendTransaction(ctx, tx)
 	Require(t, err)
 
 	_, err = builder.L2.EnsureTxSucceeded(tx)
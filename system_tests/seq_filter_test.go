@@ -97,7 +97,7 @@ func setupSequencerFilterTest(t *testing.T, isBlockFilter bool) (*NodeBuilder, *
 	var err error
 	for i := 0; latestL2 < 3; i++ {
 		_, _ = builder.L2.TransferBalance(t, "Owner", "User", big.NewInt(1e18), builder.L2Info)
-		latestL2, err = builder.L2.Client.BlockNumber(ctx)
+		latestL2, err = builder.L2Client().BlockNumber(ctx)
 		Require(t, err)
 	}
 
@@ -42,7 +42,7 @@ func TestSequencerParallelNonces(t *testing.T) {
 				// Sleep a random amount of time up to 20 milliseconds
 				time.Sleep(time.Millisecond * time.Duration(rand.Intn(20)))
 				t.Log("Submitting transaction with nonce", tx.Nonce())
-				err := builder.L2.Client.SendTransaction(ctx, tx)
+				err := builder.L2Client().SendTransaction(ctx, tx)
 				Require(t, err)
 				t.Log("Got response for transaction with nonce", tx.Nonce())
 			}
@@ -51,7 +51,7 @@ func TestSequencerParallelNonces(t *testing.T) {
 	wg.Wait()
 
 	addr := builder.L2Info.GetAddress("Destination")
-	balance, err := builder.L2.Client.BalanceAt(ctx, addr, nil)
+	balance, err := builder.L2Client().BalanceAt(ctx, addr, nil)
 	Require(t, err)
 	if !arbmath.BigEquals(balance, big.NewInt(100)) {
 		Fatal(t, "Unexpected user balance", balance)
@@ -72,7 +72,7 @@ func TestSequencerNonceTooHigh(t *testing.T) {
 
 	before := time.Now()
 	tx := builder.L2Info.PrepareTx("Owner", "Owner", builder.L2Info.TransferGas, common.Big0, nil)
-	err := builder.L2.Client.SendTransaction(ctx, tx)
+	err := builder.L2Client().SendTransaction(ctx, tx)
 	if err == nil {
 		Fatal(t, "No error when nonce was too high")
 	}
@@ -103,7 +103,7 @@ func TestSequencerNonceTooHighQueueFull(t *testing.T) {
 		builder.L2Info.GetInfoWithPrivKey("Owner").Nonce.Add(1)
 		tx := builder.L2Info.PrepareTx("Owner", "Owner", builder.L2Info.TransferGas, common.Big0, nil)
 		go func() {
-			err := builder.L2.Client.SendTransaction(ctx, tx)
+			err := builder.L2Client().SendTransaction(ctx, tx)
 			if err == nil {
 				Fatal(t, "No error when nonce was too high")
 			}
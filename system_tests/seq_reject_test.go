@@ -61,7 +61,7 @@ func TestSequencerRejection(t *testing.T) {
 		name := fmt.Sprintf("User%v", user)
 		tx := builderSeq.L2Info.PrepareTx("Owner", name, builderSeq.L2Info.TransferGas, big.NewInt(params.Ether), nil)
 
-		err := builderSeq.L2.Client.SendTransaction(ctx, tx)
+		err := builderSeq.L2Client().SendTransaction(ctx, tx)
 		Require(t, err)
 
 		_, err = builderSeq.L2.EnsureTxSucceeded(tx)
@@ -93,7 +93,7 @@ func TestSequencerRejection(t *testing.T) {
 					expectedErr = "nonce too high"
 				}
 				tx = builderSeq.L2Info.SignTxAs(name, txData)
-				err = builderSeq.L2.Client.SendTransaction(ctx, tx)
+				err = builderSeq.L2Client().SendTransaction(ctx, tx)
 				if err != nil && (expectedErr == "" || !strings.Contains(err.Error(), expectedErr)) {
 					Require(t, err, "failed to send tx for user", user)
 				}
@@ -102,7 +102,7 @@ func TestSequencerRejection(t *testing.T) {
 	}
 
 	for i := 100; i >= 0; i-- {
-		block, err := builderSeq.L2.Client.BlockNumber(ctx)
+		block, err := builderSeq.L2Client().BlockNumber(ctx)
 		Require(t, err)
 		if block >= 200 {
 			break
@@ -120,11 +120,11 @@ func TestSequencerRejection(t *testing.T) {
 	stopBackground.Store(1)
 	wg.Wait()
 
-	header1, err := builderSeq.L2.Client.HeaderByNumber(ctx, nil)
+	header1, err := builderSeq.L2Client().HeaderByNumber(ctx, nil)
 	Require(t, err)
 
 	for i := 100; i >= 0; i-- {
-		header2, err := builder.L2.Client.HeaderByNumber(ctx, header1.Number)
+		header2, err := builder.L2Client().HeaderByNumber(ctx, header1.Number)
 		if err != nil {
 			select {
 			case err := <-feedErrChan:
@@ -132,7 +132,7 @@ func TestSequencerRejection(t *testing.T) {
 			case <-time.After(time.Millisecond * 100):
 			}
 			if i == 0 {
-				client2Block, _ := builder.L2.Client.BlockNumber(ctx)
+				client2Block, _ := builder.L2Client().BlockNumber(ctx)
 				Fatal(t, "client2 failed to reach client1 block ", header1.Number, ", only reached block", client2Block)
 			}
 			continue
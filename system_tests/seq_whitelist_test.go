@@ -32,7 +32,7 @@ func TestSequencerWhitelist(t *testing.T) {
 
 	// User2 is *not* on the whitelist, therefore this should fail
 	tx := builder.L2Info.PrepareTx("User2", "User", builder.L2Info.TransferGas, big.NewInt(params.Ether/10), nil)
-	err := builder.L2.Client.SendTransaction(ctx, tx)
+	err := builder.L2Client().SendTransaction(ctx, tx)
 	if err == nil {
 		Fatal(t, "transaction from user not on whitelist accepted")
 	}
@@ -29,7 +29,7 @@ func TestSequencerCompensation(t *testing.T) {
 	builder.L2Info.GenerateAccount("User2")
 
 	tx := builder.L2Info.PrepareTx("Owner", "User2", builder.L2Info.TransferGas, big.NewInt(1e12), nil)
-	err := builder.L2.Client.SendTransaction(ctx, tx)
+	err := builder.L2Client().SendTransaction(ctx, tx)
 	Require(t, err)
 	_, err = builder.L2.EnsureTxSucceeded(tx)
 	Require(t, err)
@@ -55,31 +55,19 @@ func TestSequencerFeed(t *testing.T) {
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 
-	builderSeq := NewNodeBuilder(ctx).DefaultConfig(t, false)
-	builderSeq.nodeConfig.Feed.Output = *newBroadcasterConfigTest()
-	cleanupSeq := builderSeq.Build(t)
-	defer cleanupSeq()
-	seqInfo, seqNode, seqClient := builderSeq.L2Info, builderSeq.L2.ConsensusNode, builderSeq.L2.Client
-
-	port := testhelpers.AddrTCPPort(seqNode.BroadcastServer.ListenerAddr(), t)
-	builder := NewNodeBuilder(ctx).DefaultConfig(t, false)
-	builder.nodeConfig.Feed.Input = *newBroadcastClientConfigTest(port)
-	builder.takeOwnership = false
+	builder := NewNodeBuilder(ctx).DefaultConfig(t, false).WithReplica(nil)
 	cleanup := builder.Build(t)
 	defer cleanup()
-	client := builder.L2.Client
+	seqInfo, client := builder.L2Info, builder.L2Replica.Client
 
 	seqInfo.GenerateAccount("User2")
 
 	tx := seqInfo.PrepareTx("Owner", "User2", seqInfo.TransferGas, big.NewInt(1e12), nil)
 
-	err := seqClient.SendTransaction(ctx, tx)
-	Require(t, err)
-
-	_, err = builderSeq.L2.EnsureTxSucceeded(tx)
+	err := builder.L2Client().SendTransaction(ctx, tx)
 	Require(t, err)
 
-	_, err = WaitForTx(ctx, client, tx.Hash(), time.Second*5)
+	_, err = builder.L2Replica.EnsureTxSucceeded(tx)
 	Require(t, err)
 	l2balance, err := client.BalanceAt(ctx, seqInfo.GetAddress("User2"), nil)
 	Require(t, err)
@@ -101,7 +89,7 @@ func TestRelayedSequencerFeed(t *testing.T) {
 	builderSeq.nodeConfig.Feed.Output = *newBroadcasterConfigTest()
 	cleanupSeq := builderSeq.Build(t)
 	defer cleanupSeq()
-	seqInfo, seqNode, seqClient := builderSeq.L2Info, builderSeq.L2.ConsensusNode, builderSeq.L2.Client
+	seqInfo, seqNode, seqClient := builderSeq.L2Info, builderSeq.L2.ConsensusNode, builderSeq.L2Client()
 
 	bigChainId, err := seqClient.ChainID(ctx)
 	Require(t, err)
@@ -125,7 +113,7 @@ func TestRelayedSequencerFeed(t *testing.T) {
 	builder.takeOwnership = false
 	cleanup := builder.Build(t)
 	defer cleanup()
-	node, client := builder.L2.ConsensusNode, builder.L2.Client
+	node, client := builder.L2.ConsensusNode, builder.L2Client()
 	StartWatchChanErr(t, ctx, feedErrChan, node)
 
 	seqInfo.GenerateAccount("User2")
@@ -205,9 +193,9 @@ func testLyingSequencer(t *testing.T, dasModeStr string) {
 	cleanup := builder.Build(t)
 	defer cleanup()
 
-	l2clientA := builder.L2.Client
+	l2clientA := builder.L2Client()
 
-	authorizeDASKeyset(t, ctx, dasSignerKey, builder.L1Info, builder.L1.Client)
+	authorizeDASKeyset(t, ctx, dasSignerKey, builder.L1Info, builder.L1Client())
 
 	// The lying sequencer
 	nodeConfigC := arbnode.ConfigDefaultL1Test()
@@ -452,7 +440,7 @@ func TestPopulateFeedBacklog(t *testing.T) {
 
 	// Sends a transaction
 	tx := builder.L2Info.PrepareTx("Owner", userAccount, builder.L2Info.TransferGas, big.NewInt(1e12), nil)
-	err := builder.L2.Client.SendTransaction(ctx, tx)
+	err := builder.L2Client().SendTransaction(ctx, tx)
 	Require(t, err)
 	_, err = builder.L2.EnsureTxSucceeded(tx)
 	Require(t, err)
@@ -490,3 +478,40 @@ func TestPopulateFeedBacklog(t *testing.T) {
 		t.Fatal("BlockHashMismatchLogMsg was logged unexpectedly")
 	}
 }
+
+func TestPopulateFeedBacklogStartupLimit(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	builder := NewNodeBuilder(ctx).DefaultConfig(t, true)
+	builder.BuildL1(t)
+
+	userAccount := "User2"
+	builder.L2Info.GenerateAccount(userAccount)
+
+	builder.nodeConfig.BatchPoster.Enable = false
+	builder.BuildL2OnL1(t)
+
+	dataDir := builder.l2StackConfig.DataDir
+
+	for i := 0; i < 5; i++ {
+		tx := builder.L2Info.PrepareTx("Owner", userAccount, builder.L2Info.TransferGas, big.NewInt(1e12), nil)
+		err := builder.L2Client().SendTransaction(ctx, tx)
+		Require(t, err)
+		_, err = builder.L2.EnsureTxSucceeded(tx)
+		Require(t, err)
+	}
+
+	// Restarts the node with the backlog limited to the most recent message on startup
+	builder.L2.cleanup()
+	builder.l2StackConfig.DataDir = dataDir
+	builder.nodeConfig.Feed.Output = *newBroadcasterConfigTest()
+	builder.nodeConfig.Feed.Output.Backlog.StartupMaxMessages = 1
+	cleanup := builder.BuildL2OnL1(t)
+	defer cleanup()
+
+	cached := builder.L2.ConsensusNode.BroadcastServer.GetCachedMessageCount()
+	if cached != 1 {
+		t.Fatal("unexpected number of messages in feed backlog after startup:", cached)
+	}
+}
@@ -97,7 +97,7 @@ func deployGasRefunder(ctx context.Context, t *testing.T, builder *NodeBuilder)
 		t.Fatalf("Error getting gas refunder abi: %v", err)
 	}
 	fauOpts := builder.L1Info.GetDefaultTransactOpts("Faucet", ctx)
-	addr, tx, _, err := bind.DeployContract(&fauOpts, *abi, common.FromHex(bridgegen.GasRefunderBin), builder.L1.Client)
+	addr, tx, _, err := bind.DeployContract(&fauOpts, *abi, common.FromHex(bridgegen.GasRefunderBin), builder.L1Client())
 	if err != nil {
 		t.Fatalf("Error getting gas refunder contract deployment transaction: %v", err)
 	}
@@ -105,13 +105,13 @@ func deployGasRefunder(ctx context.Context, t *testing.T, builder *NodeBuilder)
 		t.Fatalf("Error deploying gas refunder contract: %v", err)
 	}
 	tx = builder.L1Info.PrepareTxTo("Faucet", &addr, 30000, big.NewInt(9223372036854775807), nil)
-	if err := builder.L1.Client.SendTransaction(ctx, tx); err != nil {
+	if err := builder.L1Client().SendTransaction(ctx, tx); err != nil {
 		t.Fatalf("Error sending gas refunder funding transaction")
 	}
 	if _, err := builder.L1.EnsureTxSucceeded(tx); err != nil {
 		t.Fatalf("Error funding gas refunder")
 	}
-	contract, err := bridgegen.NewGasRefunder(addr, builder.L1.Client)
+	contract, err := bridgegen.NewGasRefunder(addr, builder.L1Client())
 	if err != nil {
 		t.Fatalf("Error getting gas refunder contract binding: %v", err)
 	}
@@ -153,7 +153,7 @@ func testSequencerInboxReaderImpl(t *testing.T, validator bool) {
 	rpcC := builder.L1.Stack.Attach()
 	gethClient := gethclient.New(rpcC)
 
-	seqInbox, err := bridgegen.NewSequencerInbox(builder.L1Info.GetAddress("SequencerInbox"), builder.L1.Client)
+	seqInbox, err := bridgegen.NewSequencerInbox(builder.L1Info.GetAddress("SequencerInbox"), builder.L1Client())
 	Require(t, err)
 	seqOpts := builder.L1Info.GetDefaultTransactOpts("Sequencer", ctx)
 
@@ -222,11 +222,11 @@ func testSequencerInboxReaderImpl(t *testing.T, validator bool) {
 					Nonce:     j,
 				}
 				tx := builder.L1Info.SignTxAs("ReorgPadding", rawTx)
-				Require(t, builder.L1.Client.SendTransaction(ctx, tx))
+				Require(t, builder.L1Client().SendTransaction(ctx, tx))
 				_, _ = builder.L1.EnsureTxSucceeded(tx)
 			}
 			reorgTargetNumber := blockStates[reorgTo].l1BlockNumber
-			currentHeader, err := builder.L1.Client.HeaderByNumber(ctx, nil)
+			currentHeader, err := builder.L1Client().HeaderByNumber(ctx, nil)
 			Require(t, err)
 			// #nosec G115
 			if currentHeader.Number.Int64()-int64(reorgTargetNumber) < 65 {
@@ -243,9 +243,9 @@ func testSequencerInboxReaderImpl(t *testing.T, validator bool) {
 			// To work around this, we create a sacrificial tx, which may or may not succeed.
 			// Whichever happens, by the end of this block, the miner will have processed the reorg.
 			tx := builder.L1Info.PrepareTx(fmt.Sprintf("ReorgSacrifice%v", i/10), "Faucet", 30000, big.NewInt(0), nil)
-			err = builder.L1.Client.SendTransaction(ctx, tx)
+			err = builder.L1Client().SendTransaction(ctx, tx)
 			Require(t, err)
-			_, _ = WaitForTx(ctx, builder.L1.Client, tx.Hash(), time.Second)
+			_, _ = WaitForTx(ctx, builder.L1Client(), tx.Hash(), time.Second)
 		} else {
 			state := blockStates[len(blockStates)-1]
 			newBalances := make(map[common.Address]*big.Int)
@@ -312,7 +312,7 @@ func testSequencerInboxReaderImpl(t *testing.T, validator bool) {
 
 			seqNonce := len(blockStates) - 1
 			for j := 0; ; j++ {
-				haveNonce, err := builder.L1.Client.PendingNonceAt(ctx, seqOpts.From)
+				haveNonce, err := builder.L1Client().PendingNonceAt(ctx, seqOpts.From)
 				Require(t, err)
 				// #nosec G115
 				if haveNonce == uint64(seqNonce) {
@@ -325,7 +325,7 @@ func testSequencerInboxReaderImpl(t *testing.T, validator bool) {
 			}
 			seqOpts.Nonce = big.NewInt(int64(seqNonce))
 			var tx *types.Transaction
-			before, err := builder.L1.Client.BalanceAt(ctx, seqOpts.From, nil)
+			before, err := builder.L1Client().BalanceAt(ctx, seqOpts.From, nil)
 			if err != nil {
 				t.Fatalf("BalanceAt(%v) unexpected error: %v", seqOpts.From, err)
 			}
@@ -367,11 +367,11 @@ func testSequencerInboxReaderImpl(t *testing.T, validator bool) {
 				// Specifically, I suspect there's a race where it thinks there's no txs to put in the new block,
 				// if a new tx arrives at the same time as it tries to create a block.
 				// Resubmit the transaction in an attempt to get the miner going again.
-				_ = builder.L1.Client.SendTransaction(ctx, tx)
+				_ = builder.L1Client().SendTransaction(ctx, tx)
 				txRes, err = builder.L1.EnsureTxSucceeded(tx)
 				Require(t, err)
 			}
-			after, err := builder.L1.Client.BalanceAt(ctx, seqOpts.From, nil)
+			after, err := builder.L1Client().BalanceAt(ctx, seqOpts.From, nil)
 			if err != nil {
 				t.Fatalf("BalanceAt(%v) unexpected error: %v", seqOpts.From, err)
 			}
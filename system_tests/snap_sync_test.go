@@ -57,7 +57,7 @@ func TestSnapSync(t *testing.T) {
 	// Create transactions till batch count is 10
 	createTransactionTillBatchCount(ctx, t, builder, 10)
 	// Wait for nodeB to sync up to the first node
-	waitForBlocksToCatchup(ctx, t, builder.L2.Client, nodeB.Client)
+	waitForBlocksToCatchup(ctx, t, builder.L2Client(), nodeB.Client)
 
 	// Create a config with snap sync enabled and same database directory as the 2nd node
 	nodeConfig := createNodeConfigWithSnapSync(t, builder)
@@ -88,12 +88,12 @@ func TestSnapSync(t *testing.T) {
 		t.Error("Batch metadata mismatch")
 	}
 	finalMessageCount := uint64(metadata.MessageCount)
-	waitForBlockToCatchupToMessageCount(ctx, t, builder.L2.Client, finalMessageCount)
+	waitForBlockToCatchupToMessageCount(ctx, t, builder.L2Client(), finalMessageCount)
 	waitForBlockToCatchupToMessageCount(ctx, t, nodeC.Client, finalMessageCount)
 	// Fetching message count - 1 instead on the latest block number as the latest block number might not be
 	// present in the snap sync node since it does not have the sequencer feed.
 	// #nosec G115
-	header, err := builder.L2.Client.HeaderByNumber(ctx, big.NewInt(int64(finalMessageCount)-1))
+	header, err := builder.L2Client().HeaderByNumber(ctx, big.NewInt(int64(finalMessageCount)-1))
 	Require(t, err)
 	// #nosec G115
 	headerNodeC, err := nodeC.Client.HeaderByNumber(ctx, big.NewInt(int64(finalMessageCount)-1))
@@ -169,7 +169,7 @@ func createTransactionTillBatchCount(ctx context.Context, t *testing.T, builder
 	for {
 		Require(t, ctx.Err())
 		tx := builder.L2Info.PrepareTx("Faucet", "BackgroundUser", builder.L2Info.TransferGas, big.NewInt(1), nil)
-		err := builder.L2.Client.SendTransaction(ctx, tx)
+		err := builder.L2Client().SendTransaction(ctx, tx)
 		Require(t, err)
 		_, err = builder.L2.EnsureTxSucceeded(tx)
 		Require(t, err)
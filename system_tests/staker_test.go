@@ -47,7 +47,7 @@ func makeBackgroundTxs(ctx context.Context, builder *NodeBuilder) error {
 	for i := uint64(0); ctx.Err() == nil; i++ {
 		builder.L2Info.Accounts["BackgroundUser"].Nonce.Store(i)
 		tx := builder.L2Info.PrepareTx("BackgroundUser", "BackgroundUser", builder.L2Info.TransferGas, common.Big0, nil)
-		err := builder.L2.Client.SendTransaction(ctx, tx)
+		err := builder.L2Client().SendTransaction(ctx, tx)
 		if err != nil {
 			return err
 		}
@@ -136,10 +136,10 @@ func stakerTestImpl(t *testing.T, faultyStaker bool, honestStakerInactive bool)
 	builder.L1.TransferBalance(t, "Faucet", "ValidatorB", balance, builder.L1Info)
 	l1authB := builder.L1Info.GetDefaultTransactOpts("ValidatorB", ctx)
 
-	rollup, err := rollupgen.NewRollupAdminLogic(l2nodeA.DeployInfo.Rollup, builder.L1.Client)
+	rollup, err := rollupgen.NewRollupAdminLogic(l2nodeA.DeployInfo.Rollup, builder.L1Client())
 	Require(t, err)
 
-	upgradeExecutor, err := upgrade_executorgen.NewUpgradeExecutor(l2nodeA.DeployInfo.UpgradeExecutor, builder.L1.Client)
+	upgradeExecutor, err := upgrade_executorgen.NewUpgradeExecutor(l2nodeA.DeployInfo.UpgradeExecutor, builder.L1Client())
 	Require(t, err, "unable to bind upgrade executor")
 	rollupABI, err := abi.JSON(strings.NewReader(rollupgen.RollupAdminLogicABI))
 	Require(t, err, "unable to parse rollup ABI")
@@ -151,11 +151,11 @@ func stakerTestImpl(t *testing.T, faultyStaker bool, honestStakerInactive bool)
 	_, err = builder.L1.EnsureTxSucceeded(tx)
 	Require(t, err)
 
-	validatorUtils, err := rollupgen.NewValidatorUtils(l2nodeA.DeployInfo.ValidatorUtils, builder.L1.Client)
+	validatorUtils, err := rollupgen.NewValidatorUtils(l2nodeA.DeployInfo.ValidatorUtils, builder.L1Client())
 	Require(t, err)
 
 	valConfigA := legacystaker.TestL1ValidatorConfig
-	parentChainID, err := builder.L1.Client.ChainID(ctx)
+	parentChainID, err := builder.L1Client().ChainID(ctx)
 	if err != nil {
 		t.Fatalf("Failed to get parent chain id: %v", err)
 	}
@@ -282,7 +282,7 @@ func stakerTestImpl(t *testing.T, faultyStaker bool, honestStakerInactive bool)
 		err = valWalletB.Initialize(ctx)
 		Require(t, err)
 	}
-	valWalletC := validatorwallet.NewNoOp(builder.L1.Client, l2nodeA.DeployInfo.Rollup)
+	valWalletC := validatorwallet.NewNoOp(builder.L1Client(), l2nodeA.DeployInfo.Rollup)
 	valConfigC := legacystaker.TestL1ValidatorConfig
 	valConfigC.Strategy = "Watchtower"
 	stakerC, err := legacystaker.NewStaker(
@@ -307,7 +307,7 @@ func stakerTestImpl(t *testing.T, faultyStaker bool, honestStakerInactive bool)
 
 	builder.L2Info.GenerateAccount("BackgroundUser")
 	tx = builder.L2Info.PrepareTx("Faucet", "BackgroundUser", builder.L2Info.TransferGas, balance, nil)
-	err = builder.L2.Client.SendTransaction(ctx, tx)
+	err = builder.L2Client().SendTransaction(ctx, tx)
 	Require(t, err)
 	_, err = builder.L2.EnsureTxSucceeded(tx)
 	Require(t, err)
@@ -364,7 +364,7 @@ func stakerTestImpl(t *testing.T, faultyStaker bool, honestStakerInactive bool)
 				if !challengeMangerTimedOut {
 					// Upgrade the ChallengeManager contract to an implementation which says challenges are always timed out
 
-					mockImpl, tx, _, err := mocksgen.DeployTimedOutChallengeManager(&deployAuth, builder.L1.Client)
+					mockImpl, tx, _, err := mocksgen.DeployTimedOutChallengeManager(&deployAuth, builder.L1Client())
 					Require(t, err)
 					_, err = builder.L1.EnsureTxSucceeded(tx)
 					Require(t, err)
@@ -372,7 +372,7 @@ func stakerTestImpl(t *testing.T, faultyStaker bool, honestStakerInactive bool)
 					managerAddr := valWalletA.ChallengeManagerAddress()
 					// 0xb53127684a568b3173ae13b9f8a6016e243e63b6e8ee1178d6a717850b5d6103
 					proxyAdminSlot := common.BigToHash(arbmath.BigSub(crypto.Keccak256Hash([]byte("eip1967.proxy.admin")).Big(), common.Big1))
-					proxyAdminBytes, err := builder.L1.Client.StorageAt(ctx, managerAddr, proxyAdminSlot, nil)
+					proxyAdminBytes, err := builder.L1Client().StorageAt(ctx, managerAddr, proxyAdminSlot, nil)
 					Require(t, err)
 					proxyAdminAddr := common.BytesToAddress(proxyAdminBytes)
 					if proxyAdminAddr == (common.Address{}) {
@@ -494,7 +494,7 @@ func TestGetValidatorWalletContractWithDataposterOnlyUsedToCreateValidatorWallet
 	builder.L1.TransferBalance(t, "Faucet", "ValidatorA", balance, builder.L1Info)
 	l1auth := builder.L1Info.GetDefaultTransactOpts("ValidatorA", ctx)
 
-	parentChainID, err := builder.L1.Client.ChainID(ctx)
+	parentChainID, err := builder.L1Client().ChainID(ctx)
 	Require(t, err)
 
 	dataPoster, err := arbnode.DataposterOnlyUsedToCreateValidatorWalletContract(
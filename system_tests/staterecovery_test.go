@@ -38,14 +38,14 @@ func TestRectreateMissingStates(t *testing.T) {
 	for i := uint64(0); i < 200; i++ {
 		tx := builder.L2Info.PrepareTx("Owner", "User2", builder.L2Info.TransferGas, common.Big1, nil)
 		txs = append(txs, tx)
-		err := builder.L2.Client.SendTransaction(ctx, tx)
+		err := builder.L2Client().SendTransaction(ctx, tx)
 		Require(t, err)
 	}
 	for _, tx := range txs {
 		_, err := builder.L2.EnsureTxSucceeded(tx)
 		Require(t, err)
 	}
-	lastBlock, err := builder.L2.Client.BlockNumber(ctx)
+	lastBlock, err := builder.L2Client().BlockNumber(ctx)
 	Require(t, err)
 	l2cleanupDone = true
 	builder.L2.cleanup()
@@ -50,7 +50,7 @@ func sendAndTraceTransaction(
 	data []byte,
 ) logger.ExecutionResult {
 	ctx := builder.ctx
-	l2client := builder.L2.Client
+	l2client := builder.L2Client()
 	l2info := builder.L2Info
 	rpcClient := builder.L2.ConsensusNode.Stack.Attach()
 
@@ -87,7 +87,7 @@ func TestStylusOpcodeTraceStorage(t *testing.T) {
 	const jit = false
 	builder, auth, cleanup := setupProgramTest(t, jit)
 	ctx := builder.ctx
-	l2client := builder.L2.Client
+	l2client := builder.L2Client()
 	defer cleanup()
 
 	program := deployWasm(t, ctx, auth, l2client, rustFile("storage"))
@@ -123,7 +123,7 @@ func TestStylusOpcodeTraceNativeKeccak(t *testing.T) {
 	const jit = false
 	builder, auth, cleanup := setupProgramTest(t, jit)
 	ctx := builder.ctx
-	l2client := builder.L2.Client
+	l2client := builder.L2Client()
 	defer cleanup()
 
 	program := deployWasm(t, ctx, auth, l2client, watFile("timings/keccak"))
@@ -142,7 +142,7 @@ func TestStylusOpcodeTraceMath(t *testing.T) {
 	const jit = false
 	builder, auth, cleanup := setupProgramTest(t, jit)
 	ctx := builder.ctx
-	l2client := builder.L2.Client
+	l2client := builder.L2Client()
 	defer cleanup()
 
 	program := deployWasm(t, ctx, auth, l2client, rustFile("math"))
@@ -184,7 +184,7 @@ func TestStylusOpcodeTraceExit(t *testing.T) {
 	const jit = false
 	builder, auth, cleanup := setupProgramTest(t, jit)
 	ctx := builder.ctx
-	l2client := builder.L2.Client
+	l2client := builder.L2Client()
 	defer cleanup()
 
 	// normal exit with return value
@@ -211,7 +211,7 @@ func TestStylusOpcodeTraceEvmData(t *testing.T) {
 	builder, auth, cleanup := setupProgramTest(t, jit)
 	ctx := builder.ctx
 	l2info := builder.L2Info
-	l2client := builder.L2.Client
+	l2client := builder.L2Client()
 	defer cleanup()
 
 	program := deployWasm(t, ctx, auth, l2client, rustFile("evm-data"))
@@ -318,7 +318,7 @@ func TestStylusOpcodeTraceLog(t *testing.T) {
 	const jit = false
 	builder, auth, cleanup := setupProgramTest(t, jit)
 	ctx := builder.ctx
-	l2client := builder.L2.Client
+	l2client := builder.L2Client()
 	defer cleanup()
 
 	program := deployWasm(t, ctx, auth, l2client, rustFile("log"))
@@ -344,7 +344,7 @@ func TestStylusOpcodeTraceReturnDataSize(t *testing.T) {
 	const jit = false
 	builder, auth, cleanup := setupProgramTest(t, jit)
 	ctx := builder.ctx
-	l2client := builder.L2.Client
+	l2client := builder.L2Client()
 	defer cleanup()
 
 	program := deployWasm(t, ctx, auth, l2client, watFile("timings/return_data_size"))
@@ -360,7 +360,7 @@ func TestStylusOpcodeTraceCall(t *testing.T) {
 	const jit = false
 	builder, auth, cleanup := setupProgramTest(t, jit)
 	ctx := builder.ctx
-	l2client := builder.L2.Client
+	l2client := builder.L2Client()
 	defer cleanup()
 
 	storage := deployWasm(t, ctx, auth, l2client, rustFile("storage"))
@@ -393,7 +393,7 @@ func TestStylusOpcodeTraceCreate(t *testing.T) {
 	const jit = false
 	builder, auth, cleanup := setupProgramTest(t, jit)
 	ctx := builder.ctx
-	l2client := builder.L2.Client
+	l2client := builder.L2Client()
 	defer cleanup()
 
 	program := deployWasm(t, ctx, auth, l2client, rustFile("create"))
@@ -430,7 +430,7 @@ func TestStylusOpcodeTraceEquivalence(t *testing.T) {
 	const jit = false
 	builder, auth, cleanup := setupProgramTest(t, jit)
 	ctx := builder.ctx
-	l2client := builder.L2.Client
+	l2client := builder.L2Client()
 	defer cleanup()
 
 	key := testhelpers.RandomHash()
@@ -447,7 +447,7 @@ func TestStylusOpcodeTraceEquivalence(t *testing.T) {
 	wasmResult := sendAndTraceTransaction(t, builder, wasmMulticall, nil, wasmArgs)
 
 	// Trace recursive call in evm
-	evmMulticall, tx, _, err := mocksgen.DeployMultiCallTest(&auth, builder.L2.Client)
+	evmMulticall, tx, _, err := mocksgen.DeployMultiCallTest(&auth, builder.L2Client())
 	Require(t, err)
 	_, err = EnsureTxSucceeded(ctx, l2client, tx)
 	Require(t, err)
@@ -486,7 +486,7 @@ func TestStylusHugeWriteResultTrace(t *testing.T) {
 	const jit = false
 	builder, auth, cleanup := setupProgramTest(t, jit)
 	ctx := builder.ctx
-	l2client := builder.L2.Client
+	l2client := builder.L2Client()
 	defer cleanup()
 
 	program := deployWasm(t, ctx, auth, l2client, watFile("write-result-len"))
@@ -22,9 +22,9 @@ func TestStylusTracer(t *testing.T) {
 	const jit = false
 	builder, auth, cleanup := setupProgramTest(t, jit)
 	ctx := builder.ctx
-	l2client := builder.L2.Client
+	l2client := builder.L2Client()
 	l2info := builder.L2Info
-	rpcClient := builder.L2.Client.Client()
+	rpcClient := builder.L2Client().Client()
 	defer cleanup()
 
 	traceTransaction := func(tx common.Hash, tracer string) []gethexec.HostioTraceInfo {
@@ -41,7 +41,7 @@ func TestStylusTracer(t *testing.T) {
 
 	// Deploy contracts
 	stylusMulticall := deployWasm(t, ctx, auth, l2client, rustFile("multicall"))
-	evmMulticall, tx, _, err := mocksgen.DeployMultiCallTest(&auth, builder.L2.Client)
+	evmMulticall, tx, _, err := mocksgen.DeployMultiCallTest(&auth, builder.L2Client())
 	Require(t, err, "deploy evm multicall")
 	_, err = EnsureTxSucceeded(ctx, l2client, tx)
 	Require(t, err, "ensure evm multicall deployment")
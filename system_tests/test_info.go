@@ -18,6 +18,7 @@ import (
 	"github.com/ethereum/go-ethereum/log"
 	"github.com/ethereum/go-ethereum/params"
 
+	"github.com/offchainlabs/nitro/arbnode/dataposter"
 	"github.com/offchainlabs/nitro/arbos/l2pricing"
 	"github.com/offchainlabs/nitro/statetransfer"
 	"github.com/offchainlabs/nitro/util"
@@ -28,7 +29,9 @@ var simulatedChainID = big.NewInt(1337)
 type AccountInfo struct {
 	Address    common.Address
 	PrivateKey *ecdsa.PrivateKey
-	Nonce      atomic.Uint64
+	// ExternalSigner, if set, signs the account's transactions instead of a local private key
+	ExternalSigner dataposter.SignerFn
+	Nonce          atomic.Uint64
 }
 
 type BlockchainTestInfo struct {
@@ -138,12 +141,48 @@ func (b *BlockchainTestInfo) SetContract(name string, address common.Address) {
 
 func (b *BlockchainTestInfo) SetFullAccountInfo(name string, info *AccountInfo) {
 	b.Accounts[name] = &AccountInfo{
-		Address:    info.Address,
-		PrivateKey: info.PrivateKey,
+		Address:        info.Address,
+		PrivateKey:     info.PrivateKey,
+		ExternalSigner: info.ExternalSigner,
 	}
 	b.Accounts[name].Nonce.Store(info.Nonce.Load())
 }
 
+// UseExternalSigner has the external signer at url sign for the account rather than its local key,
+// moving the account's genesis balance to the signer's address.
+func (b *BlockchainTestInfo) UseExternalSigner(ctx context.Context, name string, url string) {
+	b.T.Helper()
+	oldAddress := b.GetAddress(name)
+	// The test signer server signs with its own account whatever the sender requested,
+	// so its address is learnt by recovering the sender of a probe transaction.
+	signerCfg, err := dataposter.ExternalSignerTestCfg(common.Address{}, url)
+	if err != nil {
+		b.T.Fatal(err)
+	}
+	signer, _, err := dataposter.ExternalSigner(ctx, signerCfg)
+	if err != nil {
+		b.T.Fatal(err)
+	}
+	probe, err := signer(ctx, common.Address{}, types.NewTx(&types.DynamicFeeTx{ChainID: b.Signer.ChainID()}))
+	if err != nil {
+		b.T.Fatal("failed to sign with external signer: ", err)
+	}
+	address, err := types.Sender(b.Signer, probe)
+	if err != nil {
+		b.T.Fatal(err)
+	}
+	for i := range b.ArbInitData.Accounts {
+		if b.ArbInitData.Accounts[i].Addr == oldAddress {
+			b.ArbInitData.Accounts[i].Addr = address
+		}
+	}
+	b.Accounts[name] = &AccountInfo{
+		Address:        address,
+		ExternalSigner: signer,
+	}
+	log.Info("Using external signer", "name", name, "Address", address)
+}
+
 func (b *BlockchainTestInfo) GetAddress(name string) common.Address {
 	b.T.Helper()
 	info, ok := b.Accounts[name]
@@ -167,6 +206,24 @@ func (b *BlockchainTestInfo) GetInfoWithPrivKey(name string) *AccountInfo {
 
 func (b *BlockchainTestInfo) GetDefaultTransactOpts(name string, ctx context.Context) bind.TransactOpts {
 	b.T.Helper()
+	if info := b.Accounts[name]; info != nil && info.ExternalSigner != nil {
+		return bind.TransactOpts{
+			From: info.Address,
+			Signer: func(address common.Address, tx *types.Transaction) (*types.Transaction, error) {
+				if address != info.Address {
+					return nil, errors.New("bad address")
+				}
+				signedTx, err := info.ExternalSigner(ctx, address, tx)
+				if err != nil {
+					return nil, err
+				}
+				info.Nonce.Add(1) // we don't set Nonce, but try to keep track..
+				return signedTx, nil
+			},
+			GasMargin: 2000, // adjust by 20%
+			Context:   ctx,
+		}
+	}
 	info := b.GetInfoWithPrivKey(name)
 	return bind.TransactOpts{
 		From: info.Address,
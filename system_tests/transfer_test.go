@@ -26,16 +26,16 @@ func TestTransfer(t *testing.T) {
 
 	tx := builder.L2Info.PrepareTx("Owner", "User2", builder.L2Info.TransferGas, big.NewInt(1e12), nil)
 
-	err := builder.L2.Client.SendTransaction(ctx, tx)
+	err := builder.L2Client().SendTransaction(ctx, tx)
 	Require(t, err)
 
 	_, err = builder.L2.EnsureTxSucceeded(tx)
 	Require(t, err)
 
-	bal, err := builder.L2.Client.BalanceAt(ctx, builder.L2Info.GetAddress("Owner"), nil)
+	bal, err := builder.L2Client().BalanceAt(ctx, builder.L2Info.GetAddress("Owner"), nil)
 	Require(t, err)
 	fmt.Println("Owner balance is: ", bal)
-	bal2, err := builder.L2.Client.BalanceAt(ctx, builder.L2Info.GetAddress("User2"), nil)
+	bal2, err := builder.L2Client().BalanceAt(ctx, builder.L2Info.GetAddress("User2"), nil)
 	Require(t, err)
 	if bal2.Cmp(big.NewInt(1e12)) != 0 {
 		Fatal(t, "Unexpected recipient balance: ", bal2)
@@ -67,7 +67,7 @@ func TestP256Verify(t *testing.T) {
 			cleanup := builder.Build(t)
 			defer cleanup()
 			addr := common.BytesToAddress([]byte{0x01, 0x00})
-			got, err := builder.L2.Client.CallContract(ctx, ethereum.CallMsg{
+			got, err := builder.L2Client().CallContract(ctx, ethereum.CallMsg{
 				From:  builder.L2Info.GetAddress("Owner"),
 				To:    &addr,
 				Gas:   builder.L2Info.TransferGas,
@@ -27,7 +27,7 @@ func testTwoNodesSimple(t *testing.T, dasModeStr string) {
 	cleanup := builder.Build(t)
 	defer cleanup()
 
-	authorizeDASKeyset(t, ctx, dasSignerKey, builder.L1Info, builder.L1.Client)
+	authorizeDASKeyset(t, ctx, dasSignerKey, builder.L1Info, builder.L1Client())
 	l1NodeConfigBDataAvailability := l1NodeConfigA.DataAvailability
 	l1NodeConfigBDataAvailability.RPCAggregator.Enable = false
 	testClientB, cleanupB := builder.Build2ndNode(t, &SecondNodeParams{dasConfig: &l1NodeConfigBDataAvailability})
@@ -37,7 +37,7 @@ func testTwoNodesSimple(t *testing.T, dasModeStr string) {
 
 	tx := builder.L2Info.PrepareTx("Owner", "User2", builder.L2Info.TransferGas, big.NewInt(1e12), nil)
 
-	err := builder.L2.Client.SendTransaction(ctx, tx)
+	err := builder.L2Client().SendTransaction(ctx, tx)
 	Require(t, err)
 
 	_, err = builder.L2.EnsureTxSucceeded(tx)
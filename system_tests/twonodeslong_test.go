@@ -49,7 +49,7 @@ func testTwoNodesLong(t *testing.T, dasModeStr string) {
 	builder.Build(t)
 	defer requireClose(t, builder.L1.Stack)
 
-	authorizeDASKeyset(t, ctx, dasSignerKey, builder.L1Info, builder.L1.Client)
+	authorizeDASKeyset(t, ctx, dasSignerKey, builder.L1Info, builder.L1Client())
 
 	l1NodeConfigBDataAvailability := l1NodeConfigA.DataAvailability
 	l1NodeConfigBDataAvailability.RPCAggregator.Enable = false
@@ -72,7 +72,7 @@ func testTwoNodesLong(t *testing.T, dasModeStr string) {
 	builder.L2.SendWaitTestTransactions(t, []*types.Transaction{
 		builder.L2Info.PrepareTx("Faucet", "DelayedFaucet", builder.L2Info.TransferGas, delayedFaucetNeeds, nil),
 	})
-	delayedFaucetBalance, err := builder.L2.Client.BalanceAt(ctx, builder.L2Info.GetAddress("DelayedFaucet"), nil)
+	delayedFaucetBalance, err := builder.L2Client().BalanceAt(ctx, builder.L2Info.GetAddress("DelayedFaucet"), nil)
 	Require(t, err)
 
 	if delayedFaucetBalance.Cmp(delayedFaucetNeeds) != 0 {
@@ -144,7 +144,7 @@ func testTwoNodesLong(t *testing.T, dasModeStr string) {
 		var tx *types.Transaction
 		for j := 0; j < 30; j++ {
 			tx = builder.L1Info.PrepareTx("Faucet", "User", 30000, big.NewInt(1e12), nil)
-			err := builder.L1.Client.SendTransaction(ctx, tx)
+			err := builder.L1Client().SendTransaction(ctx, tx)
 			if err != nil {
 				Fatal(t, err)
 			}
@@ -43,14 +43,14 @@ func TestBlobAndInternalTxsReject(t *testing.T) {
 	}
 	blobTx, err := types.SignNewTx(privKey, types.NewCancunSigner(l2ChainID), txDataBlob)
 	Require(t, err)
-	err = builder.L2.Client.SendTransaction(ctx, blobTx)
+	err = builder.L2Client().SendTransaction(ctx, blobTx)
 	if err == nil && !errors.Is(err, types.ErrTxTypeNotSupported) {
 		t.Fatalf("did not receive expected error when submitting blob transaction. Want: %v, Got: %v", types.ErrTxTypeNotSupported, err)
 	}
 
 	txDataInternal := &types.ArbitrumInternalTx{ChainId: l2ChainID}
 	internalTx := types.NewTx(txDataInternal)
-	err = builder.L2.Client.SendTransaction(ctx, internalTx)
+	err = builder.L2Client().SendTransaction(ctx, internalTx)
 	if err == nil && !errors.Is(err, types.ErrTxTypeNotSupported) {
 		t.Fatalf("did not receive expected error when submitting arbitrum internal transaction. Want: %v, Got: %v", types.ErrTxTypeNotSupported, err)
 	}
@@ -105,16 +105,16 @@ func TestBlobAndInternalTxsAsDelayedMsgReject(t *testing.T) {
 		Require(t, err)
 	}
 
-	confirmLatestBlock(ctx, t, builder.L1Info, builder.L1.Client)
+	confirmLatestBlock(ctx, t, builder.L1Info, builder.L1Client())
 	for _, tx := range l1Txs {
 		_, err = builder.L1.EnsureTxSucceeded(tx)
 		Require(t, err)
 	}
 
-	blocknum, err := builder.L2.Client.BlockNumber(ctx)
+	blocknum, err := builder.L2Client().BlockNumber(ctx)
 	Require(t, err)
 	for i := uint64(0); i <= blocknum; i++ {
-		block, err := builder.L2.Client.BlockByNumber(ctx, new(big.Int).SetUint64(i))
+		block, err := builder.L2Client().BlockByNumber(ctx, new(big.Int).SetUint64(i))
 		Require(t, err)
 		for _, tx := range block.Transactions() {
 			if _, ok := txAcceptStatus[tx.Hash()]; ok {
@@ -159,7 +159,7 @@ func (r *mockExecRun) CheckAlive(ctx context.Context) error {
 	return nil
 }
 
-func (r *mockExecRun) Close() {}
+func (r *mockExecRun) Close() error { return nil }
 
 func createMockValidationNode(t *testing.T, ctx context.Context, config *server_arb.ArbitratorSpawnerConfig) (*mockSpawner, *node.Node) {
 	stackConf := node.DefaultConfig
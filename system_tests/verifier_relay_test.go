@@ -0,0 +1,165 @@
+// Copyright 2024, Offchain Labs, Inc.
+// For license information, see https://github.com/nitro/blob/master/LICENSE
+
+package arbtest
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/node"
+
+	"github.com/offchainlabs/nitro/arbnode"
+	"github.com/offchainlabs/nitro/arbos/arbostypes"
+	"github.com/offchainlabs/nitro/arbutil"
+	"github.com/offchainlabs/nitro/broadcaster"
+	"github.com/offchainlabs/nitro/cmd/conf"
+	"github.com/offchainlabs/nitro/util/headerreader"
+	"github.com/offchainlabs/nitro/util/testhelpers"
+	"github.com/offchainlabs/nitro/wsbroadcastserver"
+)
+
+func TestVerifierRelayDetectsTamperedFeed(t *testing.T) {
+	t.Parallel()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// the full node sequences, but only posts batches once the verifier relay has the tampered feed
+	builder := NewNodeBuilder(ctx).DefaultConfig(t, true)
+	builder.nodeConfig.BatchPoster.Enable = false
+	cleanup := builder.Build(t)
+	defer cleanup()
+
+	tamperedFeedConfig := newBroadcasterConfigTest()
+	tamperedFeed := broadcaster.NewBroadcaster(func() *wsbroadcastserver.BroadcasterConfig { return tamperedFeedConfig }, builder.chainConfig.ChainID.Uint64(), make(chan error, 10), nil)
+	Require(t, tamperedFeed.Initialize())
+	Require(t, tamperedFeed.Start(ctx))
+	defer tamperedFeed.StopAndWait()
+
+	relayConfig := arbnode.ConfigDefaultVerifierRelay()
+	relayConfig.ParentChainReader = headerreader.TestConfig
+	relayConfig.InboxReader = arbnode.TestInboxReaderConfig
+	relayConfig.Dangerous = arbnode.TestDangerousConfig
+	relayConfig.SyncMonitor = arbnode.TestSyncMonitorConfig
+	relayConfig.Feed.Input = *newBroadcastClientConfigTest(testhelpers.AddrTCPPort(tamperedFeed.ListenerAddr(), t))
+	relayConfig.Feed.Output = *newBroadcasterConfigTest()
+	Require(t, relayConfig.Validate())
+
+	relayStack, err := node.New(testhelpers.CreateStackConfigForTest(t.TempDir()))
+	Require(t, err)
+	relayDb, err := relayStack.OpenDatabaseWithExtraOptions("arbitrumdata", 0, 0, "arbitrumdata/", false, conf.PersistentConfigDefault.Pebble.ExtraOptions("arbitrumdata"))
+	Require(t, err)
+	feedErrChan := make(chan error, 10)
+	relay, err := arbnode.CreateNode(ctx, relayStack, nil, relayDb, NewFetcherFromConfig(relayConfig), builder.chainConfig, builder.L1Client(), builder.addresses, nil, nil, nil, feedErrChan, big.NewInt(1337), nil)
+	Require(t, err)
+	Require(t, relay.Start(ctx))
+	defer relay.StopAndWait()
+	StartWatchChanErr(t, ctx, feedErrChan, relay)
+
+	builder.L2Info.GenerateAccount("User2")
+	tx := builder.L2Info.PrepareTx("Owner", "User2", builder.L2Info.TransferGas, big.NewInt(1e12), nil)
+	Require(t, builder.L2Client().SendTransaction(ctx, tx))
+	_, err = builder.L2.EnsureTxSucceeded(tx)
+	Require(t, err)
+
+	// feed the relay everything the full node sequenced, tampering with the transfer's message
+	msgCount, err := builder.L2.ConsensusNode.TxStreamer.GetMessageCount()
+	Require(t, err)
+	tamperedPos := msgCount - 1
+	var feedMsgs []arbostypes.MessageWithMetadataAndBlockHash
+	for pos := arbutil.MessageIndex(0); pos < msgCount; pos++ {
+		msg, err := builder.L2.ConsensusNode.TxStreamer.GetMessage(pos)
+		Require(t, err)
+		feedMsgs = append(feedMsgs, arbostypes.MessageWithMetadataAndBlockHash{MessageWithMeta: *msg})
+	}
+	honestMsg := feedMsgs[tamperedPos].MessageWithMeta
+	tamperedMsg := honestMsg
+	tamperedMsg.Message = new(arbostypes.L1IncomingMessage)
+	*tamperedMsg.Message = *honestMsg.Message
+	tamperedMsg.Message.L2msg = slices.Clone(honestMsg.Message.L2msg)
+	tamperedMsg.Message.L2msg[len(tamperedMsg.Message.L2msg)-1] ^= 1
+	feedMsgs[tamperedPos].MessageWithMeta = tamperedMsg
+	Require(t, tamperedFeed.BroadcastMessages(feedMsgs, 0))
+
+	waitForRelay := func(description string, done func() bool) {
+		t.Helper()
+		for i := 0; !done(); i++ {
+			if i == 200 {
+				Fatal(t, "verifier relay didn't", description)
+			}
+			time.Sleep(50 * time.Millisecond)
+		}
+	}
+	waitForRelay("store the tampered feed", func() bool {
+		count, err := relay.TxStreamer.GetMessageCount()
+		Require(t, err)
+		return count == msgCount
+	})
+	relayed, err := relay.TxStreamer.GetMessage(tamperedPos)
+	Require(t, err)
+	if relayed.Message.Equals(honestMsg.Message) {
+		Fatal(t, "verifier relay didn't take the tampered feed message")
+	}
+
+	batchPosterConfig := builder.nodeConfig.BatchPoster
+	batchPosterConfig.Enable = true
+	seqTxOpts := builder.L1Info.GetDefaultTransactOpts("Sequencer", ctx)
+	parentChainID, err := builder.L1Client().ChainID(ctx)
+	Require(t, err)
+	batchPoster, err := arbnode.NewBatchPoster(ctx,
+		&arbnode.BatchPosterOpts{
+			DataPosterDB:  nil,
+			L1Reader:      builder.L2.ConsensusNode.L1Reader,
+			Inbox:         builder.L2.ConsensusNode.InboxTracker,
+			Streamer:      builder.L2.ConsensusNode.TxStreamer,
+			VersionGetter: builder.L2.ExecNode,
+			SyncMonitor:   builder.L2.ConsensusNode.SyncMonitor,
+			Config:        func() *arbnode.BatchPosterConfig { return &batchPosterConfig },
+			DeployInfo:    builder.L2.ConsensusNode.DeployInfo,
+			TransactOpts:  &seqTxOpts,
+			DAPWriter:     nil,
+			ParentChainID: parentChainID,
+		},
+	)
+	Require(t, err)
+	batchPoster.Start(ctx)
+	defer batchPoster.StopAndWait()
+
+	waitForRelay("verify the feed against the batch", func() bool {
+		return relay.FeedVerifier.VerifiedCount() >= msgCount
+	})
+	mismatches := relay.FeedVerifier.Mismatches()
+	if len(mismatches) != 1 || mismatches[0].Position != tamperedPos {
+		Fatal(t, "expected only the tampered message at", tamperedPos, "to mismatch, got", mismatches)
+	}
+	if !mismatches[0].Batch.Message.Equals(honestMsg.Message) || mismatches[0].Feed.Message.Equals(honestMsg.Message) {
+		Fatal(t, "mismatch didn't record the tampered feed message and the batch's")
+	}
+	relayed, err = relay.TxStreamer.GetMessage(tamperedPos)
+	Require(t, err)
+	if !relayed.Message.Equals(honestMsg.Message) {
+		Fatal(t, "verifier relay kept the tampered feed message over the batch's")
+	}
+
+	var health arbnode.VerifierRelayHealth
+	Require(t, relayStack.Attach().CallContext(ctx, &health, "arb_nodeHealth"))
+	if health.Status != "degraded" || len(health.Mismatches) != 1 {
+		Fatal(t, "expected health to report the mismatch, got", health)
+	}
+	if slices.Contains(health.Components, "execution") || !slices.Contains(health.Components, "feed-verifier") {
+		Fatal(t, "unexpected components", health.Components)
+	}
+
+	if relay.Execution != nil {
+		Fatal(t, "verifier relay has an execution client")
+	}
+	if _, err := os.Stat(filepath.Join(relayStack.InstanceDir(), "l2chaindata")); !errors.Is(err, os.ErrNotExist) {
+		Fatal(t, "verifier relay has an execution database", err)
+	}
+}
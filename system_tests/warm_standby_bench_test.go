@@ -0,0 +1,66 @@
+// Copyright 2024, Offchain Labs, Inc.
+// For license information, see https://github.com/nitro/blob/master/LICENSE
+
+//go:build benchmarks
+// +build benchmarks
+
+package arbtest
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// TestWarmStandbyFailoverLatency compares how long a standby sequencer takes to sequence the queue it
+// inherits in a failover, with and without having speculatively executed that queue beforehand.
+func TestWarmStandbyFailoverLatency(t *testing.T) {
+	const queued = 100
+	for _, warm := range []bool{false, true} {
+		latency, first := measureFailoverLatency(t, warm, queued)
+		t.Logf("warm=%v: first transaction sequenced after %v, all %v after %v", warm, first, queued, latency)
+	}
+}
+
+func measureFailoverLatency(t *testing.T, warm bool, queued int) (time.Duration, time.Duration) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	pair, cleanup := setupWarmStandbyPair(t, ctx, warm, 10*time.Second)
+	defer cleanup()
+	builder := pair.builder
+
+	// fresh recipients, so the queue touches state neither sequencer has cached
+	var txes types.Transactions
+	for i := 0; i < queued; i++ {
+		name := fmt.Sprintf("FailoverUser%d", i)
+		builder.L2Info.GenerateAccount(name)
+		txes = append(txes, builder.L2Info.PrepareTx("Owner", name, builder.L2Info.TransferGas, big.NewInt(1), nil))
+	}
+	Require(t, pair.chosen.Client.SendTransaction(ctx, txes[0]))
+	for _, tx := range txes[1:] {
+		tx := tx
+		go func() { _ = pair.chosen.Client.SendTransaction(ctx, tx) }()
+	}
+	if warm {
+		pair.waitForStandbyState(t, func(pending, executed int) bool { return executed >= queued-1 })
+	} else {
+		time.Sleep(time.Second)
+	}
+
+	start := time.Now()
+	pair.failover()
+	for _, tx := range txes[1:] {
+		_ = pair.standby.Client.SendTransaction(ctx, tx)
+	}
+	_, err := EnsureTxSucceeded(ctx, pair.standby.Client, txes[1])
+	Require(t, err)
+	first := time.Since(start)
+	_, err = EnsureTxSucceeded(ctx, pair.standby.Client, txes[queued-1])
+	Require(t, err)
+	return time.Since(start), first
+}
@@ -0,0 +1,149 @@
+// Copyright 2024, Offchain Labs, Inc.
+// For license information, see https://github.com/nitro/blob/master/LICENSE
+
+package arbtest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/core/types"
+
+	"github.com/offchainlabs/nitro/arbnode"
+)
+
+type warmStandbyPair struct {
+	builder     *NodeBuilder
+	standby     *TestClient
+	chosen      *TestClient
+	chosenPath  string
+	standbyPath string
+}
+
+// setupWarmStandbyPair creates two sequencers coordinated through redis. The builder's node is the standby,
+// which hands the lockout over to the second node as soon as it's created because of its higher priority.
+func setupWarmStandbyPair(t *testing.T, ctx context.Context, warm bool, chosenBlockSpeed time.Duration) (*warmStandbyPair, func()) {
+	t.Helper()
+	nodePaths := testNodes(t, 2)
+	chosenPath, standbyPath := nodePaths[0], nodePaths[1]
+	redisServer, redisUrl := initRedis(ctx, t, nodePaths)
+
+	builder := NewNodeBuilder(ctx).DefaultConfig(t, true)
+	builder.l2StackConfig.IPCPath = standbyPath
+	builder.nodeConfig.SeqCoordinator.Enable = true
+	builder.nodeConfig.SeqCoordinator.RedisUrl = redisUrl
+	builder.nodeConfig.SeqCoordinator.MyUrl = standbyPath
+	builder.execConfig.Sequencer.WarmStandby.Enable = warm
+	cleanup := builder.Build(t)
+
+	builder.l2StackConfig.IPCPath = chosenPath
+	nodeConfig := arbnode.ConfigDefaultL1Test()
+	nodeConfig.BatchPoster.Enable = false
+	nodeConfig.SeqCoordinator.Enable = true
+	nodeConfig.SeqCoordinator.RedisUrl = redisUrl
+	nodeConfig.SeqCoordinator.MyUrl = chosenPath
+	execConfig := ExecConfigDefaultTest(t)
+	execConfig.Sequencer.MaxBlockSpeed = chosenBlockSpeed
+	execConfig.Sequencer.WarmStandby.Enable = warm
+	chosen, _ := builder.Build2ndNode(t, &SecondNodeParams{nodeConfig: nodeConfig, execConfig: execConfig})
+
+	err := tryWithTimeout(func() error {
+		current, err := builder.L2.ConsensusNode.SeqCoordinator.RedisCoordinator().CurrentChosenSequencer(ctx)
+		if err == nil && current != chosenPath {
+			return fmt.Errorf("chosen sequencer is %v", current)
+		}
+		return err
+	}, 10*time.Second)
+	Require(t, err, "second sequencer never acquired the lockout")
+
+	pair := &warmStandbyPair{
+		builder:     builder,
+		standby:     builder.L2,
+		chosen:      chosen,
+		chosenPath:  chosenPath,
+		standbyPath: standbyPath,
+	}
+	return pair, func() {
+		if pair.chosen != nil {
+			pair.chosen.ConsensusNode.StopAndWait()
+		}
+		cleanup()
+		redisServer.Close()
+	}
+}
+
+// failover stops the chosen sequencer, which hands the lockout and its queue to the standby
+func (p *warmStandbyPair) failover() {
+	p.chosen.ConsensusNode.StopAndWait()
+	p.chosen = nil
+}
+
+func (p *warmStandbyPair) waitForStandbyState(t *testing.T, check func(pending, executed int) bool) {
+	t.Helper()
+	err := tryWithTimeout(func() error {
+		if check(p.standby.ExecNode.Sequencer.WarmStandbyState()) {
+			return nil
+		}
+		time.Sleep(10 * time.Millisecond)
+		return errors.New("standby not ready")
+	}, 10*time.Second)
+	Require(t, err, "standby never reached the expected state")
+}
+
+func TestWarmStandbySequencerFailover(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	pair, cleanup := setupWarmStandbyPair(t, ctx, true, 2*time.Second)
+	defer cleanup()
+	builder := pair.builder
+
+	builder.L2Info.GenerateAccount("User")
+	tx := builder.L2Info.PrepareTx("Owner", "User", builder.L2Info.TransferGas, transferAmount, nil)
+	Require(t, pair.chosen.Client.SendTransaction(ctx, tx))
+
+	// the chosen sequencer just made a block, so the next transaction waits in its queue
+	queued := builder.L2Info.PrepareTx("Owner", "User", builder.L2Info.TransferGas, transferAmount, nil)
+	sendErr := make(chan error, 1)
+	go func() { sendErr <- pair.chosen.Client.SendTransaction(ctx, queued) }()
+	pair.waitForStandbyState(t, func(pending, executed int) bool { return executed > 0 })
+
+	Require(t, <-sendErr)
+	_, err := EnsureTxSucceeded(ctx, pair.standby.Client, queued)
+	Require(t, err)
+	pair.waitForStandbyState(t, func(pending, executed int) bool { return pending == 0 && executed == 0 })
+
+	// queue up more transactions at the chosen sequencer, then take it down
+	var txes types.Transactions
+	for i := 0; i < 5; i++ {
+		txes = append(txes, builder.L2Info.PrepareTx("Owner", "User", builder.L2Info.TransferGas, transferAmount, nil))
+	}
+	Require(t, pair.chosen.Client.SendTransaction(ctx, txes[0]))
+	for _, tx := range txes[1:] {
+		tx := tx
+		go func() { _ = pair.chosen.Client.SendTransaction(ctx, tx) }()
+	}
+	pair.waitForStandbyState(t, func(pending, executed int) bool { return pending == len(txes)-1 })
+
+	start := time.Now()
+	pair.failover()
+	for _, tx := range txes[1:] {
+		// the chosen sequencer forwards its queue on shutdown, so these are usually known already
+		_ = pair.standby.Client.SendTransaction(ctx, tx)
+	}
+	for _, tx := range txes {
+		_, err := EnsureTxSucceeded(ctx, pair.standby.Client, tx)
+		Require(t, err)
+	}
+	t.Log("sequenced the failed over queue after", time.Since(start))
+
+	// the standby is now the chosen sequencer, so it keeps no speculative state
+	pair.waitForStandbyState(t, func(pending, executed int) bool { return pending == 0 && executed == 0 })
+	tx = builder.L2Info.PrepareTx("Owner", "User", builder.L2Info.TransferGas, transferAmount, nil)
+	Require(t, pair.standby.Client.SendTransaction(ctx, tx))
+	_, err = EnsureTxSucceeded(ctx, pair.standby.Client, tx)
+	Require(t, err)
+}
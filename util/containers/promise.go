@@ -3,7 +3,9 @@ package containers
 import (
 	"context"
 	"errors"
+	"fmt"
 	"sync/atomic"
+	"time"
 )
 
 type PromiseInterface[R any] interface {
@@ -16,6 +18,9 @@ type PromiseInterface[R any] interface {
 
 var ErrNotReady error = errors.New("not ready")
 
+// ErrPromiseTimeout is returned by AwaitWithTimeout when nothing was produced before the deadline
+var ErrPromiseTimeout error = errors.New("promise timed out")
+
 type Promise[R any] struct {
 	chanReady chan struct{}
 	result    R
@@ -48,6 +53,29 @@ func (p *Promise[R]) Await(ctx context.Context) (R, error) {
 	}
 }
 
+// AwaitWithTimeout is like Await, but gives up after d, returning an error wrapping ErrPromiseTimeout.
+// Both a timeout and ctx being done cancel the producer, as in Await.
+func (p *Promise[R]) AwaitWithTimeout(ctx context.Context, d time.Duration) (R, error) {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-p.chanReady:
+		return p.result, p.err
+	case <-ctx.Done():
+		var empty R
+		p.Cancel()
+		return empty, ctx.Err()
+	case <-timer.C:
+		// a value produced as the deadline passed still wins
+		if p.Ready() {
+			return p.result, p.err
+		}
+		var empty R
+		p.Cancel()
+		return empty, fmt.Errorf("%w after %v", ErrPromiseTimeout, d)
+	}
+}
+
 func (p *Promise[R]) Current() (R, error) {
 	if !p.Ready() {
 		var empty R
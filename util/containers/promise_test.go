@@ -92,3 +92,67 @@ func TestPromise(t *testing.T) {
 		t.Fatal("cancel not called by promise.Cancel")
 	}
 }
+
+func TestPromiseAwaitWithTimeout(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var cancelCalled atomic.Int64
+	cancelFunc := func() { cancelCalled.Add(1) }
+
+	tempPromise := NewPromise[int](cancelFunc)
+	if tempPromise.Ready() {
+		t.Fatal("promise ready before produce")
+	}
+	res, err := tempPromise.AwaitWithTimeout(ctx, time.Millisecond*10)
+	if res != 0 || !errors.Is(err, ErrPromiseTimeout) {
+		t.Fatal("unexpected Promise.AwaitWithTimeout on timeout", res, err)
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		t.Fatal("timeout shouldn't look like the context's deadline")
+	}
+	if cancelCalled.Load() != 1 {
+		t.Fatal("cancel not called by AwaitWithTimeout on timeout")
+	}
+
+	errErrorProduced := errors.New("err produced")
+	tempPromise.ProduceError(errErrorProduced)
+	if !tempPromise.Ready() {
+		t.Fatal("promise not ready after produce")
+	}
+	res, err = tempPromise.AwaitWithTimeout(ctx, time.Hour)
+	if res != 0 || !errors.Is(err, errErrorProduced) || errors.Is(err, ErrPromiseTimeout) {
+		t.Fatal("produced error not told apart from a timeout", err)
+	}
+
+	tempPromise = NewPromise[int](cancelFunc)
+	canceledCtx, cancelCtx := context.WithCancel(ctx)
+	cancelCtx()
+	_, err = tempPromise.AwaitWithTimeout(canceledCtx, time.Hour)
+	if !errors.Is(err, context.Canceled) || errors.Is(err, ErrPromiseTimeout) {
+		t.Fatal("unexpected Promise.AwaitWithTimeout with canceled context", err)
+	}
+	if cancelCalled.Load() != 2 {
+		t.Fatal("cancel not called by AwaitWithTimeout on canceled context")
+	}
+
+	// race produce against the deadline: either the value or a timeout, nothing else
+	for i := 0; i < 100; i++ {
+		tempPromise := NewPromise[int](nil)
+		var wg sync.WaitGroup
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			tempPromise.Produce(i)
+		}()
+		res, err := tempPromise.AwaitWithTimeout(ctx, time.Microsecond)
+		wg.Wait()
+		if err == nil {
+			if res != i {
+				t.Fatal("unexpected value", res, "expected", i)
+			}
+		} else if !errors.Is(err, ErrPromiseTimeout) || res != 0 {
+			t.Fatal("unexpected Promise.AwaitWithTimeout racing produce", res, err)
+		}
+	}
+}
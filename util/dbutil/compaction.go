@@ -0,0 +1,107 @@
+// Copyright 2024, Offchain Labs, Inc.
+// For license information, see https://github.com/nitro/blob/master/LICENSE
+
+package dbutil
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/ethereum/go-ethereum/metrics"
+)
+
+// CompactionStats are cumulative counters of a database's compaction activity,
+// as reported by the meters geth's leveldb and pebble wrappers register.
+type CompactionStats struct {
+	// bytes written to disk, including the WAL, memtable flushes and compactions
+	DiskWriteBytes       int64
+	CompactionWriteBytes int64
+	CompactionTime       time.Duration
+	// time writes were delayed waiting for compactions to catch up
+	WriteStallTime time.Duration
+	WriteStalls    int64
+}
+
+// Sub returns the compaction activity between an earlier snapshot and s.
+func (s CompactionStats) Sub(earlier CompactionStats) CompactionStats {
+	return CompactionStats{
+		DiskWriteBytes:       s.DiskWriteBytes - earlier.DiskWriteBytes,
+		CompactionWriteBytes: s.CompactionWriteBytes - earlier.CompactionWriteBytes,
+		CompactionTime:       s.CompactionTime - earlier.CompactionTime,
+		WriteStallTime:       s.WriteStallTime - earlier.WriteStallTime,
+		WriteStalls:          s.WriteStalls - earlier.WriteStalls,
+	}
+}
+
+// WriteAmplification estimates how many bytes hit the disk for every byte written
+// by the database's users, or returns 0 if nothing was written.
+func (s CompactionStats) WriteAmplification() float64 {
+	userBytes := s.DiskWriteBytes - s.CompactionWriteBytes
+	if userBytes <= 0 {
+		return 0
+	}
+	return float64(s.DiskWriteBytes) / float64(userBytes)
+}
+
+// CompactionController exposes the runtime compaction knobs of a database, identified
+// by the name it was opened with. Databases opened before or after the controller was
+// created share it, as pebble asks for its compaction concurrency on every compaction.
+type CompactionController struct {
+	name string
+	// maximum number of concurrent background compactions, or 0 for no limit beyond the configured one
+	concurrencyLimit atomic.Int64
+}
+
+var compactionControllers sync.Map // name -> *CompactionController
+
+// CompactionControllerFor returns the compaction controller of the database with the given name.
+func CompactionControllerFor(name string) *CompactionController {
+	controller, _ := compactionControllers.LoadOrStore(name, &CompactionController{name: name})
+	return controller.(*CompactionController)
+}
+
+func (c *CompactionController) Name() string {
+	return c.name
+}
+
+// MaxConcurrentCompactions returns the number of concurrent background compactions
+// the database may run, given the configured maximum.
+func (c *CompactionController) MaxConcurrentCompactions(configured int) int {
+	limit := int(c.concurrencyLimit.Load())
+	if limit > 0 && limit < configured {
+		return limit
+	}
+	return configured
+}
+
+// SetConcurrencyLimit caps the database's background compaction concurrency, or
+// removes the cap if limit is 0. Only pebble databases support this.
+func (c *CompactionController) SetConcurrencyLimit(limit int) {
+	c.concurrencyLimit.Store(int64(limit))
+}
+
+func (c *CompactionController) ConcurrencyLimit() int {
+	return int(c.concurrencyLimit.Load())
+}
+
+// Stats reads the database's compaction counters from its metrics, which are only
+// collected if metrics are enabled.
+func (c *CompactionController) Stats() CompactionStats {
+	prefix := c.name + "/"
+	return CompactionStats{
+		DiskWriteBytes:       meterCount(prefix + "disk/write"),
+		CompactionWriteBytes: meterCount(prefix + "compact/output"),
+		CompactionTime:       time.Duration(meterCount(prefix + "compact/time")),
+		WriteStallTime:       time.Duration(meterCount(prefix + "compact/writedelay/duration")),
+		WriteStalls:          meterCount(prefix + "compact/writedelay/counter"),
+	}
+}
+
+func meterCount(name string) int64 {
+	meter, ok := metrics.DefaultRegistry.Get(name).(metrics.Meter)
+	if !ok {
+		return 0
+	}
+	return meter.Snapshot().Count()
+}
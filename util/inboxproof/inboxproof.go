@@ -0,0 +1,124 @@
+// Copyright 2024, Offchain Labs, Inc.
+// For license information, see https://github.com/nitro/blob/master/LICENSE
+
+// Package inboxproof proves that an L2 transaction was part of a batch posted
+// to the SequencerInbox, using only data available on L1.
+package inboxproof
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+
+	"github.com/offchainlabs/nitro/arbcompress"
+	"github.com/offchainlabs/nitro/arbos/arbostypes"
+	"github.com/offchainlabs/nitro/arbstate"
+)
+
+var (
+	ErrBatchDataHashMismatch = errors.New("batch data hash doesn't match batch data")
+	ErrInboxAccMismatch      = errors.New("sequencer inbox accumulator mismatch")
+	ErrDelayedAccMismatch    = errors.New("delayed inbox accumulator mismatch")
+	ErrSegmentMismatch       = errors.New("batch segment doesn't match proof")
+	ErrTxNotInSegment        = errors.New("transaction not found in batch segment")
+)
+
+// DelayedMessageProof links a delayed message to the delayed inbox accumulator a batch committed to.
+type DelayedMessageProof struct {
+	Index     uint64      `json:"index"`
+	BeforeAcc common.Hash `json:"beforeAcc"`
+	// Hashes of the delayed message and every later delayed message read by the batch, in order.
+	MessageHashes []common.Hash `json:"messageHashes"`
+}
+
+// InclusionProof ties a message to a posted batch and the batch to the SequencerInbox accumulator.
+type InclusionProof struct {
+	BatchNumber         uint64 `json:"batchNumber"`
+	MessageIndex        uint64 `json:"messageIndex"`
+	MessageIndexInBatch uint64 `json:"messageIndexInBatch"`
+	// Byte offset of the message's segment within the decompressed batch payload.
+	// Nil for delayed messages read after the batch's last segment.
+	SegmentOffset *uint64 `json:"segmentOffset,omitempty"`
+
+	BatchData       hexutil.Bytes `json:"batchData"` // the 40 byte batch header followed by the posted data
+	BatchDataHash   common.Hash   `json:"batchDataHash"`
+	BeforeInboxAcc  common.Hash   `json:"beforeInboxAcc"`
+	DelayedInboxAcc common.Hash   `json:"delayedInboxAcc"`
+	AfterInboxAcc   common.Hash   `json:"afterInboxAcc"`
+
+	// Set only if the message was delivered via the delayed inbox.
+	DelayedMessage *DelayedMessageProof `json:"delayedMessage,omitempty"`
+}
+
+// Verify checks the proof against inboxAcc, the SequencerInbox accumulator for the proof's batch.
+// For messages posted by the sequencer, txBytes must be the binary encoding of the transaction,
+// which is checked to be part of the message's segment. Delayed messages are instead checked
+// against the delayed inbox accumulator the batch committed to, and txBytes is ignored.
+// Only batches posted as calldata without a compression dictionary can be verified,
+// as other data availability modes and dictionaries need data that isn't part of the proof.
+func Verify(ctx context.Context, proof *InclusionProof, txBytes []byte, inboxAcc common.Hash) error {
+	if crypto.Keccak256Hash(proof.BatchData) != proof.BatchDataHash {
+		return ErrBatchDataHashMismatch
+	}
+	acc := crypto.Keccak256Hash(proof.BeforeInboxAcc[:], proof.BatchDataHash[:], proof.DelayedInboxAcc[:])
+	if acc != proof.AfterInboxAcc || acc != inboxAcc {
+		return fmt.Errorf("%w: computed %v expected %v", ErrInboxAccMismatch, acc, inboxAcc)
+	}
+
+	segments, afterDelayedMessages, err := arbstate.ParseMessageSegments(ctx, proof.BatchNumber, common.Hash{}, proof.BatchData, nil, nil)
+	if err != nil {
+		return err
+	}
+	var segment *arbstate.BatchSegment
+	if proof.MessageIndexInBatch < uint64(len(segments)) {
+		segment = &segments[proof.MessageIndexInBatch]
+		if proof.SegmentOffset == nil || *proof.SegmentOffset != segment.Offset {
+			return fmt.Errorf("%w: message %v is at offset %v", ErrSegmentMismatch, proof.MessageIndexInBatch, segment.Offset)
+		}
+	} else if proof.SegmentOffset != nil {
+		return fmt.Errorf("%w: message %v is past the last segment", ErrSegmentMismatch, proof.MessageIndexInBatch)
+	}
+
+	if proof.DelayedMessage != nil {
+		if segment != nil && segment.Data[0] != arbstate.BatchSegmentKindDelayedMessages {
+			return fmt.Errorf("%w: segment kind %v isn't a delayed message", ErrSegmentMismatch, segment.Data[0])
+		}
+		delayed := proof.DelayedMessage
+		if delayed.Index+uint64(len(delayed.MessageHashes)) != afterDelayedMessages {
+			return fmt.Errorf("%w: batch reads %v delayed messages", ErrDelayedAccMismatch, afterDelayedMessages)
+		}
+		delayedAcc := delayed.BeforeAcc
+		for _, hash := range delayed.MessageHashes {
+			delayedAcc = crypto.Keccak256Hash(delayedAcc[:], hash[:])
+		}
+		if delayedAcc != proof.DelayedInboxAcc {
+			return fmt.Errorf("%w: computed %v expected %v", ErrDelayedAccMismatch, delayedAcc, proof.DelayedInboxAcc)
+		}
+		return nil
+	}
+
+	if segment == nil {
+		return fmt.Errorf("%w: message %v has no segment", ErrSegmentMismatch, proof.MessageIndexInBatch)
+	}
+	kind := segment.Data[0]
+	payload := segment.Data[1:]
+	switch kind {
+	case arbstate.BatchSegmentKindL2Message:
+	case arbstate.BatchSegmentKindL2MessageBrotli:
+		payload, err = arbcompress.Decompress(payload, arbostypes.MaxL2MessageSize)
+		if err != nil {
+			return fmt.Errorf("%w: failed to decompress segment: %w", ErrSegmentMismatch, err)
+		}
+	default:
+		return fmt.Errorf("%w: segment kind %v isn't an L2 message", ErrSegmentMismatch, kind)
+	}
+	if len(txBytes) == 0 || !bytes.Contains(payload, txBytes) {
+		return ErrTxNotInSegment
+	}
+	return nil
+}
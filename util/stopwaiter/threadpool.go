@@ -0,0 +1,179 @@
+// Copyright 2024, Offchain Labs, Inc.
+// For license information, see https://github.com/nitro/blob/master/LICENSE
+
+package stopwaiter
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"github.com/offchainlabs/nitro/util/containers"
+)
+
+// ThreadPool bounds how many of the threads launched through it run at once.
+// Threads beyond the bound wait their turn in the order they were launched,
+// and a thread whose context is done before its turn comes never runs.
+// It launches threads on the wrapped ThreadLauncher, so they're stopped and waited for with it.
+type ThreadPool struct {
+	launcher ThreadLauncher
+	workers  int
+
+	mutex   sync.Mutex
+	running int
+	waiting []chan struct{}
+}
+
+// NewThreadPool creates a pool running at most workers threads at once, or any number if workers isn't positive.
+func NewThreadPool(launcher ThreadLauncher, workers int) *ThreadPool {
+	return &ThreadPool{
+		launcher: launcher,
+		workers:  workers,
+	}
+}
+
+func (p *ThreadPool) GetContextSafe() (context.Context, error) {
+	return p.launcher.GetContextSafe()
+}
+
+func (p *ThreadPool) Stopped() bool {
+	return p.launcher.Stopped()
+}
+
+// LaunchUntrackedThread isn't bounded by the pool, as it's meant for threads that must run regardless.
+func (p *ThreadPool) LaunchUntrackedThread(foo func()) {
+	p.launcher.LaunchUntrackedThread(foo)
+}
+
+// LaunchThreadSafe queues foo to run once the pool has room for it.
+// If stop was already called, thread might silently not be launched
+func (p *ThreadPool) LaunchThreadSafe(foo func(context.Context)) error {
+	return p.launch(nil, foo, nil)
+}
+
+// launch queues foo right away, so threads get their turns in the order they're launched.
+// If ctx isn't nil foo runs with it, unless it's done before foo's turn comes, in which case skipped is called instead.
+func (p *ThreadPool) launch(ctx context.Context, foo func(context.Context), skipped func()) error {
+	if p.Stopped() {
+		return nil
+	}
+	turn := p.enqueue()
+	err := p.launcher.LaunchThreadSafe(func(launcherCtx context.Context) {
+		runCtx := ctx
+		if runCtx == nil {
+			runCtx = launcherCtx
+		}
+		if !p.await(runCtx, turn) {
+			if skipped != nil {
+				skipped()
+			}
+			return
+		}
+		defer p.release()
+		foo(runCtx)
+	})
+	if err != nil {
+		p.abandon(turn)
+	}
+	return err
+}
+
+// enqueue takes a turn to run, returning nil if it's already come or a channel closed once it does.
+func (p *ThreadPool) enqueue() chan struct{} {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	if p.workers <= 0 || (p.running < p.workers && len(p.waiting) == 0) {
+		p.running++
+		return nil
+	}
+	turn := make(chan struct{})
+	p.waiting = append(p.waiting, turn)
+	return turn
+}
+
+// await waits for a turn to come, returning false if ctx is done first.
+func (p *ThreadPool) await(ctx context.Context, turn chan struct{}) bool {
+	if turn == nil {
+		return true
+	}
+	select {
+	case <-turn:
+		return true
+	case <-ctx.Done():
+		p.abandon(turn)
+		return false
+	}
+}
+
+// abandon gives up a turn, whether it's come or not.
+func (p *ThreadPool) abandon(turn chan struct{}) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	for i, other := range p.waiting {
+		if other == turn {
+			p.waiting = append(p.waiting[:i], p.waiting[i+1:]...)
+			return
+		}
+	}
+	p.releaseLocked()
+}
+
+func (p *ThreadPool) release() {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	p.releaseLocked()
+}
+
+// releaseLocked hands a finished thread's turn to the longest waiting one, if any.
+func (p *ThreadPool) releaseLocked() {
+	if len(p.waiting) > 0 {
+		close(p.waiting[0])
+		p.waiting = p.waiting[1:]
+		return
+	}
+	p.running--
+}
+
+// LaunchPooledPromiseThread is LaunchPromiseThread for threads bounded by a pool.
+// Cancelling the promise while foo is still waiting for its turn means it never runs,
+// and if the launcher's context is already done no thread is launched at all.
+func LaunchPooledPromiseThread[T any](
+	p *ThreadPool,
+	foo func(context.Context) (T, error),
+) containers.PromiseInterface[T] {
+	ctx, err := p.GetContextSafe()
+	if err != nil {
+		promise := containers.NewPromise[T](nil)
+		promise.ProduceError(err)
+		return &promise
+	}
+	if p.Stopped() {
+		promise := containers.NewPromise[T](nil)
+		promise.ProduceError(errors.New("stopped"))
+		return &promise
+	}
+	if ctx.Err() != nil {
+		// don't bother launching a thread that won't get to run
+		promise := containers.NewPromise[T](nil)
+		promise.ProduceError(ctx.Err())
+		return &promise
+	}
+	innerCtx, cancel := context.WithCancel(ctx)
+	promise := containers.NewPromise[T](cancel)
+	err = p.launch(innerCtx, func(ctx context.Context) {
+		val, err := foo(ctx)
+		if err != nil {
+			promise.ProduceError(err)
+		} else {
+			promise.Produce(val)
+		}
+		cancel()
+	}, func() {
+		promise.ProduceError(innerCtx.Err())
+		cancel()
+	})
+	if err != nil {
+		promise.ProduceError(err)
+	}
+	return &promise
+}
@@ -0,0 +1,134 @@
+// Copyright 2024, Offchain Labs, Inc.
+// For license information, see https://github.com/nitro/blob/master/LICENSE
+
+package stopwaiter
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/offchainlabs/nitro/util/containers"
+	"github.com/offchainlabs/nitro/util/testhelpers"
+)
+
+func TestThreadPoolBoundsConcurrency(t *testing.T) {
+	sw := StopWaiter{}
+	sw.Start(context.Background(), &TestStruct{})
+	defer sw.StopAndWait()
+	const workers = 3
+	pool := NewThreadPool(&sw, workers)
+
+	var running, maxRunning atomic.Int32
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		err := pool.LaunchThreadSafe(func(ctx context.Context) {
+			defer wg.Done()
+			now := running.Add(1)
+			for {
+				highest := maxRunning.Load()
+				if now <= highest || maxRunning.CompareAndSwap(highest, now) {
+					break
+				}
+			}
+			time.Sleep(5 * time.Millisecond)
+			running.Add(-1)
+		})
+		testhelpers.RequireImpl(t, err)
+	}
+	wg.Wait()
+	if highest := maxRunning.Load(); highest != workers {
+		testhelpers.FailImpl(t, "expected", workers, "threads to run at once, got", highest)
+	}
+}
+
+func TestThreadPoolRunsInOrder(t *testing.T) {
+	sw := StopWaiter{}
+	sw.Start(context.Background(), &TestStruct{})
+	defer sw.StopAndWait()
+	pool := NewThreadPool(&sw, 1)
+
+	unblock := make(chan struct{})
+	err := pool.LaunchThreadSafe(func(ctx context.Context) {
+		<-unblock
+	})
+	testhelpers.RequireImpl(t, err)
+	var mutex sync.Mutex
+	var order []int
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		err := pool.LaunchThreadSafe(func(ctx context.Context) {
+			defer wg.Done()
+			mutex.Lock()
+			defer mutex.Unlock()
+			order = append(order, i)
+		})
+		testhelpers.RequireImpl(t, err)
+	}
+	close(unblock)
+	wg.Wait()
+	for i, ran := range order {
+		if ran != i {
+			testhelpers.FailImpl(t, "threads ran out of order", order)
+		}
+	}
+}
+
+func TestThreadPoolCancelledPromisesDontRun(t *testing.T) {
+	sw := StopWaiter{}
+	sw.Start(context.Background(), &TestStruct{})
+	defer sw.StopAndWait()
+	pool := NewThreadPool(&sw, 2)
+
+	unblock := make(chan struct{})
+	var blockers []containers.PromiseInterface[int]
+	for i := 0; i < 2; i++ {
+		blockers = append(blockers, LaunchPooledPromiseThread[int](pool, func(ctx context.Context) (int, error) {
+			<-unblock
+			return i, nil
+		}))
+	}
+	var ran atomic.Int32
+	var queued []containers.PromiseInterface[int]
+	for i := 0; i < 5; i++ {
+		queued = append(queued, LaunchPooledPromiseThread[int](pool, func(ctx context.Context) (int, error) {
+			ran.Add(1)
+			return i, nil
+		}))
+	}
+	kept := LaunchPooledPromiseThread[int](pool, func(ctx context.Context) (int, error) {
+		return 42, nil
+	})
+	for _, promise := range queued {
+		promise.Cancel()
+	}
+	for _, promise := range queued {
+		_, err := promise.Await(context.Background())
+		if !errors.Is(err, context.Canceled) {
+			testhelpers.FailImpl(t, "expected cancelled queued thread to error, got", err)
+		}
+	}
+	close(unblock)
+
+	for i, promise := range blockers {
+		val, err := promise.Await(context.Background())
+		testhelpers.RequireImpl(t, err)
+		if val != i {
+			testhelpers.FailImpl(t, "expected", i, "got", val)
+		}
+	}
+	// the turns of the cancelled threads pass on to the one queued after them
+	val, err := kept.Await(context.Background())
+	testhelpers.RequireImpl(t, err)
+	if val != 42 {
+		testhelpers.FailImpl(t, "expected 42, got", val)
+	}
+	if count := ran.Load(); count != 0 {
+		testhelpers.FailImpl(t, count, "cancelled threads ran")
+	}
+}
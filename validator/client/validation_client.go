@@ -258,7 +258,7 @@ func (r *ExecutionClientRun) PrepareRange(start, end uint64) containers.PromiseI
 	})
 }
 
-func (r *ExecutionClientRun) Close() {
+func (r *ExecutionClientRun) Close() error {
 	r.StopOnly()
 	r.LaunchUntrackedThread(func() {
 		err := r.client.client.CallContext(r.GetParentContext(), nil, server_api.Namespace+"_closeExec", r.id)
@@ -266,4 +266,5 @@ func (r *ExecutionClientRun) Close() {
 			log.Warn("closing execution client run got error", "err", err, "client", r.client.Name(), "id", r.id)
 		}
 	})
+	return nil
 }
@@ -121,3 +121,18 @@ type MachineStepResult struct {
 	Status      MachineStatus
 	GlobalState GoGlobalState
 }
+
+// MachineErrorContext describes where in the wasm an errored machine stopped.
+type MachineErrorContext struct {
+	ModuleIndex   uint32
+	ModuleName    string
+	FunctionIndex uint32
+	PC            uint32
+}
+
+// MachineStepResultDebug is a MachineStepResult with extra information for debugging errored machines.
+type MachineStepResultDebug struct {
+	MachineStepResult
+	// ErrorContext is nil unless the machine errored
+	ErrorContext *MachineErrorContext
+}
@@ -2,6 +2,7 @@ package validator
 
 import (
 	"context"
+	"io"
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/ethdb"
@@ -36,6 +37,44 @@ type ExecutionRun interface {
 	GetLastStep() containers.PromiseInterface[*MachineStepResult]
 	GetProofAt(uint64) containers.PromiseInterface[[]byte]
 	PrepareRange(uint64, uint64) containers.PromiseInterface[struct{}]
-	Close()
+	// Close releases the run in the background, so it always returns nil
+	io.Closer
 	CheckAlive(ctx context.Context) error
 }
+
+// ExecutionRunStatus is how far along an execution run is, for operators following long challenge executions.
+type ExecutionRunStatus struct {
+	// CacheStart and CacheEnd are the steps of the first and last machines in the cache
+	CacheStart     uint64
+	CacheEnd       uint64
+	CachedMachines uint64
+	// StepsExecuted counts the machine steps executed since the run was created
+	StepsExecuted uint64
+	// StepsPerSecond is averaged over the last minute, or since the run was created if that's more recent
+	StepsPerSecond float64
+}
+
+// ExecutionRunStatusReporter is implemented by execution runs that can report their status.
+type ExecutionRunStatusReporter interface {
+	Status() ExecutionRunStatus
+}
+
+// MachineInterface is a machine that executes a validation, which challenges bisect over.
+// It's implemented by the arbitrator machines in server_arb, and by the mock in validator/testutil.
+type MachineInterface interface {
+	CloneMachineInterface() MachineInterface
+	GetStepCount() uint64
+	IsRunning() bool
+	IsErrored() bool
+	ValidForStep(uint64) bool
+	Status() uint8
+	Step(context.Context, uint64) error
+	Hash() common.Hash
+	GetGlobalState() GoGlobalState
+	// ProveNextStep writes the proof of the next step to the writer, returning how many bytes were written
+	ProveNextStep(io.Writer) (int, error)
+	// GetErrorContext returns where the machine errored, or nil if it hasn't
+	GetErrorContext() *MachineErrorContext
+	Freeze()
+	Destroy()
+}
@@ -2,6 +2,8 @@ package server_arb
 
 import (
 	"context"
+	"errors"
+	"io"
 
 	"github.com/ethereum/go-ethereum/common"
 
@@ -101,6 +103,15 @@ func (m *BoldMachine) IsErrored() bool {
 	return m.inner.IsErrored()
 }
 
+// GetErrorContext returns the error context of the inner machine, or nil if the
+// machine has not stepped.
+func (m *BoldMachine) GetErrorContext() *validator.MachineErrorContext {
+	if !m.hasStepped {
+		return nil
+	}
+	return m.inner.GetErrorContext()
+}
+
 // Step steps the inner machine if the machine has not stepped, otherwise it
 // steps the zeroth step machine.
 func (m *BoldMachine) Step(ctx context.Context, steps uint64) error {
@@ -134,12 +145,36 @@ func (m *BoldMachine) GetGlobalState() validator.GoGlobalState {
 	return m.inner.GetGlobalState()
 }
 
-// ProveNextStep returns the proof of the next step of the inner machine if the
-// machine has stepped, otherwise it returns the proof that the zeroth step
+// ProveNextStep writes the proof of the next step of the inner machine if the
+// machine has stepped, otherwise it writes the proof that the zeroth step
 // results in the inner machine's initial global state.
-func (m *BoldMachine) ProveNextStep() []byte {
+func (m *BoldMachine) ProveNextStep(w io.Writer) (int, error) {
 	if !m.hasStepped {
-		return m.zeroMachine.ProveNextStep()
+		return m.zeroMachine.ProveNextStep(w)
+	}
+	return m.inner.ProveNextStep(w)
+}
+
+// SerializeState serializes the inner machine. Only machines that have stepped are
+// persisted, as the zeroth step machine is recreated from the inner machine at step 0.
+func (m *BoldMachine) SerializeState(path string) error {
+	inner, ok := m.inner.(SerializableMachine)
+	if !ok || !m.hasStepped {
+		return errors.New("bold machine can't be serialized")
+	}
+	return inner.SerializeState(path)
+}
+
+// DeserializeAndReplaceState replaces the inner machine's state with a serialized one,
+// which is of a machine that has stepped.
+func (m *BoldMachine) DeserializeAndReplaceState(path string) error {
+	inner, ok := m.inner.(SerializableMachine)
+	if !ok {
+		return errors.New("bold machine can't be deserialized")
+	}
+	if err := inner.DeserializeAndReplaceState(path); err != nil {
+		return err
 	}
-	return m.inner.ProveNextStep()
+	m.hasStepped = true
+	return nil
 }
@@ -4,87 +4,347 @@
 package server_arb
 
 import (
+	"bytes"
 	"context"
+	"errors"
 	"fmt"
+	"io"
+	"math/bits"
 	"sync"
+	"sync/atomic"
 	"time"
 
+	"github.com/prometheus/client_golang/prometheus"
+
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/metrics"
 
+	"github.com/offchainlabs/nitro/util/arbmath"
 	"github.com/offchainlabs/nitro/util/containers"
 	"github.com/offchainlabs/nitro/util/stopwaiter"
 	"github.com/offchainlabs/nitro/validator"
 )
 
+var errNilMachine = errors.New("cache returned nil machine with no error")
+
+var (
+	executionRunStepsQueriedCounter    = metrics.NewRegisteredCounter("arb/validator/execution/steps_queried", nil)
+	executionRunProofsGeneratedCounter = metrics.NewRegisteredCounter("arb/validator/execution/proofs_generated", nil)
+)
+
+// executionRun is safe for concurrent use. Its methods may be called from
+// any number of goroutines, and each returns a promise that is resolved on a
+// thread of its own. The machine cache guards its own structure, including
+// against PrepareRange repopulating it, but it hands out the same machine to
+// consecutive lookups, so machineMutex serializes reading machines obtained
+// from it. Work that steps a machine further, like GetStepsInRange, is done
+// on a clone outside of the lock.
 type executionRun struct {
 	stopwaiter.StopWaiter
 	cache *MachineCache
 	close sync.Once
+
+	// pool bounds how much machine work runs at once, as stepping machines is CPU heavy
+	pool *stopwaiter.ThreadPool
+
+	// machineMutex must be held while using a machine returned by the cache
+	machineMutex sync.Mutex
+
+	prepareMutex sync.Mutex
+	preparing    containers.PromiseInterface[struct{}]
+
+	stepsQueried    atomic.Uint64
+	proofsGenerated atomic.Uint64
+}
+
+// ExecutionRunMetrics are the statistics of an execution run since it was created.
+// They're also reported, summed over all runs, as metrics under arb/validator/execution.
+type ExecutionRunMetrics struct {
+	// CacheHits counts machine lookups served by a machine already at the step,
+	// and CacheMisses those that had to step a machine there.
+	CacheHits       uint64
+	CacheMisses     uint64
+	ProofsGenerated uint64
+	StepsQueried    uint64
+}
+
+func (e *executionRun) Metrics() ExecutionRunMetrics {
+	return ExecutionRunMetrics{
+		CacheHits:       e.cache.hits.Load(),
+		CacheMisses:     e.cache.misses.Load(),
+		ProofsGenerated: e.proofsGenerated.Load(),
+		StepsQueried:    e.stepsQueried.Load(),
+	}
+}
+
+var _ validator.ExecutionRunStatusReporter = (*executionRun)(nil)
+
+// Status reports how far along the run is, without waiting on its machine cache.
+func (e *executionRun) Status() validator.ExecutionRunStatus {
+	return e.cache.Status()
+}
+
+// ExecutionRunOption configures the machine cache backing an executionRun.
+type ExecutionRunOption func(*MachineCacheConfig)
+
+// WithConfig replaces the whole machine cache config.
+func WithConfig(c *MachineCacheConfig) ExecutionRunOption {
+	return func(config *MachineCacheConfig) {
+		*config = *c
+	}
+}
+
+// WithMaxCachedMachines sets how many machines are cached while working on a challenge.
+// A negative n is treated as 0, which the config rejects.
+func WithMaxCachedMachines(n int) ExecutionRunOption {
+	return func(config *MachineCacheConfig) {
+		config.CachedChallengeMachines = arbmath.SaturatingUCast[uint64](n)
+	}
+}
+
+// WithCacheSize sets the size of the machine cache. The number of machines it holds is its
+// only bound, so this is the same as WithMaxCachedMachines.
+func WithCacheSize(n int) ExecutionRunOption {
+	return WithMaxCachedMachines(n)
+}
+
+// WithInitialSteps sets the initial number of steps between cached machines.
+func WithInitialSteps(n uint64) ExecutionRunOption {
+	return func(config *MachineCacheConfig) {
+		config.InitialSteps = n
+	}
+}
+
+// WithMaxStepRangeSize sets the maximum number of steps GetStepsInRange may cover.
+func WithMaxStepRangeSize(n uint64) ExecutionRunOption {
+	return func(config *MachineCacheConfig) {
+		config.MaxStepRangeSize = n
+	}
+}
+
+// WithMaxConcurrentRuns sets how many machine operations may run at once, or no limit if 0.
+func WithMaxConcurrentRuns(n int) ExecutionRunOption {
+	return func(config *MachineCacheConfig) {
+		config.MaxConcurrentRuns = n
+	}
+}
+
+// WithPersistenceKey identifies the execution run, so its cached machines are persisted to and loaded from
+// the configured persist path. Without it, or without a persist path, the cache isn't persisted.
+func WithPersistenceKey(moduleRoot common.Hash, messageIndex uint64) ExecutionRunOption {
+	return func(config *MachineCacheConfig) {
+		config.moduleRoot = moduleRoot
+		config.messageIndex = messageIndex
+		config.persistKeySet = true
+	}
 }
 
 // NewExecutionRun creates a backend with the given arguments.
-// Note: machineCache may be nil, but if present, it must not have a restricted
-// range.
+// The machine cache starts from DefaultMachineCacheConfig, and opts are
+// applied in order on top of it. The resulting config must be valid.
 func NewExecutionRun(
 	ctxIn context.Context,
 	initialMachineGetter func(context.Context) (MachineInterface, error),
-	config *MachineCacheConfig,
+	opts ...ExecutionRunOption,
 ) (*executionRun, error) {
+	config := DefaultMachineCacheConfig
+	for _, opt := range opts {
+		opt(&config)
+	}
+	if err := config.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid machine cache config: %w", err)
+	}
 	exec := &executionRun{}
 	exec.Start(ctxIn, exec)
-	exec.cache = NewMachineCache(exec.GetContext(), initialMachineGetter, config)
+	exec.cache = NewMachineCache(exec.GetContext(), initialMachineGetter, &config)
+	exec.pool = stopwaiter.NewThreadPool(exec, config.MaxConcurrentRuns)
 	return exec, nil
 }
 
-func (e *executionRun) Close() {
+var _ io.Closer = (*executionRun)(nil)
+
+// Close stops the run and destroys its machine cache in the background.
+func (e *executionRun) Close() error {
 	go e.close.Do(func() {
 		e.StopAndWait()
 		if e.cache != nil {
 			e.cache.Destroy(e.GetParentContext())
 		}
 	})
+	return nil
 }
 
+// PrepareRange populates the machine cache for the range in the background.
+// Steps requested before it completes wait for it. A later call cancels any
+// population still in flight, which leaves the cache as it was.
+// It doesn't go through the pool, as pooled work may be waiting on it.
 func (e *executionRun) PrepareRange(start uint64, end uint64) containers.PromiseInterface[struct{}] {
-	return stopwaiter.LaunchPromiseThread[struct{}](e, func(ctx context.Context) (struct{}, error) {
+	e.prepareMutex.Lock()
+	defer e.prepareMutex.Unlock()
+	if e.preparing != nil {
+		e.preparing.Cancel()
+	}
+	e.preparing = stopwaiter.LaunchPromiseThread[struct{}](e, func(ctx context.Context) (struct{}, error) {
 		err := e.cache.SetRange(ctx, start, end)
 		return struct{}{}, err
 	})
+	return e.preparing
 }
 
 func (e *executionRun) GetStepAt(position uint64) containers.PromiseInterface[*validator.MachineStepResult] {
-	return stopwaiter.LaunchPromiseThread[*validator.MachineStepResult](e, func(ctx context.Context) (*validator.MachineStepResult, error) {
-		var machine MachineInterface
-		var err error
-		if position == ^uint64(0) {
-			machine, err = e.cache.GetFinalMachine(ctx)
-		} else {
-			machine, err = e.cache.GetMachineAt(ctx, position)
-		}
-		if err != nil {
-			return nil, err
-		}
-		machineStep := machine.GetStepCount()
-		if position != machineStep {
-			machineRunning := machine.IsRunning()
-			if machineRunning || machineStep > position {
-				return nil, fmt.Errorf("machine is in wrong position want: %d, got: %d", position, machine.GetStepCount())
+	return stopwaiter.LaunchPooledPromiseThread[*validator.MachineStepResult](e.pool, func(ctx context.Context) (*validator.MachineStepResult, error) {
+		return consumeMachine(ctx, e, position, func(machine MachineInterface) (*validator.MachineStepResult, error) {
+			e.stepsQueried.Add(1)
+			executionRunStepsQueriedCounter.Inc(1)
+			return machineStepResult(machine), nil
+		})
+	})
+}
+
+// GetHashAt returns only the hash of the machine at the given position, without reading its global state.
+func (e *executionRun) GetHashAt(position uint64) containers.PromiseInterface[common.Hash] {
+	return stopwaiter.LaunchPooledPromiseThread[common.Hash](e.pool, func(ctx context.Context) (common.Hash, error) {
+		return consumeMachine(ctx, e, position, func(machine MachineInterface) (common.Hash, error) {
+			return machine.Hash(), nil
+		})
+	})
+}
+
+// GetStepAtWithDebugInfo is like GetStepAt, but if the machine errored it also reports where.
+func (e *executionRun) GetStepAtWithDebugInfo(position uint64) containers.PromiseInterface[*validator.MachineStepResultDebug] {
+	return stopwaiter.LaunchPooledPromiseThread[*validator.MachineStepResultDebug](e.pool, func(ctx context.Context) (*validator.MachineStepResultDebug, error) {
+		return consumeMachine(ctx, e, position, func(machine MachineInterface) (*validator.MachineStepResultDebug, error) {
+			result := &validator.MachineStepResultDebug{
+				MachineStepResult: *machineStepResult(machine),
+			}
+			if result.Status == validator.MachineStatusErrored {
+				result.ErrorContext = machine.GetErrorContext()
 			}
+			return result, nil
+		})
+	})
+}
+
+// machineStepDuration is how long consuming a machine took once the cache returned it. It's labeled by
+// the power of two bucket of the machine's position, which keeps the number of series bounded.
+var machineStepDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+	Name:    "arb_validator_machine_step_duration_seconds",
+	Help:    "Time spent consuming a machine once the cache returned it, by power of two bucket of its position.",
+	Buckets: prometheus.ExponentialBuckets(1e-6, 4, 12),
+}, []string{"position_bucket"})
+
+func init() {
+	prometheus.MustRegister(machineStepDuration)
+}
+
+// consumeMachine reads the machine at the position with machineMutex held,
+// recording how long consuming it took once the cache returned it.
+func consumeMachine[T any](ctx context.Context, e *executionRun, position uint64, consume func(MachineInterface) (T, error)) (T, error) {
+	duration := machineStepDuration.WithLabelValues(machinePositionBucket(position))
+	e.machineMutex.Lock()
+	defer e.machineMutex.Unlock()
+	machine, err := e.machineAtStep(ctx, position)
+	if err != nil {
+		var zero T
+		return zero, err
+	}
+	start := time.Now()
+	result, err := consume(machine)
+	duration.Observe(time.Since(start).Seconds())
+	return result, err
+}
+
+func machinePositionBucket(position uint64) string {
+	if position == ^uint64(0) {
+		return "final"
+	}
+	return fmt.Sprintf("pow2_%d", bits.Len64(position))
+}
+
+// GetStepsInRange returns the result of every step from start to end inclusive, stepping a single machine
+// one step at a time. The results stop early if the machine stops running before end.
+func (e *executionRun) GetStepsInRange(start, end uint64) containers.PromiseInterface[[]validator.MachineStepResult] {
+	return stopwaiter.LaunchPooledPromiseThread[[]validator.MachineStepResult](e.pool, func(ctx context.Context) ([]validator.MachineStepResult, error) {
+		return e.stepsInRange(ctx, start, end)
+	})
+}
 
+func (e *executionRun) stepsInRange(ctx context.Context, start, end uint64) ([]validator.MachineStepResult, error) {
+	if end < start {
+		return nil, fmt.Errorf("step range end %d is before its start %d", end, start)
+	}
+	if maxSize := e.cache.config.MaxStepRangeSize; end-start > maxSize {
+		return nil, fmt.Errorf("step range %d-%d is larger than the maximum of %d steps", start, end, maxSize)
+	}
+	machine, err := e.cloneMachineAtStep(ctx, start)
+	if err != nil {
+		return nil, err
+	}
+	defer machine.Destroy()
+
+	results := []validator.MachineStepResult{*machineStepResult(machine)}
+	for position := start; position < end && machine.IsRunning(); position++ {
+		if err := ctx.Err(); err != nil {
+			return nil, err
 		}
-		result := &validator.MachineStepResult{
-			Position:    machineStep,
-			Status:      validator.MachineStatus(machine.Status()),
-			GlobalState: machine.GetGlobalState(),
-			Hash:        machine.Hash(),
+		if err := e.cache.stepMachine(ctx, machine, 1); err != nil {
+			return nil, fmt.Errorf("failed to step machine to position %d: %w", position+1, err)
 		}
-		return result, nil
-	})
+		results = append(results, *machineStepResult(machine))
+	}
+	return results, nil
+}
+
+// cloneMachineAtStep returns a clone of the machine at the position, which the caller owns and may step.
+func (e *executionRun) cloneMachineAtStep(ctx context.Context, position uint64) (MachineInterface, error) {
+	e.machineMutex.Lock()
+	defer e.machineMutex.Unlock()
+	// the cache may hand out the same machine again, so the caller gets a clone of it
+	machine, err := e.machineAtStep(ctx, position)
+	if err != nil {
+		return nil, err
+	}
+	return machine.CloneMachineInterface(), nil
+}
+
+// machineAtStep must be called with machineMutex held, and the returned machine must not be used after releasing it.
+func (e *executionRun) machineAtStep(ctx context.Context, position uint64) (MachineInterface, error) {
+	var machine MachineInterface
+	var err error
+	if position == ^uint64(0) {
+		machine, err = e.cache.GetFinalMachine(ctx)
+	} else {
+		machine, err = e.cache.GetMachineAt(ctx, position)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if machine == nil {
+		return nil, errNilMachine
+	}
+	machineStep := machine.GetStepCount()
+	if position != machineStep {
+		machineRunning := machine.IsRunning()
+		if machineRunning || machineStep > position {
+			return nil, fmt.Errorf("machine is in wrong position want: %d, got: %d", position, machine.GetStepCount())
+		}
+
+	}
+	return machine, nil
+}
+
+func machineStepResult(machine MachineInterface) *validator.MachineStepResult {
+	return &validator.MachineStepResult{
+		Position:    machine.GetStepCount(),
+		Status:      validator.MachineStatus(machine.Status()),
+		GlobalState: machine.GetGlobalState(),
+		Hash:        machine.Hash(),
+	}
 }
 
 func (e *executionRun) GetMachineHashesWithStepSize(machineStartIndex, stepSize, maxIterations uint64) containers.PromiseInterface[[]common.Hash] {
-	return stopwaiter.LaunchPromiseThread(e, func(ctx context.Context) ([]common.Hash, error) {
+	return stopwaiter.LaunchPooledPromiseThread(e.pool, func(ctx context.Context) ([]common.Hash, error) {
 		return e.machineHashesWithStepSize(ctx, machineStartIndex, stepSize, maxIterations)
 	})
 }
@@ -101,10 +361,17 @@ func (e *executionRun) machineHashesWithStepSize(
 	if maxIterations == 0 {
 		return nil, fmt.Errorf("max number of iterations cannot be 0")
 	}
-	machine, err := e.cache.GetMachineAt(ctx, machineStartIndex)
+	e.machineMutex.Lock()
+	cached, err := e.cache.GetMachineAt(ctx, machineStartIndex)
+	var machine MachineInterface
+	if err == nil {
+		machine = cached.CloneMachineInterface()
+	}
+	e.machineMutex.Unlock()
 	if err != nil {
 		return nil, err
 	}
+	defer machine.Destroy()
 	log.Info("Advanced WASM machine index, beginning challenge hash computation", "machineStartIndex", machineStartIndex)
 
 	machineHashes := []common.Hash{machine.Hash()}
@@ -126,7 +393,7 @@ func (e *executionRun) machineHashesWithStepSize(
 		absoluteMachineIndex := machineStartIndex + stepSize*(i+1)
 
 		// Advance the machine in step size increments.
-		if err := machine.Step(ctx, stepSize); err != nil {
+		if err := e.cache.stepMachine(ctx, machine, stepSize); err != nil {
 			return nil, fmt.Errorf("failed to step machine to position %d: %w", absoluteMachineIndex, err)
 		}
 		if i%logInterval == 0 || i == maxIterations-1 {
@@ -170,15 +437,42 @@ func (e *executionRun) machineHashesWithStepSize(
 }
 
 func (e *executionRun) GetProofAt(position uint64) containers.PromiseInterface[[]byte] {
-	return stopwaiter.LaunchPromiseThread[[]byte](e, func(ctx context.Context) ([]byte, error) {
-		machine, err := e.cache.GetMachineAt(ctx, position)
-		if err != nil {
+	return stopwaiter.LaunchPooledPromiseThread[[]byte](e.pool, func(ctx context.Context) ([]byte, error) {
+		var proof bytes.Buffer
+		if _, err := e.proveAt(ctx, position, &proof); err != nil {
 			return nil, err
 		}
-		return machine.ProveNextStep(), nil
+		return proof.Bytes(), nil
+	})
+}
+
+// GetProofAtStreaming writes the proof of the step at the position to the writer as it's serialized,
+// so proofs of steps touching a lot of memory don't need to be held in memory in full.
+// The promise resolves to the number of bytes written.
+func (e *executionRun) GetProofAtStreaming(position uint64, w io.Writer) containers.PromiseInterface[int] {
+	return stopwaiter.LaunchPooledPromiseThread[int](e.pool, func(ctx context.Context) (int, error) {
+		return e.proveAt(ctx, position, w)
 	})
 }
 
+func (e *executionRun) proveAt(ctx context.Context, position uint64, w io.Writer) (int, error) {
+	e.machineMutex.Lock()
+	defer e.machineMutex.Unlock()
+	machine, err := e.cache.GetMachineAt(ctx, position)
+	if err != nil {
+		return 0, err
+	}
+	if machine == nil {
+		return 0, errNilMachine
+	}
+	written, err := machine.ProveNextStep(w)
+	if err == nil {
+		e.proofsGenerated.Add(1)
+		executionRunProofsGeneratedCounter.Inc(1)
+	}
+	return written, err
+}
+
 func (e *executionRun) GetLastStep() containers.PromiseInterface[*validator.MachineStepResult] {
 	return e.GetStepAt(^uint64(0))
 }
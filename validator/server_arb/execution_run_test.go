@@ -2,67 +2,28 @@ package server_arb
 
 import (
 	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
 
 	"github.com/ethereum/go-ethereum/common"
 
 	"github.com/offchainlabs/nitro/validator"
+	"github.com/offchainlabs/nitro/validator/testutil"
 )
 
-type mockMachine struct {
-	gs         validator.GoGlobalState
-	totalSteps uint64
-}
-
-func (m *mockMachine) Hash() common.Hash {
-	return m.gs.Hash()
-}
-
-func (m *mockMachine) GetGlobalState() validator.GoGlobalState {
-	return m.gs
-}
-
-func (m *mockMachine) Step(ctx context.Context, stepSize uint64) error {
-	for i := uint64(0); i < stepSize; i++ {
-		if m.gs.PosInBatch == m.totalSteps-1 {
-			return nil
-		}
-		m.gs.PosInBatch += 1
-	}
-	return nil
-}
-
-func (m *mockMachine) CloneMachineInterface() MachineInterface {
-	return &mockMachine{
-		gs:         validator.GoGlobalState{Batch: m.gs.Batch, PosInBatch: m.gs.PosInBatch},
-		totalSteps: m.totalSteps,
-	}
-}
-func (m *mockMachine) GetStepCount() uint64 {
-	return 0
-}
-func (m *mockMachine) IsRunning() bool {
-	return m.gs.PosInBatch < m.totalSteps-1
-}
-func (m *mockMachine) IsErrored() bool {
-	return false
-}
-func (m *mockMachine) ValidForStep(uint64) bool {
-	return true
-}
-func (m *mockMachine) Status() uint8 {
-	if m.gs.PosInBatch == m.totalSteps-1 {
-		return uint8(validator.MachineStatusFinished)
-	}
-	return uint8(validator.MachineStatusRunning)
-}
-func (m *mockMachine) ProveNextStep() []byte {
-	return nil
-}
-func (m *mockMachine) Freeze()  {}
-func (m *mockMachine) Destroy() {}
-
 func Test_machineHashesWithStep(t *testing.T) {
 	t.Run("basic argument checks", func(t *testing.T) {
 		ctx, cancel := context.WithCancel(context.Background())
@@ -84,12 +45,7 @@ func Test_machineHashesWithStep(t *testing.T) {
 	t.Run("machine at start index 0 hash is the finished state hash", func(t *testing.T) {
 		ctx, cancel := context.WithCancel(context.Background())
 		defer cancel()
-		mm := &mockMachine{
-			gs: validator.GoGlobalState{
-				Batch: 1,
-			},
-			totalSteps: 20,
-		}
+		mm := testutil.NewMockMachineBuilder(19).WithBatch(1).Build()
 		machStartIndex := uint64(0)
 		stepSize := uint64(1)
 		maxIterations := uint64(1)
@@ -103,7 +59,7 @@ func Test_machineHashesWithStep(t *testing.T) {
 		if err != nil {
 			t.Fatal(err)
 		}
-		expected := mm.gs.Hash()
+		expected := mm.Hash()
 		if len(hashes) != 1 {
 			t.Error("Wanted one hash")
 		}
@@ -118,10 +74,7 @@ func Test_machineHashesWithStep(t *testing.T) {
 			Batch:      1,
 			PosInBatch: 0,
 		}
-		mm := &mockMachine{
-			gs:         initialGs,
-			totalSteps: 20,
-		}
+		mm := testutil.NewMockMachineBuilder(19).WithBatch(1).Build()
 		machStartIndex := uint64(0)
 		stepSize := uint64(5)
 		maxIterations := uint64(4)
@@ -162,10 +115,7 @@ func Test_machineHashesWithStep(t *testing.T) {
 			Batch:      1,
 			PosInBatch: 0,
 		}
-		mm := &mockMachine{
-			gs:         initialGs,
-			totalSteps: 20,
-		}
+		mm := testutil.NewMockMachineBuilder(19).WithBatch(1).Build()
 		machStartIndex := uint64(0)
 		stepSize := uint64(5)
 		maxIterations := uint64(10)
@@ -193,7 +143,7 @@ func Test_machineHashesWithStep(t *testing.T) {
 		}
 		expectedHashes = append(expectedHashes, validator.GoGlobalState{
 			Batch:      1,
-			PosInBatch: mm.totalSteps - 1,
+			PosInBatch: 19,
 		}.Hash())
 		if uint64(len(hashes)) >= maxIterations {
 			t.Fatal("Wanted fewer hashes than the max iterations")
@@ -205,3 +155,790 @@ func Test_machineHashesWithStep(t *testing.T) {
 		}
 	})
 }
+
+func Test_executionRunOptions(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	getter := func(_ context.Context) (MachineInterface, error) {
+		return testutil.NewMockMachineBuilder(19).Build(), nil
+	}
+
+	e, err := NewExecutionRun(ctx, getter)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer e.Close()
+	if *e.cache.config != DefaultMachineCacheConfig {
+		t.Errorf("Wanted default config, got %+v", *e.cache.config)
+	}
+
+	custom := MachineCacheConfig{CachedChallengeMachines: 8, InitialSteps: 10}
+	e, err = NewExecutionRun(ctx, getter, WithConfig(&custom), WithMaxCachedMachines(6))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer e.Close()
+	want := MachineCacheConfig{CachedChallengeMachines: 6, InitialSteps: 10}
+	if *e.cache.config != want {
+		t.Errorf("Wanted %+v, got %+v", want, *e.cache.config)
+	}
+	if custom.CachedChallengeMachines != 8 {
+		t.Error("WithConfig option mutated the passed in config")
+	}
+
+	e, err = NewExecutionRun(ctx, getter, WithInitialSteps(50))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer e.Close()
+	if e.cache.config.InitialSteps != 50 || e.cache.config.CachedChallengeMachines != DefaultMachineCacheConfig.CachedChallengeMachines {
+		t.Errorf("Wanted only initial steps to change, got %+v", *e.cache.config)
+	}
+
+	e, err = NewExecutionRun(ctx, getter, WithCacheSize(10))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer e.Close()
+	if e.cache.config.CachedChallengeMachines != 10 {
+		t.Errorf("Wanted 10 cached machines, got %+v", *e.cache.config)
+	}
+}
+
+func Test_executionRunInvalidConfig(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	getter := func(_ context.Context) (MachineInterface, error) {
+		return testutil.NewMockMachineBuilder(19).Build(), nil
+	}
+
+	for name, opt := range map[string]ExecutionRunOption{
+		"no cached machines":   WithMaxCachedMachines(0),
+		"negative cache size":  WithCacheSize(-1),
+		"no initial steps":     WithInitialSteps(0),
+		"negative concurrency": WithMaxConcurrentRuns(-1),
+	} {
+		t.Run(name, func(t *testing.T) {
+			e, err := NewExecutionRun(ctx, getter, opt)
+			if err == nil {
+				e.Close()
+				t.Fatal("Wanted an error for an invalid config")
+			}
+			if e != nil {
+				t.Error("Wanted no execution run along with the error")
+			}
+		})
+	}
+}
+
+func newBlockingExecutionRun(t *testing.T, ctx context.Context, opts ...ExecutionRunOption) (*executionRun, *atomic.Bool, chan struct{}) {
+	t.Helper()
+	blocked := &atomic.Bool{}
+	stepping := make(chan struct{}, 1)
+	// while blocked is set, stepping a nonzero number of steps blocks until the context is cancelled
+	builder := testutil.NewMockMachineBuilder(999).WithBatch(1).WithStepHook(func(ctx context.Context, _, stepSize uint64) error {
+		if !blocked.Load() || stepSize == 0 {
+			return nil
+		}
+		select {
+		case stepping <- struct{}{}:
+		default:
+		}
+		<-ctx.Done()
+		return ctx.Err()
+	})
+	getter := func(_ context.Context) (MachineInterface, error) {
+		return builder.Build(), nil
+	}
+	e, err := NewExecutionRun(ctx, getter, append([]ExecutionRunOption{WithInitialSteps(10), WithMaxCachedMachines(4)}, opts...)...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// wait for the initial cache to be built
+	if _, err := e.GetStepAt(0).Await(ctx); err != nil {
+		t.Fatal(err)
+	}
+	return e, blocked, stepping
+}
+
+func waitForStepping(t *testing.T, stepping chan struct{}) {
+	t.Helper()
+	select {
+	case <-stepping:
+	case <-time.After(5 * time.Second):
+		t.Fatal("cache population didn't start")
+	}
+}
+
+func Test_prepareRangeCancelledByLaterCall(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	e, blocked, stepping := newBlockingExecutionRun(t, ctx)
+	defer e.Close()
+
+	blocked.Store(true)
+	first := e.PrepareRange(100, 200)
+	waitForStepping(t, stepping)
+	blocked.Store(false)
+	second := e.PrepareRange(300, 400)
+
+	if _, err := first.Await(ctx); !errors.Is(err, context.Canceled) {
+		t.Fatalf("Wanted first PrepareRange to be cancelled, got %v", err)
+	}
+	if _, err := second.Await(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if err := e.cache.lockBuild(ctx); err != nil {
+		t.Fatal(err)
+	}
+	firstMachineStep := e.cache.firstMachineStep
+	e.cache.unlockBuild(nil)
+	if firstMachineStep != 300 {
+		t.Errorf("Wanted cache to start at the latest range 300, got %d", firstMachineStep)
+	}
+
+	result, err := e.GetStepAt(350).Await(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Position != 350 {
+		t.Errorf("Wanted step 350, got %d", result.Position)
+	}
+}
+
+func Test_prepareRangeStoppedWithoutLeaks(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	e, blocked, stepping := newBlockingExecutionRun(t, ctx)
+
+	blocked.Store(true)
+	promise := e.PrepareRange(100, 200)
+	waitForStepping(t, stepping)
+
+	stopped := make(chan struct{})
+	go func() {
+		e.StopAndWait()
+		close(stopped)
+	}()
+	select {
+	case <-stopped:
+	case <-time.After(5 * time.Second):
+		t.Fatal("executionRun threads still running after stopping")
+	}
+	if _, err := promise.Await(ctx); err == nil {
+		t.Error("Wanted stopped PrepareRange to fail")
+	}
+}
+
+// erroringMachineContext is where machines built by newErroringMachine error.
+var erroringMachineContext = validator.MachineErrorContext{
+	ModuleIndex:   2,
+	ModuleName:    "user",
+	FunctionIndex: 7,
+	PC:            42,
+}
+
+// newErroringMachine runs until errorStep, where it errors as if it hit an unreachable instruction.
+func newErroringMachine(errorStep uint64) *testutil.MockMachine {
+	return testutil.NewMockMachineBuilder(errorStep).WithBatch(1).WithError(erroringMachineContext).Build()
+}
+
+func Test_getStepAtWithDebugInfo(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	getter := func(_ context.Context) (MachineInterface, error) {
+		return newErroringMachine(50), nil
+	}
+	e, err := NewExecutionRun(ctx, getter, WithInitialSteps(10))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer e.Close()
+
+	running, err := e.GetStepAtWithDebugInfo(20).Await(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if running.Status != validator.MachineStatusRunning || running.Position != 20 {
+		t.Errorf("Wanted running machine at step 20, got %+v", running.MachineStepResult)
+	}
+	if running.ErrorContext != nil {
+		t.Errorf("Wanted no error context for a running machine, got %+v", running.ErrorContext)
+	}
+
+	errored, err := e.GetStepAtWithDebugInfo(^uint64(0)).Await(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if errored.Status != validator.MachineStatusErrored || errored.Position != 50 {
+		t.Errorf("Wanted errored machine at step 50, got %+v", errored.MachineStepResult)
+	}
+	if errored.ErrorContext == nil {
+		t.Fatal("Wanted error context for an errored machine")
+	}
+	if *errored.ErrorContext != erroringMachineContext {
+		t.Errorf("Wanted error context %+v, got %+v", erroringMachineContext, *errored.ErrorContext)
+	}
+
+	plain, err := e.GetStepAt(^uint64(0)).Await(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if *plain != errored.MachineStepResult {
+		t.Errorf("Wanted GetStepAt to match the debug result, got %+v and %+v", *plain, errored.MachineStepResult)
+	}
+}
+
+func Test_getHashAt(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	getter := func(_ context.Context) (MachineInterface, error) {
+		return newErroringMachine(50), nil
+	}
+	e, err := NewExecutionRun(ctx, getter, WithInitialSteps(10))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer e.Close()
+
+	for _, position := range []uint64{0, 20, ^uint64(0)} {
+		hash, err := e.GetHashAt(position).Await(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		step, err := e.GetStepAt(position).Await(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if hash != step.Hash {
+			t.Errorf("Wanted hash %v at position %d, got %v", step.Hash, position, hash)
+		}
+	}
+}
+
+func benchmarkExecutionRun(b *testing.B) (context.Context, *executionRun) {
+	b.Helper()
+	ctx, cancel := context.WithCancel(context.Background())
+	b.Cleanup(cancel)
+	getter := func(_ context.Context) (MachineInterface, error) {
+		return newErroringMachine(1 << 20), nil
+	}
+	e, err := NewExecutionRun(ctx, getter, WithInitialSteps(1000))
+	if err != nil {
+		b.Fatal(err)
+	}
+	b.Cleanup(e.Close)
+	// build the cache before timing
+	if _, err := e.GetStepAt(1000).Await(ctx); err != nil {
+		b.Fatal(err)
+	}
+	return ctx, e
+}
+
+func BenchmarkGetStepAt(b *testing.B) {
+	ctx, e := benchmarkExecutionRun(b)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := e.GetStepAt(1000).Await(ctx); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkGetHashAt(b *testing.B) {
+	ctx, e := benchmarkExecutionRun(b)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := e.GetHashAt(1000).Await(ctx); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func Test_getStepsInRange(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	machine := testutil.NewMockMachineBuilder(999).WithBatch(1).Build()
+	getter := func(_ context.Context) (MachineInterface, error) {
+		return machine, nil
+	}
+	e, err := NewExecutionRun(ctx, getter, WithInitialSteps(10), WithMaxStepRangeSize(100))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer e.Close()
+
+	start, end := uint64(205), uint64(300)
+	// position the cache at the start so that only the range itself is stepped through
+	if _, err := e.GetStepAt(start).Await(ctx); err != nil {
+		t.Fatal(err)
+	}
+	stepsBefore := machine.StepsTaken()
+	results, err := e.GetStepsInRange(start, end).Await(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if steps := machine.StepsTaken() - stepsBefore; steps != end-start {
+		t.Errorf("Wanted %d steps, got %d", end-start, steps)
+	}
+	if uint64(len(results)) != end-start+1 {
+		t.Fatalf("Wanted %d results, got %d", end-start+1, len(results))
+	}
+	for i, result := range results {
+		position := start + uint64(i)
+		step, err := e.GetStepAt(position).Await(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if result != *step {
+			t.Errorf("Wanted %+v at position %d, got %+v", *step, position, result)
+		}
+	}
+
+	// the results stop at the final step
+	results, err = e.GetStepsInRange(950, 1010).Await(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != 50 || results[len(results)-1].Status != validator.MachineStatusFinished {
+		t.Errorf("Wanted 50 results ending with the finished machine, got %d", len(results))
+	}
+
+	if _, err := e.GetStepsInRange(300, 200).Await(ctx); err == nil || !strings.Contains(err.Error(), "before its start") {
+		t.Errorf("Wanted error for a range ending before it starts, got %v", err)
+	}
+	if _, err := e.GetStepsInRange(0, 101).Await(ctx); err == nil || !strings.Contains(err.Error(), "larger than the maximum") {
+		t.Errorf("Wanted error for a range over the maximum size, got %v", err)
+	}
+}
+
+func Test_getStepsInRangeCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	e, blocked, stepping := newBlockingExecutionRun(t, ctx)
+	defer e.Close()
+
+	// the cache already holds a machine at step 0, so this blocks stepping through the range
+	blocked.Store(true)
+	promise := e.GetStepsInRange(0, 100)
+	waitForStepping(t, stepping)
+	promise.Cancel()
+	if _, err := promise.Await(ctx); !errors.Is(err, context.Canceled) {
+		t.Errorf("Wanted cancelled GetStepsInRange to fail with context.Canceled, got %v", err)
+	}
+}
+
+func Test_maxConcurrentRunsQueuesWork(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	e, blocked, stepping := newBlockingExecutionRun(t, ctx, WithMaxConcurrentRuns(1))
+	defer e.Close()
+
+	// occupy the only worker stepping through a range
+	blocked.Store(true)
+	busy := e.GetStepsInRange(0, 100)
+	waitForStepping(t, stepping)
+
+	queued := e.GetHashAt(0)
+	cancelled := e.GetStepAt(0)
+	time.Sleep(50 * time.Millisecond)
+	if queued.Ready() || cancelled.Ready() {
+		t.Fatal("Wanted work to wait for the busy worker")
+	}
+	cancelled.Cancel()
+	if _, err := cancelled.Await(ctx); !errors.Is(err, context.Canceled) {
+		t.Errorf("Wanted cancelled queued GetStepAt to fail with context.Canceled, got %v", err)
+	}
+
+	busy.Cancel()
+	if _, err := queued.Await(ctx); err != nil {
+		t.Errorf("Wanted queued GetHashAt to run once the worker was free, got %v", err)
+	}
+}
+
+func Test_getStepAtWithCancelledContextLaunchesNothing(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	e, _, _ := newBlockingExecutionRun(t, ctx)
+	defer e.Close()
+	cancel()
+	<-e.GetContext().Done()
+
+	before := runtime.NumGoroutine()
+	for position := uint64(0); position < 100; position++ {
+		promise := e.GetStepAt(position)
+		if !promise.Ready() {
+			t.Fatal("Wanted GetStepAt to fail right away with a cancelled context")
+		}
+		if _, err := promise.Await(context.Background()); !errors.Is(err, context.Canceled) {
+			t.Fatalf("Wanted context.Canceled, got %v", err)
+		}
+	}
+	if after := runtime.NumGoroutine(); after > before {
+		t.Errorf("Wanted no goroutines to be left behind, went from %d to %d", before, after)
+	}
+}
+
+// exclusiveMachine records a violation whenever it's used by two goroutines at once or after being destroyed.
+type exclusiveMachine struct {
+	inner      *testutil.MockMachine
+	inUse      *atomic.Int32
+	violations *atomic.Uint64
+}
+
+func newExclusiveMachine(inner *testutil.MockMachine, violations *atomic.Uint64) *exclusiveMachine {
+	return &exclusiveMachine{
+		inner:      inner,
+		inUse:      &atomic.Int32{},
+		violations: violations,
+	}
+}
+
+func (m *exclusiveMachine) enter() func() {
+	if m.inUse.Add(1) != 1 || m.inner.Destroyed() {
+		m.violations.Add(1)
+	}
+	// give other goroutines a chance to use the machine at the same time
+	runtime.Gosched()
+	return func() { m.inUse.Add(-1) }
+}
+
+func (m *exclusiveMachine) Hash() common.Hash {
+	defer m.enter()()
+	return m.inner.Hash()
+}
+func (m *exclusiveMachine) GetGlobalState() validator.GoGlobalState {
+	defer m.enter()()
+	return m.inner.GetGlobalState()
+}
+func (m *exclusiveMachine) Step(ctx context.Context, stepSize uint64) error {
+	defer m.enter()()
+	return m.inner.Step(ctx, stepSize)
+}
+func (m *exclusiveMachine) CloneMachineInterface() MachineInterface {
+	defer m.enter()()
+	return newExclusiveMachine(m.inner.Clone(), m.violations)
+}
+func (m *exclusiveMachine) GetStepCount() uint64 {
+	defer m.enter()()
+	return m.inner.GetStepCount()
+}
+func (m *exclusiveMachine) IsRunning() bool {
+	defer m.enter()()
+	return m.inner.IsRunning()
+}
+func (m *exclusiveMachine) IsErrored() bool {
+	return m.inner.IsErrored()
+}
+func (m *exclusiveMachine) ValidForStep(step uint64) bool {
+	return m.inner.ValidForStep(step)
+}
+func (m *exclusiveMachine) Status() uint8 {
+	return m.inner.Status()
+}
+func (m *exclusiveMachine) ProveNextStep(w io.Writer) (int, error) {
+	defer m.enter()()
+	return m.inner.ProveNextStep(w)
+}
+func (m *exclusiveMachine) GetErrorContext() *validator.MachineErrorContext {
+	return m.inner.GetErrorContext()
+}
+func (m *exclusiveMachine) Freeze() {
+	m.inner.Freeze()
+}
+func (m *exclusiveMachine) Destroy() {
+	defer m.enter()()
+	m.inner.Destroy()
+}
+
+func Test_executionRunConcurrentUse(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	const totalSteps = 10000
+	violations := &atomic.Uint64{}
+	getter := func(_ context.Context) (MachineInterface, error) {
+		return newExclusiveMachine(testutil.NewMockMachineBuilder(totalSteps-1).WithBatch(1).Build(), violations), nil
+	}
+	e, err := NewExecutionRun(ctx, getter, WithInitialSteps(100), WithMaxCachedMachines(4))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer e.Close()
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 8)
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func(g uint64) {
+			defer wg.Done()
+			for i := uint64(0); i < 50; i++ {
+				position := (g*997 + i*131) % (totalSteps - 1)
+				if i%10 == 0 {
+					// PrepareRange is cancelled by later calls, so only wait for it without checking its result
+					_, _ = e.PrepareRange(position, position+1000).Await(ctx)
+				}
+				step, err := e.GetStepAt(position).Await(ctx)
+				if err != nil {
+					errs <- err
+					return
+				}
+				if step.Position != position || step.GlobalState.PosInBatch != position {
+					errs <- fmt.Errorf("wanted step %d, got %+v", position, *step)
+					return
+				}
+				proof, err := e.GetProofAt(position).Await(ctx)
+				if err != nil {
+					errs <- err
+					return
+				}
+				if got := binary.BigEndian.Uint64(proof); got != position {
+					errs <- fmt.Errorf("wanted proof at step %d, got step %d", position, got)
+					return
+				}
+			}
+		}(uint64(g))
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Error(err)
+	}
+	if count := violations.Load(); count != 0 {
+		t.Errorf("machines were used concurrently or after being destroyed %d times", count)
+	}
+}
+
+func Test_nilMachineFromCache(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	e, err := NewExecutionRun(ctx, func(_ context.Context) (MachineInterface, error) {
+		return testutil.NewMockMachineBuilder(19).WithBatch(1).Build(), nil
+	}, WithInitialSteps(5))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer e.Close()
+	// wait for the cache to be built, then simulate it losing its final machine without an error
+	if err := e.cache.lockBuild(ctx); err != nil {
+		t.Fatal(err)
+	}
+	e.cache.finalMachine = nil
+	e.cache.unlockBuild(nil)
+
+	if _, err := e.GetLastStep().Await(ctx); !errors.Is(err, errNilMachine) {
+		t.Errorf("Wanted errNilMachine from GetLastStep, got %v", err)
+	}
+	if _, err := e.GetHashAt(^uint64(0)).Await(ctx); !errors.Is(err, errNilMachine) {
+		t.Errorf("Wanted errNilMachine from GetHashAt, got %v", err)
+	}
+}
+
+func Test_machineCachePersistence(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	persistPath := t.TempDir()
+	moduleRoot := common.HexToHash("0x1234")
+	// each run gets its own builder, so the steps it takes are counted separately, like after a restart
+	startRun := func(moduleRoot common.Hash) (*executionRun, *testutil.MockMachine) {
+		t.Helper()
+		initial := testutil.NewMockMachineBuilder(1000).WithBatch(1).Build()
+		getter := func(_ context.Context) (MachineInterface, error) {
+			return initial.Clone(), nil
+		}
+		config := DefaultMachineCacheConfig
+		config.PersistPath = persistPath
+		e, err := NewExecutionRun(ctx, getter, WithConfig(&config), WithInitialSteps(10), WithMaxCachedMachines(4), WithPersistenceKey(moduleRoot, 7))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return e, initial
+	}
+	expectStep := func(e *executionRun, position uint64) {
+		t.Helper()
+		result, err := e.GetStepAt(position).Await(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if result.Position != position || result.GlobalState.PosInBatch != position {
+			t.Fatalf("Wanted machine at step %d, got %+v", position, result)
+		}
+	}
+
+	e, initial := startRun(moduleRoot)
+	if _, err := e.PrepareRange(200, 600).Await(ctx); err != nil {
+		t.Fatal(err)
+	}
+	expectStep(e, 400)
+	if initial.StepsTaken() == 0 {
+		t.Fatal("Wanted the first run to step its machines")
+	}
+	e.Close()
+
+	e, initial = startRun(moduleRoot)
+	expectStep(e, 400)
+	expectStep(e, 1000)
+	if steps := initial.StepsTaken(); steps != 0 {
+		t.Errorf("Wanted the restarted run to load its machines without stepping, took %d steps", steps)
+	}
+	expectStep(e, 450)
+	if steps := initial.StepsTaken(); steps != 50 {
+		t.Errorf("Wanted the restarted run to step from the persisted machine at step 400, took %d steps", steps)
+	}
+	e.Close()
+
+	// machines persisted for another module root aren't used
+	e, initial = startRun(common.HexToHash("0x5678"))
+	expectStep(e, 400)
+	if initial.StepsTaken() == 0 {
+		t.Error("Wanted a run of another module root to step its own machines")
+	}
+	e.Close()
+
+	// a corrupt machine is deleted, and the run steps from zero again
+	runDir := filepath.Join(persistPath, moduleRoot.Hex(), "7")
+	if err := os.WriteFile(filepath.Join(runDir, "400"), []byte("corrupt"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	e, initial = startRun(moduleRoot)
+	expectStep(e, 400)
+	if initial.StepsTaken() == 0 {
+		t.Error("Wanted the run with a corrupt persisted machine to step from zero")
+	}
+	e.Close()
+	if _, err := os.Stat(filepath.Join(runDir, "400")); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("Wanted the corrupt machine to be deleted, got %v", err)
+	}
+
+	// the rebuilt cache was persisted again
+	e, initial = startRun(moduleRoot)
+	defer e.Close()
+	expectStep(e, 1000)
+	if steps := initial.StepsTaken(); steps != 0 {
+		t.Errorf("Wanted the rebuilt cache to be loaded without stepping, took %d steps", steps)
+	}
+}
+
+// largeProofMachine writes a synthetic proof of proofSize bytes, in chunks from a small buffer.
+type largeProofMachine struct {
+	*testutil.MockMachine
+	proofSize int
+}
+
+func (m *largeProofMachine) CloneMachineInterface() MachineInterface {
+	return &largeProofMachine{m.Clone(), m.proofSize}
+}
+
+func (m *largeProofMachine) ProveNextStep(w io.Writer) (int, error) {
+	chunk := make([]byte, 64*1024)
+	written := 0
+	for written < m.proofSize {
+		n, err := w.Write(chunk[:min(len(chunk), m.proofSize-written)])
+		written += n
+		if err != nil {
+			return written, err
+		}
+	}
+	return written, nil
+}
+
+type countingWriter struct {
+	count int
+}
+
+func (w *countingWriter) Write(p []byte) (int, error) {
+	w.count += len(p)
+	return len(p), nil
+}
+
+func Test_getProofAtStreamingBoundsMemory(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	const proofSize = 100 * 1024 * 1024
+	e, err := NewExecutionRun(ctx, func(_ context.Context) (MachineInterface, error) {
+		return &largeProofMachine{testutil.NewMockMachineBuilder(99).WithBatch(1).Build(), proofSize}, nil
+	}, WithInitialSteps(10))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer e.Close()
+	// build the cache first, so only the proof's allocations are measured
+	if _, err := e.GetStepAt(50).Await(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	var before, after runtime.MemStats
+	runtime.ReadMemStats(&before)
+	var w countingWriter
+	written, err := e.GetProofAtStreaming(50, &w).Await(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	runtime.ReadMemStats(&after)
+	if written != proofSize || w.count != proofSize {
+		t.Errorf("Wanted %d bytes of proof, wrote %d and the writer got %d", proofSize, written, w.count)
+	}
+	if allocated := after.TotalAlloc - before.TotalAlloc; allocated > 16*1024*1024 {
+		t.Errorf("Wanted streaming a %d byte proof to allocate a bounded amount of memory, allocated %d bytes", proofSize, allocated)
+	}
+}
+
+func Test_consumeMachineRecordsDuration(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	e, err := NewExecutionRun(ctx, func(_ context.Context) (MachineInterface, error) {
+		return testutil.NewMockMachineBuilder(199).WithBatch(1).Build(), nil
+	}, WithInitialSteps(10))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer e.Close()
+
+	// positions 64 to 127 all fall in the same bucket, which other tests may have observed too
+	observations := func() uint64 {
+		var m dto.Metric
+		if err := machineStepDuration.WithLabelValues(machinePositionBucket(64)).(prometheus.Metric).Write(&m); err != nil {
+			t.Fatal(err)
+		}
+		return m.GetHistogram().GetSampleCount()
+	}
+	before := observations()
+	for i := uint64(0); i < 100; i++ {
+		if _, err := e.GetStepAt(64 + i%64).Await(ctx); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if observed := observations() - before; observed != 100 {
+		t.Errorf("Wanted 100 observations of consuming machines, got %d", observed)
+	}
+}
+
+func Test_executionRunMetrics(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	e, err := NewExecutionRun(ctx, func(_ context.Context) (MachineInterface, error) {
+		return testutil.NewMockMachineBuilder(99).WithBatch(1).Build(), nil
+	}, WithInitialSteps(10))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer e.Close()
+
+	for _, position := range []uint64{5, 20, 20, 50} {
+		if _, err := e.GetStepAt(position).Await(ctx); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := e.GetProofAt(30).Await(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	stats := e.Metrics()
+	if stats.StepsQueried != 4 {
+		t.Errorf("Wanted 4 steps queried, got %d", stats.StepsQueried)
+	}
+	if stats.ProofsGenerated != 1 {
+		t.Errorf("Wanted 1 proof generated, got %d", stats.ProofsGenerated)
+	}
+	if stats.CacheHits == 0 || stats.CacheMisses == 0 {
+		t.Errorf("Wanted both cache hits and misses, got %d hits and %d misses", stats.CacheHits, stats.CacheMisses)
+	}
+}
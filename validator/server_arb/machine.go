@@ -15,6 +15,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io"
 	"runtime"
 	"sync"
 	"sync/atomic"
@@ -35,20 +36,8 @@ type u32 = C.uint32_t
 type u64 = C.uint64_t
 type usize = C.size_t
 
-type MachineInterface interface {
-	CloneMachineInterface() MachineInterface
-	GetStepCount() uint64
-	IsRunning() bool
-	IsErrored() bool
-	ValidForStep(uint64) bool
-	Status() uint8
-	Step(context.Context, uint64) error
-	Hash() common.Hash
-	GetGlobalState() validator.GoGlobalState
-	ProveNextStep() []byte
-	Freeze()
-	Destroy()
-}
+// MachineInterface is defined in the validator package, so it can be implemented without cgo.
+type MachineInterface = validator.MachineInterface
 
 // ArbitratorMachine holds an arbitrator machine pointer, and manages its lifetime
 type ArbitratorMachine struct {
@@ -195,6 +184,25 @@ func (m *ArbitratorMachine) IsErrored() bool {
 	return C.arbitrator_get_status(m.ptr) == C.ARBITRATOR_MACHINE_STATUS_ERRORED
 }
 
+func (m *ArbitratorMachine) GetErrorContext() *validator.MachineErrorContext {
+	defer runtime.KeepAlive(m)
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	var pc C.ProgramCounter
+	moduleName := &C.RustBytes{}
+	if !C.arbitrator_get_error_context(m.ptr, &pc, moduleName) {
+		return nil
+	}
+	defer C.free_rust_bytes(*moduleName)
+	return &validator.MachineErrorContext{
+		ModuleIndex:   uint32(pc.module),
+		ModuleName:    string(C.GoBytes(unsafe.Pointer(moduleName.ptr), C.int(moduleName.len))),
+		FunctionIndex: uint32(pc._func),
+		PC:            uint32(pc.inst),
+	}
+}
+
 func (m *ArbitratorMachine) Status() uint8 {
 	defer runtime.KeepAlive(m)
 	m.mutex.Lock()
@@ -299,7 +307,8 @@ func (m *ArbitratorMachine) GetModuleRoot() (hash common.Hash) {
 	return
 }
 
-func (m *ArbitratorMachine) ProveNextStep() []byte {
+// ProveNextStep writes the proof straight from the prover's buffer, so proofs aren't copied into Go memory.
+func (m *ArbitratorMachine) ProveNextStep(w io.Writer) (int, error) {
 	defer runtime.KeepAlive(m)
 	m.mutex.Lock()
 	defer m.mutex.Unlock()
@@ -308,11 +317,11 @@ func (m *ArbitratorMachine) ProveNextStep() []byte {
 	C.arbitrator_gen_proof(m.ptr, output)
 	defer C.free_rust_bytes(*output)
 	if output.len == 0 {
-		return nil
+		return 0, nil
 	}
-	proofBytes := C.GoBytes(unsafe.Pointer(output.ptr), C.int(output.len))
-
-	return proofBytes
+	// writers must not retain the slice, so it's fine for it to point into the prover's buffer
+	proof := unsafe.Slice((*byte)(unsafe.Pointer(output.ptr)), int(output.len))
+	return w.Write(proof)
 }
 
 func (m *ArbitratorMachine) SerializeState(path string) error {
@@ -7,9 +7,23 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"runtime"
 	"sync"
+	"sync/atomic"
+	"time"
 
 	flag "github.com/spf13/pflag"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/metrics"
+
+	"github.com/offchainlabs/nitro/validator"
+)
+
+var (
+	machineCacheHitsCounter   = metrics.NewRegisteredCounter("arb/validator/execution/cache/hits", nil)
+	machineCacheMissesCounter = metrics.NewRegisteredCounter("arb/validator/execution/cache/misses", nil)
 )
 
 // MachineCache manages a list of machines at various step counts.
@@ -25,24 +39,103 @@ type MachineCache struct {
 	firstMachineStep    uint64
 	machineStepInterval uint64
 	config              *MachineCacheConfig
+	// nil unless the cache is persisted to disk
+	persister *machinePersister
 
 	lastMachine     MachineInterface
 	lastMachineLock sync.Mutex
+
+	// lookups served by a machine already at the step, and those that had to step one there
+	hits   atomic.Uint64
+	misses atomic.Uint64
+
+	// the status is kept in atomics, so reporting it neither waits on nor slows down stepping
+	created        time.Time
+	cacheStart     atomic.Uint64
+	cacheEnd       atomic.Uint64
+	cachedMachines atomic.Uint64
+	stepsExecuted  atomic.Uint64
+	stepRate       stepRate
+}
+
+// stepRateWindow is how many seconds the steps per second are averaged over
+const stepRateWindow = 60
+
+// stepRate counts the steps executed in each of the last stepRateWindow seconds.
+// Steps executed concurrently as a bucket is reused may be miscounted, which is fine for an estimate.
+type stepRate struct {
+	buckets [stepRateWindow]struct {
+		second atomic.Int64
+		steps  atomic.Uint64
+	}
+}
+
+func (r *stepRate) add(now time.Time, steps uint64) {
+	second := now.Unix()
+	bucket := &r.buckets[second%stepRateWindow]
+	if old := bucket.second.Load(); old != second && bucket.second.CompareAndSwap(old, second) {
+		bucket.steps.Store(0)
+	}
+	bucket.steps.Add(steps)
+}
+
+// perSecond averages the steps over the window, or over the time since start if that's shorter
+func (r *stepRate) perSecond(now time.Time, start time.Time) float64 {
+	second := now.Unix()
+	steps := uint64(0)
+	for i := range r.buckets {
+		bucketSecond := r.buckets[i].second.Load()
+		if bucketSecond > second-stepRateWindow && bucketSecond <= second {
+			steps += r.buckets[i].steps.Load()
+		}
+	}
+	window := min(now.Sub(start).Seconds(), stepRateWindow)
+	if window < 1 {
+		window = 1
+	}
+	return float64(steps) / window
 }
 
 type MachineCacheConfig struct {
 	CachedChallengeMachines uint64 `koanf:"cached-challenge-machines"`
 	InitialSteps            uint64 `koanf:"initial-steps"`
+	MaxStepRangeSize        uint64 `koanf:"max-step-range-size"`
+	MaxConcurrentRuns       int    `koanf:"max-concurrent-runs"`
+	PersistPath             string `koanf:"persist-path"`
+
+	// set by WithPersistenceKey, identifying the execution run whose machines are persisted
+	moduleRoot    common.Hash
+	messageIndex  uint64
+	persistKeySet bool
 }
 
 var DefaultMachineCacheConfig = MachineCacheConfig{
 	CachedChallengeMachines: 4,
 	InitialSteps:            100000,
+	MaxStepRangeSize:        1 << 20,
+	MaxConcurrentRuns:       runtime.NumCPU(),
+	PersistPath:             "",
 }
 
 func MachineCacheConfigConfigAddOptions(prefix string, f *flag.FlagSet) {
 	f.Uint64(prefix+".initial-steps", DefaultMachineCacheConfig.InitialSteps, "initial steps between machines")
 	f.Uint64(prefix+".cached-challenge-machines", DefaultMachineCacheConfig.CachedChallengeMachines, "how many machines to store in cache while working on a challenge (should be even)")
+	f.Uint64(prefix+".max-step-range-size", DefaultMachineCacheConfig.MaxStepRangeSize, "maximum number of steps whose results can be requested at once")
+	f.Int(prefix+".max-concurrent-runs", DefaultMachineCacheConfig.MaxConcurrentRuns, "maximum number of machine operations an execution run does at once, such as computing steps or proofs (0 for no limit)")
+	f.String(prefix+".persist-path", DefaultMachineCacheConfig.PersistPath, "directory to persist cached machines to, so they don't need to be stepped again after a restart (empty to not persist)")
+}
+
+func (c *MachineCacheConfig) Validate() error {
+	if c.CachedChallengeMachines == 0 {
+		return errors.New("cached-challenge-machines must be at least 1")
+	}
+	if c.InitialSteps == 0 {
+		return errors.New("initial-steps must be at least 1")
+	}
+	if c.MaxConcurrentRuns < 0 {
+		return fmt.Errorf("max-concurrent-runs %v is negative", c.MaxConcurrentRuns)
+	}
+	return nil
 }
 
 // `initialMachine` won't be mutated by this function.
@@ -50,6 +143,8 @@ func NewMachineCache(ctx context.Context, initialMachineGetter func(context.Cont
 	cache := &MachineCache{
 		buildingLock: make(chan struct{}, 1), // locked on init
 		config:       config,
+		created:      time.Now(),
+		persister:    newMachinePersister(config),
 	}
 	go func() {
 		zeroStepMachine, err := initialMachineGetter(ctx)
@@ -63,6 +158,15 @@ func NewMachineCache(ctx context.Context, initialMachineGetter func(context.Cont
 		}
 		zeroStepMachine.Freeze()
 		cache.zeroStepMachine = zeroStepMachine
+		if cache.persister != nil {
+			if _, ok := zeroStepMachine.(SerializableMachine); !ok {
+				log.Warn("not persisting machine cache, as its machines can't be serialized")
+				cache.persister = nil
+			} else if cache.persister.load(cache) {
+				cache.unlockBuild(nil)
+				return
+			}
+		}
 		cache.machines = []MachineInterface{}
 		cache.machineStepInterval = config.InitialSteps
 		cache.finalMachineStep = ^uint64(0)
@@ -91,6 +195,9 @@ func NewMachineCache(ctx context.Context, initialMachineGetter func(context.Cont
 		cache.machines = cache.machines[:len(cache.machines)-1]
 		cache.finalMachine = lastMachine
 		cache.finalMachineStep = lastMachine.GetStepCount()
+		if cache.persister != nil {
+			cache.persister.save(cache)
+		}
 		cache.unlockBuild(nil)
 	}()
 	return cache
@@ -135,11 +242,43 @@ func (c *MachineCache) unlockBuild(err error) {
 	c.err = err
 	if err != nil {
 		c.destroyWithLock()
+		c.cachedMachines.Store(0)
+	} else if len(c.machines) > 0 {
+		c.cacheStart.Store(c.machines[0].GetStepCount())
+		c.cacheEnd.Store(c.machines[len(c.machines)-1].GetStepCount())
+		c.cachedMachines.Store(uint64(len(c.machines)))
 	}
 	c.buildingLock <- struct{}{}
 }
 
+// stepMachine steps the machine, recording how many steps it executed
+func (c *MachineCache) stepMachine(ctx context.Context, machine MachineInterface, steps uint64) error {
+	before := machine.GetStepCount()
+	err := machine.Step(ctx, steps)
+	if executed := machine.GetStepCount() - before; executed > 0 {
+		c.stepsExecuted.Add(executed)
+		c.stepRate.add(time.Now(), executed)
+	}
+	return err
+}
+
+// Status reports the cached range and the steps executed, without waiting for the cache to be built.
+func (c *MachineCache) Status() validator.ExecutionRunStatus {
+	return validator.ExecutionRunStatus{
+		CacheStart:     c.cacheStart.Load(),
+		CacheEnd:       c.cacheEnd.Load(),
+		CachedMachines: c.cachedMachines.Load(),
+		StepsExecuted:  c.stepsExecuted.Load(),
+		StepsPerSecond: c.stepRate.perSecond(time.Now(), c.created),
+	}
+}
+
+// setRangeLocked repopulates the cache for the range. If it fails, including
+// if ctx is cancelled, the cache is left as it was.
 func (c *MachineCache) setRangeLocked(ctx context.Context, start uint64, end uint64) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
 	newInterval := (end - start) / c.config.CachedChallengeMachines
 	if newInterval == 0 {
 		newInterval = 2
@@ -162,38 +301,58 @@ func (c *MachineCache) setRangeLocked(ctx context.Context, start uint64, end uin
 	if closestStep > start {
 		return fmt.Errorf("initial machine step too large %d > %d", closestStep, start)
 	}
-	for i, mach := range c.machines {
-		if i != closestIndex {
-			mach.Destroy()
-		}
-	}
 	var initial MachineInterface
 	if closestStep < start {
 		initial = closest.CloneMachineInterface()
-		err := initial.Step(ctx, start-closestStep)
+		err := c.stepMachine(ctx, initial, start-closestStep)
 		if err != nil {
+			initial.Destroy()
 			return err
 		}
-		if closest != c.zeroStepMachine && closest != c.finalMachine {
-			closest.Destroy()
-		}
 		initial.Freeze()
+		// the closest machine is no longer needed once the new cache is in place
+		closestIndex = -1
 	} else {
 		initial = closest
 	}
+	oldMachines := c.machines
+	oldFirstMachineStep := c.firstMachineStep
+	oldMachineStepInterval := c.machineStepInterval
 	c.machines = []MachineInterface{initial}
 	c.firstMachineStep = start
 	c.machineStepInterval = newInterval
-	return c.populateCache(ctx)
+	err := c.populateCache(ctx)
+	if err != nil {
+		for _, mach := range c.machines {
+			if mach != initial || initial != closest {
+				mach.Destroy()
+			}
+		}
+		c.machines = oldMachines
+		c.firstMachineStep = oldFirstMachineStep
+		c.machineStepInterval = oldMachineStepInterval
+		return err
+	}
+	for i, mach := range oldMachines {
+		if i != closestIndex {
+			mach.Destroy()
+		}
+	}
+	if c.persister != nil {
+		c.persister.save(c)
+	}
+	return nil
 }
 
+// SetRange repopulates the cache for the range. Failing to do so, for
+// instance because ctx was cancelled, leaves the previous cache in place.
 func (c *MachineCache) SetRange(ctx context.Context, start uint64, end uint64) error {
 	err := c.lockBuild(ctx)
 	if err != nil {
 		return err
 	}
 	err = c.setRangeLocked(ctx, start, end)
-	c.unlockBuild(err)
+	c.unlockBuild(nil)
 	return err
 }
 
@@ -206,6 +365,9 @@ func (c *MachineCache) populateCache(ctx context.Context) error {
 		nextMachine = c.machines[len(c.machines)-1]
 	}
 	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
 		if !nextMachine.IsRunning() {
 			break
 		}
@@ -216,7 +378,7 @@ func (c *MachineCache) populateCache(ctx context.Context) error {
 			break
 		}
 		nextMachine = nextMachine.CloneMachineInterface()
-		err := nextMachine.Step(ctx, c.machineStepInterval)
+		err := c.stepMachine(ctx, nextMachine, c.machineStepInterval)
 		if err != nil {
 			return err
 		}
@@ -279,7 +441,14 @@ func (c *MachineCache) GetMachineAt(ctx context.Context, stepCount uint64) (Mach
 	}
 	c.unlockBuild(nil)
 
-	err = closestMachine.Step(ctx, stepCount-closestMachine.GetStepCount())
+	if closestMachine.GetStepCount() == stepCount {
+		c.hits.Add(1)
+		machineCacheHitsCounter.Inc(1)
+	} else {
+		c.misses.Add(1)
+		machineCacheMissesCounter.Inc(1)
+	}
+	err = c.stepMachine(ctx, closestMachine, stepCount-closestMachine.GetStepCount())
 	if err != nil {
 		return nil, err
 	}
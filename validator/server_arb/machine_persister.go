@@ -0,0 +1,190 @@
+// Copyright 2024, Offchain Labs, Inc.
+// For license information, see https://github.com/nitro/blob/master/LICENSE
+
+package server_arb
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/log"
+)
+
+// SerializableMachine is a machine whose state can be written to and read back from a file.
+type SerializableMachine interface {
+	MachineInterface
+	SerializeState(path string) error
+	DeserializeAndReplaceState(path string) error
+}
+
+const persistedCacheManifest = "cache.json"
+
+// persistedCache describes the machines of a cache persisted to disk, each stored in a file named after its step count.
+type persistedCache struct {
+	// StartHash is the hash of the zero step machine, to not load machines of a different input
+	StartHash           common.Hash `json:"startHash"`
+	FirstMachineStep    uint64      `json:"firstMachineStep"`
+	MachineStepInterval uint64      `json:"machineStepInterval"`
+	MachineSteps        []uint64    `json:"machineSteps"`
+	FinalMachineStep    uint64      `json:"finalMachineStep"`
+}
+
+// machinePersister persists the machines of a cache, so a restarted validator doesn't need to step them again.
+// It's keyed by the wasm module root and message index of the execution run, and isn't safe for concurrent use.
+type machinePersister struct {
+	dir string
+	// the steps of the machines currently on disk
+	persisted map[uint64]bool
+}
+
+func newMachinePersister(config *MachineCacheConfig) *machinePersister {
+	if config.PersistPath == "" || !config.persistKeySet {
+		return nil
+	}
+	return &machinePersister{
+		dir:       filepath.Join(config.PersistPath, config.moduleRoot.Hex(), strconv.FormatUint(config.messageIndex, 10)),
+		persisted: make(map[uint64]bool),
+	}
+}
+
+func (p *machinePersister) machinePath(step uint64) string {
+	return filepath.Join(p.dir, strconv.FormatUint(step, 10))
+}
+
+// clear deletes everything persisted for the execution run.
+func (p *machinePersister) clear() {
+	if err := os.RemoveAll(p.dir); err != nil {
+		log.Warn("failed to delete persisted machines", "dir", p.dir, "err", err)
+	}
+	p.persisted = make(map[uint64]bool)
+}
+
+// load restores the cache from disk, returning whether there was a usable cache persisted.
+// Persisted machines that can't be loaded are deleted.
+func (p *machinePersister) load(c *MachineCache) bool {
+	data, err := os.ReadFile(filepath.Join(p.dir, persistedCacheManifest))
+	if errors.Is(err, os.ErrNotExist) {
+		return false
+	}
+	var manifest persistedCache
+	if err == nil {
+		err = json.Unmarshal(data, &manifest)
+	}
+	if err != nil {
+		log.Warn("deleting unreadable persisted machine cache", "dir", p.dir, "err", err)
+		p.clear()
+		return false
+	}
+	if manifest.StartHash != c.zeroStepMachine.Hash() {
+		log.Info("deleting persisted machine cache of a different input", "dir", p.dir)
+		p.clear()
+		return false
+	}
+	machines := make([]MachineInterface, 0, len(manifest.MachineSteps))
+	for _, step := range append(manifest.MachineSteps, manifest.FinalMachineStep) {
+		machine, err := p.loadMachine(c.zeroStepMachine, step)
+		if err != nil {
+			log.Warn("deleting persisted machine cache with a corrupt machine", "dir", p.dir, "step", step, "err", err)
+			for _, mach := range machines {
+				mach.Destroy()
+			}
+			p.clear()
+			return false
+		}
+		machines = append(machines, machine)
+		p.persisted[step] = true
+	}
+	c.machines = machines[:len(machines)-1]
+	c.finalMachine = machines[len(machines)-1]
+	c.finalMachineStep = manifest.FinalMachineStep
+	c.firstMachineStep = manifest.FirstMachineStep
+	c.machineStepInterval = manifest.MachineStepInterval
+	log.Info("loaded persisted machine cache", "dir", p.dir, "machines", len(machines), "finalStep", manifest.FinalMachineStep)
+	return true
+}
+
+func (p *machinePersister) loadMachine(zeroStepMachine MachineInterface, step uint64) (MachineInterface, error) {
+	machine, ok := zeroStepMachine.CloneMachineInterface().(SerializableMachine)
+	if !ok {
+		return nil, errors.New("machine can't be deserialized")
+	}
+	if err := machine.DeserializeAndReplaceState(p.machinePath(step)); err != nil {
+		machine.Destroy()
+		return nil, err
+	}
+	if machine.GetStepCount() != step {
+		machine.Destroy()
+		return nil, fmt.Errorf("machine is at step %v", machine.GetStepCount())
+	}
+	machine.Freeze()
+	return machine, nil
+}
+
+// save writes the cache's machines that aren't on disk yet, and deletes those no longer in the cache.
+// Failing to persist the cache is logged, as it only slows down a restart.
+func (p *machinePersister) save(c *MachineCache) {
+	if err := p.trySave(c); err != nil {
+		log.Warn("failed to persist machine cache", "dir", p.dir, "err", err)
+	}
+}
+
+func (p *machinePersister) trySave(c *MachineCache) error {
+	if err := os.MkdirAll(p.dir, 0o755); err != nil {
+		return err
+	}
+	manifest := persistedCache{
+		StartHash:           c.zeroStepMachine.Hash(),
+		FirstMachineStep:    c.firstMachineStep,
+		MachineStepInterval: c.machineStepInterval,
+		FinalMachineStep:    c.finalMachineStep,
+	}
+	keep := make(map[uint64]bool)
+	for _, machine := range append(append([]MachineInterface{}, c.machines...), c.finalMachine) {
+		step := machine.GetStepCount()
+		if machine != c.finalMachine {
+			manifest.MachineSteps = append(manifest.MachineSteps, step)
+		}
+		keep[step] = true
+		if p.persisted[step] {
+			continue
+		}
+		serializable, ok := machine.(SerializableMachine)
+		if !ok {
+			return errors.New("machine can't be serialized")
+		}
+		path := p.machinePath(step)
+		if err := serializable.SerializeState(path + ".tmp"); err != nil {
+			return err
+		}
+		if err := os.Rename(path+".tmp", path); err != nil {
+			return err
+		}
+		p.persisted[step] = true
+	}
+	data, err := json.Marshal(manifest)
+	if err != nil {
+		return err
+	}
+	manifestPath := filepath.Join(p.dir, persistedCacheManifest)
+	if err := os.WriteFile(manifestPath+".tmp", data, 0o600); err != nil {
+		return err
+	}
+	if err := os.Rename(manifestPath+".tmp", manifestPath); err != nil {
+		return err
+	}
+	for step := range p.persisted {
+		if keep[step] {
+			continue
+		}
+		if err := os.Remove(p.machinePath(step)); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+		delete(p.persisted, step)
+	}
+	return nil
+}
@@ -256,7 +256,7 @@ func (v *ArbitratorSpawner) CreateExecutionRun(wasmModuleRoot common.Hash, input
 	}
 	currentExecConfig := v.config().Execution
 	return stopwaiter.LaunchPromiseThread[validator.ExecutionRun](v, func(ctx context.Context) (validator.ExecutionRun, error) {
-		return NewExecutionRun(v.GetContext(), getMachine, &currentExecConfig)
+		return NewExecutionRun(v.GetContext(), getMachine, WithConfig(&currentExecConfig), WithPersistenceKey(wasmModuleRoot, input.Id))
 	})
 }
 
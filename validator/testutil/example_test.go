@@ -0,0 +1,82 @@
+// Copyright 2024, Offchain Labs, Inc.
+// For license information, see https://github.com/nitro/blob/master/LICENSE
+
+package testutil_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/offchainlabs/nitro/validator"
+	"github.com/offchainlabs/nitro/validator/testutil"
+)
+
+// hashAt steps a clone of the machine to the step, leaving the machine itself where it is.
+func hashAt(ctx context.Context, machine validator.MachineInterface, step uint64) (common.Hash, error) {
+	clone := machine.CloneMachineInterface()
+	defer clone.Destroy()
+	if err := clone.Step(ctx, step); err != nil {
+		return common.Hash{}, err
+	}
+	return clone.Hash(), nil
+}
+
+// Bisect over an honest and a dishonest machine down to the single step they disagree on,
+// then have the honest machine prove that step.
+func Example_bisection() {
+	ctx := context.Background()
+	honest := testutil.NewMockMachineBuilder(1000).WithBatch(1).Build()
+	dishonest := testutil.NewMockMachineBuilder(1000).WithBatch(1).WithDivergenceAt(677).Build()
+	honest.Freeze()
+	dishonest.Freeze()
+
+	agreed, disagreed := uint64(0), uint64(1000)
+	rounds := 0
+	for disagreed-agreed > 1 {
+		middle := agreed + (disagreed-agreed)/2
+		honestHash, err := hashAt(ctx, honest, middle)
+		if err != nil {
+			panic(err)
+		}
+		dishonestHash, err := hashAt(ctx, dishonest, middle)
+		if err != nil {
+			panic(err)
+		}
+		if honestHash == dishonestHash {
+			agreed = middle
+		} else {
+			disagreed = middle
+		}
+		rounds++
+	}
+	fmt.Println("disagreed from step", disagreed, "after", rounds, "rounds")
+
+	prover := honest.CloneMachineInterface()
+	defer prover.Destroy()
+	if err := prover.Step(ctx, agreed); err != nil {
+		panic(err)
+	}
+	var proof bytes.Buffer
+	if _, err := prover.ProveNextStep(&proof); err != nil {
+		panic(err)
+	}
+	fmt.Println("proved the step after", binary.BigEndian.Uint64(proof.Bytes()))
+	// Output:
+	// disagreed from step 677 after 10 rounds
+	// proved the step after 676
+}
+
+// Script a machine that errors partway through its execution.
+func ExampleMockMachineBuilder_WithError() {
+	machine := testutil.NewMockMachineBuilder(50).WithError(validator.MachineErrorContext{ModuleName: "user", PC: 42}).Build()
+	if err := machine.Step(context.Background(), 100); err != nil {
+		panic(err)
+	}
+	fmt.Println("errored:", machine.IsErrored(), "at step", machine.GetStepCount(), "pc", machine.GetErrorContext().PC)
+	// Output:
+	// errored: true at step 50 pc 42
+}
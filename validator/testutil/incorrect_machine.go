@@ -1,31 +1,32 @@
-// Copyright 2021-2022, Offchain Labs, Inc.
+// Copyright 2021-2024, Offchain Labs, Inc.
 // For license information, see https://github.com/nitro/blob/master/LICENSE
 
-package arbtest
+package testutil
 
 import (
 	"github.com/ethereum/go-ethereum/common"
 
-	"github.com/offchainlabs/nitro/validator/server_arb"
+	"github.com/offchainlabs/nitro/validator"
 )
 
 // IncorrectIntermediateMachine will report an incorrect hash while running from incorrectStep onwards.
 // However, it'll reach the correct final hash and global state once finished.
+// It can wrap any machine, such as to have a real arbitrator machine play the dishonest side of a challenge.
 type IncorrectIntermediateMachine struct {
-	server_arb.MachineInterface
+	validator.MachineInterface
 	incorrectStep uint64
 }
 
-var _ server_arb.MachineInterface = (*IncorrectIntermediateMachine)(nil)
+var _ validator.MachineInterface = (*IncorrectIntermediateMachine)(nil)
 
-func NewIncorrectIntermediateMachine(inner server_arb.MachineInterface, incorrectStep uint64) *IncorrectIntermediateMachine {
+func NewIncorrectIntermediateMachine(inner validator.MachineInterface, incorrectStep uint64) *IncorrectIntermediateMachine {
 	return &IncorrectIntermediateMachine{
 		MachineInterface: inner,
 		incorrectStep:    incorrectStep,
 	}
 }
 
-func (m *IncorrectIntermediateMachine) CloneMachineInterface() server_arb.MachineInterface {
+func (m *IncorrectIntermediateMachine) CloneMachineInterface() validator.MachineInterface {
 	return &IncorrectIntermediateMachine{
 		MachineInterface: m.MachineInterface.CloneMachineInterface(),
 		incorrectStep:    m.incorrectStep,
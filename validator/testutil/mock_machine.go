@@ -0,0 +1,283 @@
+// Copyright 2024, Offchain Labs, Inc.
+// For license information, see https://github.com/nitro/blob/master/LICENSE
+
+// Package testutil provides a mock validator.MachineInterface, so code driving machines,
+// like challenge bisection, can be tested without cgo or an arbitrator build.
+package testutil
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"io"
+	"os"
+	"sync/atomic"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+
+	"github.com/offchainlabs/nitro/validator"
+)
+
+// StepHook is called before a MockMachine at position takes stepSize steps.
+// An error fails the Step, leaving the machine where it was.
+type StepHook func(ctx context.Context, position, stepSize uint64) error
+
+type mockMachineScript struct {
+	haltStep     uint64
+	errorContext *validator.MachineErrorContext
+	globalStates func(step uint64) validator.GoGlobalState
+	hashes       func(step uint64) common.Hash
+	proofs       func(step uint64) []byte
+	stepErrorAt  uint64
+	stepError    error
+	stepHook     StepHook
+	stepsTaken   *atomic.Uint64
+}
+
+// MockMachineBuilder scripts how the machines it builds behave.
+type MockMachineBuilder struct {
+	script     mockMachineScript
+	divergence *uint64
+}
+
+// NewMockMachineBuilder starts scripting a machine that runs until it finishes at haltStep.
+// By default its global state is at batch 0 with the step as the position in the batch,
+// its hash is that of its global state, and its proof of the next step is the step as 8 big endian bytes.
+func NewMockMachineBuilder(haltStep uint64) *MockMachineBuilder {
+	return &MockMachineBuilder{
+		script: mockMachineScript{haltStep: haltStep},
+	}
+}
+
+// WithBatch has the machine's global state be at the batch, with the step as the position in it.
+func (b *MockMachineBuilder) WithBatch(batch uint64) *MockMachineBuilder {
+	b.script.globalStates = func(step uint64) validator.GoGlobalState {
+		return validator.GoGlobalState{Batch: batch, PosInBatch: step}
+	}
+	return b
+}
+
+// WithGlobalStates scripts the machine's global state at each step.
+func (b *MockMachineBuilder) WithGlobalStates(globalStates func(step uint64) validator.GoGlobalState) *MockMachineBuilder {
+	b.script.globalStates = globalStates
+	return b
+}
+
+// WithHashes scripts the machine's hash at each step, instead of hashing its global state.
+func (b *MockMachineBuilder) WithHashes(hashes func(step uint64) common.Hash) *MockMachineBuilder {
+	b.script.hashes = hashes
+	return b
+}
+
+// WithDivergenceAt has the machine's hash differ from that of an otherwise identical machine from the step on,
+// like a dishonest machine does in a challenge.
+func (b *MockMachineBuilder) WithDivergenceAt(step uint64) *MockMachineBuilder {
+	b.divergence = &step
+	return b
+}
+
+// WithProofs scripts the machine's proof of the step after each step.
+func (b *MockMachineBuilder) WithProofs(proofs func(step uint64) []byte) *MockMachineBuilder {
+	b.script.proofs = proofs
+	return b
+}
+
+// WithError has the machine error at its halt step rather than finish, reporting errorContext as where it did.
+func (b *MockMachineBuilder) WithError(errorContext validator.MachineErrorContext) *MockMachineBuilder {
+	b.script.errorContext = &errorContext
+	return b
+}
+
+// WithStepErrorAt fails stepping the machine past the step with err, leaving it at the step.
+func (b *MockMachineBuilder) WithStepErrorAt(step uint64, err error) *MockMachineBuilder {
+	b.script.stepErrorAt = step
+	b.script.stepError = err
+	return b
+}
+
+// WithStepHook calls hook whenever the machine or any of its clones steps, such as to block or fail stepping.
+func (b *MockMachineBuilder) WithStepHook(hook StepHook) *MockMachineBuilder {
+	b.script.stepHook = hook
+	return b
+}
+
+// Build makes a machine at step 0. Changing the builder afterwards doesn't change the machine.
+func (b *MockMachineBuilder) Build() *MockMachine {
+	script := b.script
+	if script.globalStates == nil {
+		script.globalStates = func(step uint64) validator.GoGlobalState {
+			return validator.GoGlobalState{PosInBatch: step}
+		}
+	}
+	if script.hashes == nil {
+		globalStates := script.globalStates
+		script.hashes = func(step uint64) common.Hash {
+			return globalStates(step).Hash()
+		}
+	}
+	if b.divergence != nil {
+		divergence, hashes := *b.divergence, script.hashes
+		script.hashes = func(step uint64) common.Hash {
+			hash := hashes(step)
+			if step < divergence {
+				return hash
+			}
+			return crypto.Keccak256Hash([]byte("diverged:"), hash.Bytes())
+		}
+	}
+	if script.proofs == nil {
+		script.proofs = func(step uint64) []byte {
+			return binary.BigEndian.AppendUint64(nil, step)
+		}
+	}
+	script.stepsTaken = &atomic.Uint64{}
+	return &MockMachine{script: &script}
+}
+
+// MockMachine is a validator.MachineInterface following a script from a MockMachineBuilder.
+// Like an arbitrator machine, it can't be stepped once frozen, though its clones can,
+// and it isn't safe to use from multiple goroutines at once.
+type MockMachine struct {
+	script    *mockMachineScript
+	step      uint64
+	frozen    bool
+	destroyed atomic.Bool
+}
+
+var _ validator.MachineInterface = (*MockMachine)(nil)
+
+// Clone is CloneMachineInterface without losing the machine's type.
+// Even if the machine is frozen, its clone isn't.
+func (m *MockMachine) Clone() *MockMachine {
+	return &MockMachine{
+		script: m.script,
+		step:   m.step,
+	}
+}
+
+func (m *MockMachine) CloneMachineInterface() validator.MachineInterface {
+	return m.Clone()
+}
+
+func (m *MockMachine) GetStepCount() uint64 {
+	return m.step
+}
+
+func (m *MockMachine) IsRunning() bool {
+	return m.step < m.script.haltStep
+}
+
+func (m *MockMachine) IsErrored() bool {
+	return !m.IsRunning() && m.script.errorContext != nil
+}
+
+func (m *MockMachine) ValidForStep(requestedStep uint64) bool {
+	if m.step > requestedStep {
+		return false
+	}
+	// if the machine is halted, its state persists for future steps
+	return m.step == requestedStep || !m.IsRunning()
+}
+
+func (m *MockMachine) Status() uint8 {
+	if m.IsRunning() {
+		return uint8(validator.MachineStatusRunning)
+	}
+	if m.IsErrored() {
+		return uint8(validator.MachineStatusErrored)
+	}
+	return uint8(validator.MachineStatusFinished)
+}
+
+// Step steps the machine count steps, stopping early if it halts.
+func (m *MockMachine) Step(ctx context.Context, count uint64) error {
+	if m.destroyed.Load() {
+		return errors.New("machine destroyed")
+	}
+	if m.frozen {
+		return errors.New("machine frozen")
+	}
+	if m.script.stepHook != nil {
+		if err := m.script.stepHook(ctx, m.step, count); err != nil {
+			return err
+		}
+	}
+	target := m.script.haltStep
+	if count < target-m.step {
+		target = m.step + count
+	}
+	var err error
+	if m.script.stepError != nil && m.step <= m.script.stepErrorAt && m.script.stepErrorAt < target {
+		target = m.script.stepErrorAt
+		err = m.script.stepError
+	}
+	m.script.stepsTaken.Add(target - m.step)
+	m.step = target
+	return err
+}
+
+func (m *MockMachine) Hash() common.Hash {
+	return m.script.hashes(m.step)
+}
+
+func (m *MockMachine) GetGlobalState() validator.GoGlobalState {
+	return m.script.globalStates(m.step)
+}
+
+func (m *MockMachine) ProveNextStep(w io.Writer) (int, error) {
+	return w.Write(m.script.proofs(m.step))
+}
+
+func (m *MockMachine) GetErrorContext() *validator.MachineErrorContext {
+	if !m.IsErrored() {
+		return nil
+	}
+	errorContext := *m.script.errorContext
+	return &errorContext
+}
+
+func (m *MockMachine) Freeze() {
+	m.frozen = true
+}
+
+func (m *MockMachine) Destroy() {
+	m.destroyed.Store(true)
+}
+
+var mockMachineStateHeader = []byte("mock machine state:")
+
+// SerializeState writes the machine's step to the file at path, as its script can't be serialized.
+func (m *MockMachine) SerializeState(path string) error {
+	if m.destroyed.Load() {
+		return errors.New("machine destroyed")
+	}
+	return os.WriteFile(path, binary.BigEndian.AppendUint64(append([]byte{}, mockMachineStateHeader...), m.step), 0o600)
+}
+
+// DeserializeAndReplaceState moves the machine to the step serialized to the file at path, without taking any steps.
+func (m *MockMachine) DeserializeAndReplaceState(path string) error {
+	if m.frozen {
+		return errors.New("machine frozen")
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	if len(data) != len(mockMachineStateHeader)+8 || !bytes.HasPrefix(data, mockMachineStateHeader) {
+		return errors.New("invalid mock machine state")
+	}
+	m.step = binary.BigEndian.Uint64(data[len(mockMachineStateHeader):])
+	return nil
+}
+
+// Destroyed is whether the machine has been destroyed.
+func (m *MockMachine) Destroyed() bool {
+	return m.destroyed.Load()
+}
+
+// StepsTaken counts the steps taken by the machine and every machine cloned from it, directly or not.
+func (m *MockMachine) StepsTaken() uint64 {
+	return m.script.stepsTaken.Load()
+}
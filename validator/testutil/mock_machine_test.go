@@ -0,0 +1,148 @@
+// Copyright 2024, Offchain Labs, Inc.
+// For license information, see https://github.com/nitro/blob/master/LICENSE
+
+package testutil
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/offchainlabs/nitro/validator"
+)
+
+func TestMockMachineHalts(t *testing.T) {
+	ctx := context.Background()
+	machine := NewMockMachineBuilder(20).WithBatch(3).Build()
+	if err := machine.Step(ctx, 5); err != nil {
+		t.Fatal(err)
+	}
+	if !machine.IsRunning() || machine.GetStepCount() != 5 || machine.Status() != uint8(validator.MachineStatusRunning) {
+		t.Fatalf("Wanted running machine at step 5, got step %d with status %d", machine.GetStepCount(), machine.Status())
+	}
+	want := validator.GoGlobalState{Batch: 3, PosInBatch: 5}
+	if machine.GetGlobalState() != want || machine.Hash() != want.Hash() {
+		t.Errorf("Wanted global state %+v, got %+v", want, machine.GetGlobalState())
+	}
+	if machine.ValidForStep(6) || !machine.ValidForStep(5) {
+		t.Error("Wanted running machine to only be valid for its own step")
+	}
+
+	if err := machine.Step(ctx, 100); err != nil {
+		t.Fatal(err)
+	}
+	if machine.IsRunning() || machine.GetStepCount() != 20 || machine.Status() != uint8(validator.MachineStatusFinished) {
+		t.Fatalf("Wanted finished machine at step 20, got step %d with status %d", machine.GetStepCount(), machine.Status())
+	}
+	if !machine.ValidForStep(1000) || machine.ValidForStep(19) {
+		t.Error("Wanted halted machine to be valid for later steps")
+	}
+	if machine.IsErrored() || machine.GetErrorContext() != nil {
+		t.Error("Wanted finished machine not to have errored")
+	}
+	if steps := machine.StepsTaken(); steps != 20 {
+		t.Errorf("Wanted 20 steps taken, got %d", steps)
+	}
+}
+
+func TestMockMachineErrors(t *testing.T) {
+	errorContext := validator.MachineErrorContext{ModuleIndex: 1, ModuleName: "user", FunctionIndex: 2, PC: 3}
+	machine := NewMockMachineBuilder(10).WithError(errorContext).Build()
+	if machine.GetErrorContext() != nil {
+		t.Error("Wanted no error context before the machine errored")
+	}
+	if err := machine.Step(context.Background(), 11); err != nil {
+		t.Fatal(err)
+	}
+	if !machine.IsErrored() || machine.Status() != uint8(validator.MachineStatusErrored) {
+		t.Fatalf("Wanted errored machine, got status %d", machine.Status())
+	}
+	if got := machine.GetErrorContext(); got == nil || *got != errorContext {
+		t.Errorf("Wanted error context %+v, got %+v", errorContext, got)
+	}
+}
+
+func TestMockMachineClonesAndFreezing(t *testing.T) {
+	ctx := context.Background()
+	machine := NewMockMachineBuilder(100).Build()
+	machine.Freeze()
+	if err := machine.Step(ctx, 1); err == nil {
+		t.Error("Wanted stepping a frozen machine to fail")
+	}
+	clone := machine.Clone()
+	if err := clone.Step(ctx, 30); err != nil {
+		t.Fatal(err)
+	}
+	if machine.GetStepCount() != 0 || clone.GetStepCount() != 30 {
+		t.Errorf("Wanted stepping the clone to leave the machine alone, got steps %d and %d", machine.GetStepCount(), clone.GetStepCount())
+	}
+	if machine.StepsTaken() != 30 {
+		t.Errorf("Wanted the clone's steps to be counted, got %d", machine.StepsTaken())
+	}
+	clone.Destroy()
+	if !clone.Destroyed() || machine.Destroyed() {
+		t.Error("Wanted only the clone to be destroyed")
+	}
+	if err := clone.Step(ctx, 1); err == nil {
+		t.Error("Wanted stepping a destroyed machine to fail")
+	}
+}
+
+func TestMockMachineScriptedFailures(t *testing.T) {
+	ctx := context.Background()
+	injected := errors.New("injected")
+	blocked := errors.New("blocked")
+	var hookCalls int
+	machine := NewMockMachineBuilder(100).
+		WithStepErrorAt(40, injected).
+		WithStepHook(func(ctx context.Context, position, stepSize uint64) error {
+			hookCalls++
+			if position == 0 && stepSize == 7 {
+				return blocked
+			}
+			return nil
+		}).
+		Build()
+
+	if err := machine.Step(ctx, 7); !errors.Is(err, blocked) || machine.GetStepCount() != 0 {
+		t.Errorf("Wanted the hook to fail stepping without moving, got %v at step %d", err, machine.GetStepCount())
+	}
+	if err := machine.Step(ctx, 40); err != nil {
+		t.Fatalf("Wanted stepping up to the failing step to succeed, got %v", err)
+	}
+	if err := machine.Step(ctx, 10); !errors.Is(err, injected) || machine.GetStepCount() != 40 {
+		t.Errorf("Wanted stepping past step 40 to fail there, got %v at step %d", err, machine.GetStepCount())
+	}
+	if hookCalls != 3 {
+		t.Errorf("Wanted the hook to be called for every step, got %d calls", hookCalls)
+	}
+
+	honest := NewMockMachineBuilder(100).Build()
+	dishonest := NewMockMachineBuilder(100).WithDivergenceAt(60).Build()
+	for _, step := range []uint64{0, 59, 60, 100} {
+		honestClone, dishonestClone := honest.Clone(), dishonest.Clone()
+		if err := errors.Join(honestClone.Step(ctx, step), dishonestClone.Step(ctx, step)); err != nil {
+			t.Fatal(err)
+		}
+		if agree := honestClone.Hash() == dishonestClone.Hash(); agree != (step < 60) {
+			t.Errorf("Wanted machines to agree at step %d only before step 60", step)
+		}
+	}
+}
+
+func TestIncorrectIntermediateMachine(t *testing.T) {
+	ctx := context.Background()
+	correct := NewMockMachineBuilder(10).Build()
+	incorrect := NewIncorrectIntermediateMachine(NewMockMachineBuilder(10).Build(), 4)
+	for _, step := range []uint64{0, 3, 4, 9, 10} {
+		correctClone, incorrectClone := correct.Clone(), incorrect.CloneMachineInterface()
+		if err := errors.Join(correctClone.Step(ctx, step), incorrectClone.Step(ctx, step)); err != nil {
+			t.Fatal(err)
+		}
+		// the machines only disagree while the incorrect one is running from step 4
+		wantAgree := step < 4 || step == 10
+		if agree := correctClone.Hash() == incorrectClone.Hash(); agree != wantAgree {
+			t.Errorf("Wanted machines agreeing to be %v at step %d", wantAgree, step)
+		}
+	}
+}
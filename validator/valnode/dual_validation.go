@@ -0,0 +1,309 @@
+// Copyright 2024, Offchain Labs, Inc.
+// For license information, see https://github.com/OffchainLabs/nitro/blob/master/LICENSE
+
+package valnode
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/spf13/pflag"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/metrics"
+
+	"github.com/offchainlabs/nitro/util/stopwaiter"
+	"github.com/offchainlabs/nitro/validator"
+	"github.com/offchainlabs/nitro/validator/inputs"
+	"github.com/offchainlabs/nitro/validator/server_api"
+)
+
+var (
+	dualValidationComparedCounter  = metrics.NewRegisteredCounter("arb/validator/dual/compared", nil)
+	dualValidationDisagreedCounter = metrics.NewRegisteredCounter("arb/validator/dual/disagreed", nil)
+	dualValidationFailedCounter    = metrics.NewRegisteredCounter("arb/validator/dual/failed", nil)
+	dualValidationAgreementGauge   = metrics.NewRegisteredGaugeFloat64("arb/validator/dual/agreement", nil)
+	dualValidationJitDurationHist  = metrics.NewRegisteredHistogram("arb/validator/dual/jit/duration", nil, metrics.NewBoundedHistogramSample())
+	dualValidationArbDurationHist  = metrics.NewRegisteredHistogram("arb/validator/dual/arbitrator/duration", nil, metrics.NewBoundedHistogramSample())
+	dualValidationSpeedupHist      = metrics.NewRegisteredHistogram("arb/validator/dual/speedup", nil, metrics.NewBoundedHistogramSample())
+)
+
+const (
+	DualValidationRecordsFile   = "records.jsonl"
+	DualValidationSummaryFile   = "summary.json"
+	DualValidationInputsSubdir  = "disagreements"
+	dualValidationSpeedupWindow = 4096
+)
+
+type DualValidationConfig struct {
+	Enable     bool    `koanf:"enable"`
+	SampleRate float64 `koanf:"sample-rate" reload:"hot"`
+	ReportDir  string  `koanf:"report-dir"`
+}
+
+var DefaultDualValidationConfig = DualValidationConfig{
+	Enable:     false,
+	SampleRate: 0.01,
+	ReportDir:  "",
+}
+
+func DualValidationConfigAddOptions(prefix string, f *pflag.FlagSet) {
+	f.Bool(prefix+".enable", DefaultDualValidationConfig.Enable, "also run a sample of jit validations through the arbitrator, comparing their results and timing")
+	f.Float64(prefix+".sample-rate", DefaultDualValidationConfig.SampleRate, "fraction of validations to run through both jit and arbitrator")
+	f.String(prefix+".report-dir", DefaultDualValidationConfig.ReportDir, "directory to write the dual validation report and the inputs of disagreeing validations to (defaults to ~/.arbitrum/dual-validation)")
+}
+
+func (c *DualValidationConfig) Validate() error {
+	if c.SampleRate < 0 || c.SampleRate > 1 {
+		return fmt.Errorf("dual validation sample rate %v is not between 0 and 1", c.SampleRate)
+	}
+	return nil
+}
+
+// DualValidationRecord is the outcome of running a single validation through both jit and arbitrator.
+type DualValidationRecord struct {
+	Id               uint64                  `json:"id"`
+	ModuleRoot       common.Hash             `json:"moduleRoot"`
+	Agree            bool                    `json:"agree"`
+	JitEnd           validator.GoGlobalState `json:"jitEnd"`
+	ArbitratorEnd    validator.GoGlobalState `json:"arbitratorEnd"`
+	JitMillis        int64                   `json:"jitMillis"`
+	ArbitratorMillis int64                   `json:"arbitratorMillis"`
+	Speedup          float64                 `json:"speedup"`
+	InputPath        string                  `json:"inputPath,omitempty"`
+}
+
+// DualValidationSummary aggregates the records of all validations compared so far.
+// Speedup is how many times faster jit was than the arbitrator, over the most recent comparisons.
+type DualValidationSummary struct {
+	Compared      uint64  `json:"compared"`
+	Agreed        uint64  `json:"agreed"`
+	Disagreed     uint64  `json:"disagreed"`
+	Failed        uint64  `json:"failed"`
+	AgreementRate float64 `json:"agreementRate"`
+	SpeedupP50    float64 `json:"speedupP50"`
+	SpeedupP90    float64 `json:"speedupP90"`
+	SpeedupP99    float64 `json:"speedupP99"`
+}
+
+// DualSpawner validates with jit, and runs a sample of validations through the arbitrator as well.
+// Results always come from jit; the arbitrator's are only compared against them and reported.
+type DualSpawner struct {
+	stopwaiter.StopWaiter
+	jit         validator.ValidationSpawner
+	arbitrator  validator.ValidationSpawner
+	config      func() *DualValidationConfig
+	reportDir   string
+	inputWriter *inputs.Writer
+
+	reportMutex sync.Mutex
+	summary     DualValidationSummary
+	speedups    []float64
+}
+
+func NewDualSpawner(jit, arbitrator validator.ValidationSpawner, config func() *DualValidationConfig) (*DualSpawner, error) {
+	if err := config().Validate(); err != nil {
+		return nil, err
+	}
+	reportDir := config().ReportDir
+	if reportDir == "" {
+		homeDir, err := os.UserHomeDir()
+		if err != nil {
+			return nil, err
+		}
+		reportDir = filepath.Join(homeDir, ".arbitrum", "dual-validation")
+	}
+	if err := os.MkdirAll(reportDir, 0700); err != nil {
+		return nil, err
+	}
+	inputWriter, err := inputs.NewWriter(
+		inputs.WithBaseDir(reportDir),
+		inputs.WithSlug(DualValidationInputsSubdir),
+		inputs.WithTimestampDirEnabled(false),
+	)
+	if err != nil {
+		return nil, err
+	}
+	dual := &DualSpawner{
+		jit:         jit,
+		arbitrator:  arbitrator,
+		config:      config,
+		reportDir:   reportDir,
+		inputWriter: inputWriter,
+	}
+	// keep counting from where a previous run left off
+	summary, err := os.ReadFile(filepath.Join(reportDir, DualValidationSummaryFile))
+	if err == nil {
+		err = json.Unmarshal(summary, &dual.summary)
+	}
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("failed to read dual validation summary: %w", err)
+	}
+	return dual, nil
+}
+
+func (d *DualSpawner) Start(ctx context.Context) error {
+	d.StopWaiter.Start(ctx, d)
+	return nil
+}
+
+func (d *DualSpawner) Stop() {
+	d.StopAndWait()
+}
+
+func (d *DualSpawner) Name() string {
+	return d.jit.Name()
+}
+
+func (d *DualSpawner) Room() int {
+	return d.jit.Room()
+}
+
+func (d *DualSpawner) WasmModuleRoots() ([]common.Hash, error) {
+	return d.jit.WasmModuleRoots()
+}
+
+// StylusArchs asks for the programs of both engines, since either may run a validation.
+func (d *DualSpawner) StylusArchs() []ethdb.WasmTarget {
+	archs := slices.Clone(d.jit.StylusArchs())
+	for _, arch := range d.arbitrator.StylusArchs() {
+		if !slices.Contains(archs, arch) {
+			archs = append(archs, arch)
+		}
+	}
+	return archs
+}
+
+func (d *DualSpawner) Launch(entry *validator.ValidationInput, moduleRoot common.Hash) validator.ValidationRun {
+	start := time.Now()
+	jitRun := d.jit.Launch(entry, moduleRoot)
+	// #nosec G404
+	if rand.Float64() >= d.config().SampleRate {
+		return jitRun
+	}
+	arbitratorRun := d.arbitrator.Launch(entry, moduleRoot)
+	d.LaunchThread(func(ctx context.Context) {
+		d.compare(ctx, entry, moduleRoot, start, jitRun, arbitratorRun)
+	})
+	return jitRun
+}
+
+func (d *DualSpawner) compare(ctx context.Context, entry *validator.ValidationInput, moduleRoot common.Hash, start time.Time, jitRun, arbitratorRun validator.ValidationRun) {
+	// wait for both at once, so that each is timed when it finishes
+	var jitTime, arbitratorTime time.Duration
+	jitReady, arbitratorReady := jitRun.ReadyChan(), arbitratorRun.ReadyChan()
+	for jitReady != nil || arbitratorReady != nil {
+		select {
+		case <-jitReady:
+			jitTime = time.Since(start)
+			jitReady = nil
+		case <-arbitratorReady:
+			arbitratorTime = time.Since(start)
+			arbitratorReady = nil
+		case <-ctx.Done():
+			arbitratorRun.Cancel()
+			return
+		}
+	}
+	jitEnd, jitErr := jitRun.Current()
+	arbitratorEnd, arbitratorErr := arbitratorRun.Current()
+	if jitErr != nil || arbitratorErr != nil {
+		// an error is more likely to be a cancelled or timed out run than a disagreement
+		log.Warn("dual validation failed", "id", entry.Id, "jitErr", jitErr, "arbitratorErr", arbitratorErr)
+		dualValidationFailedCounter.Inc(1)
+		d.reportMutex.Lock()
+		d.summary.Failed++
+		d.reportMutex.Unlock()
+		return
+	}
+
+	record := DualValidationRecord{
+		Id:               entry.Id,
+		ModuleRoot:       moduleRoot,
+		Agree:            jitEnd == arbitratorEnd,
+		JitEnd:           jitEnd,
+		ArbitratorEnd:    arbitratorEnd,
+		JitMillis:        jitTime.Milliseconds(),
+		ArbitratorMillis: arbitratorTime.Milliseconds(),
+		Speedup:          float64(arbitratorTime) / float64(max(jitTime, time.Nanosecond)),
+	}
+	dualValidationJitDurationHist.Update(record.JitMillis)
+	dualValidationArbDurationHist.Update(record.ArbitratorMillis)
+	dualValidationSpeedupHist.Update(int64(record.Speedup * 100))
+	if !record.Agree {
+		log.Error("jit and arbitrator validations disagree", "id", entry.Id, "moduleRoot", moduleRoot, "jit", jitEnd, "arbitrator", arbitratorEnd)
+		dualValidationDisagreedCounter.Inc(1)
+		if err := d.inputWriter.Write(server_api.ValidationInputToJson(entry)); err != nil {
+			log.Error("failed to capture input of disagreeing validation", "id", entry.Id, "err", err)
+		} else {
+			record.InputPath = filepath.Join(d.reportDir, DualValidationInputsSubdir, fmt.Sprintf("block_inputs_%d.json", entry.Id))
+		}
+	}
+	dualValidationComparedCounter.Inc(1)
+	if err := d.report(&record); err != nil {
+		log.Warn("failed to write dual validation report", "dir", d.reportDir, "err", err)
+	}
+}
+
+// report adds the record to the report and rewrites the summary
+func (d *DualSpawner) report(record *DualValidationRecord) error {
+	d.reportMutex.Lock()
+	defer d.reportMutex.Unlock()
+
+	d.summary.Compared++
+	if record.Agree {
+		d.summary.Agreed++
+	} else {
+		d.summary.Disagreed++
+	}
+	d.summary.AgreementRate = float64(d.summary.Agreed) / float64(d.summary.Compared)
+	dualValidationAgreementGauge.Update(d.summary.AgreementRate)
+	d.speedups = append(d.speedups, record.Speedup)
+	if len(d.speedups) > dualValidationSpeedupWindow {
+		d.speedups = d.speedups[len(d.speedups)-dualValidationSpeedupWindow:]
+	}
+	sorted := slices.Clone(d.speedups)
+	slices.Sort(sorted)
+	percentile := func(p float64) float64 {
+		return sorted[int(p*float64(len(sorted)-1))]
+	}
+	d.summary.SpeedupP50 = percentile(0.5)
+	d.summary.SpeedupP90 = percentile(0.9)
+	d.summary.SpeedupP99 = percentile(0.99)
+
+	line, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+	records, err := os.OpenFile(filepath.Join(d.reportDir, DualValidationRecordsFile), os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+	_, err = records.Write(append(line, '\n'))
+	err = errors.Join(err, records.Close())
+	if err != nil {
+		return err
+	}
+	summary, err := json.MarshalIndent(&d.summary, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(d.reportDir, DualValidationSummaryFile), summary, 0600)
+}
+
+// Summary returns the aggregate of all validations compared so far.
+func (d *DualSpawner) Summary() DualValidationSummary {
+	d.reportMutex.Lock()
+	defer d.reportMutex.Unlock()
+	return d.summary
+}
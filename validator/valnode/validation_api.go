@@ -183,6 +183,19 @@ func (a *ExecServerAPI) PrepareRange(ctx context.Context, execid uint64, start,
 	return err
 }
 
+func (a *ExecServerAPI) GetExecutionRunStatus(ctx context.Context, execid uint64) (*validator.ExecutionRunStatus, error) {
+	run, err := a.getRun(execid)
+	if err != nil {
+		return nil, err
+	}
+	reporter, ok := run.(validator.ExecutionRunStatusReporter)
+	if !ok {
+		return nil, errors.New("run doesn't report its status")
+	}
+	status := reporter.Status()
+	return &status, nil
+}
+
 func (a *ExecServerAPI) ExecKeepAlive(ctx context.Context, execid uint64) error {
 	_, err := a.getRun(execid)
 	if err != nil {
@@ -0,0 +1,51 @@
+package valnode
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/offchainlabs/nitro/validator/server_arb"
+	"github.com/offchainlabs/nitro/validator/testutil"
+)
+
+func TestGetExecutionRunStatus(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	run, err := server_arb.NewExecutionRun(ctx, func(_ context.Context) (server_arb.MachineInterface, error) {
+		return testutil.NewMockMachineBuilder(99).WithBatch(1).Build(), nil
+	}, server_arb.WithInitialSteps(10))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer run.Close()
+	config := func() *server_arb.ArbitratorSpawnerConfig { return &server_arb.DefaultArbitratorSpawnerConfig }
+	api := NewExecutionServerAPI(nil, nil, config)
+	const runId = 1
+	api.runs[runId] = &execRunEntry{run, time.Now()}
+
+	for _, position := range []uint64{5, 25, 45} {
+		if _, err := api.GetStepAt(ctx, runId, position); err != nil {
+			t.Fatal(err)
+		}
+	}
+	status, err := api.GetExecutionRunStatus(ctx, runId)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if status.CachedMachines == 0 || status.CacheEnd < status.CacheStart {
+		t.Errorf("Wanted a cached range, got %d machines from step %d to %d", status.CachedMachines, status.CacheStart, status.CacheEnd)
+	}
+	// building the cache alone steps past every position queried
+	if status.StepsExecuted < 45 {
+		t.Errorf("Wanted at least 45 steps executed, got %d", status.StepsExecuted)
+	}
+	if status.StepsPerSecond <= 0 {
+		t.Errorf("Wanted a positive step rate, got %v", status.StepsPerSecond)
+	}
+
+	if _, err := api.GetExecutionRunStatus(ctx, runId+1); !errors.Is(err, errRunNotFound) {
+		t.Errorf("Wanted the status of an unknown run to fail with %v, got %v", errRunNotFound, err)
+	}
+}
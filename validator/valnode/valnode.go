@@ -2,6 +2,7 @@ package valnode
 
 import (
 	"context"
+	"errors"
 
 	"github.com/spf13/pflag"
 
@@ -42,6 +43,8 @@ type Config struct {
 	Arbitrator server_arb.ArbitratorSpawnerConfig `koanf:"arbitrator" reload:"hot"`
 	Jit        server_jit.JitSpawnerConfig        `koanf:"jit" reload:"hot"`
 	Wasm       WasmConfig                         `koanf:"wasm"`
+	// DualValidation compares jit against the arbitrator, and requires UseJit
+	DualValidation DualValidationConfig `koanf:"dual-validation"`
 }
 
 type ValidationConfigFetcher func() *Config
@@ -53,6 +56,8 @@ var DefaultValidationConfig = Config{
 	ApiPublic:  false,
 	Arbitrator: server_arb.DefaultArbitratorSpawnerConfig,
 	Wasm:       DefaultWasmConfig,
+
+	DualValidation: DefaultDualValidationConfig,
 }
 
 var TestValidationConfig = Config{
@@ -62,6 +67,8 @@ var TestValidationConfig = Config{
 	ApiPublic:  true,
 	Arbitrator: server_arb.DefaultArbitratorSpawnerConfig,
 	Wasm:       DefaultWasmConfig,
+
+	DualValidation: DefaultDualValidationConfig,
 }
 
 func ValidationConfigAddOptions(prefix string, f *pflag.FlagSet) {
@@ -71,12 +78,14 @@ func ValidationConfigAddOptions(prefix string, f *pflag.FlagSet) {
 	server_arb.ArbitratorSpawnerConfigAddOptions(prefix+".arbitrator", f)
 	server_jit.JitSpawnerConfigAddOptions(prefix+".jit", f)
 	WasmConfigAddOptions(prefix+".wasm", f)
+	DualValidationConfigAddOptions(prefix+".dual-validation", f)
 }
 
 type ValidationNode struct {
-	config     ValidationConfigFetcher
-	arbSpawner *server_arb.ArbitratorSpawner
-	jitSpawner *server_jit.JitSpawner
+	config      ValidationConfigFetcher
+	arbSpawner  *server_arb.ArbitratorSpawner
+	jitSpawner  *server_jit.JitSpawner
+	dualSpawner *DualSpawner
 
 	redisConsumer *redis.ValidationServer
 }
@@ -109,6 +118,7 @@ func CreateValidationNode(configFetcher ValidationConfigFetcher, stack *node.Nod
 	}
 	var serverAPI *ExecServerAPI
 	var jitSpawner *server_jit.JitSpawner
+	var dualSpawner *DualSpawner
 	if config.UseJit {
 		jitConfigFetcher := func() *server_jit.JitSpawnerConfig { return &configFetcher().Jit }
 		var err error
@@ -116,7 +126,18 @@ func CreateValidationNode(configFetcher ValidationConfigFetcher, stack *node.Nod
 		if err != nil {
 			return nil, err
 		}
-		serverAPI = NewExecutionServerAPI(jitSpawner, arbSpawner, arbConfigFetcher)
+		if config.DualValidation.Enable {
+			dualConfigFetcher := func() *DualValidationConfig { return &configFetcher().DualValidation }
+			dualSpawner, err = NewDualSpawner(jitSpawner, arbSpawner, dualConfigFetcher)
+			if err != nil {
+				return nil, err
+			}
+			serverAPI = NewExecutionServerAPI(dualSpawner, arbSpawner, arbConfigFetcher)
+		} else {
+			serverAPI = NewExecutionServerAPI(jitSpawner, arbSpawner, arbConfigFetcher)
+		}
+	} else if config.DualValidation.Enable {
+		return nil, errors.New("dual validation requires use-jit")
 	} else {
 		serverAPI = NewExecutionServerAPI(arbSpawner, arbSpawner, arbConfigFetcher)
 	}
@@ -137,7 +158,7 @@ func CreateValidationNode(configFetcher ValidationConfigFetcher, stack *node.Nod
 	}}
 	stack.RegisterAPIs(valAPIs)
 
-	return &ValidationNode{configFetcher, arbSpawner, jitSpawner, redisConsumer}, nil
+	return &ValidationNode{configFetcher, arbSpawner, jitSpawner, dualSpawner, redisConsumer}, nil
 }
 
 func (v *ValidationNode) Start(ctx context.Context) error {
@@ -149,6 +170,11 @@ func (v *ValidationNode) Start(ctx context.Context) error {
 			return err
 		}
 	}
+	if v.dualSpawner != nil {
+		if err := v.dualSpawner.Start(ctx); err != nil {
+			return err
+		}
+	}
 	if v.redisConsumer != nil {
 		v.redisConsumer.Start(ctx)
 	}
@@ -158,3 +184,8 @@ func (v *ValidationNode) Start(ctx context.Context) error {
 func (v *ValidationNode) GetExec() validator.ExecutionSpawner {
 	return v.arbSpawner
 }
+
+// DualValidation returns the spawner comparing jit against the arbitrator, or nil if dual validation is disabled.
+func (v *ValidationNode) DualValidation() *DualSpawner {
+	return v.dualSpawner
+}
@@ -61,7 +61,8 @@ type ClientConnection struct {
 	compression bool
 	flateReader *wsflate.Reader
 
-	delay time.Duration
+	delay         time.Duration
+	catchupNotice bool
 }
 
 func NewClientConnection(
@@ -73,6 +74,7 @@ func NewClientConnection(
 	compression bool,
 	maxSendQueue int,
 	delay time.Duration,
+	catchupNotice bool,
 	bklg backlog.Backlog,
 ) *ClientConnection {
 	clientConnection := &ClientConnection{
@@ -87,6 +89,7 @@ func NewClientConnection(
 		compression:     compression,
 		flateReader:     NewFlateReader(),
 		delay:           delay,
+		catchupNotice:   catchupNotice,
 		backlog:         bklg,
 		registered:      make(chan bool, 1),
 		backlogSent:     false,
@@ -166,6 +169,17 @@ func (cc *ClientConnection) writeBacklog(ctx context.Context, segment backlog.Ba
 	return nil
 }
 
+func (cc *ClientConnection) writeCatchupNotice(firstAvailable uint64) error {
+	log.Debug("requested sequence number is older than the backlog, sending catchup notice", "client", cc.Name, "requestedSeqNum", cc.requestedSeqNum, "firstAvailable", firstAvailable)
+	return cc.writeBroadcastMessage(&m.BroadcastMessage{
+		Version: m.V1,
+		CatchupNoticeMessage: &m.CatchupNoticeMessage{
+			RequestedSequenceNumber:      cc.requestedSeqNum,
+			FirstAvailableSequenceNumber: arbutil.MessageIndex(firstAvailable),
+		},
+	})
+}
+
 func (cc *ClientConnection) writeBroadcastMessage(bm *m.BroadcastMessage) error {
 	notCompressed, compressed, err := serializeMessage(bm, !cc.compression, cc.compression)
 	if err != nil {
@@ -211,6 +225,16 @@ func (cc *ClientConnection) Start(parentCtx context.Context) {
 			} else {
 				segment = s
 			}
+		} else if !backlog.IsBacklogSegmentNil(segment) && cc.catchupNotice && cc.requestedSeqNum != 0 && uint64(cc.requestedSeqNum) < segment.Start() {
+			// The requested messages are no longer in the backlog. Tell the
+			// client to read them from L1 before sending what the backlog has.
+			// A fresh client requests 0, and has nothing to catch up on.
+			err := cc.writeCatchupNotice(segment.Start())
+			if err != nil {
+				logWarn(err, "error writing catchup notice")
+				cc.Remove()
+				return
+			}
 		}
 		err := cc.writeBacklog(ctx, segment)
 		if errors.Is(err, errContextDone) {